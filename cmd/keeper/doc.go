@@ -11,12 +11,14 @@
 	{
 	    "database_dsn":"",
 	    "grpc":":3200",
-	    "key":"byrhtvtyn"
+	    "key":"byrhtvtyn",
+	    "metrics":":9090"
 	}
 
 В параметре database_dsn указывается строка подключения к БД.
 В параметре grpc - порт для grpc.
 Параметр key - ключ для создания токена.
+В параметре metrics - адрес HTTP-сервера метрик (по умолчанию :9090).
 
 Используемая БД - PostgreSQL.
 
@@ -30,6 +32,34 @@ https://github.com/Julia-ivv/info-keeper/releases/tag/v1.0.0-server
 
 	keeperserver -c="./config.json"
 
+# Миграции схемы БД.
+
+Схема БД описывается версионными миграциями, встроенными в исполняемый файл
+(internal/keeper/storage/migrations). Примененные версии хранятся в таблице
+schema_migrations. При запуске сервер применяет недостающие миграции; одновременный
+запуск нескольких серверов безопасен, так как миграции выполняются под рекомендательной
+блокировкой PostgreSQL. Миграциями можно управлять отдельной командой,
+флаги сервера задаются перед ней, а флаг -d команды migrate - перед подкомандой:
+
+	keeperserver -c="./config.json" migrate up
+	keeperserver -c="./config.json" migrate down
+	keeperserver migrate -d="sqlite:///var/lib/keeper/keeper.db" status
+
+Команда down откатывает одну последнюю примененную миграцию.
+Аргументы после подкоманды не принимаются.
+
+# Встроенная БД SQLite.
+
+Для развертывания на одном сервере вместо PostgreSQL можно использовать встроенную БД SQLite.
+Она выбирается схемой sqlite: в строке подключения, файл БД создается при первом запуске:
+
+	keeperserver -g ":3200" -d "sqlite:///var/lib/keeper/keeper.db" -k "key"
+
+Для SQLite используются собственные миграции (internal/keeper/storage/migrations/sqlite).
+Файл БД SQLite создается только сервером: если в нем уже есть таблицы, не созданные
+миграциями, запуск завершается ошибкой.
+Файл БД должен использовать только один экземпляр сервера.
+
 # Описание возможностей.
 
 Сервер предоставляет следующие возможности:
@@ -40,6 +70,129 @@ https://github.com/Julia-ivv/info-keeper/releases/tag/v1.0.0-server
   - обновление информации в базе данных.
   - синхронизация данных с клиентом.
 
+# Ревизии записей.
+
+Каждая запись на сервере имеет идентификатор и номер ревизии,
+который увеличивается при каждом обновлении записи.
+Методы добавления и обновления записей возвращают идентификатор,
+номер ревизии и сохраненное время изменения записи.
+
+Кроме того, сервер ведет для каждого пользователя счетчик синхронизации,
+который увеличивается при каждой записи данных пользователя и сохраняется
+вместе с записью. При синхронизации клиент передает полученный ранее номер
+(since_revision) и получает записи, измененные после него, и текущий номер
+(current_revision). Время на устройствах клиентов при этом не учитывается.
+
+# Мониторинг.
+
+Метрики в формате Prometheus доступны по адресу http://<metrics>/metrics:
+количество запросов и коды ошибок по методам, гистограммы времени выполнения,
+статистика пула соединений с БД и размеры данных синхронизации.
+
+Сервер регистрирует стандартный сервис grpc.health.v1.Health и reflection,
+например:
+
+	grpcurl -plaintext localhost:3200 grpc.health.v1.Health/Check
+
+# HTTP/JSON-интерфейс.
+
+Если задан адрес http (по умолчанию интерфейс отключен), все методы сервиса
+InfoKeeper доступны по нему, например POST /v1/auth, GET /v1/records, PUT /v1/cards.
+Запросы обрабатываются той же реализацией и теми же интерсепторами, что и gRPC-запросы.
+Токен передается в заголовке Authorization: Bearer <token>, имя устройства -
+в заголовке X-Device, ключ идемпотентности - в заголовке Idempotency-Key.
+Параметры GET-запросов передаются в строке запроса (вложенные поля через точку,
+байты в base64), остальных - в теле запроса в формате JSON. Потоковые методы
+принимают и возвращают по одному JSON-объекту в строке. Размер тела запроса,
+а для потоковых методов - каждого сообщения, ограничен 4 МБ, как у gRPC-сервера.
+Описание в формате OpenAPI возвращается по пути /v1/openapi.json
+и хранится в файле api/openapi.json.
+
+# Администрирование.
+
+Сервис KeeperAdmin позволяет получить список пользователей с количеством
+и размером их записей, заблокировать и разблокировать учетную запись,
+завершить все сессии пользователя, удалить учетную запись и получить
+статистику сервера. Методы сервиса требуют ключ администратора
+из параметра admin_key в метаданных запроса adminKey, без заданного ключа
+сервис отключен. Для вызова методов используется приложение keeperadmin.
+
+Токен пользователя содержит идентификатор пользователя и номер сессии.
+Запросы заблокированного пользователя отклоняются с кодом PermissionDenied,
+запросы с токеном завершенной сессии или удаленного пользователя - с кодом Unauthenticated.
+
+# Журнал аудита.
+
+Сервер записывает в журнал аудита каждое чтение, создание, обновление,
+принудительное обновление, восстановление и перенос в общую коллекцию записи пользователя
+вместе с именем устройства клиента и его IP-адресом.
+Журнал доступен только для добавления, пользователь получает свои события
+методом GetAuditLog с фильтром по интервалу времени.
+
+# Квоты.
+
+Сервер ограничивает общий объем данных пользователя, количество записей
+каждого типа и размер одной записи бинарных данных. При превышении квоты
+методы добавления и обновления возвращают код ResourceExhausted,
+а синхронизация - ошибку записи SYNC_ERROR_CODE_QUOTA_EXCEEDED.
+Текущий объем данных и квоты возвращает метод GetUsage.
+
+# Идемпотентность.
+
+Методы Add*, ForceUpdate*, SyncUserData, CreateOrganization, CreateCollection
+и MoveRecordToCollection принимают необязательный ключ
+идемпотентности в метаданных запроса idempotency-key. Сервер хранит результат
+запроса пользователя с этим ключом в течение idempotency_window секунд
+и при повторном запросе возвращает сохраненный ответ без повторной записи.
+Повторный запрос с тем же ключом и другими данными завершается кодом InvalidArgument,
+результаты с временными ошибками (Unavailable, Internal и т.п.) не сохраняются.
+
+# Сроки выполнения.
+
+Операции с отдельными записями в БД ограничены параметром storage_timeout,
+выборки для синхронизации, списки и удаление пользователя - параметром
+storage_bulk_timeout (в секундах). Если клиент gRPC передал срок выполнения
+запроса, операции с БД выполняются до этого срока.
+
+# Хранилище блобов.
+
+Если задан каталог blob_dir, зашифрованное содержимое бинарных записей и их предыдущих
+версий хранится в нем, а в БД остаются только ссылка, сумма SHA-256 и размер.
+Файлы называются по сумме содержимого, поэтому одинаковые данные хранятся один раз,
+а при чтении содержимое сверяется с суммой. Содержимое записей и их версий, сохраненных
+до включения каталога, переносится в него при запуске сервера. Каждые blob_gc_interval
+секунд сервер удаляет блобы, на которые больше не ссылается ни одна запись и которые
+не изменялись дольше часа. Откат миграции 0002_binary_blobs завершается ошибкой,
+пока содержимое хотя бы одной записи хранится в каталоге.
+
+# Общие коллекции.
+
+Пользователи объединяются в организации, внутри которых создаются общие коллекции
+записей. Участник коллекции имеет роль owner, editor или viewer: владелец приглашает
+участников, редактор переносит в коллекцию свои записи, читатель только получает их.
+Записи коллекции зашифрованы ключом коллекции, который хранится на сервере отдельно
+для каждого участника в зашифрованном его открытым ключом виде, поэтому сервер
+не видит ни ключ, ни данные. Методы SetUserKeys, CreateOrganization, CreateCollection,
+AddCollectionMember, MoveRecordToCollection и ListCollectionRecords при недостаточной роли
+возвращают код PermissionDenied.
+
+Ключи пользователя задаются методом SetUserKeys один раз, повторный вызов
+возвращает код AlreadyExists, чтобы нельзя было подменить ключ, которым уже
+зашифрованы ключи коллекций. Перенесенная в коллекцию запись удаляется из личных
+записей пользователя, а ее ключ возвращается при следующей синхронизации в списке
+deleted_records, по которому клиент удаляет запись локально.
+
+# Экстренный доступ.
+
+Пользователь может назначить доверенное лицо с периодом ожидания, передав копию своих
+записей, зашифрованную случайным ключом экстренного доступа, и этот ключ, зашифрованный
+открытым ключом доверенного лица. Доверенное лицо запрашивает доступ методом
+RequestEmergencyAccess; если владелец не отклонил запрос методом DeclineEmergencyAccess
+в течение периода ожидания, метод GetEmergencyKey возвращает зашифрованный ключ и копию
+записей. До окончания периода ожидания GetEmergencyKey возвращает код PermissionDenied.
+Клиент заменяет ключ и копию записей методом UpdateEmergencyRecords после каждой
+синхронизации; период ожидания и запрос доступа при этом не меняются.
+
 База данных может хранить следующую информацию:
 
   - реквизиты банковских карт.
@@ -1,16 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
 	kConfig "github.com/Julia-ivv/info-keeper.git/internal/keeper/config"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/gateway"
 	"github.com/Julia-ivv/info-keeper.git/internal/keeper/grpcserver"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/idempotency"
 	"github.com/Julia-ivv/info-keeper.git/internal/keeper/interceptors"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/metrics"
 	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
 	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
 	"github.com/Julia-ivv/info-keeper.git/pkg/logger"
@@ -19,6 +31,18 @@ import (
 func main() {
 	cfg := kConfig.NewConfig()
 
+	if flag.Arg(0) == "migrate" {
+		dsn, cmd, err := parseMigrateArgs(cfg.DBDSN, flag.Args()[1:])
+		if err == nil {
+			err = migrate(dsn, cmd)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	logger.ZapSugar = logger.NewLogger()
 	logger.ZapSugar.Infow("Starting gRPC server", "port", cfg.GRPC)
 	logger.ZapSugar.Infow("flags", "db dsn", cfg.DBDSN)
@@ -29,16 +53,83 @@ func main() {
 	}
 	defer repo.Close()
 
+	gcCtx, stopGC := context.WithCancel(context.Background())
+	defer stopGC()
+	if cfg.BlobDir != "" {
+		logger.ZapSugar.Infow("Storing binary records in blob directory", "dir", cfg.BlobDir)
+		go collectBlobs(gcCtx, repo, time.Duration(cfg.BlobGCInterval)*time.Second)
+	}
+
+	m := metrics.New()
+	m.RegisterDBStats(repo.Stats)
+	idem := idempotency.New(time.Duration(cfg.IdempotencyWindow)*time.Second, cfg.SecretKey)
+
+	unary := []grpc.UnaryServerInterceptor{
+		m.UnaryInterceptor,
+		interceptors.AdminAuth(cfg.AdminKey),
+		interceptors.HandlerWithAuth,
+		idem.UnaryInterceptor,
+		interceptors.HandlerWithLogging,
+	}
+	stream := []grpc.StreamServerInterceptor{
+		m.StreamInterceptor,
+		interceptors.StreamHandlerWithAuth,
+		interceptors.StreamHandlerWithLogging,
+	}
+	keeperSrv := grpcserver.NewKeeperServer(repo, *cfg)
+
 	srvGRPC := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(interceptors.HandlerWithAuth),
-		grpc.ChainUnaryInterceptor(interceptors.HandlerWithLogging))
-	pb.RegisterInfoKeeperServer(srvGRPC, grpcserver.NewKeeperServer(repo, *cfg))
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...))
+	pb.RegisterInfoKeeperServer(srvGRPC, keeperSrv)
+	pb.RegisterKeeperAdminServer(srvGRPC, grpcserver.NewAdminServer(repo))
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus(pb.InfoKeeper_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(srvGRPC, healthSrv)
+	reflection.Register(srvGRPC)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	srvMetrics := &http.Server{Addr: cfg.Metrics, Handler: mux}
+	go func() {
+		logger.ZapSugar.Infow("Starting metrics server", "address", cfg.Metrics)
+		err := srvMetrics.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.ZapSugar.Errorw(err.Error(), "event", "start metrics server")
+		}
+	}()
+
+	var srvHTTP *http.Server
+	if cfg.HTTP != "" {
+		gw, err := gateway.New(keeperSrv, unary, stream)
+		if err != nil {
+			logger.ZapSugar.Fatal(err)
+		}
+		srvHTTP = &http.Server{Addr: cfg.HTTP, Handler: gw}
+		go func() {
+			logger.ZapSugar.Infow("Starting HTTP gateway", "address", cfg.HTTP)
+			err := srvHTTP.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.ZapSugar.Errorw(err.Error(), "event", "start HTTP gateway")
+			}
+		}()
+	}
 
 	idleConnsClosed := make(chan struct{})
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
 	go func() {
 		<-sigs
+		healthSrv.Shutdown()
+		if err := srvMetrics.Shutdown(context.Background()); err != nil {
+			logger.ZapSugar.Infow(err.Error(), "event", "stop metrics server")
+		}
+		if srvHTTP != nil {
+			if err := srvHTTP.Shutdown(context.Background()); err != nil {
+				logger.ZapSugar.Infow(err.Error(), "event", "stop HTTP gateway")
+			}
+		}
 		srvGRPC.GracefulStop()
 		close(idleConnsClosed)
 	}()
@@ -53,3 +144,98 @@ func main() {
 
 	<-idleConnsClosed
 }
+
+// blobGracePeriod - время, в течение которого неиспользуемый блоб не удаляется:
+// запись, для которой он сохранен, может еще добавляться в БД.
+const blobGracePeriod = time.Hour
+
+// collectBlobs периодически удаляет блобы, на которые не ссылаются бинарные записи.
+func collectBlobs(ctx context.Context, repo storage.BlobCollector, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			removed, err := repo.CollectBlobs(ctx, now.Add(-blobGracePeriod))
+			if err != nil {
+				logger.ZapSugar.Errorw(err.Error(), "event", "collect blobs")
+				continue
+			}
+			if removed > 0 {
+				logger.ZapSugar.Infow("Removed unused blobs", "count", removed)
+			}
+		}
+	}
+}
+
+// parseMigrateArgs разбирает аргументы после migrate: флаги команды и одну подкоманду.
+// Флаг -d задает имя для доступа к БД вместо имени из настроек сервера.
+func parseMigrateArgs(dsn string, args []string) (string, string, error) {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.StringVar(&dsn, "d", dsn, "database DSN")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: keeperserver [flags] migrate [-d dsn] up|down|status")
+		fs.PrintDefaults()
+	}
+	err := fs.Parse(args)
+	if err != nil {
+		return "", "", err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return "", "", fmt.Errorf("migrate expects one command, got %q", fs.Args())
+	}
+	return dsn, fs.Arg(0), nil
+}
+
+// migrate выполняет команду миграции схемы БД: up, down или status.
+func migrate(dsn string, cmd string) error {
+	m, err := storage.OpenMigrator(dsn)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	ctx := context.Background()
+	switch cmd {
+	case "up":
+		done, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		for _, mg := range done {
+			fmt.Printf("applied %04d_%s\n", mg.Version, mg.Name)
+		}
+		if len(done) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		mg, err := m.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled back %04d_%s\n", mg.Version, mg.Name)
+	case "status":
+		states, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range states {
+			applied := "pending"
+			if !st.AppliedAt.IsZero() {
+				applied = "applied " + st.AppliedAt.Format(time.RFC3339)
+			}
+			name := st.Name
+			if name == "" {
+				name = "(unknown)"
+			}
+			fmt.Printf("%04d_%s\t%s\n", st.Version, name, applied)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q, use up, down or status", cmd)
+	}
+
+	return nil
+}
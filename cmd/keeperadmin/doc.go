@@ -0,0 +1,36 @@
+/*
+# Пакет main.
+
+Точка входа в приложение администрирования сервера.
+
+Приложение подключается к сервису KeeperAdmin сервера и выполняет одну команду.
+Для доступа используется ключ администратора, заданный на сервере
+параметром admin_key (флаг -admin-key, переменная окружения ADMIN_KEY).
+
+# Запуск.
+
+	keeperadmin -g=":3200" -admin-key="<ключ>" <команда> [логин]
+
+Адрес сервера можно задать переменной окружения GRPC_PORT,
+ключ администратора - переменной окружения ADMIN_KEY.
+
+# Команды.
+
+	users
+		Список пользователей с состоянием учетной записи,
+		количеством записей и их общим размером в байтах.
+	disable <логин>
+		Блокировка учетной записи: пользователь не может войти,
+		запросы с выданными ему токенами отклоняются.
+	enable <логин>
+		Разблокировка учетной записи.
+	logout <логин>
+		Завершение всех сессий пользователя, выданные токены становятся недействительными.
+	delete <логин>
+		Удаление учетной записи и всех записей пользователя вместе с их версиями.
+		События журнала аудита сохраняются, логин может быть зарегистрирован заново.
+	stats
+		Статистика сервера: количество пользователей и записей, общий размер данных,
+		время работы и состояние пула соединений с БД.
+*/
+package main
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keeperadmin"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+const requestTimeout = 30 * time.Second
+
+func main() {
+	cfg := keeperadmin.NewConfig()
+
+	conn, err := grpc.NewClient(cfg.GRPC, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	err = keeperadmin.Run(ctx, pb.NewKeeperAdminClient(conn), cfg.AdminKey, cfg.Args, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, keeperadmin.ErrUsage) {
+			fmt.Fprintln(os.Stderr, keeperadmin.Usage)
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdexecutor"
+	cliConfig "github.com/Julia-ivv/info-keeper.git/internal/keepercli/config"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	"github.com/Julia-ivv/info-keeper.git/pkg/logger"
+)
+
+// runAgent запускает клиента в режиме ssh-agent.
+// Агент работает с локальной БД и не требует подключения к серверу.
+func runAgent(cfg *cliConfig.Flags, repo storage.Repositorier) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Println("Enter user login: ")
+	login, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		logger.ZapSugar.Infoln("can`t read user login.", err)
+		return
+	}
+
+	logger.ZapSugar.Infow("Starting ssh-agent", "socket", cfg.AgentSocket)
+	err = cmdexecutor.ServeSshAgent(ctx, strings.TrimSpace(login), cfg.AgentSocket, repo)
+	if err != nil {
+		logger.ZapSugar.Infoln("ssh-agent stopped with error.", err)
+	}
+}
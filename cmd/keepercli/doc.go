@@ -10,7 +10,8 @@
 
 	{
 	   	"database_uri":"keeper.db",
-	   	"grpc":":3200"
+	   	"grpc":":3200",
+	   	"agent_socket":"keeper-agent.sock"
 	}
 
 В параметре database_uri указывается имя файла БД.
@@ -18,6 +19,13 @@
 
 В параметре grpc - порт gRPC сервера.
 
+В параметре agent_socket - путь к unix-сокету ssh-agent (необязательный).
+
+Необязательные параметры storage_timeout и storage_bulk_timeout задают время
+ожидания операций с локальной БД, request_timeout - срок выполнения обычной команды,
+sync_timeout - срок выполнения команд auth, reg, syncExit, resolve, restore и команд
+передачи бинарных данных на сервер и с сервера (в секундах).
+
 # Запуск клиента.
 
 Скачайте исполняемый файл:
@@ -44,12 +52,39 @@ https://github.com/Julia-ivv/info-keeper/releases/tag/v1.0.0
   - пары логин - пароль.
   - текстовые данные.
   - бинарные данные из файлов.
+  - генераторы одноразовых паролей (TOTP).
+  - ключи SSH.
+  - пользовательские записи, созданные по шаблонам.
 
 Для каждого типа информации можно хранить описание или комментарий и
 короткую подсказку (prompt).
 
+Любую запись можно отметить тегами и поместить в папку вида a/b/c.
+Теги и папки хранятся в зашифрованном виде и синхронизируются с сервером
+вместе с записями.
+
+Записи можно переносить в общие коллекции организации и делиться ими с другими
+пользователями. Ключ коллекции шифруется открытым ключом каждого участника,
+поэтому сервер не может расшифровать общие записи.
+
 При использовании клиента, информация изменяется в БД клиента.
 Синхронизация с БД сервера происходит при аутентификации и при выходе из приложения.
+После аутентификации клиент также подписывается на уведомления сервера и в фоне
+сохраняет записи, измененные на других устройствах, если они новее локальных.
+Запросы на запись передаются серверу с ключом идемпотентности и повторяются
+с тем же ключом, если сервер недоступен, поэтому повтор не применяет изменения дважды.
+
+# Режим ssh-agent.
+
+Клиент может работать как ssh-agent и выдавать сохраненные ключи SSH
+без записи их на диск в открытом виде:
+
+	keepercli -c="./config.json" agent
+
+После ввода логина, пароля и ключа шифрования агент начинает обслуживать
+подключения на unix-сокете и выводит значение переменной SSH_AUTH_SOCK.
+Агент работает только с локальной БД, добавлять и удалять ключи через него нельзя.
+Путь к сокету можно указать флагом -a или в файле конфигурации.
 
 # Использование клиента.
 
@@ -123,21 +158,21 @@ https://github.com/Julia-ivv/info-keeper/releases/tag/v1.0.0
 
 	--gcards
 		Получает информацию обо всех банковских картах пользователя.
-		Используется без дополнительных флагов.
-		Например, --gcards
+		Используется с необязательными флагами -g -d для отбора по тегам и папке.
+		Например, --gcards -g=work -d=finance
 	--gpwds
 		Получает информацию обо всех парах логин-пароль пользователя.
-		Используется без дополнительных флагов.
-		Например, --gpwds
+		Используется с необязательными флагами -g -d для отбора по тегам и папке.
+		Например, --gpwds -g=work -d=finance
 	--gtexts
 		Получает всю текстовую информацию пользователя.
-		Используется без дополнительных флагов.
-		Например, --gtexts
+		Используется с необязательными флагами -g -d для отбора по тегам и папке.
+		Например, --gtexts -g=work -d=finance
 	--gbytes
 		Получает всю бинарную информацию пользователя.
 		Данные сохраняются в разные файлы.
-		Используется без дополнительных флагов.
-		Например, --gbytes
+		Используется с необязательными флагами -g -d для отбора по тегам и папке.
+		Например, --gbytes -g=work -d=finance
 
 	--fcard
 		Обновляет данные банковской карты на сервере.
@@ -181,8 +216,161 @@ https://github.com/Julia-ivv/info-keeper/releases/tag/v1.0.0
 		Используется с флагом -p.
 		Например, --sbyte -p=prompt
 
+	--scards
+		Получает с сервера список банковских карт без их данных.
+		Используется с необязательным флагом -s.
+		Например, --scards -s=2024-01-02T15:04:05Z
+	--spwds
+		Получает с сервера список пар логин-пароль без их данных.
+		Используется с необязательным флагом -s.
+		Например, --spwds
+	--stexts
+		Получает с сервера список текстовых данных без их содержимого.
+		Используется с необязательным флагом -s.
+		Например, --stexts
+	--sbytes
+		Получает с сервера список бинарных данных без их содержимого.
+		Используется с необязательным флагом -s.
+		Например, --sbytes
+
+	--notp
+		Добавляет генератор одноразовых паролей из URI формата otpauth://totp.
+		Используется с флагами -o -m.
+		Например, --notp -o=otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example -m=comment
+	--gotp
+		Выводит текущий одноразовый пароль и время до его смены в секундах.
+		Используется с флагами -p -l, где -p - издатель (issuer), -l - имя учетной записи.
+		Например, --gotp -p=Example -l=alice
+
+	--nssh
+		Добавляет ключ SSH из файла закрытого ключа.
+		Если ключ защищен кодовой фразой, она будет запрошена и сохранена вместе с ключом.
+		Используется с флагами -p -k -m, где -m - комментарий ключа.
+		Например, --nssh -p=deploy -k=/home/user/.ssh/id_ed25519 -m=comment
+	--gssh
+		Выводит открытый ключ SSH и комментарий по подсказке.
+		Используется с флагом -p.
+		Например, --gssh -p=deploy
+
+	--ntmpl
+		Добавляет шаблон пользовательских записей.
+		Используется с флагом -r и повторяющимся флагом -f=name:type[:secret].
+		Допустимые типы полей: text, number, date (в формате 2006-01-02).
+		Значения полей с пометкой secret скрываются при выводе.
+		Например, --ntmpl -r=server -f=host:text -f=port:number -f=password:text:secret
+	--gtmpls
+		Получает все шаблоны пользовательских записей.
+		Используется с необязательными флагами -g -d для отбора по тегам и папке.
+		Например, --gtmpls -g=work -d=finance
+	--ncustom
+		Добавляет пользовательскую запись по шаблону.
+		Значения полей проверяются по типам, указанным в шаблоне.
+		Используется с флагами -p -r -m и повторяющимся флагом -f=name=value.
+		Например, --ncustom -p=db -r=server -f=host=localhost -f=port=5432 -m=comment
+	--gcustom
+		Получает пользовательскую запись по подсказке.
+		Используется с флагом -p и необязательным флагом -w.
+		Например, --gcustom -p=db -w
+	--gcustoms
+		Получает все пользовательские записи.
+		Используется с необязательными флагами -w -g -d.
+		Например, --gcustoms -g=work
+
+	--settags
+		Заменяет теги записи. Без флагов -g удаляет все теги записи.
+		Используется с флагом -y, флагами ключа записи и повторяющимся флагом -g.
+		Флаги ключа записи: card - -n, login и otp - -p -l, template - -r,
+		text, binary, ssh и custom - -p.
+		Например, --settags -y=login -p=prompt -l=login -g=work -g=mail
+	--move
+		Перемещает запись в папку. Без флага -d убирает запись из папки.
+		Используется с флагом -y, флагами ключа записи и флагом -d.
+		Например, --move -y=card -n=12345 -d=finance/cards
+
+	--history
+		Получает с сервера предыдущие версии записи, начиная с последней.
+		Используется с флагом -y, флагами ключа записи и необязательным флагом -w.
+		Например, --history -y=text -p=prompt
+	--restore
+		Восстанавливает на сервере и в локальном хранилище предыдущую версию записи.
+		Текущая версия записи сохраняется в истории.
+		Используется с флагом -y, флагами ключа записи, флагом -i и необязательным флагом -w.
+		Например, --restore -y=login -p=prompt -l=login -i=3
+	--resolve
+		Разрешает конфликт записи, обнаруженный при синхронизации.
+		С флагом -c=local локальная версия записи принудительно сохраняется на сервере,
+		с флагом -c=server версия с сервера заменяет локальную.
+		Команда для каждого конфликта выводится в списке ошибок синхронизации.
+		Используется с флагом -y, флагами ключа записи и флагом -c.
+		Например, --resolve -y=text -p=prompt -c=server
+
+	--audit
+		Получает с сервера журнал доступа к записям пользователя:
+		время, действие, запись, устройство и IP-адрес.
+		Используется с необязательными флагами -s и -a, задающими интервал времени.
+		Например, --audit -s=2024-01-01T00:00:00Z -a=2024-02-01T00:00:00Z
+	--usage
+		Получает с сервера объем данных пользователя по типам записей
+		и квоты хранилища: общий объем, количество записей одного типа
+		и максимальный размер бинарных данных.
+
+	--initkeys
+		Создает пару ключей пользователя для общих коллекций. Закрытый ключ
+		сохраняется на сервере в зашифрованном ключом пользователя виде.
+	--norg
+		Создает организацию, пользователь становится ее владельцем.
+		Используется с флагом --name.
+		Например, --norg --name=team
+	--ncoll
+		Создает общую коллекцию в организации, пользователь становится ее владельцем.
+		Используется с флагами --org и --name.
+		Например, --ncoll --org=1 --name=servers
+	--colls
+		Выводит общие коллекции пользователя и его роль в каждой из них.
+	--invite
+		Приглашает пользователя в общую коллекцию с ролью owner, editor или viewer.
+		Пользователь должен заранее выполнить --initkeys.
+		Используется с флагами -j, -u и --role.
+		Например, --invite -j=2 -u=bob --role=viewer
+	--tocoll
+		Переносит запись в общую коллекцию: запись шифруется ключом коллекции,
+		личная запись удаляется на сервере и в локальном хранилище.
+		Используется с флагом -j, флагом -y и флагами ключа записи.
+		Например, --tocoll -j=2 -y=login -p=prompt -l=login
+	--gcoll
+		Получает записи общей коллекции. Используется с флагом -j и необязательным флагом -w.
+		Например, --gcoll -j=2
+
+	--emadd
+		Назначает пользователя доверенным лицом для экстренного доступа. Копия всех локальных
+		записей шифруется новым случайным ключом, а ключ - открытым ключом доверенного лица,
+		поэтому оно должно заранее выполнить --initkeys. Пароль пользователя доверенному лицу
+		не передается. Копия и ключ заменяются после каждой синхронизации. Используется
+		с флагами -u и --wait. Повторный вызов меняет период ожидания и отменяет запрос доступа.
+		Например, --emadd -u=bob --wait=48
+	--emremove
+		Удаляет доверенное лицо. Используется с флагом -u.
+		Например, --emremove -u=bob
+	--emdecline
+		Отклоняет запрос доступа доверенного лица. Используется с флагом -u.
+		Например, --emdecline -u=bob
+	--emlist
+		Выводит доверенных лиц пользователя и владельцев, назначивших его доверенным лицом,
+		с состоянием запросов доступа.
+	--emrequest
+		Запрашивает экстренный доступ к хранилищу владельца. Доступ будет предоставлен,
+		если владелец не отклонит запрос в течение периода ожидания.
+		Используется с флагом -u.
+		Например, --emrequest -u=alice
+	--emkey
+		Получает и выводит копию записей владельца после окончания периода ожидания.
+		Ключ экстренного доступа расшифровывается только в памяти и не выводится.
+		Используется с флагом -u и необязательным флагом -w.
+		Например, --emkey -u=alice
+
 	-u
-		Используется для указания логина пользователя при регистрации и аутентификации.
+		Используется для указания логина пользователя при регистрации, аутентификации,
+		приглашении в общую коллекцию и в командах экстренного доступа.
 	-p
 		Используется для указания короткой подсказки для данных.
 	-l
@@ -199,6 +387,46 @@ https://github.com/Julia-ivv/info-keeper/releases/tag/v1.0.0
 		Используется для указания текстовых данных.
 	-b
 		Используется для указания пути к файлу с данными.
+	-s
+		Используется для указания времени изменения в формате RFC3339.
+		Выводятся только записи, измененные после этого времени.
+		Для команды --audit задает начало интервала журнала.
+	-a
+		Используется для указания конца интервала журнала аудита в формате RFC3339.
+	-o
+		Используется для указания URI генератора одноразовых паролей.
+	-k
+		Используется для указания пути к файлу закрытого ключа SSH.
+	-r
+		Используется для указания имени шаблона пользовательских записей.
+	-f
+		Используется для указания поля шаблона или значения поля пользовательской записи.
+		Флаг можно указать несколько раз.
+	-w
+		Используется для вывода значений секретных полей пользовательских записей.
+	-g
+		Используется для указания тега записи. Тег не может содержать запятую.
+		Флаг можно указать несколько раз, при отборе запись должна иметь все теги.
+	-d
+		Используется для указания папки записи в виде a/b/c.
+		При отборе выводятся записи из папки и всех вложенных в нее папок.
+	-y
+		Используется для указания типа записи: card, login, text, binary, otp,
+		ssh, template или custom.
+	-i
+		Используется для указания номера версии записи из вывода команды --history.
+	-c
+		Используется для выбора версии записи при разрешении конфликта: local или server.
+	-j
+		Используется для указания номера общей коллекции из вывода команды --colls.
+	--name
+		Используется для указания названия организации или коллекции.
+	--org
+		Используется для указания номера организации.
+	--role
+		Используется для указания роли участника коллекции: owner, editor или viewer.
+	--wait
+		Используется для указания периода ожидания экстренного доступа в часах.
 
 	-x
 		Используется для выхода из приложения.
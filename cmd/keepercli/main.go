@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -14,6 +15,7 @@ import (
 	cliConfig "github.com/Julia-ivv/info-keeper.git/internal/keepercli/config"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
 	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+	"github.com/Julia-ivv/info-keeper.git/pkg/deadline"
 	"github.com/Julia-ivv/info-keeper.git/pkg/logger"
 )
 
@@ -29,13 +31,28 @@ func main() {
 	}
 	defer repo.Close()
 
-	conn, err := grpc.NewClient(cfg.GRPC, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if cfg.Mode == cliConfig.ModeAgent {
+		runAgent(cfg, repo)
+		return
+	}
+
+	conn, err := grpc.NewClient(cfg.GRPC,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(cmdexecutor.DeviceUnaryInterceptor, cmdexecutor.IdempotencyUnaryInterceptor),
+		grpc.WithChainStreamInterceptor(cmdexecutor.DeviceStreamInterceptor))
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer conn.Close()
 
 	cl := pb.NewInfoKeeperClient(conn)
+	cmdexecutor.CmdDeadlines = cmdexecutor.Deadlines{
+		Request: deadline.Seconds(cfg.RequestTimeout),
+		Sync:    deadline.Seconds(cfg.SyncTimeout),
+	}
+
+	var watcher changeWatcher
+	defer watcher.stop()
 
 	for {
 		fmt.Println("Enter command: ")
@@ -49,11 +66,14 @@ func main() {
 			logger.ZapSugar.Infoln("can`t parse command ", userInput, err)
 		}
 		if userCmd != "" {
-			res, err := cmdexecutor.ExecuteCmd(userCmd, userArgs, cl, repo)
+			res, err := cmdexecutor.ExecuteCmd(context.Background(), userCmd, userArgs, cl, repo)
 			if err != nil {
 				logger.ZapSugar.Infoln("can`t execute command ", userCmd, err)
 				continue
 			}
+			if userCmd == cmdparser.CmdAuth || userCmd == cmdparser.CmdReg {
+				watcher.restart(cl, repo)
+			}
 			if res != nil {
 				res.PrintData()
 			}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdexecutor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+	"github.com/Julia-ivv/info-keeper.git/pkg/logger"
+)
+
+// changeWatcher получает изменения записей пользователя с сервера в фоне.
+type changeWatcher struct {
+	cancel context.CancelFunc
+}
+
+// restart останавливает предыдущую подписку и подписывается на изменения
+// записей текущего пользователя.
+func (w *changeWatcher) restart(cl pb.InfoKeeperClient, repo storage.Repositorier) {
+	w.stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	login, token := cmdexecutor.UserLogin, cmdexecutor.UserToken
+	go func() {
+		err := cmdexecutor.WatchChanges(ctx, cl, repo, login, token)
+		if err != nil {
+			logger.ZapSugar.Infoln("change watcher stopped with error.", err)
+		}
+	}()
+}
+
+// stop останавливает подписку на изменения.
+func (w *changeWatcher) stop() {
+	if w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+	}
+}
@@ -2,6 +2,7 @@
 package authorizer
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -10,6 +11,15 @@ import (
 // AccessToken используется для доступа к токену в метаданных gRPC-запроса.
 const AccessToken = "accessToken"
 
+// DeviceName используется для передачи имени устройства клиента в метаданных gRPC-запроса.
+const DeviceName = "device"
+
+// IdempotencyKey используется для передачи ключа идемпотентности записи в метаданных gRPC-запроса.
+const IdempotencyKey = "idempotency-key"
+
+// AdminKey используется для передачи ключа администратора в метаданных gRPC-запроса.
+const AdminKey = "adminKey"
+
 // TokenExp - время действия токена.
 const TokenExp = time.Hour * 10
 
@@ -22,17 +32,28 @@ type Claims struct {
 	jwt.RegisteredClaims
 	Login string
 	Pwd   string
+	// UserID - идентификатор пользователя, для которого выдан токен.
+	UserID int64
+	// Session - номер сессии пользователя, токены с предыдущими номерами недействительны.
+	Session int64
 }
 
 // BuildToken - создает новый токен.
 func BuildToken(userLogin, userPwd, secretKey string) (tokenString string, err error) {
+	return BuildSessionToken(userLogin, userPwd, secretKey, 0, 0)
+}
+
+// BuildSessionToken - создает новый токен для сессии session пользователя с идентификатором userID.
+func BuildSessionToken(userLogin, userPwd, secretKey string, userID, session int64) (tokenString string, err error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
 		Claims{
 			RegisteredClaims: jwt.RegisteredClaims{
 				ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExp)),
 			},
-			Login: userLogin,
-			Pwd:   userPwd,
+			Login:   userLogin,
+			Pwd:     userPwd,
+			UserID:  userID,
+			Session: session,
 		})
 	tokenString, err = token.SignedString([]byte(secretKey))
 	if err != nil {
@@ -43,12 +64,24 @@ func BuildToken(userLogin, userPwd, secretKey string) (tokenString string, err e
 
 // GetUserDataFromToken - получает логин из токена.
 func GetUserDataFromToken(tokenString, secretKey string) (userLogin string, err error) {
+	claims, err := ParseToken(tokenString, secretKey)
+	if err != nil {
+		return "", err
+	}
+	return claims.Login, nil
+}
+
+// ParseToken - проверяет токен и получает его данные.
+func ParseToken(tokenString, secretKey string) (*Claims, error) {
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
 		return []byte(secretKey), nil
 	})
-	if (err != nil) || (!token.Valid) {
-		return "", err
+	if err != nil {
+		return nil, err
 	}
-	return claims.Login, nil
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
 }
@@ -23,3 +23,22 @@ func TestGetUserIDFromToken(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestParseToken(t *testing.T) {
+	tokenStr, err := BuildSessionToken("login", "pwd", "key", 7, 3)
+	assert.NoError(t, err)
+
+	t.Run("ok test", func(t *testing.T) {
+		claims, err := ParseToken(tokenStr, "key")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "login", claims.Login)
+			assert.Equal(t, int64(7), claims.UserID)
+			assert.Equal(t, int64(3), claims.Session)
+		}
+	})
+	t.Run("wrong key test", func(t *testing.T) {
+		claims, err := ParseToken(tokenStr, "other")
+		assert.Nil(t, claims)
+		assert.Error(t, err)
+	})
+}
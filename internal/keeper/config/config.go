@@ -16,18 +16,51 @@ import (
 type Flags struct {
 	// GRPC (флаг -g) - порт для gRPC, например :3200.
 	GRPC string `env:"GRPC_PORT" json:"grpc"`
-	// DBDSN (флаг -d) - имя для доступа к БД.
+	// DBDSN (флаг -d) - имя для доступа к БД; со схемой sqlite: - путь к файлу встроенной БД SQLite.
 	DBDSN string `env:"DATABASE_DSN" json:"database_dsn"`
+	// HTTP (флаг -http) - адрес HTTP/JSON-интерфейса, например :8080, пустой адрес отключает интерфейс.
+	HTTP string `env:"HTTP_ADDRESS" json:"http"`
 	// ConfigFileName (флаг -c/-config) - имя файла конфигурации.
 	ConfigFileName string `env:"CONFIG"`
 	// SecretKey ключ для создания токена.
 	SecretKey string `env:"SKEY" json:"key"`
+	// AdminKey (флаг -admin-key) - ключ администратора для сервиса KeeperAdmin, пустой ключ отключает сервис.
+	AdminKey string `env:"ADMIN_KEY" json:"admin_key"`
+	// HistoryRetention (флаг -r) - количество хранимых предыдущих версий каждой записи, 0 - не хранить историю.
+	HistoryRetention int `env:"HISTORY_RETENTION" json:"history_retention"`
+	// Metrics (флаг -m) - адрес HTTP-сервера метрик Prometheus, например :9090.
+	Metrics string `env:"METRICS_ADDRESS" json:"metrics"`
+	// QuotaBytes (флаг -quota-bytes) - максимальный суммарный размер записей пользователя в байтах, 0 - без ограничений.
+	QuotaBytes int64 `env:"QUOTA_BYTES" json:"quota_bytes"`
+	// QuotaRecords (флаг -quota-records) - максимальное количество записей пользователя каждого типа, 0 - без ограничений.
+	QuotaRecords int64 `env:"QUOTA_RECORDS" json:"quota_records"`
+	// QuotaBinarySize (флаг -quota-binary) - максимальный размер одной записи с бинарными данными в байтах, 0 - без ограничений.
+	QuotaBinarySize int64 `env:"QUOTA_BINARY_SIZE" json:"quota_binary_size"`
+	// IdempotencyWindow (флаг -idempotency-window) - время хранения результатов запросов с ключом идемпотентности в секундах.
+	IdempotencyWindow int `env:"IDEMPOTENCY_WINDOW" json:"idempotency_window"`
+	// StorageTimeout (флаг -storage-timeout) - время ожидания операций с отдельными записями в БД в секундах.
+	StorageTimeout int `env:"STORAGE_TIMEOUT" json:"storage_timeout"`
+	// StorageBulkTimeout (флаг -storage-bulk-timeout) - время ожидания выборок для синхронизации, списков и удаления пользователя в секундах.
+	StorageBulkTimeout int `env:"STORAGE_BULK_TIMEOUT" json:"storage_bulk_timeout"`
+	// BlobDir (флаг -blob-dir) - каталог для содержимого бинарных записей, пустой - хранить содержимое в БД.
+	BlobDir string `env:"BLOB_DIR" json:"blob_dir"`
+	// BlobGCInterval (флаг -blob-gc-interval) - период удаления неиспользуемых блобов в секундах.
+	BlobGCInterval int `env:"BLOB_GC_INTERVAL" json:"blob_gc_interval"`
 }
 
 const (
-	defGRPC string = ":3200"
+	defGRPC              string = ":3200"
+	defHistoryRetention  int    = 10
+	defMetrics           string = ":9090"
+	defIdempotencyWindow int    = 600
+	defStorageTimeout    int    = 3
+	defStorageBulk       int    = 30
+	defBlobGCInterval    int    = 3600
 )
 
+// unsetRetention - значение HistoryRetention, не заданное ни флагом, ни переменной окружения, ни в файле конфигурации.
+const unsetRetention int = -1
+
 func readFromConf(c *Flags) error {
 	f, err := os.Open(c.ConfigFileName)
 	if err != nil {
@@ -44,7 +77,7 @@ func readFromConf(c *Flags) error {
 		return err
 	}
 
-	var conf Flags
+	conf := Flags{HistoryRetention: unsetRetention}
 	err = json.Unmarshal(allData, &conf)
 	if err != nil {
 		return err
@@ -56,9 +89,45 @@ func readFromConf(c *Flags) error {
 	if c.GRPC == "" {
 		c.GRPC = conf.GRPC
 	}
+	if c.HTTP == "" {
+		c.HTTP = conf.HTTP
+	}
 	if c.SecretKey == "" {
 		c.SecretKey = conf.SecretKey
 	}
+	if c.AdminKey == "" {
+		c.AdminKey = conf.AdminKey
+	}
+	if c.HistoryRetention < 0 {
+		c.HistoryRetention = conf.HistoryRetention
+	}
+	if c.Metrics == "" {
+		c.Metrics = conf.Metrics
+	}
+	if c.QuotaBytes == 0 {
+		c.QuotaBytes = conf.QuotaBytes
+	}
+	if c.QuotaRecords == 0 {
+		c.QuotaRecords = conf.QuotaRecords
+	}
+	if c.QuotaBinarySize == 0 {
+		c.QuotaBinarySize = conf.QuotaBinarySize
+	}
+	if c.IdempotencyWindow == 0 {
+		c.IdempotencyWindow = conf.IdempotencyWindow
+	}
+	if c.StorageTimeout == 0 {
+		c.StorageTimeout = conf.StorageTimeout
+	}
+	if c.StorageBulkTimeout == 0 {
+		c.StorageBulkTimeout = conf.StorageBulkTimeout
+	}
+	if c.BlobDir == "" {
+		c.BlobDir = conf.BlobDir
+	}
+	if c.BlobGCInterval == 0 {
+		c.BlobGCInterval = conf.BlobGCInterval
+	}
 
 	return nil
 }
@@ -68,10 +137,23 @@ func NewConfig() *Flags {
 	c := &Flags{}
 
 	flag.StringVar(&c.GRPC, "g", defGRPC, "gRPC port")
+	flag.StringVar(&c.HTTP, "http", "", "address of HTTP/JSON gateway, empty to disable it")
 	flag.StringVar(&c.DBDSN, "d", "", "database connection address")
 	flag.StringVar(&c.ConfigFileName, "c", "", "the name of configuration file")
 	flag.StringVar(&c.ConfigFileName, "config", "", "the name of configuration file")
 	flag.StringVar(&c.SecretKey, "k", "", "secret key for token")
+	flag.StringVar(&c.AdminKey, "admin-key", "", "admin key for the KeeperAdmin service, empty to disable it")
+	flag.IntVar(&c.HistoryRetention, "r", unsetRetention,
+		"number of previous record versions to keep, 0 to keep no history (default 10)")
+	flag.StringVar(&c.Metrics, "m", "", "address of Prometheus metrics HTTP server")
+	flag.Int64Var(&c.QuotaBytes, "quota-bytes", 0, "maximum total size of user records in bytes, 0 for no limit")
+	flag.Int64Var(&c.QuotaRecords, "quota-records", 0, "maximum number of user records of each type, 0 for no limit")
+	flag.Int64Var(&c.QuotaBinarySize, "quota-binary", 0, "maximum size of a single binary record in bytes, 0 for no limit")
+	flag.IntVar(&c.IdempotencyWindow, "idempotency-window", 0, "how long to keep results of requests with an idempotency key, in seconds")
+	flag.IntVar(&c.StorageTimeout, "storage-timeout", 0, "timeout of single record database operations, in seconds")
+	flag.IntVar(&c.StorageBulkTimeout, "storage-bulk-timeout", 0, "timeout of sync queries, listings and user deletion in the database, in seconds")
+	flag.StringVar(&c.BlobDir, "blob-dir", "", "directory for binary record contents, empty to keep them in the database")
+	flag.IntVar(&c.BlobGCInterval, "blob-gc-interval", 0, "how often to remove unused blobs, in seconds")
 	flag.Parse()
 
 	env.Parse(c)
@@ -82,6 +164,24 @@ func NewConfig() *Flags {
 			logger.ZapSugar.Infow("reading configuration file", err)
 		}
 	}
+	if c.HistoryRetention < 0 {
+		c.HistoryRetention = defHistoryRetention
+	}
+	if c.Metrics == "" {
+		c.Metrics = defMetrics
+	}
+	if c.IdempotencyWindow <= 0 {
+		c.IdempotencyWindow = defIdempotencyWindow
+	}
+	if c.StorageTimeout <= 0 {
+		c.StorageTimeout = defStorageTimeout
+	}
+	if c.StorageBulkTimeout <= 0 {
+		c.StorageBulkTimeout = defStorageBulk
+	}
+	if c.BlobGCInterval <= 0 {
+		c.BlobGCInterval = defBlobGCInterval
+	}
 
 	return c
 }
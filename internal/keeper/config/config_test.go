@@ -10,13 +10,32 @@ func TestNewConfig(t *testing.T) {
 	flags := NewConfig()
 	if assert.NotEmpty(t, flags) {
 		assert.NotEmpty(t, flags.GRPC)
+		assert.NotEmpty(t, flags.Metrics)
+		assert.Empty(t, flags.HTTP)
+		assert.Equal(t, defHistoryRetention, flags.HistoryRetention)
+		assert.Positive(t, flags.IdempotencyWindow)
+		assert.Positive(t, flags.StorageTimeout)
+		assert.Positive(t, flags.StorageBulkTimeout)
+		assert.Positive(t, flags.BlobGCInterval)
 	}
 }
 
 func TestReadFromConf(t *testing.T) {
 	c := Flags{
-		ConfigFileName: "for_tests.json",
+		ConfigFileName:   "for_tests.json",
+		HistoryRetention: unsetRetention,
 	}
 	err := readFromConf(&c)
 	assert.NoError(t, err)
+	assert.Equal(t, ":8081", c.HTTP)
+	assert.Equal(t, "fdvby", c.AdminKey)
+	assert.Equal(t, 0, c.HistoryRetention)
+	assert.Equal(t, int64(1048576), c.QuotaBytes)
+	assert.Equal(t, int64(100), c.QuotaRecords)
+	assert.Equal(t, int64(65536), c.QuotaBinarySize)
+	assert.Equal(t, 300, c.IdempotencyWindow)
+	assert.Equal(t, 5, c.StorageTimeout)
+	assert.Equal(t, 60, c.StorageBulkTimeout)
+	assert.Equal(t, "/var/lib/keeper/blobs", c.BlobDir)
+	assert.Equal(t, 600, c.BlobGCInterval)
 }
@@ -0,0 +1,355 @@
+// Пакет gateway реализует HTTP/JSON-интерфейс к методам сервиса InfoKeeper.
+// Запросы передаются той же реализации сервиса и тем же интерсепторам, что и у gRPC-сервера.
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+const (
+	// DeviceHeader - заголовок с именем устройства клиента для журнала аудита.
+	DeviceHeader = "X-Device"
+	// IdempotencyHeader - заголовок с ключом идемпотентности запроса на запись.
+	IdempotencyHeader = "Idempotency-Key"
+	// OpenAPIPath - путь к описанию HTTP-интерфейса в формате OpenAPI.
+	OpenAPIPath = "/v1/openapi.json"
+
+	ndjsonContentType = "application/x-ndjson"
+	jsonContentType   = "application/json"
+
+	// maxMessageSize - максимальный размер сообщения запроса, как у gRPC-сервера по умолчанию.
+	maxMessageSize = 4 << 20
+)
+
+// errMessageTooLarge - ошибка сообщения запроса больше maxMessageSize.
+var errMessageTooLarge = status.Errorf(codes.ResourceExhausted, "request message larger than %d bytes", maxMessageSize)
+
+// route связывает HTTP-метод и путь с методом сервиса InfoKeeper.
+type route struct {
+	httpMethod string
+	path       string
+	rpc        string
+}
+
+// routes - HTTP-методы и пути для всех методов сервиса InfoKeeper.
+// Параметры GET-запросов передаются в строке запроса, остальных - в теле запроса в формате JSON.
+// Потоковые методы принимают и возвращают сообщения в формате JSON, по одному в строке.
+var routes = []route{
+	{http.MethodPost, "/v1/users", "AddUser"},
+	{http.MethodPost, "/v1/auth", "AuthUser"},
+	{http.MethodPost, "/v1/cards", "AddCard"},
+	{http.MethodGet, "/v1/cards", "GetUserCard"},
+	{http.MethodPut, "/v1/cards", "ForceUpdateCard"},
+	{http.MethodPost, "/v1/logins", "AddLogin"},
+	{http.MethodGet, "/v1/logins", "GetUserLogin"},
+	{http.MethodPut, "/v1/logins", "ForceUpdateLoginPwd"},
+	{http.MethodPost, "/v1/texts", "AddTextData"},
+	{http.MethodGet, "/v1/texts", "GetUserText"},
+	{http.MethodPut, "/v1/texts", "ForceUpdateTextRecord"},
+	{http.MethodPost, "/v1/binaries", "AddBinaryData"},
+	{http.MethodGet, "/v1/binaries", "GetUserBinary"},
+	{http.MethodPut, "/v1/binaries", "ForceUpdateBinaryRecord"},
+	{http.MethodPost, "/v1/binaries/upload", "UploadBinary"},
+	{http.MethodGet, "/v1/binaries/download", "DownloadBinary"},
+	{http.MethodPost, "/v1/sync", "SyncUserData"},
+	{http.MethodGet, "/v1/records", "ListRecords"},
+	{http.MethodPost, "/v1/records", "AddRecord"},
+	{http.MethodPut, "/v1/records", "ForceUpdateRecord"},
+	{http.MethodGet, "/v1/records/record", "GetRecord"},
+	{http.MethodGet, "/v1/records/versions", "ListVersions"},
+	{http.MethodPost, "/v1/records/versions/restore", "RestoreVersion"},
+	{http.MethodGet, "/v1/changes", "WatchChanges"},
+	{http.MethodGet, "/v1/audit", "GetAuditLog"},
+	{http.MethodGet, "/v1/usage", "GetUsage"},
+	{http.MethodPut, "/v1/keys", "SetUserKeys"},
+	{http.MethodGet, "/v1/keys", "GetUserKeys"},
+	{http.MethodGet, "/v1/keys/public", "GetPublicKey"},
+	{http.MethodPost, "/v1/organizations", "CreateOrganization"},
+	{http.MethodPost, "/v1/collections", "CreateCollection"},
+	{http.MethodGet, "/v1/collections", "ListCollections"},
+	{http.MethodPost, "/v1/collections/members", "AddCollectionMember"},
+	{http.MethodPost, "/v1/collections/records", "MoveRecordToCollection"},
+	{http.MethodGet, "/v1/collections/records", "ListCollectionRecords"},
+	{http.MethodPost, "/v1/emergency/contacts", "AddEmergencyContact"},
+	{http.MethodPut, "/v1/emergency/contacts", "UpdateEmergencyRecords"},
+	{http.MethodGet, "/v1/emergency/contacts", "ListEmergencyContacts"},
+	{http.MethodPost, "/v1/emergency/contacts/remove", "RemoveEmergencyContact"},
+	{http.MethodPost, "/v1/emergency/requests", "RequestEmergencyAccess"},
+	{http.MethodPost, "/v1/emergency/requests/decline", "DeclineEmergencyAccess"},
+	{http.MethodGet, "/v1/emergency/key", "GetEmergencyKey"},
+}
+
+// handler вызывает метод сервиса по HTTP-запросу.
+type handler func(w http.ResponseWriter, r *http.Request)
+
+// Gateway обрабатывает HTTP-запросы к методам сервиса InfoKeeper.
+type Gateway struct {
+	srv      pb.InfoKeeperServer
+	unary    grpc.UnaryServerInterceptor
+	stream   grpc.StreamServerInterceptor
+	handlers map[string]map[string]handler
+	openAPI  []byte
+}
+
+// New создает HTTP-интерфейс к реализации сервиса srv.
+// Интерсепторы unary и stream вызываются в указанном порядке, как в grpc.ChainUnaryInterceptor.
+func New(srv pb.InfoKeeperServer, unary []grpc.UnaryServerInterceptor, stream []grpc.StreamServerInterceptor) (*Gateway, error) {
+	g := &Gateway{
+		srv:      srv,
+		unary:    chainUnary(unary),
+		stream:   chainStream(stream),
+		handlers: make(map[string]map[string]handler),
+	}
+
+	unaryMethods := make(map[string]grpc.MethodDesc)
+	for _, m := range pb.InfoKeeper_ServiceDesc.Methods {
+		unaryMethods[m.MethodName] = m
+	}
+	streamMethods := make(map[string]grpc.StreamDesc)
+	for _, s := range pb.InfoKeeper_ServiceDesc.Streams {
+		streamMethods[s.StreamName] = s
+	}
+
+	for _, rt := range routes {
+		var h handler
+		if m, ok := unaryMethods[rt.rpc]; ok {
+			h = g.unaryHandler(rt, m)
+		} else if s, ok := streamMethods[rt.rpc]; ok {
+			h = g.streamHandler(rt, s)
+		} else {
+			return nil, status.Errorf(codes.Internal, "unknown method %s", rt.rpc)
+		}
+		if g.handlers[rt.path] == nil {
+			g.handlers[rt.path] = make(map[string]handler)
+		}
+		g.handlers[rt.path][rt.httpMethod] = h
+	}
+
+	doc, err := buildOpenAPI()
+	if err != nil {
+		return nil, err
+	}
+	g.openAPI = doc
+
+	return g, nil
+}
+
+// ServeHTTP находит метод сервиса по пути и HTTP-методу запроса и вызывает его.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == OpenAPIPath && r.Method == http.MethodGet {
+		w.Header().Set("Content-Type", jsonContentType)
+		w.Write(g.openAPI)
+		return
+	}
+
+	methods, ok := g.handlers[r.URL.Path]
+	if !ok {
+		writeError(w, status.Error(codes.NotFound, "unknown path"))
+		return
+	}
+	h, ok := methods[r.Method]
+	if !ok {
+		allowed := make([]string, 0, len(methods))
+		for m := range methods {
+			allowed = append(allowed, m)
+		}
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeErrorWithCode(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method not allowed"))
+		return
+	}
+	h(w, r)
+}
+
+// fullMethod возвращает полное имя метода сервиса InfoKeeper.
+func fullMethod(rpc string) string {
+	return "/" + pb.InfoKeeper_ServiceDesc.ServiceName + "/" + rpc
+}
+
+// incomingContext переносит токен, имя устройства, ключ идемпотентности
+// и адрес клиента из HTTP-запроса в контекст метода сервиса.
+func incomingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		token, ok := strings.CutPrefix(auth, "Bearer ")
+		if ok {
+			md.Set(authorizer.AccessToken, strings.TrimSpace(token))
+		}
+	}
+	if device := r.Header.Get(DeviceHeader); device != "" {
+		md.Set(authorizer.DeviceName, device)
+	}
+	if key := r.Header.Get(IdempotencyHeader); key != "" {
+		md.Set(authorizer.IdempotencyKey, key)
+	}
+
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+	return ctx
+}
+
+// decodeRequest заполняет сообщение req из строки GET-запроса или из тела остальных запросов.
+func decodeRequest(r *http.Request, req proto.Message) error {
+	if r.Method == http.MethodGet {
+		return setQuery(req.ProtoReflect(), r.URL.Query())
+	}
+	body, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return errMessageTooLarge
+	}
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	if err = protojson.Unmarshal(body, req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// unaryHandler создает обработчик для метода m.
+func (g *Gateway) unaryHandler(rt route, m grpc.MethodDesc) handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+		dec := func(v interface{}) error {
+			return decodeRequest(r, v.(proto.Message))
+		}
+		resp, err := m.Handler(g.srv, incomingContext(r), dec, g.unaryInterceptor(rt.rpc))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeMessage(w, http.StatusOK, resp.(proto.Message))
+	}
+}
+
+// unaryInterceptor возвращает цепочку интерсепторов с информацией о методе rpc.
+// Сгенерированный обработчик передает в интерсептор свою информацию о методе,
+// поэтому сервер в ней заменяется реализацией сервиса.
+func (g *Gateway) unaryInterceptor(rpc string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return g.unary(ctx, req, &grpc.UnaryServerInfo{Server: g.srv, FullMethod: fullMethod(rpc)}, handler)
+	}
+}
+
+// streamHandler создает обработчик для потокового метода s.
+func (g *Gateway) streamHandler(rt route, s grpc.StreamDesc) handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Размер сообщений клиентского потока ограничивается при чтении каждого сообщения.
+		if !s.ClientStreams {
+			r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+		}
+		ss := newServerStream(incomingContext(r), w, r, s.ClientStreams)
+		info := &grpc.StreamServerInfo{
+			FullMethod:     fullMethod(rt.rpc),
+			IsClientStream: s.ClientStreams,
+			IsServerStream: s.ServerStreams,
+		}
+		err := g.stream(g.srv, ss, info, s.Handler)
+		ss.finish(err)
+	}
+}
+
+// chainUnary объединяет интерсепторы в один, первый интерсептор вызывается первым.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, h := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, h)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// chainStream объединяет потоковые интерсепторы в один, первый интерсептор вызывается первым.
+func chainStream(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, h := interceptors[i], next
+			next = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, h)
+			}
+		}
+		return next(srv, ss)
+	}
+}
+
+// marshaler кодирует ответы с именами полей из proto-файла и пустыми значениями.
+var marshaler = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// writeMessage записывает сообщение msg в ответ в формате JSON.
+func writeMessage(w http.ResponseWriter, code int, msg proto.Message) {
+	b, err := marshaler.Marshal(msg)
+	if err != nil {
+		writeError(w, status.Error(codes.Internal, err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", jsonContentType)
+	w.WriteHeader(code)
+	w.Write(b)
+}
+
+// writeError записывает ошибку метода сервиса в ответ с соответствующим HTTP-кодом.
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	writeErrorWithCode(w, httpStatus(st.Code()), st)
+}
+
+// writeErrorWithCode записывает статус st в ответ с HTTP-кодом code.
+func writeErrorWithCode(w http.ResponseWriter, code int, st *status.Status) {
+	writeMessage(w, code, st.Proto())
+}
+
+// httpStatus возвращает HTTP-код, соответствующий коду gRPC.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,261 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/interceptors"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+type testServer struct {
+	pb.UnimplementedInfoKeeperServer
+}
+
+func (s *testServer) AddUser(ctx context.Context, req *pb.AddUserRequest) (*pb.AddUserResponse, error) {
+	return &pb.AddUserResponse{Token: "token-" + req.Login}, nil
+}
+
+func (s *testServer) GetRecord(ctx context.Context, req *pb.GetRecordRequest) (*pb.GetRecordResponse, error) {
+	token := ctx.Value(authorizer.UserContextKey).(string)
+	md, _ := metadata.FromIncomingContext(ctx)
+	p, _ := peer.FromContext(ctx)
+	if token != "utoken" || md.Get(authorizer.DeviceName)[0] != "laptop" || p == nil {
+		return nil, status.Error(codes.PermissionDenied, "unexpected context")
+	}
+	return &pb.GetRecordResponse{Record: &pb.Record{
+		Type:    req.Key.Type,
+		Payload: &pb.Record_TextRecord{TextRecord: &pb.UserTextRecord{Prompt: req.Key.Prompt}},
+	}}, nil
+}
+
+func (s *testServer) AddRecord(ctx context.Context, req *pb.AddRecordRequest) (*pb.AddRecordResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get(authorizer.IdempotencyKey)) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no idempotency key")
+	}
+	return nil, status.Error(codes.AlreadyExists, "record already exists")
+}
+
+func (s *testServer) UploadBinary(stream pb.InfoKeeper_UploadBinaryServer) error {
+	var size int64
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		size += int64(len(req.GetChunk()))
+	}
+	return stream.SendAndClose(&pb.UploadBinaryResponse{Revision: &pb.RecordRevision{RecordId: size}})
+}
+
+func (s *testServer) WatchChanges(req *pb.WatchChangesRequest, stream pb.InfoKeeper_WatchChangesServer) error {
+	for _, ts := range []string{"2024-01-02T15:04:05Z", "2024-01-02T15:04:06Z"} {
+		if err := stream.Send(&pb.ChangeEvent{TimeStamp: ts}); err != nil {
+			return err
+		}
+	}
+	return status.Error(codes.Unavailable, "server is stopping")
+}
+
+func TestGateway(t *testing.T) {
+	gw, err := New(&testServer{},
+		[]grpc.UnaryServerInterceptor{interceptors.HandlerWithAuth},
+		[]grpc.StreamServerInterceptor{interceptors.StreamHandlerWithAuth})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		headers  map[string]string
+		body     string
+		wantCode int
+		wantType string
+		wantBody []string
+	}{
+		{
+			name:     "ok public method test",
+			method:   http.MethodPost,
+			path:     "/v1/users",
+			body:     `{"login":"ulogin","pwd":"upwd"}`,
+			wantCode: http.StatusOK,
+			wantType: jsonContentType,
+			wantBody: []string{`"token":"token-ulogin"`},
+		},
+		{
+			name:     "invalid body test",
+			method:   http.MethodPost,
+			path:     "/v1/users",
+			body:     `{"login":`,
+			wantCode: http.StatusBadRequest,
+			wantBody: []string{`"code":3`},
+		},
+		{
+			name:     "ok query test",
+			method:   http.MethodGet,
+			path:     "/v1/records/record?key.type=RECORD_TYPE_TEXT&key.prompt=cHJvbXB0",
+			headers:  map[string]string{"Authorization": "Bearer utoken", DeviceHeader: "laptop"},
+			wantCode: http.StatusOK,
+			wantType: jsonContentType,
+			wantBody: []string{`"type":"RECORD_TYPE_TEXT"`, `"prompt":"cHJvbXB0"`},
+		},
+		{
+			name:     "unknown parameter test",
+			method:   http.MethodGet,
+			path:     "/v1/records/record?key.name=1",
+			headers:  map[string]string{"Authorization": "Bearer utoken"},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "missing token test",
+			method:   http.MethodGet,
+			path:     "/v1/records/record?key.type=3",
+			wantCode: http.StatusInternalServerError,
+			wantBody: []string{`"message":"missing token"`},
+		},
+		{
+			name:     "error status test",
+			method:   http.MethodPost,
+			path:     "/v1/records",
+			headers:  map[string]string{"Authorization": "Bearer utoken", IdempotencyHeader: "key1"},
+			body:     `{"record":{"type":"RECORD_TYPE_TEXT"}}`,
+			wantCode: http.StatusConflict,
+			wantBody: []string{`"message":"record already exists"`},
+		},
+		{
+			name:     "unimplemented test",
+			method:   http.MethodGet,
+			path:     "/v1/usage",
+			headers:  map[string]string{"Authorization": "Bearer utoken"},
+			wantCode: http.StatusNotImplemented,
+		},
+		{
+			name:     "ok client stream test",
+			method:   http.MethodPost,
+			path:     "/v1/binaries/upload",
+			headers:  map[string]string{"Authorization": "Bearer utoken"},
+			body:     "{\"info\":{\"size\":5}}\n{\"chunk\":\"YWJj\"}\n{\"chunk\":\"ZGU=\"}\n",
+			wantCode: http.StatusOK,
+			wantType: jsonContentType,
+			wantBody: []string{`"record_id":"5"`},
+		},
+		{
+			name:     "client stream larger than message size test",
+			method:   http.MethodPost,
+			path:     "/v1/binaries/upload",
+			headers:  map[string]string{"Authorization": "Bearer utoken"},
+			body:     strings.Repeat("{\"chunk\":\""+strings.Repeat("A", 2<<20)+"\"}\n", 3),
+			wantCode: http.StatusOK,
+			wantBody: []string{`"record_id":"4718592"`},
+		},
+		{
+			name:     "client stream message too large test",
+			method:   http.MethodPost,
+			path:     "/v1/binaries/upload",
+			headers:  map[string]string{"Authorization": "Bearer utoken"},
+			body:     "{\"chunk\":\"" + strings.Repeat("A", maxMessageSize) + "\"}\n",
+			wantCode: http.StatusTooManyRequests,
+			wantBody: []string{`"code":8`},
+		},
+		{
+			name:     "body too large test",
+			method:   http.MethodPost,
+			path:     "/v1/users",
+			body:     `{"login":"` + strings.Repeat("a", maxMessageSize) + `"}`,
+			wantCode: http.StatusTooManyRequests,
+			wantBody: []string{`"code":8`},
+		},
+		{
+			name:     "ok server stream test",
+			method:   http.MethodGet,
+			path:     "/v1/changes",
+			headers:  map[string]string{"Authorization": "Bearer utoken"},
+			wantCode: http.StatusOK,
+			wantType: ndjsonContentType,
+			wantBody: []string{`"time_stamp":"2024-01-02T15:04:05Z"`, `"time_stamp":"2024-01-02T15:04:06Z"`,
+				`{"error":{"code":14`},
+		},
+		{
+			name:     "unknown path test",
+			method:   http.MethodGet,
+			path:     "/v1/unknown",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "method not allowed test",
+			method:   http.MethodDelete,
+			path:     "/v1/cards",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:     "openapi test",
+			method:   http.MethodGet,
+			path:     OpenAPIPath,
+			wantCode: http.StatusOK,
+			wantType: jsonContentType,
+			wantBody: []string{`"openapi":"3.0.3"`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			gw.ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.wantCode, res.StatusCode, string(body))
+			if test.wantType != "" {
+				assert.Equal(t, test.wantType, res.Header.Get("Content-Type"))
+			}
+			// protojson может добавлять пробелы между элементами.
+			compact := strings.NewReplacer(`": `, `":`, `, "`, `,"`).Replace(string(body))
+			for _, want := range test.wantBody {
+				assert.Contains(t, compact, want)
+			}
+		})
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	rpcs := make(map[string]bool)
+	for _, rt := range routes {
+		rpcs[rt.rpc] = true
+	}
+	for _, m := range pb.InfoKeeper_ServiceDesc.Methods {
+		assert.True(t, rpcs[m.MethodName], m.MethodName)
+	}
+	for _, s := range pb.InfoKeeper_ServiceDesc.Streams {
+		assert.True(t, rpcs[s.StreamName], s.StreamName)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	assert.Equal(t, http.StatusOK, httpStatus(codes.OK))
+	assert.Equal(t, http.StatusNotFound, httpStatus(codes.NotFound))
+	assert.Equal(t, http.StatusTooManyRequests, httpStatus(codes.ResourceExhausted))
+	assert.Equal(t, http.StatusInternalServerError, httpStatus(codes.DataLoss))
+}
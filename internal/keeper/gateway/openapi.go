@@ -0,0 +1,215 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/idempotency"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// obj - объект описания OpenAPI.
+type obj = map[string]interface{}
+
+const statusSchema = "Status"
+
+// schemaName возвращает имя схемы сообщения или перечисления без имени пакета.
+func schemaName(d protoreflect.Descriptor) string {
+	return strings.TrimPrefix(string(d.FullName()), string(d.ParentFile().Package())+".")
+}
+
+// schemaRef возвращает ссылку на схему с именем name.
+func schemaRef(name string) obj {
+	return obj{"$ref": "#/components/schemas/" + name}
+}
+
+// openAPISchemas собирает схемы сообщений и перечислений, используемых в методах.
+type openAPISchemas map[string]interface{}
+
+// addMessage добавляет схему сообщения md и всех вложенных в его поля сообщений.
+func (s openAPISchemas) addMessage(md protoreflect.MessageDescriptor) obj {
+	name := schemaName(md)
+	if _, ok := s[name]; ok {
+		return schemaRef(name)
+	}
+	props := obj{}
+	schema := obj{"type": "object", "properties": props}
+	s[name] = schema
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		props[string(fd.Name())] = s.field(fd)
+	}
+	return schemaRef(name)
+}
+
+// addEnum добавляет схему перечисления ed.
+func (s openAPISchemas) addEnum(ed protoreflect.EnumDescriptor) obj {
+	name := schemaName(ed)
+	if _, ok := s[name]; !ok {
+		values := ed.Values()
+		names := make([]string, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		s[name] = obj{"type": "string", "enum": names}
+	}
+	return schemaRef(name)
+}
+
+// field возвращает схему поля fd в формате protojson.
+func (s openAPISchemas) field(fd protoreflect.FieldDescriptor) obj {
+	if fd.IsMap() {
+		return obj{"type": "object", "additionalProperties": s.scalar(fd.MapValue())}
+	}
+	schema := s.scalar(fd)
+	if fd.IsList() {
+		schema = obj{"type": "array", "items": schema}
+	}
+	if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts.GetDeprecated() {
+		schema = obj{"allOf": []interface{}{schema}, "deprecated": true}
+	}
+	return schema
+}
+
+// scalar возвращает схему одного значения поля fd.
+func (s openAPISchemas) scalar(fd protoreflect.FieldDescriptor) obj {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return obj{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return obj{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return obj{"type": "integer", "format": "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return obj{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return obj{"type": "number"}
+	case protoreflect.BytesKind:
+		return obj{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		return s.addEnum(fd.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return s.addMessage(fd.Message())
+	default:
+		return obj{"type": "string"}
+	}
+}
+
+// queryParameters возвращает параметры строки запроса для полей сообщения md.
+// Поля вложенных сообщений задаются через точку.
+func (s openAPISchemas) queryParameters(md protoreflect.MessageDescriptor, prefix string) []interface{} {
+	params := []interface{}{}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := prefix + string(fd.Name())
+		if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() {
+			params = append(params, s.queryParameters(fd.Message(), name+".")...)
+			continue
+		}
+		params = append(params, obj{"name": name, "in": "query", "schema": s.field(fd)})
+	}
+	return params
+}
+
+// operation возвращает описание HTTP-метода для маршрута rt.
+func (s openAPISchemas) operation(rt route, md protoreflect.MethodDescriptor) obj {
+	op := obj{"operationId": rt.rpc}
+
+	params := []interface{}{}
+	public := rt.rpc == "AddUser" || rt.rpc == "AuthUser"
+	if !public {
+		op["security"] = []interface{}{obj{"bearerAuth": []interface{}{}}}
+		params = append(params, obj{"name": DeviceHeader, "in": "header", "schema": obj{"type": "string"},
+			"description": "Client device name for the audit log."})
+	}
+	if idempotency.IsWriteMethod(fullMethod(rt.rpc)) {
+		params = append(params, obj{"name": IdempotencyHeader, "in": "header", "schema": obj{"type": "string"},
+			"description": "Retries with the same key replay the first response."})
+	}
+
+	switch {
+	case md.IsStreamingClient():
+		op["requestBody"] = obj{
+			"required":    true,
+			"description": "Stream of messages, one JSON object per line.",
+			"content":     obj{ndjsonContentType: obj{"schema": s.addMessage(md.Input())}},
+		}
+	case rt.httpMethod == http.MethodGet:
+		params = append(params, s.queryParameters(md.Input(), "")...)
+	default:
+		op["requestBody"] = obj{
+			"required": true,
+			"content":  obj{jsonContentType: obj{"schema": s.addMessage(md.Input())}},
+		}
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	okResp := obj{"description": "OK"}
+	if md.IsStreamingServer() {
+		okResp["description"] = "Stream of messages, one JSON object per line."
+		okResp["content"] = obj{ndjsonContentType: obj{"schema": s.addMessage(md.Output())}}
+	} else {
+		okResp["content"] = obj{jsonContentType: obj{"schema": s.addMessage(md.Output())}}
+	}
+	op["responses"] = obj{
+		"200": okResp,
+		"default": obj{
+			"description": "Error",
+			"content":     obj{jsonContentType: obj{"schema": schemaRef(statusSchema)}},
+		},
+	}
+	return op
+}
+
+// buildOpenAPI создает описание HTTP-интерфейса в формате OpenAPI 3.
+func buildOpenAPI() ([]byte, error) {
+	sd := pb.File_keeper_proto.Services().ByName("InfoKeeper")
+	schemas := openAPISchemas{
+		statusSchema: obj{
+			"type": "object",
+			"properties": obj{
+				"code":    obj{"type": "integer", "format": "int32"},
+				"message": obj{"type": "string"},
+				"details": obj{"type": "array", "items": obj{"type": "object"}},
+			},
+		},
+	}
+
+	paths := obj{}
+	for _, rt := range routes {
+		item, ok := paths[rt.path].(obj)
+		if !ok {
+			item = obj{}
+			paths[rt.path] = item
+		}
+		item[strings.ToLower(rt.httpMethod)] = schemas.operation(rt, sd.Methods().ByName(protoreflect.Name(rt.rpc)))
+	}
+
+	doc := obj{
+		"openapi": "3.0.3",
+		"info": obj{
+			"title":       "InfoKeeper HTTP API",
+			"version":     "v1",
+			"description": "HTTP/JSON interface to the InfoKeeper gRPC service.",
+		},
+		"paths": paths,
+		"components": obj{
+			"schemas":         map[string]interface{}(schemas),
+			"securitySchemes": obj{"bearerAuth": obj{"type": "http", "scheme": "bearer"}},
+		},
+	}
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,28 @@
+package gateway
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAPIFile - описание HTTP-интерфейса, поставляемое вместе с сервером.
+const openAPIFile = "../../../api/openapi.json"
+
+var update = flag.Bool("update", false, "update "+openAPIFile)
+
+func TestBuildOpenAPI(t *testing.T) {
+	doc, err := buildOpenAPI()
+	require.NoError(t, err)
+
+	if *update {
+		require.NoError(t, os.WriteFile(openAPIFile, doc, 0644))
+	}
+	want, err := os.ReadFile(openAPIFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(doc),
+		"api/openapi.json is out of date, run: go test ./internal/keeper/gateway -run TestBuildOpenAPI -update")
+}
@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// setQuery заполняет сообщение msg параметрами строки запроса.
+// Поля вложенных сообщений задаются через точку, например key.prompt,
+// байты передаются в base64, перечисления - по имени или номеру.
+func setQuery(msg protoreflect.Message, values url.Values) error {
+	obj := map[string]interface{}{}
+	for name, vals := range values {
+		if err := setQueryValue(obj, msg.Descriptor(), strings.Split(name, "."), vals); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = protojson.Unmarshal(b, msg.Interface()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// setQueryValue добавляет в JSON-объект obj значения vals поля с путем path.
+func setQueryValue(obj map[string]interface{}, md protoreflect.MessageDescriptor, path []string, vals []string) error {
+	fd := fieldByName(md, path[0])
+	if fd == nil {
+		return status.Errorf(codes.InvalidArgument, "unknown parameter %s", path[0])
+	}
+	name := string(fd.Name())
+
+	if fd.Kind() == protoreflect.MessageKind {
+		if len(path) == 1 || fd.IsList() || fd.IsMap() {
+			return status.Errorf(codes.InvalidArgument, "unsupported parameter %s", path[0])
+		}
+		nested, ok := obj[name].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			obj[name] = nested
+		}
+		return setQueryValue(nested, fd.Message(), path[1:], vals)
+	}
+	if len(path) > 1 {
+		return status.Errorf(codes.InvalidArgument, "unknown parameter %s", strings.Join(path, "."))
+	}
+
+	list := make([]json.RawMessage, 0, len(vals))
+	for _, v := range vals {
+		raw, err := jsonValue(fd, v)
+		if err != nil {
+			return err
+		}
+		list = append(list, raw)
+	}
+	if fd.IsList() {
+		obj[name] = list
+	} else if len(list) > 0 {
+		obj[name] = list[len(list)-1]
+	}
+	return nil
+}
+
+// fieldByName ищет поле сообщения по имени из proto-файла или по имени в JSON.
+func fieldByName(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+	return md.Fields().ByJSONName(name)
+}
+
+// jsonValue возвращает значение параметра строки запроса в виде JSON для поля fd.
+// Числа и байты передаются строкой, protojson принимает их в таком виде.
+func jsonValue(fd protoreflect.FieldDescriptor, value string) (json.RawMessage, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid value for %s: %s", fd.Name(), value)
+		}
+		return json.Marshal(b)
+	case protoreflect.EnumKind:
+		if _, err := strconv.ParseInt(value, 10, 32); err == nil {
+			return json.RawMessage(value), nil
+		}
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,144 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// serverStream реализует grpc.ServerStream поверх HTTP-запроса.
+// Сообщения клиентского потока читаются из тела запроса, по одному JSON-объекту в строке.
+// Сообщения серверного потока записываются в ответ так же и отправляются клиенту сразу.
+type serverStream struct {
+	ctx           context.Context
+	w             http.ResponseWriter
+	r             *http.Request
+	clientStreams bool
+	body          *messageReader
+	dec           *json.Decoder
+	received      bool
+	wrote         bool
+}
+
+func newServerStream(ctx context.Context, w http.ResponseWriter, r *http.Request, clientStreams bool) *serverStream {
+	body := &messageReader{r: r.Body}
+	return &serverStream{
+		ctx:           ctx,
+		w:             w,
+		r:             r,
+		clientStreams: clientStreams,
+		body:          body,
+		dec:           json.NewDecoder(body),
+	}
+}
+
+// messageReader ограничивает объем тела запроса, читаемый для одного сообщения клиентского потока.
+type messageReader struct {
+	r    io.Reader
+	left int64
+}
+
+// Read читает тело запроса, пока не исчерпан объем, оставшийся для текущего сообщения.
+func (m *messageReader) Read(p []byte) (int, error) {
+	if m.left <= 0 {
+		return 0, errMessageTooLarge
+	}
+	if int64(len(p)) > m.left {
+		p = p[:m.left]
+	}
+	n, err := m.r.Read(p)
+	m.left -= int64(n)
+	return n, err
+}
+
+// SetHeader не используется, заголовки gRPC не передаются в HTTP-ответ.
+func (s *serverStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+// SendHeader не используется, заголовки gRPC не передаются в HTTP-ответ.
+func (s *serverStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+// SetTrailer не используется, трейлеры gRPC не передаются в HTTP-ответ.
+func (s *serverStream) SetTrailer(metadata.MD) {}
+
+// Context возвращает контекст метода с метаданными HTTP-запроса.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// SendMsg записывает сообщение в ответ.
+func (s *serverStream) SendMsg(m interface{}) error {
+	b, err := marshaler.Marshal(m.(proto.Message))
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if !s.wrote {
+		if s.clientStreams {
+			s.w.Header().Set("Content-Type", jsonContentType)
+		} else {
+			s.w.Header().Set("Content-Type", ndjsonContentType)
+		}
+		s.w.WriteHeader(http.StatusOK)
+		s.wrote = true
+	}
+	if _, err = s.w.Write(append(b, '\n')); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// RecvMsg читает следующее сообщение клиента.
+// Для методов с одним запросом сообщение заполняется так же, как для непотоковых методов.
+func (s *serverStream) RecvMsg(m interface{}) error {
+	if !s.clientStreams {
+		if s.received {
+			return io.EOF
+		}
+		s.received = true
+		return decodeRequest(s.r, m.(proto.Message))
+	}
+
+	var raw json.RawMessage
+	s.body.left = maxMessageSize
+	if err := s.dec.Decode(&raw); err != nil {
+		if err == io.EOF || err == errMessageTooLarge {
+			return err
+		}
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := protojson.Unmarshal(raw, m.(proto.Message)); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// finish записывает ошибку метода в ответ.
+// Если часть ответа уже отправлена, ошибка записывается последней строкой в виде {"error": ...}.
+func (s *serverStream) finish(err error) {
+	if err == nil {
+		return
+	}
+	if !s.wrote {
+		writeError(s.w, err)
+		return
+	}
+	b, mErr := marshaler.Marshal(status.Convert(err).Proto())
+	if mErr != nil {
+		return
+	}
+	line, _ := json.Marshal(map[string]json.RawMessage{"error": b})
+	s.w.Write(append(line, '\n'))
+}
@@ -0,0 +1,116 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// AdminGRPCServer реализует методы администрирования сервера.
+// Доступ к методам проверяется интерсептором interceptors.AdminAuth.
+type AdminGRPCServer struct {
+	pb.UnimplementedKeeperAdminServer
+	stor    storage.Repositorier
+	started time.Time
+}
+
+// NewAdminServer создает объект с репозиторием для методов администрирования.
+func NewAdminServer(stor storage.Repositorier) *AdminGRPCServer {
+	return &AdminGRPCServer{
+		stor:    stor,
+		started: time.Now(),
+	}
+}
+
+// ListUsers возвращает пользователей с количеством и размером их записей.
+func (as *AdminGRPCServer) ListUsers(ctx context.Context, in *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	users, err := as.stor.ListUsers(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &pb.ListUsersResponse{Users: make([]*pb.AdminUser, 0, len(users))}
+	for _, u := range users {
+		res.Users = append(res.Users, &pb.AdminUser{
+			Login:    u.Login,
+			Disabled: u.Disabled,
+			Records:  u.Records,
+			Bytes:    u.Bytes,
+		})
+	}
+	return res, nil
+}
+
+// DisableUser блокирует учетную запись пользователя.
+func (as *AdminGRPCServer) DisableUser(ctx context.Context, in *pb.DisableUserRequest) (*pb.DisableUserResponse, error) {
+	if in.GetLogin() == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty login")
+	}
+	if err := as.stor.SetUserDisabled(ctx, in.GetLogin(), true); err != nil {
+		return nil, storErrToStatus(err)
+	}
+	return &pb.DisableUserResponse{}, nil
+}
+
+// EnableUser разблокирует учетную запись пользователя.
+func (as *AdminGRPCServer) EnableUser(ctx context.Context, in *pb.EnableUserRequest) (*pb.EnableUserResponse, error) {
+	if in.GetLogin() == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty login")
+	}
+	if err := as.stor.SetUserDisabled(ctx, in.GetLogin(), false); err != nil {
+		return nil, storErrToStatus(err)
+	}
+	return &pb.EnableUserResponse{}, nil
+}
+
+// LogoutUser завершает все сессии пользователя.
+func (as *AdminGRPCServer) LogoutUser(ctx context.Context, in *pb.LogoutUserRequest) (*pb.LogoutUserResponse, error) {
+	if in.GetLogin() == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty login")
+	}
+	if err := as.stor.LogoutUser(ctx, in.GetLogin()); err != nil {
+		return nil, storErrToStatus(err)
+	}
+	return &pb.LogoutUserResponse{}, nil
+}
+
+// DeleteUser удаляет учетную запись пользователя и все его записи.
+func (as *AdminGRPCServer) DeleteUser(ctx context.Context, in *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	if in.GetLogin() == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty login")
+	}
+	if err := as.stor.DeleteUser(ctx, in.GetLogin()); err != nil {
+		return nil, storErrToStatus(err)
+	}
+	return &pb.DeleteUserResponse{}, nil
+}
+
+// GetServerStats возвращает статистику сервера.
+func (as *AdminGRPCServer) GetServerStats(ctx context.Context, in *pb.GetServerStatsRequest) (*pb.GetServerStatsResponse, error) {
+	users, err := as.stor.ListUsers(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	dbStats := as.stor.Stats()
+	res := &pb.GetServerStatsResponse{
+		Users:            int64(len(users)),
+		UptimeSeconds:    int64(time.Since(as.started).Seconds()),
+		OpenConnections:  int32(dbStats.OpenConnections),
+		InUseConnections: int32(dbStats.InUse),
+		IdleConnections:  int32(dbStats.Idle),
+	}
+	for _, u := range users {
+		if u.Disabled {
+			res.DisabledUsers++
+		}
+		res.Records += u.Records
+		res.Bytes += u.Bytes
+	}
+	return res, nil
+}
@@ -0,0 +1,180 @@
+package grpcserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/mocks"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+func TestUserLoginSession(t *testing.T) {
+	userToken, err := authorizer.BuildSessionToken(testUserLogin, testUserPwd, testCfg.SecretKey, 7, 2)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+
+	tests := []struct {
+		name       string
+		session    storage.Session
+		sessionErr error
+		wantCode   codes.Code
+	}{
+		{name: "ok test", session: storage.Session{UserID: 7, Version: 2}, wantCode: codes.OK},
+		{name: "disabled test", session: storage.Session{UserID: 7, Version: 2, Disabled: true}, wantCode: codes.PermissionDenied},
+		{name: "logout test", session: storage.Session{UserID: 7, Version: 3}, wantCode: codes.Unauthenticated},
+		{name: "deleted user test", session: storage.Session{UserID: 8}, wantCode: codes.Unauthenticated},
+		{name: "not found test", sessionErr: storage.NewStorError(storage.EmptyResult, sql.ErrNoRows), wantCode: codes.Unauthenticated},
+		{name: "storage error test", sessionErr: errors.New("db error"), wantCode: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().GetSession(gomock.Any(), testUserLogin).Return(tt.session, tt.sessionErr)
+			testGRPC := NewKeeperServer(m, testCfg)
+
+			login, err := testGRPC.userLogin(ctx)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, testUserLogin, login)
+			}
+		})
+	}
+}
+
+func TestAuthUserDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockRepositorier(ctrl)
+	m.EXPECT().AuthUser(gomock.Any(), testUserLogin, testUserPwd).Return(nil)
+	m.EXPECT().GetSession(gomock.Any(), testUserLogin).Return(storage.Session{UserID: 7, Disabled: true}, nil)
+	testGRPC := NewKeeperServer(m, testCfg)
+
+	_, err := testGRPC.AuthUser(context.Background(), &pb.AuthUserRequest{Login: testUserLogin, Pwd: testUserPwd})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAdminUsers(t *testing.T) {
+	notFound := storage.NewStorError(storage.EmptyResult, errors.New("user not found"))
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		call     func(as *AdminGRPCServer) error
+		wantCode codes.Code
+	}{
+		{
+			name: "disable test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().SetUserDisabled(gomock.Any(), testUserLogin, true).Return(nil)
+			},
+			call: func(as *AdminGRPCServer) error {
+				_, err := as.DisableUser(context.Background(), &pb.DisableUserRequest{Login: testUserLogin})
+				return err
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "enable not found test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().SetUserDisabled(gomock.Any(), testUserLogin, false).Return(notFound)
+			},
+			call: func(as *AdminGRPCServer) error {
+				_, err := as.EnableUser(context.Background(), &pb.EnableUserRequest{Login: testUserLogin})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "logout test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().LogoutUser(gomock.Any(), testUserLogin).Return(nil)
+			},
+			call: func(as *AdminGRPCServer) error {
+				_, err := as.LogoutUser(context.Background(), &pb.LogoutUserRequest{Login: testUserLogin})
+				return err
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "delete error test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().DeleteUser(gomock.Any(), testUserLogin).Return(errors.New("db error"))
+			},
+			call: func(as *AdminGRPCServer) error {
+				_, err := as.DeleteUser(context.Background(), &pb.DeleteUserRequest{Login: testUserLogin})
+				return err
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name: "empty login test",
+			call: func(as *AdminGRPCServer) error {
+				_, err := as.DeleteUser(context.Background(), &pb.DeleteUserRequest{})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			err := tt.call(NewAdminServer(m))
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestAdminListUsersAndStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := []storage.UserInfo{
+		{Login: "alice", Records: 3, Bytes: 120},
+		{Login: "bob", Disabled: true, Records: 1, Bytes: 30},
+	}
+	m := mocks.NewMockRepositorier(ctrl)
+	m.EXPECT().ListUsers(gomock.Any()).Return(users, nil).Times(2)
+	m.EXPECT().Stats().Return(sql.DBStats{OpenConnections: 3, InUse: 1, Idle: 2})
+	as := NewAdminServer(m)
+
+	list, err := as.ListUsers(context.Background(), &pb.ListUsersRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []*pb.AdminUser{
+		{Login: "alice", Records: 3, Bytes: 120},
+		{Login: "bob", Disabled: true, Records: 1, Bytes: 30},
+	}, list.GetUsers())
+
+	stats, err := as.GetServerStats(context.Background(), &pb.GetServerStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.GetUsers())
+	assert.Equal(t, int64(1), stats.GetDisabledUsers())
+	assert.Equal(t, int64(4), stats.GetRecords())
+	assert.Equal(t, int64(150), stats.GetBytes())
+	assert.Equal(t, int32(3), stats.GetOpenConnections())
+	assert.Equal(t, int32(1), stats.GetInUseConnections())
+	assert.Equal(t, int32(2), stats.GetIdleConnections())
+
+	m.EXPECT().ListUsers(gomock.Any()).Return(nil, errors.New("db error"))
+	_, err = as.ListUsers(context.Background(), &pb.ListUsersRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
@@ -0,0 +1,120 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+	"github.com/Julia-ivv/info-keeper.git/pkg/logger"
+)
+
+// recordKeyOf возвращает ключевые поля записи r.
+func recordKeyOf(r *pb.Record) *pb.RecordKey {
+	key := &pb.RecordKey{Type: payloadType(r)}
+	switch v := r.GetPayload().(type) {
+	case *pb.Record_Card:
+		key.Key = v.Card.GetNumber()
+	case *pb.Record_LoginPwd:
+		key.Prompt, key.Key = v.LoginPwd.GetPrompt(), v.LoginPwd.GetLogin()
+	case *pb.Record_TextRecord:
+		key.Prompt = v.TextRecord.GetPrompt()
+	case *pb.Record_BinaryRecord:
+		key.Prompt = v.BinaryRecord.GetPrompt()
+	case *pb.Record_Otp:
+		key.Prompt, key.Key = v.Otp.GetIssuer(), v.Otp.GetAccount()
+	case *pb.Record_SshKey:
+		key.Prompt = v.SshKey.GetPrompt()
+	case *pb.Record_Template:
+		key.Prompt = v.Template.GetName()
+	case *pb.Record_CustomRecord:
+		key.Prompt = v.CustomRecord.GetPrompt()
+	}
+	return key
+}
+
+// clientInfo возвращает имя устройства клиента из метаданных запроса и его адрес.
+func clientInfo(ctx context.Context) (device string, ip string) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(authorizer.DeviceName); len(v) > 0 {
+			device = v[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	return device, ip
+}
+
+// audit записывает действие пользователя с записью key в журнал аудита.
+// Ошибка записи в журнал не прерывает обработку запроса и только логируется.
+func (ks *KeeperGRPCServer) audit(ctx context.Context, userLogin string, action storage.AuditAction, key *pb.RecordKey) {
+	device, ip := clientInfo(ctx)
+	err := ks.stor.AddAuditEvent(ctx, userLogin, storage.AuditEvent{
+		Action:    action,
+		Type:      storage.RecordType(key.GetType()),
+		Prompt:    key.GetPrompt(),
+		Key:       key.GetKey(),
+		Device:    device,
+		IP:        ip,
+		TimeStamp: time.Now(),
+	})
+	if err != nil && logger.ZapSugar != nil {
+		logger.ZapSugar.Errorln("audit event", action, "for user", userLogin, "not saved:", err)
+	}
+}
+
+// GetAuditLog реализует получение журнала аудита пользователя за интервал времени.
+// Пустое значение from означает начало журнала, пустое значение to - отсутствие ограничения сверху.
+func (ks *KeeperGRPCServer) GetAuditLog(ctx context.Context, in *pb.GetAuditLogRequest) (*pb.GetAuditLogResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to time.Time
+	if in.GetFrom() != "" {
+		from, err = time.Parse(time.RFC3339, in.GetFrom())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if in.GetTo() != "" {
+		to, err = time.Parse(time.RFC3339, in.GetTo())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	events, err := ks.stor.GetAuditLog(ctx, userLogin, from, to)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &pb.GetAuditLogResponse{Events: make([]*pb.AuditEvent, 0, len(events))}
+	for _, e := range events {
+		res.Events = append(res.Events, &pb.AuditEvent{
+			Action: string(e.Action),
+			Key: &pb.RecordKey{
+				Type:   pb.RecordType(e.Type),
+				Prompt: e.Prompt,
+				Key:    e.Key,
+			},
+			Device:    e.Device,
+			Ip:        e.IP,
+			TimeStamp: e.TimeStamp.Format(time.RFC3339),
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,170 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/mocks"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+func TestRecordKeyOf(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *pb.Record
+		want *pb.RecordKey
+	}{
+		{
+			name: "card",
+			r:    &pb.Record{Payload: &pb.Record_Card{Card: testCardPb}},
+			want: &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number},
+		},
+		{
+			name: "login",
+			r:    &pb.Record{Payload: &pb.Record_LoginPwd{LoginPwd: testLoginPwdPb}},
+			want: &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_LOGIN_PWD, Prompt: testLoginPwd.Prompt, Key: testLoginPwd.Login},
+		},
+		{
+			name: "otp",
+			r:    &pb.Record{Payload: &pb.Record_Otp{Otp: otpToPb(testOtp)}},
+			want: &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_OTP, Prompt: testOtp.Issuer, Key: testOtp.Account},
+		},
+		{
+			name: "template",
+			r:    &pb.Record{Payload: &pb.Record_Template{Template: templateToPb(testTemplate)}},
+			want: &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEMPLATE, Prompt: testTemplate.Name},
+		},
+		{
+			name: "empty",
+			r:    &pb.Record{},
+			want: &pb.RecordKey{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, recordKeyOf(tt.r))
+		})
+	}
+}
+
+func TestAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizer.DeviceName, "laptop"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+	key := &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt}
+
+	m := mocks.NewMockRepositorier(ctrl)
+	m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+	m.EXPECT().AddAuditEvent(ctx, testUserLogin, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userLogin string, e storage.AuditEvent) error {
+			assert.Equal(t, storage.AuditRead, e.Action)
+			assert.Equal(t, storage.TextDataRecord, e.Type)
+			assert.Equal(t, testTextRecord.Prompt, e.Prompt)
+			assert.Equal(t, "laptop", e.Device)
+			assert.Equal(t, "10.0.0.1", e.IP)
+			assert.False(t, e.TimeStamp.IsZero())
+			return errors.New("error")
+		})
+	testGRPC := NewKeeperServer(m, testCfg)
+	testGRPC.audit(ctx, testUserLogin, storage.AuditRead, key)
+}
+
+func TestGetAuditLog(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+
+	testTimePrs, err := time.Parse(time.RFC3339, testTime)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		ctx      context.Context
+		req      *pb.GetAuditLogRequest
+		wantRes  *pb.GetAuditLogResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "ok test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetAuditLog(ctxWithValue, testUserLogin, testTimePrs, time.Time{}).
+					Return([]storage.AuditEvent{{
+						Action:    storage.AuditForceUpdate,
+						Type:      storage.CardRecord,
+						Key:       testCard.Number,
+						Device:    "laptop",
+						IP:        "10.0.0.1",
+						TimeStamp: testTimePrs,
+					}}, nil)
+			},
+			ctx: ctxWithValue,
+			req: &pb.GetAuditLogRequest{From: testTime},
+			wantRes: &pb.GetAuditLogResponse{Events: []*pb.AuditEvent{{
+				Action:    string(storage.AuditForceUpdate),
+				Key:       &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number},
+				Device:    "laptop",
+				Ip:        "10.0.0.1",
+				TimeStamp: testTime,
+			}}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "storage error test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetAuditLog(ctxWithValue, testUserLogin, time.Time{}, testTimePrs).
+					Return(nil, errors.New("error"))
+			},
+			ctx:      ctxWithValue,
+			req:      &pb.GetAuditLogRequest{To: testTime},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "invalid time test",
+			ctx:      ctxWithValue,
+			req:      &pb.GetAuditLogRequest{From: "yesterday"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "missing login test",
+			ctx:      context.Background(),
+			req:      &pb.GetAuditLogRequest{},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			testGRPC := NewKeeperServer(m, testCfg)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			res, err := testGRPC.GetAuditLog(tt.ctx, tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package grpcserver
+
+import (
+	"time"
+
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// WatchChanges передает клиенту события об изменении записей пользователя,
+// пока клиент не закроет поток.
+// Событие содержит тип записи, зашифрованные ключевые поля и время изменения,
+// сами данные клиент получает методом GetRecord.
+func (ks *KeeperGRPCServer) WatchChanges(in *pb.WatchChangesRequest, stream pb.InfoKeeper_WatchChangesServer) error {
+	ctx := stream.Context()
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return err
+	}
+
+	for c := range ks.stor.WatchChanges(ctx, userLogin) {
+		err = stream.Send(&pb.ChangeEvent{
+			Key: &pb.RecordKey{
+				Type:   pb.RecordType(c.Type),
+				Prompt: c.Prompt,
+				Key:    c.Key,
+			},
+			TimeStamp: c.TimeStamp.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
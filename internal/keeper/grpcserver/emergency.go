@@ -0,0 +1,193 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// emergencyToPb преобразует доверенное лицо из репозитория в протокол.
+func emergencyToPb(c storage.EmergencyContact, now time.Time) *pb.EmergencyContact {
+	res := &pb.EmergencyContact{
+		Grantor:     c.Grantor,
+		Grantee:     c.Grantee,
+		WaitSeconds: int64(c.WaitPeriod / time.Second),
+		Granted:     c.Granted(now),
+	}
+	if !c.RequestedAt.IsZero() {
+		res.RequestedAt = c.RequestedAt.Format(time.RFC3339)
+		res.AvailableAt = c.AvailableAt().Format(time.RFC3339)
+	}
+	return res
+}
+
+// emergencyRecords проверяет и преобразует копию записей владельца для доверенного лица.
+func emergencyRecords(in []*pb.SharedRecord) ([]storage.SharedRecord, error) {
+	records := make([]storage.SharedRecord, 0, len(in))
+	for _, r := range in {
+		if _, ok := recordCodecs[r.GetType()]; !ok || len(r.GetData()) == 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid emergency record")
+		}
+		timeStamp, err := time.Parse(time.RFC3339, r.GetTimeStamp())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		records = append(records, storage.SharedRecord{
+			Type:      storage.RecordType(r.GetType()),
+			Data:      r.GetData(),
+			TimeStamp: timeStamp,
+		})
+	}
+	return records, nil
+}
+
+// AddEmergencyContact реализует назначение доверенного лица с периодом ожидания.
+// Ключ экстренного доступа передается зашифрованным открытым ключом доверенного лица,
+// копия записей владельца - зашифрованной этим ключом.
+func (ks *KeeperGRPCServer) AddEmergencyContact(ctx context.Context, in *pb.AddEmergencyContactRequest) (*pb.AddEmergencyContactResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.GetLogin() == "" || len(in.GetWrappedKey()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty login or key")
+	}
+	if in.GetWaitSeconds() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "negative wait period")
+	}
+
+	records, err := emergencyRecords(in.GetRecords())
+	if err != nil {
+		return nil, err
+	}
+
+	err = ks.stor.AddEmergencyContact(ctx, userLogin, in.GetLogin(),
+		time.Duration(in.GetWaitSeconds())*time.Second, in.GetWrappedKey(), records)
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.AddEmergencyContactResponse{}, nil
+}
+
+// UpdateEmergencyRecords реализует замену ключа экстренного доступа и копии записей владельца
+// для назначенного доверенного лица. Период ожидания и запрос доступа не меняются.
+func (ks *KeeperGRPCServer) UpdateEmergencyRecords(ctx context.Context, in *pb.UpdateEmergencyRecordsRequest) (*pb.UpdateEmergencyRecordsResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.GetLogin() == "" || len(in.GetWrappedKey()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty login or key")
+	}
+
+	records, err := emergencyRecords(in.GetRecords())
+	if err != nil {
+		return nil, err
+	}
+
+	err = ks.stor.UpdateEmergencyRecords(ctx, userLogin, in.GetLogin(), in.GetWrappedKey(), records)
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.UpdateEmergencyRecordsResponse{}, nil
+}
+
+// RemoveEmergencyContact реализует удаление доверенного лица.
+func (ks *KeeperGRPCServer) RemoveEmergencyContact(ctx context.Context, in *pb.RemoveEmergencyContactRequest) (*pb.RemoveEmergencyContactResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ks.stor.RemoveEmergencyContact(ctx, userLogin, in.GetLogin())
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.RemoveEmergencyContactResponse{}, nil
+}
+
+// RequestEmergencyAccess реализует запрос доверенным лицом доступа к хранилищу владельца.
+// Повторный запрос не сдвигает начало периода ожидания.
+func (ks *KeeperGRPCServer) RequestEmergencyAccess(ctx context.Context, in *pb.RequestEmergencyAccessRequest) (*pb.RequestEmergencyAccessResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := ks.stor.RequestEmergencyAccess(ctx, userLogin, in.GetGrantor(), time.Now())
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.RequestEmergencyAccessResponse{AvailableAt: c.AvailableAt().Format(time.RFC3339)}, nil
+}
+
+// DeclineEmergencyAccess реализует отклонение владельцем запроса доверенного лица.
+func (ks *KeeperGRPCServer) DeclineEmergencyAccess(ctx context.Context, in *pb.DeclineEmergencyAccessRequest) (*pb.DeclineEmergencyAccessResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ks.stor.DeclineEmergencyAccess(ctx, userLogin, in.GetLogin())
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.DeclineEmergencyAccessResponse{}, nil
+}
+
+// ListEmergencyContacts реализует получение доверенных лиц пользователя
+// и владельцев, назначивших пользователя доверенным лицом.
+func (ks *KeeperGRPCServer) ListEmergencyContacts(ctx context.Context, in *pb.ListEmergencyContactsRequest) (*pb.ListEmergencyContactsResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, err := ks.stor.ListEmergencyContacts(ctx, userLogin)
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	now := time.Now()
+	res := &pb.ListEmergencyContactsResponse{Contacts: make([]*pb.EmergencyContact, 0, len(contacts))}
+	for _, c := range contacts {
+		res.Contacts = append(res.Contacts, emergencyToPb(c, now))
+	}
+
+	return res, nil
+}
+
+// GetEmergencyKey реализует получение доверенным лицом ключа экстренного доступа
+// и копии записей владельца после окончания периода ожидания.
+func (ks *KeeperGRPCServer) GetEmergencyKey(ctx context.Context, in *pb.GetEmergencyKeyRequest) (*pb.GetEmergencyKeyResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, records, err := ks.stor.GetEmergencyKey(ctx, userLogin, in.GetGrantor(), time.Now())
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	res := &pb.GetEmergencyKeyResponse{WrappedKey: key, Records: make([]*pb.SharedRecord, 0, len(records))}
+	for _, r := range records {
+		res.Records = append(res.Records, &pb.SharedRecord{
+			Type:      pb.RecordType(r.Type),
+			Data:      r.Data,
+			TimeStamp: r.TimeStamp.Format(time.RFC3339),
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,183 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/mocks"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+func TestAddEmergencyContact(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	at, err := time.Parse(time.RFC3339, testTime)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		req      *pb.AddEmergencyContactRequest
+		wantCode codes.Code
+	}{
+		{
+			name: "ok test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().AddEmergencyContact(ctx, testUserLogin, "contact", 48*time.Hour, []byte("wrapped"),
+					[]storage.SharedRecord{{Type: storage.TextDataRecord, Data: []byte("sealed"), TimeStamp: at}}).
+					Return(nil)
+			},
+			req: &pb.AddEmergencyContactRequest{Login: "contact", WaitSeconds: 172800, WrappedKey: []byte("wrapped"),
+				Records: []*pb.SharedRecord{{Type: pb.RecordType_RECORD_TYPE_TEXT, Data: []byte("sealed"), TimeStamp: testTime}}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "invalid record test",
+			req: &pb.AddEmergencyContactRequest{Login: "contact", WaitSeconds: 60, WrappedKey: []byte("wrapped"),
+				Records: []*pb.SharedRecord{{Type: pb.RecordType_RECORD_TYPE_TEXT, TimeStamp: testTime}}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "empty key test",
+			req:      &pb.AddEmergencyContactRequest{Login: "contact", WaitSeconds: 60},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "negative wait test",
+			req:      &pb.AddEmergencyContactRequest{Login: "contact", WaitSeconds: -1, WrappedKey: []byte("wrapped")},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "contact not found test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().AddEmergencyContact(ctx, testUserLogin, "contact", time.Minute, []byte("wrapped"), []storage.SharedRecord{}).
+					Return(storage.NewStorError(storage.EmptyResult, errors.New("error")))
+			},
+			req:      &pb.AddEmergencyContactRequest{Login: "contact", WaitSeconds: 60, WrappedKey: []byte("wrapped")},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := newOrgTestServer(t, test.prepare)
+			_, err := srv.AddEmergencyContact(ctx, test.req)
+			assert.Equal(t, test.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestUpdateEmergencyRecords(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	at, err := time.Parse(time.RFC3339, testTime)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		req      *pb.UpdateEmergencyRecordsRequest
+		wantCode codes.Code
+	}{
+		{
+			name: "ok test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().UpdateEmergencyRecords(ctx, testUserLogin, "contact", []byte("wrapped"),
+					[]storage.SharedRecord{{Type: storage.BinaryDataRecord, Data: []byte("sealed"), TimeStamp: at}}).
+					Return(nil)
+			},
+			req: &pb.UpdateEmergencyRecordsRequest{Login: "contact", WrappedKey: []byte("wrapped"),
+				Records: []*pb.SharedRecord{{Type: pb.RecordType_RECORD_TYPE_BINARY, Data: []byte("sealed"), TimeStamp: testTime}}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "invalid time stamp test",
+			req: &pb.UpdateEmergencyRecordsRequest{Login: "contact", WrappedKey: []byte("wrapped"),
+				Records: []*pb.SharedRecord{{Type: pb.RecordType_RECORD_TYPE_TEXT, Data: []byte("sealed"), TimeStamp: "time"}}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "empty key test",
+			req:      &pb.UpdateEmergencyRecordsRequest{Login: "contact"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "contact not found test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().UpdateEmergencyRecords(ctx, testUserLogin, "contact", []byte("wrapped"), []storage.SharedRecord{}).
+					Return(storage.NewStorError(storage.EmptyResult, errors.New("error")))
+			},
+			req:      &pb.UpdateEmergencyRecordsRequest{Login: "contact", WrappedKey: []byte("wrapped")},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := newOrgTestServer(t, test.prepare)
+			_, err := srv.UpdateEmergencyRecords(ctx, test.req)
+			assert.Equal(t, test.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestEmergencyAccess(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	requestedAt := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+
+	srv := newOrgTestServer(t, func(m *mocks.MockRepositorier) {
+		m.EXPECT().RequestEmergencyAccess(ctx, testUserLogin, "owner", gomock.Any()).
+			Return(storage.EmergencyContact{Grantor: "owner", Grantee: testUserLogin, WaitPeriod: time.Hour, RequestedAt: requestedAt}, nil)
+		m.EXPECT().ListEmergencyContacts(ctx, testUserLogin).Return([]storage.EmergencyContact{
+			{Grantor: testUserLogin, Grantee: "contact", WaitPeriod: time.Hour},
+			{Grantor: "owner", Grantee: testUserLogin, WaitPeriod: time.Hour, RequestedAt: requestedAt},
+		}, nil)
+		m.EXPECT().GetEmergencyKey(ctx, testUserLogin, "owner", gomock.Any()).Return([]byte("wrapped"),
+			[]storage.SharedRecord{{Type: storage.TextDataRecord, Data: []byte("sealed"), TimeStamp: requestedAt}}, nil)
+		m.EXPECT().GetEmergencyKey(ctx, testUserLogin, "other", gomock.Any()).
+			Return(nil, nil, storage.NewStorError(storage.Forbidden, errors.New("error")))
+		m.EXPECT().DeclineEmergencyAccess(ctx, testUserLogin, "contact").Return(nil)
+		m.EXPECT().RemoveEmergencyContact(ctx, testUserLogin, "contact").Return(nil)
+	})
+
+	reqRes, err := srv.RequestEmergencyAccess(ctx, &pb.RequestEmergencyAccessRequest{Grantor: "owner"})
+	require.NoError(t, err)
+	assert.Equal(t, requestedAt.Add(time.Hour).Format(time.RFC3339), reqRes.GetAvailableAt())
+
+	listRes, err := srv.ListEmergencyContacts(ctx, &pb.ListEmergencyContactsRequest{})
+	require.NoError(t, err)
+	require.Len(t, listRes.GetContacts(), 2)
+	assert.Equal(t, &pb.EmergencyContact{Grantor: testUserLogin, Grantee: "contact", WaitSeconds: 3600}, listRes.GetContacts()[0])
+	assert.True(t, listRes.GetContacts()[1].GetGranted())
+	assert.Equal(t, requestedAt.Format(time.RFC3339), listRes.GetContacts()[1].GetRequestedAt())
+
+	keyRes, err := srv.GetEmergencyKey(ctx, &pb.GetEmergencyKeyRequest{Grantor: "owner"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("wrapped"), keyRes.GetWrappedKey())
+	assert.Equal(t, []*pb.SharedRecord{{
+		Type: pb.RecordType_RECORD_TYPE_TEXT, Data: []byte("sealed"), TimeStamp: requestedAt.Format(time.RFC3339),
+	}}, keyRes.GetRecords())
+
+	_, err = srv.GetEmergencyKey(ctx, &pb.GetEmergencyKeyRequest{Grantor: "other"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = srv.DeclineEmergencyAccess(ctx, &pb.DeclineEmergencyAccessRequest{Login: "contact"})
+	assert.NoError(t, err)
+	_, err = srv.RemoveEmergencyContact(ctx, &pb.RemoveEmergencyContactRequest{Login: "contact"})
+	assert.NoError(t, err)
+
+	_, err = srv.ListEmergencyContacts(context.Background(), &pb.ListEmergencyContactsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -2,13 +2,16 @@
 package grpcserver
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"io"
 	"slices"
+	"strconv"
 	"time"
 
-	"github.com/jackc/pgerrcode"
-	"github.com/jackc/pgx/v5/pgconn"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -18,6 +21,15 @@ import (
 	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
 )
 
+// BinaryChunkSize - размер части бинарных данных при потоковой передаче.
+const BinaryChunkSize = 1 << 20
+
+// DefaultPageSize - количество записей на странице ListRecords по умолчанию.
+const DefaultPageSize = 100
+
+// MaxPageSize - максимальное количество записей на странице ListRecords.
+const MaxPageSize = 1000
+
 // ShortenerServer хранит репозиторий и настройки приложения.
 type KeeperGRPCServer struct {
 	pb.UnimplementedInfoKeeperServer
@@ -41,8 +53,8 @@ func (ks *KeeperGRPCServer) AddUser(ctx context.Context, in *pb.AddUserRequest)
 
 	err := ks.stor.RegUser(ctx, in.GetLogin(), in.GetPwd())
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+		var storErr *storage.StorErr
+		if errors.As(err, &storErr) && storErr.ErrType == storage.AlreadyExists {
 			return nil, status.Error(codes.AlreadyExists, "user with login "+in.Login+" already exists")
 		}
 		return nil, status.Error(codes.Internal, err.Error())
@@ -57,14 +69,32 @@ func (ks *KeeperGRPCServer) AddUser(ctx context.Context, in *pb.AddUserRequest)
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	tokenString, err := authorizer.BuildToken(in.Login, in.Pwd, ks.cfg.SecretKey)
+	tokenString, err := ks.sessionToken(ctx, in.GetLogin(), in.GetPwd())
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	return &pb.AddUserResponse{Token: tokenString}, nil
 }
 
+// sessionToken создает токен для текущей сессии пользователя.
+// Заблокированному пользователю токен не выдается.
+func (ks *KeeperGRPCServer) sessionToken(ctx context.Context, login, pwd string) (string, error) {
+	session, err := ks.stor.GetSession(ctx, login)
+	if err != nil {
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	if session.Disabled {
+		return "", status.Error(codes.PermissionDenied, "user is disabled")
+	}
+
+	tokenString, err := authorizer.BuildSessionToken(login, pwd, ks.cfg.SecretKey, session.UserID, session.Version)
+	if err != nil {
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	return tokenString, nil
+}
+
 // AuthUser - аутентификация существующего пользователя.
 func (ks *KeeperGRPCServer) AuthUser(ctx context.Context, in *pb.AuthUserRequest) (*pb.AuthUserResponse, error) {
 	if in.GetLogin() == "" || in.GetPwd() == "" {
@@ -80,9 +110,9 @@ func (ks *KeeperGRPCServer) AuthUser(ctx context.Context, in *pb.AuthUserRequest
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	tokenString, err := authorizer.BuildToken(in.GetLogin(), in.GetPwd(), ks.cfg.SecretKey)
+	tokenString, err := ks.sessionToken(ctx, in.GetLogin(), in.GetPwd())
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	return &pb.AuthUserResponse{Token: tokenString}, nil
@@ -90,604 +120,526 @@ func (ks *KeeperGRPCServer) AuthUser(ctx context.Context, in *pb.AuthUserRequest
 
 // AddCard реализует добавление информации о банковской карте.
 func (ks *KeeperGRPCServer) AddCard(ctx context.Context, in *pb.AddCardRequest) (*pb.AddCardResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
-	}
-	userToken := v.(string)
-
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+	rev, err := ks.addRecord(ctx, &pb.Record{Payload: &pb.Record_Card{Card: in.GetCard()}})
 	if err != nil {
 		return nil, err
 	}
-
-	if in.GetCard() == nil {
-		return nil, status.Error(codes.DataLoss, "empty request")
-	}
-
-	timeStamp, err := time.Parse(time.RFC3339, in.Card.GetTimeStamp())
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-	err = ks.stor.AddCard(ctx, userLogin, in.Card.GetPrompt(), in.Card.GetNumber(), in.Card.GetDate(),
-		in.Card.GetCode(), in.Card.GetNote(), timeStamp)
-	if err != nil {
-		var addErr *storage.StorErr
-		if errors.As(err, &addErr) && addErr.ErrType == storage.EmptyValues {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
-		if errors.As(err, &addErr) && addErr.ErrType == storage.ExistsDataNewerVersion {
-			return nil, status.Error(codes.AlreadyExists, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-
-	return nil, nil
+	return &pb.AddCardResponse{Revision: rev}, nil
 }
 
 // AddLogin реализует добавление пары логин - пароль в БД.
 func (ks *KeeperGRPCServer) AddLogin(ctx context.Context, in *pb.AddLoginRequest) (*pb.AddLoginResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
-	}
-	userToken := v.(string)
-
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+	rev, err := ks.addRecord(ctx, &pb.Record{Payload: &pb.Record_LoginPwd{LoginPwd: in.GetLoginPwd()}})
 	if err != nil {
 		return nil, err
 	}
-
-	if in.GetLoginPwd() == nil {
-		return nil, status.Error(codes.DataLoss, "empty request")
-	}
-
-	timeStamp, err := time.Parse(time.RFC3339, in.LoginPwd.GetTimeStamp())
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-	err = ks.stor.AddLoginPwd(ctx, userLogin, in.LoginPwd.GetPrompt(), in.LoginPwd.GetLogin(), in.LoginPwd.GetPwd(), in.LoginPwd.GetNote(), timeStamp)
-	if err != nil {
-		var addErr *storage.StorErr
-		if errors.As(err, &addErr) && addErr.ErrType == storage.EmptyValues {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
-		if errors.As(err, &addErr) && addErr.ErrType == storage.ExistsDataNewerVersion {
-			return nil, status.Error(codes.AlreadyExists, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-
-	return nil, nil
+	return &pb.AddLoginResponse{Revision: rev}, nil
 }
 
 // AddTextData - раелизует добавление текстовой информации.
 func (ks *KeeperGRPCServer) AddTextData(ctx context.Context, in *pb.AddTextDataRequest) (*pb.AddTextDataResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
-	}
-	userToken := v.(string)
-
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+	rev, err := ks.addRecord(ctx, &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: in.GetTextRecord()}})
 	if err != nil {
 		return nil, err
 	}
-
-	if in.GetTextRecord() == nil {
-		return nil, status.Error(codes.DataLoss, "empty request")
-	}
-
-	timeStamp, err := time.Parse(time.RFC3339, in.TextRecord.GetTimeStamp())
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-	err = ks.stor.AddTextRecord(ctx, userLogin, in.TextRecord.GetPrompt(), in.TextRecord.GetData(), in.TextRecord.GetNote(), timeStamp)
-	if err != nil {
-		var addErr *storage.StorErr
-		if errors.As(err, &addErr) && addErr.ErrType == storage.EmptyValues {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
-		if errors.As(err, &addErr) && addErr.ErrType == storage.ExistsDataNewerVersion {
-			return nil, status.Error(codes.AlreadyExists, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-
-	return nil, nil
+	return &pb.AddTextDataResponse{Revision: rev}, nil
 }
 
 // AddBinaryData реализует добавление бинарной информации в БД.
 func (ks *KeeperGRPCServer) AddBinaryData(ctx context.Context, in *pb.AddBinaryDataRequest) (*pb.AddBinaryDataResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
-	}
-	userToken := v.(string)
-
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+	rev, err := ks.addRecord(ctx, &pb.Record{Payload: &pb.Record_BinaryRecord{BinaryRecord: in.GetBinaryRecord()}})
 	if err != nil {
 		return nil, err
 	}
+	return &pb.AddBinaryDataResponse{Revision: rev}, nil
+}
 
-	if in.GetBinaryRecord() == nil {
-		return nil, status.Error(codes.DataLoss, "empty request")
-	}
-
-	timeStamp, err := time.Parse(time.RFC3339, in.BinaryRecord.GetTimeStamp())
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
+// SyncErrInfo - содержит информацию об ошибках при синхронизации.
+type SyncErrInfo struct {
+	Text            string
+	Value           []byte
+	Err             string
+	Key             *pb.RecordKey
+	Code            pb.SyncErrorCode
+	ServerTimeStamp string
+}
 
-	err = ks.stor.AddBinaryRecord(ctx, userLogin, in.BinaryRecord.GetPrompt(), in.BinaryRecord.GetData(), in.BinaryRecord.GetNote(), timeStamp)
-	if err != nil {
-		var addErr *storage.StorErr
-		if errors.As(err, &addErr) && addErr.ErrType == storage.EmptyValues {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
-		if errors.As(err, &addErr) && addErr.ErrType == storage.ExistsDataNewerVersion {
-			return nil, status.Error(codes.AlreadyExists, err.Error())
+// newSyncErrInfo создает описание ошибки синхронизации записи с ключом key.
+// Код ошибки определяется по ее типу.
+func newSyncErrInfo(text string, value []byte, key *pb.RecordKey, err error) SyncErrInfo {
+	info := SyncErrInfo{
+		Text:  text,
+		Value: value,
+		Err:   err.Error(),
+		Key:   key,
+		Code:  pb.SyncErrorCode_SYNC_ERROR_CODE_INTERNAL,
+	}
+
+	var parseErr *time.ParseError
+	var storErr *storage.StorErr
+	switch {
+	case errors.As(err, &parseErr):
+		info.Code = pb.SyncErrorCode_SYNC_ERROR_CODE_INVALID_TIMESTAMP
+	case errors.As(err, &storErr) && storErr.ErrType == storage.ExistsDataNewerVersion:
+		info.Code = pb.SyncErrorCode_SYNC_ERROR_CODE_CONFLICT_NEWER_ON_SERVER
+		if !storErr.TimeStamp.IsZero() {
+			info.ServerTimeStamp = storErr.TimeStamp.Format(time.RFC3339)
 		}
-		return nil, status.Error(codes.Internal, err.Error())
+	case errors.As(err, &storErr) && storErr.ErrType == storage.NullValues:
+		info.Code = pb.SyncErrorCode_SYNC_ERROR_CODE_NULL_VALUES
+	case errors.As(err, &storErr) && storErr.ErrType == storage.EmptyValues:
+		info.Code = pb.SyncErrorCode_SYNC_ERROR_CODE_EMPTY_VALUES
+	case errors.As(err, &storErr) && storErr.ErrType == storage.QuotaExceeded,
+		status.Code(err) == codes.ResourceExhausted:
+		info.Code = pb.SyncErrorCode_SYNC_ERROR_CODE_QUOTA_EXCEEDED
 	}
 
-	return nil, nil
-}
-
-// SyncErrInfo - содержит информацию об ошибках при синхронизации.
-type SyncErrInfo struct {
-	Text  string
-	Value []byte
-	Err   string
+	return info
 }
 
 // SyncUserData выполняет синхронизацию данных между сервером и клиентом.
+// Клиент получает записи, измененные после ревизии since_revision, и номер текущей ревизии,
+// который нужно передать при следующей синхронизации.
+// Если переданная ревизия больше текущей (например, данные сервера восстановлены из копии),
+// клиент получает все записи.
+// Кроме того, клиент получает ключи записей, удаленных после since_revision.
 func (ks *KeeperGRPCServer) SyncUserData(ctx context.Context, in *pb.SyncUserDataRequest) (*pb.SyncUserDataResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
-	}
-	userToken := v.(string)
-
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+	userLogin, err := ks.userLogin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	lastSync, err := time.Parse(time.RFC3339, string(in.GetLastSync()))
+	current, err := ks.stor.GetSyncRevision(ctx, userLogin)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	since := in.GetSinceRevision()
+	if since > current {
+		since = 0
+	}
 
-	newCards, err := ks.stor.GetUserCardsAfterTime(ctx, userLogin, lastSync)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	changed := make(map[pb.RecordType][]any, len(syncRecordTypes))
+	for _, t := range syncRecordTypes {
+		changed[t], err = ks.stor.GetRecordsAfterRevision(ctx, userLogin, recordCodecs[t].storType, since, current)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
 	}
-	newLogins, err := ks.stor.GetUserLoginsPwdsAfterTime(ctx, userLogin, lastSync)
+
+	deletedRecords, err := ks.stor.GetDeletedRecordsAfterRevision(ctx, userLogin, since, current)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	newTextRecords, err := ks.stor.GetUserTextRecordsAfterTime(ctx, userLogin, lastSync)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	deleted := make([]*pb.RecordKey, 0, len(deletedRecords))
+	for _, v := range deletedRecords {
+		deleted = append(deleted, &pb.RecordKey{Type: pb.RecordType(v.Type), Prompt: v.Prompt, Key: v.Key})
 	}
-	newBinaryRecords, err := ks.stor.GetUserBinaryRecordsAfterTime(ctx, userLogin, lastSync)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+
+	// unchanged удаляет из ответа запись с ключом key, которая уже есть у клиента.
+	unchanged := func(key *pb.RecordKey) {
+		changed[key.GetType()] = slices.DeleteFunc(changed[key.GetType()], func(v any) bool {
+			return sameRecordKey(recordKeyOf(recordToPb(v)), key)
+		})
 	}
 
+	quota := ks.newQuotaChecker(userLogin)
 	var respErrors []SyncErrInfo
-
-	if in.GetCards() != nil {
-		for _, v := range in.GetCards() {
-			timeStamp, err := time.Parse(time.RFC3339, v.GetTimeStamp())
-			if err != nil {
-				respErrors = append(respErrors, SyncErrInfo{
-					Text:  "error for card number ",
-					Value: v.GetNumber(),
-					Err:   err.Error(),
-				})
-				continue
+	var written int64
+
+	for _, t := range syncRecordTypes {
+		codec := recordCodecs[t]
+		for _, r := range codec.fromSync(in) {
+			key := recordKeyOf(r)
+			timeStamp, err := time.Parse(time.RFC3339, codec.timeStamp(r))
+			if err == nil {
+				err = quota.check(ctx, r)
 			}
-			err = ks.stor.AddCard(ctx, userLogin, v.GetPrompt(), v.GetNumber(), v.GetDate(), v.GetCode(), v.GetNote(), timeStamp)
 			if err != nil {
-				respErrors = append(respErrors, SyncErrInfo{
-					Text:  "error for card number ",
-					Value: v.GetNumber(),
-					Err:   err.Error(),
-				})
-			}
-			newCards = slices.DeleteFunc(newCards, func(c storage.Card) bool {
-				return slices.Compare(c.Number, v.GetNumber()) == 0
-			})
-		}
-	}
-
-	if in.GetLogins() != nil {
-		for _, v := range in.GetLogins() {
-			timeStamp, err := time.Parse(time.RFC3339, v.GetTimeStamp())
-			if err != nil {
-				respErrors = append(respErrors, SyncErrInfo{
-					Text:  "error for pair login/password with prompt ",
-					Value: v.GetPrompt(),
-					Err:   err.Error(),
-				})
+				respErrors = append(respErrors, newSyncErrInfo(codec.syncErrText, codec.syncErrValue(key), key, err))
 				continue
 			}
-			err = ks.stor.AddLoginPwd(ctx, userLogin, v.GetPrompt(), v.GetLogin(), v.GetPwd(), v.GetNote(), timeStamp)
+			_, err = ks.stor.AddRecord(ctx, userLogin, codec.toStorage(r, timeStamp))
 			if err != nil {
-				respErrors = append(respErrors, SyncErrInfo{
-					Text:  "error for pair login/password with prompt ",
-					Value: v.GetPrompt(),
-					Err:   err.Error(),
+				respErrors = append(respErrors, newSyncErrInfo(codec.syncErrText, codec.syncErrValue(key), key, err))
+			} else {
+				ks.audit(ctx, userLogin, storage.AuditUpdate, key)
+				written++
+				// Запись, снова сохраненная клиентом, не нужно удалять у него.
+				deleted = slices.DeleteFunc(deleted, func(d *pb.RecordKey) bool {
+					return sameRecordKey(d, key)
 				})
 			}
-			newLogins = slices.DeleteFunc(newLogins, func(l storage.LoginPwd) bool {
-				return slices.Compare(l.Prompt, v.GetPrompt()) == 0 && slices.Compare(l.Login, v.GetLogin()) == 0
-			})
+			unchanged(key)
 		}
-	}
-
-	if in.GetTextRecords() != nil {
-		for _, v := range in.GetTextRecords() {
-			timeStamp, err := time.Parse(time.RFC3339, v.GetTimeStamp())
-			if err != nil {
-				respErrors = append(respErrors, SyncErrInfo{
-					Text:  "error for text data with prompt ",
-					Value: v.GetPrompt(),
-					Err:   err.Error(),
-				})
-				continue
+		if codec.refs != nil {
+			for _, key := range codec.refs(in) {
+				unchanged(key)
 			}
-			err = ks.stor.AddTextRecord(ctx, userLogin, v.GetPrompt(), v.GetData(), v.GetNote(), timeStamp)
-			if err != nil {
-				respErrors = append(respErrors, SyncErrInfo{
-					Text:  "error for text data with prompt ",
-					Value: v.GetPrompt(),
-					Err:   err.Error(),
-				})
-			}
-			newTextRecords = slices.DeleteFunc(newTextRecords, func(t storage.TextRecord) bool {
-				return slices.Compare(t.Prompt, v.GetPrompt()) == 0
-			})
 		}
 	}
 
-	if in.GetBinaryRecords() != nil {
-		for _, v := range in.GetBinaryRecords() {
-			timeStamp, err := time.Parse(time.RFC3339, v.GetTimeStamp())
-			if err != nil {
-				respErrors = append(respErrors, SyncErrInfo{
-					Text:  "error for binary data with prompt ",
-					Value: v.GetPrompt(),
-					Err:   err.Error(),
-				})
-				continue
-			}
-			err = ks.stor.AddBinaryRecord(ctx, userLogin, v.GetPrompt(), v.GetData(), v.GetNote(), timeStamp)
-			if err != nil {
-				respErrors = append(respErrors, SyncErrInfo{
-					Text:  "error for binary data with prompt ",
-					Value: v.GetPrompt(),
-					Err:   err.Error(),
-				})
+	res := &pb.SyncUserDataResponse{
+		NewLogins:        []*pb.UserLoginPwd{},
+		NewCards:         []*pb.UserCard{},
+		NewTextRecords:   []*pb.UserTextRecord{},
+		NewBinaryRecords: []*pb.UserBinaryRecord{},
+		NewBinaryRefs:    []*pb.BinaryRecordRef{},
+		NewOtps:          []*pb.UserOtp{},
+		NewSshKeys:       []*pb.UserSshKey{},
+		NewTemplates:     []*pb.UserTemplate{},
+		NewCustomRecords: []*pb.UserCustomRecord{},
+	}
+	for _, t := range syncRecordTypes {
+		codec := recordCodecs[t]
+		for _, v := range changed[t] {
+			// Содержимое записей, переданных ссылкой, клиент получает отдельным запросом.
+			if codec.refs == nil {
+				ks.audit(ctx, userLogin, storage.AuditRead, recordKeyOf(recordToPb(v)))
 			}
-			newBinaryRecords = slices.DeleteFunc(newBinaryRecords, func(b storage.BinaryRecord) bool {
-				return slices.Compare(b.Prompt, v.GetPrompt()) == 0
-			})
+			codec.toSync(res, v)
 		}
 	}
 
-	respCards := make([]*pb.UserCard, 0, len(newCards))
-	for _, v := range newCards {
-		respCards = append(respCards, &pb.UserCard{
-			Prompt:    v.Prompt,
-			Number:    v.Number,
-			Date:      v.Date,
-			Code:      v.Code,
-			Note:      v.Note,
-			TimeStamp: v.TimeStamp.Format(time.RFC3339),
-		})
-	}
-
-	respLogins := make([]*pb.UserLoginPwd, 0, len(newLogins))
-	for _, v := range newLogins {
-		respLogins = append(respLogins, &pb.UserLoginPwd{
-			Prompt:    v.Prompt,
-			Login:     v.Login,
-			Pwd:       v.Pwd,
-			Note:      v.Note,
-			TimeStamp: v.TimeStamp.Format(time.RFC3339),
-		})
-	}
-
-	respText := make([]*pb.UserTextRecord, 0, len(newTextRecords))
-	for _, v := range newTextRecords {
-		respText = append(respText, &pb.UserTextRecord{
-			Prompt:    v.Prompt,
-			Data:      v.Data,
-			Note:      v.Note,
-			TimeStamp: v.TimeStamp.Format(time.RFC3339),
-		})
-	}
-
-	respBinary := make([]*pb.UserBinaryRecord, 0, len(newBinaryRecords))
-	for _, v := range newBinaryRecords {
-		respBinary = append(respBinary, &pb.UserBinaryRecord{
-			Prompt:    v.Prompt,
-			Data:      v.Data,
-			Note:      v.Note,
-			TimeStamp: v.TimeStamp.Format(time.RFC3339),
-		})
-	}
-
 	errInfo := make([]*pb.SyncUserDataResponse_SyncErrorInfo, 0, len(respErrors))
 	for _, v := range respErrors {
 		errInfo = append(errInfo, &pb.SyncUserDataResponse_SyncErrorInfo{
-			Text:  v.Text,
-			Value: v.Value,
-			Err:   v.Err,
+			Text:            v.Text,
+			Value:           v.Value,
+			Err:             v.Err,
+			Key:             v.Key,
+			Code:            v.Code,
+			ServerTimeStamp: v.ServerTimeStamp,
 		})
 	}
 
-	return &pb.SyncUserDataResponse{
-		SyncErrors:       errInfo,
-		NewLogins:        respLogins,
-		NewCards:         respCards,
-		NewTextRecords:   respText,
-		NewBinaryRecords: respBinary,
-	}, nil
+	// Если во время синхронизации данные пользователя изменял только этот запрос,
+	// клиенту не нужно получать переданные им записи при следующей синхронизации.
+	after, err := ks.stor.GetSyncRevision(ctx, userLogin)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if after == current+written {
+		current = after
+	}
+
+	res.SyncErrors = errInfo
+	res.DeletedRecords = deleted
+	res.CurrentRevision = current
+	return res, nil
 }
 
 // GetUserCard реализует получение информации о банковской карте.
 func (ks *KeeperGRPCServer) GetUserCard(ctx context.Context, in *pb.GetUserCardRequest) (*pb.GetUserCardResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
-	}
-	userToken := v.(string)
-
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+	r, err := ks.getRecord(ctx, &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: in.GetNumber()})
 	if err != nil {
 		return nil, err
 	}
-
-	card, err := ks.stor.GetCard(ctx, userLogin, in.GetNumber())
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-
-	return &pb.GetUserCardResponse{
-		Card: &pb.UserCard{
-			Prompt:    card.Prompt,
-			Number:    card.Number,
-			Date:      card.Date,
-			Code:      card.Code,
-			Note:      card.Note,
-			TimeStamp: card.TimeStamp.Format(time.RFC3339),
-		},
-	}, nil
+	return &pb.GetUserCardResponse{Card: r.GetCard()}, nil
 }
 
 // GetUserLogin - получает информацию о паре логин-пароль из БД.
 func (ks *KeeperGRPCServer) GetUserLogin(ctx context.Context, in *pb.GetUserLoginRequest) (*pb.GetUserLoginResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
-	}
-	userToken := v.(string)
-
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+	r, err := ks.getRecord(ctx, &pb.RecordKey{
+		Type:   pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+		Prompt: in.GetPrompt(),
+		Key:    in.GetLogin(),
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	lg, err := ks.stor.GetLoginPwd(ctx, userLogin, in.GetPrompt(), in.GetLogin())
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-
-	return &pb.GetUserLoginResponse{
-		LoginPwd: &pb.UserLoginPwd{
-			Prompt:    lg.Prompt,
-			Login:     lg.Login,
-			Pwd:       lg.Pwd,
-			Note:      lg.Note,
-			TimeStamp: lg.TimeStamp.Format(time.RFC3339),
-		},
-	}, nil
+	return &pb.GetUserLoginResponse{LoginPwd: r.GetLoginPwd()}, nil
 }
 
 // GetUserText - получает текстовую информацию из БД.
 func (ks *KeeperGRPCServer) GetUserText(ctx context.Context, in *pb.GetUserTextRequest) (*pb.GetUserTextResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
-	}
-	userToken := v.(string)
-
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+	r, err := ks.getRecord(ctx, &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: in.GetPrompt()})
 	if err != nil {
 		return nil, err
 	}
-
-	tr, err := ks.stor.GetTextRecord(ctx, userLogin, in.GetPrompt())
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-
-	return &pb.GetUserTextResponse{
-		TextRecord: &pb.UserTextRecord{
-			Prompt:    tr.Prompt,
-			Data:      tr.Data,
-			Note:      tr.Note,
-			TimeStamp: tr.TimeStamp.Format(time.RFC3339),
-		},
-	}, nil
+	return &pb.GetUserTextResponse{TextRecord: r.GetTextRecord()}, nil
 }
 
 // GetUserBinary - получает бинарную информацию из БД.
 func (ks *KeeperGRPCServer) GetUserBinary(ctx context.Context, in *pb.GetUserBinaryRequest) (*pb.GetUserBinaryResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
+	r, err := ks.getRecord(ctx, &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: in.GetPrompt()})
+	if err != nil {
+		return nil, err
 	}
-	userToken := v.(string)
+	return &pb.GetUserBinaryResponse{BinaryRecord: r.GetBinaryRecord()}, nil
+}
 
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+// ForceUpdateCard - обновляет информацию о банковской карте.
+func (ks *KeeperGRPCServer) ForceUpdateCard(ctx context.Context, in *pb.ForceUpdateCardRequest) (*pb.ForceUpdateCardResponse, error) {
+	rev, err := ks.forceUpdateRecord(ctx, &pb.Record{Payload: &pb.Record_Card{Card: in.GetCard()}})
 	if err != nil {
 		return nil, err
 	}
+	return &pb.ForceUpdateCardResponse{Revision: rev}, nil
+}
 
-	br, err := ks.stor.GetBinaryRecord(ctx, userLogin, in.GetPrompt())
+// ForceUpdateLoginPwd - обновляет информацию о паре логин-пароль.
+func (ks *KeeperGRPCServer) ForceUpdateLoginPwd(ctx context.Context, in *pb.ForceUpdateLoginPwdRequest) (*pb.ForceUpdateLoginPwdResponse, error) {
+	rev, err := ks.forceUpdateRecord(ctx, &pb.Record{Payload: &pb.Record_LoginPwd{LoginPwd: in.GetLoginPwd()}})
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
-
-	return &pb.GetUserBinaryResponse{
-		BinaryRecord: &pb.UserBinaryRecord{
-			Prompt:    br.Prompt,
-			Data:      br.Data,
-			Note:      br.Note,
-			TimeStamp: br.TimeStamp.Format(time.RFC3339),
-		},
-	}, nil
+	return &pb.ForceUpdateLoginPwdResponse{Revision: rev}, nil
 }
 
-// ForceUpdateCard - обновляет информацию о банковской карте.
-func (ks *KeeperGRPCServer) ForceUpdateCard(ctx context.Context, in *pb.ForceUpdateCardRequest) (*pb.ForceUpdateCardResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
+// ForceUpdateTextRecord - обновляет текстовую информацию.
+func (ks *KeeperGRPCServer) ForceUpdateTextRecord(ctx context.Context, in *pb.ForceUpdateTextRecordRequest) (*pb.ForceUpdateTextRecordResponse, error) {
+	rev, err := ks.forceUpdateRecord(ctx, &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: in.GetTextRecord()}})
+	if err != nil {
+		return nil, err
 	}
-	userToken := v.(string)
+	return &pb.ForceUpdateTextRecordResponse{Revision: rev}, nil
+}
 
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+// ForceUpdateBinaryRecord - обновляет бинарную информацию в БД.
+func (ks *KeeperGRPCServer) ForceUpdateBinaryRecord(ctx context.Context, in *pb.ForceUpdateBinaryRecordRequest) (*pb.ForceUpdateBinaryRecordResponse, error) {
+	rev, err := ks.forceUpdateRecord(ctx, &pb.Record{Payload: &pb.Record_BinaryRecord{BinaryRecord: in.GetBinaryRecord()}})
 	if err != nil {
 		return nil, err
 	}
+	return &pb.ForceUpdateBinaryRecordResponse{Revision: rev}, nil
+}
 
-	if in.GetCard() == nil {
-		return nil, status.Error(codes.InvalidArgument, "empty request")
+// UploadBinary принимает бинарные данные частями.
+// Первым сообщением передается информация о записи, затем части данных
+// и последним - контрольная сумма SHA-256 всех данных.
+func (ks *KeeperGRPCServer) UploadBinary(stream pb.InfoKeeper_UploadBinaryServer) error {
+	ctx := stream.Context()
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return err
 	}
 
-	timeStamp, err := time.Parse(time.RFC3339, in.Card.GetTimeStamp())
+	upload, err := ks.stor.NewBinaryUpload(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return status.Error(codes.Internal, err.Error())
 	}
+	defer upload.Close()
 
-	err = ks.stor.ForceUpdateCard(ctx, userLogin, in.Card.GetPrompt(), in.Card.GetNumber(), in.Card.GetDate(),
-		in.Card.GetCode(), in.Card.GetNote(), timeStamp)
-	if err != nil {
-		var addErr *storage.StorErr
-		if errors.As(err, &addErr) && addErr.ErrType == storage.EmptyValues {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
+	var info *pb.UploadBinaryRequest_Info
+	var r *pb.Record
+	var timeStamp time.Time
+	var checksum []byte
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
 		}
-		return nil, status.Error(codes.Internal, err.Error())
-	}
 
-	return nil, nil
-}
+		switch part := req.GetPart().(type) {
+		case *pb.UploadBinaryRequest_Info_:
+			if info != nil {
+				return status.Error(codes.InvalidArgument, "duplicate record info")
+			}
+			info = part.Info
+			br := info.GetBinaryRecord()
+			if br == nil || info.GetSize() < 0 {
+				return status.Error(codes.InvalidArgument, "invalid record info")
+			}
+			timeStamp, err = time.Parse(time.RFC3339, br.GetTimeStamp())
+			if err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			r = &pb.Record{Payload: &pb.Record_BinaryRecord{BinaryRecord: &pb.UserBinaryRecord{
+				Prompt: br.GetPrompt(),
+				Note:   br.GetNote(),
+				Tags:   br.GetTags(),
+				Folder: br.GetFolder(),
+			}}}
+			// Квоты проверяются по объявленному размеру до получения данных,
+			// а получить больше объявленного размера сервер не дает.
+			err = ks.newQuotaChecker(userLogin).checkPending(ctx, r, info.GetSize())
+			if err != nil {
+				return err
+			}
+		case *pb.UploadBinaryRequest_Chunk:
+			if info == nil {
+				return status.Error(codes.InvalidArgument, "data chunk before record info")
+			}
+			if upload.Size()+int64(len(part.Chunk)) > info.GetSize() {
+				return status.Error(codes.InvalidArgument, "data exceeds declared size")
+			}
+			_, err = upload.Write(part.Chunk)
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+		case *pb.UploadBinaryRequest_Checksum:
+			checksum = part.Checksum
+		default:
+			return status.Error(codes.InvalidArgument, "empty request")
+		}
+	}
 
-// ForceUpdateLoginPwd - обновляет информацию о паре логин-пароль.
-func (ks *KeeperGRPCServer) ForceUpdateLoginPwd(ctx context.Context, in *pb.ForceUpdateLoginPwdRequest) (*pb.ForceUpdateLoginPwdResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
+	if info == nil {
+		return status.Error(codes.DataLoss, "empty request")
+	}
+	if upload.Size() != info.GetSize() {
+		return status.Error(codes.DataLoss, "incomplete data")
+	}
+	if !bytes.Equal(upload.Sum(), checksum) {
+		return status.Error(codes.DataLoss, "checksum mismatch")
 	}
-	userToken := v.(string)
 
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+	br := info.GetBinaryRecord()
+	action := storage.AuditCreate
+	if info.GetForce() {
+		action = storage.AuditForceUpdate
+	}
+	rev, err := ks.stor.SaveBinaryUpload(ctx, userLogin, upload, storage.BinaryRecord{
+		Prompt:    br.GetPrompt(),
+		Note:      br.GetNote(),
+		Tags:      br.GetTags(),
+		Folder:    br.GetFolder(),
+		TimeStamp: timeStamp,
+	}, info.GetForce())
 	if err != nil {
-		return nil, err
+		return storErrToStatus(err)
 	}
+	ks.audit(ctx, userLogin, action, &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: br.GetPrompt()})
+
+	return stream.SendAndClose(&pb.UploadBinaryResponse{Revision: revisionToPb(rev)})
+}
 
-	if in.GetLoginPwd() == nil {
-		return nil, status.Error(codes.InvalidArgument, "empty request")
+// DownloadBinary передает бинарные данные частями.
+// Первым сообщением передается информация о записи без данных, затем части данных
+// и последним - контрольная сумма SHA-256 всех данных.
+func (ks *KeeperGRPCServer) DownloadBinary(in *pb.DownloadBinaryRequest, stream pb.InfoKeeper_DownloadBinaryServer) error {
+	ctx := stream.Context()
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return err
 	}
 
-	timeStamp, err := time.Parse(time.RFC3339, in.LoginPwd.GetTimeStamp())
+	v, err := ks.stor.GetRecord(ctx, userLogin, storage.BinaryRecord{Prompt: in.GetPrompt()})
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return storErrToStatus(err)
 	}
+	br := v.(storage.BinaryRecord)
+	ks.audit(ctx, userLogin, storage.AuditRead, &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: br.Prompt})
 
-	err = ks.stor.ForceUpdateLoginPwd(ctx, userLogin, in.LoginPwd.GetPrompt(), in.LoginPwd.GetLogin(), in.LoginPwd.GetPwd(),
-		in.LoginPwd.GetNote(), timeStamp)
+	err = stream.Send(&pb.DownloadBinaryResponse{
+		Part: &pb.DownloadBinaryResponse_Info{
+			Info: &pb.UserBinaryRecord{
+				Prompt:    br.Prompt,
+				Note:      br.Note,
+				Tags:      br.Tags,
+				Folder:    br.Folder,
+				TimeStamp: br.TimeStamp.Format(time.RFC3339),
+			},
+		},
+	})
 	if err != nil {
-		var addErr *storage.StorErr
-		if errors.As(err, &addErr) && addErr.ErrType == storage.EmptyValues {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
+		return err
+	}
+
+	for start := 0; start < len(br.Data); start += BinaryChunkSize {
+		end := start + BinaryChunkSize
+		if end > len(br.Data) {
+			end = len(br.Data)
+		}
+		err = stream.Send(&pb.DownloadBinaryResponse{
+			Part: &pb.DownloadBinaryResponse_Chunk{Chunk: br.Data[start:end]},
+		})
+		if err != nil {
+			return err
 		}
-		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return nil, nil
+	sum := sha256.Sum256(br.Data)
+	return stream.Send(&pb.DownloadBinaryResponse{
+		Part: &pb.DownloadBinaryResponse_Checksum{Checksum: sum[:]},
+	})
 }
 
-// ForceUpdateTextRecord - обновляет текстовую информацию.
-func (ks *KeeperGRPCServer) ForceUpdateTextRecord(ctx context.Context, in *pb.ForceUpdateTextRecordRequest) (*pb.ForceUpdateTextRecordResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
-	}
-	userToken := v.(string)
-
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+// ListRecords получает постраничный список записей пользователя без их данных.
+func (ks *KeeperGRPCServer) ListRecords(ctx context.Context, in *pb.ListRecordsRequest) (*pb.ListRecordsResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if in.GetTextRecord() == nil {
-		return nil, status.Error(codes.InvalidArgument, "empty request")
-	}
-
-	timeStamp, err := time.Parse(time.RFC3339, in.TextRecord.GetTimeStamp())
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	if in.GetType() < pb.RecordType_RECORD_TYPE_UNSPECIFIED || in.GetType() > pb.RecordType_RECORD_TYPE_CUSTOM {
+		return nil, status.Error(codes.InvalidArgument, "unknown record type")
 	}
 
-	err = ks.stor.ForceUpdateTextRecord(ctx, userLogin, in.TextRecord.GetPrompt(), in.TextRecord.GetData(), in.TextRecord.GetNote(), timeStamp)
-	if err != nil {
-		var addErr *storage.StorErr
-		if errors.As(err, &addErr) && addErr.ErrType == storage.EmptyValues {
+	var since time.Time
+	if in.GetModifiedSince() != "" {
+		since, err = time.Parse(time.RFC3339, in.GetModifiedSince())
+		if err != nil {
 			return nil, status.Error(codes.InvalidArgument, err.Error())
 		}
-		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	return nil, nil
-}
+	offset, err := decodePageToken(in.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page token")
+	}
 
-// ForceUpdateBinaryRecord - обновляет бинарную информацию в БД.
-func (ks *KeeperGRPCServer) ForceUpdateBinaryRecord(ctx context.Context, in *pb.ForceUpdateBinaryRecordRequest) (*pb.ForceUpdateBinaryRecordResponse, error) {
-	v := ctx.Value(authorizer.UserContextKey)
-	if v == nil {
-		return nil, status.Error(codes.Unauthenticated, "missing token")
+	pageSize := int(in.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
 	}
-	userToken := v.(string)
 
-	userLogin, err := authorizer.GetUserDataFromToken(userToken, ks.cfg.SecretKey)
+	records, err := ks.stor.ListRecords(ctx, userLogin, storage.ListFilter{
+		Type:          storage.RecordType(in.GetType()),
+		ModifiedSince: since,
+		Offset:        offset,
+		Limit:         pageSize + 1,
+	})
 	if err != nil {
-		return nil, err
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	if in.GetBinaryRecord() == nil {
-		return nil, status.Error(codes.InvalidArgument, "empty request")
+	var nextToken string
+	if len(records) > pageSize {
+		records = records[:pageSize]
+		nextToken = encodePageToken(offset + pageSize)
 	}
 
-	timeStamp, err := time.Parse(time.RFC3339, in.BinaryRecord.GetTimeStamp())
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	resp := make([]*pb.RecordInfo, 0, len(records))
+	for _, r := range records {
+		resp = append(resp, &pb.RecordInfo{
+			Type:      pb.RecordType(r.Type),
+			Prompt:    r.Prompt,
+			TimeStamp: r.TimeStamp.Format(time.RFC3339),
+			Size:      r.Size,
+		})
 	}
 
-	err = ks.stor.ForceUpdateBinaryRecord(ctx, userLogin, in.BinaryRecord.GetPrompt(), in.BinaryRecord.GetData(), in.BinaryRecord.GetNote(), timeStamp)
+	return &pb.ListRecordsResponse{
+		Records:       resp,
+		NextPageToken: nextToken,
+	}, nil
+}
+
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
 	if err != nil {
-		var addErr *storage.StorErr
-		if errors.As(err, &addErr) && addErr.ErrType == storage.EmptyValues {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return 0, err
 	}
-
-	return nil, nil
+	offset, err := strconv.Atoi(string(b))
+	if err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		return 0, errors.New("negative offset")
+	}
+	return offset, nil
 }
@@ -1,15 +1,21 @@
 package grpcserver
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
 	"github.com/Julia-ivv/info-keeper.git/internal/keeper/config"
@@ -23,7 +29,9 @@ const (
 )
 
 var (
-	testLoginPwd = storage.LoginPwd{
+	testRevision   = storage.Revision{ID: 1, Revision: 1, TimeStamp: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)}
+	testRevisionPb = &pb.RecordRevision{RecordId: 1, Revision: 1, TimeStamp: testTime}
+	testLoginPwd   = storage.LoginPwd{
 		Prompt:    []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
 		Login:     []byte{20, 251, 144, 177, 213, 73, 247, 129, 45, 118, 66, 54, 135, 0, 6, 123, 43, 112, 216, 226, 196},
 		Pwd:       []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
@@ -77,6 +85,69 @@ var (
 		Note:      testBinaryRecord.Note,
 		TimeStamp: time.Time{}.Format(time.RFC3339),
 	}
+	testOtp = storage.Otp{
+		Issuer:    []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		Account:   []byte{20, 251, 144, 177, 213, 73, 247, 129, 45, 118, 66, 54, 135, 0, 6, 123, 43, 112, 216, 226, 196},
+		Secret:    []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		Algorithm: []byte{73, 166, 196, 108},
+		Digits:    []byte{75},
+		Period:    []byte{75, 85},
+		Note:      []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
+		TimeStamp: time.Time{},
+	}
+	testOtpPb = &pb.UserOtp{
+		Issuer:    testOtp.Issuer,
+		Account:   testOtp.Account,
+		Secret:    testOtp.Secret,
+		Algorithm: testOtp.Algorithm,
+		Digits:    testOtp.Digits,
+		Period:    testOtp.Period,
+		Note:      testOtp.Note,
+		TimeStamp: time.Time{}.Format(time.RFC3339),
+	}
+	testSshKey = storage.SshKey{
+		Prompt:     []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		PrivateKey: []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		PublicKey:  []byte{20, 251, 144, 177, 213, 73, 247, 129, 45, 118, 66, 54, 135, 0, 6, 123, 43, 112, 216, 226, 196},
+		Comment:    []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
+		TimeStamp:  time.Time{},
+	}
+	testSshKeyPb = &pb.UserSshKey{
+		Prompt:     testSshKey.Prompt,
+		PrivateKey: testSshKey.PrivateKey,
+		PublicKey:  testSshKey.PublicKey,
+		Comment:    testSshKey.Comment,
+		TimeStamp:  time.Time{}.Format(time.RFC3339),
+	}
+	testTemplate = storage.Template{
+		Name:      []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		Fields:    []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		TimeStamp: time.Time{},
+	}
+	testTemplatePb = &pb.UserTemplate{
+		Name:      testTemplate.Name,
+		Fields:    testTemplate.Fields,
+		TimeStamp: time.Time{}.Format(time.RFC3339),
+	}
+	testCustomRecord = storage.CustomRecord{
+		Prompt:    []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		Template:  []byte{20, 251, 144, 177, 213, 73, 247, 129, 45, 118, 66, 54, 135, 0, 6, 123, 43, 112, 216, 226, 196},
+		Data:      []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		Note:      []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
+		TimeStamp: time.Time{},
+	}
+	testCustomRecordPb = &pb.UserCustomRecord{
+		Prompt:    testCustomRecord.Prompt,
+		Template:  testCustomRecord.Template,
+		Data:      testCustomRecord.Data,
+		Note:      testCustomRecord.Note,
+		TimeStamp: time.Time{}.Format(time.RFC3339),
+	}
+	testBinaryRef = &pb.BinaryRecordRef{
+		Prompt:    testBinaryRecord.Prompt,
+		TimeStamp: time.Time{}.Format(time.RFC3339),
+		Size:      int64(len(testBinaryRecord.Data)),
+	}
 	testUserLogin = "ulogin"
 	testUserPwd   = "ulogin"
 	testCfg       = config.Flags{SecretKey: "rtyhg"}
@@ -146,6 +217,19 @@ func TestAddUser(t *testing.T) {
 			expectRes: nil,
 			wantErr:   true,
 		},
+		{
+			name: "login exists test",
+			prepare: func(m *mocks.MockRepositorier, a args) {
+				m.EXPECT().RegUser(a.ctx, a.login, a.pwd).Return(storage.NewStorError(storage.AlreadyExists, errors.New("")))
+			},
+			args: args{
+				ctx:   context.Background(),
+				login: "user",
+				pwd:   "pwd",
+			},
+			expectRes: nil,
+			wantErr:   true,
+		},
 		{
 			name: "error auth test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
@@ -170,6 +254,8 @@ func TestAddUser(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, config.Flags{})
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -251,6 +337,8 @@ func TestAuthUser(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, config.Flags{})
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -295,8 +383,10 @@ func TestAddCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).Return(nil)
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -311,8 +401,10 @@ func TestAddCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).Return(nil).AnyTimes()
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -327,9 +419,11 @@ func TestAddCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).
-					Return(storage.NewStorError(storage.EmptyValues, errors.New("err")))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.EmptyValues, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -351,9 +445,11 @@ func TestAddCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).
-					Return(storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -368,8 +464,10 @@ func TestAddCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).Return(errors.New("err"))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(storage.Revision{}, errors.New("err"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -384,8 +482,10 @@ func TestAddCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, testTime)
 				require.NoError(t, err)
-				m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).Return(nil).AnyTimes()
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -403,6 +503,8 @@ func TestAddCard(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -452,7 +554,10 @@ func TestAddLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).Return(nil)
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -467,7 +572,10 @@ func TestAddLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).Return(nil).AnyTimes()
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -482,8 +590,11 @@ func TestAddLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).
-					Return(storage.NewStorError(storage.EmptyValues, errors.New("err")))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.EmptyValues, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -504,8 +615,11 @@ func TestAddLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).
-					Return(storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -520,8 +634,11 @@ func TestAddLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).
-					Return(errors.New("err"))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, errors.New("err"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -536,8 +653,11 @@ func TestAddLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, testTime)
 				require.NoError(t, err)
-				m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).
-					Return(nil).AnyTimes()
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -555,6 +675,8 @@ func TestAddLogin(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -603,7 +725,10 @@ func TestAddTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).Return(nil)
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -618,7 +743,10 @@ func TestAddTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).Return(nil).AnyTimes()
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -633,8 +761,11 @@ func TestAddTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(storage.NewStorError(storage.EmptyValues, errors.New("err")))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.EmptyValues, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -654,8 +785,11 @@ func TestAddTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -670,8 +804,11 @@ func TestAddTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(errors.New("err"))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, errors.New("err"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -686,8 +823,11 @@ func TestAddTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, testTime)
 				require.NoError(t, err)
-				m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(nil).AnyTimes()
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -705,6 +845,8 @@ func TestAddTextData(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -752,7 +894,10 @@ func TestAddBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).Return(nil)
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -767,7 +912,10 @@ func TestAddBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).Return(nil).AnyTimes()
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -782,8 +930,11 @@ func TestAddBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(storage.NewStorError(storage.EmptyValues, errors.New("err")))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.EmptyValues, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -798,8 +949,11 @@ func TestAddBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -814,8 +968,11 @@ func TestAddBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(errors.New("err"))
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, errors.New("err"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -830,8 +987,11 @@ func TestAddBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, testTime)
 				require.NoError(t, err)
-				m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(nil).AnyTimes()
+				m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -849,6 +1009,8 @@ func TestAddBinaryData(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -870,6 +1032,20 @@ func TestAddBinaryData(t *testing.T) {
 	}
 }
 
+func TestSyncRecordTypes(t *testing.T) {
+	assert.Len(t, syncRecordTypes, len(recordCodecs))
+	for _, rt := range syncRecordTypes {
+		codec, ok := recordCodecs[rt]
+		require.True(t, ok, rt.String())
+		assert.NotEmpty(t, codec.syncErrText, rt.String())
+		assert.NotNil(t, codec.syncErrValue, rt.String())
+		assert.NotNil(t, codec.fromSync, rt.String())
+		assert.NotNil(t, codec.toStorage, rt.String())
+		assert.Equal(t, int32(rt), int32(codec.storType), rt.String())
+		assert.NotNil(t, codec.toSync, rt.String())
+	}
+}
+
 func TestSyncUserData(t *testing.T) {
 	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
 	if err != nil {
@@ -878,11 +1054,6 @@ func TestSyncUserData(t *testing.T) {
 	}
 	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
 
-	testTimePrs, err := time.Parse(time.RFC3339, testTime)
-	if err != nil {
-		fmt.Println("time parse error")
-	}
-
 	type args struct {
 		ctx       context.Context
 		userLogin string
@@ -890,7 +1061,13 @@ func TestSyncUserData(t *testing.T) {
 		l         storage.LoginPwd
 		t         storage.TextRecord
 		b         storage.BinaryRecord
-		lastSync  string
+		o         []any
+		k         []any
+		tm        []any
+		cr        []any
+		timeStamp string
+		since     int64
+		current   int64
 	}
 
 	tests := []struct {
@@ -901,31 +1078,59 @@ func TestSyncUserData(t *testing.T) {
 		inLogins   []*pb.UserLoginPwd
 		inTexts    []*pb.UserTextRecord
 		inBinaryes []*pb.UserBinaryRecord
+		inOtps     []*pb.UserOtp
+		inSshKeys  []*pb.UserSshKey
+		inTmpls    []*pb.UserTemplate
+		inCustoms  []*pb.UserCustomRecord
 		wantRes    *pb.SyncUserDataResponse
 		wantErr    bool
 	}{
 		{
 			name: "ok test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				tp, err := time.Parse(time.RFC3339, a.lastSync)
+				tp, err := time.Parse(time.RFC3339, a.timeStamp)
 				require.NoError(t, err)
 				gomock.InOrder(
-					m.EXPECT().GetUserCardsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.Card{a.c}, nil),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.LoginPwd{a.l}, nil),
-					m.EXPECT().GetUserTextRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.TextRecord{a.t}, nil),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.BinaryRecord{a.b}, nil),
-					m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code, a.c.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.t.Prompt, a.t.Data, a.t.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.b.Prompt, a.b.Data, a.b.Note, tp).
-						Return(nil).AnyTimes(),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, a.since, a.current).
+						Return([]any{a.c}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, a.since, a.current).
+						Return([]any{a.l}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, a.since, a.current).
+						Return([]any{a.t}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, a.since, a.current).
+						Return([]any{a.b}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.OtpRecord, a.since, a.current).
+						Return(a.o, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.SshKeyRecord, a.since, a.current).
+						Return(a.k, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TemplateRecord, a.since, a.current).
+						Return(a.tm, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CustomDataRecord, a.since, a.current).
+						Return(a.cr, nil),
+					m.EXPECT().GetDeletedRecordsAfterRevision(a.ctx, a.userLogin, a.since, a.current).
+						Return(nil, nil),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+						Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+						Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+						Prompt: a.l.Prompt, Login: a.l.Login, Pwd: a.l.Pwd, Note: a.l.Note,
+						Tags: a.l.Tags, Folder: a.l.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+						Prompt: a.t.Prompt, Data: a.t.Data, Note: a.t.Note, Tags: a.t.Tags,
+						Folder: a.t.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+						Prompt: a.b.Prompt, Data: a.b.Data, Note: a.b.Note, Tags: a.b.Tags,
+						Folder: a.b.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
 				)
 			},
 			args: args{
@@ -935,7 +1140,13 @@ func TestSyncUserData(t *testing.T) {
 				l:         testLoginPwd,
 				t:         testTextRecord,
 				b:         testBinaryRecord,
-				lastSync:  testTime,
+				o:         []any{testOtp},
+				k:         []any{testSshKey},
+				tm:        []any{testTemplate},
+				cr:        []any{testCustomRecord},
+				timeStamp: testTime,
+				since:     3,
+				current:   5,
 			},
 			inCards:    []*pb.UserCard{},
 			inLogins:   []*pb.UserLoginPwd{},
@@ -946,32 +1157,116 @@ func TestSyncUserData(t *testing.T) {
 				NewLogins:        []*pb.UserLoginPwd{testLoginPwdPb},
 				NewCards:         []*pb.UserCard{testCardPb},
 				NewTextRecords:   []*pb.UserTextRecord{testTextPb},
-				NewBinaryRecords: []*pb.UserBinaryRecord{testBinaryPb},
+				NewBinaryRecords: []*pb.UserBinaryRecord{},
+				NewBinaryRefs:    []*pb.BinaryRecordRef{testBinaryRef},
+				NewOtps:          []*pb.UserOtp{testOtpPb},
+				NewSshKeys:       []*pb.UserSshKey{testSshKeyPb},
+				NewTemplates:     []*pb.UserTemplate{testTemplatePb},
+				NewCustomRecords: []*pb.UserCustomRecord{testCustomRecordPb},
+				DeletedRecords:   []*pb.RecordKey{},
+				CurrentRevision:  5,
+			},
+			wantErr: false,
+		},
+		{
+			name: "since revision ahead of server test",
+			prepare: func(m *mocks.MockRepositorier, a args) {
+				gomock.InOrder(
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, int64(0), a.current).
+						Return([]any{a.c}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, int64(0), a.current).
+						Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, int64(0), a.current).
+						Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, int64(0), a.current).
+						Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.OtpRecord, int64(0), a.current).
+						Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.SshKeyRecord, int64(0), a.current).
+						Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TemplateRecord, int64(0), a.current).
+						Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CustomDataRecord, int64(0), a.current).
+						Return(nil, nil),
+					m.EXPECT().GetDeletedRecordsAfterRevision(a.ctx, a.userLogin, int64(0), a.current).
+						Return(nil, nil),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+				)
+			},
+			args: args{
+				ctx:       ctxWithValue,
+				userLogin: testUserLogin,
+				c:         testCard,
+				since:     10,
+				current:   5,
+			},
+			wantRes: &pb.SyncUserDataResponse{
+				SyncErrors:       []*pb.SyncUserDataResponse_SyncErrorInfo{},
+				NewLogins:        []*pb.UserLoginPwd{},
+				NewCards:         []*pb.UserCard{testCardPb},
+				NewTextRecords:   []*pb.UserTextRecord{},
+				NewBinaryRecords: []*pb.UserBinaryRecord{},
+				NewBinaryRefs:    []*pb.BinaryRecordRef{},
+				NewOtps:          []*pb.UserOtp{},
+				NewSshKeys:       []*pb.UserSshKey{},
+				NewTemplates:     []*pb.UserTemplate{},
+				NewCustomRecords: []*pb.UserCustomRecord{},
+				DeletedRecords:   []*pb.RecordKey{},
+				CurrentRevision:  5,
 			},
 			wantErr: false,
 		},
 		{
 			name: "ok test with clients data",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				tp, err := time.Parse(time.RFC3339, a.lastSync)
+				tp, err := time.Parse(time.RFC3339, a.timeStamp)
 				require.NoError(t, err)
 				gomock.InOrder(
-					m.EXPECT().GetUserCardsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.Card{a.c, a.c}, nil).AnyTimes(),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.LoginPwd{a.l, a.l}, nil).AnyTimes(),
-					m.EXPECT().GetUserTextRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.TextRecord{a.t, a.t}, nil).AnyTimes(),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.BinaryRecord{a.b, a.b}, nil).AnyTimes(),
-					m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code, a.c.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.t.Prompt, a.t.Data, a.t.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.b.Prompt, a.b.Data, a.b.Note, tp).
-						Return(nil).AnyTimes(),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, a.since, a.current).
+						Return([]any{a.c, a.c}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, a.since, a.current).
+						Return([]any{a.l, a.l}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, a.since, a.current).
+						Return([]any{a.t, a.t}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, a.since, a.current).
+						Return([]any{a.b, a.b}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.OtpRecord, a.since, a.current).
+						Return(a.o, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.SshKeyRecord, a.since, a.current).
+						Return(a.k, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TemplateRecord, a.since, a.current).
+						Return(a.tm, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CustomDataRecord, a.since, a.current).
+						Return(a.cr, nil).AnyTimes(),
+					m.EXPECT().GetDeletedRecordsAfterRevision(a.ctx, a.userLogin, a.since, a.current).
+						Return(nil, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+						Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+						Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+						Prompt: a.l.Prompt, Login: a.l.Login, Pwd: a.l.Pwd, Note: a.l.Note,
+						Tags: a.l.Tags, Folder: a.l.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+						Prompt: a.t.Prompt, Data: a.t.Data, Note: a.t.Note, Tags: a.t.Tags,
+						Folder: a.t.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+						Prompt: a.b.Prompt, Data: a.b.Data, Note: a.b.Note, Tags: a.b.Tags,
+						Folder: a.b.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, testOtp).Return(testRevision, nil),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, testSshKey).Return(testRevision, nil),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, testTemplate).Return(testRevision, nil),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, testCustomRecord).Return(testRevision, nil),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current+8, nil),
 				)
 			},
 			args: args{
@@ -981,43 +1276,139 @@ func TestSyncUserData(t *testing.T) {
 				l:         testLoginPwd,
 				t:         testTextRecord,
 				b:         testBinaryRecord,
-				lastSync:  "0001-01-01T00:00:00Z",
+				o:         []any{testOtp},
+				k:         []any{testSshKey},
+				tm:        []any{testTemplate},
+				cr:        []any{testCustomRecord},
+				timeStamp: "0001-01-01T00:00:00Z",
+				since:     3,
+				current:   5,
 			},
 			inCards:    []*pb.UserCard{testCardPb},
 			inLogins:   []*pb.UserLoginPwd{testLoginPwdPb},
 			inTexts:    []*pb.UserTextRecord{testTextPb},
 			inBinaryes: []*pb.UserBinaryRecord{testBinaryPb},
+			inOtps:     []*pb.UserOtp{testOtpPb},
+			inSshKeys:  []*pb.UserSshKey{testSshKeyPb},
+			inTmpls:    []*pb.UserTemplate{testTemplatePb},
+			inCustoms:  []*pb.UserCustomRecord{testCustomRecordPb},
+			wantRes: &pb.SyncUserDataResponse{
+				SyncErrors:       []*pb.SyncUserDataResponse_SyncErrorInfo{},
+				NewLogins:        []*pb.UserLoginPwd{},
+				NewCards:         []*pb.UserCard{},
+				NewTextRecords:   []*pb.UserTextRecord{},
+				NewBinaryRecords: []*pb.UserBinaryRecord{},
+				NewBinaryRefs:    []*pb.BinaryRecordRef{},
+				NewOtps:          []*pb.UserOtp{},
+				NewSshKeys:       []*pb.UserSshKey{},
+				NewTemplates:     []*pb.UserTemplate{},
+				NewCustomRecords: []*pb.UserCustomRecord{},
+				DeletedRecords:   []*pb.RecordKey{},
+				CurrentRevision:  13,
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok test with deleted records",
+			prepare: func(m *mocks.MockRepositorier, a args) {
+				tp, err := time.Parse(time.RFC3339, a.timeStamp)
+				require.NoError(t, err)
+				gomock.InOrder(
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, a.since, a.current).Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, a.since, a.current).Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, a.since, a.current).Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, a.since, a.current).Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.OtpRecord, a.since, a.current).Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.SshKeyRecord, a.since, a.current).Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TemplateRecord, a.since, a.current).Return(nil, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CustomDataRecord, a.since, a.current).Return(nil, nil),
+					m.EXPECT().GetDeletedRecordsAfterRevision(a.ctx, a.userLogin, a.since, a.current).
+						Return([]storage.DeletedRecord{
+							{Type: storage.CardRecord, Key: a.c.Number},
+							{Type: storage.TextDataRecord, Prompt: a.t.Prompt},
+						}, nil),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+						Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+						Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current+1, nil),
+				)
+			},
+			args: args{
+				ctx:       ctxWithValue,
+				userLogin: testUserLogin,
+				c:         testCard,
+				t:         testTextRecord,
+				timeStamp: "0001-01-01T00:00:00Z",
+				since:     3,
+				current:   5,
+			},
+			inCards: []*pb.UserCard{testCardPb},
 			wantRes: &pb.SyncUserDataResponse{
 				SyncErrors:       []*pb.SyncUserDataResponse_SyncErrorInfo{},
 				NewLogins:        []*pb.UserLoginPwd{},
 				NewCards:         []*pb.UserCard{},
 				NewTextRecords:   []*pb.UserTextRecord{},
 				NewBinaryRecords: []*pb.UserBinaryRecord{},
+				NewBinaryRefs:    []*pb.BinaryRecordRef{},
+				NewOtps:          []*pb.UserOtp{},
+				NewSshKeys:       []*pb.UserSshKey{},
+				NewTemplates:     []*pb.UserTemplate{},
+				NewCustomRecords: []*pb.UserCustomRecord{},
+				DeletedRecords: []*pb.RecordKey{
+					{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+				},
+				CurrentRevision: 6,
 			},
 			wantErr: false,
 		},
 		{
 			name: "empty user test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				tp, err := time.Parse(time.RFC3339, a.lastSync)
+				tp, err := time.Parse(time.RFC3339, a.timeStamp)
 				require.NoError(t, err)
 				gomock.InOrder(
-					m.EXPECT().GetUserCardsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.Card{a.c}, nil).AnyTimes(),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.LoginPwd{a.l}, nil).AnyTimes(),
-					m.EXPECT().GetUserTextRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.TextRecord{a.t}, nil).AnyTimes(),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.BinaryRecord{a.b}, nil).AnyTimes(),
-					m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code, a.c.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.t.Prompt, a.t.Data, a.t.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.b.Prompt, a.b.Data, a.b.Note, tp).
-						Return(nil).AnyTimes(),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, a.since, a.current).
+						Return([]any{a.c}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, a.since, a.current).
+						Return([]any{a.l}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, a.since, a.current).
+						Return([]any{a.t}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, a.since, a.current).
+						Return([]any{a.b}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.OtpRecord, a.since, a.current).
+						Return(a.o, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.SshKeyRecord, a.since, a.current).
+						Return(a.k, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TemplateRecord, a.since, a.current).
+						Return(a.tm, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CustomDataRecord, a.since, a.current).
+						Return(a.cr, nil).AnyTimes(),
+					m.EXPECT().GetDeletedRecordsAfterRevision(a.ctx, a.userLogin, a.since, a.current).
+						Return(nil, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+						Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+						Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+						Prompt: a.l.Prompt, Login: a.l.Login, Pwd: a.l.Pwd, Note: a.l.Note,
+						Tags: a.l.Tags, Folder: a.l.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+						Prompt: a.t.Prompt, Data: a.t.Data, Note: a.t.Note, Tags: a.t.Tags,
+						Folder: a.t.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+						Prompt: a.b.Prompt, Data: a.b.Data, Note: a.b.Note, Tags: a.b.Tags,
+						Folder: a.b.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
 				)
 			},
 			args: args{
@@ -1027,7 +1418,9 @@ func TestSyncUserData(t *testing.T) {
 				l:         testLoginPwd,
 				t:         testTextRecord,
 				b:         testBinaryRecord,
-				lastSync:  testTime,
+				timeStamp: testTime,
+				since:     3,
+				current:   5,
 			},
 			inCards:    []*pb.UserCard{},
 			inLogins:   []*pb.UserLoginPwd{},
@@ -1039,32 +1432,19 @@ func TestSyncUserData(t *testing.T) {
 				NewCards:         []*pb.UserCard{},
 				NewTextRecords:   []*pb.UserTextRecord{},
 				NewBinaryRecords: []*pb.UserBinaryRecord{},
+				NewBinaryRefs:    []*pb.BinaryRecordRef{},
+				NewOtps:          []*pb.UserOtp{},
+				NewSshKeys:       []*pb.UserSshKey{},
+				NewTemplates:     []*pb.UserTemplate{},
+				NewCustomRecords: []*pb.UserCustomRecord{},
+				DeletedRecords:   []*pb.RecordKey{},
 			},
 			wantErr: true,
 		},
 		{
-			name: "error parse time test",
+			name: "get sync revision error",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				tp, err := time.Parse(time.RFC3339, testTime)
-				require.NoError(t, err)
-				gomock.InOrder(
-					m.EXPECT().GetUserCardsAfterTime(a.ctx, a.userLogin, testTimePrs).
-						Return([]storage.Card{a.c}, nil).AnyTimes(),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(a.ctx, a.userLogin, testTimePrs).
-						Return([]storage.LoginPwd{a.l}, nil).AnyTimes(),
-					m.EXPECT().GetUserTextRecordsAfterTime(a.ctx, a.userLogin, testTimePrs).
-						Return([]storage.TextRecord{a.t}, nil).AnyTimes(),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(a.ctx, a.userLogin, testTimePrs).
-						Return([]storage.BinaryRecord{a.b}, nil).AnyTimes(),
-					m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code, a.c.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.t.Prompt, a.t.Data, a.t.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.b.Prompt, a.b.Data, a.b.Note, tp).
-						Return(nil).AnyTimes(),
-				)
+				m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(int64(0), errors.New("error"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1073,7 +1453,9 @@ func TestSyncUserData(t *testing.T) {
 				l:         testLoginPwd,
 				t:         testTextRecord,
 				b:         testBinaryRecord,
-				lastSync:  "2024-01T15:04:05Z",
+				timeStamp: "2024-01T15:04:05Z",
+				since:     3,
+				current:   5,
 			},
 			inCards:    []*pb.UserCard{},
 			inLogins:   []*pb.UserLoginPwd{},
@@ -1085,31 +1467,60 @@ func TestSyncUserData(t *testing.T) {
 				NewCards:         []*pb.UserCard{},
 				NewTextRecords:   []*pb.UserTextRecord{},
 				NewBinaryRecords: []*pb.UserBinaryRecord{},
+				NewBinaryRefs:    []*pb.BinaryRecordRef{},
+				NewOtps:          []*pb.UserOtp{},
+				NewSshKeys:       []*pb.UserSshKey{},
+				NewTemplates:     []*pb.UserTemplate{},
+				NewCustomRecords: []*pb.UserCustomRecord{},
+				DeletedRecords:   []*pb.RecordKey{},
 			},
 			wantErr: true,
 		},
 		{
 			name: "get user text after time error",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				tp, err := time.Parse(time.RFC3339, a.lastSync)
+				tp, err := time.Parse(time.RFC3339, a.timeStamp)
 				require.NoError(t, err)
 				gomock.InOrder(
-					m.EXPECT().GetUserCardsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.Card{a.c}, nil).AnyTimes(),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.LoginPwd{a.l}, nil).AnyTimes(),
-					m.EXPECT().GetUserTextRecordsAfterTime(a.ctx, a.userLogin, tp).
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, a.since, a.current).
+						Return([]any{a.c}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, a.since, a.current).
+						Return([]any{a.l}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, a.since, a.current).
 						Return(nil, errors.New("error")),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.BinaryRecord{a.b}, nil).AnyTimes(),
-					m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code, a.c.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.t.Prompt, a.t.Data, a.t.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.b.Prompt, a.b.Data, a.b.Note, tp).
-						Return(nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, a.since, a.current).
+						Return([]any{a.b}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.OtpRecord, a.since, a.current).
+						Return(a.o, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.SshKeyRecord, a.since, a.current).
+						Return(a.k, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TemplateRecord, a.since, a.current).
+						Return(a.tm, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CustomDataRecord, a.since, a.current).
+						Return(a.cr, nil).AnyTimes(),
+					m.EXPECT().GetDeletedRecordsAfterRevision(a.ctx, a.userLogin, a.since, a.current).
+						Return(nil, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+						Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+						Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+						Prompt: a.l.Prompt, Login: a.l.Login, Pwd: a.l.Pwd, Note: a.l.Note,
+						Tags: a.l.Tags, Folder: a.l.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+						Prompt: a.t.Prompt, Data: a.t.Data, Note: a.t.Note, Tags: a.t.Tags,
+						Folder: a.t.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+						Prompt: a.b.Prompt, Data: a.b.Data, Note: a.b.Note, Tags: a.b.Tags,
+						Folder: a.b.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
 				)
 			},
 			args: args{
@@ -1119,7 +1530,9 @@ func TestSyncUserData(t *testing.T) {
 				l:         testLoginPwd,
 				t:         testTextRecord,
 				b:         testBinaryRecord,
-				lastSync:  testTime,
+				timeStamp: testTime,
+				since:     3,
+				current:   5,
 			},
 			inCards:    []*pb.UserCard{},
 			inLogins:   []*pb.UserLoginPwd{},
@@ -1131,25 +1544,48 @@ func TestSyncUserData(t *testing.T) {
 		{
 			name: "get user logins after time error",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				tp, err := time.Parse(time.RFC3339, a.lastSync)
+				tp, err := time.Parse(time.RFC3339, a.timeStamp)
 				require.NoError(t, err)
 				gomock.InOrder(
-					m.EXPECT().GetUserCardsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.Card{a.c}, nil).AnyTimes(),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(a.ctx, a.userLogin, tp).
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, a.since, a.current).
+						Return([]any{a.c}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, a.since, a.current).
 						Return(nil, errors.New("error")),
-					m.EXPECT().GetUserTextRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.TextRecord{a.t}, nil).AnyTimes(),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.BinaryRecord{a.b}, nil).AnyTimes(),
-					m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code, a.c.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.t.Prompt, a.t.Data, a.t.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.b.Prompt, a.b.Data, a.b.Note, tp).
-						Return(nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, a.since, a.current).
+						Return([]any{a.t}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, a.since, a.current).
+						Return([]any{a.b}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.OtpRecord, a.since, a.current).
+						Return(a.o, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.SshKeyRecord, a.since, a.current).
+						Return(a.k, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TemplateRecord, a.since, a.current).
+						Return(a.tm, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CustomDataRecord, a.since, a.current).
+						Return(a.cr, nil).AnyTimes(),
+					m.EXPECT().GetDeletedRecordsAfterRevision(a.ctx, a.userLogin, a.since, a.current).
+						Return(nil, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+						Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+						Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+						Prompt: a.l.Prompt, Login: a.l.Login, Pwd: a.l.Pwd, Note: a.l.Note,
+						Tags: a.l.Tags, Folder: a.l.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+						Prompt: a.t.Prompt, Data: a.t.Data, Note: a.t.Note, Tags: a.t.Tags,
+						Folder: a.t.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+						Prompt: a.b.Prompt, Data: a.b.Data, Note: a.b.Note, Tags: a.b.Tags,
+						Folder: a.b.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
 				)
 			},
 			args: args{
@@ -1159,7 +1595,9 @@ func TestSyncUserData(t *testing.T) {
 				l:         testLoginPwd,
 				t:         testTextRecord,
 				b:         testBinaryRecord,
-				lastSync:  testTime,
+				timeStamp: testTime,
+				since:     3,
+				current:   5,
 			},
 			inCards:    []*pb.UserCard{},
 			inLogins:   []*pb.UserLoginPwd{},
@@ -1171,25 +1609,48 @@ func TestSyncUserData(t *testing.T) {
 		{
 			name: "get user cards after time error",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				tp, err := time.Parse(time.RFC3339, a.lastSync)
+				tp, err := time.Parse(time.RFC3339, a.timeStamp)
 				require.NoError(t, err)
 				gomock.InOrder(
-					m.EXPECT().GetUserCardsAfterTime(a.ctx, a.userLogin, tp).
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, a.since, a.current).
 						Return(nil, errors.New("error")),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.LoginPwd{a.l}, nil).AnyTimes(),
-					m.EXPECT().GetUserTextRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.TextRecord{a.t}, nil).AnyTimes(),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.BinaryRecord{a.b}, nil).AnyTimes(),
-					m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code, a.c.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.t.Prompt, a.t.Data, a.t.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.b.Prompt, a.b.Data, a.b.Note, tp).
-						Return(nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, a.since, a.current).
+						Return([]any{a.l}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, a.since, a.current).
+						Return([]any{a.t}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, a.since, a.current).
+						Return([]any{a.b}, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.OtpRecord, a.since, a.current).
+						Return(a.o, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.SshKeyRecord, a.since, a.current).
+						Return(a.k, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TemplateRecord, a.since, a.current).
+						Return(a.tm, nil).AnyTimes(),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CustomDataRecord, a.since, a.current).
+						Return(a.cr, nil).AnyTimes(),
+					m.EXPECT().GetDeletedRecordsAfterRevision(a.ctx, a.userLogin, a.since, a.current).
+						Return(nil, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+						Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+						Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+						Prompt: a.l.Prompt, Login: a.l.Login, Pwd: a.l.Pwd, Note: a.l.Note,
+						Tags: a.l.Tags, Folder: a.l.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+						Prompt: a.t.Prompt, Data: a.t.Data, Note: a.t.Note, Tags: a.t.Tags,
+						Folder: a.t.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+						Prompt: a.b.Prompt, Data: a.b.Data, Note: a.b.Note, Tags: a.b.Tags,
+						Folder: a.b.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
 				)
 			},
 			args: args{
@@ -1199,7 +1660,9 @@ func TestSyncUserData(t *testing.T) {
 				l:         testLoginPwd,
 				t:         testTextRecord,
 				b:         testBinaryRecord,
-				lastSync:  testTime,
+				timeStamp: testTime,
+				since:     3,
+				current:   5,
 			},
 			inCards:    []*pb.UserCard{},
 			inLogins:   []*pb.UserLoginPwd{},
@@ -1211,25 +1674,38 @@ func TestSyncUserData(t *testing.T) {
 		{
 			name: "get user binary after time error",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				tp, err := time.Parse(time.RFC3339, a.lastSync)
+				tp, err := time.Parse(time.RFC3339, a.timeStamp)
 				require.NoError(t, err)
 				gomock.InOrder(
-					m.EXPECT().GetUserCardsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.Card{a.c}, nil),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.LoginPwd{a.l}, nil),
-					m.EXPECT().GetUserTextRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.TextRecord{a.t}, nil),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(a.ctx, a.userLogin, tp).
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, a.since, a.current).
+						Return([]any{a.c}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, a.since, a.current).
+						Return([]any{a.l}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, a.since, a.current).
+						Return([]any{a.t}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, a.since, a.current).
 						Return(nil, errors.New("error")),
-					m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code, a.c.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.t.Prompt, a.t.Data, a.t.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.b.Prompt, a.b.Data, a.b.Note, tp).
-						Return(nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+						Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+						Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+						Prompt: a.l.Prompt, Login: a.l.Login, Pwd: a.l.Pwd, Note: a.l.Note,
+						Tags: a.l.Tags, Folder: a.l.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+						Prompt: a.t.Prompt, Data: a.t.Data, Note: a.t.Note, Tags: a.t.Tags,
+						Folder: a.t.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+						Prompt: a.b.Prompt, Data: a.b.Data, Note: a.b.Note, Tags: a.b.Tags,
+						Folder: a.b.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
 				)
 			},
 			args: args{
@@ -1239,7 +1715,9 @@ func TestSyncUserData(t *testing.T) {
 				l:         testLoginPwd,
 				t:         testTextRecord,
 				b:         testBinaryRecord,
-				lastSync:  testTime,
+				timeStamp: testTime,
+				since:     3,
+				current:   5,
 			},
 			inCards:    []*pb.UserCard{},
 			inLogins:   []*pb.UserLoginPwd{},
@@ -1251,25 +1729,49 @@ func TestSyncUserData(t *testing.T) {
 		{
 			name: "add cards time parse error",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				tp, err := time.Parse(time.RFC3339, a.lastSync)
+				tp, err := time.Parse(time.RFC3339, a.timeStamp)
 				require.NoError(t, err)
 				gomock.InOrder(
-					m.EXPECT().GetUserCardsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.Card{a.c}, nil),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.LoginPwd{a.l}, nil),
-					m.EXPECT().GetUserTextRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.TextRecord{a.t}, nil),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.BinaryRecord{a.b}, nil),
-					m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code, a.c.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.t.Prompt, a.t.Data, a.t.Note, tp).
-						Return(nil).AnyTimes(),
-					m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.b.Prompt, a.b.Data, a.b.Note, tp).
-						Return(nil).AnyTimes(),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, a.since, a.current).
+						Return([]any{a.c}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, a.since, a.current).
+						Return([]any{a.l}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, a.since, a.current).
+						Return([]any{a.t}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, a.since, a.current).
+						Return([]any{a.b}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.OtpRecord, a.since, a.current).
+						Return(a.o, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.SshKeyRecord, a.since, a.current).
+						Return(a.k, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TemplateRecord, a.since, a.current).
+						Return(a.tm, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CustomDataRecord, a.since, a.current).
+						Return(a.cr, nil),
+					m.EXPECT().GetDeletedRecordsAfterRevision(a.ctx, a.userLogin, a.since, a.current).
+						Return(nil, nil),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+						Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+						Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+						Prompt: a.l.Prompt, Login: a.l.Login, Pwd: a.l.Pwd, Note: a.l.Note,
+						Tags: a.l.Tags, Folder: a.l.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+						Prompt: a.t.Prompt, Data: a.t.Data, Note: a.t.Note, Tags: a.t.Tags,
+						Folder: a.t.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+						Prompt: a.b.Prompt, Data: a.b.Data, Note: a.b.Note, Tags: a.b.Tags,
+						Folder: a.b.Folder, TimeStamp: tp,
+					}).
+						Return(testRevision, nil).AnyTimes(),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
 				)
 			},
 			args: args{
@@ -1279,7 +1781,9 @@ func TestSyncUserData(t *testing.T) {
 				l:         testLoginPwd,
 				t:         testTextRecord,
 				b:         testBinaryRecord,
-				lastSync:  testTime,
+				timeStamp: testTime,
+				since:     3,
+				current:   5,
 			},
 			inCards: []*pb.UserCard{{
 				Prompt:    testCard.Prompt,
@@ -1314,52 +1818,93 @@ func TestSyncUserData(t *testing.T) {
 						Text:  "error for card number ",
 						Value: testCard.Number,
 						Err:   "parsing time \"1\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"1\" as \"2006\"",
+						Key:   &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number},
+						Code:  pb.SyncErrorCode_SYNC_ERROR_CODE_INVALID_TIMESTAMP,
 					},
 					{
 						Text:  "error for pair login/password with prompt ",
 						Value: testLoginPwd.Prompt,
 						Err:   "parsing time \"1\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"1\" as \"2006\"",
+						Key:   &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_LOGIN_PWD, Prompt: testLoginPwd.Prompt, Key: testLoginPwd.Login},
+						Code:  pb.SyncErrorCode_SYNC_ERROR_CODE_INVALID_TIMESTAMP,
 					},
 					{
 						Text:  "error for text data with prompt ",
 						Value: testTextRecord.Prompt,
 						Err:   "parsing time \"1\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"1\" as \"2006\"",
+						Key:   &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+						Code:  pb.SyncErrorCode_SYNC_ERROR_CODE_INVALID_TIMESTAMP,
 					},
 					{
 						Text:  "error for binary data with prompt ",
 						Value: testBinaryPb.Prompt,
 						Err:   "parsing time \"1\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"1\" as \"2006\"",
+						Key:   &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: testBinaryPb.Prompt},
+						Code:  pb.SyncErrorCode_SYNC_ERROR_CODE_INVALID_TIMESTAMP,
 					},
 				},
 				NewLogins:        []*pb.UserLoginPwd{testLoginPwdPb},
 				NewCards:         []*pb.UserCard{testCardPb},
 				NewTextRecords:   []*pb.UserTextRecord{testTextPb},
-				NewBinaryRecords: []*pb.UserBinaryRecord{testBinaryPb},
+				NewBinaryRecords: []*pb.UserBinaryRecord{},
+				NewBinaryRefs:    []*pb.BinaryRecordRef{testBinaryRef},
+				NewOtps:          []*pb.UserOtp{},
+				NewSshKeys:       []*pb.UserSshKey{},
+				NewTemplates:     []*pb.UserTemplate{},
+				NewCustomRecords: []*pb.UserCustomRecord{},
+				DeletedRecords:   []*pb.RecordKey{},
+				CurrentRevision:  5,
 			},
 			wantErr: false,
 		},
 		{
 			name: "add records error test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				tp, err := time.Parse(time.RFC3339, a.lastSync)
+				tp, err := time.Parse(time.RFC3339, a.timeStamp)
+				require.NoError(t, err)
+				serverTime, err := time.Parse(time.RFC3339, testTime)
 				require.NoError(t, err)
 				gomock.InOrder(
-					m.EXPECT().GetUserCardsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.Card{a.c}, nil),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.LoginPwd{a.l}, nil),
-					m.EXPECT().GetUserTextRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.TextRecord{a.t}, nil),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(a.ctx, a.userLogin, tp).
-						Return([]storage.BinaryRecord{a.b}, nil),
-					m.EXPECT().AddCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code, a.c.Note, tp).
-						Return(errors.New("add card error")).AnyTimes(),
-					m.EXPECT().AddLoginPwd(a.ctx, a.userLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, tp).
-						Return(errors.New("add login error")).AnyTimes(),
-					m.EXPECT().AddTextRecord(a.ctx, a.userLogin, a.t.Prompt, a.t.Data, a.t.Note, tp).
-						Return(errors.New("add text error")).AnyTimes(),
-					m.EXPECT().AddBinaryRecord(a.ctx, a.userLogin, a.b.Prompt, a.b.Data, a.b.Note, tp).
-						Return((errors.New("add bytes error"))).AnyTimes(),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CardRecord, a.since, a.current).
+						Return([]any{a.c}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.LoginPwdRecord, a.since, a.current).
+						Return([]any{a.l}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TextDataRecord, a.since, a.current).
+						Return([]any{a.t}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.BinaryDataRecord, a.since, a.current).
+						Return([]any{a.b}, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.OtpRecord, a.since, a.current).
+						Return(a.o, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.SshKeyRecord, a.since, a.current).
+						Return(a.k, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.TemplateRecord, a.since, a.current).
+						Return(a.tm, nil),
+					m.EXPECT().GetRecordsAfterRevision(a.ctx, a.userLogin, storage.CustomDataRecord, a.since, a.current).
+						Return(a.cr, nil),
+					m.EXPECT().GetDeletedRecordsAfterRevision(a.ctx, a.userLogin, a.since, a.current).
+						Return(nil, nil),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.Card{
+						Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+						Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+					}).
+						Return(storage.Revision{}, storage.NewConflictError(serverTime, errors.New("add card error"))).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.LoginPwd{
+						Prompt: a.l.Prompt, Login: a.l.Login, Pwd: a.l.Pwd, Note: a.l.Note,
+						Tags: a.l.Tags, Folder: a.l.Folder, TimeStamp: tp,
+					}).
+						Return(storage.Revision{}, errors.New("add login error")).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.TextRecord{
+						Prompt: a.t.Prompt, Data: a.t.Data, Note: a.t.Note, Tags: a.t.Tags,
+						Folder: a.t.Folder, TimeStamp: tp,
+					}).
+						Return(storage.Revision{}, errors.New("add text error")).AnyTimes(),
+					m.EXPECT().AddRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+						Prompt: a.b.Prompt, Data: a.b.Data, Note: a.b.Note, Tags: a.b.Tags,
+						Folder: a.b.Folder, TimeStamp: tp,
+					}).
+						Return(storage.Revision{}, (errors.New("add bytes error"))).AnyTimes(),
+					m.EXPECT().GetSyncRevision(a.ctx, a.userLogin).Return(a.current+4, nil),
 				)
 			},
 			args: args{
@@ -1369,7 +1914,9 @@ func TestSyncUserData(t *testing.T) {
 				l:         testLoginPwd,
 				t:         testTextRecord,
 				b:         testBinaryRecord,
-				lastSync:  time.Time{}.Format(time.RFC3339),
+				timeStamp: time.Time{}.Format(time.RFC3339),
+				since:     3,
+				current:   5,
 			},
 			inCards:    []*pb.UserCard{testCardPb},
 			inLogins:   []*pb.UserLoginPwd{testLoginPwdPb},
@@ -1378,30 +1925,46 @@ func TestSyncUserData(t *testing.T) {
 			wantRes: &pb.SyncUserDataResponse{
 				SyncErrors: []*pb.SyncUserDataResponse_SyncErrorInfo{
 					{
-						Text:  "error for card number ",
-						Value: testCard.Number,
-						Err:   "add card error",
+						Text:            "error for card number ",
+						Value:           testCard.Number,
+						Err:             storage.NewConflictError(time.Time{}, nil).Error(),
+						Key:             &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number},
+						Code:            pb.SyncErrorCode_SYNC_ERROR_CODE_CONFLICT_NEWER_ON_SERVER,
+						ServerTimeStamp: testTime,
 					},
 					{
 						Text:  "error for pair login/password with prompt ",
 						Value: testLoginPwd.Prompt,
 						Err:   "add login error",
+						Key:   &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_LOGIN_PWD, Prompt: testLoginPwd.Prompt, Key: testLoginPwd.Login},
+						Code:  pb.SyncErrorCode_SYNC_ERROR_CODE_INTERNAL,
 					},
 					{
 						Text:  "error for text data with prompt ",
 						Value: testTextRecord.Prompt,
 						Err:   "add text error",
+						Key:   &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+						Code:  pb.SyncErrorCode_SYNC_ERROR_CODE_INTERNAL,
 					},
 					{
 						Text:  "error for binary data with prompt ",
 						Value: testBinaryPb.Prompt,
 						Err:   "add bytes error",
+						Key:   &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: testBinaryPb.Prompt},
+						Code:  pb.SyncErrorCode_SYNC_ERROR_CODE_INTERNAL,
 					},
 				},
 				NewLogins:        []*pb.UserLoginPwd{},
 				NewCards:         []*pb.UserCard{},
 				NewTextRecords:   []*pb.UserTextRecord{},
 				NewBinaryRecords: []*pb.UserBinaryRecord{},
+				NewBinaryRefs:    []*pb.BinaryRecordRef{},
+				NewOtps:          []*pb.UserOtp{},
+				NewSshKeys:       []*pb.UserSshKey{},
+				NewTemplates:     []*pb.UserTemplate{},
+				NewCustomRecords: []*pb.UserCustomRecord{},
+				DeletedRecords:   []*pb.RecordKey{},
+				CurrentRevision:  5,
 			},
 			wantErr: false,
 		},
@@ -1413,6 +1976,8 @@ func TestSyncUserData(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 
 			if tt.prepare != nil {
@@ -1423,7 +1988,11 @@ func TestSyncUserData(t *testing.T) {
 				Cards:         tt.inCards,
 				TextRecords:   tt.inTexts,
 				BinaryRecords: tt.inBinaryes,
-				LastSync:      tt.args.lastSync,
+				Otps:          tt.inOtps,
+				SshKeys:       tt.inSshKeys,
+				Templates:     tt.inTmpls,
+				CustomRecords: tt.inCustoms,
+				SinceRevision: tt.args.since,
 			})
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -1464,7 +2033,7 @@ func TestGetUserCard(t *testing.T) {
 		{
 			name: "ok test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetCard(a.ctx, a.userLogin, a.c.Number).Return(a.c, nil)
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.Card{Number: a.c.Number}).Return(a.c, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1493,7 +2062,7 @@ func TestGetUserCard(t *testing.T) {
 		{
 			name: "missing login test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetCard(a.ctx, a.userLogin, a.c.Number).Return(a.c, nil).AnyTimes()
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.Card{Number: a.c.Number}).Return(a.c, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -1508,7 +2077,9 @@ func TestGetUserCard(t *testing.T) {
 		{
 			name: "error test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetCard(a.ctx, a.userLogin, a.c.Number).Return(storage.Card{}, errors.New("error"))
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.Card{
+					Number: a.c.Number,
+				}).Return(storage.Card{}, errors.New("error"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1535,6 +2106,8 @@ func TestGetUserCard(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -1579,7 +2152,9 @@ func TestGetUserLogin(t *testing.T) {
 		{
 			name: "ok test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login).Return(a.c, nil)
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login,
+				}).Return(a.c, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1606,7 +2181,9 @@ func TestGetUserLogin(t *testing.T) {
 		{
 			name: "missing login test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login).Return(a.c, nil).AnyTimes()
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login,
+				}).Return(a.c, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -1621,7 +2198,9 @@ func TestGetUserLogin(t *testing.T) {
 		{
 			name: "error test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login).Return(storage.LoginPwd{}, errors.New("error"))
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login,
+				}).Return(storage.LoginPwd{}, errors.New("error"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1647,6 +2226,8 @@ func TestGetUserLogin(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -1694,7 +2275,7 @@ func TestGetUserText(t *testing.T) {
 		{
 			name: "ok test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetTextRecord(a.ctx, a.userLogin, a.c.Prompt).Return(a.c, nil)
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.TextRecord{Prompt: a.c.Prompt}).Return(a.c, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1719,7 +2300,9 @@ func TestGetUserText(t *testing.T) {
 		{
 			name: "missing login test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetTextRecord(a.ctx, a.userLogin, a.c.Prompt).Return(a.c, nil).AnyTimes()
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt,
+				}).Return(a.c, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -1734,7 +2317,9 @@ func TestGetUserText(t *testing.T) {
 		{
 			name: "error test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetTextRecord(a.ctx, a.userLogin, a.c.Prompt).Return(storage.TextRecord{}, errors.New("error"))
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt,
+				}).Return(storage.TextRecord{}, errors.New("error"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1759,6 +2344,8 @@ func TestGetUserText(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -1803,7 +2390,7 @@ func TestGetUserBinary(t *testing.T) {
 		{
 			name: "ok test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetBinaryRecord(a.ctx, a.userLogin, a.c.Prompt).Return(a.c, nil)
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.BinaryRecord{Prompt: a.c.Prompt}).Return(a.c, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1828,7 +2415,9 @@ func TestGetUserBinary(t *testing.T) {
 		{
 			name: "missing login test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetBinaryRecord(a.ctx, a.userLogin, a.c.Prompt).Return(a.c, nil).AnyTimes()
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt,
+				}).Return(a.c, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -1843,7 +2432,9 @@ func TestGetUserBinary(t *testing.T) {
 		{
 			name: "error test",
 			prepare: func(m *mocks.MockRepositorier, a args) {
-				m.EXPECT().GetBinaryRecord(a.ctx, a.userLogin, a.c.Prompt).Return(storage.BinaryRecord{}, errors.New("error"))
+				m.EXPECT().GetRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt,
+				}).Return(storage.BinaryRecord{}, errors.New("error"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1868,6 +2459,8 @@ func TestGetUserBinary(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -1909,8 +2502,10 @@ func TestForceUpdateCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).Return(nil)
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1925,8 +2520,10 @@ func TestForceUpdateCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).Return(nil).AnyTimes()
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -1941,9 +2538,11 @@ func TestForceUpdateCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).
-					Return(storage.NewStorError(storage.EmptyValues, errors.New("err")))
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.EmptyValues, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1965,8 +2564,10 @@ func TestForceUpdateCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).Return(errors.New("err"))
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(storage.Revision{}, errors.New("err"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -1981,8 +2582,10 @@ func TestForceUpdateCard(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, testTime)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateCard(a.ctx, a.userLogin, a.c.Prompt, a.c.Number,
-					a.c.Date, a.c.Code, a.c.Note, tp).Return(nil).AnyTimes()
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.Card{
+					Prompt: a.c.Prompt, Number: a.c.Number, Date: a.c.Date, Code: a.c.Code,
+					Note: a.c.Note, Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2000,6 +2603,8 @@ func TestForceUpdateCard(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -2049,7 +2654,10 @@ func TestForceUpdateLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).Return(nil)
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2064,7 +2672,10 @@ func TestForceUpdateLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).Return(nil).AnyTimes()
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -2079,8 +2690,11 @@ func TestForceUpdateLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).
-					Return(storage.NewStorError(storage.EmptyValues, errors.New("err")))
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.EmptyValues, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2101,8 +2715,11 @@ func TestForceUpdateLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).
-					Return(errors.New("err"))
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, errors.New("err"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2117,8 +2734,11 @@ func TestForceUpdateLogin(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, testTime)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateLoginPwd(a.ctx, a.userLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, tp).
-					Return(nil).AnyTimes()
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.LoginPwd{
+					Prompt: a.c.Prompt, Login: a.c.Login, Pwd: a.c.Pwd, Note: a.c.Note,
+					Tags: a.c.Tags, Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2136,6 +2756,8 @@ func TestForceUpdateLogin(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -2184,7 +2806,10 @@ func TestForceUpdateTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).Return(nil)
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2199,7 +2824,10 @@ func TestForceUpdateTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).Return(nil).AnyTimes()
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -2214,8 +2842,11 @@ func TestForceUpdateTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(storage.NewStorError(storage.EmptyValues, errors.New("err")))
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.EmptyValues, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2235,8 +2866,11 @@ func TestForceUpdateTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(errors.New("err"))
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, errors.New("err"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2251,8 +2885,11 @@ func TestForceUpdateTextData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, testTime)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateTextRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(nil).AnyTimes()
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.TextRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2270,6 +2907,8 @@ func TestForceUpdateTextData(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -2317,7 +2956,10 @@ func TestForceUpdateBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).Return(nil)
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2332,7 +2974,10 @@ func TestForceUpdateBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).Return(nil).AnyTimes()
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -2347,8 +2992,11 @@ func TestForceUpdateBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(storage.NewStorError(storage.EmptyValues, errors.New("err")))
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.EmptyValues, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2363,8 +3011,11 @@ func TestForceUpdateBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2379,8 +3030,11 @@ func TestForceUpdateBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, a.timeSt)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(errors.New("err"))
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, errors.New("err"))
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2395,8 +3049,11 @@ func TestForceUpdateBinaryData(t *testing.T) {
 			prepare: func(m *mocks.MockRepositorier, a args) {
 				tp, err := time.Parse(time.RFC3339, testTime)
 				require.NoError(t, err)
-				m.EXPECT().ForceUpdateBinaryRecord(a.ctx, a.userLogin, a.c.Prompt, a.c.Data, a.c.Note, tp).
-					Return(nil).AnyTimes()
+				m.EXPECT().ForceUpdateRecord(a.ctx, a.userLogin, storage.BinaryRecord{
+					Prompt: a.c.Prompt, Data: a.c.Data, Note: a.c.Note, Tags: a.c.Tags,
+					Folder: a.c.Folder, TimeStamp: tp,
+				}).
+					Return(testRevision, nil).AnyTimes()
 			},
 			args: args{
 				ctx:       ctxWithValue,
@@ -2414,6 +3071,8 @@ func TestForceUpdateBinaryData(t *testing.T) {
 			defer ctrl.Finish()
 
 			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
 			testGRPC := NewKeeperServer(m, testCfg)
 			if tt.prepare != nil {
 				tt.prepare(m, tt.args)
@@ -2434,3 +3093,1050 @@ func TestForceUpdateBinaryData(t *testing.T) {
 		})
 	}
 }
+
+// testUpload - загрузка бинарных данных в память для тестов UploadBinary.
+type testUpload struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (u *testUpload) Size() int64 {
+	return int64(u.Len())
+}
+
+func (u *testUpload) Sum() []byte {
+	sum := sha256.Sum256(u.Bytes())
+	return sum[:]
+}
+
+func (u *testUpload) Close() error {
+	u.closed = true
+	return nil
+}
+
+func TestUploadBinary(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	if err != nil {
+		fmt.Println("build token error")
+		return
+	}
+	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+
+	testTimePrs, err := time.Parse(time.RFC3339, testTime)
+	if err != nil {
+		fmt.Println("time parse error")
+	}
+	sum := sha256.Sum256(testBinaryRecord.Data)
+
+	infoReq := func(force bool, size int64) *pb.UploadBinaryRequest {
+		return &pb.UploadBinaryRequest{
+			Part: &pb.UploadBinaryRequest_Info_{
+				Info: &pb.UploadBinaryRequest_Info{
+					BinaryRecord: &pb.UserBinaryRecord{
+						Prompt:    testBinaryRecord.Prompt,
+						Note:      testBinaryRecord.Note,
+						TimeStamp: testTime,
+					},
+					Size:  size,
+					Force: force,
+				},
+			},
+		}
+	}
+	chunkReq := &pb.UploadBinaryRequest{Part: &pb.UploadBinaryRequest_Chunk{Chunk: testBinaryRecord.Data}}
+	checksumReq := &pb.UploadBinaryRequest{Part: &pb.UploadBinaryRequest_Checksum{Checksum: sum[:]}}
+	saved := storage.BinaryRecord{
+		Prompt:    testBinaryRecord.Prompt,
+		Note:      testBinaryRecord.Note,
+		TimeStamp: testTimePrs,
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		reqs     []*pb.UploadBinaryRequest
+		prepare  func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_UploadBinaryServer, u *testUpload)
+		quota    int64
+		wantData []byte
+		wantCode codes.Code
+		wantErr  bool
+	}{
+		{
+			name: "ok test",
+			ctx:  ctxWithValue,
+			reqs: []*pb.UploadBinaryRequest{infoReq(false, 2), chunkReq, checksumReq},
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_UploadBinaryServer, u *testUpload) {
+				m.EXPECT().NewBinaryUpload(ctxWithValue).Return(u, nil)
+				m.EXPECT().SaveBinaryUpload(ctxWithValue, testUserLogin, u, saved, false).Return(testRevision, nil)
+				s.EXPECT().SendAndClose(&pb.UploadBinaryResponse{Revision: testRevisionPb}).Return(nil)
+			},
+			wantData: testBinaryRecord.Data,
+			wantErr:  false,
+		},
+		{
+			name: "ok force test",
+			ctx:  ctxWithValue,
+			reqs: []*pb.UploadBinaryRequest{infoReq(true, 2), chunkReq, checksumReq},
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_UploadBinaryServer, u *testUpload) {
+				m.EXPECT().NewBinaryUpload(ctxWithValue).Return(u, nil)
+				m.EXPECT().SaveBinaryUpload(ctxWithValue, testUserLogin, u, saved, true).Return(testRevision, nil)
+				s.EXPECT().SendAndClose(&pb.UploadBinaryResponse{Revision: testRevisionPb}).Return(nil)
+			},
+			wantData: testBinaryRecord.Data,
+			wantErr:  false,
+		},
+		{
+			name:    "missing login test",
+			ctx:     context.Background(),
+			reqs:    []*pb.UploadBinaryRequest{},
+			wantErr: true,
+		},
+		{
+			name: "new upload error test",
+			ctx:  ctxWithValue,
+			reqs: []*pb.UploadBinaryRequest{infoReq(false, 2)},
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_UploadBinaryServer, u *testUpload) {
+				m.EXPECT().NewBinaryUpload(ctxWithValue).Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid time stamp test",
+			ctx:  ctxWithValue,
+			reqs: []*pb.UploadBinaryRequest{{
+				Part: &pb.UploadBinaryRequest_Info_{
+					Info: &pb.UploadBinaryRequest_Info{
+						BinaryRecord: &pb.UserBinaryRecord{
+							Prompt:    testBinaryRecord.Prompt,
+							TimeStamp: "2006-01T15:04:05Z",
+						},
+					},
+				},
+			}},
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_UploadBinaryServer, u *testUpload) {
+				m.EXPECT().NewBinaryUpload(ctxWithValue).Return(u, nil)
+			},
+			wantCode: codes.InvalidArgument,
+			wantErr:  true,
+		},
+		{
+			name: "chunk before info test",
+			ctx:  ctxWithValue,
+			reqs: []*pb.UploadBinaryRequest{chunkReq},
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_UploadBinaryServer, u *testUpload) {
+				m.EXPECT().NewBinaryUpload(ctxWithValue).Return(u, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "data exceeds size test",
+			ctx:  ctxWithValue,
+			reqs: []*pb.UploadBinaryRequest{infoReq(false, 1), chunkReq},
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_UploadBinaryServer, u *testUpload) {
+				m.EXPECT().NewBinaryUpload(ctxWithValue).Return(u, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "checksum mismatch test",
+			ctx:  ctxWithValue,
+			reqs: []*pb.UploadBinaryRequest{infoReq(false, 2), chunkReq,
+				{Part: &pb.UploadBinaryRequest_Checksum{Checksum: []byte{1}}}},
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_UploadBinaryServer, u *testUpload) {
+				m.EXPECT().NewBinaryUpload(ctxWithValue).Return(u, nil)
+			},
+			wantData: testBinaryRecord.Data,
+			wantErr:  true,
+		},
+		{
+			name: "binary quota test",
+			ctx:  ctxWithValue,
+			reqs: []*pb.UploadBinaryRequest{infoReq(false, 2), chunkReq, checksumReq},
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_UploadBinaryServer, u *testUpload) {
+				m.EXPECT().NewBinaryUpload(ctxWithValue).Return(u, nil)
+			},
+			quota:   1,
+			wantErr: true,
+		},
+		{
+			name: "exists newer test",
+			ctx:  ctxWithValue,
+			reqs: []*pb.UploadBinaryRequest{infoReq(false, 2), chunkReq, checksumReq},
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_UploadBinaryServer, u *testUpload) {
+				m.EXPECT().NewBinaryUpload(ctxWithValue).Return(u, nil)
+				m.EXPECT().SaveBinaryUpload(ctxWithValue, testUserLogin, u, saved, false).
+					Return(storage.Revision{}, storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
+			},
+			wantData: testBinaryRecord.Data,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			s := mocks.NewMockInfoKeeper_UploadBinaryServer(ctrl)
+			s.EXPECT().Context().Return(tt.ctx).AnyTimes()
+			calls := make([]*gomock.Call, 0, len(tt.reqs)+1)
+			for _, r := range tt.reqs {
+				calls = append(calls, s.EXPECT().Recv().Return(r, nil).MaxTimes(1))
+			}
+			calls = append(calls, s.EXPECT().Recv().Return(nil, io.EOF).MaxTimes(1))
+			gomock.InOrder(calls...)
+			cfg := testCfg
+			cfg.QuotaBinarySize = tt.quota
+			testGRPC := NewKeeperServer(m, cfg)
+			u := &testUpload{}
+			if tt.prepare != nil {
+				tt.prepare(m, s, u)
+			}
+			err := testGRPC.UploadBinary(s)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.wantCode != codes.OK {
+					assert.Equal(t, tt.wantCode, status.Code(err))
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantData, u.Bytes())
+		})
+	}
+}
+
+func TestDownloadBinary(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	if err != nil {
+		fmt.Println("build token error")
+		return
+	}
+	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	sum := sha256.Sum256(testBinaryRecord.Data)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		prepare  func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_DownloadBinaryServer)
+		wantCode codes.Code
+		wantErr  bool
+	}{
+		{
+			name: "ok test",
+			ctx:  ctxWithValue,
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_DownloadBinaryServer) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.BinaryRecord{
+					Prompt: testBinaryRecord.Prompt,
+				}).
+					Return(testBinaryRecord, nil)
+				gomock.InOrder(
+					s.EXPECT().Send(&pb.DownloadBinaryResponse{
+						Part: &pb.DownloadBinaryResponse_Info{
+							Info: &pb.UserBinaryRecord{
+								Prompt:    testBinaryPb.Prompt,
+								Note:      testBinaryPb.Note,
+								TimeStamp: testBinaryPb.TimeStamp,
+							},
+						},
+					}).Return(nil),
+					s.EXPECT().Send(&pb.DownloadBinaryResponse{
+						Part: &pb.DownloadBinaryResponse_Chunk{Chunk: testBinaryRecord.Data},
+					}).Return(nil),
+					s.EXPECT().Send(&pb.DownloadBinaryResponse{
+						Part: &pb.DownloadBinaryResponse_Checksum{Checksum: sum[:]},
+					}).Return(nil),
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "missing login test",
+			ctx:     context.Background(),
+			wantErr: true,
+		},
+		{
+			name: "error test",
+			ctx:  ctxWithValue,
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_DownloadBinaryServer) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.BinaryRecord{
+					Prompt: testBinaryRecord.Prompt,
+				}).
+					Return(storage.BinaryRecord{}, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "not found test",
+			ctx:  ctxWithValue,
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_DownloadBinaryServer) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.BinaryRecord{
+					Prompt: testBinaryRecord.Prompt,
+				}).
+					Return(nil, storage.NewStorError(storage.EmptyResult, sql.ErrNoRows))
+			},
+			wantCode: codes.NotFound,
+			wantErr:  true,
+		},
+		{
+			name: "send error test",
+			ctx:  ctxWithValue,
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_DownloadBinaryServer) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.BinaryRecord{
+					Prompt: testBinaryRecord.Prompt,
+				}).
+					Return(testBinaryRecord, nil)
+				s.EXPECT().Send(gomock.Any()).Return(errors.New("error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			s := mocks.NewMockInfoKeeper_DownloadBinaryServer(ctrl)
+			s.EXPECT().Context().Return(tt.ctx).AnyTimes()
+			testGRPC := NewKeeperServer(m, testCfg)
+			if tt.prepare != nil {
+				tt.prepare(m, s)
+			}
+			err := testGRPC.DownloadBinary(&pb.DownloadBinaryRequest{Prompt: testBinaryRecord.Prompt}, s)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.wantCode != codes.OK {
+					assert.Equal(t, tt.wantCode, status.Code(err))
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestListRecords(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	if err != nil {
+		fmt.Println("build token error")
+		return
+	}
+	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+
+	testTimePrs, err := time.Parse(time.RFC3339, testTime)
+	if err != nil {
+		fmt.Println("time parse error")
+	}
+	testInfo := storage.RecordInfo{
+		Type:      storage.BinaryDataRecord,
+		Prompt:    testBinaryRecord.Prompt,
+		TimeStamp: testTimePrs,
+		Size:      int64(len(testBinaryRecord.Data)),
+	}
+	testInfoPb := &pb.RecordInfo{
+		Type:      pb.RecordType_RECORD_TYPE_BINARY,
+		Prompt:    testBinaryRecord.Prompt,
+		TimeStamp: testTime,
+		Size:      int64(len(testBinaryRecord.Data)),
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		in      *pb.ListRecordsRequest
+		prepare func(m *mocks.MockRepositorier)
+		wantRes *pb.ListRecordsResponse
+		wantErr bool
+	}{
+		{
+			name: "ok test",
+			ctx:  ctxWithValue,
+			in: &pb.ListRecordsRequest{
+				Type:          pb.RecordType_RECORD_TYPE_BINARY,
+				ModifiedSince: testTime,
+			},
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().ListRecords(ctxWithValue, testUserLogin, storage.ListFilter{
+					Type:          storage.BinaryDataRecord,
+					ModifiedSince: testTimePrs,
+					Offset:        0,
+					Limit:         DefaultPageSize + 1,
+				}).Return([]storage.RecordInfo{testInfo}, nil)
+			},
+			wantRes: &pb.ListRecordsResponse{
+				Records:       []*pb.RecordInfo{testInfoPb},
+				NextPageToken: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "ok test with next page",
+			ctx:  ctxWithValue,
+			in: &pb.ListRecordsRequest{
+				PageSize:  1,
+				PageToken: encodePageToken(3),
+			},
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().ListRecords(ctxWithValue, testUserLogin, storage.ListFilter{
+					Type:   storage.AnyRecord,
+					Offset: 3,
+					Limit:  2,
+				}).Return([]storage.RecordInfo{testInfo, testInfo}, nil)
+			},
+			wantRes: &pb.ListRecordsResponse{
+				Records:       []*pb.RecordInfo{testInfoPb},
+				NextPageToken: encodePageToken(4),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "missing login test",
+			ctx:     context.Background(),
+			in:      &pb.ListRecordsRequest{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid type test",
+			ctx:     ctxWithValue,
+			in:      &pb.ListRecordsRequest{Type: 10},
+			wantErr: true,
+		},
+		{
+			name:    "invalid time test",
+			ctx:     ctxWithValue,
+			in:      &pb.ListRecordsRequest{ModifiedSince: "2006-01T15:04:05Z"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid page token test",
+			ctx:     ctxWithValue,
+			in:      &pb.ListRecordsRequest{PageToken: "!"},
+			wantErr: true,
+		},
+		{
+			name: "error test",
+			ctx:  ctxWithValue,
+			in:   &pb.ListRecordsRequest{},
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().ListRecords(ctxWithValue, testUserLogin, gomock.Any()).
+					Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			testGRPC := NewKeeperServer(m, testCfg)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			res, err := testGRPC.ListRecords(tt.ctx, tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
+
+func TestAddRecord(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	tp, err := time.Parse(time.RFC3339, testTextPb.TimeStamp)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		ctx      context.Context
+		record   *pb.Record
+		wantCode codes.Code
+	}{
+		{
+			name: "ok card test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().AddRecord(ctxWithValue, testUserLogin, storage.Card{
+					Prompt: testCard.Prompt, Number: testCard.Number, Date: testCard.Date,
+					Code: testCard.Code, Note: testCard.Note, Tags: testCard.Tags,
+					Folder: testCard.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
+			},
+			ctx:      ctxWithValue,
+			record:   &pb.Record{Type: pb.RecordType_RECORD_TYPE_CARD, Payload: &pb.Record_Card{Card: testCardPb}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "ok text test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().AddRecord(ctxWithValue, testUserLogin, storage.TextRecord{
+					Prompt: testTextRecord.Prompt, Data: testTextRecord.Data,
+					Note: testTextRecord.Note, Tags: testTextRecord.Tags,
+					Folder: testTextRecord.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
+			},
+			ctx:      ctxWithValue,
+			record:   &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: testTextPb}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "exists newer test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().AddRecord(ctxWithValue, testUserLogin, storage.LoginPwd{
+					Prompt: testLoginPwd.Prompt, Login: testLoginPwd.Login, Pwd: testLoginPwd.Pwd,
+					Note: testLoginPwd.Note, Tags: testLoginPwd.Tags, Folder: testLoginPwd.Folder,
+					TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.ExistsDataNewerVersion, errors.New("err")))
+			},
+			ctx:      ctxWithValue,
+			record:   &pb.Record{Payload: &pb.Record_LoginPwd{LoginPwd: testLoginPwdPb}},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name: "quota exceeded test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().AddRecord(ctxWithValue, testUserLogin, gomock.Any()).
+					Return(storage.Revision{}, storage.NewStorError(storage.QuotaExceeded, errors.New("err")))
+			},
+			ctx:      ctxWithValue,
+			record:   &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: testTextPb}},
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name:     "missing login test",
+			ctx:      context.Background(),
+			record:   &pb.Record{Payload: &pb.Record_Card{Card: testCardPb}},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "empty payload test",
+			ctx:      ctxWithValue,
+			record:   &pb.Record{Type: pb.RecordType_RECORD_TYPE_CARD},
+			wantCode: codes.DataLoss,
+		},
+		{
+			name:     "type mismatch test",
+			ctx:      ctxWithValue,
+			record:   &pb.Record{Type: pb.RecordType_RECORD_TYPE_TEXT, Payload: &pb.Record_Card{Card: testCardPb}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "invalid time stamp test",
+			ctx:  ctxWithValue,
+			record: &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: &pb.UserTextRecord{
+				Prompt:    testTextRecord.Prompt,
+				TimeStamp: "2006-01T15:04:05Z",
+			}}},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			testGRPC := NewKeeperServer(m, testCfg)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			res, err := testGRPC.AddRecord(tt.ctx, &pb.AddRecordRequest{Record: tt.record})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, testRevisionPb, res.GetRevision())
+			}
+		})
+	}
+}
+
+func TestGetRecord(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		ctx      context.Context
+		key      *pb.RecordKey
+		wantRes  *pb.GetRecordResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "ok login test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.LoginPwd{
+					Prompt: testLoginPwd.Prompt, Login: testLoginPwd.Login,
+				}).
+					Return(testLoginPwd, nil)
+			},
+			ctx: ctxWithValue,
+			key: &pb.RecordKey{
+				Type:   pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+				Prompt: testLoginPwd.Prompt,
+				Key:    testLoginPwd.Login,
+			},
+			wantRes: &pb.GetRecordResponse{Record: &pb.Record{
+				Type:    pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+				Payload: &pb.Record_LoginPwd{LoginPwd: testLoginPwdPb},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "ok binary test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.BinaryRecord{
+					Prompt: testBinaryRecord.Prompt,
+				}).
+					Return(testBinaryRecord, nil)
+			},
+			ctx: ctxWithValue,
+			key: &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: testBinaryRecord.Prompt},
+			wantRes: &pb.GetRecordResponse{Record: &pb.Record{
+				Type:    pb.RecordType_RECORD_TYPE_BINARY,
+				Payload: &pb.Record_BinaryRecord{BinaryRecord: testBinaryPb},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "ok otp test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.Otp{
+					Issuer: testOtp.Issuer, Account: testOtp.Account,
+				}).
+					Return(testOtp, nil)
+			},
+			ctx: ctxWithValue,
+			key: &pb.RecordKey{
+				Type:   pb.RecordType_RECORD_TYPE_OTP,
+				Prompt: testOtp.Issuer,
+				Key:    testOtp.Account,
+			},
+			wantRes: &pb.GetRecordResponse{Record: &pb.Record{
+				Type:    pb.RecordType_RECORD_TYPE_OTP,
+				Payload: &pb.Record_Otp{Otp: testOtpPb},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "ok custom record test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.CustomRecord{
+					Prompt: testCustomRecord.Prompt,
+				}).
+					Return(testCustomRecord, nil)
+			},
+			ctx: ctxWithValue,
+			key: &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CUSTOM, Prompt: testCustomRecord.Prompt},
+			wantRes: &pb.GetRecordResponse{Record: &pb.Record{
+				Type:    pb.RecordType_RECORD_TYPE_CUSTOM,
+				Payload: &pb.Record_CustomRecord{CustomRecord: testCustomRecordPb},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "ok ssh key test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.SshKey{Prompt: testSshKey.Prompt}).
+					Return(testSshKey, nil)
+			},
+			ctx: ctxWithValue,
+			key: &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_SSH_KEY, Prompt: testSshKey.Prompt},
+			wantRes: &pb.GetRecordResponse{Record: &pb.Record{
+				Type:    pb.RecordType_RECORD_TYPE_SSH_KEY,
+				Payload: &pb.Record_SshKey{SshKey: testSshKeyPb},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "error test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.Card{Number: testCard.Number}).
+					Return(storage.Card{}, errors.New("error"))
+			},
+			ctx:      ctxWithValue,
+			key:      &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number},
+			wantCode: codes.Internal,
+		},
+		{
+			name: "not found test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetRecord(ctxWithValue, testUserLogin, storage.Card{Number: testCard.Number}).
+					Return(nil, storage.NewStorError(storage.EmptyResult, sql.ErrNoRows))
+			},
+			ctx:      ctxWithValue,
+			key:      &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "unknown type test",
+			ctx:      ctxWithValue,
+			key:      &pb.RecordKey{Prompt: testTextRecord.Prompt},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "missing login test",
+			ctx:      context.Background(),
+			key:      &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			testGRPC := NewKeeperServer(m, testCfg)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			res, err := testGRPC.GetRecord(tt.ctx, &pb.GetRecordRequest{Key: tt.key})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
+
+func TestForceUpdateRecord(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	tp, err := time.Parse(time.RFC3339, testBinaryPb.TimeStamp)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		record   *pb.Record
+		wantCode codes.Code
+	}{
+		{
+			name: "ok binary test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().ForceUpdateRecord(ctxWithValue, testUserLogin, storage.BinaryRecord{
+					Prompt: testBinaryRecord.Prompt, Data: testBinaryRecord.Data,
+					Note: testBinaryRecord.Note, Tags: testBinaryRecord.Tags,
+					Folder: testBinaryRecord.Folder, TimeStamp: tp,
+				}).Return(testRevision, nil)
+			},
+			record:   &pb.Record{Payload: &pb.Record_BinaryRecord{BinaryRecord: testBinaryPb}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "empty values test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().ForceUpdateRecord(ctxWithValue, testUserLogin, storage.Card{
+					Prompt: testCard.Prompt, Number: testCard.Number, Date: testCard.Date,
+					Code: testCard.Code, Note: testCard.Note, Tags: testCard.Tags,
+					Folder: testCard.Folder, TimeStamp: tp,
+				}).
+					Return(storage.Revision{}, storage.NewStorError(storage.EmptyValues, errors.New("err")))
+			},
+			record:   &pb.Record{Payload: &pb.Record_Card{Card: testCardPb}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "error time stamp test",
+			record: &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: &pb.UserTextRecord{
+				Prompt:    testTextRecord.Prompt,
+				TimeStamp: "2006-01T15:04:05Z",
+			}}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "empty payload test",
+			record:   &pb.Record{},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			testGRPC := NewKeeperServer(m, testCfg)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			_, err := testGRPC.ForceUpdateRecord(ctxWithValue, &pb.ForceUpdateRecordRequest{Record: tt.record})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		ctx      context.Context
+		key      *pb.RecordKey
+		wantRes  *pb.ListVersionsResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "ok card test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().ListVersions(ctxWithValue, testUserLogin, storage.Card{Number: testCard.Number}).
+					Return([]storage.Version{{ID: 3, Record: testCard}}, nil)
+			},
+			ctx: ctxWithValue,
+			key: &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number},
+			wantRes: &pb.ListVersionsResponse{Versions: []*pb.RecordVersion{{
+				Version: 3,
+				Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_CARD,
+					Payload: &pb.Record_Card{Card: testCardPb},
+				},
+			}}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "ok otp test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().ListVersions(ctxWithValue, testUserLogin, storage.Otp{
+					Issuer: testOtp.Issuer, Account: testOtp.Account,
+				}).
+					Return([]storage.Version{{ID: 1, Record: testOtp}}, nil)
+			},
+			ctx: ctxWithValue,
+			key: &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_OTP, Prompt: testOtp.Issuer, Key: testOtp.Account},
+			wantRes: &pb.ListVersionsResponse{Versions: []*pb.RecordVersion{{
+				Version: 1,
+				Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_OTP,
+					Payload: &pb.Record_Otp{Otp: testOtpPb},
+				},
+			}}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "error test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().ListVersions(ctxWithValue, testUserLogin, storage.TextRecord{Prompt: testTextRecord.Prompt}).
+					Return(nil, errors.New("error"))
+			},
+			ctx:      ctxWithValue,
+			key:      &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "unknown type test",
+			ctx:      ctxWithValue,
+			key:      &pb.RecordKey{Prompt: testTextRecord.Prompt},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "missing login test",
+			ctx:      context.Background(),
+			key:      &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			testGRPC := NewKeeperServer(m, testCfg)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			res, err := testGRPC.ListVersions(tt.ctx, &pb.ListVersionsRequest{Key: tt.key})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
+
+func TestRestoreVersion(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		ctx      context.Context
+		key      *pb.RecordKey
+		wantRes  *pb.RestoreVersionResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "ok login test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().RestoreVersion(ctxWithValue, testUserLogin,
+					storage.LoginPwd{Prompt: testLoginPwd.Prompt, Login: testLoginPwd.Login}, int64(2), gomock.Any()).
+					Return(testLoginPwd, nil)
+			},
+			ctx: ctxWithValue,
+			key: &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_LOGIN_PWD, Prompt: testLoginPwd.Prompt, Key: testLoginPwd.Login},
+			wantRes: &pb.RestoreVersionResponse{Record: &pb.Record{
+				Type:    pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+				Payload: &pb.Record_LoginPwd{LoginPwd: testLoginPwdPb},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "not found test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().RestoreVersion(ctxWithValue, testUserLogin,
+					storage.SshKey{Prompt: testSshKey.Prompt}, int64(2), gomock.Any()).
+					Return(nil, storage.NewStorError(storage.EmptyResult, errors.New("error")))
+			},
+			ctx:      ctxWithValue,
+			key:      &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_SSH_KEY, Prompt: testSshKey.Prompt},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "unknown type test",
+			ctx:      ctxWithValue,
+			key:      &pb.RecordKey{Prompt: testTextRecord.Prompt},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "missing login test",
+			ctx:      context.Background(),
+			key:      &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			testGRPC := NewKeeperServer(m, testCfg)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			res, err := testGRPC.RestoreVersion(tt.ctx, &pb.RestoreVersionRequest{Key: tt.key, Version: 2})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
+
+func TestWatchChanges(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctxWithValue, cancel := context.WithCancel(context.WithValue(context.Background(), authorizer.UserContextKey, userToken))
+	defer cancel()
+	testChange := storage.Change{
+		Type: storage.OtpRecord, Prompt: testOtp.Issuer, Key: testOtp.Account,
+		TimeStamp: testOtp.TimeStamp,
+	}
+	testEvent := &pb.ChangeEvent{
+		Key: &pb.RecordKey{
+			Type:   pb.RecordType_RECORD_TYPE_OTP,
+			Prompt: testOtp.Issuer,
+			Key:    testOtp.Account,
+		},
+		TimeStamp: testOtp.TimeStamp.Format(time.RFC3339),
+	}
+	changes := func() <-chan storage.Change {
+		ch := make(chan storage.Change, 1)
+		ch <- testChange
+		close(ch)
+		return ch
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		prepare func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_WatchChangesServer)
+		wantErr bool
+	}{
+		{
+			name: "ok test",
+			ctx:  ctxWithValue,
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_WatchChangesServer) {
+				m.EXPECT().WatchChanges(ctxWithValue, testUserLogin).Return(changes())
+				s.EXPECT().Send(testEvent).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "missing login test",
+			ctx:     context.Background(),
+			wantErr: true,
+		},
+		{
+			name: "send error test",
+			ctx:  ctxWithValue,
+			prepare: func(m *mocks.MockRepositorier, s *mocks.MockInfoKeeper_WatchChangesServer) {
+				m.EXPECT().WatchChanges(ctxWithValue, testUserLogin).Return(changes())
+				s.EXPECT().Send(testEvent).Return(errors.New("error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().AddAuditEvent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			s := mocks.NewMockInfoKeeper_WatchChangesServer(ctrl)
+			s.EXPECT().Context().Return(tt.ctx).AnyTimes()
+			if tt.prepare != nil {
+				tt.prepare(m, s)
+			}
+			testGRPC := NewKeeperServer(m, testCfg)
+			err := testGRPC.WatchChanges(&pb.WatchChangesRequest{}, s)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// roles - соответствие ролей участников в протоколе и в репозитории.
+var roles = map[pb.MemberRole]storage.Role{
+	pb.MemberRole_MEMBER_ROLE_OWNER:  storage.RoleOwner,
+	pb.MemberRole_MEMBER_ROLE_EDITOR: storage.RoleEditor,
+	pb.MemberRole_MEMBER_ROLE_VIEWER: storage.RoleViewer,
+}
+
+// roleToPb преобразует роль участника из репозитория в протокол.
+func roleToPb(role storage.Role) pb.MemberRole {
+	for k, v := range roles {
+		if v == role {
+			return k
+		}
+	}
+	return pb.MemberRole_MEMBER_ROLE_UNSPECIFIED
+}
+
+// collectionToPb преобразует коллекцию из репозитория в протокол.
+func collectionToPb(c storage.Collection) *pb.Collection {
+	return &pb.Collection{
+		Id:           c.ID,
+		OrgId:        c.OrgID,
+		Organization: c.Organization,
+		Name:         c.Name,
+		Role:         roleToPb(c.Role),
+		WrappedKey:   c.WrappedKey,
+	}
+}
+
+// SetUserKeys реализует сохранение пары ключей пользователя для общих коллекций.
+// Закрытый ключ передается зашифрованным ключом пользователя.
+func (ks *KeeperGRPCServer) SetUserKeys(ctx context.Context, in *pb.SetUserKeysRequest) (*pb.SetUserKeysResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(in.GetKeys().GetPublicKey()) == 0 || len(in.GetKeys().GetPrivateKey()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty keys")
+	}
+
+	err = ks.stor.SetUserKeys(ctx, userLogin, storage.UserKeys{
+		PublicKey:  in.GetKeys().GetPublicKey(),
+		PrivateKey: in.GetKeys().GetPrivateKey(),
+	})
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.SetUserKeysResponse{}, nil
+}
+
+// GetUserKeys реализует получение пары ключей пользователя для общих коллекций.
+func (ks *KeeperGRPCServer) GetUserKeys(ctx context.Context, in *pb.GetUserKeysRequest) (*pb.GetUserKeysResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := ks.stor.GetUserKeys(ctx, userLogin)
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.GetUserKeysResponse{
+		Keys: &pb.UserKeys{PublicKey: keys.PublicKey, PrivateKey: keys.PrivateKey},
+	}, nil
+}
+
+// GetPublicKey реализует получение открытого ключа другого пользователя.
+func (ks *KeeperGRPCServer) GetPublicKey(ctx context.Context, in *pb.GetPublicKeyRequest) (*pb.GetPublicKeyResponse, error) {
+	_, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ks.stor.GetPublicKey(ctx, in.GetLogin())
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.GetPublicKeyResponse{PublicKey: key}, nil
+}
+
+// CreateOrganization реализует создание организации, пользователь становится ее владельцем.
+func (ks *KeeperGRPCServer) CreateOrganization(ctx context.Context, in *pb.CreateOrganizationRequest) (*pb.CreateOrganizationResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty name")
+	}
+
+	org, err := ks.stor.CreateOrganization(ctx, userLogin, in.GetName())
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.CreateOrganizationResponse{Organization: &pb.Organization{
+		Id:   org.ID,
+		Name: org.Name,
+		Role: roleToPb(org.Role),
+	}}, nil
+}
+
+// CreateCollection реализует создание коллекции в организации.
+func (ks *KeeperGRPCServer) CreateCollection(ctx context.Context, in *pb.CreateCollectionRequest) (*pb.CreateCollectionResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.GetName() == "" || len(in.GetWrappedKey()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty name or key")
+	}
+
+	c, err := ks.stor.CreateCollection(ctx, userLogin, in.GetOrgId(), in.GetName(), in.GetWrappedKey())
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.CreateCollectionResponse{Collection: collectionToPb(c)}, nil
+}
+
+// ListCollections реализует получение коллекций, в которых участвует пользователь.
+func (ks *KeeperGRPCServer) ListCollections(ctx context.Context, in *pb.ListCollectionsRequest) (*pb.ListCollectionsResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := ks.stor.ListCollections(ctx, userLogin)
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	res := &pb.ListCollectionsResponse{Collections: make([]*pb.Collection, 0, len(collections))}
+	for _, c := range collections {
+		res.Collections = append(res.Collections, collectionToPb(c))
+	}
+
+	return res, nil
+}
+
+// AddCollectionMember реализует приглашение участника в коллекцию.
+// Ключ коллекции передается зашифрованным открытым ключом участника.
+func (ks *KeeperGRPCServer) AddCollectionMember(ctx context.Context, in *pb.AddCollectionMemberRequest) (*pb.AddCollectionMemberResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	role, ok := roles[in.GetRole()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown role")
+	}
+	if in.GetLogin() == "" || len(in.GetWrappedKey()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty login or key")
+	}
+
+	err = ks.stor.AddCollectionMember(ctx, userLogin, in.GetCollectionId(), in.GetLogin(), role, in.GetWrappedKey())
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	return &pb.AddCollectionMemberResponse{}, nil
+}
+
+// MoveRecordToCollection реализует перенос личной записи в коллекцию.
+// Запись передается зашифрованной ключом коллекции, личная запись удаляется вместе с историей.
+func (ks *KeeperGRPCServer) MoveRecordToCollection(ctx context.Context, in *pb.MoveRecordToCollectionRequest) (*pb.MoveRecordToCollectionResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, ok := recordCodecs[in.GetSource().GetType()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown record type")
+	}
+	r := in.GetRecord()
+	if r.GetType() != in.GetSource().GetType() || len(r.GetData()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid shared record")
+	}
+	timeStamp, err := time.Parse(time.RFC3339, r.GetTimeStamp())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = ks.stor.MoveRecordToCollection(ctx, userLogin, in.GetCollectionId(), codec.key(in.GetSource()),
+		storage.SharedRecord{
+			Type:      storage.RecordType(r.GetType()),
+			Prompt:    r.GetPrompt(),
+			Key:       r.GetKey(),
+			Data:      r.GetData(),
+			TimeStamp: timeStamp,
+		})
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+	ks.audit(ctx, userLogin, storage.AuditMove, in.GetSource())
+
+	return &pb.MoveRecordToCollectionResponse{}, nil
+}
+
+// ListCollectionRecords реализует получение записей коллекции.
+func (ks *KeeperGRPCServer) ListCollectionRecords(ctx context.Context, in *pb.ListCollectionRecordsRequest) (*pb.ListCollectionRecordsResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := ks.stor.ListCollectionRecords(ctx, userLogin, in.GetCollectionId())
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	res := &pb.ListCollectionRecordsResponse{Records: make([]*pb.SharedRecord, 0, len(records))}
+	for _, r := range records {
+		res.Records = append(res.Records, &pb.SharedRecord{
+			Type:      pb.RecordType(r.Type),
+			Prompt:    r.Prompt,
+			Key:       r.Key,
+			Data:      r.Data,
+			TimeStamp: r.TimeStamp.Format(time.RFC3339),
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,314 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/mocks"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+func newOrgTestServer(t *testing.T, prepare func(m *mocks.MockRepositorier)) *KeeperGRPCServer {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	m := mocks.NewMockRepositorier(ctrl)
+	m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+	if prepare != nil {
+		prepare(m)
+	}
+	return NewKeeperServer(m, testCfg)
+}
+
+func TestUserKeys(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	keys := storage.UserKeys{PublicKey: []byte("public"), PrivateKey: []byte("private")}
+
+	srv := newOrgTestServer(t, func(m *mocks.MockRepositorier) {
+		m.EXPECT().SetUserKeys(ctx, testUserLogin, keys).Return(nil)
+		m.EXPECT().GetUserKeys(ctx, testUserLogin).Return(keys, nil)
+		m.EXPECT().GetPublicKey(ctx, "member").Return(nil, storage.NewStorError(storage.EmptyResult, errors.New("error")))
+	})
+
+	_, err = srv.SetUserKeys(ctx, &pb.SetUserKeysRequest{Keys: &pb.UserKeys{PublicKey: keys.PublicKey, PrivateKey: keys.PrivateKey}})
+	assert.NoError(t, err)
+	_, err = srv.SetUserKeys(ctx, &pb.SetUserKeysRequest{Keys: &pb.UserKeys{PublicKey: keys.PublicKey}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	res, err := srv.GetUserKeys(ctx, &pb.GetUserKeysRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, keys.PrivateKey, res.GetKeys().GetPrivateKey())
+
+	_, err = srv.GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: "member"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = srv.GetUserKeys(context.Background(), &pb.GetUserKeysRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestCreateOrganization(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		req      *pb.CreateOrganizationRequest
+		wantRes  *pb.CreateOrganizationResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "ok test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().CreateOrganization(ctx, testUserLogin, "team").
+					Return(storage.Organization{ID: 3, Name: "team", Role: storage.RoleOwner}, nil)
+			},
+			req: &pb.CreateOrganizationRequest{Name: "team"},
+			wantRes: &pb.CreateOrganizationResponse{Organization: &pb.Organization{
+				Id: 3, Name: "team", Role: pb.MemberRole_MEMBER_ROLE_OWNER,
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "already exists test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().CreateOrganization(ctx, testUserLogin, "team").
+					Return(storage.Organization{}, storage.NewStorError(storage.AlreadyExists, errors.New("error")))
+			},
+			req:      &pb.CreateOrganizationRequest{Name: "team"},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "empty name test",
+			req:      &pb.CreateOrganizationRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newOrgTestServer(t, tt.prepare)
+			res, err := srv.CreateOrganization(ctx, tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
+
+func TestCreateCollection(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	wrapped := []byte("wrapped")
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		req      *pb.CreateCollectionRequest
+		wantRes  *pb.CreateCollectionResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "ok test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().CreateCollection(ctx, testUserLogin, int64(3), "ops", wrapped).
+					Return(storage.Collection{ID: 5, OrgID: 3, Organization: "team", Name: "ops",
+						Role: storage.RoleOwner, WrappedKey: wrapped}, nil)
+			},
+			req: &pb.CreateCollectionRequest{OrgId: 3, Name: "ops", WrappedKey: wrapped},
+			wantRes: &pb.CreateCollectionResponse{Collection: &pb.Collection{
+				Id: 5, OrgId: 3, Organization: "team", Name: "ops",
+				Role: pb.MemberRole_MEMBER_ROLE_OWNER, WrappedKey: wrapped,
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name: "forbidden test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().CreateCollection(ctx, testUserLogin, int64(3), "ops", wrapped).
+					Return(storage.Collection{}, storage.NewStorError(storage.Forbidden, errors.New("error")))
+			},
+			req:      &pb.CreateCollectionRequest{OrgId: 3, Name: "ops", WrappedKey: wrapped},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "empty key test",
+			req:      &pb.CreateCollectionRequest{OrgId: 3, Name: "ops"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newOrgTestServer(t, tt.prepare)
+			res, err := srv.CreateCollection(ctx, tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
+
+func TestListCollections(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+
+	srv := newOrgTestServer(t, func(m *mocks.MockRepositorier) {
+		m.EXPECT().ListCollections(ctx, testUserLogin).
+			Return([]storage.Collection{{ID: 5, OrgID: 3, Organization: "team", Name: "ops", Role: storage.RoleViewer}}, nil)
+	})
+	res, err := srv.ListCollections(ctx, &pb.ListCollectionsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.Collection{{
+		Id: 5, OrgId: 3, Organization: "team", Name: "ops", Role: pb.MemberRole_MEMBER_ROLE_VIEWER,
+	}}, res.GetCollections())
+}
+
+func TestAddCollectionMember(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	wrapped := []byte("wrapped")
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		req      *pb.AddCollectionMemberRequest
+		wantCode codes.Code
+	}{
+		{
+			name: "ok test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().AddCollectionMember(ctx, testUserLogin, int64(5), "member", storage.RoleEditor, wrapped).
+					Return(nil)
+			},
+			req: &pb.AddCollectionMemberRequest{CollectionId: 5, Login: "member",
+				Role: pb.MemberRole_MEMBER_ROLE_EDITOR, WrappedKey: wrapped},
+			wantCode: codes.OK,
+		},
+		{
+			name: "not owner test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().AddCollectionMember(ctx, testUserLogin, int64(5), "member", storage.RoleViewer, wrapped).
+					Return(storage.NewStorError(storage.Forbidden, errors.New("error")))
+			},
+			req: &pb.AddCollectionMemberRequest{CollectionId: 5, Login: "member",
+				Role: pb.MemberRole_MEMBER_ROLE_VIEWER, WrappedKey: wrapped},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "unknown role test",
+			req:      &pb.AddCollectionMemberRequest{CollectionId: 5, Login: "member", WrappedKey: wrapped},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newOrgTestServer(t, tt.prepare)
+			_, err := srv.AddCollectionMember(ctx, tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestMoveRecordToCollection(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	testTimePrs, err := time.Parse(time.RFC3339, testTime)
+	require.NoError(t, err)
+
+	source := &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number}
+	shared := &pb.SharedRecord{Type: pb.RecordType_RECORD_TYPE_CARD, Prompt: []byte("p"), Key: []byte("k"),
+		Data: []byte("d"), TimeStamp: testTime}
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		req      *pb.MoveRecordToCollectionRequest
+		wantCode codes.Code
+	}{
+		{
+			name: "ok test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().MoveRecordToCollection(ctx, testUserLogin, int64(5), storage.Card{Number: testCard.Number},
+					storage.SharedRecord{Type: storage.CardRecord, Prompt: []byte("p"), Key: []byte("k"),
+						Data: []byte("d"), TimeStamp: testTimePrs}).
+					Return(nil)
+				m.EXPECT().AddAuditEvent(ctx, testUserLogin, gomock.Any()).Return(nil)
+			},
+			req:      &pb.MoveRecordToCollectionRequest{CollectionId: 5, Source: source, Record: shared},
+			wantCode: codes.OK,
+		},
+		{
+			name: "not found test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().MoveRecordToCollection(ctx, testUserLogin, int64(5), gomock.Any(), gomock.Any()).
+					Return(storage.NewStorError(storage.EmptyResult, errors.New("error")))
+			},
+			req:      &pb.MoveRecordToCollectionRequest{CollectionId: 5, Source: source, Record: shared},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "type mismatch test",
+			req: &pb.MoveRecordToCollectionRequest{CollectionId: 5, Source: source,
+				Record: &pb.SharedRecord{Type: pb.RecordType_RECORD_TYPE_TEXT, Data: []byte("d"), TimeStamp: testTime}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown type test",
+			req:      &pb.MoveRecordToCollectionRequest{CollectionId: 5, Source: &pb.RecordKey{}, Record: shared},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newOrgTestServer(t, tt.prepare)
+			_, err := srv.MoveRecordToCollection(ctx, tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestListCollectionRecords(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	testTimePrs, err := time.Parse(time.RFC3339, testTime)
+	require.NoError(t, err)
+
+	srv := newOrgTestServer(t, func(m *mocks.MockRepositorier) {
+		m.EXPECT().ListCollectionRecords(ctx, testUserLogin, int64(5)).
+			Return([]storage.SharedRecord{{Type: storage.CardRecord, Prompt: []byte("p"), Key: []byte("k"),
+				Data: []byte("d"), TimeStamp: testTimePrs}}, nil)
+		m.EXPECT().ListCollectionRecords(ctx, testUserLogin, int64(6)).
+			Return(nil, storage.NewStorError(storage.Forbidden, errors.New("error")))
+	})
+
+	res, err := srv.ListCollectionRecords(ctx, &pb.ListCollectionRecordsRequest{CollectionId: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.SharedRecord{{Type: pb.RecordType_RECORD_TYPE_CARD, Prompt: []byte("p"), Key: []byte("k"),
+		Data: []byte("d"), TimeStamp: testTime}}, res.GetRecords())
+
+	_, err = srv.ListCollectionRecords(ctx, &pb.ListCollectionRecordsRequest{CollectionId: 6})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,150 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// recordSize возвращает размер записи - сумму длин всех ее полей, кроме времени изменения.
+// Размер совпадает с размером записи, который учитывает хранилище.
+func recordSize(r *pb.Record) int64 {
+	var size int64
+	msg := r.ProtoReflect()
+	payload := msg.WhichOneof(msg.Descriptor().Oneofs().ByName("payload"))
+	if payload == nil {
+		return 0
+	}
+	msg.Get(payload).Message().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() == protoreflect.BytesKind {
+			size += int64(len(v.Bytes()))
+		}
+		return true
+	})
+	return size
+}
+
+// quotaChecker проверяет квоты пользователя перед сохранением записей, чтобы отклонить
+// их до получения данных. Окончательно квоты проверяет хранилище при записи.
+// Объем данных пользователя загружается из хранилища при первой проверке
+// и учитывает все записи, прошедшие проверку.
+type quotaChecker struct {
+	ks        *KeeperGRPCServer
+	userLogin string
+	usage     map[pb.RecordType]storage.Usage
+	bytes     int64
+}
+
+// newQuotaChecker создает объект для проверки квот пользователя.
+func (ks *KeeperGRPCServer) newQuotaChecker(userLogin string) *quotaChecker {
+	return &quotaChecker{ks: ks, userLogin: userLogin}
+}
+
+// load получает объем данных пользователя из хранилища.
+func (q *quotaChecker) load(ctx context.Context) error {
+	if q.usage != nil {
+		return nil
+	}
+	usage, err := q.ks.stor.GetUsage(ctx, q.userLogin)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	q.usage = make(map[pb.RecordType]storage.Usage, len(usage))
+	for _, u := range usage {
+		q.usage[pb.RecordType(u.Type)] = u
+		q.bytes += u.Bytes
+	}
+	return nil
+}
+
+// check проверяет, что сохранение записи r не превысит квоты пользователя.
+// Замена существующей записи не увеличивает количество записей,
+// а ее размер учитывается как разница между новой и текущей версией.
+func (q *quotaChecker) check(ctx context.Context, r *pb.Record) error {
+	return q.checkPending(ctx, r, 0)
+}
+
+// checkPending проверяет квоты для записи r, бинарные данные которой размером pending
+// еще не получены и не входят в r.
+func (q *quotaChecker) checkPending(ctx context.Context, r *pb.Record, pending int64) error {
+	cfg := q.ks.cfg
+	t := payloadType(r)
+	if t == pb.RecordType_RECORD_TYPE_BINARY && cfg.QuotaBinarySize > 0 &&
+		int64(len(r.GetBinaryRecord().GetData()))+pending > cfg.QuotaBinarySize {
+		return status.Errorf(codes.ResourceExhausted, "binary data exceeds %d bytes", cfg.QuotaBinarySize)
+	}
+	if cfg.QuotaBytes <= 0 && cfg.QuotaRecords <= 0 {
+		return nil
+	}
+
+	err := q.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	size := recordSize(r) + pending
+	isNew := false
+	current, err := q.ks.stor.GetRecordSize(ctx, q.userLogin, recordCodecs[t].key(recordKeyOf(r)))
+	var storErr *storage.StorErr
+	switch {
+	case err == nil:
+		size -= current
+	case errors.As(err, &storErr) && storErr.ErrType == storage.EmptyResult:
+		isNew = true
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	u := q.usage[t]
+	if isNew && cfg.QuotaRecords > 0 && u.Records >= cfg.QuotaRecords {
+		return status.Errorf(codes.ResourceExhausted, "number of records exceeds %d", cfg.QuotaRecords)
+	}
+	if size > 0 && cfg.QuotaBytes > 0 && q.bytes+size > cfg.QuotaBytes {
+		return status.Errorf(codes.ResourceExhausted, "total size of records exceeds %d bytes", cfg.QuotaBytes)
+	}
+
+	u.Type = storage.RecordType(t)
+	u.Bytes += size
+	if isNew {
+		u.Records++
+	}
+	q.usage[t] = u
+	q.bytes += size
+	return nil
+}
+
+// GetUsage реализует получение объема данных пользователя и его квот.
+func (ks *KeeperGRPCServer) GetUsage(ctx context.Context, in *pb.GetUsageRequest) (*pb.GetUsageResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := ks.stor.GetUsage(ctx, userLogin)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &pb.GetUsageResponse{
+		Usage:           make([]*pb.RecordUsage, 0, len(usage)),
+		QuotaBytes:      ks.cfg.QuotaBytes,
+		QuotaRecords:    ks.cfg.QuotaRecords,
+		QuotaBinarySize: ks.cfg.QuotaBinarySize,
+	}
+	for _, u := range usage {
+		res.Usage = append(res.Usage, &pb.RecordUsage{
+			Type:    pb.RecordType(u.Type),
+			Records: u.Records,
+			Bytes:   u.Bytes,
+		})
+		res.TotalBytes += u.Bytes
+	}
+
+	return res, nil
+}
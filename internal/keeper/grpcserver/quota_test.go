@@ -0,0 +1,234 @@
+package grpcserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/config"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/mocks"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+func TestRecordSize(t *testing.T) {
+	r := &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: &pb.UserTextRecord{
+		Prompt:    []byte("prompt"),
+		Data:      []byte("data"),
+		Note:      []byte("note"),
+		TimeStamp: testTime,
+	}}}
+	assert.Equal(t, int64(14), recordSize(r))
+	assert.Equal(t, int64(0), recordSize(&pb.Record{}))
+}
+
+func TestQuotaCheck(t *testing.T) {
+	textRecord := &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: &pb.UserTextRecord{
+		Prompt: testTextRecord.Prompt,
+		Data:   make([]byte, 100),
+	}}}
+	textKey := testTextRecord.Prompt
+
+	tests := []struct {
+		name     string
+		cfg      config.Flags
+		prepare  func(m *mocks.MockRepositorier)
+		r        *pb.Record
+		wantCode codes.Code
+	}{
+		{
+			name:     "no quotas test",
+			cfg:      config.Flags{},
+			r:        textRecord,
+			wantCode: codes.OK,
+		},
+		{
+			name: "binary size test",
+			cfg:  config.Flags{QuotaBinarySize: 1},
+			r: &pb.Record{Payload: &pb.Record_BinaryRecord{BinaryRecord: &pb.UserBinaryRecord{
+				Prompt: testBinaryRecord.Prompt,
+				Data:   []byte{1, 2},
+			}}},
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name: "new record within quotas test",
+			cfg:  config.Flags{QuotaBytes: 1000, QuotaRecords: 2},
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetUsage(gomock.Any(), testUserLogin).
+					Return([]storage.Usage{{Type: storage.TextDataRecord, Records: 1, Bytes: 500}}, nil)
+				m.EXPECT().GetRecordSize(gomock.Any(), testUserLogin, storage.TextRecord{Prompt: textKey}).
+					Return(int64(0), storage.NewStorError(storage.EmptyResult, sql.ErrNoRows))
+			},
+			r:        textRecord,
+			wantCode: codes.OK,
+		},
+		{
+			name: "records quota test",
+			cfg:  config.Flags{QuotaRecords: 1},
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetUsage(gomock.Any(), testUserLogin).
+					Return([]storage.Usage{{Type: storage.TextDataRecord, Records: 1, Bytes: 500}}, nil)
+				m.EXPECT().GetRecordSize(gomock.Any(), testUserLogin, storage.TextRecord{Prompt: textKey}).
+					Return(int64(0), storage.NewStorError(storage.EmptyResult, sql.ErrNoRows))
+			},
+			r:        textRecord,
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name: "replace record with full records quota test",
+			cfg:  config.Flags{QuotaRecords: 1},
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetUsage(gomock.Any(), testUserLogin).
+					Return([]storage.Usage{{Type: storage.TextDataRecord, Records: 1, Bytes: 500}}, nil)
+				m.EXPECT().GetRecordSize(gomock.Any(), testUserLogin, storage.TextRecord{Prompt: textKey}).
+					Return(int64(500), nil)
+			},
+			r:        textRecord,
+			wantCode: codes.OK,
+		},
+		{
+			name: "bytes quota test",
+			cfg:  config.Flags{QuotaBytes: 550},
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetUsage(gomock.Any(), testUserLogin).
+					Return([]storage.Usage{{Type: storage.CardRecord, Records: 3, Bytes: 500}}, nil)
+				m.EXPECT().GetRecordSize(gomock.Any(), testUserLogin, storage.TextRecord{Prompt: textKey}).
+					Return(int64(0), storage.NewStorError(storage.EmptyResult, sql.ErrNoRows))
+			},
+			r:        textRecord,
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name: "usage error test",
+			cfg:  config.Flags{QuotaBytes: 550},
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetUsage(gomock.Any(), testUserLogin).Return(nil, errors.New("error"))
+			},
+			r:        textRecord,
+			wantCode: codes.Internal,
+		},
+		{
+			name: "get record size error test",
+			cfg:  config.Flags{QuotaBytes: 550},
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetUsage(gomock.Any(), testUserLogin).Return(nil, nil)
+				m.EXPECT().GetRecordSize(gomock.Any(), testUserLogin, storage.TextRecord{Prompt: textKey}).
+					Return(int64(0), errors.New("error"))
+			},
+			r:        textRecord,
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			testGRPC := NewKeeperServer(m, tt.cfg)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			err := testGRPC.newQuotaChecker(testUserLogin).check(context.Background(), tt.r)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestQuotaCheckAccumulates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockRepositorier(ctrl)
+	m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+	m.EXPECT().GetUsage(gomock.Any(), testUserLogin).Return(nil, nil)
+	m.EXPECT().GetRecordSize(gomock.Any(), testUserLogin, gomock.Any()).
+		Return(int64(0), storage.NewStorError(storage.EmptyResult, sql.ErrNoRows)).Times(2)
+	testGRPC := NewKeeperServer(m, config.Flags{QuotaRecords: 1})
+
+	q := testGRPC.newQuotaChecker(testUserLogin)
+	err := q.check(context.Background(), &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: &pb.UserTextRecord{Prompt: []byte("a")}}})
+	require.NoError(t, err)
+	err = q.check(context.Background(), &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: &pb.UserTextRecord{Prompt: []byte("b")}}})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestGetUsage(t *testing.T) {
+	userToken, err := authorizer.BuildToken(testUserLogin, testUserPwd, testCfg.SecretKey)
+	require.NoError(t, err)
+	ctxWithValue := context.WithValue(context.Background(), authorizer.UserContextKey, userToken)
+	cfg := config.Flags{SecretKey: testCfg.SecretKey, QuotaBytes: 1000, QuotaRecords: 10, QuotaBinarySize: 100}
+
+	tests := []struct {
+		name     string
+		prepare  func(m *mocks.MockRepositorier)
+		ctx      context.Context
+		wantRes  *pb.GetUsageResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "ok test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetUsage(ctxWithValue, testUserLogin).Return([]storage.Usage{
+					{Type: storage.CardRecord, Records: 2, Bytes: 120},
+					{Type: storage.BinaryDataRecord, Records: 1, Bytes: 80},
+				}, nil)
+			},
+			ctx: ctxWithValue,
+			wantRes: &pb.GetUsageResponse{
+				Usage: []*pb.RecordUsage{
+					{Type: pb.RecordType_RECORD_TYPE_CARD, Records: 2, Bytes: 120},
+					{Type: pb.RecordType_RECORD_TYPE_BINARY, Records: 1, Bytes: 80},
+				},
+				TotalBytes:      200,
+				QuotaBytes:      1000,
+				QuotaRecords:    10,
+				QuotaBinarySize: 100,
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "storage error test",
+			prepare: func(m *mocks.MockRepositorier) {
+				m.EXPECT().GetUsage(ctxWithValue, testUserLogin).Return(nil, errors.New("error"))
+			},
+			ctx:      ctxWithValue,
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "missing login test",
+			ctx:      context.Background(),
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockRepositorier(ctrl)
+			m.EXPECT().GetSession(gomock.Any(), gomock.Any()).Return(storage.Session{}, nil).AnyTimes()
+			testGRPC := NewKeeperServer(m, cfg)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			res, err := testGRPC.GetUsage(tt.ctx, &pb.GetUsageRequest{})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,694 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeper/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// recordCodec связывает тип записи с методами хранилища.
+type recordCodec struct {
+	// storType - тип записи в хранилище.
+	storType storage.RecordType
+	// timeStamp возвращает время изменения записи.
+	timeStamp func(r *pb.Record) string
+	// toStorage возвращает запись хранилища с временем изменения t.
+	toStorage func(r *pb.Record, t time.Time) any
+	// key возвращает запись хранилища с заполненными ключевыми полями.
+	key func(key *pb.RecordKey) any
+	// syncErrText - текст описания ошибки синхронизации записи.
+	syncErrText string
+	// syncErrValue возвращает значение для описания ошибки синхронизации записи с ключом key.
+	syncErrValue func(key *pb.RecordKey) []byte
+	// fromSync возвращает записи этого типа из запроса синхронизации.
+	fromSync func(in *pb.SyncUserDataRequest) []*pb.Record
+	// refs возвращает ключи записей, о которых клиент сообщил только ссылкой.
+	// Для таких типов в ответ синхронизации также передаются только ссылки.
+	refs func(in *pb.SyncUserDataRequest) []*pb.RecordKey
+	// toSync добавляет запись хранилища в ответ синхронизации.
+	toSync func(res *pb.SyncUserDataResponse, v any)
+}
+
+// syncRecordTypes - типы записей в порядке их обработки при синхронизации.
+var syncRecordTypes = []pb.RecordType{
+	pb.RecordType_RECORD_TYPE_CARD,
+	pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+	pb.RecordType_RECORD_TYPE_TEXT,
+	pb.RecordType_RECORD_TYPE_BINARY,
+	pb.RecordType_RECORD_TYPE_OTP,
+	pb.RecordType_RECORD_TYPE_SSH_KEY,
+	pb.RecordType_RECORD_TYPE_TEMPLATE,
+	pb.RecordType_RECORD_TYPE_CUSTOM,
+}
+
+var recordCodecs = map[pb.RecordType]recordCodec{
+	pb.RecordType_RECORD_TYPE_CARD: {
+		storType:  storage.CardRecord,
+		timeStamp: func(r *pb.Record) string { return r.GetCard().GetTimeStamp() },
+		toStorage: func(r *pb.Record, t time.Time) any {
+			c := r.GetCard()
+			return storage.Card{
+				Prompt:    c.GetPrompt(),
+				Number:    c.GetNumber(),
+				Date:      c.GetDate(),
+				Code:      c.GetCode(),
+				Note:      c.GetNote(),
+				Tags:      c.GetTags(),
+				Folder:    c.GetFolder(),
+				TimeStamp: t,
+			}
+		},
+		key:          func(key *pb.RecordKey) any { return storage.Card{Number: key.GetKey()} },
+		syncErrText:  "error for card number ",
+		syncErrValue: func(key *pb.RecordKey) []byte { return key.GetKey() },
+		fromSync: func(in *pb.SyncUserDataRequest) []*pb.Record {
+			return toRecords(in.GetCards(), func(v *pb.UserCard) *pb.Record { return &pb.Record{Payload: &pb.Record_Card{Card: v}} })
+		},
+		toSync: func(res *pb.SyncUserDataResponse, v any) {
+			res.NewCards = append(res.NewCards, recordToPb(v).GetCard())
+		},
+	},
+	pb.RecordType_RECORD_TYPE_LOGIN_PWD: {
+		storType:  storage.LoginPwdRecord,
+		timeStamp: func(r *pb.Record) string { return r.GetLoginPwd().GetTimeStamp() },
+		toStorage: func(r *pb.Record, t time.Time) any {
+			l := r.GetLoginPwd()
+			return storage.LoginPwd{
+				Prompt:    l.GetPrompt(),
+				Login:     l.GetLogin(),
+				Pwd:       l.GetPwd(),
+				Note:      l.GetNote(),
+				Tags:      l.GetTags(),
+				Folder:    l.GetFolder(),
+				TimeStamp: t,
+			}
+		},
+		key:          func(key *pb.RecordKey) any { return storage.LoginPwd{Prompt: key.GetPrompt(), Login: key.GetKey()} },
+		syncErrText:  "error for pair login/password with prompt ",
+		syncErrValue: func(key *pb.RecordKey) []byte { return key.GetPrompt() },
+		fromSync: func(in *pb.SyncUserDataRequest) []*pb.Record {
+			return toRecords(in.GetLogins(), func(v *pb.UserLoginPwd) *pb.Record { return &pb.Record{Payload: &pb.Record_LoginPwd{LoginPwd: v}} })
+		},
+		toSync: func(res *pb.SyncUserDataResponse, v any) {
+			res.NewLogins = append(res.NewLogins, recordToPb(v).GetLoginPwd())
+		},
+	},
+	pb.RecordType_RECORD_TYPE_TEXT: {
+		storType:  storage.TextDataRecord,
+		timeStamp: func(r *pb.Record) string { return r.GetTextRecord().GetTimeStamp() },
+		toStorage: func(r *pb.Record, t time.Time) any {
+			tr := r.GetTextRecord()
+			return storage.TextRecord{
+				Prompt:    tr.GetPrompt(),
+				Data:      tr.GetData(),
+				Note:      tr.GetNote(),
+				Tags:      tr.GetTags(),
+				Folder:    tr.GetFolder(),
+				TimeStamp: t,
+			}
+		},
+		key:          func(key *pb.RecordKey) any { return storage.TextRecord{Prompt: key.GetPrompt()} },
+		syncErrText:  "error for text data with prompt ",
+		syncErrValue: func(key *pb.RecordKey) []byte { return key.GetPrompt() },
+		fromSync: func(in *pb.SyncUserDataRequest) []*pb.Record {
+			return toRecords(in.GetTextRecords(), func(v *pb.UserTextRecord) *pb.Record {
+				return &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: v}}
+			})
+		},
+		toSync: func(res *pb.SyncUserDataResponse, v any) {
+			res.NewTextRecords = append(res.NewTextRecords, recordToPb(v).GetTextRecord())
+		},
+	},
+	pb.RecordType_RECORD_TYPE_BINARY: {
+		storType:  storage.BinaryDataRecord,
+		timeStamp: func(r *pb.Record) string { return r.GetBinaryRecord().GetTimeStamp() },
+		toStorage: func(r *pb.Record, t time.Time) any {
+			br := r.GetBinaryRecord()
+			return storage.BinaryRecord{
+				Prompt:    br.GetPrompt(),
+				Data:      br.GetData(),
+				Note:      br.GetNote(),
+				Tags:      br.GetTags(),
+				Folder:    br.GetFolder(),
+				TimeStamp: t,
+			}
+		},
+		key:          func(key *pb.RecordKey) any { return storage.BinaryRecord{Prompt: key.GetPrompt()} },
+		syncErrText:  "error for binary data with prompt ",
+		syncErrValue: func(key *pb.RecordKey) []byte { return key.GetPrompt() },
+		fromSync: func(in *pb.SyncUserDataRequest) []*pb.Record {
+			return toRecords(in.GetBinaryRecords(), func(v *pb.UserBinaryRecord) *pb.Record {
+				return &pb.Record{Payload: &pb.Record_BinaryRecord{BinaryRecord: v}}
+			})
+		},
+		refs: func(in *pb.SyncUserDataRequest) []*pb.RecordKey {
+			keys := make([]*pb.RecordKey, 0, len(in.GetBinaryRefs()))
+			for _, v := range in.GetBinaryRefs() {
+				keys = append(keys, &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: v.GetPrompt()})
+			}
+			return keys
+		},
+		toSync: func(res *pb.SyncUserDataResponse, v any) {
+			b := v.(storage.BinaryRecord)
+			res.NewBinaryRefs = append(res.NewBinaryRefs, &pb.BinaryRecordRef{
+				Prompt:    b.Prompt,
+				TimeStamp: b.TimeStamp.Format(time.RFC3339),
+				Size:      b.Size(),
+			})
+		},
+	},
+	pb.RecordType_RECORD_TYPE_OTP: {
+		storType:  storage.OtpRecord,
+		timeStamp: func(r *pb.Record) string { return r.GetOtp().GetTimeStamp() },
+		toStorage: func(r *pb.Record, t time.Time) any {
+			return pbToOtp(r.GetOtp(), t)
+		},
+		key:          func(key *pb.RecordKey) any { return storage.Otp{Issuer: key.GetPrompt(), Account: key.GetKey()} },
+		syncErrText:  "error for one-time password with issuer ",
+		syncErrValue: func(key *pb.RecordKey) []byte { return key.GetPrompt() },
+		fromSync: func(in *pb.SyncUserDataRequest) []*pb.Record {
+			return toRecords(in.GetOtps(), func(v *pb.UserOtp) *pb.Record { return &pb.Record{Payload: &pb.Record_Otp{Otp: v}} })
+		},
+		toSync: func(res *pb.SyncUserDataResponse, v any) {
+			res.NewOtps = append(res.NewOtps, otpToPb(v.(storage.Otp)))
+		},
+	},
+	pb.RecordType_RECORD_TYPE_SSH_KEY: {
+		storType:  storage.SshKeyRecord,
+		timeStamp: func(r *pb.Record) string { return r.GetSshKey().GetTimeStamp() },
+		toStorage: func(r *pb.Record, t time.Time) any {
+			return pbToSshKey(r.GetSshKey(), t)
+		},
+		key:          func(key *pb.RecordKey) any { return storage.SshKey{Prompt: key.GetPrompt()} },
+		syncErrText:  "error for ssh key with prompt ",
+		syncErrValue: func(key *pb.RecordKey) []byte { return key.GetPrompt() },
+		fromSync: func(in *pb.SyncUserDataRequest) []*pb.Record {
+			return toRecords(in.GetSshKeys(), func(v *pb.UserSshKey) *pb.Record { return &pb.Record{Payload: &pb.Record_SshKey{SshKey: v}} })
+		},
+		toSync: func(res *pb.SyncUserDataResponse, v any) {
+			res.NewSshKeys = append(res.NewSshKeys, sshKeyToPb(v.(storage.SshKey)))
+		},
+	},
+	pb.RecordType_RECORD_TYPE_TEMPLATE: {
+		storType:  storage.TemplateRecord,
+		timeStamp: func(r *pb.Record) string { return r.GetTemplate().GetTimeStamp() },
+		toStorage: func(r *pb.Record, t time.Time) any {
+			return pbToTemplate(r.GetTemplate(), t)
+		},
+		key:          func(key *pb.RecordKey) any { return storage.Template{Name: key.GetPrompt()} },
+		syncErrText:  "error for template with name ",
+		syncErrValue: func(key *pb.RecordKey) []byte { return key.GetPrompt() },
+		fromSync: func(in *pb.SyncUserDataRequest) []*pb.Record {
+			return toRecords(in.GetTemplates(), func(v *pb.UserTemplate) *pb.Record { return &pb.Record{Payload: &pb.Record_Template{Template: v}} })
+		},
+		toSync: func(res *pb.SyncUserDataResponse, v any) {
+			res.NewTemplates = append(res.NewTemplates, templateToPb(v.(storage.Template)))
+		},
+	},
+	pb.RecordType_RECORD_TYPE_CUSTOM: {
+		storType:  storage.CustomDataRecord,
+		timeStamp: func(r *pb.Record) string { return r.GetCustomRecord().GetTimeStamp() },
+		toStorage: func(r *pb.Record, t time.Time) any {
+			return pbToCustomRecord(r.GetCustomRecord(), t)
+		},
+		key:          func(key *pb.RecordKey) any { return storage.CustomRecord{Prompt: key.GetPrompt()} },
+		syncErrText:  "error for custom record with prompt ",
+		syncErrValue: func(key *pb.RecordKey) []byte { return key.GetPrompt() },
+		fromSync: func(in *pb.SyncUserDataRequest) []*pb.Record {
+			return toRecords(in.GetCustomRecords(), func(v *pb.UserCustomRecord) *pb.Record {
+				return &pb.Record{Payload: &pb.Record_CustomRecord{CustomRecord: v}}
+			})
+		},
+		toSync: func(res *pb.SyncUserDataResponse, v any) {
+			res.NewCustomRecords = append(res.NewCustomRecords, customRecordToPb(v.(storage.CustomRecord)))
+		},
+	},
+}
+
+// toRecords преобразует записи одного типа из запроса в записи любого типа.
+func toRecords[T any](vs []T, wrap func(v T) *pb.Record) []*pb.Record {
+	res := make([]*pb.Record, 0, len(vs))
+	for _, v := range vs {
+		res = append(res, wrap(v))
+	}
+	return res
+}
+
+// sameRecordKey сообщает, совпадают ли ключи записей a и b.
+func sameRecordKey(a, b *pb.RecordKey) bool {
+	return a.GetType() == b.GetType() &&
+		bytes.Equal(a.GetPrompt(), b.GetPrompt()) &&
+		bytes.Equal(a.GetKey(), b.GetKey())
+}
+
+// pbToOtp преобразует параметры одноразовых кодов из запроса для хранилища.
+func pbToOtp(o *pb.UserOtp, timeStamp time.Time) storage.Otp {
+	return storage.Otp{
+		Issuer:    o.GetIssuer(),
+		Account:   o.GetAccount(),
+		Secret:    o.GetSecret(),
+		Algorithm: o.GetAlgorithm(),
+		Digits:    o.GetDigits(),
+		Period:    o.GetPeriod(),
+		Note:      o.GetNote(),
+		Tags:      o.GetTags(),
+		Folder:    o.GetFolder(),
+		TimeStamp: timeStamp,
+	}
+}
+
+// otpToPb преобразует параметры одноразовых кодов из хранилища для ответа.
+func otpToPb(o storage.Otp) *pb.UserOtp {
+	return &pb.UserOtp{
+		Issuer:    o.Issuer,
+		Account:   o.Account,
+		Secret:    o.Secret,
+		Algorithm: o.Algorithm,
+		Digits:    o.Digits,
+		Period:    o.Period,
+		Note:      o.Note,
+		Tags:      o.Tags,
+		Folder:    o.Folder,
+		TimeStamp: o.TimeStamp.Format(time.RFC3339),
+	}
+}
+
+// pbToSshKey преобразует ключ SSH из запроса для хранилища.
+func pbToSshKey(k *pb.UserSshKey, timeStamp time.Time) storage.SshKey {
+	return storage.SshKey{
+		Prompt:     k.GetPrompt(),
+		PrivateKey: k.GetPrivateKey(),
+		PublicKey:  k.GetPublicKey(),
+		Comment:    k.GetComment(),
+		Passphrase: k.GetPassphrase(),
+		Tags:       k.GetTags(),
+		Folder:     k.GetFolder(),
+		TimeStamp:  timeStamp,
+	}
+}
+
+// sshKeyToPb преобразует ключ SSH из хранилища для ответа.
+func sshKeyToPb(k storage.SshKey) *pb.UserSshKey {
+	return &pb.UserSshKey{
+		Prompt:     k.Prompt,
+		PrivateKey: k.PrivateKey,
+		PublicKey:  k.PublicKey,
+		Comment:    k.Comment,
+		Passphrase: k.Passphrase,
+		Tags:       k.Tags,
+		Folder:     k.Folder,
+		TimeStamp:  k.TimeStamp.Format(time.RFC3339),
+	}
+}
+
+// pbToTemplate преобразует шаблон пользовательских записей из запроса для хранилища.
+func pbToTemplate(t *pb.UserTemplate, timeStamp time.Time) storage.Template {
+	return storage.Template{
+		Name:      t.GetName(),
+		Fields:    t.GetFields(),
+		Tags:      t.GetTags(),
+		Folder:    t.GetFolder(),
+		TimeStamp: timeStamp,
+	}
+}
+
+// templateToPb преобразует шаблон пользовательских записей из хранилища для ответа.
+func templateToPb(t storage.Template) *pb.UserTemplate {
+	return &pb.UserTemplate{
+		Name:      t.Name,
+		Fields:    t.Fields,
+		Tags:      t.Tags,
+		Folder:    t.Folder,
+		TimeStamp: t.TimeStamp.Format(time.RFC3339),
+	}
+}
+
+// pbToCustomRecord преобразует пользовательскую запись из запроса для хранилища.
+func pbToCustomRecord(c *pb.UserCustomRecord, timeStamp time.Time) storage.CustomRecord {
+	return storage.CustomRecord{
+		Prompt:    c.GetPrompt(),
+		Template:  c.GetTemplate(),
+		Data:      c.GetData(),
+		Note:      c.GetNote(),
+		Tags:      c.GetTags(),
+		Folder:    c.GetFolder(),
+		TimeStamp: timeStamp,
+	}
+}
+
+// customRecordToPb преобразует пользовательскую запись из хранилища для ответа.
+func customRecordToPb(c storage.CustomRecord) *pb.UserCustomRecord {
+	return &pb.UserCustomRecord{
+		Prompt:    c.Prompt,
+		Template:  c.Template,
+		Data:      c.Data,
+		Note:      c.Note,
+		Tags:      c.Tags,
+		Folder:    c.Folder,
+		TimeStamp: c.TimeStamp.Format(time.RFC3339),
+	}
+}
+
+// recordToPb преобразует запись хранилища для ответа.
+func recordToPb(r any) *pb.Record {
+	switch v := r.(type) {
+	case storage.Card:
+		return &pb.Record{
+			Type: pb.RecordType_RECORD_TYPE_CARD,
+			Payload: &pb.Record_Card{Card: &pb.UserCard{
+				Prompt:    v.Prompt,
+				Number:    v.Number,
+				Date:      v.Date,
+				Code:      v.Code,
+				Note:      v.Note,
+				Tags:      v.Tags,
+				Folder:    v.Folder,
+				TimeStamp: v.TimeStamp.Format(time.RFC3339),
+			}},
+		}
+	case storage.LoginPwd:
+		return &pb.Record{
+			Type: pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+			Payload: &pb.Record_LoginPwd{LoginPwd: &pb.UserLoginPwd{
+				Prompt:    v.Prompt,
+				Login:     v.Login,
+				Pwd:       v.Pwd,
+				Note:      v.Note,
+				Tags:      v.Tags,
+				Folder:    v.Folder,
+				TimeStamp: v.TimeStamp.Format(time.RFC3339),
+			}},
+		}
+	case storage.TextRecord:
+		return &pb.Record{
+			Type: pb.RecordType_RECORD_TYPE_TEXT,
+			Payload: &pb.Record_TextRecord{TextRecord: &pb.UserTextRecord{
+				Prompt:    v.Prompt,
+				Data:      v.Data,
+				Note:      v.Note,
+				Tags:      v.Tags,
+				Folder:    v.Folder,
+				TimeStamp: v.TimeStamp.Format(time.RFC3339),
+			}},
+		}
+	case storage.BinaryRecord:
+		return &pb.Record{
+			Type: pb.RecordType_RECORD_TYPE_BINARY,
+			Payload: &pb.Record_BinaryRecord{BinaryRecord: &pb.UserBinaryRecord{
+				Prompt:    v.Prompt,
+				Data:      v.Data,
+				Note:      v.Note,
+				Tags:      v.Tags,
+				Folder:    v.Folder,
+				TimeStamp: v.TimeStamp.Format(time.RFC3339),
+			}},
+		}
+	case storage.Otp:
+		return &pb.Record{
+			Type:    pb.RecordType_RECORD_TYPE_OTP,
+			Payload: &pb.Record_Otp{Otp: otpToPb(v)},
+		}
+	case storage.SshKey:
+		return &pb.Record{
+			Type:    pb.RecordType_RECORD_TYPE_SSH_KEY,
+			Payload: &pb.Record_SshKey{SshKey: sshKeyToPb(v)},
+		}
+	case storage.Template:
+		return &pb.Record{
+			Type:    pb.RecordType_RECORD_TYPE_TEMPLATE,
+			Payload: &pb.Record_Template{Template: templateToPb(v)},
+		}
+	case storage.CustomRecord:
+		return &pb.Record{
+			Type:    pb.RecordType_RECORD_TYPE_CUSTOM,
+			Payload: &pb.Record_CustomRecord{CustomRecord: customRecordToPb(v)},
+		}
+	}
+	return &pb.Record{}
+}
+
+// payloadType возвращает тип записи по ее содержимому.
+func payloadType(r *pb.Record) pb.RecordType {
+	switch r.GetPayload().(type) {
+	case *pb.Record_Card:
+		return pb.RecordType_RECORD_TYPE_CARD
+	case *pb.Record_LoginPwd:
+		return pb.RecordType_RECORD_TYPE_LOGIN_PWD
+	case *pb.Record_TextRecord:
+		return pb.RecordType_RECORD_TYPE_TEXT
+	case *pb.Record_BinaryRecord:
+		return pb.RecordType_RECORD_TYPE_BINARY
+	case *pb.Record_Otp:
+		return pb.RecordType_RECORD_TYPE_OTP
+	case *pb.Record_SshKey:
+		return pb.RecordType_RECORD_TYPE_SSH_KEY
+	case *pb.Record_Template:
+		return pb.RecordType_RECORD_TYPE_TEMPLATE
+	case *pb.Record_CustomRecord:
+		return pb.RecordType_RECORD_TYPE_CUSTOM
+	}
+	return pb.RecordType_RECORD_TYPE_UNSPECIFIED
+}
+
+// recordCodecFor проверяет запись и возвращает обработчик ее типа.
+// Код emptyCode используется, если запись не содержит данных.
+func recordCodecFor(r *pb.Record, emptyCode codes.Code) (recordCodec, error) {
+	t := payloadType(r)
+	if t == pb.RecordType_RECORD_TYPE_UNSPECIFIED {
+		return recordCodec{}, status.Error(emptyCode, "empty request")
+	}
+	if r.GetType() != pb.RecordType_RECORD_TYPE_UNSPECIFIED && r.GetType() != t {
+		return recordCodec{}, status.Error(codes.InvalidArgument, "record type does not match payload")
+	}
+	return recordCodecs[t], nil
+}
+
+// storErrToStatus преобразует ошибку хранилища в ошибку gRPC.
+func storErrToStatus(err error) error {
+	var storErr *storage.StorErr
+	if errors.As(err, &storErr) && storErr.ErrType == storage.EmptyValues {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.As(err, &storErr) && storErr.ErrType == storage.ExistsDataNewerVersion {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	if errors.As(err, &storErr) && storErr.ErrType == storage.EmptyResult {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.As(err, &storErr) && storErr.ErrType == storage.AlreadyExists {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	if errors.As(err, &storErr) && storErr.ErrType == storage.Forbidden {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.As(err, &storErr) && storErr.ErrType == storage.QuotaExceeded {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// userLogin получает логин пользователя из токена в контексте.
+// Токен заблокированного пользователя и токен завершенной администратором сессии не принимаются.
+func (ks *KeeperGRPCServer) userLogin(ctx context.Context) (string, error) {
+	v := ctx.Value(authorizer.UserContextKey)
+	if v == nil {
+		return "", status.Error(codes.Unauthenticated, "missing token")
+	}
+	userToken := v.(string)
+
+	claims, err := authorizer.ParseToken(userToken, ks.cfg.SecretKey)
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, err.Error())
+	}
+	session, err := ks.stor.GetSession(ctx, claims.Login)
+	if err != nil {
+		var storErr *storage.StorErr
+		if errors.As(err, &storErr) && storErr.ErrType == storage.EmptyResult {
+			return "", status.Error(codes.Unauthenticated, "user not found")
+		}
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	if session.Disabled {
+		return "", status.Error(codes.PermissionDenied, "user is disabled")
+	}
+	if session.UserID != claims.UserID || session.Version != claims.Session {
+		return "", status.Error(codes.Unauthenticated, "session is closed, log in again")
+	}
+
+	return claims.Login, nil
+}
+
+// revisionToPb преобразует ревизию сохраненной записи в сообщение.
+func revisionToPb(rev storage.Revision) *pb.RecordRevision {
+	return &pb.RecordRevision{
+		RecordId:  rev.ID,
+		Revision:  rev.Revision,
+		TimeStamp: rev.TimeStamp.Format(time.RFC3339),
+	}
+}
+
+// addRecord добавляет запись любого типа и возвращает ее ревизию.
+func (ks *KeeperGRPCServer) addRecord(ctx context.Context, r *pb.Record) (*pb.RecordRevision, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := recordCodecFor(r, codes.DataLoss)
+	if err != nil {
+		return nil, err
+	}
+
+	timeStamp, err := time.Parse(time.RFC3339, codec.timeStamp(r))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	err = ks.newQuotaChecker(userLogin).check(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := ks.stor.AddRecord(ctx, userLogin, codec.toStorage(r, timeStamp))
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+	ks.audit(ctx, userLogin, storage.AuditCreate, recordKeyOf(r))
+
+	return revisionToPb(rev), nil
+}
+
+// forceUpdateRecord обновляет запись любого типа без проверки времени изменения
+// и возвращает ее ревизию.
+func (ks *KeeperGRPCServer) forceUpdateRecord(ctx context.Context, r *pb.Record) (*pb.RecordRevision, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := recordCodecFor(r, codes.InvalidArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	timeStamp, err := time.Parse(time.RFC3339, codec.timeStamp(r))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	err = ks.newQuotaChecker(userLogin).check(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := ks.stor.ForceUpdateRecord(ctx, userLogin, codec.toStorage(r, timeStamp))
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+	ks.audit(ctx, userLogin, storage.AuditForceUpdate, recordKeyOf(r))
+
+	return revisionToPb(rev), nil
+}
+
+// getRecord получает запись любого типа по ключу.
+func (ks *KeeperGRPCServer) getRecord(ctx context.Context, key *pb.RecordKey) (*pb.Record, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, ok := recordCodecs[key.GetType()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown record type")
+	}
+
+	r, err := ks.stor.GetRecord(ctx, userLogin, codec.key(key))
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+	ks.audit(ctx, userLogin, storage.AuditRead, key)
+
+	return recordToPb(r), nil
+}
+
+// AddRecord реализует добавление записи любого типа.
+func (ks *KeeperGRPCServer) AddRecord(ctx context.Context, in *pb.AddRecordRequest) (*pb.AddRecordResponse, error) {
+	rev, err := ks.addRecord(ctx, in.GetRecord())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AddRecordResponse{Revision: rev}, nil
+}
+
+// GetRecord реализует получение записи любого типа по ключу.
+func (ks *KeeperGRPCServer) GetRecord(ctx context.Context, in *pb.GetRecordRequest) (*pb.GetRecordResponse, error) {
+	r, err := ks.getRecord(ctx, in.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetRecordResponse{Record: r}, nil
+}
+
+// ForceUpdateRecord реализует обновление записи любого типа без проверки времени изменения.
+func (ks *KeeperGRPCServer) ForceUpdateRecord(ctx context.Context, in *pb.ForceUpdateRecordRequest) (*pb.ForceUpdateRecordResponse, error) {
+	rev, err := ks.forceUpdateRecord(ctx, in.GetRecord())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ForceUpdateRecordResponse{Revision: rev}, nil
+}
+
+// ListVersions реализует получение предыдущих версий записи любого типа, начиная с последней.
+func (ks *KeeperGRPCServer) ListVersions(ctx context.Context, in *pb.ListVersionsRequest) (*pb.ListVersionsResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, ok := recordCodecs[in.GetKey().GetType()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown record type")
+	}
+
+	versions, err := ks.stor.ListVersions(ctx, userLogin, codec.key(in.GetKey()))
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+
+	res := &pb.ListVersionsResponse{Versions: make([]*pb.RecordVersion, 0, len(versions))}
+	for _, v := range versions {
+		res.Versions = append(res.Versions, &pb.RecordVersion{
+			Version: v.ID,
+			Record:  recordToPb(v.Record),
+		})
+	}
+
+	return res, nil
+}
+
+// RestoreVersion реализует восстановление предыдущей версии записи любого типа.
+// Текущая версия записи сохраняется в истории.
+func (ks *KeeperGRPCServer) RestoreVersion(ctx context.Context, in *pb.RestoreVersionRequest) (*pb.RestoreVersionResponse, error) {
+	userLogin, err := ks.userLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, ok := recordCodecs[in.GetKey().GetType()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown record type")
+	}
+
+	r, err := ks.stor.RestoreVersion(ctx, userLogin, codec.key(in.GetKey()), in.GetVersion(),
+		time.Now().Truncate(time.Second))
+	if err != nil {
+		return nil, storErrToStatus(err)
+	}
+	ks.audit(ctx, userLogin, storage.AuditRestore, in.GetKey())
+
+	return &pb.RestoreVersionResponse{Record: recordToPb(r)}, nil
+}
@@ -0,0 +1,216 @@
+// Пакет idempotency повторяет результат записи при повторной отправке запроса
+// с тем же ключом идемпотентности.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// writeMethods - методы, результат которых сохраняется по ключу идемпотентности.
+var writeMethods = map[string]bool{
+	pb.InfoKeeper_AddCard_FullMethodName:                 true,
+	pb.InfoKeeper_AddLogin_FullMethodName:                true,
+	pb.InfoKeeper_AddTextData_FullMethodName:             true,
+	pb.InfoKeeper_AddBinaryData_FullMethodName:           true,
+	pb.InfoKeeper_AddRecord_FullMethodName:               true,
+	pb.InfoKeeper_ForceUpdateCard_FullMethodName:         true,
+	pb.InfoKeeper_ForceUpdateLoginPwd_FullMethodName:     true,
+	pb.InfoKeeper_ForceUpdateTextRecord_FullMethodName:   true,
+	pb.InfoKeeper_ForceUpdateBinaryRecord_FullMethodName: true,
+	pb.InfoKeeper_ForceUpdateRecord_FullMethodName:       true,
+	pb.InfoKeeper_SyncUserData_FullMethodName:            true,
+	pb.InfoKeeper_CreateOrganization_FullMethodName:      true,
+	pb.InfoKeeper_CreateCollection_FullMethodName:        true,
+	pb.InfoKeeper_MoveRecordToCollection_FullMethodName:  true,
+}
+
+// IsWriteMethod проверяет, что для метода поддерживается ключ идемпотентности.
+func IsWriteMethod(fullMethod string) bool {
+	return writeMethods[fullMethod]
+}
+
+// entry хранит результат запроса с ключом идемпотентности.
+// Канал done закрывается после завершения первого запроса.
+type entry struct {
+	done    chan struct{}
+	reqHash [sha256.Size]byte
+	resp    interface{}
+	err     error
+}
+
+// expiry хранит время, после которого результат запроса удаляется.
+type expiry struct {
+	key string
+	at  time.Time
+}
+
+// Cache хранит результаты запросов пользователей по ключам идемпотентности
+// в течение заданного окна.
+type Cache struct {
+	mu        sync.Mutex
+	window    time.Duration
+	secretKey string
+	entries   map[string]*entry
+	// expiries упорядочены по времени, так как окно одинаково для всех записей.
+	expiries []expiry
+	now      func() time.Time
+}
+
+// New создает хранилище результатов запросов с окном window.
+// Ключ secretKey используется для получения логина пользователя из токена.
+func New(window time.Duration, secretKey string) *Cache {
+	return &Cache{
+		window:    window,
+		secretKey: secretKey,
+		entries:   make(map[string]*entry),
+		now:       time.Now,
+	}
+}
+
+// retryable проверяет, что ошибка временная и запрос нужно выполнить повторно, а не повторить ее.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded, codes.Unavailable, codes.Aborted, codes.Internal, codes.Unknown:
+		return true
+	}
+	return false
+}
+
+// requestHash возвращает хеш запроса для проверки повторного использования ключа.
+func requestHash(req interface{}) ([sha256.Size]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return [sha256.Size]byte{}, nil
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(b), nil
+}
+
+// idempotencyKey получает ключ идемпотентности из метаданных запроса.
+func idempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(authorizer.IdempotencyKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// userLogin получает логин пользователя из токена в контексте.
+func (c *Cache) userLogin(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(authorizer.UserContextKey).(string)
+	if !ok {
+		return "", false
+	}
+	login, err := authorizer.GetUserDataFromToken(token, c.secretKey)
+	if err != nil {
+		return "", false
+	}
+	return login, true
+}
+
+// removeExpired удаляет результаты запросов, окно которых истекло.
+func (c *Cache) removeExpired() {
+	now := c.now()
+	i := 0
+	for ; i < len(c.expiries) && !now.Before(c.expiries[i].at); i++ {
+		delete(c.entries, c.expiries[i].key)
+	}
+	c.expiries = c.expiries[i:]
+}
+
+// acquire возвращает результат запроса по ключу key.
+// Если результата нет, создается новый и first равно true.
+func (c *Cache) acquire(key string, reqHash [sha256.Size]byte) (e *entry, first bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeExpired()
+	e, ok := c.entries[key]
+	if ok {
+		return e, false
+	}
+	e = &entry{done: make(chan struct{}), reqHash: reqHash}
+	c.entries[key] = e
+	return e, true
+}
+
+// complete сохраняет результат запроса по ключу key.
+// Результат с временной ошибкой не сохраняется, чтобы повторный запрос был выполнен.
+func (c *Cache) complete(key string, e *entry, resp interface{}, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.resp, e.err = resp, err
+	if retryable(err) {
+		delete(c.entries, key)
+	} else {
+		c.expiries = append(c.expiries, expiry{key: key, at: c.now().Add(c.window)})
+	}
+	close(e.done)
+}
+
+// UnaryInterceptor выполняет запрос на запись с ключом идемпотентности один раз
+// и возвращает сохраненный результат при повторных запросах пользователя с тем же ключом.
+// Должен вызываться после интерсептора авторизации.
+func (c *Cache) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !IsWriteMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	key := idempotencyKey(ctx)
+	if key == "" {
+		return handler(ctx, req)
+	}
+	login, ok := c.userLogin(ctx)
+	if !ok {
+		return handler(ctx, req)
+	}
+	reqHash, err := requestHash(req)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	key = login + "\x00" + info.FullMethod + "\x00" + key
+	for {
+		e, first := c.acquire(key, reqHash)
+		if first {
+			resp, err := handler(ctx, req)
+			c.complete(key, e, resp, err)
+			return resp, err
+		}
+
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		if e.reqHash != reqHash {
+			return nil, status.Error(codes.InvalidArgument, "idempotency key is reused with a different request")
+		}
+		if retryable(e.err) {
+			continue
+		}
+		if msg, ok := e.resp.(proto.Message); ok {
+			return proto.Clone(msg), e.err
+		}
+		return e.resp, e.err
+	}
+}
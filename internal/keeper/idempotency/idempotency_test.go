@@ -0,0 +1,156 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+const testSecretKey = "secret"
+
+func testContext(t *testing.T, login, key string) context.Context {
+	token, err := authorizer.BuildToken(login, "pwd", testSecretKey)
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.New(map[string]string{authorizer.IdempotencyKey: key}))
+	return context.WithValue(ctx, authorizer.UserContextKey, token)
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	addInfo := &grpc.UnaryServerInfo{FullMethod: pb.InfoKeeper_AddRecord_FullMethodName}
+	getInfo := &grpc.UnaryServerInfo{FullMethod: pb.InfoKeeper_GetRecord_FullMethodName}
+	req := &pb.AddRecordRequest{Record: &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: &pb.UserTextRecord{Prompt: []byte("prompt")}}}}
+	otherReq := &pb.AddRecordRequest{Record: &pb.Record{Payload: &pb.Record_TextRecord{TextRecord: &pb.UserTextRecord{Prompt: []byte("other")}}}}
+
+	tests := []struct {
+		name      string
+		calls     []*pb.AddRecordRequest
+		ctxs      []context.Context
+		info      *grpc.UnaryServerInfo
+		handleErr []error
+		wantCalls int
+		wantCodes []codes.Code
+	}{
+		{
+			name:      "replay test",
+			calls:     []*pb.AddRecordRequest{req, req},
+			ctxs:      []context.Context{testContext(t, "ulogin", "key1"), testContext(t, "ulogin", "key1")},
+			info:      addInfo,
+			handleErr: []error{nil, nil},
+			wantCalls: 1,
+			wantCodes: []codes.Code{codes.OK, codes.OK},
+		},
+		{
+			name:      "replay error test",
+			calls:     []*pb.AddRecordRequest{req, req},
+			ctxs:      []context.Context{testContext(t, "ulogin", "key1"), testContext(t, "ulogin", "key1")},
+			info:      addInfo,
+			handleErr: []error{status.Error(codes.AlreadyExists, "exists"), nil},
+			wantCalls: 1,
+			wantCodes: []codes.Code{codes.AlreadyExists, codes.AlreadyExists},
+		},
+		{
+			name:      "different request test",
+			calls:     []*pb.AddRecordRequest{req, otherReq},
+			ctxs:      []context.Context{testContext(t, "ulogin", "key1"), testContext(t, "ulogin", "key1")},
+			info:      addInfo,
+			handleErr: []error{nil, nil},
+			wantCalls: 1,
+			wantCodes: []codes.Code{codes.OK, codes.InvalidArgument},
+		},
+		{
+			name:      "transient error test",
+			calls:     []*pb.AddRecordRequest{req, req},
+			ctxs:      []context.Context{testContext(t, "ulogin", "key1"), testContext(t, "ulogin", "key1")},
+			info:      addInfo,
+			handleErr: []error{status.Error(codes.Internal, "db error"), nil},
+			wantCalls: 2,
+			wantCodes: []codes.Code{codes.Internal, codes.OK},
+		},
+		{
+			name:      "other user test",
+			calls:     []*pb.AddRecordRequest{req, req},
+			ctxs:      []context.Context{testContext(t, "ulogin", "key1"), testContext(t, "ulogin2", "key1")},
+			info:      addInfo,
+			handleErr: []error{nil, nil},
+			wantCalls: 2,
+			wantCodes: []codes.Code{codes.OK, codes.OK},
+		},
+		{
+			name:      "without key test",
+			calls:     []*pb.AddRecordRequest{req, req},
+			ctxs:      []context.Context{testContext(t, "ulogin", ""), testContext(t, "ulogin", "")},
+			info:      addInfo,
+			handleErr: []error{nil, nil},
+			wantCalls: 2,
+			wantCodes: []codes.Code{codes.OK, codes.OK},
+		},
+		{
+			name:      "read method test",
+			calls:     []*pb.AddRecordRequest{req, req},
+			ctxs:      []context.Context{testContext(t, "ulogin", "key1"), testContext(t, "ulogin", "key1")},
+			info:      getInfo,
+			handleErr: []error{nil, nil},
+			wantCalls: 2,
+			wantCodes: []codes.Code{codes.OK, codes.OK},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := New(time.Minute, testSecretKey)
+			calls := 0
+			for i, r := range test.calls {
+				resp, err := c.UnaryInterceptor(test.ctxs[i], r, test.info,
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						err := test.handleErr[calls]
+						calls++
+						if err != nil {
+							return nil, err
+						}
+						return &pb.AddRecordResponse{Revision: &pb.RecordRevision{RecordId: int64(calls)}}, nil
+					})
+				assert.Equal(t, test.wantCodes[i], status.Code(err))
+				if err == nil {
+					assert.NotNil(t, resp)
+				}
+			}
+			assert.Equal(t, test.wantCalls, calls)
+		})
+	}
+}
+
+func TestUnaryInterceptorExpired(t *testing.T) {
+	c := New(time.Minute, testSecretKey)
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	info := &grpc.UnaryServerInfo{FullMethod: pb.InfoKeeper_SyncUserData_FullMethodName}
+	req := &pb.SyncUserDataRequest{SinceRevision: 7}
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return &pb.SyncUserDataResponse{}, nil
+	}
+
+	_, err := c.UnaryInterceptor(testContext(t, "ulogin", "key1"), req, info, handler)
+	require.NoError(t, err)
+	_, err = c.UnaryInterceptor(testContext(t, "ulogin", "key1"), req, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(time.Minute)
+	_, err = c.UnaryInterceptor(testContext(t, "ulogin", "key1"), req, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Len(t, c.entries, 1)
+}
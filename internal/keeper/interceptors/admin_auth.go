@@ -0,0 +1,46 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// isAdminMethod проверяет, что метод относится к сервису KeeperAdmin.
+func isAdminMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+pb.KeeperAdmin_ServiceDesc.ServiceName+"/")
+}
+
+// AdminAuth создает интерсептор, проверяющий ключ администратора в методах сервиса KeeperAdmin.
+// Если ключ adminKey не задан, методы администрирования недоступны.
+func AdminAuth(adminKey string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !isAdminMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if adminKey == "" {
+			return nil, status.Error(codes.PermissionDenied, "admin service is disabled")
+		}
+
+		var key string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			values := md.Get(authorizer.AdminKey)
+			if len(values) > 0 {
+				key = values[0]
+			}
+		}
+		if subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid admin key")
+		}
+
+		return handler(ctx, req)
+	}
+}
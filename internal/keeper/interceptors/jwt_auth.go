@@ -2,6 +2,7 @@ package interceptors
 
 import (
 	"context"
+	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -12,9 +13,16 @@ import (
 	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
 )
 
+// isKeeperMethod проверяет, что метод относится к сервису InfoKeeper.
+// Служебные сервисы (health, reflection) не требуют токена.
+func isKeeperMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+pb.InfoKeeper_ServiceDesc.ServiceName+"/")
+}
+
 // HandlerWithAuth добавляет токен в контекст метода.
 func HandlerWithAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	if info.FullMethod == pb.InfoKeeper_AddUser_FullMethodName ||
+	if !isKeeperMethod(info.FullMethod) ||
+		info.FullMethod == pb.InfoKeeper_AddUser_FullMethodName ||
 		info.FullMethod == pb.InfoKeeper_AuthUser_FullMethodName {
 		return handler(ctx, req)
 	}
@@ -34,3 +42,35 @@ func HandlerWithAuth(ctx context.Context, req interface{}, info *grpc.UnaryServe
 
 	return handler(ctx, req)
 }
+
+type streamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает контекст потока с токеном пользователя.
+func (s *streamWithContext) Context() context.Context {
+	return s.ctx
+}
+
+// StreamHandlerWithAuth добавляет токен в контекст потокового метода.
+func StreamHandlerWithAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !isKeeperMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ss.Context()); ok {
+		values := md.Get(authorizer.AccessToken)
+		if len(values) > 0 {
+			token = values[0]
+		}
+	}
+	if len(token) == 0 {
+		return status.Error(codes.Internal, "missing token")
+	}
+
+	ctx := context.WithValue(ss.Context(), authorizer.UserContextKey, token)
+
+	return handler(srv, &streamWithContext{ServerStream: ss, ctx: ctx})
+}
@@ -0,0 +1,71 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+func TestHandlerWithAuth(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		wantErr bool
+	}{
+		{name: "health without token", method: grpc_health_v1.Health_Check_FullMethodName, wantErr: false},
+		{name: "auth without token", method: pb.InfoKeeper_AuthUser_FullMethodName, wantErr: false},
+		{name: "keeper method without token", method: pb.InfoKeeper_GetRecord_FullMethodName, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := HandlerWithAuth(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	withKey := func(key string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{authorizer.AdminKey: key}))
+	}
+
+	tests := []struct {
+		name     string
+		adminKey string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{name: "ok test", adminKey: "akey", ctx: withKey("akey"), method: pb.KeeperAdmin_ListUsers_FullMethodName, wantCode: codes.OK},
+		{name: "invalid key test", adminKey: "akey", ctx: withKey("other"), method: pb.KeeperAdmin_ListUsers_FullMethodName, wantCode: codes.Unauthenticated},
+		{name: "missing key test", adminKey: "akey", ctx: context.Background(), method: pb.KeeperAdmin_DeleteUser_FullMethodName, wantCode: codes.Unauthenticated},
+		{name: "disabled test", adminKey: "", ctx: withKey(""), method: pb.KeeperAdmin_ListUsers_FullMethodName, wantCode: codes.PermissionDenied},
+		{name: "keeper method test", adminKey: "akey", ctx: context.Background(), method: pb.InfoKeeper_GetRecord_FullMethodName, wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AdminAuth(tt.adminKey)(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -20,3 +20,15 @@ func HandlerWithLogging(ctx context.Context, req interface{}, info *grpc.UnarySe
 
 	return h, err
 }
+
+// StreamHandlerWithLogging добавляет логирование потоковых gRPC-методов.
+func StreamHandlerWithLogging(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logger.ZapSugar.Infoln(
+		"full method", info.FullMethod,
+		"duration", time.Since(start),
+	)
+
+	return err
+}
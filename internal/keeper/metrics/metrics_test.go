@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	m := New()
+
+	info := &grpc.UnaryServerInfo{FullMethod: pb.InfoKeeper_SyncUserData_FullMethodName}
+	_, err := m.UnaryInterceptor(context.Background(), &pb.SyncUserDataRequest{SinceRevision: 7}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &pb.SyncUserDataResponse{}, nil
+		})
+	require.NoError(t, err)
+
+	info = &grpc.UnaryServerInfo{FullMethod: pb.InfoKeeper_GetRecord_FullMethodName}
+	_, err = m.UnaryInterceptor(context.Background(), &pb.GetRecordRequest{}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		})
+	require.Error(t, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		m.requests.WithLabelValues(pb.InfoKeeper_SyncUserData_FullMethodName, codes.OK.String())))
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		m.requests.WithLabelValues(pb.InfoKeeper_GetRecord_FullMethodName, codes.NotFound.String())))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.latency))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.syncPayload))
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	m := New()
+
+	info := &grpc.StreamServerInfo{FullMethod: pb.InfoKeeper_WatchChanges_FullMethodName}
+	err := m.StreamInterceptor(nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return status.Error(codes.Canceled, "canceled")
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		m.requests.WithLabelValues(pb.InfoKeeper_WatchChanges_FullMethodName, codes.Canceled.String())))
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.RegisterDBStats(func() sql.DBStats {
+		return sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3, InUse: 1, Idle: 2}
+	})
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "keeper_db_open_connections 3")
+	assert.Contains(t, rec.Body.String(), "keeper_db_max_open_connections 10")
+}
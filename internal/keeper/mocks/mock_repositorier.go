@@ -6,6 +6,7 @@ package mocks
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
 	time "time"
 
@@ -37,60 +38,61 @@ func (m *MockRepositorier) EXPECT() *MockRepositorierMockRecorder {
 	return m.recorder
 }
 
-// AddBinaryRecord mocks base method.
-func (m *MockRepositorier) AddBinaryRecord(arg0 context.Context, arg1 string, arg2, arg3, arg4 []byte, arg5 time.Time) error {
+// AddAuditEvent mocks base method.
+func (m *MockRepositorier) AddAuditEvent(arg0 context.Context, arg1 string, arg2 storage.AuditEvent) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddBinaryRecord", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret := m.ctrl.Call(m, "AddAuditEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddBinaryRecord indicates an expected call of AddBinaryRecord.
-func (mr *MockRepositorierMockRecorder) AddBinaryRecord(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+// AddAuditEvent indicates an expected call of AddAuditEvent.
+func (mr *MockRepositorierMockRecorder) AddAuditEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBinaryRecord", reflect.TypeOf((*MockRepositorier)(nil).AddBinaryRecord), arg0, arg1, arg2, arg3, arg4, arg5)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAuditEvent", reflect.TypeOf((*MockRepositorier)(nil).AddAuditEvent), arg0, arg1, arg2)
 }
 
-// AddCard mocks base method.
-func (m *MockRepositorier) AddCard(arg0 context.Context, arg1 string, arg2, arg3, arg4, arg5, arg6 []byte, arg7 time.Time) error {
+// AddCollectionMember mocks base method.
+func (m *MockRepositorier) AddCollectionMember(arg0 context.Context, arg1 string, arg2 int64, arg3 string, arg4 storage.Role, arg5 []byte) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddCard", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+	ret := m.ctrl.Call(m, "AddCollectionMember", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddCard indicates an expected call of AddCard.
-func (mr *MockRepositorierMockRecorder) AddCard(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
+// AddCollectionMember indicates an expected call of AddCollectionMember.
+func (mr *MockRepositorierMockRecorder) AddCollectionMember(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCard", reflect.TypeOf((*MockRepositorier)(nil).AddCard), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCollectionMember", reflect.TypeOf((*MockRepositorier)(nil).AddCollectionMember), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
-// AddLoginPwd mocks base method.
-func (m *MockRepositorier) AddLoginPwd(arg0 context.Context, arg1 string, arg2, arg3, arg4, arg5 []byte, arg6 time.Time) error {
+// AddEmergencyContact mocks base method.
+func (m *MockRepositorier) AddEmergencyContact(arg0 context.Context, arg1, arg2 string, arg3 time.Duration, arg4 []byte, arg5 []storage.SharedRecord) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddLoginPwd", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret := m.ctrl.Call(m, "AddEmergencyContact", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddLoginPwd indicates an expected call of AddLoginPwd.
-func (mr *MockRepositorierMockRecorder) AddLoginPwd(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+// AddEmergencyContact indicates an expected call of AddEmergencyContact.
+func (mr *MockRepositorierMockRecorder) AddEmergencyContact(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLoginPwd", reflect.TypeOf((*MockRepositorier)(nil).AddLoginPwd), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEmergencyContact", reflect.TypeOf((*MockRepositorier)(nil).AddEmergencyContact), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
-// AddTextRecord mocks base method.
-func (m *MockRepositorier) AddTextRecord(arg0 context.Context, arg1 string, arg2, arg3, arg4 []byte, arg5 time.Time) error {
+// AddRecord mocks base method.
+func (m *MockRepositorier) AddRecord(arg0 context.Context, arg1 string, arg2 interface{}) (storage.Revision, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddTextRecord", arg0, arg1, arg2, arg3, arg4, arg5)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "AddRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(storage.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// AddTextRecord indicates an expected call of AddTextRecord.
-func (mr *MockRepositorierMockRecorder) AddTextRecord(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+// AddRecord indicates an expected call of AddRecord.
+func (mr *MockRepositorierMockRecorder) AddRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTextRecord", reflect.TypeOf((*MockRepositorier)(nil).AddTextRecord), arg0, arg1, arg2, arg3, arg4, arg5)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecord", reflect.TypeOf((*MockRepositorier)(nil).AddRecord), arg0, arg1, arg2)
 }
 
 // AuthUser mocks base method.
@@ -121,180 +123,406 @@ func (mr *MockRepositorierMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRepositorier)(nil).Close))
 }
 
-// ForceUpdateBinaryRecord mocks base method.
-func (m *MockRepositorier) ForceUpdateBinaryRecord(arg0 context.Context, arg1 string, arg2, arg3, arg4 []byte, arg5 time.Time) error {
+// CollectBlobs mocks base method.
+func (m *MockRepositorier) CollectBlobs(arg0 context.Context, arg1 time.Time) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ForceUpdateBinaryRecord", arg0, arg1, arg2, arg3, arg4, arg5)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CollectBlobs", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// ForceUpdateBinaryRecord indicates an expected call of ForceUpdateBinaryRecord.
-func (mr *MockRepositorierMockRecorder) ForceUpdateBinaryRecord(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+// CollectBlobs indicates an expected call of CollectBlobs.
+func (mr *MockRepositorierMockRecorder) CollectBlobs(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceUpdateBinaryRecord", reflect.TypeOf((*MockRepositorier)(nil).ForceUpdateBinaryRecord), arg0, arg1, arg2, arg3, arg4, arg5)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectBlobs", reflect.TypeOf((*MockRepositorier)(nil).CollectBlobs), arg0, arg1)
 }
 
-// ForceUpdateCard mocks base method.
-func (m *MockRepositorier) ForceUpdateCard(arg0 context.Context, arg1 string, arg2, arg3, arg4, arg5, arg6 []byte, arg7 time.Time) error {
+// CreateCollection mocks base method.
+func (m *MockRepositorier) CreateCollection(arg0 context.Context, arg1 string, arg2 int64, arg3 string, arg4 []byte) (storage.Collection, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ForceUpdateCard", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateCollection", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(storage.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// ForceUpdateCard indicates an expected call of ForceUpdateCard.
-func (mr *MockRepositorierMockRecorder) ForceUpdateCard(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockRepositorierMockRecorder) CreateCollection(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceUpdateCard", reflect.TypeOf((*MockRepositorier)(nil).ForceUpdateCard), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockRepositorier)(nil).CreateCollection), arg0, arg1, arg2, arg3, arg4)
 }
 
-// ForceUpdateLoginPwd mocks base method.
-func (m *MockRepositorier) ForceUpdateLoginPwd(arg0 context.Context, arg1 string, arg2, arg3, arg4, arg5 []byte, arg6 time.Time) error {
+// CreateOrganization mocks base method.
+func (m *MockRepositorier) CreateOrganization(arg0 context.Context, arg1, arg2 string) (storage.Organization, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ForceUpdateLoginPwd", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret := m.ctrl.Call(m, "CreateOrganization", arg0, arg1, arg2)
+	ret0, _ := ret[0].(storage.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockRepositorierMockRecorder) CreateOrganization(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockRepositorier)(nil).CreateOrganization), arg0, arg1, arg2)
+}
+
+// DeclineEmergencyAccess mocks base method.
+func (m *MockRepositorier) DeclineEmergencyAccess(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineEmergencyAccess", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ForceUpdateLoginPwd indicates an expected call of ForceUpdateLoginPwd.
-func (mr *MockRepositorierMockRecorder) ForceUpdateLoginPwd(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+// DeclineEmergencyAccess indicates an expected call of DeclineEmergencyAccess.
+func (mr *MockRepositorierMockRecorder) DeclineEmergencyAccess(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceUpdateLoginPwd", reflect.TypeOf((*MockRepositorier)(nil).ForceUpdateLoginPwd), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineEmergencyAccess", reflect.TypeOf((*MockRepositorier)(nil).DeclineEmergencyAccess), arg0, arg1, arg2)
 }
 
-// ForceUpdateTextRecord mocks base method.
-func (m *MockRepositorier) ForceUpdateTextRecord(arg0 context.Context, arg1 string, arg2, arg3, arg4 []byte, arg5 time.Time) error {
+// DeleteUser mocks base method.
+func (m *MockRepositorier) DeleteUser(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ForceUpdateTextRecord", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ForceUpdateTextRecord indicates an expected call of ForceUpdateTextRecord.
-func (mr *MockRepositorierMockRecorder) ForceUpdateTextRecord(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockRepositorierMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockRepositorier)(nil).DeleteUser), arg0, arg1)
+}
+
+// ForceUpdateRecord mocks base method.
+func (m *MockRepositorier) ForceUpdateRecord(arg0 context.Context, arg1 string, arg2 interface{}) (storage.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceUpdateRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(storage.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForceUpdateRecord indicates an expected call of ForceUpdateRecord.
+func (mr *MockRepositorierMockRecorder) ForceUpdateRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceUpdateRecord", reflect.TypeOf((*MockRepositorier)(nil).ForceUpdateRecord), arg0, arg1, arg2)
+}
+
+// GetAuditLog mocks base method.
+func (m *MockRepositorier) GetAuditLog(arg0 context.Context, arg1 string, arg2, arg3 time.Time) ([]storage.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]storage.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockRepositorierMockRecorder) GetAuditLog(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockRepositorier)(nil).GetAuditLog), arg0, arg1, arg2, arg3)
+}
+
+// GetDeletedRecordsAfterRevision mocks base method.
+func (m *MockRepositorier) GetDeletedRecordsAfterRevision(arg0 context.Context, arg1 string, arg2, arg3 int64) ([]storage.DeletedRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedRecordsAfterRevision", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]storage.DeletedRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedRecordsAfterRevision indicates an expected call of GetDeletedRecordsAfterRevision.
+func (mr *MockRepositorierMockRecorder) GetDeletedRecordsAfterRevision(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedRecordsAfterRevision", reflect.TypeOf((*MockRepositorier)(nil).GetDeletedRecordsAfterRevision), arg0, arg1, arg2, arg3)
+}
+
+// GetEmergencyKey mocks base method.
+func (m *MockRepositorier) GetEmergencyKey(arg0 context.Context, arg1, arg2 string, arg3 time.Time) ([]byte, []storage.SharedRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmergencyKey", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].([]storage.SharedRecord)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetEmergencyKey indicates an expected call of GetEmergencyKey.
+func (mr *MockRepositorierMockRecorder) GetEmergencyKey(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmergencyKey", reflect.TypeOf((*MockRepositorier)(nil).GetEmergencyKey), arg0, arg1, arg2, arg3)
+}
+
+// GetPublicKey mocks base method.
+func (m *MockRepositorier) GetPublicKey(arg0 context.Context, arg1 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicKey", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicKey indicates an expected call of GetPublicKey.
+func (mr *MockRepositorierMockRecorder) GetPublicKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicKey", reflect.TypeOf((*MockRepositorier)(nil).GetPublicKey), arg0, arg1)
+}
+
+// GetRecord mocks base method.
+func (m *MockRepositorier) GetRecord(arg0 context.Context, arg1 string, arg2 interface{}) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecord indicates an expected call of GetRecord.
+func (mr *MockRepositorierMockRecorder) GetRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecord", reflect.TypeOf((*MockRepositorier)(nil).GetRecord), arg0, arg1, arg2)
+}
+
+// GetRecordSize mocks base method.
+func (m *MockRepositorier) GetRecordSize(arg0 context.Context, arg1 string, arg2 interface{}) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecordSize", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecordSize indicates an expected call of GetRecordSize.
+func (mr *MockRepositorierMockRecorder) GetRecordSize(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordSize", reflect.TypeOf((*MockRepositorier)(nil).GetRecordSize), arg0, arg1, arg2)
+}
+
+// GetRecordsAfterRevision mocks base method.
+func (m *MockRepositorier) GetRecordsAfterRevision(arg0 context.Context, arg1 string, arg2 storage.RecordType, arg3, arg4 int64) ([]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecordsAfterRevision", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecordsAfterRevision indicates an expected call of GetRecordsAfterRevision.
+func (mr *MockRepositorierMockRecorder) GetRecordsAfterRevision(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordsAfterRevision", reflect.TypeOf((*MockRepositorier)(nil).GetRecordsAfterRevision), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetRevision mocks base method.
+func (m *MockRepositorier) GetRevision(arg0 context.Context, arg1 string, arg2 interface{}) (storage.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", arg0, arg1, arg2)
+	ret0, _ := ret[0].(storage.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockRepositorierMockRecorder) GetRevision(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockRepositorier)(nil).GetRevision), arg0, arg1, arg2)
+}
+
+// GetSession mocks base method.
+func (m *MockRepositorier) GetSession(arg0 context.Context, arg1 string) (storage.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(storage.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockRepositorierMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockRepositorier)(nil).GetSession), arg0, arg1)
+}
+
+// GetSyncRevision mocks base method.
+func (m *MockRepositorier) GetSyncRevision(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncRevision", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSyncRevision indicates an expected call of GetSyncRevision.
+func (mr *MockRepositorierMockRecorder) GetSyncRevision(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceUpdateTextRecord", reflect.TypeOf((*MockRepositorier)(nil).ForceUpdateTextRecord), arg0, arg1, arg2, arg3, arg4, arg5)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncRevision", reflect.TypeOf((*MockRepositorier)(nil).GetSyncRevision), arg0, arg1)
 }
 
-// GetBinaryRecord mocks base method.
-func (m *MockRepositorier) GetBinaryRecord(arg0 context.Context, arg1 string, arg2 []byte) (storage.BinaryRecord, error) {
+// GetUsage mocks base method.
+func (m *MockRepositorier) GetUsage(arg0 context.Context, arg1 string) ([]storage.Usage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBinaryRecord", arg0, arg1, arg2)
-	ret0, _ := ret[0].(storage.BinaryRecord)
+	ret := m.ctrl.Call(m, "GetUsage", arg0, arg1)
+	ret0, _ := ret[0].([]storage.Usage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBinaryRecord indicates an expected call of GetBinaryRecord.
-func (mr *MockRepositorierMockRecorder) GetBinaryRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockRepositorierMockRecorder) GetUsage(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBinaryRecord", reflect.TypeOf((*MockRepositorier)(nil).GetBinaryRecord), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockRepositorier)(nil).GetUsage), arg0, arg1)
 }
 
-// GetCard mocks base method.
-func (m *MockRepositorier) GetCard(arg0 context.Context, arg1 string, arg2 []byte) (storage.Card, error) {
+// GetUserKeys mocks base method.
+func (m *MockRepositorier) GetUserKeys(arg0 context.Context, arg1 string) (storage.UserKeys, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCard", arg0, arg1, arg2)
-	ret0, _ := ret[0].(storage.Card)
+	ret := m.ctrl.Call(m, "GetUserKeys", arg0, arg1)
+	ret0, _ := ret[0].(storage.UserKeys)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCard indicates an expected call of GetCard.
-func (mr *MockRepositorierMockRecorder) GetCard(arg0, arg1, arg2 interface{}) *gomock.Call {
+// GetUserKeys indicates an expected call of GetUserKeys.
+func (mr *MockRepositorierMockRecorder) GetUserKeys(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCard", reflect.TypeOf((*MockRepositorier)(nil).GetCard), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserKeys", reflect.TypeOf((*MockRepositorier)(nil).GetUserKeys), arg0, arg1)
 }
 
-// GetLoginPwd mocks base method.
-func (m *MockRepositorier) GetLoginPwd(arg0 context.Context, arg1 string, arg2, arg3 []byte) (storage.LoginPwd, error) {
+// ListCollectionRecords mocks base method.
+func (m *MockRepositorier) ListCollectionRecords(arg0 context.Context, arg1 string, arg2 int64) ([]storage.SharedRecord, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLoginPwd", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(storage.LoginPwd)
+	ret := m.ctrl.Call(m, "ListCollectionRecords", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]storage.SharedRecord)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLoginPwd indicates an expected call of GetLoginPwd.
-func (mr *MockRepositorierMockRecorder) GetLoginPwd(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// ListCollectionRecords indicates an expected call of ListCollectionRecords.
+func (mr *MockRepositorierMockRecorder) ListCollectionRecords(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginPwd", reflect.TypeOf((*MockRepositorier)(nil).GetLoginPwd), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollectionRecords", reflect.TypeOf((*MockRepositorier)(nil).ListCollectionRecords), arg0, arg1, arg2)
 }
 
-// GetTextRecord mocks base method.
-func (m *MockRepositorier) GetTextRecord(arg0 context.Context, arg1 string, arg2 []byte) (storage.TextRecord, error) {
+// ListCollections mocks base method.
+func (m *MockRepositorier) ListCollections(arg0 context.Context, arg1 string) ([]storage.Collection, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTextRecord", arg0, arg1, arg2)
-	ret0, _ := ret[0].(storage.TextRecord)
+	ret := m.ctrl.Call(m, "ListCollections", arg0, arg1)
+	ret0, _ := ret[0].([]storage.Collection)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTextRecord indicates an expected call of GetTextRecord.
-func (mr *MockRepositorierMockRecorder) GetTextRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListCollections indicates an expected call of ListCollections.
+func (mr *MockRepositorierMockRecorder) ListCollections(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTextRecord", reflect.TypeOf((*MockRepositorier)(nil).GetTextRecord), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollections", reflect.TypeOf((*MockRepositorier)(nil).ListCollections), arg0, arg1)
 }
 
-// GetUserBinaryRecordsAfterTime mocks base method.
-func (m *MockRepositorier) GetUserBinaryRecordsAfterTime(arg0 context.Context, arg1 string, arg2 time.Time) ([]storage.BinaryRecord, error) {
+// ListEmergencyContacts mocks base method.
+func (m *MockRepositorier) ListEmergencyContacts(arg0 context.Context, arg1 string) ([]storage.EmergencyContact, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserBinaryRecordsAfterTime", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]storage.BinaryRecord)
+	ret := m.ctrl.Call(m, "ListEmergencyContacts", arg0, arg1)
+	ret0, _ := ret[0].([]storage.EmergencyContact)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserBinaryRecordsAfterTime indicates an expected call of GetUserBinaryRecordsAfterTime.
-func (mr *MockRepositorierMockRecorder) GetUserBinaryRecordsAfterTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListEmergencyContacts indicates an expected call of ListEmergencyContacts.
+func (mr *MockRepositorierMockRecorder) ListEmergencyContacts(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBinaryRecordsAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserBinaryRecordsAfterTime), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmergencyContacts", reflect.TypeOf((*MockRepositorier)(nil).ListEmergencyContacts), arg0, arg1)
 }
 
-// GetUserCardsAfterTime mocks base method.
-func (m *MockRepositorier) GetUserCardsAfterTime(arg0 context.Context, arg1 string, arg2 time.Time) ([]storage.Card, error) {
+// ListRecords mocks base method.
+func (m *MockRepositorier) ListRecords(arg0 context.Context, arg1 string, arg2 storage.ListFilter) ([]storage.RecordInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserCardsAfterTime", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]storage.Card)
+	ret := m.ctrl.Call(m, "ListRecords", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]storage.RecordInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserCardsAfterTime indicates an expected call of GetUserCardsAfterTime.
-func (mr *MockRepositorierMockRecorder) GetUserCardsAfterTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListRecords indicates an expected call of ListRecords.
+func (mr *MockRepositorierMockRecorder) ListRecords(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCardsAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserCardsAfterTime), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecords", reflect.TypeOf((*MockRepositorier)(nil).ListRecords), arg0, arg1, arg2)
 }
 
-// GetUserLoginsPwdsAfterTime mocks base method.
-func (m *MockRepositorier) GetUserLoginsPwdsAfterTime(arg0 context.Context, arg1 string, arg2 time.Time) ([]storage.LoginPwd, error) {
+// ListUsers mocks base method.
+func (m *MockRepositorier) ListUsers(arg0 context.Context) ([]storage.UserInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserLoginsPwdsAfterTime", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]storage.LoginPwd)
+	ret := m.ctrl.Call(m, "ListUsers", arg0)
+	ret0, _ := ret[0].([]storage.UserInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserLoginsPwdsAfterTime indicates an expected call of GetUserLoginsPwdsAfterTime.
-func (mr *MockRepositorierMockRecorder) GetUserLoginsPwdsAfterTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockRepositorierMockRecorder) ListUsers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockRepositorier)(nil).ListUsers), arg0)
+}
+
+// ListVersions mocks base method.
+func (m *MockRepositorier) ListVersions(arg0 context.Context, arg1 string, arg2 interface{}) ([]storage.Version, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]storage.Version)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockRepositorierMockRecorder) ListVersions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockRepositorier)(nil).ListVersions), arg0, arg1, arg2)
+}
+
+// LogoutUser mocks base method.
+func (m *MockRepositorier) LogoutUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutUser indicates an expected call of LogoutUser.
+func (mr *MockRepositorierMockRecorder) LogoutUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutUser", reflect.TypeOf((*MockRepositorier)(nil).LogoutUser), arg0, arg1)
+}
+
+// MoveRecordToCollection mocks base method.
+func (m *MockRepositorier) MoveRecordToCollection(arg0 context.Context, arg1 string, arg2 int64, arg3 interface{}, arg4 storage.SharedRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveRecordToCollection", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveRecordToCollection indicates an expected call of MoveRecordToCollection.
+func (mr *MockRepositorierMockRecorder) MoveRecordToCollection(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLoginsPwdsAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserLoginsPwdsAfterTime), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveRecordToCollection", reflect.TypeOf((*MockRepositorier)(nil).MoveRecordToCollection), arg0, arg1, arg2, arg3, arg4)
 }
 
-// GetUserTextRecordsAfterTime mocks base method.
-func (m *MockRepositorier) GetUserTextRecordsAfterTime(arg0 context.Context, arg1 string, arg2 time.Time) ([]storage.TextRecord, error) {
+// NewBinaryUpload mocks base method.
+func (m *MockRepositorier) NewBinaryUpload(arg0 context.Context) (storage.BinaryUpload, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserTextRecordsAfterTime", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]storage.TextRecord)
+	ret := m.ctrl.Call(m, "NewBinaryUpload", arg0)
+	ret0, _ := ret[0].(storage.BinaryUpload)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserTextRecordsAfterTime indicates an expected call of GetUserTextRecordsAfterTime.
-func (mr *MockRepositorierMockRecorder) GetUserTextRecordsAfterTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+// NewBinaryUpload indicates an expected call of NewBinaryUpload.
+func (mr *MockRepositorierMockRecorder) NewBinaryUpload(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTextRecordsAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserTextRecordsAfterTime), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewBinaryUpload", reflect.TypeOf((*MockRepositorier)(nil).NewBinaryUpload), arg0)
 }
 
 // RegUser mocks base method.
@@ -310,3 +538,132 @@ func (mr *MockRepositorierMockRecorder) RegUser(arg0, arg1, arg2 interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegUser", reflect.TypeOf((*MockRepositorier)(nil).RegUser), arg0, arg1, arg2)
 }
+
+// RemoveEmergencyContact mocks base method.
+func (m *MockRepositorier) RemoveEmergencyContact(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveEmergencyContact", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveEmergencyContact indicates an expected call of RemoveEmergencyContact.
+func (mr *MockRepositorierMockRecorder) RemoveEmergencyContact(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveEmergencyContact", reflect.TypeOf((*MockRepositorier)(nil).RemoveEmergencyContact), arg0, arg1, arg2)
+}
+
+// RequestEmergencyAccess mocks base method.
+func (m *MockRepositorier) RequestEmergencyAccess(arg0 context.Context, arg1, arg2 string, arg3 time.Time) (storage.EmergencyContact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmergencyAccess", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(storage.EmergencyContact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestEmergencyAccess indicates an expected call of RequestEmergencyAccess.
+func (mr *MockRepositorierMockRecorder) RequestEmergencyAccess(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmergencyAccess", reflect.TypeOf((*MockRepositorier)(nil).RequestEmergencyAccess), arg0, arg1, arg2, arg3)
+}
+
+// RestoreVersion mocks base method.
+func (m *MockRepositorier) RestoreVersion(arg0 context.Context, arg1 string, arg2 interface{}, arg3 int64, arg4 time.Time) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreVersion", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreVersion indicates an expected call of RestoreVersion.
+func (mr *MockRepositorierMockRecorder) RestoreVersion(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVersion", reflect.TypeOf((*MockRepositorier)(nil).RestoreVersion), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SaveBinaryUpload mocks base method.
+func (m *MockRepositorier) SaveBinaryUpload(arg0 context.Context, arg1 string, arg2 storage.BinaryUpload, arg3 storage.BinaryRecord, arg4 bool) (storage.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBinaryUpload", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(storage.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveBinaryUpload indicates an expected call of SaveBinaryUpload.
+func (mr *MockRepositorierMockRecorder) SaveBinaryUpload(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBinaryUpload", reflect.TypeOf((*MockRepositorier)(nil).SaveBinaryUpload), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SetUserDisabled mocks base method.
+func (m *MockRepositorier) SetUserDisabled(arg0 context.Context, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserDisabled", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserDisabled indicates an expected call of SetUserDisabled.
+func (mr *MockRepositorierMockRecorder) SetUserDisabled(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserDisabled", reflect.TypeOf((*MockRepositorier)(nil).SetUserDisabled), arg0, arg1, arg2)
+}
+
+// SetUserKeys mocks base method.
+func (m *MockRepositorier) SetUserKeys(arg0 context.Context, arg1 string, arg2 storage.UserKeys) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserKeys", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserKeys indicates an expected call of SetUserKeys.
+func (mr *MockRepositorierMockRecorder) SetUserKeys(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserKeys", reflect.TypeOf((*MockRepositorier)(nil).SetUserKeys), arg0, arg1, arg2)
+}
+
+// Stats mocks base method.
+func (m *MockRepositorier) Stats() sql.DBStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(sql.DBStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockRepositorierMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockRepositorier)(nil).Stats))
+}
+
+// UpdateEmergencyRecords mocks base method.
+func (m *MockRepositorier) UpdateEmergencyRecords(arg0 context.Context, arg1, arg2 string, arg3 []byte, arg4 []storage.SharedRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmergencyRecords", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmergencyRecords indicates an expected call of UpdateEmergencyRecords.
+func (mr *MockRepositorierMockRecorder) UpdateEmergencyRecords(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmergencyRecords", reflect.TypeOf((*MockRepositorier)(nil).UpdateEmergencyRecords), arg0, arg1, arg2, arg3, arg4)
+}
+
+// WatchChanges mocks base method.
+func (m *MockRepositorier) WatchChanges(arg0 context.Context, arg1 string) <-chan storage.Change {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchChanges", arg0, arg1)
+	ret0, _ := ret[0].(<-chan storage.Change)
+	return ret0
+}
+
+// WatchChanges indicates an expected call of WatchChanges.
+func (mr *MockRepositorierMockRecorder) WatchChanges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchChanges", reflect.TypeOf((*MockRepositorier)(nil).WatchChanges), arg0, arg1)
+}
@@ -0,0 +1,387 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Julia-ivv/info-keeper.git/internal/proto/pb (interfaces: InfoKeeper_UploadBinaryServer,InfoKeeper_DownloadBinaryServer,InfoKeeper_WatchChangesServer)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	metadata "google.golang.org/grpc/metadata"
+
+	proto "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// MockInfoKeeper_UploadBinaryServer is a mock of InfoKeeper_UploadBinaryServer interface.
+type MockInfoKeeper_UploadBinaryServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockInfoKeeper_UploadBinaryServerMockRecorder
+}
+
+// MockInfoKeeper_UploadBinaryServerMockRecorder is the mock recorder for MockInfoKeeper_UploadBinaryServer.
+type MockInfoKeeper_UploadBinaryServerMockRecorder struct {
+	mock *MockInfoKeeper_UploadBinaryServer
+}
+
+// NewMockInfoKeeper_UploadBinaryServer creates a new mock instance.
+func NewMockInfoKeeper_UploadBinaryServer(ctrl *gomock.Controller) *MockInfoKeeper_UploadBinaryServer {
+	mock := &MockInfoKeeper_UploadBinaryServer{ctrl: ctrl}
+	mock.recorder = &MockInfoKeeper_UploadBinaryServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInfoKeeper_UploadBinaryServer) EXPECT() *MockInfoKeeper_UploadBinaryServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockInfoKeeper_UploadBinaryServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockInfoKeeper_UploadBinaryServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockInfoKeeper_UploadBinaryServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockInfoKeeper_UploadBinaryServer) Recv() (*proto.UploadBinaryRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*proto.UploadBinaryRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockInfoKeeper_UploadBinaryServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockInfoKeeper_UploadBinaryServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockInfoKeeper_UploadBinaryServer) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockInfoKeeper_UploadBinaryServerMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockInfoKeeper_UploadBinaryServer)(nil).RecvMsg), arg0)
+}
+
+// SendAndClose mocks base method.
+func (m *MockInfoKeeper_UploadBinaryServer) SendAndClose(arg0 *proto.UploadBinaryResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendAndClose", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAndClose indicates an expected call of SendAndClose.
+func (mr *MockInfoKeeper_UploadBinaryServerMockRecorder) SendAndClose(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAndClose", reflect.TypeOf((*MockInfoKeeper_UploadBinaryServer)(nil).SendAndClose), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockInfoKeeper_UploadBinaryServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockInfoKeeper_UploadBinaryServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockInfoKeeper_UploadBinaryServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockInfoKeeper_UploadBinaryServer) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockInfoKeeper_UploadBinaryServerMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockInfoKeeper_UploadBinaryServer)(nil).SendMsg), arg0)
+}
+
+// SetHeader mocks base method.
+func (m *MockInfoKeeper_UploadBinaryServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockInfoKeeper_UploadBinaryServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockInfoKeeper_UploadBinaryServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockInfoKeeper_UploadBinaryServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockInfoKeeper_UploadBinaryServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockInfoKeeper_UploadBinaryServer)(nil).SetTrailer), arg0)
+}
+
+// MockInfoKeeper_DownloadBinaryServer is a mock of InfoKeeper_DownloadBinaryServer interface.
+type MockInfoKeeper_DownloadBinaryServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockInfoKeeper_DownloadBinaryServerMockRecorder
+}
+
+// MockInfoKeeper_DownloadBinaryServerMockRecorder is the mock recorder for MockInfoKeeper_DownloadBinaryServer.
+type MockInfoKeeper_DownloadBinaryServerMockRecorder struct {
+	mock *MockInfoKeeper_DownloadBinaryServer
+}
+
+// NewMockInfoKeeper_DownloadBinaryServer creates a new mock instance.
+func NewMockInfoKeeper_DownloadBinaryServer(ctrl *gomock.Controller) *MockInfoKeeper_DownloadBinaryServer {
+	mock := &MockInfoKeeper_DownloadBinaryServer{ctrl: ctrl}
+	mock.recorder = &MockInfoKeeper_DownloadBinaryServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInfoKeeper_DownloadBinaryServer) EXPECT() *MockInfoKeeper_DownloadBinaryServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockInfoKeeper_DownloadBinaryServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryServer) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockInfoKeeper_DownloadBinaryServerMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryServer)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryServer) Send(arg0 *proto.DownloadBinaryResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockInfoKeeper_DownloadBinaryServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockInfoKeeper_DownloadBinaryServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryServer) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockInfoKeeper_DownloadBinaryServerMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryServer)(nil).SendMsg), arg0)
+}
+
+// SetHeader mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockInfoKeeper_DownloadBinaryServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockInfoKeeper_DownloadBinaryServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryServer)(nil).SetTrailer), arg0)
+}
+
+// MockInfoKeeper_WatchChangesServer is a mock of InfoKeeper_WatchChangesServer interface.
+type MockInfoKeeper_WatchChangesServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockInfoKeeper_WatchChangesServerMockRecorder
+}
+
+// MockInfoKeeper_WatchChangesServerMockRecorder is the mock recorder for MockInfoKeeper_WatchChangesServer.
+type MockInfoKeeper_WatchChangesServerMockRecorder struct {
+	mock *MockInfoKeeper_WatchChangesServer
+}
+
+// NewMockInfoKeeper_WatchChangesServer creates a new mock instance.
+func NewMockInfoKeeper_WatchChangesServer(ctrl *gomock.Controller) *MockInfoKeeper_WatchChangesServer {
+	mock := &MockInfoKeeper_WatchChangesServer{ctrl: ctrl}
+	mock.recorder = &MockInfoKeeper_WatchChangesServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInfoKeeper_WatchChangesServer) EXPECT() *MockInfoKeeper_WatchChangesServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockInfoKeeper_WatchChangesServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockInfoKeeper_WatchChangesServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockInfoKeeper_WatchChangesServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m *MockInfoKeeper_WatchChangesServer) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockInfoKeeper_WatchChangesServerMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockInfoKeeper_WatchChangesServer)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockInfoKeeper_WatchChangesServer) Send(arg0 *proto.ChangeEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockInfoKeeper_WatchChangesServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockInfoKeeper_WatchChangesServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockInfoKeeper_WatchChangesServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockInfoKeeper_WatchChangesServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockInfoKeeper_WatchChangesServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockInfoKeeper_WatchChangesServer) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockInfoKeeper_WatchChangesServerMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockInfoKeeper_WatchChangesServer)(nil).SendMsg), arg0)
+}
+
+// SetHeader mocks base method.
+func (m *MockInfoKeeper_WatchChangesServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockInfoKeeper_WatchChangesServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockInfoKeeper_WatchChangesServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockInfoKeeper_WatchChangesServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockInfoKeeper_WatchChangesServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockInfoKeeper_WatchChangesServer)(nil).SetTrailer), arg0)
+}
@@ -0,0 +1,525 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BlobStore хранит зашифрованное содержимое бинарных записей вне БД.
+// В БД остаются только ссылка на блоб, его контрольная сумма и размер.
+type BlobStore interface {
+	// Put сохраняет данные и возвращает ссылку на них.
+	Put(ctx context.Context, data []byte) (ref string, err error)
+	// Create начинает сохранение данных, передаваемых частями.
+	Create(ctx context.Context) (w BlobWriter, err error)
+	// Get возвращает данные по ссылке. Если блоба нет, возвращается ошибка EmptyResult.
+	Get(ctx context.Context, ref string) (data []byte, err error)
+	// Delete удаляет блоб, если он не сохранялся после времени before.
+	Delete(ctx context.Context, ref string, before time.Time) error
+	// List возвращает все блобы хранилища.
+	List(ctx context.Context) (blobs []BlobInfo, err error)
+}
+
+// BlobWriter сохраняет данные блоба, передаваемые частями.
+type BlobWriter interface {
+	io.Writer
+	// Commit завершает сохранение и возвращает ссылку на данные.
+	Commit() (ref string, err error)
+	// Close удаляет данные, сохранение которых не завершено методом Commit.
+	Close() error
+}
+
+// BlobInfo хранит ссылку на блоб и время его последнего сохранения.
+type BlobInfo struct {
+	Ref     string
+	ModTime time.Time
+}
+
+// blob хранит ссылку на содержимое записи в BlobStore.
+// Пустая ссылка означает, что содержимое хранится в самой записи.
+type blob struct {
+	ref    string
+	sha256 string
+	size   int64
+}
+
+// blobName - имя файла блоба: шестнадцатеричная сумма SHA-256 содержимого.
+var blobName = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// FSBlobStore хранит блобы в локальном каталоге по адресу их содержимого:
+// файл с суммой SHA-256 abcd... лежит в подкаталоге ab, одинаковые данные хранятся один раз.
+type FSBlobStore struct {
+	dir string
+	// mu упорядочивает завершение сохранения и удаление блобов: иначе Delete может удалить
+	// блоб, время изменения которого Commit обновил после проверки в Delete.
+	mu sync.Mutex
+}
+
+// NewFSBlobStore создает хранилище блобов в каталоге dir, создавая каталог при необходимости.
+func NewFSBlobStore(dir string) (*FSBlobStore, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, err
+	}
+	return &FSBlobStore{dir: dir}, nil
+}
+
+// path возвращает путь к файлу блоба и проверяет ссылку,
+// чтобы она не указывала за пределы каталога хранилища.
+func (s *FSBlobStore) path(ref string) (string, error) {
+	if !blobName.MatchString(ref) {
+		return "", NewStorError(EmptyValues, fmt.Errorf("invalid blob reference %q", ref))
+	}
+	return filepath.Join(s.dir, ref[:2], ref), nil
+}
+
+// Put реализует BlobStore. Ссылкой служит сумма SHA-256 данных.
+func (s *FSBlobStore) Put(ctx context.Context, data []byte) (string, error) {
+	w, err := s.Create(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer w.Close()
+
+	_, err = w.Write(data)
+	if err != nil {
+		return "", err
+	}
+	return w.Commit()
+}
+
+// Create реализует BlobStore. Данные пишутся во временный файл в каталоге хранилища,
+// который при Commit переименовывается по сумме SHA-256 содержимого.
+func (s *FSBlobStore) Create(ctx context.Context) (BlobWriter, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	return &fsBlobWriter{store: s, f: f, hash: sha256.New()}, nil
+}
+
+// digest вычисляет контрольную сумму записанных в него данных.
+type digest interface {
+	io.Writer
+	Sum(b []byte) []byte
+}
+
+// fsBlobWriter сохраняет блоб FSBlobStore во временный файл.
+type fsBlobWriter struct {
+	store  *FSBlobStore
+	f      *os.File
+	hash   digest
+	closed bool
+}
+
+// Write реализует io.Writer.
+func (w *fsBlobWriter) Write(p []byte) (int, error) {
+	n, err := w.f.Write(p)
+	w.hash.Write(p[:n])
+	return n, err
+}
+
+// Commit реализует BlobWriter. Если такие данные уже сохранены, у файла обновляется
+// время изменения, чтобы сборщик мусора не удалил его до сохранения ссылки в БД.
+func (w *fsBlobWriter) Commit() (string, error) {
+	if w.closed {
+		return "", errors.New("blob writer is closed")
+	}
+	w.closed = true
+	defer os.Remove(w.f.Name())
+
+	err := w.f.Sync()
+	if closeErr := w.f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	ref := hex.EncodeToString(w.hash.Sum(nil))
+	path, err := w.store.path(ref)
+	if err != nil {
+		return "", err
+	}
+
+	w.store.mu.Lock()
+	defer w.store.mu.Unlock()
+	now := time.Now()
+	err = os.Chtimes(path, now, now)
+	if err == nil {
+		return ref, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return "", err
+	}
+	return ref, os.Rename(w.f.Name(), path)
+}
+
+// Close реализует BlobWriter.
+func (w *fsBlobWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	err := w.f.Close()
+	if removeErr := os.Remove(w.f.Name()); err == nil && !errors.Is(removeErr, fs.ErrNotExist) {
+		err = removeErr
+	}
+	return err
+}
+
+// Get реализует BlobStore.
+func (s *FSBlobStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	path, err := s.path(ref)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, NewStorError(EmptyResult, err)
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// Delete реализует BlobStore. Отсутствующий блоб не считается ошибкой.
+func (s *FSBlobStore) Delete(ctx context.Context, ref string, before time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path, err := s.path(ref)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if !info.ModTime().Before(before) {
+		return nil
+	}
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List реализует BlobStore. Незавершенные временные файлы пропускаются.
+func (s *FSBlobStore) List(ctx context.Context) (blobs []BlobInfo, err error) {
+	err = filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || !blobName.MatchString(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, BlobInfo{Ref: d.Name(), ModTime: info.ModTime()})
+		return nil
+	})
+	return blobs, err
+}
+
+// putBlob переносит содержимое бинарной записи в хранилище блобов.
+// Если хранилище не задано, содержимое остается в записи.
+func (db *DBStorage) putBlob(ctx context.Context, r BinaryRecord) (BinaryRecord, error) {
+	if db.blobs == nil {
+		return r, nil
+	}
+	ref, err := db.blobs.Put(ctx, r.Data)
+	if err != nil {
+		return r, err
+	}
+	sum := sha256.Sum256(r.Data)
+	r.blob = blob{ref: ref, sha256: hex.EncodeToString(sum[:]), size: int64(len(r.Data))}
+	r.Data = []byte{}
+	return r, nil
+}
+
+// binaryUpload принимает содержимое бинарной записи частями. Если задано хранилище блобов,
+// части сразу записываются в него, иначе накапливаются в памяти.
+type binaryUpload struct {
+	blob BlobWriter
+	buf  bytes.Buffer
+	hash digest
+	size int64
+}
+
+// Write реализует io.Writer.
+func (u *binaryUpload) Write(p []byte) (n int, err error) {
+	if u.blob != nil {
+		n, err = u.blob.Write(p)
+	} else {
+		n, err = u.buf.Write(p)
+	}
+	u.hash.Write(p[:n])
+	u.size += int64(n)
+	return n, err
+}
+
+// Size реализует BinaryUpload.
+func (u *binaryUpload) Size() int64 {
+	return u.size
+}
+
+// Sum реализует BinaryUpload.
+func (u *binaryUpload) Sum() []byte {
+	return u.hash.Sum(nil)
+}
+
+// Close реализует BinaryUpload.
+func (u *binaryUpload) Close() error {
+	if u.blob != nil {
+		return u.blob.Close()
+	}
+	return nil
+}
+
+// NewBinaryUpload начинает загрузку содержимого бинарной записи частями.
+func (db *DBStorage) NewBinaryUpload(ctx context.Context) (BinaryUpload, error) {
+	u := &binaryUpload{hash: sha256.New()}
+	if db.blobs != nil {
+		w, err := db.blobs.Create(ctx)
+		if err != nil {
+			return nil, err
+		}
+		u.blob = w
+	}
+	return u, nil
+}
+
+// SaveBinaryUpload добавляет бинарную запись r с содержимым, загруженным через u.
+// Если force равен true, существующая запись обновляется без проверки времени изменения.
+// Возвращается ревизия сохраненной записи.
+func (db *DBStorage) SaveBinaryUpload(ctx context.Context, userLogin string, u BinaryUpload,
+	r BinaryRecord, force bool) (revision Revision, err error) {
+	upload, ok := u.(*binaryUpload)
+	if !ok {
+		return Revision{}, fmt.Errorf("unsupported binary upload %T", u)
+	}
+	if upload.blob != nil {
+		ref, err := upload.blob.Commit()
+		if err != nil {
+			return Revision{}, err
+		}
+		r.blob = blob{ref: ref, sha256: hex.EncodeToString(upload.Sum()), size: upload.size}
+		r.Data = []byte{}
+	} else {
+		r.Data = nonNil(upload.buf.Bytes())
+	}
+
+	if force {
+		return forceUpdateRecord(ctx, db, binariesTable, userLogin, r)
+	}
+	return addRecord(ctx, db, binariesTable, userLogin, r, r.TimeStamp)
+}
+
+// loadBlob загружает содержимое бинарной записи из хранилища блобов и проверяет его сумму.
+func (db *DBStorage) loadBlob(ctx context.Context, r *BinaryRecord) error {
+	if r.blob.ref == "" {
+		return nil
+	}
+	if db.blobs == nil {
+		return errors.New("binary record is stored in a blob store, but no blob store is configured")
+	}
+	data, err := db.blobs.Get(ctx, r.blob.ref)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	want, err := hex.DecodeString(r.blob.sha256)
+	if err != nil || !bytes.Equal(sum[:], want) {
+		return fmt.Errorf("blob %s checksum mismatch", r.blob.ref)
+	}
+	r.Data = data
+	r.blob = blob{}
+	return nil
+}
+
+// legacyBinaryTables - таблицы, строки которых могут хранить содержимое бинарных записей в БД,
+// и ключевые столбцы этих строк. Ключ записи включает номер синхронизации,
+// чтобы не заменить содержимое, измененное во время переноса.
+var legacyBinaryTables = []struct {
+	name string
+	key  []string
+}{
+	{binariesTable.Name, []string{"user_id", "prompt", "sync_revision"}},
+	{binariesTable.Name + "_history", []string{"version_id"}},
+}
+
+// moveBlobs переносит в хранилище блобов содержимое бинарных записей и их предыдущих версий,
+// сохраненных в БД до включения хранилища.
+func (db *DBStorage) moveBlobs(ctx context.Context) error {
+	if db.blobs == nil {
+		return nil
+	}
+	for _, t := range legacyBinaryTables {
+		keys, err := db.legacyBlobKeys(ctx, t.name, t.key)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			err = db.moveBlob(ctx, t.name, t.key, key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// keyCond возвращает условие равенства столбцов columns параметрам запроса, начиная с $first.
+func keyCond(columns []string, first int) string {
+	cond := make([]string, len(columns))
+	for i, col := range columns {
+		cond[i] = fmt.Sprintf("%s = $%d", col, first+i)
+	}
+	return strings.Join(cond, " AND ")
+}
+
+// legacyBlobKeys возвращает значения ключевых столбцов columns строк таблицы name,
+// содержимое которых хранится в БД.
+func (db *DBStorage) legacyBlobKeys(ctx context.Context, name string, columns []string) (keys [][]any, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
+	defer cancel()
+
+	rows, err := db.dbHandle.QueryContext(ctx,
+		"SELECT "+strings.Join(columns, ", ")+" FROM "+name+" WHERE blob_ref = '' AND length(data) > 0")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		err = rows.Scan(dest...)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, values)
+	}
+	return keys, rows.Err()
+}
+
+// moveBlob переносит в хранилище блобов содержимое строки таблицы name
+// со значениями key ключевых столбцов columns. Если строка изменилась после выбора ключей,
+// она не обновляется.
+func (db *DBStorage) moveBlob(ctx context.Context, name string, columns []string, key []any) error {
+	ctx, cancel := db.bulkCtx(ctx)
+	defer cancel()
+
+	var data []byte
+	err := db.dbHandle.QueryRowContext(ctx,
+		"SELECT data FROM "+name+" WHERE blob_ref = '' AND "+keyCond(columns, 1), key...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	r, err := db.putBlob(ctx, BinaryRecord{Data: data})
+	if err != nil {
+		return err
+	}
+	_, err = db.dbHandle.ExecContext(ctx,
+		"UPDATE "+name+" SET data = $1, blob_ref = $2, blob_sha256 = $3, blob_size = $4 WHERE blob_ref = '' AND "+
+			keyCond(columns, 5),
+		append([]any{r.Data, r.blob.ref, r.blob.sha256, r.blob.size}, key...)...)
+	return err
+}
+
+// CollectBlobs удаляет из хранилища блобы, на которые не ссылаются бинарные записи
+// и их предыдущие версии, и возвращает количество удаленных блобов.
+// Удаляются только блобы, сохраненные до времени before: более новые могут принадлежать
+// записям, добавление которых еще не завершено.
+func (db *DBStorage) CollectBlobs(ctx context.Context, before time.Time) (removed int, err error) {
+	if db.blobs == nil {
+		return 0, nil
+	}
+	ctx, cancel := db.bulkCtx(ctx)
+	defer cancel()
+
+	rows, err := db.dbHandle.QueryContext(ctx,
+		`SELECT blob_ref FROM binary_data WHERE blob_ref != ''
+		UNION
+		SELECT blob_ref FROM binary_data_history WHERE blob_ref != ''`)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	used := make(map[string]struct{})
+	for rows.Next() {
+		var ref string
+		err = rows.Scan(&ref)
+		if err != nil {
+			return 0, err
+		}
+		used[ref] = struct{}{}
+	}
+	err = rows.Err()
+	if err != nil {
+		return 0, err
+	}
+
+	blobs, err := db.blobs.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, b := range blobs {
+		if _, ok := used[b.Ref]; ok || !b.ModTime.Before(before) {
+			continue
+		}
+		err = db.blobs.Delete(ctx, b.Ref, before)
+		if err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
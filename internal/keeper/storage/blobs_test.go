@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSBlobStore(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "blobs")
+	s, err := NewFSBlobStore(dir)
+	require.NoError(t, err)
+
+	data := []byte("encrypted payload")
+	sum := sha256.Sum256(data)
+	ref, err := s.Put(ctx, data)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), ref)
+	assert.FileExists(t, filepath.Join(dir, ref[:2], ref))
+
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, ref[:2], ref), old, old))
+	again, err := s.Put(ctx, data)
+	require.NoError(t, err)
+	assert.Equal(t, ref, again)
+
+	got, err := s.Get(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	blobs, err := s.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, blobs, 1)
+	assert.Equal(t, ref, blobs[0].Ref)
+	assert.True(t, blobs[0].ModTime.After(old), "repeated Put must refresh the blob")
+
+	_, err = s.Get(ctx, "../../etc/passwd")
+	assertStorErr(t, err, true, EmptyValues)
+
+	require.NoError(t, s.Delete(ctx, ref, old))
+	_, err = s.Get(ctx, ref)
+	require.NoError(t, err)
+	require.NoError(t, s.Delete(ctx, ref, time.Now().Add(time.Minute)))
+	_, err = s.Get(ctx, ref)
+	assertStorErr(t, err, true, EmptyResult)
+	assert.NoError(t, s.Delete(ctx, ref, time.Now()))
+}
+
+func TestFSBlobStore_DeleteCommitRace(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "blobs")
+	s, err := NewFSBlobStore(dir)
+	require.NoError(t, err)
+
+	data := []byte("encrypted payload")
+	old := time.Now().Add(-time.Hour)
+	for i := 0; i < 200; i++ {
+		ref, err := s.Put(ctx, data)
+		require.NoError(t, err)
+		path := filepath.Join(dir, ref[:2], ref)
+		require.NoError(t, os.Chtimes(path, old, old))
+
+		before := time.Now()
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.Delete(ctx, ref, before))
+		}()
+		_, err = s.Put(ctx, data)
+		require.NoError(t, err)
+		wg.Wait()
+
+		// Сохраненный блоб не удаляется, в каком бы порядке ни выполнились Delete и Put.
+		require.FileExists(t, path)
+	}
+}
+
+func TestFSBlobStore_Create(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "blobs")
+	s, err := NewFSBlobStore(dir)
+	require.NoError(t, err)
+
+	w, err := s.Create(ctx)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("encrypted "))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("payload"))
+	require.NoError(t, err)
+	ref, err := w.Commit()
+	require.NoError(t, err)
+	assert.NoError(t, w.Close())
+	sum := sha256.Sum256([]byte("encrypted payload"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), ref)
+	got, err := s.Get(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("encrypted payload"), got)
+
+	aborted, err := s.Create(ctx)
+	require.NoError(t, err)
+	_, err = aborted.Write([]byte("partial"))
+	require.NoError(t, err)
+	require.NoError(t, aborted.Close())
+	_, err = aborted.Commit()
+	assert.Error(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary files must be removed")
+	assert.Equal(t, ref[:2], entries[0].Name())
+}
+
+func TestDBStorage_loadBlob(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFSBlobStore(t.TempDir())
+	require.NoError(t, err)
+	db := &DBStorage{blobs: s}
+
+	r, err := db.putBlob(ctx, BinaryRecord{Prompt: []byte("p"), Data: []byte("data")})
+	require.NoError(t, err)
+	assert.Empty(t, r.Data)
+	assert.Equal(t, int64(4), r.blob.size)
+
+	loaded := r
+	require.NoError(t, db.loadBlob(ctx, &loaded))
+	assert.Equal(t, BinaryRecord{Prompt: []byte("p"), Data: []byte("data")}, loaded)
+
+	broken := r
+	broken.blob.sha256 = hex.EncodeToString(make([]byte, sha256.Size))
+	assert.Error(t, db.loadBlob(ctx, &broken))
+
+	assert.Error(t, (&DBStorage{}).loadBlob(ctx, &r))
+
+	inline := BinaryRecord{Data: []byte("inline")}
+	require.NoError(t, (&DBStorage{}).loadBlob(ctx, &inline))
+	assert.Equal(t, []byte("inline"), inline.Data)
+}
+
+func TestCollectBlobs(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	s, err := NewFSBlobStore(t.TempDir())
+	require.NoError(t, err)
+	used, err := s.Put(ctx, []byte("used"))
+	require.NoError(t, err)
+	unused, err := s.Put(ctx, []byte("unused"))
+	require.NoError(t, err)
+	testDB := DBStorage{dbHandle: db, blobs: s}
+
+	mock.ExpectQuery("SELECT blob_ref FROM binary_data").
+		WillReturnRows(sqlmock.NewRows([]string{"blob_ref"}).AddRow(used))
+	removed, err := testDB.CollectBlobs(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed, "recent blobs must be kept")
+
+	mock.ExpectQuery("SELECT blob_ref FROM binary_data").
+		WillReturnRows(sqlmock.NewRows([]string{"blob_ref"}).AddRow(used))
+	removed, err = testDB.CollectBlobs(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	_, err = s.Get(ctx, used)
+	assert.NoError(t, err)
+	_, err = s.Get(ctx, unused)
+	assertStorErr(t, err, true, EmptyResult)
+
+	mock.ExpectQuery("SELECT blob_ref FROM binary_data").WillReturnError(errTest)
+	_, err = testDB.CollectBlobs(ctx, time.Now())
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	removed, err = (&DBStorage{}).CollectBlobs(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Zero(t, removed)
+}
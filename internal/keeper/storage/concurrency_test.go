@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPostgresDSNEnv - переменная окружения со строкой подключения к тестовой БД PostgreSQL.
+// Если она не задана, тесты параллельной записи выполняются только для SQLite.
+const testPostgresDSNEnv = "KEEPER_TEST_DATABASE_DSN"
+
+// concurrentWriters - количество горутин, одновременно изменяющих одну запись.
+const concurrentWriters = 16
+
+// forEachBackend запускает тест для встроенной БД SQLite и, если задана
+// строка подключения testPostgresDSNEnv, для PostgreSQL.
+// Запросы, на которых основана атомарность записи в PostgreSQL, без БД
+// проверяет TestPostgresAtomicWriteQueries.
+// Каждый запуск получает отдельного пользователя, который удаляется после теста.
+func forEachBackend(t *testing.T, retention int, test func(t *testing.T, db *DBStorage, userLogin string)) {
+	backends := []struct {
+		name  string
+		DBURI func(t *testing.T) string
+	}{
+		{
+			name: "sqlite",
+			DBURI: func(t *testing.T) string {
+				return "sqlite://" + filepath.Join(t.TempDir(), "keeper.db")
+			},
+		},
+		{
+			name: "postgres",
+			DBURI: func(t *testing.T) string {
+				dsn := os.Getenv(testPostgresDSNEnv)
+				if dsn == "" {
+					t.Skipf("%s is not set", testPostgresDSNEnv)
+				}
+				return dsn
+			},
+		},
+	}
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			db, err := NewDBStorage(b.DBURI(t), retention, Quota{}, Timeouts{Query: time.Minute, Bulk: time.Minute}, nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { db.Close() })
+
+			ctx := context.Background()
+			userLogin := fmt.Sprintf("concurrency-%d", time.Now().UnixNano())
+			require.NoError(t, db.RegUser(ctx, userLogin, "pwd"))
+			t.Cleanup(func() { assert.NoError(t, db.DeleteUser(ctx, userLogin)) })
+
+			test(t, db, userLogin)
+		})
+	}
+}
+
+// runConcurrently вызывает write из concurrentWriters горутин одновременно
+// и возвращает количество успешных вызовов. Допустима только ошибка конфликта версий.
+func runConcurrently(t *testing.T, write func(i int) error) int {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		success int
+	)
+	start := make(chan struct{})
+	for i := 0; i < concurrentWriters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			err := write(i)
+			var storErr *StorErr
+			if err != nil && !(errors.As(err, &storErr) && storErr.ErrType == ExistsDataNewerVersion) {
+				t.Errorf("writer %d: %v", i, err)
+				return
+			}
+			if err == nil {
+				mu.Lock()
+				success++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return success
+}
+
+func TestConcurrentAddCard(t *testing.T) {
+	for _, retention := range []int{0, concurrentWriters} {
+		t.Run(fmt.Sprintf("retention %d", retention), func(t *testing.T) {
+			forEachBackend(t, retention, func(t *testing.T, db *DBStorage, userLogin string) {
+				ctx := context.Background()
+				number := []byte("4111111111111111")
+				base := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+				success := runConcurrently(t, func(i int) error {
+					_, err := db.AddRecord(ctx, userLogin, Card{
+						Prompt:    []byte("card"),
+						Number:    number,
+						Date:      []byte("12/30"),
+						Code:      []byte("123"),
+						Note:      []byte(fmt.Sprintf("v%d", i)),
+						Tags:      []byte{},
+						Folder:    []byte{},
+						TimeStamp: base.Add(time.Duration(i) * time.Second),
+					})
+					return err
+				})
+				require.Positive(t, success)
+
+				r, err := db.GetRecord(ctx, userLogin, Card{Number: number})
+				require.NoError(t, err)
+				card := r.(Card)
+				assert.Equal(t, fmt.Sprintf("v%d", concurrentWriters-1), string(card.Note), "the newest version must win")
+				assert.True(t, card.TimeStamp.Equal(base.Add((concurrentWriters-1)*time.Second)))
+
+				rev, err := db.GetRevision(ctx, userLogin, Card{Number: number})
+				require.NoError(t, err)
+				assert.Equal(t, int64(success), rev.Revision, "every successful write must be counted")
+
+				syncRev, err := db.GetSyncRevision(ctx, userLogin)
+				require.NoError(t, err)
+				assert.Equal(t, int64(success), syncRev, "rejected writes must not advance the sync revision")
+				cards, err := db.GetRecordsAfterRevision(ctx, userLogin, CardRecord, syncRev-1, syncRev)
+				require.NoError(t, err)
+				assert.Len(t, cards, 1, "the record must carry the revision of its last write")
+
+				if retention == 0 {
+					return
+				}
+				versions, err := db.ListVersions(ctx, userLogin, Card{Number: number})
+				require.NoError(t, err)
+				assert.Len(t, versions, success-1, "every replaced version must be archived once")
+				for i := 1; i < len(versions); i++ {
+					assert.True(t, versions[i-1].Record.(Card).TimeStamp.After(versions[i].Record.(Card).TimeStamp),
+						"archived versions must be ordered by time without duplicates")
+				}
+			})
+		})
+	}
+}
+
+func TestConcurrentForceUpdate(t *testing.T) {
+	forEachBackend(t, concurrentWriters, func(t *testing.T, db *DBStorage, userLogin string) {
+		ctx := context.Background()
+		o := Otp{Issuer: []byte("issuer"), Account: []byte("account"), Secret: []byte("secret"),
+			Algorithm: []byte("SHA1"), Digits: []byte("6"), Period: []byte("30"), Note: []byte("initial"), Tags: []byte{}, Folder: []byte{},
+			TimeStamp: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)}
+		_, err := db.AddRecord(ctx, userLogin, o)
+		require.NoError(t, err)
+
+		revisions := make([]int64, concurrentWriters)
+		success := runConcurrently(t, func(i int) error {
+			upd := o
+			upd.Note = []byte(fmt.Sprintf("v%d", i))
+			upd.TimeStamp = o.TimeStamp.Add(time.Duration(i+1) * time.Second)
+			rev, err := db.ForceUpdateRecord(ctx, userLogin, upd)
+			revisions[i] = rev.Revision
+			return err
+		})
+		require.Equal(t, concurrentWriters, success)
+		slices.Sort(revisions)
+		assert.Equal(t, concurrentWriters, len(slices.Compact(revisions)), "each write must return its own revision")
+
+		versions, err := db.ListVersions(ctx, userLogin, Otp{Issuer: o.Issuer, Account: o.Account})
+		require.NoError(t, err)
+		require.Len(t, versions, concurrentWriters)
+		notes := make(map[string]struct{}, len(versions))
+		for _, v := range versions {
+			notes[string(v.Record.(Otp).Note)] = struct{}{}
+		}
+		assert.Len(t, notes, concurrentWriters, "each version must be archived exactly once")
+		assert.Contains(t, notes, "initial")
+	})
+}
+
+func TestPostgresAtomicWriteQueries(t *testing.T) {
+	base := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	card := testCard
+	card.Tags, card.Folder, card.TimeStamp = []byte{}, []byte{}, base
+
+	tests := []struct {
+		name         string
+		retention    int
+		mockBehavior func(mock sqlmock.Sqlmock)
+		write        func(db *DBStorage) error
+		wantErr      bool
+		wantType     TypeStorErrors
+	}{
+		{
+			name: "add without history checks time stamp in upsert",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectWriteBegin(mock)
+				mock.ExpectExec("^INSERT INTO cards .+ ON CONFLICT \\(user_id, number\\) DO UPDATE SET .+ " +
+					"WHERE cards\\.time_stamp <= excluded\\.time_stamp$").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "cards")
+			},
+			write: func(db *DBStorage) error {
+				_, err := db.AddRecord(context.Background(), testUserLogin, card)
+				return err
+			},
+		},
+		{
+			name:      "add with history locks record",
+			retention: 5,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectWriteBegin(mock)
+				mock.ExpectExec("^INSERT INTO cards .+ ON CONFLICT \\(user_id, number\\) DO NOTHING$").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("^SELECT time_stamp FROM cards WHERE .+ FOR UPDATE$").
+					WithArgs(card.Number, testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"time_stamp"}).AddRow(base.Add(time.Hour)))
+				mock.ExpectRollback()
+			},
+			write: func(db *DBStorage) error {
+				_, err := db.AddRecord(context.Background(), testUserLogin, card)
+				return err
+			},
+			wantErr:  true,
+			wantType: ExistsDataNewerVersion,
+		},
+		{
+			name:      "force update with history locks record",
+			retention: 5,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				expectWriteBegin(mock)
+				mock.ExpectQuery("^SELECT time_stamp FROM otps WHERE .+ FOR UPDATE$").
+					WithArgs(testOtp.Issuer, testOtp.Account, testUserLogin).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			write: func(db *DBStorage) error {
+				_, err := db.ForceUpdateRecord(context.Background(), testUserLogin, testOtp)
+				return err
+			},
+			wantErr:  true,
+			wantType: EmptyResult,
+		},
+		{
+			name: "delete user locks user",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT user_id FROM users WHERE login = \\$1 AND NOT deleted FOR UPDATE$").
+					WithArgs(testUserLogin).
+					WillReturnError(errTest)
+				mock.ExpectRollback()
+			},
+			write: func(db *DBStorage) error {
+				return db.DeleteUser(context.Background(), testUserLogin)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			testDB := DBStorage{dbHandle: db, dialect: postgresDialect, retention: tt.retention}
+			tt.mockBehavior(mock)
+			err = tt.write(&testDB)
+			assertStorErr(t, err, tt.wantErr, tt.wantType)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
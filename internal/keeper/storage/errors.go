@@ -1,6 +1,15 @@
 package storage
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
 
 type TypeStorErrors string
 
@@ -19,12 +28,18 @@ const (
 	DecryptionError TypeStorErrors = "decryption error"
 	// TypeStorErrors - запрашиваемые данные не найдены.
 	EmptyResult TypeStorErrors = "the requested data not found"
+	// Forbidden - роль пользователя не позволяет выполнить операцию.
+	Forbidden TypeStorErrors = "operation is not allowed for the user role"
+	// QuotaExceeded - изменение превышает квоту пользователя.
+	QuotaExceeded TypeStorErrors = "user quota exceeded"
 )
 
 // StorErr тип ошибок репозитория.
 type StorErr struct {
 	ErrType TypeStorErrors
 	Err     error
+	// TimeStamp время изменения записи на сервере для ошибки ExistsDataNewerVersion.
+	TimeStamp time.Time
 }
 
 // Error - реализация интерфейса error.
@@ -39,3 +54,39 @@ func NewStorError(t TypeStorErrors, err error) error {
 		Err:     err,
 	}
 }
+
+// NewConflictError создает ошибку ExistsDataNewerVersion
+// со временем изменения записи на сервере.
+func NewConflictError(timeStamp time.Time, err error) error {
+	return &StorErr{
+		ErrType:   ExistsDataNewerVersion,
+		Err:       err,
+		TimeStamp: timeStamp,
+	}
+}
+
+// isUniqueViolation проверяет, что ошибка СУБД - нарушение уникальности.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgerrcode.UniqueViolation
+	}
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		return liteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || liteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+	return false
+}
+
+// isNotNullViolation проверяет, что ошибка СУБД - пустое значение обязательного столбца.
+func isNotNullViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgerrcode.NotNullViolation
+	}
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		return liteErr.Code() == sqlite3.SQLITE_CONSTRAINT_NOTNULL
+	}
+	return false
+}
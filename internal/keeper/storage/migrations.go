@@ -0,0 +1,307 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var embeddedMigrations embed.FS
+
+// migrationLockID - ключ рекомендательной блокировки PostgreSQL,
+// не дающей нескольким серверам применять миграции одновременно.
+const migrationLockID int64 = 0x6b656570
+
+// dialect хранит различия СУБД, важные для применения миграций и текста запросов.
+type dialect struct {
+	driver     string
+	migrations string
+	lock       string
+	unlock     string
+	appliedAt  string
+	// forUpdate дописывается к запросу, который блокирует выбранные строки до конца транзакции.
+	forUpdate string
+}
+
+var (
+	// postgresDialect - PostgreSQL, миграции выполняются под рекомендательной блокировкой.
+	postgresDialect = dialect{
+		driver:     "pgx",
+		migrations: "migrations/postgres",
+		lock:       "SELECT pg_advisory_lock($1)",
+		unlock:     "SELECT pg_advisory_unlock($1)",
+		appliedAt:  "timestamptz NOT NULL DEFAULT now()",
+		forUpdate:  " FOR UPDATE",
+	}
+	// sqliteDialect - встроенная БД SQLite. Ее файл использует один сервер,
+	// а транзакции сразу блокируют БД на запись, поэтому ни блокировка миграций,
+	// ни блокировка строк не нужна.
+	sqliteDialect = dialect{
+		driver:     sqliteDriverName,
+		migrations: "migrations/sqlite",
+		appliedAt:  "TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP",
+	}
+)
+
+// migrationName - шаблон имени файла миграции: 0001_name.up.sql или 0001_name.down.sql.
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration - версионная миграция схемы БД.
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationState хранит состояние миграции в БД.
+// Нулевое значение AppliedAt означает, что миграция не применена.
+// Пустое Name означает миграцию, примененную более новой версией сервера.
+type MigrationState struct {
+	Migration
+	AppliedAt time.Time
+}
+
+// Migrator применяет и откатывает миграции схемы БД.
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []Migration
+}
+
+// loadMigrations читает миграции из fsys и упорядочивает их по версии.
+// У каждой миграции должны быть файлы up и down.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		m := migrationName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", e.Name())
+		}
+		version, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, err
+		}
+		data, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mg
+		}
+		if mg.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has different names %s and %s", version, mg.Name, m[2])
+		}
+		if m[3] == "up" {
+			mg.up = string(data)
+		} else {
+			mg.down = string(data)
+		}
+	}
+
+	res := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if mg.up == "" || mg.down == "" {
+			return nil, fmt.Errorf("migration %d must have up and down files", mg.Version)
+		}
+		res = append(res, *mg)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+
+	return res, nil
+}
+
+// NewMigrator создает объект для применения встроенных миграций PostgreSQL к БД db.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	return newMigrator(db, postgresDialect)
+}
+
+// newMigrator создает объект для применения встроенных миграций СУБД d к БД db.
+func newMigrator(db *sql.DB, d dialect) (*Migrator, error) {
+	fsys, err := fs.Sub(embeddedMigrations, d.migrations)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: d, migrations: migrations}, nil
+}
+
+// OpenMigrator подключается к БД по адресу DBURI и создает объект для применения миграций.
+// СУБД выбирается по схеме DBURI, как в NewStorage. Соединение закрывается методом Close.
+func OpenMigrator(DBURI string) (*Migrator, error) {
+	db, d, err := openDB(DBURI)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMigrator(db, d)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// Close закрывает БД.
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// withLock выполняет fn на отдельном соединении под рекомендательной блокировкой,
+// если СУБД ее поддерживает, и создает таблицу schema_migrations, если ее еще нет.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn, applied map[int]time.Time) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.dialect.lock != "" {
+		_, err = conn.ExecContext(ctx, m.dialect.lock, migrationLockID)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_, unlockErr := conn.ExecContext(context.Background(), m.dialect.unlock, migrationLockID)
+			if err == nil {
+				err = unlockErr
+			}
+		}()
+	}
+
+	_, err = conn.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version integer PRIMARY KEY,
+			name text NOT NULL,
+			applied_at `+m.dialect.appliedAt+`
+		)`)
+	if err != nil {
+		return err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err = rows.Scan(&version, &at); err != nil {
+			return err
+		}
+		applied[version] = at
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	return fn(conn, applied)
+}
+
+// runTx выполняет запрос миграции и изменение schema_migrations в одной транзакции.
+func runTx(ctx context.Context, conn *sql.Conn, script string, query string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, script)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Up применяет все непримененные миграции по возрастанию версий
+// и возвращает примененные миграции.
+func (m *Migrator) Up(ctx context.Context) (done []Migration, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn, applied map[int]time.Time) error {
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			err := runTx(ctx, conn, mg.up,
+				"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mg.Version, mg.Name)
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", mg.Version, mg.Name, err)
+			}
+			done = append(done, mg)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down откатывает последнюю примененную миграцию и возвращает ее.
+// Если примененных миграций нет, возвращается ошибка EmptyResult.
+func (m *Migrator) Down(ctx context.Context) (done Migration, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn, applied map[int]time.Time) error {
+		last := -1
+		for version := range applied {
+			if version > last {
+				last = version
+			}
+		}
+		if last < 0 {
+			return NewStorError(EmptyResult, errors.New("no applied migrations"))
+		}
+
+		for _, mg := range m.migrations {
+			if mg.Version != last {
+				continue
+			}
+			err := runTx(ctx, conn, mg.down, "DELETE FROM schema_migrations WHERE version = $1", mg.Version)
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", mg.Version, mg.Name, err)
+			}
+			done = mg
+			return nil
+		}
+		return fmt.Errorf("migration %04d is unknown to this server version", last)
+	})
+	return done, err
+}
+
+// Status возвращает состояние всех известных и примененных миграций по возрастанию версий.
+func (m *Migrator) Status(ctx context.Context) (states []MigrationState, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn, applied map[int]time.Time) error {
+		for _, mg := range m.migrations {
+			states = append(states, MigrationState{Migration: mg, AppliedAt: applied[mg.Version]})
+			delete(applied, mg.Version)
+		}
+		for version, at := range applied {
+			states = append(states, MigrationState{Migration: Migration{Version: version}, AppliedAt: at})
+		}
+		return nil
+	})
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+	return states, err
+}
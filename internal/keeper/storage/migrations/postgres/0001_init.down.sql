@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS
+	collection_records,
+	collection_members,
+	collections,
+	org_members,
+	organizations,
+	emergency_contacts,
+	logins_history,
+	cards_history,
+	text_data_history,
+	binary_data_history,
+	otps_history,
+	ssh_keys_history,
+	templates_history,
+	custom_records_history,
+	audit_log,
+	logins,
+	cards,
+	text_data,
+	binary_data,
+	otps,
+	ssh_keys,
+	templates,
+	custom_records,
+	users;
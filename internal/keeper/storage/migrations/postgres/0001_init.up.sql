@@ -0,0 +1,278 @@
+-- Начальная схема БД. Миграция повторяет прежнее создание таблиц при запуске сервера
+-- и поэтому применяется и к новым БД, и к БД, созданным до появления миграций.
+
+CREATE TABLE IF NOT EXISTS users (
+	user_id serial UNIQUE,
+	login text UNIQUE NOT NULL CHECK(login != ''),
+	hash text NOT NULL CHECK(hash != ''),
+	salt text NOT NULL CHECK(salt != ''),
+	PRIMARY KEY(user_id)
+);
+
+CREATE TABLE IF NOT EXISTS logins (
+	user_id integer NOT NULL REFERENCES users(user_id),
+	prompt bytea NOT NULL,
+	login bytea NOT NULL,
+	pwd bytea NOT NULL,
+	note bytea,
+	tags bytea,
+	folder bytea,
+	time_stamp timestamptz (0) NOT NULL,
+	PRIMARY KEY(user_id, login, prompt)
+);
+
+CREATE TABLE IF NOT EXISTS cards (
+	user_id integer NOT NULL REFERENCES users(user_id),
+	prompt bytea NOT NULL,
+	number bytea NOT NULL,
+	date bytea NOT NULL,
+	code bytea NOT NULL,
+	note bytea,
+	tags bytea,
+	folder bytea,
+	time_stamp timestamptz (0) NOT NULL,
+	PRIMARY KEY(user_id, number)
+);
+
+CREATE TABLE IF NOT EXISTS text_data (
+	user_id integer NOT NULL REFERENCES users(user_id),
+	prompt bytea NOT NULL,
+	data bytea NOT NULL,
+	note bytea,
+	tags bytea,
+	folder bytea,
+	time_stamp timestamptz (0) NOT NULL,
+	PRIMARY KEY(user_id, prompt)
+);
+
+CREATE TABLE IF NOT EXISTS binary_data (
+	user_id integer NOT NULL REFERENCES users(user_id),
+	prompt bytea NOT NULL,
+	data bytea NOT NULL,
+	note bytea,
+	tags bytea,
+	folder bytea,
+	time_stamp timestamptz (0) NOT NULL,
+	PRIMARY KEY(user_id, prompt)
+);
+
+CREATE TABLE IF NOT EXISTS otps (
+	user_id integer NOT NULL REFERENCES users(user_id),
+	issuer bytea NOT NULL,
+	account bytea NOT NULL,
+	secret bytea NOT NULL,
+	algorithm bytea NOT NULL,
+	digits bytea NOT NULL,
+	period bytea NOT NULL,
+	note bytea,
+	tags bytea,
+	folder bytea,
+	time_stamp timestamptz (0) NOT NULL,
+	PRIMARY KEY(user_id, issuer, account)
+);
+
+CREATE TABLE IF NOT EXISTS ssh_keys (
+	user_id integer NOT NULL REFERENCES users(user_id),
+	prompt bytea NOT NULL,
+	private_key bytea NOT NULL,
+	public_key bytea NOT NULL,
+	comment bytea,
+	passphrase bytea,
+	tags bytea,
+	folder bytea,
+	time_stamp timestamptz (0) NOT NULL,
+	PRIMARY KEY(user_id, prompt)
+);
+
+CREATE TABLE IF NOT EXISTS templates (
+	user_id integer NOT NULL REFERENCES users(user_id),
+	name bytea NOT NULL,
+	fields bytea NOT NULL,
+	tags bytea,
+	folder bytea,
+	time_stamp timestamptz (0) NOT NULL,
+	PRIMARY KEY(user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS custom_records (
+	user_id integer NOT NULL REFERENCES users(user_id),
+	prompt bytea NOT NULL,
+	template bytea NOT NULL,
+	data bytea NOT NULL,
+	note bytea,
+	tags bytea,
+	folder bytea,
+	time_stamp timestamptz (0) NOT NULL,
+	PRIMARY KEY(user_id, prompt)
+);
+
+-- Таблицы записей, созданные до появления меток и папок, получают эти столбцы
+-- до создания таблиц истории, которые повторяют их структуру.
+ALTER TABLE logins
+	ADD COLUMN IF NOT EXISTS tags bytea,
+	ADD COLUMN IF NOT EXISTS folder bytea;
+
+ALTER TABLE cards
+	ADD COLUMN IF NOT EXISTS tags bytea,
+	ADD COLUMN IF NOT EXISTS folder bytea;
+
+ALTER TABLE text_data
+	ADD COLUMN IF NOT EXISTS tags bytea,
+	ADD COLUMN IF NOT EXISTS folder bytea;
+
+ALTER TABLE binary_data
+	ADD COLUMN IF NOT EXISTS tags bytea,
+	ADD COLUMN IF NOT EXISTS folder bytea;
+
+ALTER TABLE otps
+	ADD COLUMN IF NOT EXISTS tags bytea,
+	ADD COLUMN IF NOT EXISTS folder bytea;
+
+ALTER TABLE ssh_keys
+	ADD COLUMN IF NOT EXISTS tags bytea,
+	ADD COLUMN IF NOT EXISTS folder bytea;
+
+ALTER TABLE templates
+	ADD COLUMN IF NOT EXISTS tags bytea,
+	ADD COLUMN IF NOT EXISTS folder bytea;
+
+ALTER TABLE custom_records
+	ADD COLUMN IF NOT EXISTS tags bytea,
+	ADD COLUMN IF NOT EXISTS folder bytea;
+
+CREATE TABLE IF NOT EXISTS audit_log (
+	event_id bigserial PRIMARY KEY,
+	user_id integer NOT NULL REFERENCES users(user_id),
+	action text NOT NULL,
+	record_type integer NOT NULL,
+	prompt bytea,
+	record_key bytea,
+	device text NOT NULL,
+	ip text NOT NULL,
+	time_stamp timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS audit_log_user_time ON audit_log (user_id, time_stamp);
+CREATE OR REPLACE RULE audit_log_no_update AS ON UPDATE TO audit_log DO INSTEAD NOTHING;
+CREATE OR REPLACE RULE audit_log_no_delete AS ON DELETE TO audit_log DO INSTEAD NOTHING;
+
+CREATE TABLE IF NOT EXISTS logins_history (
+	LIKE logins,
+	version_id bigserial PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS cards_history (
+	LIKE cards,
+	version_id bigserial PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS text_data_history (
+	LIKE text_data,
+	version_id bigserial PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS binary_data_history (
+	LIKE binary_data,
+	version_id bigserial PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS otps_history (
+	LIKE otps,
+	version_id bigserial PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS ssh_keys_history (
+	LIKE ssh_keys,
+	version_id bigserial PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS templates_history (
+	LIKE templates,
+	version_id bigserial PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS custom_records_history (
+	LIKE custom_records,
+	version_id bigserial PRIMARY KEY
+);
+
+ALTER TABLE logins
+	ADD COLUMN IF NOT EXISTS record_id bigserial UNIQUE,
+	ADD COLUMN IF NOT EXISTS revision bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE cards
+	ADD COLUMN IF NOT EXISTS record_id bigserial UNIQUE,
+	ADD COLUMN IF NOT EXISTS revision bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE text_data
+	ADD COLUMN IF NOT EXISTS record_id bigserial UNIQUE,
+	ADD COLUMN IF NOT EXISTS revision bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE binary_data
+	ADD COLUMN IF NOT EXISTS record_id bigserial UNIQUE,
+	ADD COLUMN IF NOT EXISTS revision bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE otps
+	ADD COLUMN IF NOT EXISTS record_id bigserial UNIQUE,
+	ADD COLUMN IF NOT EXISTS revision bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE ssh_keys
+	ADD COLUMN IF NOT EXISTS record_id bigserial UNIQUE,
+	ADD COLUMN IF NOT EXISTS revision bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE templates
+	ADD COLUMN IF NOT EXISTS record_id bigserial UNIQUE,
+	ADD COLUMN IF NOT EXISTS revision bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE custom_records
+	ADD COLUMN IF NOT EXISTS record_id bigserial UNIQUE,
+	ADD COLUMN IF NOT EXISTS revision bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE users
+	ADD COLUMN IF NOT EXISTS disabled boolean NOT NULL DEFAULT false,
+	ADD COLUMN IF NOT EXISTS deleted boolean NOT NULL DEFAULT false,
+	ADD COLUMN IF NOT EXISTS session bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS emergency_contacts (
+	grantor_id integer NOT NULL REFERENCES users(user_id),
+	grantee_id integer NOT NULL REFERENCES users(user_id) CHECK(grantee_id != grantor_id),
+	wait_period bigint NOT NULL CHECK(wait_period >= 0),
+	wrapped_key bytea NOT NULL,
+	requested_at timestamptz,
+	PRIMARY KEY(grantor_id, grantee_id)
+);
+
+CREATE TABLE IF NOT EXISTS organizations (
+	org_id bigserial PRIMARY KEY,
+	name text UNIQUE NOT NULL CHECK(name != '')
+);
+CREATE TABLE IF NOT EXISTS org_members (
+	org_id bigint NOT NULL REFERENCES organizations(org_id),
+	user_id integer NOT NULL REFERENCES users(user_id),
+	role text NOT NULL CHECK(role IN ('owner', 'editor', 'viewer')),
+	PRIMARY KEY(org_id, user_id)
+);
+CREATE TABLE IF NOT EXISTS collections (
+	collection_id bigserial PRIMARY KEY,
+	org_id bigint NOT NULL REFERENCES organizations(org_id),
+	name text NOT NULL CHECK(name != ''),
+	UNIQUE(org_id, name)
+);
+CREATE TABLE IF NOT EXISTS collection_members (
+	collection_id bigint NOT NULL REFERENCES collections(collection_id),
+	user_id integer NOT NULL REFERENCES users(user_id),
+	role text NOT NULL CHECK(role IN ('owner', 'editor', 'viewer')),
+	wrapped_key bytea NOT NULL,
+	PRIMARY KEY(collection_id, user_id)
+);
+CREATE TABLE IF NOT EXISTS collection_records (
+	collection_id bigint NOT NULL REFERENCES collections(collection_id),
+	record_type integer NOT NULL,
+	prompt bytea NOT NULL,
+	record_key bytea NOT NULL,
+	data bytea NOT NULL,
+	time_stamp timestamptz (0) NOT NULL,
+	PRIMARY KEY(collection_id, record_type, prompt, record_key)
+);
+ALTER TABLE users
+	ADD COLUMN IF NOT EXISTS public_key bytea,
+	ADD COLUMN IF NOT EXISTS private_key bytea;
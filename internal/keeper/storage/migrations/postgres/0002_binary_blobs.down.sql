@@ -0,0 +1,21 @@
+-- Откат удаляет только ссылки, поэтому он не выполняется, пока содержимое хотя бы одной
+-- записи хранится в хранилище блобов: его нужно предварительно вернуть в БД.
+
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM binary_data WHERE blob_ref <> '')
+		OR EXISTS (SELECT 1 FROM binary_data_history WHERE blob_ref <> '') THEN
+		RAISE EXCEPTION 'binary records are stored in the blob store, move them back to the database first';
+	END IF;
+END
+$$;
+
+ALTER TABLE binary_data_history
+	DROP COLUMN IF EXISTS blob_ref,
+	DROP COLUMN IF EXISTS blob_sha256,
+	DROP COLUMN IF EXISTS blob_size;
+
+ALTER TABLE binary_data
+	DROP COLUMN IF EXISTS blob_ref,
+	DROP COLUMN IF EXISTS blob_sha256,
+	DROP COLUMN IF EXISTS blob_size;
@@ -0,0 +1,13 @@
+-- Содержимое бинарных записей может храниться вне БД в хранилище блобов.
+-- Тогда в data остается пустое значение, а в записи - ссылка, сумма SHA-256 и размер блоба.
+-- Записи с пустой ссылкой хранят содержимое в data, как раньше.
+
+ALTER TABLE binary_data
+	ADD COLUMN IF NOT EXISTS blob_ref text NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS blob_sha256 text NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS blob_size bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE binary_data_history
+	ADD COLUMN IF NOT EXISTS blob_ref text NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS blob_sha256 text NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS blob_size bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,25 @@
+DROP INDEX IF EXISTS custom_records_sync_revision_idx;
+ALTER TABLE custom_records DROP COLUMN IF EXISTS sync_revision;
+
+DROP INDEX IF EXISTS templates_sync_revision_idx;
+ALTER TABLE templates DROP COLUMN IF EXISTS sync_revision;
+
+DROP INDEX IF EXISTS ssh_keys_sync_revision_idx;
+ALTER TABLE ssh_keys DROP COLUMN IF EXISTS sync_revision;
+
+DROP INDEX IF EXISTS otps_sync_revision_idx;
+ALTER TABLE otps DROP COLUMN IF EXISTS sync_revision;
+
+DROP INDEX IF EXISTS binary_data_sync_revision_idx;
+ALTER TABLE binary_data DROP COLUMN IF EXISTS sync_revision;
+
+DROP INDEX IF EXISTS text_data_sync_revision_idx;
+ALTER TABLE text_data DROP COLUMN IF EXISTS sync_revision;
+
+DROP INDEX IF EXISTS cards_sync_revision_idx;
+ALTER TABLE cards DROP COLUMN IF EXISTS sync_revision;
+
+DROP INDEX IF EXISTS logins_sync_revision_idx;
+ALTER TABLE logins DROP COLUMN IF EXISTS sync_revision;
+
+ALTER TABLE users DROP COLUMN IF EXISTS sync_revision;
@@ -0,0 +1,31 @@
+-- Номер ревизии пользователя увеличивается сервером при каждом изменении его записей,
+-- а измененная запись получает новый номер. Клиенты запрашивают изменения после сохраненного
+-- номера ревизии вместо времени последней синхронизации.
+-- Существующие записи получают ревизию 1, чтобы клиент без сохраненной ревизии получил их.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sync_revision bigint NOT NULL DEFAULT 0;
+UPDATE users SET sync_revision = 1;
+
+ALTER TABLE logins ADD COLUMN IF NOT EXISTS sync_revision bigint NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS logins_sync_revision_idx ON logins (user_id, sync_revision);
+
+ALTER TABLE cards ADD COLUMN IF NOT EXISTS sync_revision bigint NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS cards_sync_revision_idx ON cards (user_id, sync_revision);
+
+ALTER TABLE text_data ADD COLUMN IF NOT EXISTS sync_revision bigint NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS text_data_sync_revision_idx ON text_data (user_id, sync_revision);
+
+ALTER TABLE binary_data ADD COLUMN IF NOT EXISTS sync_revision bigint NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS binary_data_sync_revision_idx ON binary_data (user_id, sync_revision);
+
+ALTER TABLE otps ADD COLUMN IF NOT EXISTS sync_revision bigint NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS otps_sync_revision_idx ON otps (user_id, sync_revision);
+
+ALTER TABLE ssh_keys ADD COLUMN IF NOT EXISTS sync_revision bigint NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS ssh_keys_sync_revision_idx ON ssh_keys (user_id, sync_revision);
+
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS sync_revision bigint NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS templates_sync_revision_idx ON templates (user_id, sync_revision);
+
+ALTER TABLE custom_records ADD COLUMN IF NOT EXISTS sync_revision bigint NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS custom_records_sync_revision_idx ON custom_records (user_id, sync_revision);
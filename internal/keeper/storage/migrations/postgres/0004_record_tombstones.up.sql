@@ -0,0 +1,13 @@
+-- Ключи личных записей, удаленных с сервера (например, перенесенных в общую коллекцию),
+-- с номером ревизии пользователя, на которой запись удалена. При синхронизации клиент
+-- получает ключи записей, удаленных после сохраненной им ревизии, и удаляет их у себя.
+
+CREATE TABLE IF NOT EXISTS record_tombstones (
+	user_id integer NOT NULL REFERENCES users(user_id),
+	record_type integer NOT NULL,
+	prompt bytea NOT NULL,
+	record_key bytea NOT NULL,
+	sync_revision bigint NOT NULL,
+	PRIMARY KEY(user_id, record_type, prompt, record_key)
+);
+CREATE INDEX IF NOT EXISTS record_tombstones_sync_revision_idx ON record_tombstones (user_id, sync_revision);
@@ -0,0 +1,13 @@
+-- Копия записей владельца для доверенного лица, зашифрованная отдельным случайным ключом
+-- экстренного доступа. Копия заменяется при каждом назначении доверенного лица и удаляется
+-- вместе с ним.
+
+CREATE TABLE IF NOT EXISTS emergency_records (
+	grantor_id integer NOT NULL,
+	grantee_id integer NOT NULL,
+	record_type integer NOT NULL,
+	data bytea NOT NULL,
+	time_stamp timestamptz (0) NOT NULL,
+	FOREIGN KEY(grantor_id, grantee_id) REFERENCES emergency_contacts(grantor_id, grantee_id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS emergency_records_contact_idx ON emergency_records (grantor_id, grantee_id);
@@ -0,0 +1,24 @@
+DROP TABLE IF EXISTS collection_records;
+DROP TABLE IF EXISTS collection_members;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS org_members;
+DROP TABLE IF EXISTS organizations;
+DROP TABLE IF EXISTS emergency_contacts;
+DROP TABLE IF EXISTS logins_history;
+DROP TABLE IF EXISTS cards_history;
+DROP TABLE IF EXISTS text_data_history;
+DROP TABLE IF EXISTS binary_data_history;
+DROP TABLE IF EXISTS otps_history;
+DROP TABLE IF EXISTS ssh_keys_history;
+DROP TABLE IF EXISTS templates_history;
+DROP TABLE IF EXISTS custom_records_history;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS logins;
+DROP TABLE IF EXISTS cards;
+DROP TABLE IF EXISTS text_data;
+DROP TABLE IF EXISTS binary_data;
+DROP TABLE IF EXISTS otps;
+DROP TABLE IF EXISTS ssh_keys;
+DROP TABLE IF EXISTS templates;
+DROP TABLE IF EXISTS custom_records;
+DROP TABLE IF EXISTS users;
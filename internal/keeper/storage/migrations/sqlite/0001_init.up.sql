@@ -0,0 +1,293 @@
+-- Начальная схема встроенной БД SQLite. Повторяет схему PostgreSQL:
+-- serial заменен на INTEGER PRIMARY KEY AUTOINCREMENT, bytea - на BLOB,
+-- а правила, запрещающие изменение журнала аудита, - на триггеры.
+-- Столбцы времени объявлены как TIMESTAMP, чтобы драйвер возвращал их как время.
+-- Файлы SQLite создаются только мигратором, поэтому БД, созданных до появления миграций
+-- (как у PostgreSQL), не бывает. SQLite не поддерживает ADD COLUMN IF NOT EXISTS, и таблицы
+-- создаются без IF NOT EXISTS: миграция чужой БД с таблицами старой схемы завершается ошибкой,
+-- а не оставляет их без новых столбцов.
+
+CREATE TABLE users (
+	user_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	login TEXT UNIQUE NOT NULL CHECK(login != ''),
+	hash TEXT NOT NULL CHECK(hash != ''),
+	salt TEXT NOT NULL CHECK(salt != ''),
+	disabled BOOLEAN NOT NULL DEFAULT false,
+	deleted BOOLEAN NOT NULL DEFAULT false,
+	session INTEGER NOT NULL DEFAULT 0,
+	public_key BLOB,
+	private_key BLOB
+);
+
+CREATE TABLE logins (
+	record_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	prompt BLOB NOT NULL,
+	login BLOB NOT NULL,
+	pwd BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 1,
+	UNIQUE(user_id, login, prompt)
+);
+
+CREATE TABLE cards (
+	record_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	prompt BLOB NOT NULL,
+	number BLOB NOT NULL,
+	date BLOB NOT NULL,
+	code BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 1,
+	UNIQUE(user_id, number)
+);
+
+CREATE TABLE text_data (
+	record_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	prompt BLOB NOT NULL,
+	data BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 1,
+	UNIQUE(user_id, prompt)
+);
+
+CREATE TABLE binary_data (
+	record_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	prompt BLOB NOT NULL,
+	data BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 1,
+	UNIQUE(user_id, prompt)
+);
+
+CREATE TABLE otps (
+	record_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	issuer BLOB NOT NULL,
+	account BLOB NOT NULL,
+	secret BLOB NOT NULL,
+	algorithm BLOB NOT NULL,
+	digits BLOB NOT NULL,
+	period BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 1,
+	UNIQUE(user_id, issuer, account)
+);
+
+CREATE TABLE ssh_keys (
+	record_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	prompt BLOB NOT NULL,
+	private_key BLOB NOT NULL,
+	public_key BLOB NOT NULL,
+	comment BLOB,
+	passphrase BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 1,
+	UNIQUE(user_id, prompt)
+);
+
+CREATE TABLE templates (
+	record_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	name BLOB NOT NULL,
+	fields BLOB NOT NULL,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 1,
+	UNIQUE(user_id, name)
+);
+
+CREATE TABLE custom_records (
+	record_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	prompt BLOB NOT NULL,
+	template BLOB NOT NULL,
+	data BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 1,
+	UNIQUE(user_id, prompt)
+);
+
+CREATE TABLE audit_log (
+	event_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	action TEXT NOT NULL,
+	record_type INTEGER NOT NULL,
+	prompt BLOB,
+	record_key BLOB,
+	device TEXT NOT NULL,
+	ip TEXT NOT NULL,
+	time_stamp TIMESTAMP NOT NULL
+);
+CREATE INDEX audit_log_user_time ON audit_log (user_id, time_stamp);
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+	SELECT RAISE(IGNORE);
+END;
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+	SELECT RAISE(IGNORE);
+END;
+
+CREATE TABLE logins_history (
+	version_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	prompt BLOB NOT NULL,
+	login BLOB NOT NULL,
+	pwd BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL
+);
+
+CREATE TABLE cards_history (
+	version_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	prompt BLOB NOT NULL,
+	number BLOB NOT NULL,
+	date BLOB NOT NULL,
+	code BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL
+);
+
+CREATE TABLE text_data_history (
+	version_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	prompt BLOB NOT NULL,
+	data BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL
+);
+
+CREATE TABLE binary_data_history (
+	version_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	prompt BLOB NOT NULL,
+	data BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL
+);
+
+CREATE TABLE otps_history (
+	version_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	issuer BLOB NOT NULL,
+	account BLOB NOT NULL,
+	secret BLOB NOT NULL,
+	algorithm BLOB NOT NULL,
+	digits BLOB NOT NULL,
+	period BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL
+);
+
+CREATE TABLE ssh_keys_history (
+	version_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	prompt BLOB NOT NULL,
+	private_key BLOB NOT NULL,
+	public_key BLOB NOT NULL,
+	comment BLOB,
+	passphrase BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL
+);
+
+CREATE TABLE templates_history (
+	version_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	name BLOB NOT NULL,
+	fields BLOB NOT NULL,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL
+);
+
+CREATE TABLE custom_records_history (
+	version_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	prompt BLOB NOT NULL,
+	template BLOB NOT NULL,
+	data BLOB NOT NULL,
+	note BLOB,
+	tags BLOB,
+	folder BLOB,
+	time_stamp TIMESTAMP NOT NULL
+);
+
+CREATE TABLE emergency_contacts (
+	grantor_id INTEGER NOT NULL REFERENCES users(user_id),
+	grantee_id INTEGER NOT NULL REFERENCES users(user_id) CHECK(grantee_id != grantor_id),
+	wait_period INTEGER NOT NULL CHECK(wait_period >= 0),
+	wrapped_key BLOB NOT NULL,
+	requested_at TIMESTAMP,
+	PRIMARY KEY(grantor_id, grantee_id)
+);
+
+CREATE TABLE organizations (
+	org_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT UNIQUE NOT NULL CHECK(name != '')
+);
+CREATE TABLE org_members (
+	org_id INTEGER NOT NULL REFERENCES organizations(org_id),
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	role TEXT NOT NULL CHECK(role IN ('owner', 'editor', 'viewer')),
+	PRIMARY KEY(org_id, user_id)
+);
+CREATE TABLE collections (
+	collection_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	org_id INTEGER NOT NULL REFERENCES organizations(org_id),
+	name TEXT NOT NULL CHECK(name != ''),
+	UNIQUE(org_id, name)
+);
+CREATE TABLE collection_members (
+	collection_id INTEGER NOT NULL REFERENCES collections(collection_id),
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	role TEXT NOT NULL CHECK(role IN ('owner', 'editor', 'viewer')),
+	wrapped_key BLOB NOT NULL,
+	PRIMARY KEY(collection_id, user_id)
+);
+CREATE TABLE collection_records (
+	collection_id INTEGER NOT NULL REFERENCES collections(collection_id),
+	record_type INTEGER NOT NULL,
+	prompt BLOB NOT NULL,
+	record_key BLOB NOT NULL,
+	data BLOB NOT NULL,
+	time_stamp TIMESTAMP NOT NULL,
+	PRIMARY KEY(collection_id, record_type, prompt, record_key)
+);
@@ -0,0 +1,22 @@
+-- Откат удаляет только ссылки, поэтому он не выполняется, пока содержимое хотя бы одной
+-- записи хранится в хранилище блобов: его нужно предварительно вернуть в БД.
+-- SQLite прерывает запрос с ошибкой только в триггере, поэтому проверка выполняется
+-- триггером временной таблицы.
+
+CREATE TEMP TABLE blob_rollback_check (id INTEGER);
+CREATE TEMP TRIGGER blob_rollback_abort BEFORE INSERT ON blob_rollback_check
+WHEN EXISTS (SELECT 1 FROM binary_data WHERE blob_ref <> '')
+	OR EXISTS (SELECT 1 FROM binary_data_history WHERE blob_ref <> '')
+BEGIN
+	SELECT RAISE(ABORT, 'binary records are stored in the blob store, move them back to the database first');
+END;
+INSERT INTO blob_rollback_check VALUES (1);
+DROP TABLE blob_rollback_check;
+
+ALTER TABLE binary_data_history DROP COLUMN blob_size;
+ALTER TABLE binary_data_history DROP COLUMN blob_sha256;
+ALTER TABLE binary_data_history DROP COLUMN blob_ref;
+
+ALTER TABLE binary_data DROP COLUMN blob_size;
+ALTER TABLE binary_data DROP COLUMN blob_sha256;
+ALTER TABLE binary_data DROP COLUMN blob_ref;
@@ -0,0 +1,11 @@
+-- Содержимое бинарных записей может храниться вне БД в хранилище блобов.
+-- Тогда в data остается пустое значение, а в записи - ссылка, сумма SHA-256 и размер блоба.
+-- Записи с пустой ссылкой хранят содержимое в data, как раньше.
+
+ALTER TABLE binary_data ADD COLUMN blob_ref TEXT NOT NULL DEFAULT '';
+ALTER TABLE binary_data ADD COLUMN blob_sha256 TEXT NOT NULL DEFAULT '';
+ALTER TABLE binary_data ADD COLUMN blob_size INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE binary_data_history ADD COLUMN blob_ref TEXT NOT NULL DEFAULT '';
+ALTER TABLE binary_data_history ADD COLUMN blob_sha256 TEXT NOT NULL DEFAULT '';
+ALTER TABLE binary_data_history ADD COLUMN blob_size INTEGER NOT NULL DEFAULT 0;
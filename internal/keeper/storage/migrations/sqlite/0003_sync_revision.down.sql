@@ -0,0 +1,25 @@
+DROP INDEX custom_records_sync_revision_idx;
+ALTER TABLE custom_records DROP COLUMN sync_revision;
+
+DROP INDEX templates_sync_revision_idx;
+ALTER TABLE templates DROP COLUMN sync_revision;
+
+DROP INDEX ssh_keys_sync_revision_idx;
+ALTER TABLE ssh_keys DROP COLUMN sync_revision;
+
+DROP INDEX otps_sync_revision_idx;
+ALTER TABLE otps DROP COLUMN sync_revision;
+
+DROP INDEX binary_data_sync_revision_idx;
+ALTER TABLE binary_data DROP COLUMN sync_revision;
+
+DROP INDEX text_data_sync_revision_idx;
+ALTER TABLE text_data DROP COLUMN sync_revision;
+
+DROP INDEX cards_sync_revision_idx;
+ALTER TABLE cards DROP COLUMN sync_revision;
+
+DROP INDEX logins_sync_revision_idx;
+ALTER TABLE logins DROP COLUMN sync_revision;
+
+ALTER TABLE users DROP COLUMN sync_revision;
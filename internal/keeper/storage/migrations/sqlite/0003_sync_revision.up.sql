@@ -0,0 +1,31 @@
+-- Номер ревизии пользователя увеличивается сервером при каждом изменении его записей,
+-- а измененная запись получает новый номер. Клиенты запрашивают изменения после сохраненного
+-- номера ревизии вместо времени последней синхронизации.
+-- Существующие записи получают ревизию 1, чтобы клиент без сохраненной ревизии получил их.
+
+ALTER TABLE users ADD COLUMN sync_revision INTEGER NOT NULL DEFAULT 0;
+UPDATE users SET sync_revision = 1;
+
+ALTER TABLE logins ADD COLUMN sync_revision INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX logins_sync_revision_idx ON logins (user_id, sync_revision);
+
+ALTER TABLE cards ADD COLUMN sync_revision INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX cards_sync_revision_idx ON cards (user_id, sync_revision);
+
+ALTER TABLE text_data ADD COLUMN sync_revision INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX text_data_sync_revision_idx ON text_data (user_id, sync_revision);
+
+ALTER TABLE binary_data ADD COLUMN sync_revision INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX binary_data_sync_revision_idx ON binary_data (user_id, sync_revision);
+
+ALTER TABLE otps ADD COLUMN sync_revision INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX otps_sync_revision_idx ON otps (user_id, sync_revision);
+
+ALTER TABLE ssh_keys ADD COLUMN sync_revision INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX ssh_keys_sync_revision_idx ON ssh_keys (user_id, sync_revision);
+
+ALTER TABLE templates ADD COLUMN sync_revision INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX templates_sync_revision_idx ON templates (user_id, sync_revision);
+
+ALTER TABLE custom_records ADD COLUMN sync_revision INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX custom_records_sync_revision_idx ON custom_records (user_id, sync_revision);
@@ -0,0 +1,13 @@
+-- Ключи личных записей, удаленных с сервера (например, перенесенных в общую коллекцию),
+-- с номером ревизии пользователя, на которой запись удалена. При синхронизации клиент
+-- получает ключи записей, удаленных после сохраненной им ревизии, и удаляет их у себя.
+
+CREATE TABLE record_tombstones (
+	user_id INTEGER NOT NULL REFERENCES users(user_id),
+	record_type INTEGER NOT NULL,
+	prompt BLOB NOT NULL,
+	record_key BLOB NOT NULL,
+	sync_revision INTEGER NOT NULL,
+	PRIMARY KEY(user_id, record_type, prompt, record_key)
+);
+CREATE INDEX record_tombstones_sync_revision_idx ON record_tombstones (user_id, sync_revision);
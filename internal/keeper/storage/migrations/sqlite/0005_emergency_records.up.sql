@@ -0,0 +1,13 @@
+-- Копия записей владельца для доверенного лица, зашифрованная отдельным случайным ключом
+-- экстренного доступа. Копия заменяется при каждом назначении доверенного лица и удаляется
+-- вместе с ним.
+
+CREATE TABLE emergency_records (
+	grantor_id INTEGER NOT NULL,
+	grantee_id INTEGER NOT NULL,
+	record_type INTEGER NOT NULL,
+	data BLOB NOT NULL,
+	time_stamp TIMESTAMP NOT NULL,
+	FOREIGN KEY(grantor_id, grantee_id) REFERENCES emergency_contacts(grantor_id, grantee_id) ON DELETE CASCADE
+);
+CREATE INDEX emergency_records_contact_idx ON emergency_records (grantor_id, grantee_id);
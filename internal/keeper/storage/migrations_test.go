@@ -0,0 +1,318 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "ok test",
+			fsys: fstest.MapFS{
+				"0002_add.up.sql":    {Data: []byte("up 2")},
+				"0002_add.down.sql":  {Data: []byte("down 2")},
+				"0001_init.up.sql":   {Data: []byte("up 1")},
+				"0001_init.down.sql": {Data: []byte("down 1")},
+			},
+			want: []Migration{
+				{Version: 1, Name: "init", up: "up 1", down: "down 1"},
+				{Version: 2, Name: "add", up: "up 2", down: "down 2"},
+			},
+		},
+		{
+			name: "missing down test",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql": {Data: []byte("up 1")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "different names test",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql":    {Data: []byte("up 1")},
+				"0001_other.down.sql": {Data: []byte("down 1")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid name test",
+			fsys: fstest.MapFS{
+				"init.sql": {Data: []byte("up 1")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := loadMigrations(test.fsys)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestNewMigrator(t *testing.T) {
+	m, err := NewMigrator(nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, m.migrations)
+	assert.Equal(t, 1, m.migrations[0].Version)
+	assert.Equal(t, "init", m.migrations[0].Name)
+	for _, table := range append(historyTables, "users", "audit_log", "emergency_contacts", "collection_records") {
+		assert.Contains(t, m.migrations[0].up, "CREATE TABLE IF NOT EXISTS "+table+" ")
+		assert.Contains(t, m.migrations[0].down, "\t"+table)
+	}
+}
+
+// expectLock задает ожидания захвата блокировки и чтения примененных миграций.
+func expectLock(mock sqlmock.Sqlmock, applied *sqlmock.Rows) {
+	mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").WillReturnRows(applied)
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	m := &Migrator{db: db, dialect: postgresDialect, migrations: []Migration{
+		{Version: 1, Name: "init", up: "CREATE TABLE one", down: "DROP TABLE one"},
+		{Version: 2, Name: "add", up: "CREATE TABLE two", down: "DROP TABLE two"},
+	}}
+	at, _ := time.Parse(time.RFC3339, testTime)
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		want         []Migration
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				expectLock(mock, sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, at))
+				mock.ExpectBegin()
+				mock.ExpectExec("CREATE TABLE two").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(2, "add").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want: m.migrations[1:],
+		},
+		{
+			name: "up to date test",
+			mockBehavior: func() {
+				expectLock(mock, sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, at).AddRow(2, at))
+				mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "migration error test",
+			mockBehavior: func() {
+				expectLock(mock, sqlmock.NewRows([]string{"version", "applied_at"}))
+				mock.ExpectBegin()
+				mock.ExpectExec("CREATE TABLE one").WillReturnError(errTest)
+				mock.ExpectRollback()
+				mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+		{
+			name: "lock error test",
+			mockBehavior: func() {
+				mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(migrationLockID).WillReturnError(errTest)
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehavior()
+			got, err := m.Up(context.Background())
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	m := &Migrator{db: db, dialect: postgresDialect, migrations: []Migration{
+		{Version: 1, Name: "init", up: "CREATE TABLE one", down: "DROP TABLE one"},
+		{Version: 2, Name: "add", up: "CREATE TABLE two", down: "DROP TABLE two"},
+	}}
+	at, _ := time.Parse(time.RFC3339, testTime)
+
+	expectLock(mock, sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, at).AddRow(2, at))
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE two").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	got, err := m.Down(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, m.migrations[1], got)
+
+	expectLock(mock, sqlmock.NewRows([]string{"version", "applied_at"}))
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	_, err = m.Down(context.Background())
+	assertStorErr(t, err, true, EmptyResult)
+
+	expectLock(mock, sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(3, at))
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	_, err = m.Down(context.Background())
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	m := &Migrator{db: db, dialect: postgresDialect, migrations: []Migration{
+		{Version: 1, Name: "init", up: "CREATE TABLE one", down: "DROP TABLE one"},
+		{Version: 2, Name: "add", up: "CREATE TABLE two", down: "DROP TABLE two"},
+	}}
+	at, _ := time.Parse(time.RFC3339, testTime)
+
+	expectLock(mock, sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, at).AddRow(3, at))
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	got, err := m.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []MigrationState{
+		{Migration: m.migrations[0], AppliedAt: at},
+		{Migration: m.migrations[1]},
+		{Migration: Migration{Version: 3}, AppliedAt: at},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// recordTableNames - таблицы записей пользователей, у каждой из которых есть таблица истории.
+var recordTableNames = []string{
+	"logins", "cards", "text_data", "binary_data",
+	"otps", "ssh_keys", "templates", "custom_records",
+}
+
+func TestPostgresInitMigration_TagsBeforeHistory(t *testing.T) {
+	m, err := newMigrator(nil, postgresDialect)
+	require.NoError(t, err)
+	up := m.migrations[0].up
+
+	for _, table := range recordTableNames {
+		alter := strings.Index(up, "ALTER TABLE "+table+"\n"+
+			"\tADD COLUMN IF NOT EXISTS tags bytea,\n"+
+			"\tADD COLUMN IF NOT EXISTS folder bytea;")
+		like := strings.Index(up, "LIKE "+table+",")
+		require.NotEqual(t, -1, alter, table)
+		require.NotEqual(t, -1, like, table)
+		assert.Less(t, alter, like, table)
+	}
+}
+
+func TestPostgresInitMigration_LegacyTables(t *testing.T) {
+	dsn := os.Getenv(testPostgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testPostgresDSNEnv)
+	}
+	ctx := context.Background()
+
+	admin, err := sql.Open(postgresDialect.driver, dsn)
+	require.NoError(t, err)
+	defer admin.Close()
+	schema := fmt.Sprintf("legacy_%d", time.Now().UnixNano())
+	_, err = admin.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := admin.ExecContext(ctx, "DROP SCHEMA "+schema+" CASCADE")
+		assert.NoError(t, err)
+	})
+
+	cfg, err := pgx.ParseConfig(dsn)
+	require.NoError(t, err)
+	cfg.RuntimeParams["search_path"] = schema
+	db := stdlib.OpenDB(*cfg)
+	defer db.Close()
+
+	// Таблицы записей в том виде, в котором их создавал сервер до появления меток и папок.
+	m, err := newMigrator(db, postgresDialect)
+	require.NoError(t, err)
+	up := m.migrations[0].up
+	legacy := strings.ReplaceAll(up[:strings.Index(up, "ALTER TABLE")], "\ttags bytea,\n\tfolder bytea,\n", "")
+	_, err = db.ExecContext(ctx, legacy)
+	require.NoError(t, err)
+
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+
+	for _, table := range recordTableNames {
+		for _, name := range []string{table, table + "_history"} {
+			var n int
+			err = db.QueryRowContext(ctx,
+				`SELECT count(*) FROM information_schema.columns
+				WHERE table_schema = $1 AND table_name = $2 AND column_name IN ('tags', 'folder')`,
+				schema, name).Scan(&n)
+			require.NoError(t, err)
+			assert.Equal(t, 2, n, name)
+		}
+	}
+}
+
+func TestSqliteInitMigration_ExistingTable(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open(sqliteDialect.driver, filepath.Join(t.TempDir(), "keeper.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Таблица старой схемы без меток и папок не должна остаться без новых столбцов.
+	_, err = db.ExecContext(ctx, `CREATE TABLE logins (
+		user_id INTEGER NOT NULL, prompt BLOB NOT NULL, login BLOB NOT NULL, pwd BLOB NOT NULL)`)
+	require.NoError(t, err)
+
+	m, err := newMigrator(db, sqliteDialect)
+	require.NoError(t, err)
+	_, err = m.Up(ctx)
+	assert.ErrorContains(t, err, "already exists")
+
+	var n int
+	require.NoError(t, db.QueryRowContext(ctx,
+		"SELECT count(*) FROM sqlite_master WHERE name = 'users'").Scan(&n))
+	assert.Zero(t, n)
+}
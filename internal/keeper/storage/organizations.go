@@ -0,0 +1,449 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/recordtable"
+)
+
+// Role роль участника организации или коллекции.
+type Role string
+
+const (
+	// RoleOwner - владелец: приглашает участников, добавляет и читает записи.
+	RoleOwner Role = "owner"
+	// RoleEditor - редактор: добавляет и читает записи.
+	RoleEditor Role = "editor"
+	// RoleViewer - читатель: только читает записи.
+	RoleViewer Role = "viewer"
+)
+
+// canWrite проверяет, что роль позволяет добавлять записи.
+func (r Role) canWrite() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
+// UserKeys хранит пару ключей пользователя для общих коллекций.
+// PrivateKey зашифрован ключом пользователя на клиенте.
+type UserKeys struct {
+	PublicKey  []byte
+	PrivateKey []byte
+}
+
+// Organization хранит организацию и роль в ней текущего пользователя.
+type Organization struct {
+	ID   int64
+	Name string
+	Role Role
+}
+
+// Collection хранит общую коллекцию, роль в ней текущего пользователя
+// и ключ коллекции, зашифрованный открытым ключом пользователя.
+type Collection struct {
+	ID           int64
+	OrgID        int64
+	Organization string
+	Name         string
+	Role         Role
+	WrappedKey   []byte
+}
+
+// SharedRecord хранит запись общей коллекции.
+// Prompt и Key - ключевые поля, Data - запись целиком; все поля зашифрованы ключом коллекции.
+type SharedRecord struct {
+	Type      RecordType
+	Prompt    []byte
+	Key       []byte
+	Data      []byte
+	TimeStamp time.Time
+}
+
+// SetUserKeys сохраняет пару ключей пользователя для общих коллекций.
+// Ключи сохраняются один раз: замена ключей сделала бы недоступными
+// ключи коллекций, зашифрованные прежним открытым ключом.
+func (db *DBStorage) SetUserKeys(ctx context.Context, userLogin string, keys UserKeys) error {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	result, err := db.dbHandle.ExecContext(ctx,
+		"UPDATE users SET public_key = $2, private_key = $3 WHERE login = $1 AND public_key IS NULL",
+		userLogin, keys.PublicKey, keys.PrivateKey)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 1 {
+		return nil
+	}
+
+	var userID int64
+	err = db.dbHandle.QueryRowContext(ctx,
+		"SELECT user_id FROM users WHERE login = $1", userLogin).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NewStorError(EmptyResult, errors.New("user not found"))
+	}
+	if err != nil {
+		return err
+	}
+	return NewStorError(AlreadyExists, errors.New("user keys already exist"))
+}
+
+// GetUserKeys получает пару ключей пользователя для общих коллекций.
+func (db *DBStorage) GetUserKeys(ctx context.Context, userLogin string) (keys UserKeys, err error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	err = db.dbHandle.QueryRowContext(ctx,
+		"SELECT public_key, private_key FROM users WHERE login = $1 AND public_key IS NOT NULL",
+		userLogin).Scan(&keys.PublicKey, &keys.PrivateKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return UserKeys{}, NewStorError(EmptyResult, err)
+	}
+	return keys, err
+}
+
+// GetPublicKey получает открытый ключ пользователя для приглашения в коллекцию.
+func (db *DBStorage) GetPublicKey(ctx context.Context, login string) (publicKey []byte, err error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	err = db.dbHandle.QueryRowContext(ctx,
+		"SELECT public_key FROM users WHERE login = $1 AND NOT deleted AND public_key IS NOT NULL",
+		login).Scan(&publicKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, NewStorError(EmptyResult, err)
+	}
+	return publicKey, err
+}
+
+// uniqueErr преобразует ошибку нарушения уникальности в ошибку AlreadyExists.
+func uniqueErr(err error) error {
+	if isUniqueViolation(err) {
+		return NewStorError(AlreadyExists, err)
+	}
+	return err
+}
+
+// CreateOrganization создает организацию, пользователь становится ее владельцем.
+func (db *DBStorage) CreateOrganization(ctx context.Context, userLogin string, name string) (org Organization, err error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	tx, err := db.dbHandle.BeginTx(ctx, nil)
+	if err != nil {
+		return Organization{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO organizations (name) VALUES ($1) RETURNING org_id", name).Scan(&org.ID)
+	if err != nil {
+		return Organization{}, uniqueErr(err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO org_members (org_id, user_id, role)
+		VALUES ($1, (SELECT user_id FROM users WHERE login = $2), $3)`,
+		org.ID, userLogin, RoleOwner)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	org.Name = name
+	org.Role = RoleOwner
+	return org, tx.Commit()
+}
+
+// nonNil заменяет nil пустым срезом для ключевых столбцов NOT NULL.
+func nonNil(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
+
+// memberRole получает роль пользователя в строке таблицы участников, выбранной запросом query.
+// Отсутствие пользователя среди участников возвращает ошибку Forbidden.
+func memberRole(ctx context.Context, q queryRower, query string, args ...any) (Role, error) {
+	var role Role
+	err := q.QueryRowContext(ctx, query, args...).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", NewStorError(Forbidden, errors.New("not a member"))
+	}
+	return role, err
+}
+
+// queryRower выполняет запрос, возвращающий одну строку.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+const (
+	orgRoleQuery = `SELECT role FROM org_members
+		WHERE org_id = $1 AND user_id = (SELECT user_id FROM users WHERE login = $2)`
+	collectionRoleQuery = `SELECT role FROM collection_members
+		WHERE collection_id = $1 AND user_id = (SELECT user_id FROM users WHERE login = $2)`
+)
+
+// CreateCollection создает коллекцию в организации, пользователь становится ее владельцем.
+// Создавать коллекции могут владельцы и редакторы организации.
+// wrappedKey - ключ коллекции, зашифрованный открытым ключом пользователя.
+func (db *DBStorage) CreateCollection(ctx context.Context, userLogin string, orgID int64,
+	name string, wrappedKey []byte) (c Collection, err error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	tx, err := db.dbHandle.BeginTx(ctx, nil)
+	if err != nil {
+		return Collection{}, err
+	}
+	defer tx.Rollback()
+
+	role, err := memberRole(ctx, tx, orgRoleQuery, orgID, userLogin)
+	if err != nil {
+		return Collection{}, err
+	}
+	if !role.canWrite() {
+		return Collection{}, NewStorError(Forbidden, fmt.Errorf("role %s can't create collections", role))
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO collections (org_id, name) VALUES ($1, $2)
+		RETURNING collection_id, (SELECT name FROM organizations WHERE org_id = $1)`,
+		orgID, name).Scan(&c.ID, &c.Organization)
+	if err != nil {
+		return Collection{}, uniqueErr(err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO collection_members (collection_id, user_id, role, wrapped_key)
+		VALUES ($1, (SELECT user_id FROM users WHERE login = $2), $3, $4)`,
+		c.ID, userLogin, RoleOwner, wrappedKey)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	c.OrgID = orgID
+	c.Name = name
+	c.Role = RoleOwner
+	c.WrappedKey = wrappedKey
+	return c, tx.Commit()
+}
+
+// ListCollections получает коллекции, в которых участвует пользователь.
+func (db *DBStorage) ListCollections(ctx context.Context, userLogin string) (collections []Collection, err error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	rows, err := db.dbHandle.QueryContext(ctx,
+		`SELECT c.collection_id, c.org_id, o.name, c.name, m.role, m.wrapped_key
+		FROM collection_members m
+			JOIN collections c ON c.collection_id = m.collection_id
+			JOIN organizations o ON o.org_id = c.org_id
+		WHERE m.user_id = (SELECT user_id FROM users WHERE login = $1)
+		ORDER BY o.name, c.name`, userLogin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c Collection
+		err = rows.Scan(&c.ID, &c.OrgID, &c.Organization, &c.Name, &c.Role, &c.WrappedKey)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return collections, nil
+}
+
+// AddCollectionMember добавляет участника в коллекцию или меняет его роль.
+// Приглашать участников может только владелец коллекции.
+// Новый участник коллекции становится читателем организации, если еще не состоит в ней.
+// wrappedKey - ключ коллекции, зашифрованный открытым ключом участника.
+func (db *DBStorage) AddCollectionMember(ctx context.Context, userLogin string, collectionID int64,
+	member string, role Role, wrappedKey []byte) error {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	tx, err := db.dbHandle.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ownRole, err := memberRole(ctx, tx, collectionRoleQuery, collectionID, userLogin)
+	if err != nil {
+		return err
+	}
+	if ownRole != RoleOwner {
+		return NewStorError(Forbidden, fmt.Errorf("role %s can't invite members", ownRole))
+	}
+
+	var memberID int64
+	err = tx.QueryRowContext(ctx,
+		"SELECT user_id FROM users WHERE login = $1 AND NOT deleted", member).Scan(&memberID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NewStorError(EmptyResult, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO org_members (org_id, user_id, role)
+		VALUES ((SELECT org_id FROM collections WHERE collection_id = $1), $2, $3)
+		ON CONFLICT (org_id, user_id) DO NOTHING`,
+		collectionID, memberID, RoleViewer)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO collection_members (collection_id, user_id, role, wrapped_key)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (collection_id, user_id) DO UPDATE SET role = EXCLUDED.role, wrapped_key = EXCLUDED.wrapped_key`,
+		collectionID, memberID, role, wrappedKey)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteRecordTx удаляет запись пользователя с ключевыми полями key и ее предыдущие версии.
+func deleteRecordTx[T any](ctx context.Context, tx *sql.Tx, t recordtable.Table[T],
+	userLogin string, key T) error {
+	_, err := tx.ExecContext(ctx, t.DeleteHistoryQuery(), t.GetArgs(userLogin, &key)...)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, t.DeleteQuery(), t.GetArgs(userLogin, &key)...)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return NewStorError(EmptyResult, errors.New("record not found"))
+	}
+	return nil
+}
+
+// deleteRecord удаляет личную запись пользователя любого типа в транзакции tx.
+func deleteRecord(ctx context.Context, tx *sql.Tx, userLogin string, key any) error {
+	k, err := kindOf(key)
+	if err != nil {
+		return err
+	}
+	return k.delete(ctx, tx, userLogin, key)
+}
+
+// addTombstoneQuery сохраняет ключ удаленной личной записи пользователя
+// с текущим номером ревизии пользователя.
+const addTombstoneQuery = `INSERT INTO record_tombstones (user_id, record_type, prompt, record_key, sync_revision)
+	VALUES ((SELECT user_id FROM users WHERE login = $1), $2, $3, $4,
+		(SELECT sync_revision FROM users WHERE login = $1))
+	ON CONFLICT (user_id, record_type, prompt, record_key)
+	DO UPDATE SET sync_revision = EXCLUDED.sync_revision`
+
+// MoveRecordToCollection переносит личную запись пользователя с ключевыми полями key
+// в коллекцию: сохраняет запись r, зашифрованную ключом коллекции, и удаляет личную запись
+// вместе с предыдущими версиями. Переносить записи могут владельцы и редакторы коллекции.
+// Удаление увеличивает номер ревизии пользователя, а ключ записи сохраняется,
+// чтобы другие клиенты пользователя удалили запись при синхронизации.
+func (db *DBStorage) MoveRecordToCollection(ctx context.Context, userLogin string, collectionID int64,
+	key any, r SharedRecord) error {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	tx, err := db.dbHandle.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	role, err := memberRole(ctx, tx, collectionRoleQuery, collectionID, userLogin)
+	if err != nil {
+		return err
+	}
+	if !role.canWrite() {
+		return NewStorError(Forbidden, fmt.Errorf("role %s can't add records", role))
+	}
+	deleted, ok := changeOf(key)
+	if !ok {
+		return fmt.Errorf("unsupported record type %T", key)
+	}
+
+	_, err = tx.ExecContext(ctx, bumpSyncRevisionQuery, userLogin)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO collection_records (collection_id, record_type, prompt, record_key, data, time_stamp)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (collection_id, record_type, prompt, record_key)
+		DO UPDATE SET data = EXCLUDED.data, time_stamp = EXCLUDED.time_stamp`,
+		collectionID, r.Type, nonNil(r.Prompt), nonNil(r.Key), r.Data, r.TimeStamp)
+	if err != nil {
+		return err
+	}
+
+	err = deleteRecord(ctx, tx, userLogin, key)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, addTombstoneQuery,
+		userLogin, deleted.Type, nonNil(deleted.Prompt), nonNil(deleted.Key))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListCollectionRecords получает записи коллекции, в которой участвует пользователь.
+func (db *DBStorage) ListCollectionRecords(ctx context.Context, userLogin string,
+	collectionID int64) (records []SharedRecord, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
+	defer cancel()
+
+	_, err = memberRole(ctx, db.dbHandle, collectionRoleQuery, collectionID, userLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.dbHandle.QueryContext(ctx,
+		`SELECT record_type, prompt, record_key, data, time_stamp FROM collection_records
+		WHERE collection_id = $1 ORDER BY record_type, time_stamp`, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r SharedRecord
+		err = rows.Scan(&r.Type, &r.Prompt, &r.Key, &r.Data, &r.TimeStamp)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,444 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertStorErr(t *testing.T, err error, wantErr bool, wantType TypeStorErrors) {
+	t.Helper()
+	if !wantErr {
+		assert.NoError(t, err)
+	} else if wantType != "" {
+		var storErr *StorErr
+		assert.ErrorAs(t, err, &storErr)
+		assert.Equal(t, wantType, storErr.ErrType)
+	} else {
+		assert.Error(t, err)
+	}
+}
+
+func TestSetUserKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	keys := UserKeys{PublicKey: []byte("public"), PrivateKey: []byte("private")}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		wantType     TypeStorErrors
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				mock.ExpectExec("UPDATE users SET public_key").
+					WithArgs(testUserLogin, keys.PublicKey, keys.PrivateKey).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "keys exist test",
+			mockBehavior: func() {
+				mock.ExpectExec("UPDATE users SET public_key").
+					WithArgs(testUserLogin, keys.PublicKey, keys.PrivateKey).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT user_id FROM users").
+					WithArgs(testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(int64(1)))
+			},
+			wantErr:  true,
+			wantType: AlreadyExists,
+		},
+		{
+			name: "not found test",
+			mockBehavior: func() {
+				mock.ExpectExec("UPDATE users SET public_key").
+					WithArgs(testUserLogin, keys.PublicKey, keys.PrivateKey).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT user_id FROM users").
+					WithArgs(testUserLogin).
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr:  true,
+			wantType: EmptyResult,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehavior()
+			err := testDB.SetUserKeys(context.Background(), testUserLogin, keys)
+			assertStorErr(t, err, test.wantErr, test.wantType)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetPublicKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	mock.ExpectQuery("SELECT public_key FROM users").
+		WithArgs("member").
+		WillReturnRows(sqlmock.NewRows([]string{"public_key"}).AddRow([]byte("public")))
+	key, err := testDB.GetPublicKey(context.Background(), "member")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("public"), key)
+
+	mock.ExpectQuery("SELECT public_key FROM users").
+		WithArgs("member").
+		WillReturnError(sql.ErrNoRows)
+	_, err = testDB.GetPublicKey(context.Background(), "member")
+	assertStorErr(t, err, true, EmptyResult)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateOrganization(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		want         Organization
+		wantErr      bool
+		wantType     TypeStorErrors
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO organizations").
+					WithArgs("team").
+					WillReturnRows(sqlmock.NewRows([]string{"org_id"}).AddRow(int64(3)))
+				mock.ExpectExec("INSERT INTO org_members").
+					WithArgs(int64(3), testUserLogin, RoleOwner).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: Organization{ID: 3, Name: "team", Role: RoleOwner},
+		},
+		{
+			name: "already exists test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO organizations").
+					WithArgs("team").
+					WillReturnError(&pe)
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: AlreadyExists,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehavior()
+			org, err := testDB.CreateOrganization(context.Background(), testUserLogin, "team")
+			assertStorErr(t, err, test.wantErr, test.wantType)
+			assert.Equal(t, test.want, org)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateCollection(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	wrapped := []byte("wrapped")
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		want         Collection
+		wantErr      bool
+		wantType     TypeStorErrors
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM org_members").
+					WithArgs(int64(3), testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("editor"))
+				mock.ExpectQuery("INSERT INTO collections").
+					WithArgs(int64(3), "ops").
+					WillReturnRows(sqlmock.NewRows([]string{"collection_id", "name"}).AddRow(int64(5), "team"))
+				mock.ExpectExec("INSERT INTO collection_members").
+					WithArgs(int64(5), testUserLogin, RoleOwner, wrapped).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: Collection{ID: 5, OrgID: 3, Organization: "team", Name: "ops", Role: RoleOwner, WrappedKey: wrapped},
+		},
+		{
+			name: "viewer test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM org_members").
+					WithArgs(int64(3), testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("viewer"))
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: Forbidden,
+		},
+		{
+			name: "not a member test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM org_members").
+					WithArgs(int64(3), testUserLogin).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: Forbidden,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehavior()
+			c, err := testDB.CreateCollection(context.Background(), testUserLogin, 3, "ops", wrapped)
+			assertStorErr(t, err, test.wantErr, test.wantType)
+			assert.Equal(t, test.want, c)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListCollections(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	mock.ExpectQuery("SELECT c.collection_id, c.org_id, o.name, c.name, m.role, m.wrapped_key").
+		WithArgs(testUserLogin).
+		WillReturnRows(sqlmock.NewRows([]string{"collection_id", "org_id", "org", "name", "role", "wrapped_key"}).
+			AddRow(int64(5), int64(3), "team", "ops", "viewer", []byte("wrapped")))
+	collections, err := testDB.ListCollections(context.Background(), testUserLogin)
+	assert.NoError(t, err)
+	assert.Equal(t, []Collection{
+		{ID: 5, OrgID: 3, Organization: "team", Name: "ops", Role: RoleViewer, WrappedKey: []byte("wrapped")},
+	}, collections)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddCollectionMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	wrapped := []byte("wrapped")
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		wantType     TypeStorErrors
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM collection_members").
+					WithArgs(int64(5), testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("owner"))
+				mock.ExpectQuery("SELECT user_id FROM users").
+					WithArgs("member").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(int64(9)))
+				mock.ExpectExec("INSERT INTO org_members").
+					WithArgs(int64(5), int64(9), RoleViewer).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO collection_members").
+					WithArgs(int64(5), int64(9), RoleEditor, wrapped).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "editor test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM collection_members").
+					WithArgs(int64(5), testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("editor"))
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: Forbidden,
+		},
+		{
+			name: "member not found test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM collection_members").
+					WithArgs(int64(5), testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("owner"))
+				mock.ExpectQuery("SELECT user_id FROM users").
+					WithArgs("member").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: EmptyResult,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehavior()
+			err := testDB.AddCollectionMember(context.Background(), testUserLogin, 5, "member", RoleEditor, wrapped)
+			assertStorErr(t, err, test.wantErr, test.wantType)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMoveRecordToCollection(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	ts, _ := time.Parse(time.RFC3339, testTime)
+	shared := SharedRecord{Type: CardRecord, Prompt: []byte("p"), Key: []byte("k"), Data: []byte("d"), TimeStamp: ts}
+	key := Card{Number: testCard.Number}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		wantType     TypeStorErrors
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM collection_members").
+					WithArgs(int64(5), testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("editor"))
+				mock.ExpectExec("UPDATE users SET sync_revision = sync_revision \\+ 1").
+					WithArgs(testUserLogin).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO collection_records").
+					WithArgs(int64(5), CardRecord, shared.Prompt, shared.Key, shared.Data, ts).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM cards_history").
+					WithArgs(testUserLogin, key.Number).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("DELETE FROM cards").
+					WithArgs(testUserLogin, key.Number).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO record_tombstones").
+					WithArgs(testUserLogin, CardRecord, []byte{}, key.Number).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "record not found test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM collection_members").
+					WithArgs(int64(5), testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("owner"))
+				mock.ExpectExec("UPDATE users SET sync_revision").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO collection_records").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM cards_history").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM cards").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: EmptyResult,
+		},
+		{
+			name: "viewer test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT role FROM collection_members").
+					WithArgs(int64(5), testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("viewer"))
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: Forbidden,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehavior()
+			err := testDB.MoveRecordToCollection(context.Background(), testUserLogin, 5, key, shared)
+			assertStorErr(t, err, test.wantErr, test.wantType)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListCollectionRecords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	ts, _ := time.Parse(time.RFC3339, testTime)
+
+	mock.ExpectQuery("SELECT role FROM collection_members").
+		WithArgs(int64(5), testUserLogin).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("viewer"))
+	mock.ExpectQuery("SELECT record_type, prompt, record_key, data, time_stamp FROM collection_records").
+		WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"record_type", "prompt", "record_key", "data", "time_stamp"}).
+			AddRow(int32(CardRecord), []byte("p"), []byte("k"), []byte("d"), ts))
+	records, err := testDB.ListCollectionRecords(context.Background(), testUserLogin, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []SharedRecord{
+		{Type: CardRecord, Prompt: []byte("p"), Key: []byte("k"), Data: []byte("d"), TimeStamp: ts},
+	}, records)
+
+	mock.ExpectQuery("SELECT role FROM collection_members").
+		WithArgs(int64(5), testUserLogin).
+		WillReturnError(sql.ErrNoRows)
+	_, err = testDB.ListCollectionRecords(context.Background(), testUserLogin, 5)
+	assertStorErr(t, err, true, Forbidden)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,107 +4,118 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
-	"github.com/jackc/pgerrcode"
-	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib"
 
 	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/pkg/deadline"
+	"github.com/Julia-ivv/info-keeper.git/pkg/pubsub"
 	"github.com/Julia-ivv/info-keeper.git/pkg/randomizer"
 )
 
 type DBStorage struct {
-	dbHandle *sql.DB
+	dbHandle  *sql.DB
+	dialect   dialect
+	retention int
+	quota     Quota
+	timeouts  Timeouts
+	blobs     BlobStore
+	changes   *pubsub.Broker[Change]
 }
 
-func createTables(ctx context.Context, db *sql.DB) (err error) {
-	_, err = db.ExecContext(ctx,
-		`CREATE TABLE IF NOT EXISTS users (
-			user_id serial UNIQUE, 
-			login text UNIQUE NOT NULL CHECK(login != ''), 
-			hash text NOT NULL CHECK(hash != ''),
-			salt text NOT NULL CHECK(salt != ''), 
-			PRIMARY KEY(user_id)
-		)`)
-	if err != nil {
-		return err
-	}
+// Timeouts задает время ожидания операций с БД.
+// Нулевые значения заменяются значениями по умолчанию.
+// Если у контекста запроса уже есть срок (например, от клиента gRPC), используется он.
+type Timeouts struct {
+	// Query ограничивает операции с отдельными записями и пользователями.
+	Query time.Duration
+	// Bulk ограничивает выборки для синхронизации, списки и удаление пользователя.
+	Bulk time.Duration
+}
 
-	_, err = db.ExecContext(ctx,
-		`CREATE TABLE IF NOT EXISTS logins (
-			user_id integer NOT NULL REFERENCES users(user_id),
-			prompt bytea NOT NULL,
-			login bytea NOT NULL,
-			pwd bytea NOT NULL,
-			note bytea,
-			time_stamp timestamptz (0) NOT NULL,
-			PRIMARY KEY(user_id, login, prompt)
-		)`)
-	if err != nil {
-		return err
-	}
+const (
+	defQueryTimeout = 3 * time.Second
+	defBulkTimeout  = 30 * time.Second
+)
 
-	_, err = db.ExecContext(ctx,
-		`CREATE TABLE IF NOT EXISTS cards (
-			user_id integer NOT NULL REFERENCES users(user_id),
-			prompt bytea NOT NULL,
-			number bytea NOT NULL,
-			date bytea NOT NULL,
-			code bytea NOT NULL,
-			note bytea,
-			time_stamp timestamptz (0) NOT NULL,
-			PRIMARY KEY(user_id, number)
-		)`)
-	if err != nil {
-		return err
+func (t Timeouts) query() time.Duration {
+	if t.Query <= 0 {
+		return defQueryTimeout
 	}
+	return t.Query
+}
 
-	_, err = db.ExecContext(ctx,
-		`CREATE TABLE IF NOT EXISTS text_data (
-			user_id integer NOT NULL REFERENCES users(user_id),
-			prompt bytea NOT NULL,
-			data bytea NOT NULL,
-			note bytea,
-			time_stamp timestamptz (0) NOT NULL,
-			PRIMARY KEY(user_id, prompt)
-		)`)
-	if err != nil {
-		return err
+func (t Timeouts) bulk() time.Duration {
+	if t.Bulk <= 0 {
+		return defBulkTimeout
 	}
+	return t.Bulk
+}
 
-	_, err = db.ExecContext(ctx,
-		`CREATE TABLE IF NOT EXISTS binary_data (
-			user_id integer NOT NULL REFERENCES users(user_id),
-			prompt bytea NOT NULL,
-			data bytea NOT NULL,
-			note bytea,
-			time_stamp timestamptz (0) NOT NULL,
-			PRIMARY KEY(user_id, prompt)
-		)`)
-	if err != nil {
-		return err
-	}
+// queryCtx ограничивает контекст временем ожидания одиночной операции.
+func (db *DBStorage) queryCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	return deadline.WithTimeout(ctx, db.timeouts.query())
+}
 
-	return nil
+// bulkCtx ограничивает контекст временем ожидания массовой операции.
+func (db *DBStorage) bulkCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	return deadline.WithTimeout(ctx, db.timeouts.bulk())
 }
 
+// historyTables таблицы записей, для которых хранятся предыдущие версии.
+var historyTables = func() []string {
+	names := make([]string, 0, len(recordKinds))
+	for _, k := range recordKinds {
+		names = append(names, k.tableName())
+	}
+	return names
+}()
+
 // NewDBStorage создает объект для работы с БД.
-func NewDBStorage(DBURI string) (*DBStorage, error) {
-	db, err := sql.Open("pgx", DBURI)
+// Строка подключения со схемой sqlite: открывает встроенную БД SQLite, остальные - PostgreSQL.
+// Параметр retention задает количество хранимых предыдущих версий каждой записи,
+// quota — ограничения объема данных пользователя, проверяемые при изменении записей,
+// timeouts — время ожидания операций с БД, blobs — хранилище содержимого бинарных записей;
+// если оно не задано, содержимое хранится в БД. Содержимое, сохраненное в БД до включения
+// хранилища блобов, переносится в него при создании объекта.
+func NewDBStorage(DBURI string, retention int, quota Quota, timeouts Timeouts, blobs BlobStore) (*DBStorage, error) {
+	db, d, err := openDB(DBURI)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeouts.bulk())
 	defer cancel()
 
-	err = createTables(ctx, db)
+	m, err := newMigrator(db, d)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	_, err = m.Up(ctx)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	stor := &DBStorage{
+		dbHandle:  db,
+		dialect:   d,
+		retention: retention,
+		quota:     quota,
+		timeouts:  timeouts,
+		blobs:     blobs,
+		changes:   pubsub.NewBroker[Change](),
+	}
+	err = stor.moveBlobs(context.Background())
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	return &DBStorage{dbHandle: db}, nil
+	return stor, nil
 }
 
 // Close закрывает БД.
@@ -112,9 +123,15 @@ func (db *DBStorage) Close() error {
 	return db.dbHandle.Close()
 }
 
+// Stats возвращает статистику пула соединений с БД.
+func (db *DBStorage) Stats() sql.DBStats {
+	return db.dbHandle.Stats()
+}
+
 // RegUser добавляет нового пользователя в БД.
+// Если логин занят, возвращается ошибка AlreadyExists.
 func (db *DBStorage) RegUser(ctx context.Context, login string, pwd string) error {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	ctx, cancel := db.queryCtx(ctx)
 	defer cancel()
 
 	salt, err := randomizer.GenerateRandomString(LengthSalt)
@@ -125,7 +142,7 @@ func (db *DBStorage) RegUser(ctx context.Context, login string, pwd string) erro
 		"INSERT INTO users (login, hash, salt) VALUES ($1, $2, $3)",
 		login, hash(pwd, salt), salt)
 	if err != nil {
-		return err
+		return uniqueErr(err)
 	}
 	rows, err := result.RowsAffected()
 	if err != nil {
@@ -140,7 +157,7 @@ func (db *DBStorage) RegUser(ctx context.Context, login string, pwd string) erro
 
 // AuthUser аутентифицирует пользователя.
 func (db *DBStorage) AuthUser(ctx context.Context, login string, pwd string) error {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	ctx, cancel := db.queryCtx(ctx)
 	defer cancel()
 
 	row := db.dbHandle.QueryRowContext(ctx,
@@ -160,320 +177,287 @@ func (db *DBStorage) AuthUser(ctx context.Context, login string, pwd string) err
 	return nil
 }
 
-// AddCard добавляет информацию о банковской карте.
-func (db *DBStorage) AddCard(ctx context.Context, userLogin string, prompt []byte,
-	number []byte, date []byte, code []byte, note []byte, timeStamp time.Time) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// GetSyncRevision получает номер последней ревизии данных пользователя.
+// Номер увеличивается сервером при каждом изменении записей пользователя.
+func (db *DBStorage) GetSyncRevision(ctx context.Context, userLogin string) (revision int64, err error) {
+	ctx, cancel := db.queryCtx(ctx)
 	defer cancel()
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`INSERT INTO cards (user_id , prompt, number, date, code, note, time_stamp) 
-			VALUES ((SELECT user_id FROM users WHERE login = $1), $2, $3, $4, $5, $6, $7)`,
-		userLogin, prompt, number, date, code, note, timeStamp)
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			row := db.dbHandle.QueryRowContext(ctx,
-				`SELECT time_stamp FROM cards 
-				WHERE number = $1 AND 
-				user_id = (SELECT user_id FROM users WHERE login = $2)`, number, userLogin)
-			var tServer time.Time
-			errScan := row.Scan(&tServer)
-			if errScan != nil {
-				return errScan
-			}
-			if tServer.After(timeStamp) {
-				return NewStorError(ExistsDataNewerVersion, err)
-			}
-			result, err = db.dbHandle.ExecContext(ctx,
-				`UPDATE cards 
-				SET prompt = $1, date = $2, code = $3, note = $4, time_stamp = $5
-				WHERE user_id = (SELECT user_id FROM users WHERE login = $6)
-				AND number = $7`,
-				prompt, date, code, note, timeStamp, userLogin, number)
-			if err != nil {
-				return err
-			}
-			rowUpd, errUpd := result.RowsAffected()
-			if errUpd != nil {
-				return errUpd
-			}
-			if rowUpd != 1 {
-				return errors.New("expected to affect 1 row")
-			}
-			return nil
-		}
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.NotNullViolation {
-			return NewStorError(NullValues, err)
-		}
-		return err
+	row := db.dbHandle.QueryRowContext(ctx,
+		"SELECT sync_revision FROM users WHERE login = $1", userLogin)
+	err = row.Scan(&revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, NewStorError(EmptyResult, err)
 	}
-
-	rows, err := result.RowsAffected()
 	if err != nil {
-		return err
-	}
-	if rows != 1 {
-		return errors.New("expected to affect 1 row")
+		return 0, err
 	}
 
-	return nil
+	return revision, nil
+}
+
+// DeletedRecord хранит тип и ключевые поля удаленной личной записи пользователя
+// в том же виде, что и в запросе GetRecord.
+type DeletedRecord struct {
+	Type   RecordType
+	Prompt []byte
+	Key    []byte
 }
 
-// AddLoginPwd добавляет информацию о паре логин-пароль.
-func (db *DBStorage) AddLoginPwd(ctx context.Context, userLogin string, prompt []byte,
-	login []byte, pwd []byte, note []byte, timeStamp time.Time) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// GetDeletedRecordsAfterRevision получает ключи личных записей пользователя,
+// удаленных на ревизиях после since и не позднее until.
+func (db *DBStorage) GetDeletedRecordsAfterRevision(ctx context.Context, userLogin string,
+	since int64, until int64) (records []DeletedRecord, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
 	defer cancel()
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`INSERT INTO logins (user_id , prompt, login, pwd, note, time_stamp) 
-		VALUES ((SELECT user_id FROM users WHERE login = $1), $2, $3, $4, $5, $6)`,
-		userLogin, prompt, login, pwd, note, timeStamp)
-
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			row := db.dbHandle.QueryRowContext(ctx,
-				`SELECT time_stamp FROM logins 
-				WHERE prompt = $1 
-				AND login = $2 
-				AND user_id = (SELECT user_id FROM users WHERE login = $3)`,
-				prompt, login, userLogin)
-			var tServer time.Time
-			errScan := row.Scan(&tServer)
-			if errScan != nil {
-				return errScan
-			}
-			if tServer.After(timeStamp) {
-				return NewStorError(ExistsDataNewerVersion, err)
-			}
-			result, err = db.dbHandle.ExecContext(ctx,
-				`UPDATE logins 
-				SET pwd = $1, note = $2, time_stamp = $3
-				WHERE user_id = (SELECT user_id FROM users WHERE login = $4)
-				AND prompt = $5
-				AND login = $6`,
-				pwd, note, timeStamp, userLogin, prompt, login)
-			if err != nil {
-				return err
-			}
-			rowUpd, errUpd := result.RowsAffected()
-			if errUpd != nil {
-				return errUpd
-			}
-			if rowUpd != 1 {
-				return errors.New("expected to affect 1 row")
-			}
-			return nil
-		}
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.NotNullViolation {
-			return NewStorError(NullValues, err)
-		}
-		return err
+	rows, err := db.dbHandle.QueryContext(ctx,
+		`SELECT record_type, prompt, record_key FROM record_tombstones
+		WHERE user_id = (SELECT user_id FROM users WHERE login = $1)
+			AND sync_revision > $2 AND sync_revision <= $3
+		ORDER BY sync_revision`, userLogin, since, until)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
+	for rows.Next() {
+		var r DeletedRecord
+		err = rows.Scan(&r.Type, &r.Prompt, &r.Key)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
 	}
-	if rows != 1 {
-		return errors.New("expected to affect 1 row")
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return records, nil
 }
 
-// AddTextRecord раелизует добавление текстовой информации.
-func (db *DBStorage) AddTextRecord(ctx context.Context, userLogin string, prompt []byte,
-	data []byte, note []byte, timeStamp time.Time) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	result, err := db.dbHandle.ExecContext(ctx,
-		`INSERT INTO text_data (user_id , prompt, data, note, time_stamp) 
-		VALUES ((SELECT user_id FROM users WHERE login = $1), $2, $3, $4, $5)`,
-		userLogin, prompt, data, note, timeStamp)
-
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			row := db.dbHandle.QueryRowContext(ctx,
-				`SELECT time_stamp FROM text_data 
-				WHERE prompt = $1 AND 
-				user_id = (SELECT user_id FROM users WHERE login = $2)`, prompt, userLogin)
-			var tServer time.Time
-			errScan := row.Scan(&tServer)
-			if errScan != nil {
-				return errScan
-			}
-			if tServer.After(timeStamp) {
-				return NewStorError(ExistsDataNewerVersion, err)
-			}
-			result, err = db.dbHandle.ExecContext(ctx,
-				`UPDATE text_data 
-				SET data = $1, note = $2, time_stamp = $3
-				WHERE user_id = (SELECT user_id FROM users WHERE login = $4)
-				AND prompt = $5`,
-				data, note, timeStamp, userLogin, prompt)
-			if err != nil {
-				return err
-			}
-			rowUpd, errUpd := result.RowsAffected()
-			if errUpd != nil {
-				return errUpd
-			}
-			if rowUpd != 1 {
-				return errors.New("expected to affect 1 row")
-			}
-			return nil
-		}
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.NotNullViolation {
-			return NewStorError(NullValues, err)
-		}
-		return err
-	}
+// Card хранит информацию о банковской карте.
+type Card struct {
+	Prompt    []byte
+	Number    []byte
+	Date      []byte
+	Code      []byte
+	Note      []byte
+	Tags      []byte
+	Folder    []byte
+	TimeStamp time.Time
+}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rows != 1 {
-		return errors.New("expected to affect 1 row")
-	}
+// LoginPwd хранит информацию о парах логин-пароль.
+type LoginPwd struct {
+	Prompt    []byte
+	Login     []byte
+	Pwd       []byte
+	Note      []byte
+	Tags      []byte
+	Folder    []byte
+	TimeStamp time.Time
+}
 
-	return nil
+// TextRecord хранит текстовую информацию.
+type TextRecord struct {
+	Prompt    []byte
+	Data      []byte
+	Note      []byte
+	Tags      []byte
+	Folder    []byte
+	TimeStamp time.Time
 }
 
-// AddBinaryRecord реализует добавление бинарной информации в БД.
-func (db *DBStorage) AddBinaryRecord(ctx context.Context, userLogin string, prompt []byte,
-	data []byte, note []byte, timeStamp time.Time) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
+// BinaryRecord хранит бинарные данные.
+type BinaryRecord struct {
+	Prompt    []byte
+	Data      []byte
+	Note      []byte
+	Tags      []byte
+	Folder    []byte
+	TimeStamp time.Time
+	blob      blob
+}
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`INSERT INTO binary_data (user_id , prompt, data, note, time_stamp) 
-		VALUES ((SELECT user_id FROM users WHERE login = $1), $2, $3, $4, $5)`,
-		userLogin, prompt, data, note, timeStamp)
-
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			row := db.dbHandle.QueryRowContext(ctx,
-				`SELECT time_stamp FROM binary_data 
-				WHERE prompt = $1 AND 
-				user_id = (SELECT user_id FROM users WHERE login = $2)`, prompt, userLogin)
-			var tServer time.Time
-			errScan := row.Scan(&tServer)
-			if errScan != nil {
-				return errScan
-			}
-			if tServer.After(timeStamp) {
-				return NewStorError(ExistsDataNewerVersion, err)
-			}
-			result, err = db.dbHandle.ExecContext(ctx,
-				`UPDATE binary_data 
-				SET data = $1, note = $2, time_stamp = $3
-				WHERE user_id = (SELECT user_id FROM users WHERE login = $4)
-				AND prompt = $5`,
-				data, note, timeStamp, userLogin, prompt)
-			if err != nil {
-				return err
-			}
-			rowUpd, errUpd := result.RowsAffected()
-			if errUpd != nil {
-				return errUpd
-			}
-			if rowUpd != 1 {
-				return errors.New("expected to affect 1 row")
-			}
-			return nil
-		}
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.NotNullViolation {
-			return NewStorError(NullValues, err)
-		}
-		return err
+// Size возвращает размер содержимого записи, в том числе хранящегося в хранилище блобов.
+func (r BinaryRecord) Size() int64 {
+	if r.blob.ref != "" {
+		return r.blob.size
 	}
+	return int64(len(r.Data))
+}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rows != 1 {
-		return errors.New("expected to affect 1 row")
-	}
+// Otp хранит параметры генерации одноразовых кодов.
+type Otp struct {
+	Issuer    []byte
+	Account   []byte
+	Secret    []byte
+	Algorithm []byte
+	Digits    []byte
+	Period    []byte
+	Note      []byte
+	Tags      []byte
+	Folder    []byte
+	TimeStamp time.Time
+}
 
-	return nil
+// SshKey хранит ключ SSH.
+type SshKey struct {
+	Prompt     []byte
+	PrivateKey []byte
+	PublicKey  []byte
+	Comment    []byte
+	Passphrase []byte
+	Tags       []byte
+	Folder     []byte
+	TimeStamp  time.Time
 }
 
-// Card хранит информацию о банковской карте.
-type Card struct {
+// Template хранит шаблон пользовательских записей.
+type Template struct {
+	Name      []byte
+	Fields    []byte
+	Tags      []byte
+	Folder    []byte
+	TimeStamp time.Time
+}
+
+// CustomRecord хранит пользовательскую запись, созданную по шаблону.
+type CustomRecord struct {
 	Prompt    []byte
-	Number    []byte
-	Date      []byte
-	Code      []byte
+	Template  []byte
+	Data      []byte
 	Note      []byte
+	Tags      []byte
+	Folder    []byte
 	TimeStamp time.Time
 }
 
-// GetCard получает информацию о банковской карте.
-func (db *DBStorage) GetCard(ctx context.Context, userLogin string, number []byte) (card Card, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
+// AddRecord добавляет запись пользователя r одного из типов Card, LoginPwd, TextRecord,
+// BinaryRecord, Otp, SshKey, Template или CustomRecord.
+// Если запись уже существует, она обновляется, только если на сервере нет более новой версии.
+// Если задано хранилище блобов, содержимое бинарной записи сохраняется в нем, а в БД - только ссылка на него.
+// Возвращается ревизия сохраненной записи.
+func (db *DBStorage) AddRecord(ctx context.Context, userLogin string, r any) (revision Revision, err error) {
+	k, err := kindOf(r)
+	if err != nil {
+		return Revision{}, err
+	}
+	return k.add(ctx, db, userLogin, r)
+}
 
-	row := db.dbHandle.QueryRowContext(ctx,
-		`SELECT prompt, date, code, note, time_stamp
-		FROM cards
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $1)
-		AND number = $2`, userLogin, number)
+// GetRecord получает запись пользователя с ключевыми полями key.
+// Запись возвращается значением того же типа, что и key.
+func (db *DBStorage) GetRecord(ctx context.Context, userLogin string, key any) (record any, err error) {
+	k, err := kindOf(key)
+	if err != nil {
+		return nil, err
+	}
+	return k.get(ctx, db, userLogin, key)
+}
 
-	var prompt, date, code, note []byte
-	var timeStamp time.Time
-	err = row.Scan(&prompt, &date, &code, &note, &timeStamp)
+// ForceUpdateRecord обновляет запись пользователя r без проверки времени изменения
+// и возвращает ее ревизию.
+func (db *DBStorage) ForceUpdateRecord(ctx context.Context, userLogin string, r any) (revision Revision, err error) {
+	k, err := kindOf(r)
+	if err != nil {
+		return Revision{}, err
+	}
+	return k.forceUpdate(ctx, db, userLogin, r)
+}
+
+// GetRecordsAfterRevision получает записи пользователя типа t,
+// измененные на ревизиях после since и не позднее until.
+// Содержимое бинарных записей, хранящееся в хранилище блобов, не загружается, его размер возвращает метод Size.
+func (db *DBStorage) GetRecordsAfterRevision(ctx context.Context, userLogin string, t RecordType,
+	since int64, until int64) (records []any, err error) {
+	k, err := kindByType(t)
 	if err != nil {
-		return Card{}, err
+		return nil, err
 	}
+	return k.afterRevision(ctx, db, userLogin, since, until)
+}
+
+// RecordType тип записи пользователя.
+type RecordType int32
+
+const (
+	// AnyRecord - запись любого типа.
+	AnyRecord RecordType = iota
+	// CardRecord - банковская карта.
+	CardRecord
+	// LoginPwdRecord - пара логин-пароль.
+	LoginPwdRecord
+	// TextDataRecord - текстовые данные.
+	TextDataRecord
+	// BinaryDataRecord - бинарные данные.
+	BinaryDataRecord
+	// OtpRecord - параметры генерации одноразовых кодов.
+	OtpRecord
+	// SshKeyRecord - ключ SSH.
+	SshKeyRecord
+	// TemplateRecord - шаблон пользовательских записей.
+	TemplateRecord
+	// CustomDataRecord - пользовательская запись по шаблону.
+	CustomDataRecord
+)
+
+// RecordInfo хранит информацию о записи без ее данных.
+type RecordInfo struct {
+	Type      RecordType
+	Prompt    []byte
+	TimeStamp time.Time
+	Size      int64
+}
 
-	return Card{
-		Prompt:    prompt,
-		Number:    number,
-		Date:      date,
-		Code:      code,
-		Note:      note,
-		TimeStamp: timeStamp,
-	}, nil
+// ListFilter хранит условия отбора записей для ListRecords.
+type ListFilter struct {
+	Type          RecordType
+	ModifiedSince time.Time
+	Offset        int
+	Limit         int
 }
 
-// GetUserCardsAfterTime - получает все банковские карты пользователя,
-// добавленные или измененные после указанного времени.
-func (db *DBStorage) GetUserCardsAfterTime(ctx context.Context, userLogin string, afterTime time.Time) (cards []Card, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// ListRecords получает информацию о записях пользователя без их данных.
+// Записи упорядочены по типу и подсказке.
+func (db *DBStorage) ListRecords(ctx context.Context, userLogin string, filter ListFilter) (records []RecordInfo, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
 	defer cancel()
 
+	cond, args := "time_stamp > $2", []any{userLogin, filter.ModifiedSince}
+	if filter.Type != AnyRecord {
+		cond += " AND record_type = $3"
+		args = append(args, filter.Type)
+	}
+	args = append(args, filter.Limit, filter.Offset)
 	rows, err := db.dbHandle.QueryContext(ctx,
-		`SELECT prompt, number, date, code, note, time_stamp
-		FROM cards
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $1)
-		AND time_stamp > $2`, userLogin, afterTime)
+		`SELECT record_type, prompt, time_stamp, size FROM (
+			`+kindsQuery(kind.listQuery)+`
+		) AS records
+		WHERE `+cond+`
+		ORDER BY record_type, prompt, record_key
+		LIMIT `+fmt.Sprintf("$%d OFFSET $%d", len(args)-1, len(args)),
+		args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var prompt, number, date, code, note []byte
+	var prompt []byte
+	var recordType RecordType
 	var timeStamp time.Time
+	var size int64
 	for rows.Next() {
-		err = rows.Scan(&prompt, &number, &date, &code, &note, &timeStamp)
+		err = rows.Scan(&recordType, &prompt, &timeStamp, &size)
 		if err != nil {
 			return nil, err
 		}
-		cards = append(cards, Card{
+		records = append(records, RecordInfo{
+			Type:      recordType,
 			Prompt:    prompt,
-			Number:    number,
-			Date:      date,
-			Code:      code,
-			Note:      note,
 			TimeStamp: timeStamp,
+			Size:      size,
 		})
 	}
 
@@ -482,75 +466,49 @@ func (db *DBStorage) GetUserCardsAfterTime(ctx context.Context, userLogin string
 		return nil, err
 	}
 
-	return cards, nil
+	return records, nil
 }
 
-// LoginPwd хранит информацию о парах логин-пароль.
-type LoginPwd struct {
-	Prompt    []byte
-	Login     []byte
-	Pwd       []byte
-	Note      []byte
-	TimeStamp time.Time
+// Usage хранит количество и суммарный размер записей пользователя одного типа.
+// Размер записи - сумма длин всех ее полей, кроме времени изменения.
+type Usage struct {
+	Type    RecordType
+	Records int64
+	Bytes   int64
 }
 
-// GetLoginPwd получает информацию о паре логин-пароль.
-func (db *DBStorage) GetLoginPwd(ctx context.Context, userLogin string, prompt []byte, login []byte) (loginPwd LoginPwd, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
+// recordSizesQuery возвращает пользователя, тип и размер каждой записи.
+var recordSizesQuery = kindsQuery(kind.sizeQuery)
 
-	row := db.dbHandle.QueryRowContext(ctx,
-		`SELECT pwd, note, time_stamp
-		FROM logins
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $1)
-		AND prompt = $2 AND login = $3`, userLogin, prompt, login)
-
-	var pwd, note []byte
-	var timeStamp time.Time
-	err = row.Scan(&pwd, &note, &timeStamp)
-	if err != nil {
-		return LoginPwd{}, err
-	}
+// GetUsage получает количество и размер записей пользователя по типам.
+// Типы, для которых у пользователя нет записей, не возвращаются.
+func (db *DBStorage) GetUsage(ctx context.Context, userLogin string) (usage []Usage, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
+	defer cancel()
 
-	return LoginPwd{
-		Prompt:    prompt,
-		Login:     login,
-		Pwd:       pwd,
-		Note:      note,
-		TimeStamp: timeStamp,
-	}, nil
+	return userUsage(ctx, db.dbHandle, userLogin)
 }
 
-// GetUserLoginsPwdsAfterTime получает информацию о парах логин-пароль пользователя,
-// добавленных или измененных после указанного времени.
-func (db *DBStorage) GetUserLoginsPwdsAfterTime(ctx context.Context, userLogin string, afterTime time.Time) (loginsPwds []LoginPwd, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	rows, err := db.dbHandle.QueryContext(ctx,
-		`SELECT prompt, login, pwd, note, time_stamp
-		FROM logins
+// userUsage получает количество и размер записей пользователя по типам запросом q.
+func userUsage(ctx context.Context, q querier, userLogin string) (usage []Usage, err error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT record_type, count(*), coalesce(sum(size), 0) FROM (`+recordSizesQuery+`) AS records
 		WHERE user_id = (SELECT user_id FROM users WHERE login = $1)
-		AND time_stamp > $2`, userLogin, afterTime)
+		GROUP BY record_type
+		ORDER BY record_type`,
+		userLogin)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var prompt, login, pwd, note []byte
-	var timeStamp time.Time
 	for rows.Next() {
-		err = rows.Scan(&prompt, &login, &pwd, &note, &timeStamp)
+		var u Usage
+		err = rows.Scan(&u.Type, &u.Records, &u.Bytes)
 		if err != nil {
 			return nil, err
 		}
-		loginsPwds = append(loginsPwds, LoginPwd{
-			Prompt:    prompt,
-			Login:     login,
-			Pwd:       pwd,
-			Note:      note,
-			TimeStamp: timeStamp,
-		})
+		usage = append(usage, u)
 	}
 
 	err = rows.Err()
@@ -558,250 +516,664 @@ func (db *DBStorage) GetUserLoginsPwdsAfterTime(ctx context.Context, userLogin s
 		return nil, err
 	}
 
-	return loginsPwds, nil
+	return usage, nil
 }
 
-// TextRecord хранит текстовую информацию.
-type TextRecord struct {
-	Prompt    []byte
-	Data      []byte
-	Note      []byte
+// GetRecordSize получает размер записи пользователя с ключевыми полями key
+// без загрузки ее содержимого. Если записи нет, возвращается ошибка EmptyResult.
+func (db *DBStorage) GetRecordSize(ctx context.Context, userLogin string, key any) (size int64, err error) {
+	k, err := kindOf(key)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	return k.size(ctx, db.dbHandle, userLogin, key)
+}
+
+// Quota задает ограничения объема данных пользователя. Нулевые значения - без ограничений.
+type Quota struct {
+	// Bytes - максимальный суммарный размер записей пользователя в байтах.
+	Bytes int64
+	// Records - максимальное количество записей пользователя каждого типа.
+	Records int64
+}
+
+// quotaWrite хранит размер записи до изменения для проверки квот после него.
+type quotaWrite struct {
+	quota   Quota
+	k       kind
+	existed bool
+	size    int64
+}
+
+// beginQuota запоминает размер записи r до ее изменения в транзакции tx.
+// Если квоты не заданы, размер не запрашивается.
+func (db *DBStorage) beginQuota(ctx context.Context, tx *sql.Tx, userLogin string, r any) (quotaWrite, error) {
+	if db.quota == (Quota{}) {
+		return quotaWrite{}, nil
+	}
+	k, err := kindOf(r)
+	if err != nil {
+		return quotaWrite{}, err
+	}
+	w := quotaWrite{quota: db.quota, k: k, existed: true}
+	w.size, err = k.size(ctx, tx, userLogin, r)
+	var storErr *StorErr
+	if errors.As(err, &storErr) && storErr.ErrType == EmptyResult {
+		w.existed = false
+		err = nil
+	}
+	return w, err
+}
+
+// check проверяет после изменения записи r в транзакции tx, что объем данных пользователя
+// не превышает квоты. Проверяются только добавление записи и увеличение ее размера,
+// поэтому уменьшение записей пользователя, уже превысившего квоту, разрешено.
+func (w quotaWrite) check(ctx context.Context, tx *sql.Tx, userLogin string, r any) error {
+	if w.quota == (Quota{}) {
+		return nil
+	}
+	size, err := w.k.size(ctx, tx, userLogin, r)
+	var storErr *StorErr
+	if errors.As(err, &storErr) && storErr.ErrType == EmptyResult {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	added := !w.existed && w.quota.Records > 0
+	grown := size > w.size && w.quota.Bytes > 0
+	if !added && !grown {
+		return nil
+	}
+
+	usage, err := userUsage(ctx, tx, userLogin)
+	if err != nil {
+		return err
+	}
+	var records, bytes int64
+	for _, u := range usage {
+		if u.Type == w.k.recordType() {
+			records = u.Records
+		}
+		bytes += u.Bytes
+	}
+	if added && records > w.quota.Records {
+		return NewStorError(QuotaExceeded, fmt.Errorf("number of records exceeds %d", w.quota.Records))
+	}
+	if grown && bytes > w.quota.Bytes {
+		return NewStorError(QuotaExceeded, fmt.Errorf("total size of records exceeds %d bytes", w.quota.Bytes))
+	}
+	return nil
+}
+
+// Version хранит предыдущую версию записи.
+// Record содержит запись одного из типов Card, LoginPwd, TextRecord, BinaryRecord,
+// Otp, SshKey, Template или CustomRecord.
+type Version struct {
+	ID     int64
+	Record any
+}
+
+// ListVersions получает предыдущие версии записи пользователя с ключевыми полями key, начиная с последней.
+func (db *DBStorage) ListVersions(ctx context.Context, userLogin string, key any) (versions []Version, err error) {
+	k, err := kindOf(key)
+	if err != nil {
+		return nil, err
+	}
+	return k.versions(ctx, db, userLogin, key)
+}
+
+// Revision хранит идентификатор, номер ревизии и время изменения записи на сервере.
+type Revision struct {
+	ID        int64
+	Revision  int64
 	TimeStamp time.Time
 }
 
-// GetTextRecord получает текстовую информацию.
-func (db *DBStorage) GetTextRecord(ctx context.Context, userLogin string, prompt []byte) (record TextRecord, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
+// GetRevision получает текущую ревизию записи пользователя с ключевыми полями key.
+func (db *DBStorage) GetRevision(ctx context.Context, userLogin string, key any) (revision Revision, err error) {
+	k, err := kindOf(key)
+	if err != nil {
+		return Revision{}, err
+	}
+	return k.revision(ctx, db, userLogin, key)
+}
 
-	row := db.dbHandle.QueryRowContext(ctx,
-		`SELECT data, note, time_stamp
-		FROM text_data
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $1)
-		AND prompt = $2`, userLogin, prompt)
+// RestoreVersion заменяет запись пользователя с ключевыми полями key версией с номером version.
+// Восстановленная запись получает время изменения timeStamp.
+func (db *DBStorage) RestoreVersion(ctx context.Context, userLogin string, key any,
+	version int64, timeStamp time.Time) (record any, err error) {
+	k, err := kindOf(key)
+	if err != nil {
+		return nil, err
+	}
+	return k.restore(ctx, db, userLogin, key, version, timeStamp)
+}
 
-	var data, note []byte
-	var timeStamp time.Time
-	err = row.Scan(&data, &note, &timeStamp)
+// Change хранит информацию об изменении записи пользователя.
+// Prompt и Key содержат ключевые поля записи в том же виде, что и в запросе GetRecord.
+type Change struct {
+	Type      RecordType
+	Prompt    []byte
+	Key       []byte
+	TimeStamp time.Time
+}
+
+// changeOf возвращает информацию об изменении записи r.
+func changeOf(r any) (c Change, ok bool) {
+	k, err := kindOf(r)
 	if err != nil {
-		return TextRecord{}, err
+		return Change{}, false
+	}
+	return k.change(r), true
+}
+
+// notify сообщает подписчикам пользователя об изменении записи r.
+func (db *DBStorage) notify(userLogin string, r any) {
+	if db.changes == nil {
+		return
 	}
+	c, ok := changeOf(r)
+	if ok {
+		db.changes.Publish(userLogin, c)
+	}
+}
+
+// WatchChanges подписывается на изменения записей пользователя.
+// Канал закрывается после завершения контекста.
+func (db *DBStorage) WatchChanges(ctx context.Context, userLogin string) <-chan Change {
+	return db.changes.Subscribe(ctx, userLogin)
+}
+
+// AuditAction действие пользователя с записью, сохраняемое в журнале аудита.
+type AuditAction string
+
+const (
+	// AuditRead - чтение записи.
+	AuditRead AuditAction = "read"
+	// AuditCreate - создание записи.
+	AuditCreate AuditAction = "create"
+	// AuditUpdate - обновление записи при синхронизации.
+	AuditUpdate AuditAction = "update"
+	// AuditForceUpdate - принудительное обновление записи.
+	AuditForceUpdate AuditAction = "force_update"
+	// AuditRestore - восстановление предыдущей версии записи.
+	AuditRestore AuditAction = "restore"
+	// AuditMove - перенос записи в общую коллекцию.
+	AuditMove AuditAction = "move"
+)
 
-	return TextRecord{
-		Prompt:    prompt,
-		Data:      data,
-		Note:      note,
-		TimeStamp: timeStamp,
-	}, nil
+// AuditEvent хранит событие журнала аудита.
+// Prompt и Key содержат ключевые поля записи в том же виде, что и в запросе GetRecord.
+type AuditEvent struct {
+	Action    AuditAction
+	Type      RecordType
+	Prompt    []byte
+	Key       []byte
+	Device    string
+	IP        string
+	TimeStamp time.Time
 }
 
-// GetUserTextRecordsAfterTime получает все текстовые данные пользователя,
-// добавленные или измененнные после указанного времени.
-func (db *DBStorage) GetUserTextRecordsAfterTime(ctx context.Context, userLogin string, afterTime time.Time) (records []TextRecord, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// AddAuditEvent добавляет событие в журнал аудита пользователя.
+func (db *DBStorage) AddAuditEvent(ctx context.Context, userLogin string, e AuditEvent) error {
+	ctx, cancel := db.queryCtx(ctx)
 	defer cancel()
 
+	_, err := db.dbHandle.ExecContext(ctx,
+		`INSERT INTO audit_log (user_id, action, record_type, prompt, record_key, device, ip, time_stamp)
+		VALUES ((SELECT user_id FROM users WHERE login = $1), $2, $3, $4, $5, $6, $7, $8)`,
+		userLogin, e.Action, e.Type, e.Prompt, e.Key, e.Device, e.IP, e.TimeStamp)
+	return err
+}
+
+// GetAuditLog получает события журнала аудита пользователя в интервале [from, to), начиная с ранних.
+// Нулевое значение to означает отсутствие ограничения сверху.
+func (db *DBStorage) GetAuditLog(ctx context.Context, userLogin string, from time.Time, to time.Time) (events []AuditEvent, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
+	defer cancel()
+
+	cond, args := "time_stamp >= $2", []any{userLogin, from}
+	if !to.IsZero() {
+		cond += " AND time_stamp < $3"
+		args = append(args, to)
+	}
 	rows, err := db.dbHandle.QueryContext(ctx,
-		`SELECT prompt, data, note, time_stamp
-		FROM text_data
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $1)
-		AND time_stamp > $2`, userLogin, afterTime)
+		`SELECT action, record_type, prompt, record_key, device, ip, time_stamp FROM audit_log
+		WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND `+cond+`
+		ORDER BY time_stamp, event_id`,
+		args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var prompt, data, note []byte
-		var timeStamp time.Time
-		err = rows.Scan(&prompt, &data, &note, &timeStamp)
+		var e AuditEvent
+		err = rows.Scan(&e.Action, &e.Type, &e.Prompt, &e.Key, &e.Device, &e.IP, &e.TimeStamp)
 		if err != nil {
 			return nil, err
 		}
-		records = append(records, TextRecord{
-			Prompt:    prompt,
-			Data:      data,
-			Note:      note,
-			TimeStamp: timeStamp,
-		})
+		events = append(events, e)
 	}
 
 	err = rows.Err()
 	if err != nil {
 		return nil, err
 	}
-	return records, nil
+
+	return events, nil
 }
 
-// BinaryRecord хранит бинарные данные.
-type BinaryRecord struct {
-	Prompt    []byte
-	Data      []byte
-	Note      []byte
-	TimeStamp time.Time
+// Session хранит состояние учетной записи пользователя для проверки токена.
+type Session struct {
+	UserID   int64
+	Version  int64
+	Disabled bool
 }
 
-// GetBinaryRecord получает бинарные данные.
-func (db *DBStorage) GetBinaryRecord(ctx context.Context, userLogin string, prompt []byte) (record BinaryRecord, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// GetSession получает состояние учетной записи пользователя.
+func (db *DBStorage) GetSession(ctx context.Context, userLogin string) (s Session, err error) {
+	ctx, cancel := db.queryCtx(ctx)
 	defer cancel()
 
-	row := db.dbHandle.QueryRowContext(ctx,
-		`SELECT data, note, time_stamp
-		FROM binary_data
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $1)
-		AND prompt = $2`, userLogin, prompt)
-
-	var data, note []byte
-	var timeStamp time.Time
-	err = row.Scan(&data, &note, &timeStamp)
+	err = db.dbHandle.QueryRowContext(ctx,
+		"SELECT user_id, session, disabled FROM users WHERE login = $1 AND NOT deleted", userLogin).
+		Scan(&s.UserID, &s.Version, &s.Disabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, NewStorError(EmptyResult, err)
+	}
 	if err != nil {
-		return BinaryRecord{}, err
+		return Session{}, err
 	}
+	return s, nil
+}
 
-	return BinaryRecord{
-		Prompt:    prompt,
-		Data:      data,
-		Note:      note,
-		TimeStamp: timeStamp,
-	}, nil
+// UserInfo хранит сведения о пользователе для администратора.
+type UserInfo struct {
+	Login    string
+	Disabled bool
+	Records  int64
+	Bytes    int64
 }
 
-// GetUserBinaryRecordsAfterTime получает все бинарные данные пользователя,
-// добавленные или измененные после указанного времени.
-func (db *DBStorage) GetUserBinaryRecordsAfterTime(ctx context.Context, userLogin string, afterTime time.Time) (records []BinaryRecord, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// ListUsers получает всех пользователей с количеством и общим размером их записей.
+func (db *DBStorage) ListUsers(ctx context.Context) (users []UserInfo, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
 	defer cancel()
 
 	rows, err := db.dbHandle.QueryContext(ctx,
-		`SELECT prompt, data, note, time_stamp
-		FROM binary_data
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $1)
-		AND time_stamp > $2`, userLogin, afterTime)
+		`SELECT u.login, u.disabled, count(r.user_id), coalesce(sum(r.size), 0)
+		FROM users u LEFT JOIN (`+recordSizesQuery+`) AS r ON r.user_id = u.user_id
+		WHERE NOT u.deleted
+		GROUP BY u.user_id, u.login, u.disabled
+		ORDER BY u.login`)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var prompt, data, note []byte
-		var timeStamp time.Time
-		err = rows.Scan(&prompt, &data, &note, &timeStamp)
+		var u UserInfo
+		err = rows.Scan(&u.Login, &u.Disabled, &u.Records, &u.Bytes)
 		if err != nil {
 			return nil, err
 		}
-		records = append(records, BinaryRecord{
-			Prompt:    prompt,
-			Data:      data,
-			Note:      note,
-			TimeStamp: timeStamp,
-		})
+		users = append(users, u)
 	}
 
 	err = rows.Err()
 	if err != nil {
 		return nil, err
 	}
-	return records, nil
+
+	return users, nil
 }
 
-// ForceUpdateCard обновляет информацию о банковской карте.
-func (db *DBStorage) ForceUpdateCard(ctx context.Context, userLogin string, prompt []byte,
-	number []byte, date []byte, code []byte, note []byte, timeStamp time.Time) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// execUser выполняет запрос query к учетной записи пользователя userLogin.
+// Если пользователь не найден, возвращается ошибка EmptyResult.
+func (db *DBStorage) execUser(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := db.queryCtx(ctx)
 	defer cancel()
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`UPDATE cards 
-		SET prompt = $1, date = $2, code = $3, note = $4, time_stamp = $5
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $6)
-		AND number = $7`,
-		prompt, date, code, note, timeStamp, userLogin, number)
+	result, err := db.dbHandle.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
-	row, err := result.RowsAffected()
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
-	if row != 1 {
-		return errors.New("expected to affect 1 row")
+	if rows == 0 {
+		return NewStorError(EmptyResult, errors.New("user not found"))
 	}
 	return nil
 }
 
-// ForceUpdateLoginPwd обновляет информацию о паре логин-пароль.
-func (db *DBStorage) ForceUpdateLoginPwd(ctx context.Context, userLogin string, prompt []byte,
-	login []byte, pwd []byte, note []byte, timeStamp time.Time) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// SetUserDisabled блокирует или разблокирует учетную запись пользователя.
+func (db *DBStorage) SetUserDisabled(ctx context.Context, userLogin string, disabled bool) error {
+	return db.execUser(ctx,
+		"UPDATE users SET disabled = $2 WHERE login = $1 AND NOT deleted", userLogin, disabled)
+}
+
+// LogoutUser завершает все сессии пользователя, выданные ему токены становятся недействительными.
+func (db *DBStorage) LogoutUser(ctx context.Context, userLogin string) error {
+	return db.execUser(ctx,
+		"UPDATE users SET session = session + 1 WHERE login = $1 AND NOT deleted", userLogin)
+}
+
+// DeleteUser удаляет все записи пользователя и их предыдущие версии.
+// Учетная запись остается для журнала аудита под именем deleted-<user_id>,
+// поэтому логин может быть зарегистрирован заново.
+func (db *DBStorage) DeleteUser(ctx context.Context, userLogin string) (err error) {
+	ctx, cancel := db.bulkCtx(ctx)
 	defer cancel()
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`UPDATE logins 
-		SET pwd = $1, note = $2, time_stamp = $3
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $4)
-		AND prompt = $5
-		AND login = $6`,
-		pwd, note, timeStamp, userLogin, prompt, login)
+	tx, err := db.dbHandle.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	row, err := result.RowsAffected()
+	defer tx.Rollback()
+
+	var userID int64
+	err = tx.QueryRowContext(ctx,
+		"SELECT user_id FROM users WHERE login = $1 AND NOT deleted"+db.dialect.forUpdate, userLogin).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NewStorError(EmptyResult, err)
+	}
 	if err != nil {
 		return err
 	}
-	if row != 1 {
-		return errors.New("expected to affect 1 row")
+
+	for _, name := range historyTables {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s_history WHERE user_id = $1", name), userID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", name), userID)
+		if err != nil {
+			return err
+		}
 	}
-	return nil
+
+	for _, query := range []string{
+		"DELETE FROM record_tombstones WHERE user_id = $1",
+		"DELETE FROM collection_members WHERE user_id = $1",
+		"DELETE FROM org_members WHERE user_id = $1",
+		"DELETE FROM emergency_contacts WHERE grantor_id = $1 OR grantee_id = $1",
+	} {
+		_, err = tx.ExecContext(ctx, query, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	salt, err := randomizer.GenerateRandomString(LengthSalt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`UPDATE users SET login = 'deleted-' || user_id, hash = $2, salt = $2,
+			disabled = true, deleted = true, session = session + 1,
+			public_key = NULL, private_key = NULL
+		WHERE user_id = $1`, userID, salt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// EmergencyContact хранит доверенное лицо пользователя для экстренного доступа.
+// Grantor - владелец хранилища, Grantee - доверенное лицо.
+// Нулевое значение RequestedAt означает, что доступ не запрошен.
+type EmergencyContact struct {
+	Grantor     string
+	Grantee     string
+	WaitPeriod  time.Duration
+	RequestedAt time.Time
+}
+
+// AvailableAt возвращает время, после которого доверенное лицо получит ключ хранилища.
+// Для незапрошенного доступа возвращается нулевое время.
+func (c EmergencyContact) AvailableAt() time.Time {
+	if c.RequestedAt.IsZero() {
+		return time.Time{}
+	}
+	return c.RequestedAt.Add(c.WaitPeriod)
+}
+
+// Granted проверяет, что доступ запрошен и срок ожидания истек к моменту now.
+func (c EmergencyContact) Granted(now time.Time) bool {
+	return !c.RequestedAt.IsZero() && !now.Before(c.AvailableAt())
 }
 
-// ForceUpdateTextRecord обновляет текстовую информацию.
-func (db *DBStorage) ForceUpdateTextRecord(ctx context.Context, userLogin string, prompt []byte,
-	data []byte, note []byte, timeStamp time.Time) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// AddEmergencyContact назначает пользователя contact доверенным лицом со сроком ожидания wait.
+// wrappedKey - ключ экстренного доступа, зашифрованный открытым ключом доверенного лица,
+// records - копия записей пользователя, зашифрованная этим ключом.
+// Повторное назначение заменяет срок ожидания, ключ и копию записей и отменяет запрос доступа.
+func (db *DBStorage) AddEmergencyContact(ctx context.Context, userLogin string, contact string,
+	wait time.Duration, wrappedKey []byte, records []SharedRecord) error {
+	ctx, cancel := db.bulkCtx(ctx)
 	defer cancel()
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`UPDATE text_data 
-		SET data = $1, note = $2, time_stamp = $3
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $4)
-		AND prompt = $5`,
-		data, note, timeStamp, userLogin, prompt)
+	tx, err := db.dbHandle.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	row, err := result.RowsAffected()
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO emergency_contacts (grantor_id, grantee_id, wait_period, wrapped_key)
+		SELECT g.user_id, c.user_id, $3, $4 FROM users g, users c
+		WHERE g.login = $1 AND c.login = $2 AND NOT c.deleted AND c.user_id != g.user_id
+		ON CONFLICT (grantor_id, grantee_id)
+		DO UPDATE SET wait_period = EXCLUDED.wait_period, wrapped_key = EXCLUDED.wrapped_key, requested_at = NULL`,
+		userLogin, contact, int64(wait/time.Second), wrappedKey)
 	if err != nil {
 		return err
 	}
-	if row != 1 {
-		return errors.New("expected to affect 1 row")
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
 	}
-	return nil
+	if rows == 0 {
+		return NewStorError(EmptyResult, errors.New("contact not found"))
+	}
+
+	err = replaceEmergencyRecords(ctx, tx, userLogin, contact, records)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// ForceUpdateBinaryRecord обновляет бинарные данные.
-func (db *DBStorage) ForceUpdateBinaryRecord(ctx context.Context, userLogin string, prompt []byte,
-	data []byte, note []byte, timeStamp time.Time) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// UpdateEmergencyRecords заменяет ключ экстренного доступа и копию записей для доверенного лица contact.
+// Срок ожидания и запрос доступа не меняются.
+func (db *DBStorage) UpdateEmergencyRecords(ctx context.Context, userLogin string, contact string,
+	wrappedKey []byte, records []SharedRecord) error {
+	ctx, cancel := db.bulkCtx(ctx)
 	defer cancel()
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`UPDATE binary_data 
-		SET data = $1, note = $2, time_stamp = $3
-		WHERE user_id = (SELECT user_id FROM users WHERE login = $4)
-		AND prompt = $5`,
-		data, note, timeStamp, userLogin, prompt)
+	tx, err := db.dbHandle.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	row, err := result.RowsAffected()
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE emergency_contacts SET wrapped_key = $3 WHERE "+emergencyCondition,
+		userLogin, contact, wrappedKey)
 	if err != nil {
 		return err
 	}
-	if row != 1 {
-		return errors.New("expected to affect 1 row")
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return NewStorError(EmptyResult, errors.New("contact not found"))
+	}
+
+	err = replaceEmergencyRecords(ctx, tx, userLogin, contact, records)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// replaceEmergencyRecords заменяет копию записей пользователя для доверенного лица contact.
+func replaceEmergencyRecords(ctx context.Context, tx *sql.Tx, userLogin string, contact string,
+	records []SharedRecord) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM emergency_records WHERE "+emergencyCondition, userLogin, contact)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO emergency_records (grantor_id, grantee_id, record_type, data, time_stamp)
+			VALUES ((SELECT user_id FROM users WHERE login = $1), (SELECT user_id FROM users WHERE login = $2), $3, $4, $5)`,
+			userLogin, contact, r.Type, r.Data, r.TimeStamp)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
+
+// emergencyCondition - условие отбора доверенного лица по логинам владельца ($1) и доверенного лица ($2).
+const emergencyCondition = `grantor_id = (SELECT user_id FROM users WHERE login = $1)
+	AND grantee_id = (SELECT user_id FROM users WHERE login = $2)`
+
+// RemoveEmergencyContact отменяет назначение пользователя contact доверенным лицом.
+func (db *DBStorage) RemoveEmergencyContact(ctx context.Context, userLogin string, contact string) error {
+	return db.execUser(ctx, "DELETE FROM emergency_contacts WHERE "+emergencyCondition, userLogin, contact)
+}
+
+// RequestEmergencyAccess запрашивает доступ к хранилищу пользователя grantor в момент at.
+// Повторный запрос не изменяет время первого запроса.
+func (db *DBStorage) RequestEmergencyAccess(ctx context.Context, userLogin string, grantor string,
+	at time.Time) (c EmergencyContact, err error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	var wait int64
+	err = db.dbHandle.QueryRowContext(ctx,
+		`UPDATE emergency_contacts SET requested_at = COALESCE(requested_at, $3)
+		WHERE `+emergencyCondition+`
+		RETURNING wait_period, requested_at`,
+		grantor, userLogin, at).Scan(&wait, &c.RequestedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return EmergencyContact{}, NewStorError(EmptyResult, err)
+	}
+	if err != nil {
+		return EmergencyContact{}, err
+	}
+
+	c.Grantor, c.Grantee, c.WaitPeriod = grantor, userLogin, time.Duration(wait)*time.Second
+	return c, nil
+}
+
+// DeclineEmergencyAccess отклоняет запрос доступа доверенного лица contact.
+func (db *DBStorage) DeclineEmergencyAccess(ctx context.Context, userLogin string, contact string) error {
+	return db.execUser(ctx,
+		"UPDATE emergency_contacts SET requested_at = NULL WHERE "+emergencyCondition, userLogin, contact)
+}
+
+// ListEmergencyContacts получает доверенных лиц пользователя и пользователей,
+// назначивших его доверенным лицом.
+func (db *DBStorage) ListEmergencyContacts(ctx context.Context, userLogin string) (contacts []EmergencyContact, err error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	rows, err := db.dbHandle.QueryContext(ctx,
+		`SELECT g.login, c.login, e.wait_period, e.requested_at
+		FROM emergency_contacts e
+			JOIN users g ON g.user_id = e.grantor_id
+			JOIN users c ON c.user_id = e.grantee_id
+		WHERE g.login = $1 OR c.login = $1
+		ORDER BY g.login, c.login`, userLogin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c EmergencyContact
+		var wait int64
+		var requestedAt sql.NullTime
+		err = rows.Scan(&c.Grantor, &c.Grantee, &wait, &requestedAt)
+		if err != nil {
+			return nil, err
+		}
+		c.WaitPeriod = time.Duration(wait) * time.Second
+		c.RequestedAt = requestedAt.Time
+		contacts = append(contacts, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// GetEmergencyKey получает ключ экстренного доступа к хранилищу пользователя grantor,
+// зашифрованный открытым ключом доверенного лица, и копию записей grantor, зашифрованную
+// этим ключом. Если доступ не запрошен или срок ожидания к моменту now не истек,
+// возвращается ошибка Forbidden.
+func (db *DBStorage) GetEmergencyKey(ctx context.Context, userLogin string, grantor string,
+	now time.Time) (wrappedKey []byte, records []SharedRecord, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
+	defer cancel()
+
+	c := EmergencyContact{Grantor: grantor, Grantee: userLogin}
+	var wait int64
+	var requestedAt sql.NullTime
+	err = db.dbHandle.QueryRowContext(ctx,
+		"SELECT wait_period, requested_at, wrapped_key FROM emergency_contacts WHERE "+emergencyCondition,
+		grantor, userLogin).Scan(&wait, &requestedAt, &wrappedKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, NewStorError(EmptyResult, err)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.WaitPeriod = time.Duration(wait) * time.Second
+	c.RequestedAt = requestedAt.Time
+	if !c.Granted(now) {
+		if c.RequestedAt.IsZero() {
+			return nil, nil, NewStorError(Forbidden, errors.New("emergency access is not requested"))
+		}
+		return nil, nil, NewStorError(Forbidden, fmt.Errorf("emergency access is available after %s",
+			c.AvailableAt().Format(time.RFC3339)))
+	}
+
+	rows, err := db.dbHandle.QueryContext(ctx,
+		"SELECT record_type, data, time_stamp FROM emergency_records WHERE "+emergencyCondition+
+			" ORDER BY record_type, time_stamp", grantor, userLogin)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r SharedRecord
+		err = rows.Scan(&r.Type, &r.Data, &r.TimeStamp)
+		if err != nil {
+			return nil, nil, err
+		}
+		records = append(records, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return wrappedKey, records, nil
+}
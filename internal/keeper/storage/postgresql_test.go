@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"fmt"
@@ -12,6 +13,8 @@ import (
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Julia-ivv/info-keeper.git/pkg/pubsub"
 )
 
 var (
@@ -45,95 +48,59 @@ var (
 		Note:      []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
 		TimeStamp: time.Time{},
 	}
+	testOtp = Otp{
+		Issuer:    []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		Account:   []byte{20, 251, 144, 177, 213, 73, 247, 129, 45, 118, 66, 54, 135, 0, 6, 123, 43, 112, 216, 226, 196},
+		Secret:    []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		Algorithm: []byte{73, 166, 196, 108},
+		Digits:    []byte{75},
+		Period:    []byte{75, 85},
+		Note:      []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
+		TimeStamp: time.Time{},
+	}
+	testSshKey = SshKey{
+		Prompt:     []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		PrivateKey: []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		PublicKey:  []byte{20, 251, 144, 177, 213, 73, 247, 129, 45, 118, 66, 54, 135, 0, 6, 123, 43, 112, 216, 226, 196},
+		Comment:    []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
+		Passphrase: []byte{75, 85},
+		TimeStamp:  time.Time{},
+	}
+	testTemplate = Template{
+		Name:      []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		Fields:    []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		TimeStamp: time.Time{},
+	}
+	testCustomRecord = CustomRecord{
+		Prompt:    []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		Template:  []byte{20, 251, 144, 177, 213, 73, 247, 129, 45, 118, 66, 54, 135, 0, 6, 123, 43, 112, 216, 226, 196},
+		Data:      []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		Note:      []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
+		TimeStamp: time.Time{},
+	}
 	testUserLogin = "ulogin"
 	testUserPwd   = "pwd"
+	// testWriteRevision - ревизия записи, которую возвращает отметка записи в expectWriteCommit.
+	testWriteRevision = Revision{ID: 7, Revision: 3, TimeStamp: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)}
 )
 
-func TestCreateTables(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("An error occurred while creating mock: %s", err)
-	}
-	defer db.Close()
-
-	testDB := DBStorage{dbHandle: db}
-
-	type mockBehavior func()
-
-	tests := []struct {
-		name         string
-		mockBehavior mockBehavior
-		wantErr      bool
-	}{
-		{
-			name: "ok test",
-			mockBehavior: func() {
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS logins").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS cards").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS text_data").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS binary_data").WillReturnResult(sqlmock.NewResult(1, 1))
-			},
-			wantErr: false,
-		},
-		{
-			name: "create user error",
-			mockBehavior: func() {
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnError(errTest)
-			},
-			wantErr: true,
-		},
-		{
-			name: "create login pwd error",
-			mockBehavior: func() {
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS logins").WillReturnError(errTest)
-			},
-			wantErr: true,
-		},
-		{
-			name: "create cards error",
-			mockBehavior: func() {
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS logins").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS cards").WillReturnError(errTest)
-			},
-			wantErr: true,
-		},
-		{
-			name: "create text error",
-			mockBehavior: func() {
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS logins").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS cards").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS text_data").WillReturnError(errTest)
-			},
-			wantErr: true,
-		},
-		{
-			name: "create binary record error",
-			mockBehavior: func() {
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS logins").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS cards").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS text_data").WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec("CREATE TABLE IF NOT EXISTS binary_data").WillReturnError(errTest)
-			},
-			wantErr: true,
-		},
-	}
+// expectWriteBegin ожидает начало транзакции изменения записи
+// и увеличение номера ревизии пользователя.
+func expectWriteBegin(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE users SET sync_revision = sync_revision \\+ 1").
+		WithArgs(testUserLogin).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockBehavior()
-			err := createTables(context.Background(), testDB.dbHandle)
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-			}
-		})
-	}
+// expectWriteCommit ожидает отметку записи таблицы table номером ревизии, возвращающую
+// testWriteRevision, и фиксацию транзакции.
+func expectWriteCommit(mock sqlmock.Sqlmock, table string) {
+	mock.ExpectQuery("UPDATE " + table + " SET sync_revision = \\(SELECT sync_revision FROM users .+ " +
+		"RETURNING record_id, revision, time_stamp").
+		WillReturnRows(sqlmock.NewRows([]string{"record_id", "revision", "time_stamp"}).
+			AddRow(testWriteRevision.ID, testWriteRevision.Revision, testWriteRevision.TimeStamp))
+	mock.ExpectCommit()
 }
 
 func TestAuthUser(t *testing.T) {
@@ -306,10 +273,12 @@ func TestAddCard(t *testing.T) {
 				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, testTimePrs}...).
+						a.c.Code, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "cards")
 			},
 			wantErr: false,
 		},
@@ -318,97 +287,59 @@ func TestAddCard(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{testTimePrs},
+				rows:   []string{},
+				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, testTimePrs}...).WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT time_stamp FROM card").
-					WithArgs([]driver.Value{a.c.Number, testUserLogin}...).WillReturnRows(rows)
+						a.c.Code, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
-			name: "insert, select error",
+			name: "not null error",
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{testTimePrs},
+				rows:   []string{},
+				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, testTimePrs}...).WillReturnError(&pe)
-				mock.ExpectQuery("SELECT time_stamp FROM cards").
-					WithArgs([]driver.Value{a.c.Number, testUserLogin}...).WillReturnError(errTest)
+						a.c.Code, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
+					WillReturnError(&pgconn.PgError{Code: pgerrcode.NotNullViolation})
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
 			name: "Exists Data Newer Version error",
 			ctx:  context.Background(),
-			args: args{
-				c:      testCard,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{time.Now()},
-			},
-			mockBehavior: func(a args) {
-				mock.ExpectExec("INSERT INTO cards").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, testTimePrs}...).WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT time_stamp FROM cards").
-					WithArgs([]driver.Value{a.c.Number, testUserLogin}...).WillReturnRows(rows)
-			},
-			wantErr: true,
-		},
-		{
-			name: "upd error",
-			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
 				rows:   []string{"time_stamp"},
 				values: []driver.Value{testTimePrs.AddDate(1, 0, 0)},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, testTimePrs}...).WillReturnError(&pe)
+						a.c.Code, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
 				mock.ExpectQuery("SELECT time_stamp FROM cards").
 					WithArgs([]driver.Value{a.c.Number, testUserLogin}...).WillReturnRows(rows)
-				mock.ExpectExec("UPDATE cards").WithArgs([]driver.Value{a.c.Prompt, a.c.Date,
-					a.c.Code, a.c.Note, testTimePrs, testUserLogin, a.c.Number}...).WillReturnError(errTest)
-			},
-			wantErr: true,
-		},
-		{
-			name: "upd executed",
-			ctx:  context.Background(),
-			args: args{
-				c:      testCard,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{testTimePrs},
-			},
-			mockBehavior: func(a args) {
-				mock.ExpectExec("INSERT INTO cards").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, testTimePrs}...).WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow([]driver.Value{testTimePrs.AddDate(-1, 0, 0)}...)
-				mock.ExpectQuery("SELECT time_stamp FROM cards").
-					WithArgs([]driver.Value{a.c.Number, testUserLogin}...).WillReturnRows(rows)
-				mock.ExpectExec("UPDATE cards").WithArgs([]driver.Value{a.c.Prompt, a.c.Date,
-					a.c.Code, a.c.Note, testTimePrs, testUserLogin, a.c.Number}...).
-					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
-			name: "upd executed",
+			name: "newer version, select error",
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
@@ -416,15 +347,14 @@ func TestAddCard(t *testing.T) {
 				values: []driver.Value{testTimePrs},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, testTimePrs}...).WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow([]driver.Value{testTimePrs.AddDate(-1, 0, 0)}...)
+						a.c.Code, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 				mock.ExpectQuery("SELECT time_stamp FROM cards").
-					WithArgs([]driver.Value{a.c.Number, testUserLogin}...).WillReturnRows(rows)
-				mock.ExpectExec("UPDATE cards").WithArgs([]driver.Value{a.c.Prompt, a.c.Date,
-					a.c.Code, a.c.Note, testTimePrs, testUserLogin, a.c.Number}...).
-					WillReturnResult(sqlmock.NewResult(1, 2))
+					WithArgs([]driver.Value{a.c.Number, testUserLogin}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -437,10 +367,12 @@ func TestAddCard(t *testing.T) {
 				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, testTimePrs}...).
+						a.c.Code, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
 					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -449,13 +381,16 @@ func TestAddCard(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			err := testDB.AddCard(tt.ctx, testUserLogin, tt.args.c.Prompt, tt.args.c.Number,
-				tt.args.c.Date, tt.args.c.Code, tt.args.c.Note, testTimePrs)
+			r := tt.args.c
+			r.TimeStamp = testTimePrs
+			rev, err := testDB.AddRecord(tt.ctx, testUserLogin, r)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, testWriteRevision, rev)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -498,10 +433,12 @@ func TestAddLoginPwd(t *testing.T) {
 				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO logins").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login,
-						a.c.Pwd, a.c.Note, testTimePrs}...).
+						a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "logins")
 			},
 			wantErr: false,
 		},
@@ -510,79 +447,59 @@ func TestAddLoginPwd(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{testTimePrs},
+				rows:   []string{},
+				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO logins").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login,
-						a.c.Pwd, a.c.Note, testTimePrs}...).WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT time_stamp FROM logins").
-					WithArgs([]driver.Value{a.c.Prompt, a.c.Login, testUserLogin}...).
-					WillReturnRows(rows)
+						a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
-			name: "insert, select error",
+			name: "not null error",
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{testTimePrs},
+				rows:   []string{},
+				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO logins").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login,
-						a.c.Pwd, a.c.Note, testTimePrs}...).WillReturnError(&pe)
-				mock.ExpectQuery("SELECT time_stamp FROM logins").
-					WithArgs([]driver.Value{a.c.Prompt, a.c.Login, testUserLogin}...).
-					WillReturnError(errTest)
+						a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
+					WillReturnError(&pgconn.PgError{Code: pgerrcode.NotNullViolation})
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
 			name: "Exists Data Newer Version error",
 			ctx:  context.Background(),
-			args: args{
-				c:      testLoginPwd,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{time.Now()},
-			},
-			mockBehavior: func(a args) {
-				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login,
-						a.c.Pwd, a.c.Note, testTimePrs}...).WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT time_stamp FROM logins").
-					WithArgs([]driver.Value{a.c.Prompt, a.c.Login, testUserLogin}...).
-					WillReturnRows(rows)
-			},
-			wantErr: true,
-		},
-		{
-			name: "upd error",
-			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
 				rows:   []string{"time_stamp"},
 				values: []driver.Value{testTimePrs.AddDate(1, 0, 0)},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO logins").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login,
-						a.c.Pwd, a.c.Note, testTimePrs}...).WillReturnError(&pe)
+						a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
 				mock.ExpectQuery("SELECT time_stamp FROM logins").
 					WithArgs([]driver.Value{a.c.Prompt, a.c.Login, testUserLogin}...).WillReturnRows(rows)
-				mock.ExpectExec("UPDATE logins").WithArgs([]driver.Value{a.c.Pwd, a.c.Note,
-					testTimePrs, testUserLogin, a.c.Prompt, a.c.Login}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
-			name: "upd executed",
+			name: "newer version, select error",
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
@@ -590,15 +507,14 @@ func TestAddLoginPwd(t *testing.T) {
 				values: []driver.Value{testTimePrs},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO logins").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login,
-						a.c.Pwd, a.c.Note, testTimePrs}...).WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow([]driver.Value{testTimePrs.AddDate(-1, 0, 0)}...)
+						a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 				mock.ExpectQuery("SELECT time_stamp FROM logins").
-					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).WillReturnRows(rows)
-				mock.ExpectExec("UPDATE logins").WithArgs([]driver.Value{a.c.Pwd, a.c.Note,
-					testTimePrs, testUserLogin, a.c.Prompt, a.c.Login}...).
-					WillReturnResult(sqlmock.NewResult(1, 2))
+					WithArgs([]driver.Value{a.c.Prompt, a.c.Login, testUserLogin}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -611,10 +527,12 @@ func TestAddLoginPwd(t *testing.T) {
 				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO logins").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login,
-						a.c.Pwd, a.c.Note, testTimePrs}...).
+						a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
 					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -623,13 +541,15 @@ func TestAddLoginPwd(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			err := testDB.AddLoginPwd(tt.ctx, testUserLogin, tt.args.c.Prompt, tt.args.c.Login,
-				tt.args.c.Pwd, tt.args.c.Note, testTimePrs)
+			r := tt.args.c
+			r.TimeStamp = testTimePrs
+			_, err := testDB.AddRecord(tt.ctx, testUserLogin, r)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -672,9 +592,11 @@ func TestAddTextRecord(t *testing.T) {
 				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "text_data")
 			},
 			wantErr: false,
 		},
@@ -683,79 +605,56 @@ func TestAddTextRecord(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{testTimePrs},
+				rows:   []string{},
+				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
-					WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT time_stamp FROM text_data").
-					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).
-					WillReturnRows(rows)
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
-			name: "insert, select error",
+			name: "not null error",
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{testTimePrs},
+				rows:   []string{},
+				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
-					WillReturnError(&pe)
-				mock.ExpectQuery("SELECT time_stamp FROM text_data").
-					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).
-					WillReturnError(errTest)
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
+					WillReturnError(&pgconn.PgError{Code: pgerrcode.NotNullViolation})
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
 			name: "Exists Data Newer Version error",
 			ctx:  context.Background(),
-			args: args{
-				c:      testTextRecord,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{time.Now()},
-			},
-			mockBehavior: func(a args) {
-				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
-					WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT time_stamp FROM text_data").
-					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).
-					WillReturnRows(rows)
-			},
-			wantErr: true,
-		},
-		{
-			name: "upd error",
-			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
 				rows:   []string{"time_stamp"},
 				values: []driver.Value{testTimePrs.AddDate(1, 0, 0)},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
-					WillReturnError(&pe)
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
 				mock.ExpectQuery("SELECT time_stamp FROM text_data").
 					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).WillReturnRows(rows)
-				mock.ExpectExec("UPDATE text_data").WithArgs([]driver.Value{a.c.Data, a.c.Note,
-					testTimePrs, testUserLogin, a.c.Prompt}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
-			name: "upd executed",
+			name: "newer version, select error",
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
@@ -763,15 +662,13 @@ func TestAddTextRecord(t *testing.T) {
 				values: []driver.Value{testTimePrs},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
-					WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow([]driver.Value{testTimePrs.AddDate(-1, 0, 0)}...)
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 				mock.ExpectQuery("SELECT time_stamp FROM text_data").
-					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).WillReturnRows(rows)
-				mock.ExpectExec("UPDATE text_data").WithArgs([]driver.Value{a.c.Data, a.c.Note,
-					testTimePrs, testUserLogin, a.c.Prompt}...).
-					WillReturnResult(sqlmock.NewResult(1, 2))
+					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -784,9 +681,11 @@ func TestAddTextRecord(t *testing.T) {
 				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs}...).
 					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -795,13 +694,15 @@ func TestAddTextRecord(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			err := testDB.AddTextRecord(tt.ctx, testUserLogin, tt.args.c.Prompt, tt.args.c.Data,
-				tt.args.c.Note, testTimePrs)
+			r := tt.args.c
+			r.TimeStamp = testTimePrs
+			_, err := testDB.AddRecord(tt.ctx, testUserLogin, r)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -844,9 +745,11 @@ func TestAddBinaryRecord(t *testing.T) {
 				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs, "", "", int64(0)}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "binary_data")
 			},
 			wantErr: false,
 		},
@@ -855,59 +758,36 @@ func TestAddBinaryRecord(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{testTimePrs},
+				rows:   []string{},
+				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
-					WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT time_stamp FROM binary_data").
-					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).
-					WillReturnRows(rows)
-			},
-			wantErr: true,
-		},
-		{
-			name: "insert, select error",
-			ctx:  context.Background(),
-			args: args{
-				c:      testBinaryRecord,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{testTimePrs},
-			},
-			mockBehavior: func(a args) {
-				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
-					WillReturnError(&pe)
-				mock.ExpectQuery("SELECT time_stamp FROM binary_data").
-					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).
-					WillReturnError(errTest)
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs, "", "", int64(0)}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
-			name: "Exists Data Newer Version error",
+			name: "not null error",
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
-				rows:   []string{"time_stamp"},
-				values: []driver.Value{time.Now()},
+				rows:   []string{},
+				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
-					WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT time_stamp FROM binary_data").
-					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).
-					WillReturnRows(rows)
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs, "", "", int64(0)}...).
+					WillReturnError(&pgconn.PgError{Code: pgerrcode.NotNullViolation})
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
-			name: "upd error",
+			name: "Exists Data Newer Version error",
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
@@ -915,19 +795,19 @@ func TestAddBinaryRecord(t *testing.T) {
 				values: []driver.Value{testTimePrs.AddDate(1, 0, 0)},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
-					WillReturnError(&pe)
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs, "", "", int64(0)}...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
 				mock.ExpectQuery("SELECT time_stamp FROM binary_data").
 					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).WillReturnRows(rows)
-				mock.ExpectExec("UPDATE binary_data").WithArgs([]driver.Value{a.c.Data, a.c.Note,
-					testTimePrs, testUserLogin, a.c.Prompt}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
 		{
-			name: "upd executed",
+			name: "newer version, select error",
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
@@ -935,15 +815,13 @@ func TestAddBinaryRecord(t *testing.T) {
 				values: []driver.Value{testTimePrs},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
-					WillReturnError(&pe)
-				rows := sqlmock.NewRows(a.rows).AddRow([]driver.Value{testTimePrs.AddDate(-1, 0, 0)}...)
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs, "", "", int64(0)}...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
 				mock.ExpectQuery("SELECT time_stamp FROM binary_data").
-					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).WillReturnRows(rows)
-				mock.ExpectExec("UPDATE binary_data").WithArgs([]driver.Value{a.c.Data, a.c.Note,
-					testTimePrs, testUserLogin, a.c.Prompt}...).
-					WillReturnResult(sqlmock.NewResult(1, 2))
+					WithArgs([]driver.Value{a.c.Prompt, testUserLogin}...).WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -956,9 +834,11 @@ func TestAddBinaryRecord(t *testing.T) {
 				values: []driver.Value{},
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, testTimePrs}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, testTimePrs, "", "", int64(0)}...).
 					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -967,13 +847,15 @@ func TestAddBinaryRecord(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			err := testDB.AddBinaryRecord(tt.ctx, testUserLogin, tt.args.c.Prompt, tt.args.c.Data,
-				tt.args.c.Note, testTimePrs)
+			r := tt.args.c
+			r.TimeStamp = testTimePrs
+			_, err := testDB.AddRecord(tt.ctx, testUserLogin, r)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -1001,18 +883,19 @@ func TestGetCard(t *testing.T) {
 		mockBehavior mockBehavior
 		wantRes      Card
 		wantErr      bool
+		wantType     TypeStorErrors
 	}{
 		{
 			name: "ok test",
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
-				rows:   []string{"prompt", "date", "code", "note", "time_stamp"},
-				values: []driver.Value{testCard.Prompt, testCard.Date, testCard.Code, testCard.Note, testCard.TimeStamp},
+				rows:   []string{"prompt", "date", "code", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testCard.Prompt, testCard.Date, testCard.Code, testCard.Note, testCard.Tags, testCard.Folder, testCard.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT prompt, date, code, note, time_stamp FROM cards").
+				mock.ExpectQuery("SELECT prompt, date, code, note, tags, folder, time_stamp FROM cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Number}...).
 					WillReturnRows(rows)
 			},
@@ -1024,27 +907,37 @@ func TestGetCard(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
-				rows:   []string{"prompt", "date", "code", "note", "time_stamp"},
-				values: []driver.Value{testCard.Prompt, testCard.Date, testCard.Code, testCard.Note, testCard.TimeStamp},
+				rows:   []string{"prompt", "date", "code", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testCard.Prompt, testCard.Date, testCard.Code, testCard.Note, testCard.Tags, testCard.Folder, testCard.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT prompt, date, code, note, time_stamp FROM cards").
+				mock.ExpectQuery("SELECT prompt, date, code, note, tags, folder, time_stamp FROM cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Number}...).
 					WillReturnError(errTest)
 			},
 			wantRes: testCard,
 			wantErr: true,
 		},
+		{
+			name: "not found test",
+			ctx:  context.Background(),
+			args: args{c: testCard},
+			mockBehavior: func(a args) {
+				mock.ExpectQuery("SELECT prompt, date, code, note, tags, folder, time_stamp FROM cards").
+					WithArgs([]driver.Value{testUserLogin, a.c.Number}...).
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr:  true,
+			wantType: EmptyResult,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			c, err := testDB.GetCard(tt.ctx, testUserLogin, tt.args.c.Number)
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
+			c, err := testDB.GetRecord(tt.ctx, testUserLogin, Card{Number: tt.args.c.Number})
+			assertStorErr(t, err, tt.wantErr, tt.wantType)
+			if !tt.wantErr {
 				assert.Equal(t, tt.wantRes, c)
 			}
 		})
@@ -1080,12 +973,12 @@ func TestGetLoginPwd(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
-				rows:   []string{"pwd", "note", "time_stamp"},
-				values: []driver.Value{testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.TimeStamp},
+				rows:   []string{"pwd", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.Tags, testLoginPwd.Folder, testLoginPwd.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT pwd, note, time_stamp FROM logins").
+				mock.ExpectQuery("SELECT pwd, note, tags, folder, time_stamp FROM logins").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login}...).
 					WillReturnRows(rows)
 			},
@@ -1097,11 +990,11 @@ func TestGetLoginPwd(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
-				rows:   []string{"prompt", "note", "time_stamp"},
-				values: []driver.Value{testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.TimeStamp},
+				rows:   []string{"prompt", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.Tags, testLoginPwd.Folder, testLoginPwd.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT prompt, date, code, note, time_stamp FROM cards").
+				mock.ExpectQuery("SELECT prompt, date, code, note, tags, folder, time_stamp FROM cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login}...).
 					WillReturnError(errTest)
 			},
@@ -1113,7 +1006,7 @@ func TestGetLoginPwd(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			c, err := testDB.GetLoginPwd(tt.ctx, testUserLogin, tt.args.c.Prompt, tt.args.c.Login)
+			c, err := testDB.GetRecord(tt.ctx, testUserLogin, LoginPwd{Prompt: tt.args.c.Prompt, Login: tt.args.c.Login})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1153,12 +1046,12 @@ func TestGetTextRecord(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
-				rows:   []string{"data", "note", "time_stamp"},
-				values: []driver.Value{testTextRecord.Data, testTextRecord.Note, testTextRecord.TimeStamp},
+				rows:   []string{"data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testTextRecord.Data, testTextRecord.Note, testTextRecord.Tags, testTextRecord.Folder, testTextRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT data, note, time_stamp FROM text_data").
+				mock.ExpectQuery("SELECT data, note, tags, folder, time_stamp FROM text_data").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt}...).
 					WillReturnRows(rows)
 			},
@@ -1170,11 +1063,11 @@ func TestGetTextRecord(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
-				rows:   []string{"data", "note", "time_stamp"},
-				values: []driver.Value{testTextRecord.Data, testTextRecord.Note, testTextRecord.TimeStamp},
+				rows:   []string{"data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testTextRecord.Data, testTextRecord.Note, testTextRecord.Tags, testTextRecord.Folder, testTextRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT data, note, time_stamp FROM text_data").
+				mock.ExpectQuery("SELECT data, note, tags, folder, time_stamp FROM text_data").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt}...).
 					WillReturnError(errTest)
 			},
@@ -1186,7 +1079,7 @@ func TestGetTextRecord(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			c, err := testDB.GetTextRecord(tt.ctx, testUserLogin, tt.args.c.Prompt)
+			c, err := testDB.GetRecord(tt.ctx, testUserLogin, TextRecord{Prompt: tt.args.c.Prompt})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1226,12 +1119,12 @@ func TestGetBinaryRecord(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
-				rows:   []string{"data", "note", "time_stamp"},
-				values: []driver.Value{testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.TimeStamp},
+				rows:   []string{"data", "note", "tags", "folder", "time_stamp", "blob_ref", "blob_sha256", "blob_size"},
+				values: []driver.Value{testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.Tags, testBinaryRecord.Folder, testBinaryRecord.TimeStamp, "", "", int64(0)},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT data, note, time_stamp FROM binary_data").
+				mock.ExpectQuery("SELECT data, note, tags, folder, time_stamp, blob_ref, blob_sha256, blob_size FROM binary_data").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt}...).
 					WillReturnRows(rows)
 			},
@@ -1243,11 +1136,11 @@ func TestGetBinaryRecord(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
-				rows:   []string{"data", "note", "time_stamp"},
-				values: []driver.Value{testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.TimeStamp},
+				rows:   []string{"data", "note", "tags", "folder", "time_stamp", "blob_ref", "blob_sha256", "blob_size"},
+				values: []driver.Value{testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.Tags, testBinaryRecord.Folder, testBinaryRecord.TimeStamp, "", "", int64(0)},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT data, note, time_stamp FROM binary_data").
+				mock.ExpectQuery("SELECT data, note, tags, folder, time_stamp, blob_ref, blob_sha256, blob_size FROM binary_data").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt}...).
 					WillReturnError(errTest)
 			},
@@ -1259,7 +1152,7 @@ func TestGetBinaryRecord(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			c, err := testDB.GetBinaryRecord(tt.ctx, testUserLogin, tt.args.c.Prompt)
+			c, err := testDB.GetRecord(tt.ctx, testUserLogin, BinaryRecord{Prompt: tt.args.c.Prompt})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1270,7 +1163,7 @@ func TestGetBinaryRecord(t *testing.T) {
 	}
 }
 
-func TestGetUserCardsAfterTime(t *testing.T) {
+func TestGetUserCardsAfterRevision(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("An error occurred while creating mock: %s", err)
@@ -1279,12 +1172,6 @@ func TestGetUserCardsAfterTime(t *testing.T) {
 
 	testDB := DBStorage{dbHandle: db}
 
-	testTimePrs, err := time.Parse(time.RFC3339, testTime)
-	if err != nil {
-		fmt.Println("time parse error")
-		return
-	}
-
 	type args struct {
 		c      Card
 		rows   []string
@@ -1297,7 +1184,7 @@ func TestGetUserCardsAfterTime(t *testing.T) {
 		ctx          context.Context
 		args         args
 		mockBehavior mockBehavior
-		wantRes      []Card
+		wantRes      []any
 		wantErr      bool
 	}{
 		{
@@ -1305,16 +1192,16 @@ func TestGetUserCardsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
-				rows:   []string{"prompt", "number", "date", "code", "note", "time_stamp"},
-				values: []driver.Value{testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.TimeStamp},
+				rows:   []string{"prompt", "number", "date", "code", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.Tags, testCard.Folder, testCard.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT prompt, number, date, code, note, time_stamp FROM cards").
-					WithArgs([]driver.Value{testUserLogin, testTimePrs.AddDate(-1, 0, 0)}...).
+				mock.ExpectQuery("SELECT prompt, number, date, code, note, tags, folder, time_stamp FROM cards").
+					WithArgs([]driver.Value{testUserLogin, int64(3), int64(7)}...).
 					WillReturnRows(rows)
 			},
-			wantRes: []Card{testCard},
+			wantRes: []any{testCard},
 			wantErr: false,
 		},
 		{
@@ -1322,12 +1209,12 @@ func TestGetUserCardsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
-				rows:   []string{"prompt", "number", "date", "code", "note", "time_stamp"},
-				values: []driver.Value{testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.TimeStamp},
+				rows:   []string{"prompt", "number", "date", "code", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.Tags, testCard.Folder, testCard.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT prompt, number, date, code, note, time_stamp FROM cards").
-					WithArgs([]driver.Value{testUserLogin, testTimePrs.AddDate(-1, 0, 0)}...).
+				mock.ExpectQuery("SELECT prompt, number, date, code, note, tags, folder, time_stamp FROM cards").
+					WithArgs([]driver.Value{testUserLogin, int64(3), int64(7)}...).
 					WillReturnError(errTest)
 			},
 			wantRes: nil,
@@ -1338,7 +1225,7 @@ func TestGetUserCardsAfterTime(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			c, err := testDB.GetUserCardsAfterTime(tt.ctx, testUserLogin, testTimePrs.AddDate(-1, 0, 0))
+			c, err := testDB.GetRecordsAfterRevision(tt.ctx, testUserLogin, CardRecord, 3, 7)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1349,7 +1236,7 @@ func TestGetUserCardsAfterTime(t *testing.T) {
 	}
 }
 
-func TestGetUserLoginsPwdsAfterTime(t *testing.T) {
+func TestGetUserLoginsPwdsAfterRevision(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("An error occurred while creating mock: %s", err)
@@ -1358,12 +1245,6 @@ func TestGetUserLoginsPwdsAfterTime(t *testing.T) {
 
 	testDB := DBStorage{dbHandle: db}
 
-	testTimePrs, err := time.Parse(time.RFC3339, testTime)
-	if err != nil {
-		fmt.Println("time parse error")
-		return
-	}
-
 	type args struct {
 		c      LoginPwd
 		rows   []string
@@ -1376,7 +1257,7 @@ func TestGetUserLoginsPwdsAfterTime(t *testing.T) {
 		ctx          context.Context
 		args         args
 		mockBehavior mockBehavior
-		wantRes      []LoginPwd
+		wantRes      []any
 		wantErr      bool
 	}{
 		{
@@ -1384,16 +1265,16 @@ func TestGetUserLoginsPwdsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
-				rows:   []string{"prompt", "login", "pwd", "note", "time_stamp"},
-				values: []driver.Value{testLoginPwd.Prompt, testLoginPwd.Login, testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.TimeStamp},
+				rows:   []string{"prompt", "login", "pwd", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testLoginPwd.Prompt, testLoginPwd.Login, testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.Tags, testLoginPwd.Folder, testLoginPwd.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT prompt, login, pwd, note, time_stamp FROM logins").
-					WithArgs([]driver.Value{testUserLogin, testTimePrs.AddDate(-1, 0, 0)}...).
+				mock.ExpectQuery("SELECT prompt, login, pwd, note, tags, folder, time_stamp FROM logins").
+					WithArgs([]driver.Value{testUserLogin, int64(3), int64(7)}...).
 					WillReturnRows(rows)
 			},
-			wantRes: []LoginPwd{testLoginPwd},
+			wantRes: []any{testLoginPwd},
 			wantErr: false,
 		},
 		{
@@ -1401,12 +1282,12 @@ func TestGetUserLoginsPwdsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
-				rows:   []string{"prompt", "login", "pwd", "note", "time_stamp"},
-				values: []driver.Value{testLoginPwd.Prompt, testLoginPwd.Login, testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.TimeStamp},
+				rows:   []string{"prompt", "login", "pwd", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testLoginPwd.Prompt, testLoginPwd.Login, testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.Tags, testLoginPwd.Folder, testLoginPwd.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT prompt, login, pwd, note, time_stamp FROM logins").
-					WithArgs([]driver.Value{testUserLogin, testTimePrs.AddDate(-1, 0, 0)}...).
+				mock.ExpectQuery("SELECT prompt, login, pwd, note, tags, folder, time_stamp FROM logins").
+					WithArgs([]driver.Value{testUserLogin, int64(3), int64(7)}...).
 					WillReturnError(errTest)
 			},
 			wantRes: nil,
@@ -1417,7 +1298,7 @@ func TestGetUserLoginsPwdsAfterTime(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			c, err := testDB.GetUserLoginsPwdsAfterTime(tt.ctx, testUserLogin, testTimePrs.AddDate(-1, 0, 0))
+			c, err := testDB.GetRecordsAfterRevision(tt.ctx, testUserLogin, LoginPwdRecord, 3, 7)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1428,7 +1309,7 @@ func TestGetUserLoginsPwdsAfterTime(t *testing.T) {
 	}
 }
 
-func TestGetUserTextRecordsAfterTime(t *testing.T) {
+func TestGetUserTextRecordsAfterRevision(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("An error occurred while creating mock: %s", err)
@@ -1437,12 +1318,6 @@ func TestGetUserTextRecordsAfterTime(t *testing.T) {
 
 	testDB := DBStorage{dbHandle: db}
 
-	testTimePrs, err := time.Parse(time.RFC3339, testTime)
-	if err != nil {
-		fmt.Println("time parse error")
-		return
-	}
-
 	type args struct {
 		c      TextRecord
 		rows   []string
@@ -1455,7 +1330,7 @@ func TestGetUserTextRecordsAfterTime(t *testing.T) {
 		ctx          context.Context
 		args         args
 		mockBehavior mockBehavior
-		wantRes      []TextRecord
+		wantRes      []any
 		wantErr      bool
 	}{
 		{
@@ -1463,16 +1338,16 @@ func TestGetUserTextRecordsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
-				rows:   []string{"prompt", "data", "note", "time_stamp"},
-				values: []driver.Value{testTextRecord.Prompt, testTextRecord.Data, testTextRecord.Note, testTextRecord.TimeStamp},
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testTextRecord.Prompt, testTextRecord.Data, testTextRecord.Note, testTextRecord.Tags, testTextRecord.Folder, testTextRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT prompt, data, note, time_stamp FROM text_data").
-					WithArgs([]driver.Value{testUserLogin, testTimePrs.AddDate(-1, 0, 0)}...).
+				mock.ExpectQuery("SELECT prompt, data, note, tags, folder, time_stamp FROM text_data").
+					WithArgs([]driver.Value{testUserLogin, int64(3), int64(7)}...).
 					WillReturnRows(rows)
 			},
-			wantRes: []TextRecord{testTextRecord},
+			wantRes: []any{testTextRecord},
 			wantErr: false,
 		},
 		{
@@ -1480,12 +1355,12 @@ func TestGetUserTextRecordsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
-				rows:   []string{"prompt", "data", "note", "time_stamp"},
-				values: []driver.Value{testTextRecord.Prompt, testTextRecord.Data, testTextRecord.Note, testTextRecord.TimeStamp},
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testTextRecord.Prompt, testTextRecord.Data, testTextRecord.Note, testTextRecord.Tags, testTextRecord.Folder, testTextRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT prompt, data, note, time_stamp FROM text_data").
-					WithArgs([]driver.Value{testUserLogin, testTimePrs.AddDate(-1, 0, 0)}...).
+				mock.ExpectQuery("SELECT prompt, data, note, tags, folder, time_stamp FROM text_data").
+					WithArgs([]driver.Value{testUserLogin, int64(3), int64(7)}...).
 					WillReturnError(errTest)
 			},
 			wantRes: nil,
@@ -1496,7 +1371,7 @@ func TestGetUserTextRecordsAfterTime(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			c, err := testDB.GetUserTextRecordsAfterTime(tt.ctx, testUserLogin, testTimePrs.AddDate(-1, 0, 0))
+			c, err := testDB.GetRecordsAfterRevision(tt.ctx, testUserLogin, TextDataRecord, 3, 7)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1507,7 +1382,7 @@ func TestGetUserTextRecordsAfterTime(t *testing.T) {
 	}
 }
 
-func TestGetUserBinaryRecordAfterTime(t *testing.T) {
+func TestGetUserBinaryRecordAfterRevision(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("An error occurred while creating mock: %s", err)
@@ -1516,12 +1391,6 @@ func TestGetUserBinaryRecordAfterTime(t *testing.T) {
 
 	testDB := DBStorage{dbHandle: db}
 
-	testTimePrs, err := time.Parse(time.RFC3339, testTime)
-	if err != nil {
-		fmt.Println("time parse error")
-		return
-	}
-
 	type args struct {
 		c      BinaryRecord
 		rows   []string
@@ -1534,7 +1403,7 @@ func TestGetUserBinaryRecordAfterTime(t *testing.T) {
 		ctx          context.Context
 		args         args
 		mockBehavior mockBehavior
-		wantRes      []BinaryRecord
+		wantRes      []any
 		wantErr      bool
 	}{
 		{
@@ -1542,16 +1411,16 @@ func TestGetUserBinaryRecordAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
-				rows:   []string{"prompt", "data", "note", "time_stamp"},
-				values: []driver.Value{testBinaryRecord.Prompt, testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.TimeStamp},
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp", "blob_ref", "blob_sha256", "blob_size"},
+				values: []driver.Value{testBinaryRecord.Prompt, testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.Tags, testBinaryRecord.Folder, testBinaryRecord.TimeStamp, "", "", int64(0)},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT prompt, data, note, time_stamp FROM binary_data").
-					WithArgs([]driver.Value{testUserLogin, testTimePrs.AddDate(-1, 0, 0)}...).
+				mock.ExpectQuery("SELECT prompt, data, note, tags, folder, time_stamp, blob_ref, blob_sha256, blob_size FROM binary_data").
+					WithArgs([]driver.Value{testUserLogin, int64(3), int64(7)}...).
 					WillReturnRows(rows)
 			},
-			wantRes: []BinaryRecord{testBinaryRecord},
+			wantRes: []any{testBinaryRecord},
 			wantErr: false,
 		},
 		{
@@ -1559,12 +1428,12 @@ func TestGetUserBinaryRecordAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
-				rows:   []string{"prompt", "data", "note", "time_stamp"},
-				values: []driver.Value{testBinaryRecord.Prompt, testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.TimeStamp},
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp", "blob_ref", "blob_sha256", "blob_size"},
+				values: []driver.Value{testBinaryRecord.Prompt, testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.Tags, testBinaryRecord.Folder, testBinaryRecord.TimeStamp, "", "", int64(0)},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT data, note, time_stamp FROM binary_data").
-					WithArgs([]driver.Value{testUserLogin, testTimePrs.AddDate(-1, 0, 0)}...).
+				mock.ExpectQuery("SELECT prompt, data, note, tags, folder, time_stamp, blob_ref, blob_sha256, blob_size FROM binary_data").
+					WithArgs([]driver.Value{testUserLogin, int64(3), int64(7)}...).
 					WillReturnError(errTest)
 			},
 			wantRes: nil,
@@ -1575,7 +1444,7 @@ func TestGetUserBinaryRecordAfterTime(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			c, err := testDB.GetUserBinaryRecordsAfterTime(tt.ctx, testUserLogin, testTimePrs.AddDate(-1, 0, 0))
+			c, err := testDB.GetRecordsAfterRevision(tt.ctx, testUserLogin, BinaryDataRecord, 3, 7)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1614,10 +1483,12 @@ func TestForceUpdateCard(t *testing.T) {
 				c: testCard,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE cards").
-					WithArgs([]driver.Value{a.c.Prompt, a.c.Date, a.c.Code, a.c.Note,
+					WithArgs([]driver.Value{a.c.Prompt, a.c.Date, a.c.Code, a.c.Note, a.c.Tags, a.c.Folder,
 						a.c.TimeStamp, testUserLogin, a.c.Number}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "cards")
 			},
 			wantErr: false,
 		},
@@ -1628,10 +1499,12 @@ func TestForceUpdateCard(t *testing.T) {
 				c: testCard,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE cards").
-					WithArgs([]driver.Value{a.c.Prompt, a.c.Date, a.c.Code, a.c.Note,
+					WithArgs([]driver.Value{a.c.Prompt, a.c.Date, a.c.Code, a.c.Note, a.c.Tags, a.c.Folder,
 						a.c.TimeStamp, testUserLogin, a.c.Number}...).
 					WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -1642,10 +1515,12 @@ func TestForceUpdateCard(t *testing.T) {
 				c: testCard,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE cards").
-					WithArgs([]driver.Value{a.c.Prompt, a.c.Date, a.c.Code, a.c.Note,
+					WithArgs([]driver.Value{a.c.Prompt, a.c.Date, a.c.Code, a.c.Note, a.c.Tags, a.c.Folder,
 						a.c.TimeStamp, testUserLogin, a.c.Number}...).
 					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -1654,8 +1529,7 @@ func TestForceUpdateCard(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			err := testDB.ForceUpdateCard(tt.ctx, testUserLogin, tt.args.c.Prompt, tt.args.c.Number, tt.args.c.Date,
-				tt.args.c.Code, tt.args.c.Note, tt.args.c.TimeStamp)
+			_, err := testDB.ForceUpdateRecord(tt.ctx, testUserLogin, tt.args.c)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1693,10 +1567,12 @@ func TestForceUpdateLogin(t *testing.T) {
 				c: testLoginPwd,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE logins").
-					WithArgs([]driver.Value{a.c.Pwd, a.c.Note, a.c.TimeStamp, testUserLogin,
+					WithArgs([]driver.Value{a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp, testUserLogin,
 						a.c.Prompt, a.c.Login}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "logins")
 			},
 			wantErr: false,
 		},
@@ -1707,10 +1583,12 @@ func TestForceUpdateLogin(t *testing.T) {
 				c: testLoginPwd,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE logins").
-					WithArgs([]driver.Value{a.c.Pwd, a.c.Note, a.c.TimeStamp, testUserLogin,
+					WithArgs([]driver.Value{a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp, testUserLogin,
 						a.c.Prompt, a.c.Login}...).
 					WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -1721,10 +1599,12 @@ func TestForceUpdateLogin(t *testing.T) {
 				c: testLoginPwd,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE logins").
-					WithArgs([]driver.Value{a.c.Pwd, a.c.Note, a.c.TimeStamp, testUserLogin,
+					WithArgs([]driver.Value{a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp, testUserLogin,
 						a.c.Prompt, a.c.Login}...).
 					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -1733,8 +1613,7 @@ func TestForceUpdateLogin(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			err := testDB.ForceUpdateLoginPwd(tt.ctx, testUserLogin, tt.args.c.Prompt, tt.args.c.Login,
-				tt.args.c.Pwd, tt.args.c.Note, tt.args.c.TimeStamp)
+			_, err := testDB.ForceUpdateRecord(tt.ctx, testUserLogin, tt.args.c)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1772,9 +1651,11 @@ func TestForceUpdateTextRecord(t *testing.T) {
 				c: testTextRecord,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE text_data").
-					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.TimeStamp, testUserLogin, a.c.Prompt}...).
+					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp, testUserLogin, a.c.Prompt}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "text_data")
 			},
 			wantErr: false,
 		},
@@ -1785,9 +1666,11 @@ func TestForceUpdateTextRecord(t *testing.T) {
 				c: testTextRecord,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE text_data").
-					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.TimeStamp, testUserLogin, a.c.Prompt}...).
+					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp, testUserLogin, a.c.Prompt}...).
 					WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -1798,9 +1681,11 @@ func TestForceUpdateTextRecord(t *testing.T) {
 				c: testTextRecord,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE text_data").
-					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.TimeStamp, testUserLogin, a.c.Prompt}...).
+					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp, testUserLogin, a.c.Prompt}...).
 					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -1809,8 +1694,7 @@ func TestForceUpdateTextRecord(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			err := testDB.ForceUpdateTextRecord(tt.ctx, testUserLogin, tt.args.c.Prompt, tt.args.c.Data,
-				tt.args.c.Note, tt.args.c.TimeStamp)
+			_, err := testDB.ForceUpdateRecord(tt.ctx, testUserLogin, tt.args.c)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1848,9 +1732,11 @@ func TestForceUpdateBinaryRecord(t *testing.T) {
 				c: testBinaryRecord,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE binary_data").
-					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.TimeStamp, testUserLogin, a.c.Prompt}...).
+					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp, "", "", int64(0), testUserLogin, a.c.Prompt}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "binary_data")
 			},
 			wantErr: false,
 		},
@@ -1861,9 +1747,11 @@ func TestForceUpdateBinaryRecord(t *testing.T) {
 				c: testBinaryRecord,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE binary_data").
-					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.TimeStamp, testUserLogin, a.c.Prompt}...).
+					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp, "", "", int64(0), testUserLogin, a.c.Prompt}...).
 					WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -1874,9 +1762,11 @@ func TestForceUpdateBinaryRecord(t *testing.T) {
 				c: testBinaryRecord,
 			},
 			mockBehavior: func(a args) {
+				expectWriteBegin(mock)
 				mock.ExpectExec("UPDATE binary_data").
-					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.TimeStamp, testUserLogin, a.c.Prompt}...).
+					WithArgs([]driver.Value{a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp, "", "", int64(0), testUserLogin, a.c.Prompt}...).
 					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -1885,8 +1775,7 @@ func TestForceUpdateBinaryRecord(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			err := testDB.ForceUpdateBinaryRecord(tt.ctx, testUserLogin, tt.args.c.Prompt, tt.args.c.Data,
-				tt.args.c.Note, tt.args.c.TimeStamp)
+			_, err := testDB.ForceUpdateRecord(tt.ctx, testUserLogin, tt.args.c)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1895,3 +1784,1438 @@ func TestForceUpdateBinaryRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestListRecords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	testTimePrs, err := time.Parse(time.RFC3339, testTime)
+	if err != nil {
+		fmt.Println("time parse error")
+		return
+	}
+	testFilter := ListFilter{
+		Type:          BinaryDataRecord,
+		ModifiedSince: testTimePrs,
+		Offset:        10,
+		Limit:         5,
+	}
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		mockBehavior func()
+		wantRes      []RecordInfo
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			ctx:  context.Background(),
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"record_type", "prompt", "time_stamp", "size"}).
+					AddRow(int64(BinaryDataRecord), testBinaryRecord.Prompt, testBinaryRecord.TimeStamp, int64(len(testBinaryRecord.Data)))
+				mock.ExpectQuery("SELECT record_type, prompt, time_stamp, size FROM").
+					WithArgs(testUserLogin, testFilter.ModifiedSince, testFilter.Type, testFilter.Limit, testFilter.Offset).
+					WillReturnRows(rows)
+			},
+			wantRes: []RecordInfo{{
+				Type:      BinaryDataRecord,
+				Prompt:    testBinaryRecord.Prompt,
+				TimeStamp: testBinaryRecord.TimeStamp,
+				Size:      int64(len(testBinaryRecord.Data)),
+			}},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			ctx:  context.Background(),
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT record_type, prompt, time_stamp, size FROM").
+					WithArgs(testUserLogin, testFilter.ModifiedSince, testFilter.Type, testFilter.Limit, testFilter.Offset).
+					WillReturnError(errTest)
+			},
+			wantRes: nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+			res, err := testDB.ListRecords(tt.ctx, testUserLogin, testFilter)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
+
+func TestRecordKinds(t *testing.T) {
+	for typ := CardRecord; typ <= CustomDataRecord; typ++ {
+		k, err := kindByType(typ)
+		if assert.NoError(t, err, "record type %d", typ) {
+			assert.Equal(t, typ, k.recordType())
+		}
+	}
+	_, err := kindByType(AnyRecord)
+	assert.Error(t, err)
+	_, err = kindOf(struct{}{})
+	assert.Error(t, err)
+
+	c, ok := changeOf(testCard)
+	assert.True(t, ok)
+	assert.Equal(t, Change{Type: CardRecord, Key: testCard.Number, TimeStamp: testCard.TimeStamp}, c)
+	c, ok = changeOf(testOtp)
+	assert.True(t, ok)
+	assert.Equal(t, Change{Type: OtpRecord, Prompt: testOtp.Issuer, Key: testOtp.Account, TimeStamp: testOtp.TimeStamp}, c)
+	_, ok = changeOf(struct{}{})
+	assert.False(t, ok)
+}
+
+func TestGetUsage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantRes      []Usage
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"record_type", "count", "coalesce"}).
+					AddRow(int64(CardRecord), int64(2), int64(120)).
+					AddRow(int64(BinaryDataRecord), int64(1), int64(2048))
+				mock.ExpectQuery("SELECT record_type, count\\(\\*\\), coalesce\\(sum\\(size\\), 0\\) FROM").
+					WithArgs(testUserLogin).
+					WillReturnRows(rows)
+			},
+			wantRes: []Usage{
+				{Type: CardRecord, Records: 2, Bytes: 120},
+				{Type: BinaryDataRecord, Records: 1, Bytes: 2048},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT record_type, count").
+					WithArgs(testUserLogin).
+					WillReturnError(errTest)
+			},
+			wantRes: nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+			res, err := testDB.GetUsage(context.Background(), testUserLogin)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
+
+func TestAddOtp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	type mockBehavior func(o Otp)
+
+	tests := []struct {
+		name         string
+		o            Otp
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			o:    testOtp,
+			mockBehavior: func(o Otp) {
+				expectWriteBegin(mock)
+				mock.ExpectExec("INSERT INTO otps").
+					WithArgs([]driver.Value{testUserLogin, o.Issuer, o.Account, o.Secret, o.Algorithm,
+						o.Digits, o.Period, o.Note, o.Tags, o.Folder, o.TimeStamp}...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "otps")
+			},
+			wantErr: false,
+		},
+		{
+			name: "exists newer test",
+			o:    testOtp,
+			mockBehavior: func(o Otp) {
+				expectWriteBegin(mock)
+				mock.ExpectExec("INSERT INTO otps").
+					WithArgs([]driver.Value{testUserLogin, o.Issuer, o.Account, o.Secret, o.Algorithm,
+						o.Digits, o.Period, o.Note, o.Tags, o.Folder, o.TimeStamp}...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT time_stamp FROM otps").
+					WithArgs([]driver.Value{o.Issuer, o.Account, testUserLogin}...).
+					WillReturnRows(sqlmock.NewRows([]string{"time_stamp"}).AddRow(o.TimeStamp.Add(time.Hour)))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.o)
+			_, err := testDB.AddRecord(context.Background(), testUserLogin, tt.o)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetOtp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	rows := sqlmock.NewRows([]string{"secret", "algorithm", "digits", "period", "note", "tags", "folder", "time_stamp"}).
+		AddRow(testOtp.Secret, testOtp.Algorithm, testOtp.Digits, testOtp.Period, testOtp.Note, testOtp.Tags, testOtp.Folder, testOtp.TimeStamp)
+	mock.ExpectQuery("SELECT secret, algorithm, digits, period, note, tags, folder, time_stamp FROM otps").
+		WithArgs([]driver.Value{testUserLogin, testOtp.Issuer, testOtp.Account}...).
+		WillReturnRows(rows)
+
+	o, err := testDB.GetRecord(context.Background(), testUserLogin, Otp{Issuer: testOtp.Issuer, Account: testOtp.Account})
+	assert.NoError(t, err)
+	assert.Equal(t, testOtp, o)
+
+	mock.ExpectQuery("SELECT secret, algorithm, digits, period, note, tags, folder, time_stamp FROM otps").
+		WithArgs([]driver.Value{testUserLogin, testOtp.Issuer, testOtp.Account}...).
+		WillReturnError(errTest)
+
+	_, err = testDB.GetRecord(context.Background(), testUserLogin, Otp{Issuer: testOtp.Issuer, Account: testOtp.Account})
+	assert.Error(t, err)
+}
+
+func TestForceUpdateOtp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	expectWriteBegin(mock)
+	mock.ExpectExec("UPDATE otps").
+		WithArgs([]driver.Value{testOtp.Secret, testOtp.Algorithm, testOtp.Digits, testOtp.Period,
+			testOtp.Note, testOtp.Tags, testOtp.Folder, testOtp.TimeStamp, testUserLogin, testOtp.Issuer, testOtp.Account}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWriteCommit(mock, "otps")
+
+	_, err = testDB.ForceUpdateRecord(context.Background(), testUserLogin, testOtp)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddSshKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	expectWriteBegin(mock)
+	mock.ExpectExec("INSERT INTO ssh_keys").
+		WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt, testSshKey.PrivateKey, testSshKey.PublicKey,
+			testSshKey.Comment, testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWriteCommit(mock, "ssh_keys")
+
+	_, err = testDB.AddRecord(context.Background(), testUserLogin, testSshKey)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSshKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	rows := sqlmock.NewRows([]string{"private_key", "public_key", "comment", "passphrase", "tags", "folder", "time_stamp"}).
+		AddRow(testSshKey.PrivateKey, testSshKey.PublicKey, testSshKey.Comment, testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp)
+	mock.ExpectQuery("SELECT private_key, public_key, comment, passphrase, tags, folder, time_stamp FROM ssh_keys").
+		WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt}...).
+		WillReturnRows(rows)
+
+	k, err := testDB.GetRecord(context.Background(), testUserLogin, SshKey{Prompt: testSshKey.Prompt})
+	assert.NoError(t, err)
+	assert.Equal(t, testSshKey, k)
+
+	mock.ExpectQuery("SELECT private_key, public_key, comment, passphrase, tags, folder, time_stamp FROM ssh_keys").
+		WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt}...).
+		WillReturnError(errTest)
+
+	_, err = testDB.GetRecord(context.Background(), testUserLogin, SshKey{Prompt: testSshKey.Prompt})
+	assert.Error(t, err)
+}
+
+func TestForceUpdateSshKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	expectWriteBegin(mock)
+	mock.ExpectExec("UPDATE ssh_keys").
+		WithArgs([]driver.Value{testSshKey.PrivateKey, testSshKey.PublicKey, testSshKey.Comment,
+			testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp, testUserLogin, testSshKey.Prompt}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWriteCommit(mock, "ssh_keys")
+
+	_, err = testDB.ForceUpdateRecord(context.Background(), testUserLogin, testSshKey)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddTemplate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	expectWriteBegin(mock)
+	mock.ExpectExec("INSERT INTO templates").
+		WithArgs([]driver.Value{testUserLogin, testTemplate.Name, testTemplate.Fields, testTemplate.Tags, testTemplate.Folder, testTemplate.TimeStamp}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWriteCommit(mock, "templates")
+
+	_, err = testDB.AddRecord(context.Background(), testUserLogin, testTemplate)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTemplate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	rows := sqlmock.NewRows([]string{"fields", "tags", "folder", "time_stamp"}).
+		AddRow(testTemplate.Fields, testTemplate.Tags, testTemplate.Folder, testTemplate.TimeStamp)
+	mock.ExpectQuery("SELECT fields, tags, folder, time_stamp FROM templates").
+		WithArgs([]driver.Value{testUserLogin, testTemplate.Name}...).
+		WillReturnRows(rows)
+
+	tmpl, err := testDB.GetRecord(context.Background(), testUserLogin, Template{Name: testTemplate.Name})
+	assert.NoError(t, err)
+	assert.Equal(t, testTemplate, tmpl)
+}
+
+func TestForceUpdateTemplate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	expectWriteBegin(mock)
+	mock.ExpectExec("UPDATE templates").
+		WithArgs([]driver.Value{testTemplate.Fields, testTemplate.Tags, testTemplate.Folder, testTemplate.TimeStamp, testUserLogin, testTemplate.Name}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWriteCommit(mock, "templates")
+
+	_, err = testDB.ForceUpdateRecord(context.Background(), testUserLogin, testTemplate)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddCustomRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	expectWriteBegin(mock)
+	mock.ExpectExec("INSERT INTO custom_records").
+		WithArgs([]driver.Value{testUserLogin, testCustomRecord.Prompt, testCustomRecord.Template,
+			testCustomRecord.Data, testCustomRecord.Note, testCustomRecord.Tags, testCustomRecord.Folder, testCustomRecord.TimeStamp}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWriteCommit(mock, "custom_records")
+
+	_, err = testDB.AddRecord(context.Background(), testUserLogin, testCustomRecord)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCustomRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	rows := sqlmock.NewRows([]string{"template", "data", "note", "tags", "folder", "time_stamp"}).
+		AddRow(testCustomRecord.Template, testCustomRecord.Data, testCustomRecord.Note, testCustomRecord.Tags, testCustomRecord.Folder, testCustomRecord.TimeStamp)
+	mock.ExpectQuery("SELECT template, data, note, tags, folder, time_stamp FROM custom_records").
+		WithArgs([]driver.Value{testUserLogin, testCustomRecord.Prompt}...).
+		WillReturnRows(rows)
+
+	r, err := testDB.GetRecord(context.Background(), testUserLogin, CustomRecord{Prompt: testCustomRecord.Prompt})
+	assert.NoError(t, err)
+	assert.Equal(t, testCustomRecord, r)
+
+	mock.ExpectQuery("SELECT template, data, note, tags, folder, time_stamp FROM custom_records").
+		WithArgs([]driver.Value{testUserLogin, testCustomRecord.Prompt}...).
+		WillReturnError(errTest)
+
+	_, err = testDB.GetRecord(context.Background(), testUserLogin, CustomRecord{Prompt: testCustomRecord.Prompt})
+	assert.Error(t, err)
+}
+
+func TestForceUpdateCustomRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	expectWriteBegin(mock)
+	mock.ExpectExec("UPDATE custom_records").
+		WithArgs([]driver.Value{testCustomRecord.Template, testCustomRecord.Data, testCustomRecord.Note, testCustomRecord.Tags, testCustomRecord.Folder,
+			testCustomRecord.TimeStamp, testUserLogin, testCustomRecord.Prompt}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWriteCommit(mock, "custom_records")
+
+	_, err = testDB.ForceUpdateRecord(context.Background(), testUserLogin, testCustomRecord)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestForceUpdateOtpHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db, dialect: postgresDialect, retention: 5}
+
+	expectWriteBegin(mock)
+	mock.ExpectQuery("SELECT time_stamp FROM otps (.+) FOR UPDATE").
+		WithArgs([]driver.Value{testOtp.Issuer, testOtp.Account, testUserLogin}...).
+		WillReturnRows(sqlmock.NewRows([]string{"time_stamp"}).AddRow(testOtp.TimeStamp))
+	mock.ExpectExec("INSERT INTO otps_history").
+		WithArgs([]driver.Value{testUserLogin, testOtp.Issuer, testOtp.Account}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE otps").
+		WithArgs([]driver.Value{testOtp.Secret, testOtp.Algorithm, testOtp.Digits, testOtp.Period,
+			testOtp.Note, testOtp.Tags, testOtp.Folder, testOtp.TimeStamp, testUserLogin, testOtp.Issuer, testOtp.Account}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM otps_history").
+		WithArgs([]driver.Value{testUserLogin, testOtp.Issuer, testOtp.Account,
+			testUserLogin, testOtp.Issuer, testOtp.Account, 5}...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectWriteCommit(mock, "otps")
+
+	_, err = testDB.ForceUpdateRecord(context.Background(), testUserLogin, testOtp)
+	assert.NoError(t, err)
+
+	expectWriteBegin(mock)
+	mock.ExpectQuery("SELECT time_stamp FROM otps (.+) FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"time_stamp"}).AddRow(testOtp.TimeStamp))
+	mock.ExpectExec("INSERT INTO otps_history").
+		WithArgs([]driver.Value{testUserLogin, testOtp.Issuer, testOtp.Account}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE otps").
+		WillReturnError(errTest)
+	mock.ExpectRollback()
+
+	_, err = testDB.ForceUpdateRecord(context.Background(), testUserLogin, testOtp)
+	assert.Error(t, err)
+
+	expectWriteBegin(mock)
+	mock.ExpectQuery("SELECT time_stamp FROM otps (.+) FOR UPDATE").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err = testDB.ForceUpdateRecord(context.Background(), testUserLogin, testOtp)
+	assertStorErr(t, err, true, EmptyResult)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddOtpHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db, dialect: postgresDialect, retention: 5}
+	insertArgs := []driver.Value{testUserLogin, testOtp.Issuer, testOtp.Account, testOtp.Secret, testOtp.Algorithm,
+		testOtp.Digits, testOtp.Period, testOtp.Note, testOtp.Tags, testOtp.Folder, testOtp.TimeStamp}
+	keyArgs := []driver.Value{testUserLogin, testOtp.Issuer, testOtp.Account}
+	lockArgs := []driver.Value{testOtp.Issuer, testOtp.Account, testUserLogin}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		wantType     TypeStorErrors
+	}{
+		{
+			name: "new record test",
+			mockBehavior: func() {
+				expectWriteBegin(mock)
+				mock.ExpectExec("INSERT INTO otps (.+) ON CONFLICT (.+) DO NOTHING").
+					WithArgs(insertArgs...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWriteCommit(mock, "otps")
+			},
+		},
+		{
+			name: "update test",
+			mockBehavior: func() {
+				expectWriteBegin(mock)
+				mock.ExpectExec("INSERT INTO otps (.+) ON CONFLICT (.+) DO NOTHING").
+					WithArgs(insertArgs...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT time_stamp FROM otps (.+) FOR UPDATE").
+					WithArgs(lockArgs...).
+					WillReturnRows(sqlmock.NewRows([]string{"time_stamp"}).AddRow(testOtp.TimeStamp.Add(-time.Hour)))
+				mock.ExpectExec("INSERT INTO otps_history").
+					WithArgs(keyArgs...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE otps").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("DELETE FROM otps_history").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectWriteCommit(mock, "otps")
+			},
+		},
+		{
+			name: "exists newer test",
+			mockBehavior: func() {
+				expectWriteBegin(mock)
+				mock.ExpectExec("INSERT INTO otps (.+) ON CONFLICT (.+) DO NOTHING").
+					WithArgs(insertArgs...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT time_stamp FROM otps (.+) FOR UPDATE").
+					WithArgs(lockArgs...).
+					WillReturnRows(sqlmock.NewRows([]string{"time_stamp"}).AddRow(testOtp.TimeStamp.Add(time.Hour)))
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: ExistsDataNewerVersion,
+		},
+		{
+			name: "not null test",
+			mockBehavior: func() {
+				expectWriteBegin(mock)
+				mock.ExpectExec("INSERT INTO otps (.+) ON CONFLICT (.+) DO NOTHING").
+					WillReturnError(&pgconn.PgError{Code: pgerrcode.NotNullViolation})
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: NullValues,
+		},
+		{
+			name: "deleted while adding test",
+			mockBehavior: func() {
+				expectWriteBegin(mock)
+				mock.ExpectExec("INSERT INTO otps (.+) ON CONFLICT (.+) DO NOTHING").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT time_stamp FROM otps (.+) FOR UPDATE").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: EmptyResult,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+			_, err := testDB.AddRecord(context.Background(), testUserLogin, testOtp)
+			assertStorErr(t, err, tt.wantErr, tt.wantType)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	rows := sqlmock.NewRows([]string{"version_id", "prompt", "private_key", "public_key", "comment", "passphrase", "tags", "folder", "time_stamp"}).
+		AddRow(2, testSshKey.Prompt, testSshKey.PrivateKey, testSshKey.PublicKey, testSshKey.Comment, testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp)
+	mock.ExpectQuery("SELECT version_id, prompt, private_key, public_key, comment, passphrase, tags, folder, time_stamp FROM ssh_keys_history").
+		WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt}...).
+		WillReturnRows(rows)
+
+	versions, err := testDB.ListVersions(context.Background(), testUserLogin, SshKey{Prompt: testSshKey.Prompt})
+	assert.NoError(t, err)
+	assert.Equal(t, []Version{{ID: 2, Record: testSshKey}}, versions)
+
+	_, err = testDB.ListVersions(context.Background(), testUserLogin, "unknown")
+	assert.Error(t, err)
+}
+
+func TestGetRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	rows := sqlmock.NewRows([]string{"record_id", "revision", "time_stamp"}).
+		AddRow(7, 3, testCard.TimeStamp)
+	mock.ExpectQuery("SELECT record_id, revision, time_stamp FROM cards").
+		WithArgs([]driver.Value{testUserLogin, testCard.Number}...).
+		WillReturnRows(rows)
+
+	r, err := testDB.GetRevision(context.Background(), testUserLogin, Card{Number: testCard.Number})
+	assert.NoError(t, err)
+	assert.Equal(t, Revision{ID: 7, Revision: 3, TimeStamp: testCard.TimeStamp}, r)
+
+	mock.ExpectQuery("SELECT record_id, revision, time_stamp FROM text_data").
+		WithArgs([]driver.Value{testUserLogin, testTextRecord.Prompt}...).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = testDB.GetRevision(context.Background(), testUserLogin, TextRecord{Prompt: testTextRecord.Prompt})
+	var storErr *StorErr
+	assert.ErrorAs(t, err, &storErr)
+	assert.Equal(t, EmptyResult, storErr.ErrType)
+
+	_, err = testDB.GetRevision(context.Background(), testUserLogin, "unknown")
+	assert.Error(t, err)
+}
+
+func TestRestoreVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	restoreTime := testSshKey.TimeStamp.Add(time.Hour)
+	want := testSshKey
+	want.TimeStamp = restoreTime
+
+	rows := sqlmock.NewRows([]string{"prompt", "private_key", "public_key", "comment", "passphrase", "tags", "folder", "time_stamp"}).
+		AddRow(testSshKey.Prompt, testSshKey.PrivateKey, testSshKey.PublicKey, testSshKey.Comment, testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp)
+	mock.ExpectQuery("SELECT prompt, private_key, public_key, comment, passphrase, tags, folder, time_stamp FROM ssh_keys_history").
+		WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt, 2}...).
+		WillReturnRows(rows)
+	expectWriteBegin(mock)
+	mock.ExpectExec("UPDATE ssh_keys").
+		WithArgs([]driver.Value{want.PrivateKey, want.PublicKey, want.Comment,
+			want.Passphrase, want.Tags, want.Folder, restoreTime, testUserLogin, want.Prompt}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWriteCommit(mock, "ssh_keys")
+
+	r, err := testDB.RestoreVersion(context.Background(), testUserLogin, SshKey{Prompt: testSshKey.Prompt}, 2, restoreTime)
+	assert.NoError(t, err)
+	assert.Equal(t, want, r)
+
+	mock.ExpectQuery("SELECT prompt, private_key, public_key, comment, passphrase, tags, folder, time_stamp FROM ssh_keys_history").
+		WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt, 3}...).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = testDB.RestoreVersion(context.Background(), testUserLogin, SshKey{Prompt: testSshKey.Prompt}, 3, restoreTime)
+	var storErr *StorErr
+	assert.ErrorAs(t, err, &storErr)
+	assert.Equal(t, EmptyResult, storErr.ErrType)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWatchChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db, changes: pubsub.NewBroker[Change]()}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := testDB.WatchChanges(ctx, testUserLogin)
+
+	expectWriteBegin(mock)
+	mock.ExpectExec("INSERT INTO otps").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWriteCommit(mock, "otps")
+	_, err = testDB.AddRecord(context.Background(), testUserLogin, testOtp)
+	assert.NoError(t, err)
+	assert.Equal(t, Change{Type: OtpRecord, Prompt: testOtp.Issuer, Key: testOtp.Account, TimeStamp: testOtp.TimeStamp}, <-changes)
+
+	expectWriteBegin(mock)
+	mock.ExpectExec("UPDATE ssh_keys").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWriteCommit(mock, "ssh_keys")
+	_, err = testDB.ForceUpdateRecord(context.Background(), testUserLogin, testSshKey)
+	assert.NoError(t, err)
+	assert.Equal(t, Change{Type: SshKeyRecord, Prompt: testSshKey.Prompt, TimeStamp: testSshKey.TimeStamp}, <-changes)
+
+	expectWriteBegin(mock)
+	mock.ExpectExec("UPDATE ssh_keys").
+		WillReturnError(errTest)
+	mock.ExpectRollback()
+	_, err = testDB.ForceUpdateRecord(context.Background(), testUserLogin, testSshKey)
+	assert.Error(t, err)
+	assert.Empty(t, changes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddAuditEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	testTimePrs, err := time.Parse(time.RFC3339, testTime)
+	if err != nil {
+		fmt.Println("time parse error")
+		return
+	}
+	testEvent := AuditEvent{
+		Action:    AuditRead,
+		Type:      TextDataRecord,
+		Prompt:    testTextRecord.Prompt,
+		Device:    "laptop",
+		IP:        "127.0.0.1",
+		TimeStamp: testTimePrs,
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				mock.ExpectExec("INSERT INTO audit_log").
+					WithArgs(testUserLogin, testEvent.Action, testEvent.Type, testEvent.Prompt, testEvent.Key,
+						testEvent.Device, testEvent.IP, testEvent.TimeStamp).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			mockBehavior: func() {
+				mock.ExpectExec("INSERT INTO audit_log").WillReturnError(errTest)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+			err := testDB.AddAuditEvent(context.Background(), testUserLogin, testEvent)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	testTimePrs, err := time.Parse(time.RFC3339, testTime)
+	if err != nil {
+		fmt.Println("time parse error")
+		return
+	}
+	testEvent := AuditEvent{
+		Action:    AuditForceUpdate,
+		Type:      CardRecord,
+		Key:       testCard.Number,
+		Device:    "laptop",
+		IP:        "127.0.0.1",
+		TimeStamp: testTimePrs,
+	}
+	columns := []string{"action", "record_type", "prompt", "record_key", "device", "ip", "time_stamp"}
+
+	tests := []struct {
+		name         string
+		to           time.Time
+		mockBehavior func(to any)
+		wantRes      []AuditEvent
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			to:   testTimePrs.Add(time.Hour),
+			mockBehavior: func(to any) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(string(testEvent.Action), int64(testEvent.Type), nil, testEvent.Key,
+						testEvent.Device, testEvent.IP, testEvent.TimeStamp)
+				mock.ExpectQuery("SELECT action, record_type, prompt, record_key, device, ip, time_stamp FROM audit_log").
+					WithArgs(testUserLogin, testTimePrs, to).
+					WillReturnRows(rows)
+			},
+			wantRes: []AuditEvent{testEvent},
+			wantErr: false,
+		},
+		{
+			name: "without upper bound",
+			mockBehavior: func(to any) {
+				mock.ExpectQuery("SELECT action, record_type, prompt, record_key, device, ip, time_stamp FROM audit_log").
+					WithArgs(testUserLogin, testTimePrs).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantRes: nil,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			to:   testTimePrs.Add(time.Hour),
+			mockBehavior: func(to any) {
+				mock.ExpectQuery("SELECT action, record_type, prompt, record_key, device, ip, time_stamp FROM audit_log").
+					WithArgs(testUserLogin, testTimePrs, to).
+					WillReturnError(errTest)
+			},
+			wantRes: nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.to)
+			res, err := testDB.GetAuditLog(context.Background(), testUserLogin, testTimePrs, tt.to)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRes, res)
+			}
+		})
+	}
+}
+
+func TestGetSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	mock.ExpectQuery("SELECT user_id, session, disabled FROM users").
+		WithArgs(testUserLogin).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "session", "disabled"}).AddRow(int64(7), int64(2), true))
+	s, err := testDB.GetSession(context.Background(), testUserLogin)
+	assert.NoError(t, err)
+	assert.Equal(t, Session{UserID: 7, Version: 2, Disabled: true}, s)
+
+	mock.ExpectQuery("SELECT user_id, session, disabled FROM users").
+		WithArgs(testUserLogin).
+		WillReturnError(sql.ErrNoRows)
+	_, err = testDB.GetSession(context.Background(), testUserLogin)
+	var storErr *StorErr
+	assert.ErrorAs(t, err, &storErr)
+	assert.Equal(t, EmptyResult, storErr.ErrType)
+}
+
+func TestGetSyncRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	mock.ExpectQuery("SELECT sync_revision FROM users").
+		WithArgs(testUserLogin).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_revision"}).AddRow(int64(42)))
+	rev, err := testDB.GetSyncRevision(context.Background(), testUserLogin)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), rev)
+
+	mock.ExpectQuery("SELECT sync_revision FROM users").
+		WithArgs(testUserLogin).
+		WillReturnError(sql.ErrNoRows)
+	_, err = testDB.GetSyncRevision(context.Background(), testUserLogin)
+	assertStorErr(t, err, true, EmptyResult)
+
+	mock.ExpectQuery("SELECT sync_revision FROM users").
+		WithArgs(testUserLogin).
+		WillReturnError(errTest)
+	_, err = testDB.GetSyncRevision(context.Background(), testUserLogin)
+	assert.ErrorIs(t, err, errTest)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeletedRecordsAfterRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	mock.ExpectQuery("SELECT record_type, prompt, record_key FROM record_tombstones").
+		WithArgs(testUserLogin, int64(3), int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"record_type", "prompt", "record_key"}).
+			AddRow(int32(CardRecord), []byte{}, testCard.Number).
+			AddRow(int32(TextDataRecord), testTextRecord.Prompt, []byte{}))
+	records, err := testDB.GetDeletedRecordsAfterRevision(context.Background(), testUserLogin, 3, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []DeletedRecord{
+		{Type: CardRecord, Prompt: []byte{}, Key: testCard.Number},
+		{Type: TextDataRecord, Prompt: testTextRecord.Prompt, Key: []byte{}},
+	}, records)
+
+	mock.ExpectQuery("SELECT record_type, prompt, record_key FROM record_tombstones").
+		WithArgs(testUserLogin, int64(3), int64(5)).
+		WillReturnError(errTest)
+	_, err = testDB.GetDeletedRecordsAfterRevision(context.Background(), testUserLogin, 3, 5)
+	assert.ErrorIs(t, err, errTest)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantRes      []UserInfo
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"login", "disabled", "count", "coalesce"}).
+					AddRow("alice", false, int64(3), int64(120)).
+					AddRow("bob", true, int64(0), int64(0))
+				mock.ExpectQuery("SELECT u.login, u.disabled, count\\(r.user_id\\), coalesce\\(sum\\(r.size\\), 0\\)").
+					WillReturnRows(rows)
+			},
+			wantRes: []UserInfo{
+				{Login: "alice", Records: 3, Bytes: 120},
+				{Login: "bob", Disabled: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT u.login").WillReturnError(errTest)
+			},
+			wantRes: nil,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehavior()
+			res, err := testDB.ListUsers(context.Background())
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantRes, res)
+		})
+	}
+}
+
+func TestSetUserDisabled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	mock.ExpectExec("UPDATE users SET disabled").
+		WithArgs(testUserLogin, true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, testDB.SetUserDisabled(context.Background(), testUserLogin, true))
+
+	mock.ExpectExec("UPDATE users SET disabled").
+		WithArgs(testUserLogin, false).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = testDB.SetUserDisabled(context.Background(), testUserLogin, false)
+	var storErr *StorErr
+	assert.ErrorAs(t, err, &storErr)
+	assert.Equal(t, EmptyResult, storErr.ErrType)
+}
+
+func TestLogoutUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	mock.ExpectExec("UPDATE users SET session = session \\+ 1").
+		WithArgs(testUserLogin).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, testDB.LogoutUser(context.Background(), testUserLogin))
+
+	mock.ExpectExec("UPDATE users SET session").
+		WithArgs(testUserLogin).
+		WillReturnError(errTest)
+	assert.Error(t, testDB.LogoutUser(context.Background(), testUserLogin))
+}
+
+func TestDeleteUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		wantType     TypeStorErrors
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT user_id FROM users").
+					WithArgs(testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(int64(7)))
+				for _, name := range historyTables {
+					mock.ExpectExec("DELETE FROM " + name + "_history").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectExec("DELETE FROM " + name).WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectExec("DELETE FROM record_tombstones").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM collection_members").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM org_members").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM emergency_contacts").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE users SET login = 'deleted-' \\|\\| user_id").
+					WithArgs(int64(7), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "not found test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT user_id FROM users").
+					WithArgs(testUserLogin).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: EmptyResult,
+		},
+		{
+			name: "delete error test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT user_id FROM users").
+					WithArgs(testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(int64(7)))
+				mock.ExpectExec("DELETE FROM cards_history").WithArgs(int64(7)).WillReturnError(errTest)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehavior()
+			err := testDB.DeleteUser(context.Background(), testUserLogin)
+			if !test.wantErr {
+				assert.NoError(t, err)
+			} else if test.wantType != "" {
+				var storErr *StorErr
+				assert.ErrorAs(t, err, &storErr)
+				assert.Equal(t, test.wantType, storErr.ErrType)
+			} else {
+				assert.Error(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAddEmergencyContact(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	wrapped := []byte("wrapped")
+	at, _ := time.Parse(time.RFC3339, testTime)
+	records := []SharedRecord{{Type: TextDataRecord, Data: []byte("sealed"), TimeStamp: at}}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		wantType     TypeStorErrors
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO emergency_contacts").
+					WithArgs(testUserLogin, "contact", int64(172800), wrapped).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM emergency_records").
+					WithArgs(testUserLogin, "contact").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO emergency_records").
+					WithArgs(testUserLogin, "contact", TextDataRecord, []byte("sealed"), at).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "contact not found test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO emergency_contacts").
+					WithArgs(testUserLogin, "contact", int64(172800), wrapped).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: EmptyResult,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehavior()
+			err := testDB.AddEmergencyContact(context.Background(), testUserLogin, "contact", 48*time.Hour, wrapped, records)
+			if !test.wantErr {
+				assert.NoError(t, err)
+			} else {
+				var storErr *StorErr
+				assert.ErrorAs(t, err, &storErr)
+				assert.Equal(t, test.wantType, storErr.ErrType)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateEmergencyRecords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	wrapped := []byte("wrapped")
+	at, _ := time.Parse(time.RFC3339, testTime)
+	records := []SharedRecord{{Type: BinaryDataRecord, Data: []byte("sealed"), TimeStamp: at}}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      bool
+		wantType     TypeStorErrors
+	}{
+		{
+			name: "ok test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE emergency_contacts SET wrapped_key").
+					WithArgs(testUserLogin, "contact", wrapped).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM emergency_records").
+					WithArgs(testUserLogin, "contact").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO emergency_records").
+					WithArgs(testUserLogin, "contact", BinaryDataRecord, []byte("sealed"), at).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "contact not found test",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE emergency_contacts SET wrapped_key").
+					WithArgs(testUserLogin, "contact", wrapped).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr:  true,
+			wantType: EmptyResult,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.mockBehavior()
+			err := testDB.UpdateEmergencyRecords(context.Background(), testUserLogin, "contact", wrapped, records)
+			if !test.wantErr {
+				assert.NoError(t, err)
+			} else {
+				var storErr *StorErr
+				assert.ErrorAs(t, err, &storErr)
+				assert.Equal(t, test.wantType, storErr.ErrType)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRequestEmergencyAccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	at, _ := time.Parse(time.RFC3339, testTime)
+
+	mock.ExpectQuery("UPDATE emergency_contacts SET requested_at = COALESCE").
+		WithArgs("owner", testUserLogin, at).
+		WillReturnRows(sqlmock.NewRows([]string{"wait_period", "requested_at"}).AddRow(int64(3600), at))
+	c, err := testDB.RequestEmergencyAccess(context.Background(), testUserLogin, "owner", at)
+	assert.NoError(t, err)
+	assert.Equal(t, EmergencyContact{Grantor: "owner", Grantee: testUserLogin, WaitPeriod: time.Hour, RequestedAt: at}, c)
+	assert.Equal(t, at.Add(time.Hour), c.AvailableAt())
+
+	mock.ExpectQuery("UPDATE emergency_contacts SET requested_at = COALESCE").
+		WithArgs("owner", testUserLogin, at).
+		WillReturnError(sql.ErrNoRows)
+	_, err = testDB.RequestEmergencyAccess(context.Background(), testUserLogin, "owner", at)
+	var storErr *StorErr
+	assert.ErrorAs(t, err, &storErr)
+	assert.Equal(t, EmptyResult, storErr.ErrType)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeclineEmergencyAccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+
+	mock.ExpectExec("UPDATE emergency_contacts SET requested_at = NULL").
+		WithArgs(testUserLogin, "contact").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, testDB.DeclineEmergencyAccess(context.Background(), testUserLogin, "contact"))
+
+	mock.ExpectExec("DELETE FROM emergency_contacts").
+		WithArgs(testUserLogin, "contact").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Error(t, testDB.RemoveEmergencyContact(context.Background(), testUserLogin, "contact"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListEmergencyContacts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	at, _ := time.Parse(time.RFC3339, testTime)
+
+	mock.ExpectQuery("SELECT g.login, c.login, e.wait_period, e.requested_at").
+		WithArgs(testUserLogin).
+		WillReturnRows(sqlmock.NewRows([]string{"grantor", "grantee", "wait_period", "requested_at"}).
+			AddRow(testUserLogin, "contact", int64(60), nil).
+			AddRow("owner", testUserLogin, int64(3600), at))
+	contacts, err := testDB.ListEmergencyContacts(context.Background(), testUserLogin)
+	assert.NoError(t, err)
+	assert.Equal(t, []EmergencyContact{
+		{Grantor: testUserLogin, Grantee: "contact", WaitPeriod: time.Minute},
+		{Grantor: "owner", Grantee: testUserLogin, WaitPeriod: time.Hour, RequestedAt: at},
+	}, contacts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEmergencyKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := DBStorage{dbHandle: db}
+	at, _ := time.Parse(time.RFC3339, testTime)
+	wrapped := []byte("wrapped")
+	records := []SharedRecord{{Type: TextDataRecord, Data: []byte("sealed"), TimeStamp: at}}
+
+	tests := []struct {
+		name        string
+		requestedAt any
+		now         time.Time
+		want        []byte
+		wantRecords []SharedRecord
+		wantType    TypeStorErrors
+	}{
+		{name: "granted test", requestedAt: at, now: at.Add(time.Hour), want: wrapped, wantRecords: records},
+		{name: "waiting test", requestedAt: at, now: at.Add(time.Minute), wantType: Forbidden},
+		{name: "not requested test", requestedAt: nil, now: at, wantType: Forbidden},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock.ExpectQuery("SELECT wait_period, requested_at, wrapped_key FROM emergency_contacts").
+				WithArgs("owner", testUserLogin).
+				WillReturnRows(sqlmock.NewRows([]string{"wait_period", "requested_at", "wrapped_key"}).
+					AddRow(int64(3600), test.requestedAt, wrapped))
+			if test.wantType == "" {
+				mock.ExpectQuery("SELECT record_type, data, time_stamp FROM emergency_records").
+					WithArgs("owner", testUserLogin).
+					WillReturnRows(sqlmock.NewRows([]string{"record_type", "data", "time_stamp"}).
+						AddRow(TextDataRecord, []byte("sealed"), at))
+			}
+			key, recs, err := testDB.GetEmergencyKey(context.Background(), testUserLogin, "owner", test.now)
+			if test.wantType == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, key)
+				assert.Equal(t, test.wantRecords, recs)
+			} else {
+				var storErr *StorErr
+				assert.ErrorAs(t, err, &storErr)
+				assert.Equal(t, test.wantType, storErr.ErrType)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDBStorage_Timeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts Timeouts
+		parent   time.Duration
+		bulk     bool
+		want     time.Duration
+	}{
+		{name: "default query", want: defQueryTimeout},
+		{name: "default bulk", bulk: true, want: defBulkTimeout},
+		{name: "configured query", timeouts: Timeouts{Query: time.Minute}, want: time.Minute},
+		{name: "configured bulk", timeouts: Timeouts{Bulk: time.Hour}, bulk: true, want: time.Hour},
+		{name: "incoming deadline", timeouts: Timeouts{Query: time.Second}, parent: time.Hour, want: time.Hour},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := DBStorage{timeouts: test.timeouts}
+			parent := context.Background()
+			if test.parent > 0 {
+				var cancel context.CancelFunc
+				parent, cancel = context.WithTimeout(parent, test.parent)
+				defer cancel()
+			}
+
+			var ctx context.Context
+			var cancel context.CancelFunc
+			if test.bulk {
+				ctx, cancel = db.bulkCtx(parent)
+			} else {
+				ctx, cancel = db.queryCtx(parent)
+			}
+			defer cancel()
+
+			dl, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(test.want), dl, time.Second)
+		})
+	}
+}
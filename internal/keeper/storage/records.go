@@ -0,0 +1,681 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/recordtable"
+)
+
+var cardsTable = recordtable.Table[Card]{
+	Name:    "cards",
+	Columns: []string{"prompt", "number", "date", "code", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"number"},
+	Fields: func(r *Card) []any {
+		return []any{&r.Prompt, &r.Number, &r.Date, &r.Code, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Dollar,
+	Revision:    true,
+}
+
+var loginsTable = recordtable.Table[LoginPwd]{
+	Name:    "logins",
+	Columns: []string{"prompt", "login", "pwd", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"prompt", "login"},
+	Fields: func(r *LoginPwd) []any {
+		return []any{&r.Prompt, &r.Login, &r.Pwd, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Dollar,
+	Revision:    true,
+}
+
+var textsTable = recordtable.Table[TextRecord]{
+	Name:    "text_data",
+	Columns: []string{"prompt", "data", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"prompt"},
+	Fields: func(r *TextRecord) []any {
+		return []any{&r.Prompt, &r.Data, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Dollar,
+	Revision:    true,
+}
+
+var binariesTable = recordtable.Table[BinaryRecord]{
+	Name: "binary_data",
+	Columns: []string{"prompt", "data", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn,
+		"blob_ref", "blob_sha256", "blob_size"},
+	Keys: []string{"prompt"},
+	Fields: func(r *BinaryRecord) []any {
+		return []any{&r.Prompt, &r.Data, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp, &r.blob.ref, &r.blob.sha256, &r.blob.size}
+	},
+	Placeholder: recordtable.Dollar,
+	Revision:    true,
+}
+
+var otpsTable = recordtable.Table[Otp]{
+	Name:    "otps",
+	Columns: []string{"issuer", "account", "secret", "algorithm", "digits", "period", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"issuer", "account"},
+	Fields: func(r *Otp) []any {
+		return []any{&r.Issuer, &r.Account, &r.Secret, &r.Algorithm, &r.Digits, &r.Period, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Dollar,
+	Revision:    true,
+}
+
+var sshKeysTable = recordtable.Table[SshKey]{
+	Name:    "ssh_keys",
+	Columns: []string{"prompt", "private_key", "public_key", "comment", "passphrase", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"prompt"},
+	Fields: func(r *SshKey) []any {
+		return []any{&r.Prompt, &r.PrivateKey, &r.PublicKey, &r.Comment, &r.Passphrase, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Dollar,
+	Revision:    true,
+}
+
+var templatesTable = recordtable.Table[Template]{
+	Name:    "templates",
+	Columns: []string{"name", "fields", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"name"},
+	Fields: func(r *Template) []any {
+		return []any{&r.Name, &r.Fields, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Dollar,
+	Revision:    true,
+}
+
+var customRecordsTable = recordtable.Table[CustomRecord]{
+	Name:    "custom_records",
+	Columns: []string{"prompt", "template", "data", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"prompt"},
+	Fields: func(r *CustomRecord) []any {
+		return []any{&r.Prompt, &r.Template, &r.Data, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Dollar,
+	Revision:    true,
+}
+
+// recordKind описывает тип личных записей пользователя: таблицу записей и представление
+// записи для клиента. Новый тип записей добавляется описанием его таблицы в recordKinds.
+type recordKind[T any] struct {
+	// Type - тип записи.
+	Type RecordType
+	// Table - таблица записей.
+	Table recordtable.Table[T]
+	// Prompt - столбец с подсказкой записи. Остальные ключевые столбцы передаются клиенту как ключ записи.
+	Prompt string
+	// ListSize - столбцы, из которых складывается размер записи в списке записей.
+	ListSize []string
+	// Service - служебные столбцы, не входящие в размер записи.
+	Service []string
+	// Save подготавливает запись к сохранению в БД.
+	Save func(db *DBStorage, ctx context.Context, r T) (T, error)
+	// Load дополняет запись, прочитанную из БД.
+	Load func(db *DBStorage, ctx context.Context, r *T) error
+}
+
+// kind - операции с записями одного типа, переданными без указания типа.
+type kind interface {
+	recordType() RecordType
+	tableName() string
+	is(r any) bool
+	add(ctx context.Context, db *DBStorage, userLogin string, r any) (Revision, error)
+	get(ctx context.Context, db *DBStorage, userLogin string, key any) (any, error)
+	forceUpdate(ctx context.Context, db *DBStorage, userLogin string, r any) (Revision, error)
+	afterRevision(ctx context.Context, db *DBStorage, userLogin string, since int64, until int64) ([]any, error)
+	versions(ctx context.Context, db *DBStorage, userLogin string, key any) ([]Version, error)
+	revision(ctx context.Context, db *DBStorage, userLogin string, key any) (Revision, error)
+	restore(ctx context.Context, db *DBStorage, userLogin string, key any, version int64, timeStamp time.Time) (any, error)
+	delete(ctx context.Context, tx *sql.Tx, userLogin string, key any) error
+	change(r any) Change
+	size(ctx context.Context, q querier, userLogin string, key any) (int64, error)
+	listQuery() string
+	sizeQuery() string
+}
+
+// recordKinds - типы личных записей пользователя.
+var recordKinds = []kind{
+	recordKind[Card]{Type: CardRecord, Table: cardsTable, Prompt: "prompt",
+		ListSize: []string{"number", "date", "code"}},
+	recordKind[LoginPwd]{Type: LoginPwdRecord, Table: loginsTable, Prompt: "prompt",
+		ListSize: []string{"login", "pwd"}},
+	recordKind[TextRecord]{Type: TextDataRecord, Table: textsTable, Prompt: "prompt",
+		ListSize: []string{"data"}},
+	recordKind[BinaryRecord]{Type: BinaryDataRecord, Table: binariesTable, Prompt: "prompt",
+		ListSize: []string{"data", "blob_size"}, Service: []string{"blob_ref", "blob_sha256"},
+		Save: (*DBStorage).putBlob, Load: (*DBStorage).loadBlob},
+	recordKind[Otp]{Type: OtpRecord, Table: otpsTable, Prompt: "issuer",
+		ListSize: []string{"secret"}},
+	recordKind[SshKey]{Type: SshKeyRecord, Table: sshKeysTable, Prompt: "prompt",
+		ListSize: []string{"private_key"}},
+	recordKind[Template]{Type: TemplateRecord, Table: templatesTable, Prompt: "name",
+		ListSize: []string{"fields"}},
+	recordKind[CustomRecord]{Type: CustomDataRecord, Table: customRecordsTable, Prompt: "prompt",
+		ListSize: []string{"data"}},
+}
+
+// kindOf возвращает тип записи r.
+func kindOf(r any) (kind, error) {
+	for _, k := range recordKinds {
+		if k.is(r) {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported record type %T", r)
+}
+
+// kindByType возвращает тип записи с номером t.
+func kindByType(t RecordType) (kind, error) {
+	for _, k := range recordKinds {
+		if k.recordType() == t {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported record type %d", t)
+}
+
+// kindsQuery объединяет запросы query ко всем таблицам записей.
+func kindsQuery(query func(k kind) string) string {
+	queries := make([]string, 0, len(recordKinds))
+	for _, k := range recordKinds {
+		queries = append(queries, query(k))
+	}
+	return strings.Join(queries, "\n\tUNION ALL\n\t")
+}
+
+func (k recordKind[T]) recordType() RecordType {
+	return k.Type
+}
+
+func (k recordKind[T]) tableName() string {
+	return k.Table.Name
+}
+
+func (k recordKind[T]) is(r any) bool {
+	_, ok := r.(T)
+	return ok
+}
+
+func (k recordKind[T]) save(ctx context.Context, db *DBStorage, r T) (T, error) {
+	if k.Save == nil {
+		return r, nil
+	}
+	return k.Save(db, ctx, r)
+}
+
+func (k recordKind[T]) load(ctx context.Context, db *DBStorage, r *T) error {
+	if k.Load == nil {
+		return nil
+	}
+	return k.Load(db, ctx, r)
+}
+
+func (k recordKind[T]) add(ctx context.Context, db *DBStorage, userLogin string, r any) (Revision, error) {
+	v, err := k.save(ctx, db, r.(T))
+	if err != nil {
+		return Revision{}, err
+	}
+	return addRecord(ctx, db, k.Table, userLogin, v, k.Table.Get(&v, recordtable.TimeStampColumn).(time.Time))
+}
+
+func (k recordKind[T]) get(ctx context.Context, db *DBStorage, userLogin string, key any) (any, error) {
+	r, err := getRecord(ctx, db, k.Table, userLogin, key.(T))
+	if err != nil {
+		return nil, err
+	}
+	err = k.load(ctx, db, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (k recordKind[T]) forceUpdate(ctx context.Context, db *DBStorage, userLogin string, r any) (Revision, error) {
+	v, err := k.save(ctx, db, r.(T))
+	if err != nil {
+		return Revision{}, err
+	}
+	return forceUpdateRecord(ctx, db, k.Table, userLogin, v)
+}
+
+func (k recordKind[T]) afterRevision(ctx context.Context, db *DBStorage, userLogin string,
+	since int64, until int64) ([]any, error) {
+	records, err := getRecordsAfterRevision(ctx, db, k.Table, userLogin, since, until)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]any, 0, len(records))
+	for _, r := range records {
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+func (k recordKind[T]) versions(ctx context.Context, db *DBStorage, userLogin string, key any) ([]Version, error) {
+	versions, err := listVersions(ctx, db, k.Table, userLogin, key.(T))
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range versions {
+		r := v.Record.(T)
+		err = k.load(ctx, db, &r)
+		if err != nil {
+			return nil, err
+		}
+		versions[i].Record = r
+	}
+	return versions, nil
+}
+
+func (k recordKind[T]) revision(ctx context.Context, db *DBStorage, userLogin string, key any) (Revision, error) {
+	return getRevision(ctx, db, k.Table, userLogin, key.(T))
+}
+
+func (k recordKind[T]) restore(ctx context.Context, db *DBStorage, userLogin string, key any,
+	version int64, timeStamp time.Time) (any, error) {
+	r, err := restoreVersion(ctx, db, k.Table, userLogin, key.(T), version, timeStamp)
+	if err != nil {
+		return nil, err
+	}
+	err = k.load(ctx, db, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (k recordKind[T]) delete(ctx context.Context, tx *sql.Tx, userLogin string, key any) error {
+	return deleteRecordTx(ctx, tx, k.Table, userLogin, key.(T))
+}
+
+func (k recordKind[T]) change(r any) Change {
+	v := r.(T)
+	c := Change{Type: k.Type, TimeStamp: k.Table.Get(&v, recordtable.TimeStampColumn).(time.Time)}
+	for _, column := range k.Table.Keys {
+		if column == k.Prompt {
+			c.Prompt = k.Table.Get(&v, column).([]byte)
+		} else {
+			c.Key = k.Table.Get(&v, column).([]byte)
+		}
+	}
+	return c
+}
+
+// listQuery возвращает запрос для получения типа, подсказки, последнего ключевого столбца,
+// времени изменения и размера записей пользователя с логином $1.
+func (k recordKind[T]) listQuery() string {
+	return fmt.Sprintf("SELECT %d AS record_type, %s AS prompt, %s AS record_key, %s, %s AS size "+
+		"FROM %s WHERE user_id = (SELECT user_id FROM users WHERE login = $1)",
+		k.Type, k.Prompt, k.Table.Keys[len(k.Table.Keys)-1], recordtable.TimeStampColumn,
+		k.Table.SizeExpr(k.ListSize), k.Table.Name)
+}
+
+// sizeColumns возвращает столбцы, из которых складывается размер записи:
+// все, кроме времени изменения и служебных.
+func (k recordKind[T]) sizeColumns() []string {
+	columns := make([]string, 0, len(k.Table.Columns))
+	for _, c := range k.Table.Columns {
+		if c != recordtable.TimeStampColumn && !slices.Contains(k.Service, c) {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// size получает размер записи пользователя с ключевыми полями key без загрузки ее содержимого.
+// Если записи нет, возвращается ошибка EmptyResult.
+func (k recordKind[T]) size(ctx context.Context, q querier, userLogin string, key any) (int64, error) {
+	v := key.(T)
+	var size int64
+	err := q.QueryRowContext(ctx, k.Table.SizeQuery(k.sizeColumns()), k.Table.GetArgs(userLogin, &v)...).Scan(&size)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, NewStorError(EmptyResult, err)
+	}
+	return size, err
+}
+
+// sizeQuery возвращает запрос для получения пользователя, типа и размера каждой записи.
+// Размер записи - сумма длин всех ее полей, кроме времени изменения и служебных.
+func (k recordKind[T]) sizeQuery() string {
+	return fmt.Sprintf("SELECT user_id, %d AS record_type, %s AS size FROM %s",
+		k.Type, k.Table.SizeExpr(k.sizeColumns()), k.Table.Name)
+}
+
+// bumpSyncRevisionQuery увеличивает номер ревизии пользователя. Строка пользователя остается
+// заблокированной до конца транзакции, поэтому изменения записей одного пользователя
+// получают номера ревизий в порядке фиксации транзакций.
+const bumpSyncRevisionQuery = "UPDATE users SET sync_revision = sync_revision + 1 WHERE login = $1"
+
+// writeRecord выполняет изменение записи пользователя write в транзакции, в которой
+// увеличивается номер ревизии пользователя и запись отмечается этим номером.
+// Квоты пользователя проверяются в той же транзакции, пока строка пользователя заблокирована.
+// После фиксации транзакции об изменении сообщается подписчикам.
+// Возвращается ревизия записи, полученная в той же транзакции.
+func writeRecord[T any](ctx context.Context, db *DBStorage, t recordtable.Table[T],
+	userLogin string, r T, write func(tx *sql.Tx) error) (Revision, error) {
+	tx, err := db.dbHandle.BeginTx(ctx, nil)
+	if err != nil {
+		return Revision{}, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, bumpSyncRevisionQuery, userLogin)
+	if err != nil {
+		return Revision{}, err
+	}
+	quota, err := db.beginQuota(ctx, tx, userLogin, r)
+	if err != nil {
+		return Revision{}, err
+	}
+	err = write(tx)
+	if err != nil {
+		return Revision{}, err
+	}
+	err = quota.check(ctx, tx, userLogin, r)
+	if err != nil {
+		return Revision{}, err
+	}
+	var rev Revision
+	err = tx.QueryRowContext(ctx, t.SyncRevisionQuery(), t.GetArgs(userLogin, &r)...).
+		Scan(&rev.ID, &rev.Revision, &rev.TimeStamp)
+	if err != nil {
+		return Revision{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return Revision{}, err
+	}
+	db.notify(userLogin, r)
+
+	return rev, nil
+}
+
+// addRecord добавляет запись пользователя.
+// Если запись уже существует, она обновляется, только если на сервере нет более новой версии.
+// Предыдущая версия при этом сохраняется в истории.
+// Проверка времени изменения и запись выполняются атомарно: без истории одним запросом
+// INSERT ... ON CONFLICT DO UPDATE, с историей - с блокировкой записи.
+// Возвращается ревизия сохраненной записи.
+func addRecord[T any](ctx context.Context, db *DBStorage, t recordtable.Table[T],
+	userLogin string, r T, timeStamp time.Time) (Revision, error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	return writeRecord(ctx, db, t, userLogin, r, func(tx *sql.Tx) error {
+		if db.retention > 0 {
+			return addRecordWithHistory(ctx, tx, db.dialect, t, userLogin, r, timeStamp, db.retention)
+		}
+
+		result, err := tx.ExecContext(ctx, t.UpsertQuery(), t.InsertArgs(userLogin, &r)...)
+		if err != nil {
+			if isNotNullViolation(err) {
+				return NewStorError(NullValues, err)
+			}
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			row := tx.QueryRowContext(ctx, t.TimeStampQuery(), t.TimeStampArgs(userLogin, &r)...)
+			var tServer time.Time
+			err = row.Scan(&tServer)
+			if err != nil {
+				return err
+			}
+			return NewConflictError(tServer, errors.New("the server has a newer version of the record"))
+		}
+		if rows != 1 {
+			return errors.New("expected to affect 1 row")
+		}
+		return nil
+	})
+}
+
+// addRecordWithHistory добавляет или обновляет запись пользователя в транзакции tx.
+// Существующая запись блокируется до конца транзакции, поэтому параллельные изменения
+// одной записи не теряются и не сохраняют в истории одну и ту же версию дважды.
+func addRecordWithHistory[T any](ctx context.Context, tx *sql.Tx, d dialect, t recordtable.Table[T],
+	userLogin string, r T, timeStamp time.Time, retention int) error {
+	result, err := tx.ExecContext(ctx, t.InsertOrSkipQuery(), t.InsertArgs(userLogin, &r)...)
+	if err != nil {
+		if isNotNullViolation(err) {
+			return NewStorError(NullValues, err)
+		}
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 1 {
+		return nil
+	}
+
+	tServer, err := lockRecord(ctx, tx, d, t, userLogin, r)
+	if err != nil {
+		return err
+	}
+	if tServer.After(timeStamp) {
+		return NewConflictError(tServer, errors.New("the server has a newer version of the record"))
+	}
+	return archiveAndUpdate(ctx, tx, t, userLogin, r, retention)
+}
+
+// getRecord получает запись пользователя по ключевым полям записи key.
+func getRecord[T any](ctx context.Context, db *DBStorage, t recordtable.Table[T],
+	userLogin string, key T) (T, error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	row := db.dbHandle.QueryRowContext(ctx, t.GetQuery(), t.GetArgs(userLogin, &key)...)
+	err := row.Scan(t.GetDest(&key)...)
+	if err != nil {
+		var empty T
+		if errors.Is(err, sql.ErrNoRows) {
+			return empty, NewStorError(EmptyResult, err)
+		}
+		return empty, err
+	}
+
+	return key, nil
+}
+
+// getRevision получает ревизию записи пользователя по ключевым полям записи key.
+func getRevision[T any](ctx context.Context, db *DBStorage, t recordtable.Table[T],
+	userLogin string, key T) (Revision, error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	var r Revision
+	row := db.dbHandle.QueryRowContext(ctx, t.RevisionQuery(), t.GetArgs(userLogin, &key)...)
+	err := row.Scan(&r.ID, &r.Revision, &r.TimeStamp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Revision{}, NewStorError(EmptyResult, err)
+		}
+		return Revision{}, err
+	}
+
+	return r, nil
+}
+
+// getRecordsAfterRevision получает записи пользователя, измененные на ревизиях
+// после since и не позднее until.
+func getRecordsAfterRevision[T any](ctx context.Context, db *DBStorage, t recordtable.Table[T],
+	userLogin string, since int64, until int64) (records []T, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
+	defer cancel()
+
+	rows, err := db.dbHandle.QueryContext(ctx, t.AfterRevisionQuery(), userLogin, since, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r T
+		err = rows.Scan(t.RowDest(&r)...)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// forceUpdateRecord обновляет запись пользователя без проверки времени изменения
+// и возвращает ее ревизию. Предыдущая версия сохраняется в истории.
+func forceUpdateRecord[T any](ctx context.Context, db *DBStorage, t recordtable.Table[T],
+	userLogin string, r T) (Revision, error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	return updateRecord(ctx, db, t, userLogin, r)
+}
+
+// execer выполняет запросы к БД как вне транзакции, так и внутри нее.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// querier выполняет выборки из БД как вне транзакции, так и внутри нее.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// execUpdate обновляет одну запись пользователя.
+func execUpdate[T any](ctx context.Context, db execer, t recordtable.Table[T],
+	userLogin string, r T) error {
+	result, err := db.ExecContext(ctx, t.UpdateQuery(), t.UpdateArgs(userLogin, &r)...)
+	if err != nil {
+		return err
+	}
+	row, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if row != 1 {
+		return errors.New("expected to affect 1 row")
+	}
+	return nil
+}
+
+// updateRecord обновляет запись пользователя и сообщает об изменении подписчикам.
+// Если задано количество хранимых версий, запись блокируется, ее текущая версия
+// перед обновлением копируется в историю, а в истории остаются только последние версии.
+// Возвращается ревизия обновленной записи.
+func updateRecord[T any](ctx context.Context, db *DBStorage, t recordtable.Table[T],
+	userLogin string, r T) (Revision, error) {
+	return writeRecord(ctx, db, t, userLogin, r, func(tx *sql.Tx) error {
+		if db.retention <= 0 {
+			return execUpdate(ctx, tx, t, userLogin, r)
+		}
+		_, err := lockRecord(ctx, tx, db.dialect, t, userLogin, r)
+		if err != nil {
+			return err
+		}
+		return archiveAndUpdate(ctx, tx, t, userLogin, r, db.retention)
+	})
+}
+
+// lockRecord блокирует запись пользователя до конца транзакции и возвращает время ее изменения.
+func lockRecord[T any](ctx context.Context, tx *sql.Tx, d dialect, t recordtable.Table[T],
+	userLogin string, r T) (time.Time, error) {
+	var tServer time.Time
+	err := tx.QueryRowContext(ctx, t.TimeStampQuery()+d.forUpdate, t.TimeStampArgs(userLogin, &r)...).Scan(&tServer)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return tServer, NewStorError(EmptyResult, err)
+		}
+		return tServer, err
+	}
+	return tServer, nil
+}
+
+// archiveAndUpdate копирует текущую версию записи в историю, обновляет запись
+// и удаляет из истории версии сверх retention.
+func archiveAndUpdate[T any](ctx context.Context, tx *sql.Tx, t recordtable.Table[T],
+	userLogin string, r T, retention int) error {
+	_, err := tx.ExecContext(ctx, t.ArchiveQuery(), t.GetArgs(userLogin, &r)...)
+	if err != nil {
+		return err
+	}
+	err = execUpdate(ctx, tx, t, userLogin, r)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, t.PruneQuery(), t.PruneArgs(userLogin, &r, retention)...)
+	return err
+}
+
+// listVersions получает предыдущие версии записи пользователя, начиная с последней.
+func listVersions[T any](ctx context.Context, db *DBStorage, t recordtable.Table[T],
+	userLogin string, key T) (versions []Version, err error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	rows, err := db.dbHandle.QueryContext(ctx, t.VersionsQuery(), t.GetArgs(userLogin, &key)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v int64
+		var r T
+		err = rows.Scan(t.VersionsDest(&v, &r)...)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, Version{ID: v, Record: r})
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// restoreVersion заменяет запись пользователя ее предыдущей версией с новым временем изменения.
+// Заменяемая версия сохраняется в истории.
+func restoreVersion[T any](ctx context.Context, db *DBStorage, t recordtable.Table[T],
+	userLogin string, key T, version int64, timeStamp time.Time) (T, error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	var r T
+	row := db.dbHandle.QueryRowContext(ctx, t.VersionQuery(), t.VersionArgs(userLogin, &key, version)...)
+	err := row.Scan(t.Fields(&r)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return r, NewStorError(EmptyResult, err)
+		}
+		return r, err
+	}
+
+	t.Set(&r, recordtable.TimeStampColumn, timeStamp)
+	_, err = updateRecord(ctx, db, t, userLogin, r)
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+
+	return r, nil
+}
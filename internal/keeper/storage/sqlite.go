@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// sqliteScheme - префикс строки подключения к встроенной БД SQLite,
+// например sqlite:///var/lib/keeper/keeper.db или sqlite:keeper.db.
+const sqliteScheme = "sqlite:"
+
+// sqliteDriverName - имя драйвера SQLite, приводящего время в параметрах запросов к UTC.
+const sqliteDriverName = "keeper-sqlite"
+
+// sqliteParams - параметры подключения к SQLite: проверка внешних ключей, ожидание блокировки,
+// транзакции сразу на запись и запись времени в формате, который драйвер читает обратно.
+const sqliteParams = "_pragma=foreign_keys(1)&_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)" +
+	"&_txlock=immediate&_time_format=sqlite"
+
+func init() {
+	sql.Register(sqliteDriverName, sqliteDriver{})
+}
+
+// sqliteDSN преобразует строку подключения со схемой sqlite: в имя файла для драйвера.
+// Второй результат false означает, что строка подключения относится к PostgreSQL.
+func sqliteDSN(DBURI string) (string, bool) {
+	path, ok := strings.CutPrefix(DBURI, sqliteScheme)
+	if !ok {
+		return "", false
+	}
+	path = strings.TrimPrefix(path, "//")
+	if strings.Contains(path, "?") {
+		return "file:" + path + "&" + sqliteParams, true
+	}
+	return "file:" + path + "?" + sqliteParams, true
+}
+
+// openDB подключается к БД, выбирая СУБД по схеме строки подключения:
+// sqlite: - встроенная БД SQLite, остальные строки - PostgreSQL.
+func openDB(DBURI string) (*sql.DB, dialect, error) {
+	if name, ok := sqliteDSN(DBURI); ok {
+		db, err := sql.Open(sqliteDialect.driver, name)
+		return db, sqliteDialect, err
+	}
+	db, err := sql.Open(postgresDialect.driver, DBURI)
+	return db, postgresDialect, err
+}
+
+// sqliteDriver - драйвер SQLite, приводящий время в параметрах запросов к UTC.
+type sqliteDriver struct{}
+
+// Open реализует интерфейс driver.Driver.
+func (sqliteDriver) Open(name string) (driver.Conn, error) {
+	c, err := (&sqlite.Driver{}).Open(name)
+	if err != nil {
+		return nil, err
+	}
+	inner, ok := c.(sqliteInner)
+	if !ok {
+		c.Close()
+		return nil, errors.New("unsupported sqlite driver connection")
+	}
+	return &sqliteConn{inner}, nil
+}
+
+// sqliteInner - возможности соединения драйвера SQLite, используемые sqliteConn.
+type sqliteInner interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+}
+
+// sqliteConn - соединение с SQLite, приводящее время в параметрах запросов к UTC.
+type sqliteConn struct {
+	sqliteInner
+}
+
+// CheckNamedValue реализует интерфейс driver.NamedValueChecker.
+// Время приводится к UTC, чтобы строки со временем в БД сравнивались в хронологическом порядке.
+func (c *sqliteConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if t, ok := nv.Value.(time.Time); ok {
+		nv.Value = t.UTC()
+		return nil
+	}
+	return driver.ErrSkip
+}
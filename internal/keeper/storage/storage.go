@@ -2,70 +2,161 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"io"
 	"time"
 
 	"github.com/Julia-ivv/info-keeper.git/internal/keeper/config"
+	"github.com/Julia-ivv/info-keeper.git/pkg/deadline"
 )
 
 // Customer интерфейс для работы с пользователем.
 type Customer interface {
 	RegUser(ctx context.Context, login string, pwd string) error
 	AuthUser(ctx context.Context, login string, pwd string) error
+	GetSession(ctx context.Context, userLogin string) (s Session, err error)
+	GetSyncRevision(ctx context.Context, userLogin string) (revision int64, err error)
+	GetDeletedRecordsAfterRevision(ctx context.Context, userLogin string, since int64, until int64) (records []DeletedRecord, err error)
 }
 
-// CardWorker интерфейс для работы с банковскими картами.
-type CardWorker interface {
-	AddCard(ctx context.Context, userLogin string, prompt []byte,
-		number []byte, date []byte, code []byte, note []byte, timeStamp time.Time) (err error)
-	GetUserCardsAfterTime(ctx context.Context, userLogin string, afterTime time.Time) (cards []Card, err error)
-	GetCard(ctx context.Context, userLogin string, number []byte) (card Card, err error)
-	ForceUpdateCard(ctx context.Context, userLogin string, prompt []byte,
-		number []byte, date []byte, code []byte, note []byte, timeStamp time.Time) (err error)
+// AdminWorker интерфейс для администрирования учетных записей пользователей.
+type AdminWorker interface {
+	ListUsers(ctx context.Context) (users []UserInfo, err error)
+	SetUserDisabled(ctx context.Context, userLogin string, disabled bool) error
+	LogoutUser(ctx context.Context, userLogin string) error
+	DeleteUser(ctx context.Context, userLogin string) error
 }
 
-// LoginPwdWorker интерфейс для работы с парами логин-пароль.
-type LoginPwdWorker interface {
-	AddLoginPwd(ctx context.Context, userLogin string, prompt []byte,
-		login []byte, pwd []byte, note []byte, timeStamp time.Time) (err error)
-	GetUserLoginsPwdsAfterTime(ctx context.Context, userLogin string, afterTime time.Time) (loginsPwds []LoginPwd, err error)
-	GetLoginPwd(ctx context.Context, userLogin string, prompt []byte, login []byte) (loginPwd LoginPwd, err error)
-	ForceUpdateLoginPwd(ctx context.Context, userLogin string, prompt []byte,
-		login []byte, pwd []byte, note []byte, timeStamp time.Time) (err error)
+// RecordWorker интерфейс для работы с личными записями пользователя любого типа.
+// Записи и их ключевые поля передаются значениями типов Card, LoginPwd, TextRecord,
+// BinaryRecord, Otp, SshKey, Template или CustomRecord.
+type RecordWorker interface {
+	AddRecord(ctx context.Context, userLogin string, r any) (revision Revision, err error)
+	GetRecord(ctx context.Context, userLogin string, key any) (record any, err error)
+	ForceUpdateRecord(ctx context.Context, userLogin string, r any) (revision Revision, err error)
+	GetRecordsAfterRevision(ctx context.Context, userLogin string, t RecordType, since int64, until int64) (records []any, err error)
 }
 
-// TextDataWorker интерфейс для работы с текстовыми данными.
-type TextDataWorker interface {
-	AddTextRecord(ctx context.Context, userLogin string, prompt []byte,
-		data []byte, note []byte, timeStamp time.Time) (err error)
-	GetUserTextRecordsAfterTime(ctx context.Context, userLogin string, afterTime time.Time) (records []TextRecord, err error)
-	GetTextRecord(ctx context.Context, userLogin string, prompt []byte) (record TextRecord, err error)
-	ForceUpdateTextRecord(ctx context.Context, userLogin string, prompt []byte,
-		data []byte, note []byte, timeStamp time.Time) (err error)
+// BinaryUploader интерфейс для загрузки содержимого бинарных записей частями.
+type BinaryUploader interface {
+	NewBinaryUpload(ctx context.Context) (u BinaryUpload, err error)
+	SaveBinaryUpload(ctx context.Context, userLogin string, u BinaryUpload, r BinaryRecord, force bool) (revision Revision, err error)
 }
 
-// BinaryDataWorker интерфейс для работы с бинарными данными.
-type BinaryDataWorker interface {
-	AddBinaryRecord(ctx context.Context, userLogin string, prompt []byte,
-		data []byte, note []byte, timeStamp time.Time) (err error)
-	GetUserBinaryRecordsAfterTime(ctx context.Context, userLogin string, afterTime time.Time) (records []BinaryRecord, err error)
-	GetBinaryRecord(ctx context.Context, userLogin string, prompt []byte) (record BinaryRecord, err error)
-	ForceUpdateBinaryRecord(ctx context.Context, userLogin string, prompt []byte,
-		data []byte, note []byte, timeStamp time.Time) (err error)
+// BinaryUpload принимает содержимое бинарной записи частями.
+type BinaryUpload interface {
+	io.Writer
+	// Size возвращает количество принятых байт.
+	Size() int64
+	// Sum возвращает сумму SHA-256 принятых данных.
+	Sum() []byte
+	// Close освобождает ресурсы загрузки, не сохраненной методом SaveBinaryUpload.
+	Close() error
+}
+
+// RecordLister интерфейс для получения списка записей без их данных.
+type RecordLister interface {
+	ListRecords(ctx context.Context, userLogin string, filter ListFilter) (records []RecordInfo, err error)
+}
+
+// UsageCounter интерфейс для получения объема данных пользователя.
+type UsageCounter interface {
+	GetUsage(ctx context.Context, userLogin string) (usage []Usage, err error)
+	GetRecordSize(ctx context.Context, userLogin string, key any) (size int64, err error)
+}
+
+// VersionWorker интерфейс для работы с предыдущими версиями записей.
+type VersionWorker interface {
+	ListVersions(ctx context.Context, userLogin string, key any) (versions []Version, err error)
+	RestoreVersion(ctx context.Context, userLogin string, key any, version int64, timeStamp time.Time) (record any, err error)
+	GetRevision(ctx context.Context, userLogin string, key any) (revision Revision, err error)
+}
+
+// ChangeWatcher интерфейс для подписки на изменения записей.
+type ChangeWatcher interface {
+	WatchChanges(ctx context.Context, userLogin string) <-chan Change
+}
+
+// AuditWorker интерфейс для работы с журналом аудита.
+type AuditWorker interface {
+	AddAuditEvent(ctx context.Context, userLogin string, e AuditEvent) error
+	GetAuditLog(ctx context.Context, userLogin string, from time.Time, to time.Time) (events []AuditEvent, err error)
+}
+
+// OrganizationWorker интерфейс для работы с организациями и общими коллекциями.
+type OrganizationWorker interface {
+	SetUserKeys(ctx context.Context, userLogin string, keys UserKeys) error
+	GetUserKeys(ctx context.Context, userLogin string) (keys UserKeys, err error)
+	GetPublicKey(ctx context.Context, login string) (publicKey []byte, err error)
+	CreateOrganization(ctx context.Context, userLogin string, name string) (org Organization, err error)
+	CreateCollection(ctx context.Context, userLogin string, orgID int64, name string, wrappedKey []byte) (c Collection, err error)
+	ListCollections(ctx context.Context, userLogin string) (collections []Collection, err error)
+	AddCollectionMember(ctx context.Context, userLogin string, collectionID int64, member string, role Role, wrappedKey []byte) error
+	MoveRecordToCollection(ctx context.Context, userLogin string, collectionID int64, key any, r SharedRecord) error
+	ListCollectionRecords(ctx context.Context, userLogin string, collectionID int64) (records []SharedRecord, err error)
+}
+
+// EmergencyWorker интерфейс для работы с экстренным доступом доверенных лиц.
+type EmergencyWorker interface {
+	AddEmergencyContact(ctx context.Context, userLogin string, contact string, wait time.Duration,
+		wrappedKey []byte, records []SharedRecord) error
+	UpdateEmergencyRecords(ctx context.Context, userLogin string, contact string,
+		wrappedKey []byte, records []SharedRecord) error
+	RemoveEmergencyContact(ctx context.Context, userLogin string, contact string) error
+	RequestEmergencyAccess(ctx context.Context, userLogin string, grantor string, at time.Time) (c EmergencyContact, err error)
+	DeclineEmergencyAccess(ctx context.Context, userLogin string, contact string) error
+	ListEmergencyContacts(ctx context.Context, userLogin string) (contacts []EmergencyContact, err error)
+	GetEmergencyKey(ctx context.Context, userLogin string, grantor string, now time.Time) (wrappedKey []byte, records []SharedRecord, err error)
+}
+
+// BlobCollector интерфейс для удаления неиспользуемых блобов бинарных записей.
+type BlobCollector interface {
+	CollectBlobs(ctx context.Context, before time.Time) (removed int, err error)
+}
+
+// StatsProvider интерфейс для получения статистики пула соединений с БД.
+type StatsProvider interface {
+	Stats() sql.DBStats
 }
 
 // Repositorier интерфейс для работы с репозиторием.
 type Repositorier interface {
 	Close() error
 	Customer
-	CardWorker
-	LoginPwdWorker
-	TextDataWorker
-	BinaryDataWorker
+	RecordWorker
+	BinaryUploader
+	RecordLister
+	UsageCounter
+	VersionWorker
+	ChangeWatcher
+	AuditWorker
+	AdminWorker
+	OrganizationWorker
+	EmergencyWorker
+	BlobCollector
+	StatsProvider
 }
 
 // NewStorage создает новый объект репозитория.
+// СУБД выбирается по схеме строки подключения: sqlite: - встроенная БД SQLite
+// для развертывания на одном сервере, остальные строки - PostgreSQL.
+// Если задан каталог блобов, содержимое бинарных записей хранится в нем.
 func NewStorage(cfg config.Flags) (Repositorier, error) {
-	db, err := NewDBStorage(cfg.DBDSN)
+	var blobs BlobStore
+	if cfg.BlobDir != "" {
+		fsBlobs, err := NewFSBlobStore(cfg.BlobDir)
+		if err != nil {
+			return nil, err
+		}
+		blobs = fsBlobs
+	}
+	db, err := NewDBStorage(cfg.DBDSN, cfg.HistoryRetention, Quota{
+		Bytes:   cfg.QuotaBytes,
+		Records: cfg.QuotaRecords,
+	}, Timeouts{
+		Query: deadline.Seconds(cfg.StorageTimeout),
+		Bulk:  deadline.Seconds(cfg.StorageBulkTimeout),
+	}, blobs)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,33 @@
+package keeperadmin
+
+import (
+	"flag"
+
+	"github.com/caarlos0/env"
+)
+
+// Flags хранит настройки запуска приложения администрирования.
+type Flags struct {
+	// GRPC (флаг -g) - адрес gRPC-сервера, например :3200.
+	GRPC string `env:"GRPC_PORT"`
+	// AdminKey (флаг -admin-key) - ключ администратора, заданный в настройках сервера.
+	AdminKey string `env:"ADMIN_KEY"`
+	// Args - команда и ее аргументы.
+	Args []string `env:"-"`
+}
+
+const defGRPC string = ":3200"
+
+// NewConfig создает объект с настройками из флагов или переменных окружения.
+func NewConfig() *Flags {
+	c := &Flags{}
+
+	flag.StringVar(&c.GRPC, "g", defGRPC, "gRPC server address")
+	flag.StringVar(&c.AdminKey, "admin-key", "", "admin key configured on the server")
+	flag.Parse()
+	c.Args = flag.Args()
+
+	env.Parse(c)
+
+	return c
+}
@@ -0,0 +1,130 @@
+// Пакет keeperadmin выполняет команды администрирования сервера через сервис KeeperAdmin.
+package keeperadmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// Usage - описание команд администрирования.
+const Usage = `commands:
+  users            list users with record counts and storage usage
+  disable <login>  disable the user account
+  enable <login>   enable the user account
+  logout <login>   close all sessions of the user
+  delete <login>   delete the user account and all its records
+  stats            print server stats`
+
+// ErrUsage - неизвестная команда или неверные аргументы команды.
+var ErrUsage = errors.New("invalid command")
+
+// command выполняет команду администрирования.
+type command func(ctx context.Context, cl pb.KeeperAdminClient, args []string, out io.Writer) error
+
+var commands = map[string]command{
+	"users":   listUsers,
+	"disable": userCommand("disabled", disableUser),
+	"enable":  userCommand("enabled", enableUser),
+	"logout":  userCommand("logged out", logoutUser),
+	"delete":  userCommand("deleted", deleteUser),
+	"stats":   serverStats,
+}
+
+// Run выполняет команду args[0] с аргументами args[1:] и выводит результат в out.
+// Ключ администратора adminKey передается серверу в метаданных запроса.
+func Run(ctx context.Context, cl pb.KeeperAdminClient, adminKey string, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUsage, args[0])
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, authorizer.AdminKey, adminKey)
+	return cmd(ctx, cl, args[1:], out)
+}
+
+func listUsers(ctx context.Context, cl pb.KeeperAdminClient, args []string, out io.Writer) error {
+	if len(args) != 0 {
+		return ErrUsage
+	}
+	res, err := cl.ListUsers(ctx, &pb.ListUsersRequest{})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LOGIN\tSTATUS\tRECORDS\tBYTES")
+	for _, u := range res.GetUsers() {
+		state := "active"
+		if u.GetDisabled() {
+			state = "disabled"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", u.GetLogin(), state, u.GetRecords(), u.GetBytes())
+	}
+	return w.Flush()
+}
+
+// userCommand создает команду над учетной записью пользователя с логином args[0].
+// После выполнения выводится сообщение с результатом done.
+func userCommand(done string, call func(ctx context.Context, cl pb.KeeperAdminClient, login string) error) command {
+	return func(ctx context.Context, cl pb.KeeperAdminClient, args []string, out io.Writer) error {
+		if len(args) != 1 || args[0] == "" {
+			return ErrUsage
+		}
+		if err := call(ctx, cl, args[0]); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(out, "user %s %s\n", args[0], done)
+		return err
+	}
+}
+
+func disableUser(ctx context.Context, cl pb.KeeperAdminClient, login string) error {
+	_, err := cl.DisableUser(ctx, &pb.DisableUserRequest{Login: login})
+	return err
+}
+
+func enableUser(ctx context.Context, cl pb.KeeperAdminClient, login string) error {
+	_, err := cl.EnableUser(ctx, &pb.EnableUserRequest{Login: login})
+	return err
+}
+
+func logoutUser(ctx context.Context, cl pb.KeeperAdminClient, login string) error {
+	_, err := cl.LogoutUser(ctx, &pb.LogoutUserRequest{Login: login})
+	return err
+}
+
+func deleteUser(ctx context.Context, cl pb.KeeperAdminClient, login string) error {
+	_, err := cl.DeleteUser(ctx, &pb.DeleteUserRequest{Login: login})
+	return err
+}
+
+func serverStats(ctx context.Context, cl pb.KeeperAdminClient, args []string, out io.Writer) error {
+	if len(args) != 0 {
+		return ErrUsage
+	}
+	res, err := cl.GetServerStats(ctx, &pb.GetServerStatsRequest{})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Users:\t%d\n", res.GetUsers())
+	fmt.Fprintf(w, "Disabled users:\t%d\n", res.GetDisabledUsers())
+	fmt.Fprintf(w, "Records:\t%d\n", res.GetRecords())
+	fmt.Fprintf(w, "Bytes:\t%d\n", res.GetBytes())
+	fmt.Fprintf(w, "Uptime:\t%s\n", time.Duration(res.GetUptimeSeconds())*time.Second)
+	fmt.Fprintf(w, "DB connections:\t%d open, %d in use, %d idle\n",
+		res.GetOpenConnections(), res.GetInUseConnections(), res.GetIdleConnections())
+	return w.Flush()
+}
@@ -0,0 +1,148 @@
+package keeperadmin
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keeperadmin/mocks"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+const testAdminKey = "akey"
+
+// adminKeyMatcher проверяет, что ключ администратора передан в метаданных запроса.
+type adminKeyMatcher struct{}
+
+func (adminKeyMatcher) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+	if !ok {
+		return false
+	}
+	md, ok := metadata.FromOutgoingContext(ctx)
+	return ok && len(md.Get(authorizer.AdminKey)) == 1 && md.Get(authorizer.AdminKey)[0] == testAdminKey
+}
+
+func (adminKeyMatcher) String() string {
+	return "context with admin key " + testAdminKey
+}
+
+func adminCtx() gomock.Matcher {
+	return adminKeyMatcher{}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		prepare func(m *mocks.MockKeeperAdminClient)
+		wantOut []string
+		wantErr error
+	}{
+		{
+			name: "users test",
+			args: []string{"users"},
+			prepare: func(m *mocks.MockKeeperAdminClient) {
+				m.EXPECT().ListUsers(adminCtx(), &pb.ListUsersRequest{}).Return(&pb.ListUsersResponse{
+					Users: []*pb.AdminUser{
+						{Login: "alice", Records: 3, Bytes: 120},
+						{Login: "bob", Disabled: true},
+					},
+				}, nil)
+			},
+			wantOut: []string{"LOGIN  STATUS", "alice  active    3        120", "bob    disabled  0        0"},
+		},
+		{
+			name: "disable test",
+			args: []string{"disable", "alice"},
+			prepare: func(m *mocks.MockKeeperAdminClient) {
+				m.EXPECT().DisableUser(adminCtx(), &pb.DisableUserRequest{Login: "alice"}).Return(&pb.DisableUserResponse{}, nil)
+			},
+			wantOut: []string{"user alice disabled"},
+		},
+		{
+			name: "enable test",
+			args: []string{"enable", "alice"},
+			prepare: func(m *mocks.MockKeeperAdminClient) {
+				m.EXPECT().EnableUser(adminCtx(), &pb.EnableUserRequest{Login: "alice"}).Return(&pb.EnableUserResponse{}, nil)
+			},
+			wantOut: []string{"user alice enabled"},
+		},
+		{
+			name: "logout test",
+			args: []string{"logout", "alice"},
+			prepare: func(m *mocks.MockKeeperAdminClient) {
+				m.EXPECT().LogoutUser(adminCtx(), &pb.LogoutUserRequest{Login: "alice"}).Return(&pb.LogoutUserResponse{}, nil)
+			},
+			wantOut: []string{"user alice logged out"},
+		},
+		{
+			name: "delete error test",
+			args: []string{"delete", "alice"},
+			prepare: func(m *mocks.MockKeeperAdminClient) {
+				m.EXPECT().DeleteUser(adminCtx(), &pb.DeleteUserRequest{Login: "alice"}).
+					Return(nil, status.Error(codes.NotFound, "user not found"))
+			},
+			wantErr: status.Error(codes.NotFound, "user not found"),
+		},
+		{
+			name: "stats test",
+			args: []string{"stats"},
+			prepare: func(m *mocks.MockKeeperAdminClient) {
+				m.EXPECT().GetServerStats(adminCtx(), &pb.GetServerStatsRequest{}).Return(&pb.GetServerStatsResponse{
+					Users: 2, DisabledUsers: 1, Records: 4, Bytes: 150, UptimeSeconds: 90,
+					OpenConnections: 3, InUseConnections: 1, IdleConnections: 2,
+				}, nil)
+			},
+			wantOut: []string{"Users:           2", "Disabled users:  1", "Uptime:          1m30s",
+				"DB connections:  3 open, 1 in use, 2 idle"},
+		},
+		{
+			name:    "missing login test",
+			args:    []string{"delete"},
+			wantErr: ErrUsage,
+		},
+		{
+			name:    "unknown command test",
+			args:    []string{"drop"},
+			wantErr: ErrUsage,
+		},
+		{
+			name:    "empty command test",
+			args:    nil,
+			wantErr: ErrUsage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mocks.NewMockKeeperAdminClient(ctrl)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+			var out bytes.Buffer
+			err := Run(context.Background(), m, testAdminKey, tt.args, &out)
+			if tt.wantErr != nil {
+				if st, ok := status.FromError(tt.wantErr); ok && st.Code() != codes.Unknown {
+					assert.Equal(t, st.Code(), status.Code(err))
+				} else {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			for _, want := range tt.wantOut {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Julia-ivv/info-keeper.git/internal/proto/pb (interfaces: KeeperAdminClient)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	grpc "google.golang.org/grpc"
+
+	proto "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// MockKeeperAdminClient is a mock of KeeperAdminClient interface.
+type MockKeeperAdminClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeeperAdminClientMockRecorder
+}
+
+// MockKeeperAdminClientMockRecorder is the mock recorder for MockKeeperAdminClient.
+type MockKeeperAdminClientMockRecorder struct {
+	mock *MockKeeperAdminClient
+}
+
+// NewMockKeeperAdminClient creates a new mock instance.
+func NewMockKeeperAdminClient(ctrl *gomock.Controller) *MockKeeperAdminClient {
+	mock := &MockKeeperAdminClient{ctrl: ctrl}
+	mock.recorder = &MockKeeperAdminClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeeperAdminClient) EXPECT() *MockKeeperAdminClientMockRecorder {
+	return m.recorder
+}
+
+// DeleteUser mocks base method.
+func (m *MockKeeperAdminClient) DeleteUser(arg0 context.Context, arg1 *proto.DeleteUserRequest, arg2 ...grpc.CallOption) (*proto.DeleteUserResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteUser", varargs...)
+	ret0, _ := ret[0].(*proto.DeleteUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockKeeperAdminClientMockRecorder) DeleteUser(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockKeeperAdminClient)(nil).DeleteUser), varargs...)
+}
+
+// DisableUser mocks base method.
+func (m *MockKeeperAdminClient) DisableUser(arg0 context.Context, arg1 *proto.DisableUserRequest, arg2 ...grpc.CallOption) (*proto.DisableUserResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DisableUser", varargs...)
+	ret0, _ := ret[0].(*proto.DisableUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableUser indicates an expected call of DisableUser.
+func (mr *MockKeeperAdminClientMockRecorder) DisableUser(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUser", reflect.TypeOf((*MockKeeperAdminClient)(nil).DisableUser), varargs...)
+}
+
+// EnableUser mocks base method.
+func (m *MockKeeperAdminClient) EnableUser(arg0 context.Context, arg1 *proto.EnableUserRequest, arg2 ...grpc.CallOption) (*proto.EnableUserResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EnableUser", varargs...)
+	ret0, _ := ret[0].(*proto.EnableUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableUser indicates an expected call of EnableUser.
+func (mr *MockKeeperAdminClientMockRecorder) EnableUser(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUser", reflect.TypeOf((*MockKeeperAdminClient)(nil).EnableUser), varargs...)
+}
+
+// GetServerStats mocks base method.
+func (m *MockKeeperAdminClient) GetServerStats(arg0 context.Context, arg1 *proto.GetServerStatsRequest, arg2 ...grpc.CallOption) (*proto.GetServerStatsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetServerStats", varargs...)
+	ret0, _ := ret[0].(*proto.GetServerStatsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServerStats indicates an expected call of GetServerStats.
+func (mr *MockKeeperAdminClientMockRecorder) GetServerStats(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerStats", reflect.TypeOf((*MockKeeperAdminClient)(nil).GetServerStats), varargs...)
+}
+
+// ListUsers mocks base method.
+func (m *MockKeeperAdminClient) ListUsers(arg0 context.Context, arg1 *proto.ListUsersRequest, arg2 ...grpc.CallOption) (*proto.ListUsersResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListUsers", varargs...)
+	ret0, _ := ret[0].(*proto.ListUsersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockKeeperAdminClientMockRecorder) ListUsers(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockKeeperAdminClient)(nil).ListUsers), varargs...)
+}
+
+// LogoutUser mocks base method.
+func (m *MockKeeperAdminClient) LogoutUser(arg0 context.Context, arg1 *proto.LogoutUserRequest, arg2 ...grpc.CallOption) (*proto.LogoutUserResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LogoutUser", varargs...)
+	ret0, _ := ret[0].(*proto.LogoutUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LogoutUser indicates an expected call of LogoutUser.
+func (mr *MockKeeperAdminClientMockRecorder) LogoutUser(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutUser", reflect.TypeOf((*MockKeeperAdminClient)(nil).LogoutUser), varargs...)
+}
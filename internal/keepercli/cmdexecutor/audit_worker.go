@@ -0,0 +1,97 @@
+package cmdexecutor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// DeviceName - имя устройства, передаваемое серверу для журнала аудита.
+var DeviceName, _ = os.Hostname()
+
+// withDevice добавляет имя устройства в метаданные запроса.
+func withDevice(ctx context.Context) context.Context {
+	if DeviceName == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, authorizer.DeviceName, DeviceName)
+}
+
+// DeviceUnaryInterceptor передает серверу имя устройства в каждом запросе.
+func DeviceUnaryInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(withDevice(ctx), method, req, reply, cc, opts...)
+}
+
+// DeviceStreamInterceptor передает серверу имя устройства в каждом потоковом запросе.
+func DeviceStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(withDevice(ctx), desc, cc, method, opts...)
+}
+
+// AuditEvent хранит событие журнала аудита на сервере.
+type AuditEvent struct {
+	TimeStamp string
+	Action    string
+	Record    string
+	Device    string
+	IP        string
+}
+
+// AuditEvents используется для вывода результата пользователю.
+type AuditEvents []AuditEvent
+
+// PrintData используется для вывода результата пользователю.
+func (a AuditEvents) PrintData() {
+	fmt.Println("AUDIT LOG")
+	for _, v := range a {
+		fmt.Println("Time Stamp: ", v.TimeStamp)
+		fmt.Println("Action: ", v.Action)
+		fmt.Println("Record: ", v.Record)
+		fmt.Println("Device: ", v.Device)
+		fmt.Println("IP: ", v.IP)
+	}
+}
+
+// recordKeyText возвращает описание записи с ключом key с расшифрованными ключевыми полями.
+func recordKeyText(key *pb.RecordKey) string {
+	text := recordTypeNames[key.GetType()] + " "
+	switch key.GetType() {
+	case pb.RecordType_RECORD_TYPE_CARD:
+		return text + decryptOrMark(key.GetKey())
+	case pb.RecordType_RECORD_TYPE_LOGIN_PWD, pb.RecordType_RECORD_TYPE_OTP:
+		return text + decryptOrMark(key.GetPrompt()) + " / " + decryptOrMark(key.GetKey())
+	default:
+		return text + decryptOrMark(key.GetPrompt())
+	}
+}
+
+var auditExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	r, err := cl.GetAuditLog(ctxMd, &pb.GetAuditLogRequest{From: args.Since, To: args.Until})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(AuditEvents, 0, len(r.GetEvents()))
+	for _, v := range r.GetEvents() {
+		res = append(res, AuditEvent{
+			TimeStamp: v.GetTimeStamp(),
+			Action:    v.GetAction(),
+			Record:    recordKeyText(v.GetKey()),
+			Device:    v.GetDevice(),
+			IP:        v.GetIp(),
+		})
+	}
+
+	return res, nil
+}
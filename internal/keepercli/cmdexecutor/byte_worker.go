@@ -1,9 +1,14 @@
 package cmdexecutor
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc/metadata"
@@ -11,16 +16,22 @@ import (
 	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/labels"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
 	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
 )
 
+// binaryChunkSize - размер части бинарных данных при передаче на сервер.
+const binaryChunkSize = 1 << 20
+
 // UserBinaryRecord хранит бинарные данные.
 type UserBinaryRecord struct {
 	Prompt    string
 	Data      []byte
 	File      string
 	Note      string
+	Tags      []string
+	Folder    string
 	TimeStamp string
 }
 
@@ -34,11 +45,13 @@ func (b BinaryRecords) PrintData() {
 		fmt.Println("Prompt: ", v.Prompt)
 		fmt.Println("File: ", v.File)
 		fmt.Println("Note: ", v.Note)
+		fmt.Println("Tags: ", strings.Join(v.Tags, ", "))
+		fmt.Println("Folder: ", v.Folder)
 		fmt.Println("Time Stamp: ", v.TimeStamp)
 	}
 }
 
-var addBinaryExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var addBinaryExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	file, err := os.Open(args.Binary)
 	if err != nil {
 		return nil, err
@@ -60,7 +73,7 @@ var addBinaryExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo s
 		return nil, err
 	}
 
-	err = repo.AddBinaryRecord(context.Background(), UserLogin, enA.Prompt, enData, enA.Note, time.Now().Format(time.RFC3339))
+	err = repo.AddBinaryRecord(ctx, UserLogin, enA.Prompt, enData, enA.Note, time.Now().Format(time.RFC3339))
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +81,7 @@ var addBinaryExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo s
 	return nil, nil
 }
 
-var updBinaryExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var updBinaryExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	file, err := os.Open(args.Binary)
 	if err != nil {
 		return nil, err
@@ -90,7 +103,7 @@ var updBinaryExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo s
 		return nil, err
 	}
 
-	err = repo.UpdateBinaryRecord(context.Background(), UserLogin, enA.Prompt, enData, enA.Note, time.Now().Format(time.RFC3339))
+	err = repo.UpdateBinaryRecord(ctx, UserLogin, enA.Prompt, enData, enA.Note, time.Now().Format(time.RFC3339))
 	if err != nil {
 		return nil, err
 	}
@@ -98,12 +111,12 @@ var updBinaryExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo s
 	return nil, nil
 }
 
-var getBinaryExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var getBinaryExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enP, err := cryptor.EncryptsString(args.Prompt)
 	if err != nil {
 		return nil, err
 	}
-	b, err := repo.GetBinaryRecord(context.Background(), UserLogin, enP)
+	b, err := repo.GetBinaryRecord(ctx, UserLogin, enP)
 	if err != nil {
 		return nil, err
 	}
@@ -126,18 +139,22 @@ var getBinaryExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo s
 	return res, nil
 }
 
-var getBinarysExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
-	bs, err := repo.GetUserBinaryRecordsAfterTime(context.Background(), UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
+var getBinarysExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	bs, err := repo.GetUserBinaryRecordsAfterTime(ctx, UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
 	if err != nil {
 		return nil, err
 	}
 
+	filter := labels.Filter{Tags: args.Tags, Folder: args.Folder}
 	res := make(BinaryRecords, 0, len(bs))
 	for _, v := range bs {
 		b, err := decryptBinaryRecord(v)
 		if err != nil {
 			return nil, err
 		}
+		if !filter.Match(b.Tags, b.Folder) {
+			continue
+		}
 		err = os.WriteFile(args.Prompt, b.Data, 0666)
 		if err != nil {
 			return nil, err
@@ -150,20 +167,24 @@ var getBinarysExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo
 	return res, nil
 }
 
-var forceAddBinaryServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var forceAddBinaryServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enP, err := cryptor.EncryptsString(args.Prompt)
 	if err != nil {
 		return nil, err
 	}
-	b, err := repo.GetBinaryRecord(context.Background(), UserLogin, enP)
+	b, err := repo.GetBinaryRecord(ctx, UserLogin, enP)
 	if err != nil {
 		return nil, err
 	}
 
-	bPb := binaryToPb(b)
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
-	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
-	_, err = cl.ForceUpdateBinaryRecord(ctxMd, &pb.ForceUpdateBinaryRecordRequest{BinaryRecord: bPb})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	rev, err := uploadBinary(ctxMd, cl, b, true)
+	if err != nil {
+		return nil, err
+	}
+	err = saveRevision(ctx, repo,
+		&pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: b.Prompt}, rev)
 	if err != nil {
 		return nil, err
 	}
@@ -171,20 +192,19 @@ var forceAddBinaryServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperCli
 	return nil, nil
 }
 
-var getBinaryServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var getBinaryServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enP, err := cryptor.EncryptsString(args.Prompt)
 	if err != nil {
 		return nil, err
 	}
 
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
-	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
-	r, err := cl.GetUserBinary(ctxMd, &pb.GetUserBinaryRequest{Prompt: enP})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	b, err := downloadBinary(ctxMd, cl, enP)
 	if err != nil {
 		return nil, err
 	}
 
-	b := pbToBinary(r.BinaryRecord)
 	deB, err := decryptBinaryRecord(b)
 	if err != nil {
 		return nil, err
@@ -203,8 +223,110 @@ var getBinaryServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient,
 		Data:      deB.Data,
 		File:      deB.File,
 		Note:      deB.Note,
+		Tags:      deB.Tags,
+		Folder:    deB.Folder,
 		TimeStamp: deB.TimeStamp,
 	})
 
 	return res, nil
 }
+
+// uploadBinary передает бинарные данные на сервер частями и возвращает ревизию сохраненной записи.
+func uploadBinary(ctx context.Context, cl pb.InfoKeeperClient, b storage.BinaryRecord, force bool) (*pb.RecordRevision, error) {
+	stream, err := cl.UploadBinary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.Send(&pb.UploadBinaryRequest{
+		Part: &pb.UploadBinaryRequest_Info_{
+			Info: &pb.UploadBinaryRequest_Info{
+				BinaryRecord: &pb.UserBinaryRecord{
+					Prompt:    b.Prompt,
+					Note:      b.Note,
+					Tags:      b.Tags,
+					Folder:    b.Folder,
+					TimeStamp: b.TimeStamp,
+				},
+				Size:  int64(len(b.Data)),
+				Force: force,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(b.Data); start += binaryChunkSize {
+		end := start + binaryChunkSize
+		if end > len(b.Data) {
+			end = len(b.Data)
+		}
+		err = stream.Send(&pb.UploadBinaryRequest{
+			Part: &pb.UploadBinaryRequest_Chunk{Chunk: b.Data[start:end]},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sum := sha256.Sum256(b.Data)
+	err = stream.Send(&pb.UploadBinaryRequest{
+		Part: &pb.UploadBinaryRequest_Checksum{Checksum: sum[:]},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetRevision(), nil
+}
+
+// downloadBinary получает бинарные данные с сервера частями.
+func downloadBinary(ctx context.Context, cl pb.InfoKeeperClient, prompt []byte) (storage.BinaryRecord, error) {
+	stream, err := cl.DownloadBinary(ctx, &pb.DownloadBinaryRequest{Prompt: prompt})
+	if err != nil {
+		return storage.BinaryRecord{}, err
+	}
+
+	var info *pb.UserBinaryRecord
+	var data, checksum []byte
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return storage.BinaryRecord{}, err
+		}
+
+		switch part := resp.GetPart().(type) {
+		case *pb.DownloadBinaryResponse_Info:
+			info = part.Info
+		case *pb.DownloadBinaryResponse_Chunk:
+			data = append(data, part.Chunk...)
+		case *pb.DownloadBinaryResponse_Checksum:
+			checksum = part.Checksum
+		}
+	}
+
+	if info == nil {
+		return storage.BinaryRecord{}, errors.New("missing binary record info")
+	}
+	sum := sha256.Sum256(data)
+	if !bytes.Equal(sum[:], checksum) {
+		return storage.BinaryRecord{}, errors.New("binary data checksum mismatch")
+	}
+
+	return storage.BinaryRecord{
+		Prompt:    info.GetPrompt(),
+		Data:      data,
+		Note:      info.GetNote(),
+		Tags:      info.GetTags(),
+		Folder:    info.GetFolder(),
+		TimeStamp: info.GetTimeStamp(),
+	}, nil
+}
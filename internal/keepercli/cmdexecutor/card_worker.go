@@ -3,6 +3,7 @@ package cmdexecutor
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc/metadata"
@@ -10,6 +11,7 @@ import (
 	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/labels"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
 	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
 )
@@ -21,6 +23,8 @@ type UserCard struct {
 	Date      string
 	Code      string
 	Note      string
+	Tags      []string
+	Folder    string
 	TimeStamp string
 }
 
@@ -36,17 +40,19 @@ func (c Cards) PrintData() {
 		fmt.Println("Date: ", v.Date)
 		fmt.Println("Code: ", v.Code)
 		fmt.Println("Note: ", v.Note)
+		fmt.Println("Tags: ", strings.Join(v.Tags, ", "))
+		fmt.Println("Folder: ", v.Folder)
 		fmt.Println("Time Stamp: ", v.TimeStamp)
 	}
 }
 
-var addCardExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var addCardExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enA, err := encryptArgs(args)
 	if err != nil {
 		return nil, err
 	}
 
-	err = repo.AddCard(context.Background(), UserLogin, enA.Prompt, enA.CardNumber, enA.CardDate, enA.CardCode,
+	err = repo.AddCard(ctx, UserLogin, enA.Prompt, enA.CardNumber, enA.CardDate, enA.CardCode,
 		enA.Note, time.Now().Format(time.RFC3339))
 	if err != nil {
 		return nil, err
@@ -55,13 +61,13 @@ var addCardExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo sto
 	return nil, nil
 }
 
-var updCardExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var updCardExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enA, err := encryptArgs(args)
 	if err != nil {
 		return nil, err
 	}
 
-	err = repo.UpdateCard(context.Background(), UserLogin, enA.Prompt, enA.CardNumber, enA.CardDate, enA.CardCode,
+	err = repo.UpdateCard(ctx, UserLogin, enA.Prompt, enA.CardNumber, enA.CardDate, enA.CardCode,
 		enA.Note, time.Now().Format(time.RFC3339))
 	if err != nil {
 		return nil, err
@@ -70,13 +76,13 @@ var updCardExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo sto
 	return nil, nil
 }
 
-var getCardExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var getCardExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enN, err := cryptor.EncryptsString(args.CardNumber)
 	if err != nil {
 		return nil, err
 	}
 
-	c, err := repo.GetCard(context.Background(), UserLogin, enN)
+	c, err := repo.GetCard(ctx, UserLogin, enN)
 	if err != nil {
 		return nil, err
 	}
@@ -92,39 +98,51 @@ var getCardExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo sto
 	return res, nil
 }
 
-var getCardsExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
-	cs, err := repo.GetUserCardsAfterTime(context.Background(), UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
+var getCardsExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	cs, err := repo.GetUserCardsAfterTime(ctx, UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
 	if err != nil {
 		return nil, err
 	}
 
+	filter := labels.Filter{Tags: args.Tags, Folder: args.Folder}
 	res := make(Cards, 0, len(cs))
 	for _, v := range cs {
 		c, err := decryptCard(v)
 		if err != nil {
 			return nil, err
 		}
+		if !filter.Match(c.Tags, c.Folder) {
+			continue
+		}
 		res = append(res, c)
 	}
 
 	return res, nil
 }
 
-var forceAddCardServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var forceAddCardServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enN, err := cryptor.EncryptsString(args.CardNumber)
 	if err != nil {
 		return nil, err
 	}
 
-	c, err := repo.GetCard(context.Background(), UserLogin, enN)
+	c, err := repo.GetCard(ctx, UserLogin, enN)
 	if err != nil {
 		return nil, err
 	}
 
 	cPb := cardToPb(c)
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
-	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
-	_, err = cl.ForceUpdateCard(ctxMd, &pb.ForceUpdateCardRequest{Card: cPb})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	resp, err := cl.ForceUpdateRecord(ctxMd, &pb.ForceUpdateRecordRequest{Record: &pb.Record{
+		Type:    pb.RecordType_RECORD_TYPE_CARD,
+		Payload: &pb.Record_Card{Card: cPb},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	err = saveRevision(ctx, repo,
+		&pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: c.Number}, resp.GetRevision())
 	if err != nil {
 		return nil, err
 	}
@@ -132,19 +150,22 @@ var forceAddCardServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClien
 	return nil, nil
 }
 
-var getCardServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var getCardServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enN, err := cryptor.EncryptsString(args.CardNumber)
 	if err != nil {
 		return nil, err
 	}
 
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
-	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
-	r, err := cl.GetUserCard(ctxMd, &pb.GetUserCardRequest{Number: enN})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	r, err := cl.GetRecord(ctxMd, &pb.GetRecordRequest{Key: &pb.RecordKey{
+		Type: pb.RecordType_RECORD_TYPE_CARD,
+		Key:  enN,
+	}})
 	if err != nil {
 		return nil, err
 	}
-	c := pbToCard(r.Card)
+	c := pbToCard(r.GetRecord().GetCard())
 	deC, err := decryptCard(c)
 	if err != nil {
 		return nil, err
@@ -157,6 +178,8 @@ var getCardServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, re
 		Date:      deC.Date,
 		Code:      deC.Code,
 		Note:      deC.Note,
+		Tags:      deC.Tags,
+		Folder:    deC.Folder,
 		TimeStamp: deC.TimeStamp,
 	})
 
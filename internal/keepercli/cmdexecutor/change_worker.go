@@ -0,0 +1,57 @@
+package cmdexecutor
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// WatchChanges подписывается на уведомления сервера об изменении записей пользователя
+// и сохраняет измененные записи в локальную БД до отмены контекста.
+func WatchChanges(ctx context.Context, cl pb.InfoKeeperClient, repo storage.Repositorier,
+	userLogin string, userToken string) error {
+	md := metadata.New(map[string]string{authorizer.AccessToken: userToken})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	stream, err := cl.WatchChanges(ctxMd, &pb.WatchChangesRequest{})
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		r, err := pullRecord(ctxMd, cl, event.GetKey())
+		if err != nil {
+			return err
+		}
+		err = repo.SaveServerRecord(ctx, userLogin, r)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// pullRecord получает с сервера запись с указанным ключом.
+func pullRecord(ctx context.Context, cl pb.InfoKeeperClient, key *pb.RecordKey) (any, error) {
+	if key.GetType() == pb.RecordType_RECORD_TYPE_BINARY {
+		return downloadBinary(ctx, cl, key.GetPrompt())
+	}
+
+	r, err := cl.GetRecord(ctx, &pb.GetRecordRequest{Key: key})
+	if err != nil {
+		return nil, err
+	}
+	return pbToRecord(r.GetRecord())
+}
@@ -1,7 +1,9 @@
 package cmdexecutor
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
@@ -12,7 +14,38 @@ type DataPrinter interface {
 	PrintData()
 }
 
-var cmds = make(map[string]func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error))
+// Deadlines задает сроки выполнения команд клиента.
+type Deadlines struct {
+	// Request срок выполнения обычной команды.
+	Request time.Duration
+	// Sync срок выполнения команд с синхронизацией, вводом пароля и передачей бинарных данных.
+	Sync time.Duration
+}
+
+// CmdDeadlines сроки выполнения команд, задаются при запуске клиента.
+var CmdDeadlines = Deadlines{Request: 30 * time.Second, Sync: 10 * time.Minute}
+
+// longCmds команды, выполняемые со сроком Deadlines.Sync.
+var longCmds = map[string]bool{
+	cmdparser.CmdReg:                  true,
+	cmdparser.CmdAuth:                 true,
+	cmdparser.CmdExit:                 true,
+	cmdparser.CmdForceAddBinaryServer: true,
+	cmdparser.CmdGetBinaryServer:      true,
+	cmdparser.CmdGetBinarysServer:     true,
+	cmdparser.CmdRestore:              true,
+	cmdparser.CmdResolve:              true,
+}
+
+// commandDeadline возвращает срок выполнения команды пользователя.
+func commandDeadline(userCmd string) time.Duration {
+	if longCmds[userCmd] {
+		return CmdDeadlines.Sync
+	}
+	return CmdDeadlines.Request
+}
+
+var cmds = make(map[string]func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error))
 
 func init() {
 	cmds[cmdparser.CmdReg] = regExec
@@ -49,15 +82,62 @@ func init() {
 	cmds[cmdparser.CmdGetLoginServer] = getLoginServerExec
 	cmds[cmdparser.CmdGetTextServer] = getTextServerExec
 	cmds[cmdparser.CmdGetBinaryServer] = getBinaryServerExec
+
+	cmds[cmdparser.CmdGetCardsServer] = getCardsServerExec
+	cmds[cmdparser.CmdGetLoginsServer] = getLoginsServerExec
+	cmds[cmdparser.CmdGetTextsServer] = getTextsServerExec
+	cmds[cmdparser.CmdGetBinarysServer] = getBinarysServerExec
+
+	cmds[cmdparser.CmdAddOtp] = addOtpExec
+	cmds[cmdparser.CmdGetOtp] = getOtpExec
+
+	cmds[cmdparser.CmdAddSshKey] = addSshKeyExec
+	cmds[cmdparser.CmdGetSshKey] = getSshKeyExec
+
+	cmds[cmdparser.CmdAddTemplate] = addTemplateExec
+	cmds[cmdparser.CmdGetTemplates] = getTemplatesExec
+	cmds[cmdparser.CmdAddCustom] = addCustomExec
+	cmds[cmdparser.CmdGetCustom] = getCustomExec
+	cmds[cmdparser.CmdGetCustoms] = getCustomsExec
+
+	cmds[cmdparser.CmdSetTags] = setTagsExec
+	cmds[cmdparser.CmdMove] = moveExec
+
+	cmds[cmdparser.CmdHistory] = historyExec
+	cmds[cmdparser.CmdRestore] = restoreExec
+	cmds[cmdparser.CmdResolve] = resolveExec
+
+	cmds[cmdparser.CmdAudit] = auditExec
+	cmds[cmdparser.CmdUsage] = usageExec
+
+	cmds[cmdparser.CmdInitKeys] = initKeysExec
+	cmds[cmdparser.CmdAddOrg] = addOrgExec
+	cmds[cmdparser.CmdAddCollection] = addCollectionExec
+	cmds[cmdparser.CmdGetCollections] = getCollectionsExec
+	cmds[cmdparser.CmdInvite] = inviteExec
+	cmds[cmdparser.CmdToCollection] = toCollectionExec
+	cmds[cmdparser.CmdGetShared] = getSharedExec
+
+	cmds[cmdparser.CmdAddEmergency] = addEmergencyExec
+	cmds[cmdparser.CmdRemoveEmergency] = removeEmergencyExec
+	cmds[cmdparser.CmdDeclineEmergency] = declineEmergencyExec
+	cmds[cmdparser.CmdGetEmergency] = getEmergencyExec
+	cmds[cmdparser.CmdRequestEmergency] = requestEmergencyExec
+	cmds[cmdparser.CmdEmergencyKey] = emergencyKeyExec
 }
 
 // ExecuteCmd выполняет поиск и вызов функции по команде пользователя.
-func ExecuteCmd(userCmd string, userArgs cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+// Команда выполняется со сроком, зависящим от ее вида, см. CmdDeadlines.
+func ExecuteCmd(ctx context.Context, userCmd string, userArgs cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	fn := cmds[userCmd]
 	if fn == nil {
 		return nil, errors.New("command function not found")
 	}
-	res, err := fn(userArgs, cl, repo)
+
+	ctx, cancel := context.WithTimeout(ctx, commandDeadline(userCmd))
+	defer cancel()
+
+	res, err := fn(ctx, userArgs, cl, repo)
 	if err != nil {
 		return nil, err
 	}
@@ -2,15 +2,28 @@ package cmdexecutor
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/customrecord"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/mocks"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
 	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
@@ -44,6 +57,18 @@ var (
 		Note:      ttArgs.Note,
 		TimeStamp: testTime,
 	}}
+	testUserRecordInfo = RecordInfo{
+		Type:      "binary",
+		Prompt:    ttArgs.Prompt,
+		TimeStamp: testTime,
+		Size:      int64(len(testBinaryRecord.Data)),
+	}
+	testPbRecordInfo = &pb.RecordInfo{
+		Type:      pb.RecordType_RECORD_TYPE_BINARY,
+		Prompt:    testBinaryRecord.Prompt,
+		TimeStamp: testTime,
+		Size:      int64(len(testBinaryRecord.Data)),
+	}
 	nameTestFile = "test_file"
 	ttArgs       = cmdparser.UserArgs{
 		Prompt:     "prompt",
@@ -56,12 +81,14 @@ var (
 		Text:       "text",
 		Binary:     nameTestFile,
 	}
-	testSyncTime = "2023-01-02T15:04:05Z"
+	testSyncTime     = "2023-01-02T15:04:05Z"
+	testSyncRevision = int64(7)
 )
 
 func TestExecuteCmd(t *testing.T) {
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
-	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
+	cmdCtx := deadlineMatcher{}
+	ctxMd := deadlineMatcher{md: md}
 
 	tests := []struct {
 		name    string
@@ -75,7 +102,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok add card test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().AddCard(context.Background(), "", testCard.Prompt, testCard.Number,
+				m.EXPECT().AddCard(cmdCtx, "", testCard.Prompt, testCard.Number,
 					testCard.Date, testCard.Code, testCard.Note, gomock.Any()).Return(nil)
 			},
 			userCmd: cmdparser.CmdAddCard,
@@ -85,7 +112,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok upd card test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().UpdateCard(context.Background(), "", testCard.Prompt, testCard.Number,
+				m.EXPECT().UpdateCard(cmdCtx, "", testCard.Prompt, testCard.Number,
 					testCard.Date, testCard.Code, testCard.Note, gomock.Any()).Return(nil)
 			},
 			userCmd: cmdparser.CmdUpdCard,
@@ -95,7 +122,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get card test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetCard(context.Background(), "", testCard.Number).Return(testCard, nil)
+				m.EXPECT().GetCard(cmdCtx, "", testCard.Number).Return(testCard, nil)
 			},
 			userCmd: cmdparser.CmdGetCard,
 			args:    ttArgs,
@@ -106,7 +133,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get cards test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetUserCardsAfterTime(context.Background(), "", time.Now().AddDate(-100, 0, 0).Format(time.RFC3339)).
+				m.EXPECT().GetUserCardsAfterTime(cmdCtx, "", time.Now().AddDate(-100, 0, 0).Format(time.RFC3339)).
 					Return([]storage.Card{testCard}, nil)
 			},
 			userCmd: cmdparser.CmdGetCards,
@@ -118,21 +145,50 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok force add card test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetCard(context.Background(), "", testCard.Number).
+				m.EXPECT().GetCard(cmdCtx, "", testCard.Number).
 					Return(testCard, nil)
-				mcli.EXPECT().ForceUpdateCard(ctxMd, &pb.ForceUpdateCardRequest{Card: cardToPb(testCard)}).
-					Return(nil, nil)
+				mcli.EXPECT().ForceUpdateRecord(ctxMd, &pb.ForceUpdateRecordRequest{Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_CARD,
+					Payload: &pb.Record_Card{Card: cardToPb(testCard)},
+				}}).Return(&pb.ForceUpdateRecordResponse{Revision: &pb.RecordRevision{
+					RecordId: 7, Revision: 2, TimeStamp: testCard.TimeStamp,
+				}}, nil)
+				m.EXPECT().SaveRevision(cmdCtx, "", storage.Revision{
+					Type:      int32(pb.RecordType_RECORD_TYPE_CARD),
+					Key:       testCard.Number,
+					ID:        7,
+					Revision:  2,
+					TimeStamp: testCard.TimeStamp,
+				}).Return(nil)
 			},
 			userCmd: cmdparser.CmdForceAddCardServer,
 			args:    ttArgs,
 			wantErr: false,
 			wantRes: false,
 		},
+		{
+			name: "error save revision test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				m.EXPECT().GetCard(cmdCtx, "", testCard.Number).
+					Return(testCard, nil)
+				mcli.EXPECT().ForceUpdateRecord(ctxMd, gomock.Any()).
+					Return(&pb.ForceUpdateRecordResponse{Revision: &pb.RecordRevision{RecordId: 7, Revision: 2}}, nil)
+				m.EXPECT().SaveRevision(cmdCtx, "", gomock.Any()).Return(errors.New("error"))
+			},
+			userCmd: cmdparser.CmdForceAddCardServer,
+			args:    ttArgs,
+			wantErr: true,
+		},
 		{
 			name: "ok get server card test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				mcli.EXPECT().GetUserCard(ctxMd, &pb.GetUserCardRequest{Number: testCard.Number}).
-					Return(&pb.GetUserCardResponse{Card: cardToPb(testCard)}, nil)
+				mcli.EXPECT().GetRecord(ctxMd, &pb.GetRecordRequest{Key: &pb.RecordKey{
+					Type: pb.RecordType_RECORD_TYPE_CARD,
+					Key:  testCard.Number,
+				}}).Return(&pb.GetRecordResponse{Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_CARD,
+					Payload: &pb.Record_Card{Card: cardToPb(testCard)},
+				}}, nil)
 			},
 			userCmd: cmdparser.CmdGetCardServer,
 			args:    ttArgs,
@@ -144,7 +200,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get login test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetLoginPwd(context.Background(), "", testLoginPwd.Prompt, testLoginPwd.Login).
+				m.EXPECT().GetLoginPwd(cmdCtx, "", testLoginPwd.Prompt, testLoginPwd.Login).
 					Return(testLoginPwd, nil)
 			},
 			userCmd: cmdparser.CmdGetLogin,
@@ -156,7 +212,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get logins test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetUserLoginsPwdsAfterTime(context.Background(), "", time.Now().AddDate(-100, 0, 0).Format(time.RFC3339)).
+				m.EXPECT().GetUserLoginsPwdsAfterTime(cmdCtx, "", time.Now().AddDate(-100, 0, 0).Format(time.RFC3339)).
 					Return([]storage.LoginPwd{testLoginPwd}, nil)
 			},
 			userCmd: cmdparser.CmdGetLogins,
@@ -168,10 +224,12 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok force add login test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetLoginPwd(context.Background(), "", testLoginPwd.Prompt, testLoginPwd.Login).
+				m.EXPECT().GetLoginPwd(cmdCtx, "", testLoginPwd.Prompt, testLoginPwd.Login).
 					Return(testLoginPwd, nil)
-				mcli.EXPECT().ForceUpdateLoginPwd(ctxMd, &pb.ForceUpdateLoginPwdRequest{LoginPwd: loginToPb(testLoginPwd)}).
-					Return(nil, nil)
+				mcli.EXPECT().ForceUpdateRecord(ctxMd, &pb.ForceUpdateRecordRequest{Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+					Payload: &pb.Record_LoginPwd{LoginPwd: loginToPb(testLoginPwd)},
+				}}).Return(nil, nil)
 			},
 			userCmd: cmdparser.CmdForceAddLoginServer,
 			args:    ttArgs,
@@ -181,8 +239,14 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get server login test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				mcli.EXPECT().GetUserLogin(ctxMd, &pb.GetUserLoginRequest{Prompt: testLoginPwd.Prompt, Login: testLoginPwd.Login}).
-					Return(&pb.GetUserLoginResponse{LoginPwd: loginToPb(testLoginPwd)}, nil)
+				mcli.EXPECT().GetRecord(ctxMd, &pb.GetRecordRequest{Key: &pb.RecordKey{
+					Type:   pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+					Prompt: testLoginPwd.Prompt,
+					Key:    testLoginPwd.Login,
+				}}).Return(&pb.GetRecordResponse{Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+					Payload: &pb.Record_LoginPwd{LoginPwd: loginToPb(testLoginPwd)},
+				}}, nil)
 			},
 			userCmd: cmdparser.CmdGetLoginServer,
 			args:    ttArgs,
@@ -194,7 +258,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok add text test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().AddTextRecord(context.Background(), "", testTextRecord.Prompt, testTextRecord.Data,
+				m.EXPECT().AddTextRecord(cmdCtx, "", testTextRecord.Prompt, testTextRecord.Data,
 					testTextRecord.Note, gomock.Any()).Return(nil)
 			},
 			userCmd: cmdparser.CmdAddText,
@@ -204,7 +268,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok upd text test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().UpdateTextRecord(context.Background(), "", testTextRecord.Prompt, testTextRecord.Data,
+				m.EXPECT().UpdateTextRecord(cmdCtx, "", testTextRecord.Prompt, testTextRecord.Data,
 					testTextRecord.Note, gomock.Any()).Return(nil)
 			},
 			userCmd: cmdparser.CmdUpdText,
@@ -214,7 +278,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get text test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetTextRecord(context.Background(), "", testTextRecord.Prompt).Return(testTextRecord, nil)
+				m.EXPECT().GetTextRecord(cmdCtx, "", testTextRecord.Prompt).Return(testTextRecord, nil)
 			},
 			userCmd: cmdparser.CmdGetText,
 			args:    ttArgs,
@@ -225,7 +289,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get texts test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetUserTextRecordsAfterTime(context.Background(), "", time.Now().AddDate(-100, 0, 0).Format(time.RFC3339)).
+				m.EXPECT().GetUserTextRecordsAfterTime(cmdCtx, "", time.Now().AddDate(-100, 0, 0).Format(time.RFC3339)).
 					Return([]storage.TextRecord{testTextRecord}, nil)
 			},
 			userCmd: cmdparser.CmdGetTexts,
@@ -237,10 +301,12 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok force add text test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetTextRecord(context.Background(), "", testTextRecord.Prompt).
+				m.EXPECT().GetTextRecord(cmdCtx, "", testTextRecord.Prompt).
 					Return(testTextRecord, nil)
-				mcli.EXPECT().ForceUpdateTextRecord(ctxMd, &pb.ForceUpdateTextRecordRequest{TextRecord: textToPb(testTextRecord)}).
-					Return(nil, nil)
+				mcli.EXPECT().ForceUpdateRecord(ctxMd, &pb.ForceUpdateRecordRequest{Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_TEXT,
+					Payload: &pb.Record_TextRecord{TextRecord: textToPb(testTextRecord)},
+				}}).Return(nil, nil)
 			},
 			userCmd: cmdparser.CmdForceAddTextServer,
 			args:    ttArgs,
@@ -250,8 +316,13 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get server text test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				mcli.EXPECT().GetUserText(ctxMd, &pb.GetUserTextRequest{Prompt: testTextRecord.Prompt}).
-					Return(&pb.GetUserTextResponse{TextRecord: textToPb(testTextRecord)}, nil)
+				mcli.EXPECT().GetRecord(ctxMd, &pb.GetRecordRequest{Key: &pb.RecordKey{
+					Type:   pb.RecordType_RECORD_TYPE_TEXT,
+					Prompt: testTextRecord.Prompt,
+				}}).Return(&pb.GetRecordResponse{Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_TEXT,
+					Payload: &pb.Record_TextRecord{TextRecord: textToPb(testTextRecord)},
+				}}, nil)
 			},
 			userCmd: cmdparser.CmdGetTextServer,
 			args:    ttArgs,
@@ -263,7 +334,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok add bytes test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().AddBinaryRecord(context.Background(), "", testBinaryRecord.Prompt,
+				m.EXPECT().AddBinaryRecord(cmdCtx, "", testBinaryRecord.Prompt,
 					[]byte{2, 68, 255, 117, 104, 167, 77, 151, 89, 98, 94, 149, 234, 119, 65, 219, 240, 237, 251, 88, 23, 159, 46, 250, 216},
 					testBinaryRecord.Note, gomock.Any()).Return(nil)
 			},
@@ -274,7 +345,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok upd bytes test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().UpdateBinaryRecord(context.Background(), "", testBinaryRecord.Prompt,
+				m.EXPECT().UpdateBinaryRecord(cmdCtx, "", testBinaryRecord.Prompt,
 					[]byte{2, 68, 255, 117, 104, 167, 77, 151, 89, 98, 94, 149, 234, 119, 65, 219, 240, 237, 251, 88, 23, 159, 46, 250, 216},
 					testBinaryRecord.Note, gomock.Any()).Return(nil)
 			},
@@ -285,7 +356,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get bytes test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetBinaryRecord(context.Background(), "", testBinaryRecord.Prompt).Return(testBinaryRecord, nil)
+				m.EXPECT().GetBinaryRecord(cmdCtx, "", testBinaryRecord.Prompt).Return(testBinaryRecord, nil)
 			},
 			userCmd: cmdparser.CmdGetBinary,
 			args:    ttArgs,
@@ -296,7 +367,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get all bytes test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetUserBinaryRecordsAfterTime(context.Background(), "", time.Now().AddDate(-100, 0, 0).Format(time.RFC3339)).
+				m.EXPECT().GetUserBinaryRecordsAfterTime(cmdCtx, "", time.Now().AddDate(-100, 0, 0).Format(time.RFC3339)).
 					Return([]storage.BinaryRecord{testBinaryRecord}, nil)
 			},
 			userCmd: cmdparser.CmdGetBinarys,
@@ -308,10 +379,9 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok force add bytes test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				m.EXPECT().GetBinaryRecord(context.Background(), "", testBinaryRecord.Prompt).
+				m.EXPECT().GetBinaryRecord(cmdCtx, "", testBinaryRecord.Prompt).
 					Return(testBinaryRecord, nil)
-				mcli.EXPECT().ForceUpdateBinaryRecord(ctxMd, &pb.ForceUpdateBinaryRecordRequest{BinaryRecord: binaryToPb(testBinaryRecord)}).
-					Return(nil, nil)
+				expectUpload(t, mcli, ctxMd, testBinaryRecord, true, nil)
 			},
 			userCmd: cmdparser.CmdForceAddBinaryServer,
 			args:    ttArgs,
@@ -321,8 +391,7 @@ func TestExecuteCmd(t *testing.T) {
 		{
 			name: "ok get server bytes test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
-				mcli.EXPECT().GetUserBinary(ctxMd, &pb.GetUserBinaryRequest{Prompt: testBinaryRecord.Prompt}).
-					Return(&pb.GetUserBinaryResponse{BinaryRecord: binaryToPb(testBinaryRecord)}, nil)
+				expectDownload(t, mcli, ctxMd, testBinaryRecord)
 			},
 			userCmd: cmdparser.CmdGetBinaryServer,
 			args:    ttArgs,
@@ -330,6 +399,480 @@ func TestExecuteCmd(t *testing.T) {
 			wantRes: true,
 			res:     testUserBinarys,
 		},
+		{
+			name: "ok list server bytes test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				gomock.InOrder(
+					mcli.EXPECT().ListRecords(ctxMd, &pb.ListRecordsRequest{
+						Type:          pb.RecordType_RECORD_TYPE_BINARY,
+						ModifiedSince: testSyncTime,
+					}).Return(&pb.ListRecordsResponse{
+						Records:       []*pb.RecordInfo{testPbRecordInfo},
+						NextPageToken: "next",
+					}, nil),
+					mcli.EXPECT().ListRecords(ctxMd, &pb.ListRecordsRequest{
+						Type:          pb.RecordType_RECORD_TYPE_BINARY,
+						ModifiedSince: testSyncTime,
+						PageToken:     "next",
+					}).Return(&pb.ListRecordsResponse{
+						Records: []*pb.RecordInfo{testPbRecordInfo},
+					}, nil),
+				)
+			},
+			userCmd: cmdparser.CmdGetBinarysServer,
+			args:    cmdparser.UserArgs{Since: testSyncTime},
+			wantErr: false,
+			wantRes: true,
+			res:     RecordInfos{testUserRecordInfo, testUserRecordInfo},
+		},
+		{
+			name: "error list server cards test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().ListRecords(ctxMd, &pb.ListRecordsRequest{Type: pb.RecordType_RECORD_TYPE_CARD}).
+					Return(nil, errors.New("error"))
+			},
+			userCmd: cmdparser.CmdGetCardsServer,
+			args:    cmdparser.UserArgs{},
+			wantErr: true,
+		},
+		{
+			name: "ok add otp test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				m.EXPECT().AddOtp(cmdCtx, "", gomock.Any()).Return(nil)
+			},
+			userCmd: cmdparser.CmdAddOtp,
+			args: cmdparser.UserArgs{
+				OtpURI: "otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example",
+				Note:   "note",
+			},
+			wantErr: false,
+			wantRes: false,
+		},
+		{
+			name:    "error add otp test",
+			userCmd: cmdparser.CmdAddOtp,
+			args:    cmdparser.UserArgs{OtpURI: "otpauth://hotp/Example:alice?secret=JBSWY3DPEHPK3PXP"},
+			wantErr: true,
+		},
+		{
+			name: "ok add ssh key test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				m.EXPECT().AddSshKey(cmdCtx, "", gomock.Any()).
+					DoAndReturn(func(ctx context.Context, userLogin string, k storage.SshKey) error {
+						uk, err := decryptSshKey(k)
+						require.NoError(t, err)
+						assert.Equal(t, testUserSshKey.PublicKey, uk.PublicKey)
+						return nil
+					})
+			},
+			userCmd: cmdparser.CmdAddSshKey,
+			args:    cmdparser.UserArgs{Prompt: "prompt", Note: "note", SshKey: writeTestSshKey(t)},
+			wantErr: false,
+			wantRes: false,
+		},
+		{
+			name:    "error add ssh key test",
+			userCmd: cmdparser.CmdAddSshKey,
+			args:    cmdparser.UserArgs{Prompt: "prompt", SshKey: "test_file"},
+			wantErr: true,
+		},
+		{
+			name: "ok get ssh key test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				k, err := encryptSshKey(testUserSshKey)
+				require.NoError(t, err)
+				m.EXPECT().GetSshKey(cmdCtx, "", k.Prompt).Return(k, nil)
+			},
+			userCmd: cmdparser.CmdGetSshKey,
+			args:    cmdparser.UserArgs{Prompt: "prompt"},
+			wantErr: false,
+			wantRes: true,
+			res:     SshKeys{testUserSshKey},
+		},
+		{
+			name: "ok add template test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				tm, err := encryptTemplate(testUserTemplate)
+				require.NoError(t, err)
+				m.EXPECT().AddTemplate(cmdCtx, "", gomock.Any()).
+					DoAndReturn(func(ctx context.Context, userLogin string, got storage.Template) error {
+						assert.Equal(t, tm.Name, got.Name)
+						assert.Equal(t, tm.Fields, got.Fields)
+						return nil
+					})
+			},
+			userCmd: cmdparser.CmdAddTemplate,
+			args: cmdparser.UserArgs{
+				Template: "server",
+				Fields:   []string{"host:text", "port:number", "password:text:secret"},
+			},
+			wantErr: false,
+			wantRes: false,
+		},
+		{
+			name:    "error add template test",
+			userCmd: cmdparser.CmdAddTemplate,
+			args:    cmdparser.UserArgs{Template: "server", Fields: []string{"host:blob"}},
+			wantErr: true,
+		},
+		{
+			name: "ok get templates test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				tm, err := encryptTemplate(testUserTemplate)
+				require.NoError(t, err)
+				m.EXPECT().GetUserTemplatesAfterTime(cmdCtx, "", gomock.Any()).
+					Return([]storage.Template{tm}, nil)
+			},
+			userCmd: cmdparser.CmdGetTemplates,
+			wantErr: false,
+			wantRes: true,
+			res:     Templates{testUserTemplate},
+		},
+		{
+			name: "ok add custom record test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				tm, err := encryptTemplate(testUserTemplate)
+				require.NoError(t, err)
+				m.EXPECT().GetTemplate(cmdCtx, "", tm.Name).Return(tm, nil)
+				m.EXPECT().AddCustomRecord(cmdCtx, "", gomock.Any()).
+					DoAndReturn(func(ctx context.Context, userLogin string, r storage.CustomRecord) error {
+						d, err := decryptCustomRecord(r)
+						require.NoError(t, err)
+						assert.Equal(t, testCustomData.Values, d.Values)
+						assert.Equal(t, testCustomData.Template, d.Template)
+						return nil
+					})
+			},
+			userCmd: cmdparser.CmdAddCustom,
+			args: cmdparser.UserArgs{
+				Prompt: "prompt", Note: "note", Template: "server",
+				Fields: []string{"host=localhost", "port=5432", "password=secret"},
+			},
+			wantErr: false,
+			wantRes: false,
+		},
+		{
+			name: "invalid value add custom record test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				tm, err := encryptTemplate(testUserTemplate)
+				require.NoError(t, err)
+				m.EXPECT().GetTemplate(cmdCtx, "", tm.Name).Return(tm, nil)
+			},
+			userCmd: cmdparser.CmdAddCustom,
+			args:    cmdparser.UserArgs{Prompt: "prompt", Template: "server", Fields: []string{"port=abc"}},
+			wantErr: true,
+		},
+		{
+			name: "ok get custom record test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				tm, err := encryptTemplate(testUserTemplate)
+				require.NoError(t, err)
+				r, err := encryptCustomRecord(testCustomData)
+				require.NoError(t, err)
+				m.EXPECT().GetCustomRecord(cmdCtx, "", r.Prompt).Return(r, nil)
+				m.EXPECT().GetTemplate(cmdCtx, "", tm.Name).Return(tm, nil)
+			},
+			userCmd: cmdparser.CmdGetCustom,
+			args:    cmdparser.UserArgs{Prompt: "prompt"},
+			wantErr: false,
+			wantRes: true,
+			res: CustomRecords{{
+				Prompt:   "prompt",
+				Template: "server",
+				Values: []customrecord.Value{
+					{Name: "host", Value: "localhost"},
+					{Name: "port", Value: "5432"},
+					{Name: "password", Value: customrecord.SecretMask},
+				},
+				Note:      "note",
+				TimeStamp: testTime,
+			}},
+		},
+		{
+			name: "ok get custom records with secrets test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				tm, err := encryptTemplate(testUserTemplate)
+				require.NoError(t, err)
+				r, err := encryptCustomRecord(testCustomData)
+				require.NoError(t, err)
+				m.EXPECT().GetUserCustomRecordsAfterTime(cmdCtx, "", gomock.Any()).
+					Return([]storage.CustomRecord{r}, nil)
+				m.EXPECT().GetTemplate(cmdCtx, "", tm.Name).Return(tm, nil)
+			},
+			userCmd: cmdparser.CmdGetCustoms,
+			args:    cmdparser.UserArgs{Reveal: true},
+			wantErr: false,
+			wantRes: true,
+			res: CustomRecords{{
+				Prompt:   "prompt",
+				Template: "server",
+				Values: []customrecord.Value{
+					{Name: "host", Value: "localhost"},
+					{Name: "port", Value: "5432"},
+					{Name: "password", Value: "secret"},
+				},
+				Note:      "note",
+				TimeStamp: testTime,
+			}},
+		},
+		{
+			name: "ok get otp test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				m.EXPECT().GetOtp(cmdCtx, "", testOtp.Issuer, testOtp.Account).
+					Return(testOtp, nil)
+			},
+			userCmd: cmdparser.CmdGetOtp,
+			args:    cmdparser.UserArgs{Prompt: "Example", Login: "alice"},
+			wantErr: false,
+			wantRes: true,
+		},
+		{
+			name: "ok get cards with filter test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				c := testCard
+				var err error
+				c.Tags, err = encryptTags([]string{"bank", "work"})
+				require.NoError(t, err)
+				c.Folder, err = encryptFolder("finance/banks")
+				require.NoError(t, err)
+				m.EXPECT().GetUserCardsAfterTime(cmdCtx, "", gomock.Any()).
+					Return([]storage.Card{c}, nil)
+			},
+			userCmd: cmdparser.CmdGetCards,
+			args:    cmdparser.UserArgs{Tags: []string{"work"}, Folder: "finance"},
+			wantErr: false,
+			wantRes: true,
+		},
+		{
+			name: "ok set tags test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				enT, err := encryptTags([]string{"bank", "work"})
+				require.NoError(t, err)
+				m.EXPECT().UpdateLabel(cmdCtx, "",
+					storage.LoginPwd{Prompt: testLoginPwd.Prompt, Login: testLoginPwd.Login},
+					storage.LabelTags, enT, gomock.Any()).Return(nil)
+			},
+			userCmd: cmdparser.CmdSetTags,
+			args: cmdparser.UserArgs{
+				RecordType: "login", Prompt: ttArgs.Prompt, Login: ttArgs.Login,
+				Tags: []string{"work", " bank", "work"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "error set tags test",
+			userCmd: cmdparser.CmdSetTags,
+			args:    cmdparser.UserArgs{RecordType: "login", Prompt: ttArgs.Prompt, Login: ttArgs.Login, Tags: []string{"a,b"}},
+			wantErr: true,
+		},
+		{
+			name: "ok move test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				enF, err := encryptFolder("finance/banks")
+				require.NoError(t, err)
+				m.EXPECT().UpdateLabel(cmdCtx, "", storage.Card{Number: testCard.Number},
+					storage.LabelFolder, enF, gomock.Any()).Return(nil)
+			},
+			userCmd: cmdparser.CmdMove,
+			args:    cmdparser.UserArgs{RecordType: "card", CardNumber: ttArgs.CardNumber, Folder: "/finance/banks/"},
+			wantErr: false,
+		},
+		{
+			name:    "error move unknown type test",
+			userCmd: cmdparser.CmdMove,
+			args:    cmdparser.UserArgs{RecordType: "note", Prompt: ttArgs.Prompt, Folder: "finance"},
+			wantErr: true,
+		},
+		{
+			name: "ok history test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().ListVersions(ctxMd, &pb.ListVersionsRequest{Key: &pb.RecordKey{
+					Type: pb.RecordType_RECORD_TYPE_CARD,
+					Key:  testCard.Number,
+				}}).Return(&pb.ListVersionsResponse{Versions: []*pb.RecordVersion{{
+					Version: 2,
+					Record: &pb.Record{
+						Type:    pb.RecordType_RECORD_TYPE_CARD,
+						Payload: &pb.Record_Card{Card: cardToPb(testCard)},
+					},
+				}}}, nil)
+			},
+			userCmd: cmdparser.CmdHistory,
+			args:    cmdparser.UserArgs{RecordType: "card", CardNumber: ttArgs.CardNumber},
+			wantErr: false,
+			wantRes: true,
+		},
+		{
+			name: "ok restore test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().RestoreVersion(ctxMd, &pb.RestoreVersionRequest{
+					Key:     &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+					Version: 2,
+				}).Return(&pb.RestoreVersionResponse{Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_TEXT,
+					Payload: &pb.Record_TextRecord{TextRecord: textToPb(testTextRecord)},
+				}}, nil)
+				m.EXPECT().RestoreRecord(cmdCtx, "", testTextRecord).Return(nil)
+			},
+			userCmd: cmdparser.CmdRestore,
+			args:    cmdparser.UserArgs{RecordType: "text", Prompt: ttArgs.Prompt, VersionID: 2},
+			wantErr: false,
+			wantRes: true,
+		},
+		{
+			name: "error restore test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().RestoreVersion(ctxMd, gomock.Any()).Return(nil, errors.New("not found"))
+			},
+			userCmd: cmdparser.CmdRestore,
+			args:    cmdparser.UserArgs{RecordType: "ssh", Prompt: ttArgs.Prompt, VersionID: 5},
+			wantErr: true,
+		},
+		{
+			name: "ok resolve with server version test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().GetRecord(ctxMd, &pb.GetRecordRequest{Key: &pb.RecordKey{
+					Type:   pb.RecordType_RECORD_TYPE_TEXT,
+					Prompt: testTextRecord.Prompt,
+				}}).Return(&pb.GetRecordResponse{Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_TEXT,
+					Payload: &pb.Record_TextRecord{TextRecord: textToPb(testTextRecord)},
+				}}, nil)
+				m.EXPECT().RestoreRecord(cmdCtx, "", testTextRecord).Return(nil)
+			},
+			userCmd: cmdparser.CmdResolve,
+			args:    cmdparser.UserArgs{RecordType: "text", Prompt: ttArgs.Prompt, Keep: "server"},
+			wantErr: false,
+			wantRes: true,
+		},
+		{
+			name: "ok resolve with local version test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				m.EXPECT().GetCard(cmdCtx, "", testCard.Number).Return(testCard, nil)
+				mcli.EXPECT().ForceUpdateRecord(ctxMd, &pb.ForceUpdateRecordRequest{Record: &pb.Record{
+					Type:    pb.RecordType_RECORD_TYPE_CARD,
+					Payload: &pb.Record_Card{Card: cardToPb(testCard)},
+				}}).Return(&pb.ForceUpdateRecordResponse{}, nil)
+			},
+			userCmd: cmdparser.CmdResolve,
+			args:    cmdparser.UserArgs{RecordType: "card", CardNumber: ttArgs.CardNumber, Keep: "local"},
+			wantErr: false,
+		},
+		{
+			name:    "error resolve without version test",
+			userCmd: cmdparser.CmdResolve,
+			args:    cmdparser.UserArgs{RecordType: "card", CardNumber: ttArgs.CardNumber},
+			wantErr: true,
+		},
+		{
+			name: "ok audit test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().GetAuditLog(ctxMd, &pb.GetAuditLogRequest{From: testSyncTime, To: testTime}).
+					Return(&pb.GetAuditLogResponse{Events: []*pb.AuditEvent{{
+						Action:    "read",
+						Key:       &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number},
+						Device:    "laptop",
+						Ip:        "10.0.0.1",
+						TimeStamp: testTime,
+					}}}, nil)
+			},
+			userCmd: cmdparser.CmdAudit,
+			args:    cmdparser.UserArgs{Since: testSyncTime, Until: testTime},
+			wantErr: false,
+			wantRes: true,
+		},
+		{
+			name: "ok usage test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().GetUsage(ctxMd, &pb.GetUsageRequest{}).Return(&pb.GetUsageResponse{
+					Usage:      []*pb.RecordUsage{{Type: pb.RecordType_RECORD_TYPE_CARD, Records: 2, Bytes: 120}},
+					TotalBytes: 120,
+					QuotaBytes: 1000,
+				}, nil)
+			},
+			userCmd: cmdparser.CmdUsage,
+			args:    cmdparser.UserArgs{},
+			wantErr: false,
+			wantRes: true,
+		},
+		{
+			name: "error usage test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().GetUsage(ctxMd, &pb.GetUsageRequest{}).Return(nil, errors.New("error"))
+			},
+			userCmd: cmdparser.CmdUsage,
+			args:    cmdparser.UserArgs{},
+			wantErr: true,
+		},
+		{
+			name: "error audit test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().GetAuditLog(ctxMd, &pb.GetAuditLogRequest{}).Return(nil, errors.New("error"))
+			},
+			userCmd: cmdparser.CmdAudit,
+			args:    cmdparser.UserArgs{},
+			wantErr: true,
+		},
+		{
+			name: "ok init keys test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().GetUserKeys(ctxMd, &pb.GetUserKeysRequest{}).
+					Return(nil, status.Error(codes.NotFound, "not found"))
+				mcli.EXPECT().SetUserKeys(ctxMd, gomock.Any()).Return(&pb.SetUserKeysResponse{}, nil)
+			},
+			userCmd: cmdparser.CmdInitKeys,
+			args:    cmdparser.UserArgs{},
+			wantErr: false,
+		},
+		{
+			name: "error init keys exist test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().GetUserKeys(ctxMd, &pb.GetUserKeysRequest{}).Return(&pb.GetUserKeysResponse{}, nil)
+			},
+			userCmd: cmdparser.CmdInitKeys,
+			args:    cmdparser.UserArgs{},
+			wantErr: true,
+		},
+		{
+			name: "ok add org test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().CreateOrganization(ctxMd, &pb.CreateOrganizationRequest{Name: "team"}).
+					Return(&pb.CreateOrganizationResponse{Organization: &pb.Organization{Id: 3, Name: "team"}}, nil)
+			},
+			userCmd: cmdparser.CmdAddOrg,
+			args:    cmdparser.UserArgs{Name: "team"},
+			wantErr: false,
+			wantRes: true,
+		},
+		{
+			name: "ok get collections test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().ListCollections(ctxMd, &pb.ListCollectionsRequest{}).
+					Return(&pb.ListCollectionsResponse{Collections: []*pb.Collection{{
+						Id: 5, OrgId: 3, Organization: "team", Name: "ops", Role: pb.MemberRole_MEMBER_ROLE_EDITOR,
+					}}}, nil)
+			},
+			userCmd: cmdparser.CmdGetCollections,
+			args:    cmdparser.UserArgs{},
+			wantErr: false,
+			wantRes: true,
+		},
+		{
+			name:    "error invite unknown role test",
+			userCmd: cmdparser.CmdInvite,
+			args:    cmdparser.UserArgs{Collection: 5, Member: "bob", Role: "admin"},
+			wantErr: true,
+		},
+		{
+			name: "error add collection without keys test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				mcli.EXPECT().GetUserKeys(ctxMd, &pb.GetUserKeysRequest{}).
+					Return(nil, status.Error(codes.NotFound, "not found"))
+			},
+			userCmd: cmdparser.CmdAddCollection,
+			args:    cmdparser.UserArgs{OrgID: 3, Name: "ops"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -345,7 +888,7 @@ func TestExecuteCmd(t *testing.T) {
 			if tt.prepare != nil {
 				tt.prepare(m, mCli)
 			}
-			res, err := ExecuteCmd(tt.userCmd, tt.args, mCli, m)
+			res, err := ExecuteCmd(context.Background(), tt.userCmd, tt.args, mCli, m)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -358,9 +901,181 @@ func TestExecuteCmd(t *testing.T) {
 	}
 }
 
+func TestSharedCollection(t *testing.T) {
+	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
+	ctxMd := deadlineMatcher{md: md}
+
+	pub, priv, err := cryptor.GenerateKeyPair()
+	require.NoError(t, err)
+	enPriv, err := cryptor.EncryptsByte(priv)
+	require.NoError(t, err)
+	key, err := cryptor.GenerateCollectionKey()
+	require.NoError(t, err)
+	wrapped, err := cryptor.WrapKey(pub, key)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := mocks.NewMockRepositorier(ctrl)
+	mCli := mocks.NewMockInfoKeeperClient(ctrl)
+
+	mCli.EXPECT().ListCollections(ctxMd, &pb.ListCollectionsRequest{}).
+		Return(&pb.ListCollectionsResponse{Collections: []*pb.Collection{{Id: 5, WrappedKey: wrapped}}}, nil).Times(4)
+	mCli.EXPECT().GetUserKeys(ctxMd, &pb.GetUserKeysRequest{}).
+		Return(&pb.GetUserKeysResponse{Keys: &pb.UserKeys{PublicKey: pub, PrivateKey: enPriv}}, nil).Times(3)
+
+	mCli.EXPECT().GetPublicKey(ctxMd, &pb.GetPublicKeyRequest{Login: "bob"}).
+		Return(&pb.GetPublicKeyResponse{PublicKey: pub}, nil)
+	mCli.EXPECT().AddCollectionMember(ctxMd, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, in *pb.AddCollectionMemberRequest, opts ...grpc.CallOption) (*pb.AddCollectionMemberResponse, error) {
+			assert.Equal(t, pb.MemberRole_MEMBER_ROLE_VIEWER, in.GetRole())
+			got, err := cryptor.UnwrapKey(pub, priv, in.GetWrappedKey())
+			assert.NoError(t, err)
+			assert.Equal(t, key, got)
+			return &pb.AddCollectionMemberResponse{}, nil
+		})
+	_, err = ExecuteCmd(context.Background(), cmdparser.CmdInvite,
+		cmdparser.UserArgs{Collection: 5, Member: "bob", Role: "viewer"}, mCli, m)
+	require.NoError(t, err)
+
+	var shared *pb.SharedRecord
+	mCli.EXPECT().GetRecord(ctxMd, &pb.GetRecordRequest{Key: &pb.RecordKey{
+		Type: pb.RecordType_RECORD_TYPE_CARD,
+		Key:  testCard.Number,
+	}}).Return(&pb.GetRecordResponse{Record: &pb.Record{
+		Type:    pb.RecordType_RECORD_TYPE_CARD,
+		Payload: &pb.Record_Card{Card: cardToPb(testCard)},
+	}}, nil)
+	mCli.EXPECT().MoveRecordToCollection(ctxMd, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, in *pb.MoveRecordToCollectionRequest, opts ...grpc.CallOption) (*pb.MoveRecordToCollectionResponse, error) {
+			assert.Equal(t, int64(5), in.GetCollectionId())
+			assert.Equal(t, testCard.Number, in.GetSource().GetKey())
+			assert.NotContains(t, string(in.GetRecord().GetData()), ttArgs.CardCode)
+			number, err := cryptor.DecryptsWithKey(key, in.GetRecord().GetKey())
+			assert.NoError(t, err)
+			assert.Equal(t, ttArgs.CardNumber, string(number))
+			shared = in.GetRecord()
+			return &pb.MoveRecordToCollectionResponse{}, nil
+		})
+	m.EXPECT().DeleteRecord(gomock.Any(), "", storage.Card{Number: testCard.Number}).Return(nil)
+	_, err = ExecuteCmd(context.Background(), cmdparser.CmdToCollection,
+		cmdparser.UserArgs{Collection: 5, RecordType: "card", CardNumber: ttArgs.CardNumber}, mCli, m)
+	require.NoError(t, err)
+
+	mCli.EXPECT().ListCollectionRecords(ctxMd, &pb.ListCollectionRecordsRequest{CollectionId: 5}).
+		Return(&pb.ListCollectionRecordsResponse{Records: []*pb.SharedRecord{shared}}, nil)
+	res, err := ExecuteCmd(context.Background(), cmdparser.CmdGetShared,
+		cmdparser.UserArgs{Collection: 5}, mCli, m)
+	require.NoError(t, err)
+	assert.Equal(t, SharedRecords{testUserCards}, res)
+
+	_, err = ExecuteCmd(context.Background(), cmdparser.CmdGetShared,
+		cmdparser.UserArgs{Collection: 6}, mCli, m)
+	assert.Error(t, err)
+}
+
+func TestEmergencyAccess(t *testing.T) {
+	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
+	ctxMd := deadlineMatcher{md: md}
+	pub, priv, err := cryptor.GenerateKeyPair()
+	require.NoError(t, err)
+	enPriv, err := cryptor.EncryptsByte(priv)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := mocks.NewMockRepositorier(ctrl)
+	mCli := mocks.NewMockInfoKeeperClient(ctrl)
+
+	var wrapped []byte
+	var records []*pb.SharedRecord
+	mCli.EXPECT().GetPublicKey(ctxMd, &pb.GetPublicKeyRequest{Login: "bob"}).
+		Return(&pb.GetPublicKeyResponse{PublicKey: pub}, nil)
+	m.EXPECT().GetUserRecords(gomock.Any(), "").Return([]any{testCard, testBinaryRecord}, nil)
+	mCli.EXPECT().AddEmergencyContact(ctxMd, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, in *pb.AddEmergencyContactRequest, opts ...grpc.CallOption) (*pb.AddEmergencyContactResponse, error) {
+			assert.Equal(t, "bob", in.GetLogin())
+			assert.Equal(t, int64(172800), in.GetWaitSeconds())
+			key, err := cryptor.UnwrapKey(pub, priv, in.GetWrappedKey())
+			assert.NoError(t, err)
+			assert.NotEqual(t, cryptor.UserKey, key)
+			require.Len(t, in.GetRecords(), 2)
+			assert.Equal(t, pb.RecordType_RECORD_TYPE_CARD, in.GetRecords()[0].GetType())
+			assert.Equal(t, pb.RecordType_RECORD_TYPE_BINARY, in.GetRecords()[1].GetType())
+			assert.NotContains(t, string(in.GetRecords()[0].GetData()), ttArgs.CardCode)
+			wrapped, records = in.GetWrappedKey(), in.GetRecords()
+			return &pb.AddEmergencyContactResponse{}, nil
+		})
+	_, err = ExecuteCmd(context.Background(), cmdparser.CmdAddEmergency,
+		cmdparser.UserArgs{Member: "bob", Wait: 48}, mCli, m)
+	require.NoError(t, err)
+
+	_, err = ExecuteCmd(context.Background(), cmdparser.CmdAddEmergency,
+		cmdparser.UserArgs{Member: "bob", Wait: -1}, mCli, m)
+	assert.Error(t, err)
+
+	mCli.EXPECT().RequestEmergencyAccess(ctxMd, &pb.RequestEmergencyAccessRequest{Grantor: "alice"}).
+		Return(&pb.RequestEmergencyAccessResponse{AvailableAt: testTime}, nil)
+	res, err := ExecuteCmd(context.Background(), cmdparser.CmdRequestEmergency,
+		cmdparser.UserArgs{Member: "alice"}, mCli, m)
+	require.NoError(t, err)
+	assert.Equal(t, EmergencyRequest{Grantor: "alice", AvailableAt: testTime}, res)
+
+	mCli.EXPECT().ListEmergencyContacts(ctxMd, &pb.ListEmergencyContactsRequest{}).
+		Return(&pb.ListEmergencyContactsResponse{Contacts: []*pb.EmergencyContact{
+			{Grantor: "alice", Grantee: "bob", WaitSeconds: 3600, RequestedAt: testTime, AvailableAt: testTime, Granted: true},
+		}}, nil)
+	res, err = ExecuteCmd(context.Background(), cmdparser.CmdGetEmergency, cmdparser.UserArgs{}, mCli, m)
+	require.NoError(t, err)
+	assert.Equal(t, EmergencyContacts{{
+		Grantor: "alice", Grantee: "bob", Wait: time.Hour, RequestedAt: testTime, AvailableAt: testTime, Granted: true,
+	}}, res)
+
+	mCli.EXPECT().GetEmergencyKey(ctxMd, &pb.GetEmergencyKeyRequest{Grantor: "alice"}).
+		Return(&pb.GetEmergencyKeyResponse{WrappedKey: wrapped, Records: records}, nil)
+	mCli.EXPECT().GetUserKeys(ctxMd, &pb.GetUserKeysRequest{}).
+		Return(&pb.GetUserKeysResponse{Keys: &pb.UserKeys{PublicKey: pub, PrivateKey: enPriv}}, nil)
+	res, err = ExecuteCmd(context.Background(), cmdparser.CmdEmergencyKey,
+		cmdparser.UserArgs{Member: "alice"}, mCli, m)
+	require.NoError(t, err)
+	ub, err := decryptBinaryRecord(testBinaryRecord)
+	require.NoError(t, err)
+	assert.Equal(t, EmergencyVault{Grantor: "alice", Records: SharedRecords{testUserCards, BinaryRecords{ub}}}, res)
+
+	mCli.EXPECT().GetEmergencyKey(ctxMd, &pb.GetEmergencyKeyRequest{Grantor: "carol"}).
+		Return(nil, status.Error(codes.PermissionDenied, "emergency access is not requested"))
+	_, err = ExecuteCmd(context.Background(), cmdparser.CmdEmergencyKey,
+		cmdparser.UserArgs{Member: "carol"}, mCli, m)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	mCli.EXPECT().DeclineEmergencyAccess(ctxMd, &pb.DeclineEmergencyAccessRequest{Login: "bob"}).
+		Return(&pb.DeclineEmergencyAccessResponse{}, nil)
+	_, err = ExecuteCmd(context.Background(), cmdparser.CmdDeclineEmergency,
+		cmdparser.UserArgs{Member: "bob"}, mCli, m)
+	assert.NoError(t, err)
+	mCli.EXPECT().RemoveEmergencyContact(ctxMd, &pb.RemoveEmergencyContactRequest{Login: "bob"}).
+		Return(&pb.RemoveEmergencyContactResponse{}, nil)
+	_, err = ExecuteCmd(context.Background(), cmdparser.CmdRemoveEmergency,
+		cmdparser.UserArgs{Member: "bob"}, mCli, m)
+	assert.NoError(t, err)
+}
+
+func TestCommandDeadline(t *testing.T) {
+	saved := CmdDeadlines
+	defer func() { CmdDeadlines = saved }()
+	CmdDeadlines = Deadlines{Request: time.Second, Sync: time.Minute}
+
+	assert.Equal(t, time.Second, commandDeadline(cmdparser.CmdGetCard))
+	assert.Equal(t, time.Second, commandDeadline(cmdparser.CmdAddBinary))
+	assert.Equal(t, time.Minute, commandDeadline(cmdparser.CmdAuth))
+	assert.Equal(t, time.Minute, commandDeadline(cmdparser.CmdGetBinaryServer))
+}
+
 func TestSynchronization(t *testing.T) {
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
 	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
+	pub, priv, err := cryptor.GenerateKeyPair()
+	require.NoError(t, err)
 
 	tests := []struct {
 		name    string
@@ -373,49 +1088,236 @@ func TestSynchronization(t *testing.T) {
 			name: "ok sync test",
 			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
 				gomock.InOrder(
-					m.EXPECT().GetLastSyncTime(context.Background(), "").
-						Return(testSyncTime, nil),
-					m.EXPECT().GetUserCardsAfterTime(context.Background(), "", testSyncTime).
+					m.EXPECT().GetSyncRevision(context.Background(), "").
+						Return(testSyncRevision, nil),
+					m.EXPECT().GetUserCardsToSync(context.Background(), "").
 						Return([]storage.Card{testCard}, nil),
-					m.EXPECT().GetUserLoginsPwdsAfterTime(context.Background(), "", testSyncTime).
+					m.EXPECT().GetUserLoginsPwdsToSync(context.Background(), "").
 						Return([]storage.LoginPwd{testLoginPwd}, nil),
-					m.EXPECT().GetUserTextRecordsAfterTime(context.Background(), "", testSyncTime).
+					m.EXPECT().GetUserTextRecordsToSync(context.Background(), "").
 						Return([]storage.TextRecord{testTextRecord}, nil),
-					m.EXPECT().GetUserBinaryRecordsAfterTime(context.Background(), "", testSyncTime).
+					m.EXPECT().GetUserBinaryRecordsToSync(context.Background(), "").
 						Return([]storage.BinaryRecord{testBinaryRecord}, nil),
+					m.EXPECT().GetUserOtpsToSync(context.Background(), "").
+						Return([]storage.Otp{testOtp}, nil),
+					m.EXPECT().GetUserSshKeysToSync(context.Background(), "").
+						Return([]storage.SshKey{testSshKey}, nil),
+					m.EXPECT().GetUserTemplatesToSync(context.Background(), "").
+						Return([]storage.Template{testTemplate}, nil),
+					m.EXPECT().GetUserCustomRecordsToSync(context.Background(), "").
+						Return([]storage.CustomRecord{testCustomRecord}, nil),
+					expectUpload(t, mcli, ctxMd, testBinaryRecord, false, nil),
 					mcli.EXPECT().SyncUserData(ctxMd, &pb.SyncUserDataRequest{
 						Logins:        []*pb.UserLoginPwd{loginToPb(testLoginPwd)},
 						Cards:         []*pb.UserCard{cardToPb(testCard)},
 						TextRecords:   []*pb.UserTextRecord{textToPb(testTextRecord)},
-						BinaryRecords: []*pb.UserBinaryRecord{binaryToPb(testBinaryRecord)},
-						LastSync:      testSyncTime,
+						BinaryRefs:    []*pb.BinaryRecordRef{testBinaryRef},
+						Otps:          []*pb.UserOtp{otpToPb(testOtp)},
+						SshKeys:       []*pb.UserSshKey{sshKeyToPb(testSshKey)},
+						Templates:     []*pb.UserTemplate{templateToPb(testTemplate)},
+						CustomRecords: []*pb.UserCustomRecord{customRecordToPb(testCustomRecord)},
+						SinceRevision: testSyncRevision,
 					}).Return(&pb.SyncUserDataResponse{
 						SyncErrors: []*pb.SyncUserDataResponse_SyncErrorInfo{{
-							Text:  "text error",
-							Value: []byte{20, 89, 224, 162, 229, 20, 169, 198, 23, 48, 193, 238, 14, 23, 152, 188, 173, 160, 95},
-							Err:   "error",
+							Text:            "text error",
+							Value:           []byte{20, 89, 224, 162, 229, 20, 169, 198, 23, 48, 193, 238, 14, 23, 152, 188, 173, 160, 95},
+							Err:             "error",
+							Key:             &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+							Code:            pb.SyncErrorCode_SYNC_ERROR_CODE_CONFLICT_NEWER_ON_SERVER,
+							ServerTimeStamp: testTime,
 						}},
 						NewLogins:        []*pb.UserLoginPwd{loginToPb(testLoginPwd)},
 						NewCards:         []*pb.UserCard{cardToPb(testCard)},
 						NewTextRecords:   []*pb.UserTextRecord{textToPb(testTextRecord)},
-						NewBinaryRecords: []*pb.UserBinaryRecord{binaryToPb(testBinaryRecord)},
+						NewBinaryRefs:    []*pb.BinaryRecordRef{testBinaryRef},
+						NewOtps:          []*pb.UserOtp{otpToPb(testOtp)},
+						NewSshKeys:       []*pb.UserSshKey{sshKeyToPb(testSshKey)},
+						NewTemplates:     []*pb.UserTemplate{templateToPb(testTemplate)},
+						NewCustomRecords: []*pb.UserCustomRecord{customRecordToPb(testCustomRecord)},
+						CurrentRevision:  testSyncRevision + 1,
 					}, nil),
+					expectDownload(t, mcli, ctxMd, testBinaryRecord),
 					m.EXPECT().AddSyncData(context.Background(), "",
 						[]storage.Card{testCard}, []storage.LoginPwd{testLoginPwd},
-						[]storage.TextRecord{testTextRecord}, []storage.BinaryRecord{testBinaryRecord}).
+						[]storage.TextRecord{testTextRecord}, []storage.BinaryRecord{testBinaryRecord},
+						[]storage.Otp{testOtp}, []storage.SshKey{testSshKey},
+						[]storage.Template{testTemplate}, []storage.CustomRecord{testCustomRecord}).
+						Return(nil),
+					// Запись с ошибкой синхронизации остается непереданной.
+					m.EXPECT().UpdateSyncRevision(context.Background(), "", testSyncRevision+1,
+						[]any{testCard, testLoginPwd, testOtp, testSshKey, testTemplate, testCustomRecord, testBinaryRecord}).
+						Return(nil),
+					// Копия записей обновляется только у доверенных лиц пользователя.
+					mcli.EXPECT().ListEmergencyContacts(ctxMd, &pb.ListEmergencyContactsRequest{}).
+						Return(&pb.ListEmergencyContactsResponse{Contacts: []*pb.EmergencyContact{
+							{Grantor: "", Grantee: "bob"},
+							{Grantor: "alice", Grantee: ""},
+						}}, nil),
+					mcli.EXPECT().GetPublicKey(ctxMd, &pb.GetPublicKeyRequest{Login: "bob"}).
+						Return(&pb.GetPublicKeyResponse{PublicKey: pub}, nil),
+					m.EXPECT().GetUserRecords(context.Background(), "").
+						Return([]any{testCard, testBinaryRecord}, nil),
+					mcli.EXPECT().UpdateEmergencyRecords(ctxMd, gomock.Any()).
+						DoAndReturn(func(ctx context.Context, in *pb.UpdateEmergencyRecordsRequest, opts ...grpc.CallOption) (*pb.UpdateEmergencyRecordsResponse, error) {
+							assert.Equal(t, "bob", in.GetLogin())
+							_, err := cryptor.UnwrapKey(pub, priv, in.GetWrappedKey())
+							assert.NoError(t, err)
+							assert.Len(t, in.GetRecords(), 2)
+							return &pb.UpdateEmergencyRecordsResponse{}, nil
+						}),
+				)
+			},
+			wantErr: false,
+			wantRes: true,
+			res: SyncErrs{{
+				Text:            "text error",
+				Value:           "err",
+				ErrMsg:          "error",
+				Code:            pb.SyncErrorCode_SYNC_ERROR_CODE_CONFLICT_NEWER_ON_SERVER,
+				Key:             &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+				ServerTimeStamp: testTime,
+			}},
+		},
+		{
+			name: "upload binary error test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				gomock.InOrder(
+					m.EXPECT().GetSyncRevision(context.Background(), "").
+						Return(testSyncRevision, nil),
+					m.EXPECT().GetUserCardsToSync(context.Background(), "").
+						Return([]storage.Card{}, nil),
+					m.EXPECT().GetUserLoginsPwdsToSync(context.Background(), "").
+						Return([]storage.LoginPwd{}, nil),
+					m.EXPECT().GetUserTextRecordsToSync(context.Background(), "").
+						Return([]storage.TextRecord{}, nil),
+					m.EXPECT().GetUserBinaryRecordsToSync(context.Background(), "").
+						Return([]storage.BinaryRecord{testBinaryRecord}, nil),
+					m.EXPECT().GetUserOtpsToSync(context.Background(), "").
+						Return([]storage.Otp{}, nil),
+					m.EXPECT().GetUserSshKeysToSync(context.Background(), "").
+						Return([]storage.SshKey{}, nil),
+					m.EXPECT().GetUserTemplatesToSync(context.Background(), "").
+						Return([]storage.Template{}, nil),
+					m.EXPECT().GetUserCustomRecordsToSync(context.Background(), "").
+						Return([]storage.CustomRecord{}, nil),
+					expectUpload(t, mcli, ctxMd, testBinaryRecord, false, errors.New("error")),
+					mcli.EXPECT().SyncUserData(ctxMd, &pb.SyncUserDataRequest{
+						Logins:        []*pb.UserLoginPwd{},
+						Cards:         []*pb.UserCard{},
+						TextRecords:   []*pb.UserTextRecord{},
+						BinaryRefs:    []*pb.BinaryRecordRef{},
+						Otps:          []*pb.UserOtp{},
+						SshKeys:       []*pb.UserSshKey{},
+						Templates:     []*pb.UserTemplate{},
+						CustomRecords: []*pb.UserCustomRecord{},
+						SinceRevision: testSyncRevision,
+					}).Return(&pb.SyncUserDataResponse{CurrentRevision: testSyncRevision}, nil),
+					m.EXPECT().AddSyncData(context.Background(), "",
+						[]storage.Card{}, []storage.LoginPwd{},
+						[]storage.TextRecord{}, []storage.BinaryRecord{}, []storage.Otp{},
+						[]storage.SshKey{}, []storage.Template{}, []storage.CustomRecord{}).
 						Return(nil),
-					m.EXPECT().UpdateLastSyncTime(context.Background(), "", gomock.Any()).
+					// Незагруженные бинарные данные остаются непереданными.
+					m.EXPECT().UpdateSyncRevision(context.Background(), "", testSyncRevision, []any{}).
 						Return(nil),
+					// Ошибка обновления экстренного доступа не отменяет синхронизацию.
+					mcli.EXPECT().ListEmergencyContacts(ctxMd, &pb.ListEmergencyContactsRequest{}).
+						Return(nil, errors.New("error")),
 				)
 			},
 			wantErr: false,
 			wantRes: true,
 			res: SyncErrs{{
-				Text:   "text error",
-				Value:  "err",
+				Text:   "error for binary data with prompt ",
+				Value:  "prompt",
+				ErrMsg: "error",
+				Code:   pb.SyncErrorCode_SYNC_ERROR_CODE_INTERNAL,
+				Key:    &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: testBinaryRecord.Prompt},
+			}, {
+				Text:   "error updating emergency access for trusted contact ",
 				ErrMsg: "error",
+				Code:   pb.SyncErrorCode_SYNC_ERROR_CODE_INTERNAL,
 			}},
 		},
+		{
+			name: "deleted records test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				gomock.InOrder(
+					m.EXPECT().GetSyncRevision(context.Background(), "").
+						Return(testSyncRevision, nil),
+					m.EXPECT().GetUserCardsToSync(context.Background(), "").
+						Return([]storage.Card{}, nil),
+					m.EXPECT().GetUserLoginsPwdsToSync(context.Background(), "").
+						Return([]storage.LoginPwd{}, nil),
+					m.EXPECT().GetUserTextRecordsToSync(context.Background(), "").
+						Return([]storage.TextRecord{}, nil),
+					m.EXPECT().GetUserBinaryRecordsToSync(context.Background(), "").
+						Return([]storage.BinaryRecord{}, nil),
+					m.EXPECT().GetUserOtpsToSync(context.Background(), "").
+						Return([]storage.Otp{}, nil),
+					m.EXPECT().GetUserSshKeysToSync(context.Background(), "").
+						Return([]storage.SshKey{}, nil),
+					m.EXPECT().GetUserTemplatesToSync(context.Background(), "").
+						Return([]storage.Template{}, nil),
+					m.EXPECT().GetUserCustomRecordsToSync(context.Background(), "").
+						Return([]storage.CustomRecord{}, nil),
+					mcli.EXPECT().SyncUserData(ctxMd, gomock.Any()).Return(&pb.SyncUserDataResponse{
+						NewTextRecords: []*pb.UserTextRecord{textToPb(testTextRecord)},
+						DeletedRecords: []*pb.RecordKey{
+							{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number},
+							{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt},
+						},
+						CurrentRevision: testSyncRevision + 2,
+					}, nil),
+					m.EXPECT().DeleteRecord(context.Background(), "", storage.Card{Number: testCard.Number}).
+						Return(nil),
+					m.EXPECT().DeleteRecord(context.Background(), "", storage.TextRecord{Prompt: testTextRecord.Prompt}).
+						Return(nil),
+					m.EXPECT().AddSyncData(context.Background(), "",
+						[]storage.Card{}, []storage.LoginPwd{},
+						[]storage.TextRecord{testTextRecord}, []storage.BinaryRecord{}, []storage.Otp{},
+						[]storage.SshKey{}, []storage.Template{}, []storage.CustomRecord{}).
+						Return(nil),
+					m.EXPECT().UpdateSyncRevision(context.Background(), "", testSyncRevision+2, []any{}).
+						Return(nil),
+					mcli.EXPECT().ListEmergencyContacts(ctxMd, &pb.ListEmergencyContactsRequest{}).
+						Return(&pb.ListEmergencyContactsResponse{}, nil),
+				)
+			},
+			wantErr: false,
+			wantRes: false,
+		},
+		{
+			name: "delete record error test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient) {
+				gomock.InOrder(
+					m.EXPECT().GetSyncRevision(context.Background(), "").
+						Return(testSyncRevision, nil),
+					m.EXPECT().GetUserCardsToSync(context.Background(), "").
+						Return([]storage.Card{}, nil),
+					m.EXPECT().GetUserLoginsPwdsToSync(context.Background(), "").
+						Return([]storage.LoginPwd{}, nil),
+					m.EXPECT().GetUserTextRecordsToSync(context.Background(), "").
+						Return([]storage.TextRecord{}, nil),
+					m.EXPECT().GetUserBinaryRecordsToSync(context.Background(), "").
+						Return([]storage.BinaryRecord{}, nil),
+					m.EXPECT().GetUserOtpsToSync(context.Background(), "").
+						Return([]storage.Otp{}, nil),
+					m.EXPECT().GetUserSshKeysToSync(context.Background(), "").
+						Return([]storage.SshKey{}, nil),
+					m.EXPECT().GetUserTemplatesToSync(context.Background(), "").
+						Return([]storage.Template{}, nil),
+					m.EXPECT().GetUserCustomRecordsToSync(context.Background(), "").
+						Return([]storage.CustomRecord{}, nil),
+					mcli.EXPECT().SyncUserData(ctxMd, gomock.Any()).Return(&pb.SyncUserDataResponse{
+						DeletedRecords: []*pb.RecordKey{{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number}},
+					}, nil),
+					m.EXPECT().DeleteRecord(context.Background(), "", storage.Card{Number: testCard.Number}).
+						Return(errors.New("error")),
+				)
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -431,7 +1333,7 @@ func TestSynchronization(t *testing.T) {
 			if tt.prepare != nil {
 				tt.prepare(m, mCli)
 			}
-			resSync, err := synchronization(mCli, m)
+			resSync, err := synchronization(context.Background(), mCli, m)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -444,3 +1346,279 @@ func TestSynchronization(t *testing.T) {
 		})
 	}
 }
+
+// deadlineMatcher проверяет, что контекст команды ограничен сроком
+// и содержит метаданные md, если они заданы.
+type deadlineMatcher struct {
+	md metadata.MD
+}
+
+func (m deadlineMatcher) Matches(x any) bool {
+	ctx, ok := x.(context.Context)
+	if !ok {
+		return false
+	}
+	if _, ok = ctx.Deadline(); !ok {
+		return false
+	}
+	if m.md == nil {
+		return true
+	}
+	md, ok := metadata.FromOutgoingContext(ctx)
+	return ok && reflect.DeepEqual(md, m.md)
+}
+
+func (m deadlineMatcher) String() string {
+	return fmt.Sprintf("context with deadline and metadata %v", m.md)
+}
+
+func expectUpload(t *testing.T, mcli *mocks.MockInfoKeeperClient, ctx any, b storage.BinaryRecord,
+	force bool, errClose error) *gomock.Call {
+	stream := mocks.NewMockInfoKeeper_UploadBinaryClient(gomock.NewController(t))
+	sum := sha256.Sum256(b.Data)
+	gomock.InOrder(
+		stream.EXPECT().Send(&pb.UploadBinaryRequest{
+			Part: &pb.UploadBinaryRequest_Info_{
+				Info: &pb.UploadBinaryRequest_Info{
+					BinaryRecord: &pb.UserBinaryRecord{Prompt: b.Prompt, Note: b.Note, TimeStamp: b.TimeStamp},
+					Size:         int64(len(b.Data)),
+					Force:        force,
+				},
+			},
+		}).Return(nil),
+		stream.EXPECT().Send(&pb.UploadBinaryRequest{
+			Part: &pb.UploadBinaryRequest_Chunk{Chunk: b.Data},
+		}).Return(nil),
+		stream.EXPECT().Send(&pb.UploadBinaryRequest{
+			Part: &pb.UploadBinaryRequest_Checksum{Checksum: sum[:]},
+		}).Return(nil),
+		stream.EXPECT().CloseAndRecv().Return(&pb.UploadBinaryResponse{}, errClose),
+	)
+	return mcli.EXPECT().UploadBinary(ctx).Return(stream, nil)
+}
+
+func expectDownload(t *testing.T, mcli *mocks.MockInfoKeeperClient, ctx any, b storage.BinaryRecord) *gomock.Call {
+	stream := mocks.NewMockInfoKeeper_DownloadBinaryClient(gomock.NewController(t))
+	sum := sha256.Sum256(b.Data)
+	gomock.InOrder(
+		stream.EXPECT().Recv().Return(&pb.DownloadBinaryResponse{
+			Part: &pb.DownloadBinaryResponse_Info{
+				Info: &pb.UserBinaryRecord{Prompt: b.Prompt, Note: b.Note, TimeStamp: b.TimeStamp},
+			},
+		}, nil),
+		stream.EXPECT().Recv().Return(&pb.DownloadBinaryResponse{
+			Part: &pb.DownloadBinaryResponse_Chunk{Chunk: b.Data},
+		}, nil),
+		stream.EXPECT().Recv().Return(&pb.DownloadBinaryResponse{
+			Part: &pb.DownloadBinaryResponse_Checksum{Checksum: sum[:]},
+		}, nil),
+		stream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+	return mcli.EXPECT().DownloadBinary(ctx, &pb.DownloadBinaryRequest{Prompt: b.Prompt}).Return(stream, nil)
+}
+
+func writeTestSshKey(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	err := os.WriteFile(path, []byte(testSshPrivateKey), 0600)
+	require.NoError(t, err)
+	return path
+}
+
+func TestLoadSshKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := mocks.NewMockRepositorier(ctrl)
+
+	k, err := encryptSshKey(testUserSshKey)
+	require.NoError(t, err)
+	m.EXPECT().GetUserSshKeysAfterTime(context.Background(), "", gomock.Any()).
+		Return([]storage.SshKey{k}, nil)
+
+	keys, err := loadSshKeys(m)(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "note", keys[0].Comment)
+}
+
+func TestWatchChanges(t *testing.T) {
+	md := metadata.New(map[string]string{authorizer.AccessToken: "token"})
+	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
+	sshKeyKey := &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_SSH_KEY, Prompt: testSshKey.Prompt}
+	binaryKey := &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: testBinaryRecord.Prompt}
+
+	tests := []struct {
+		name    string
+		prepare func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient, stream *mocks.MockInfoKeeper_WatchChangesClient)
+		wantErr bool
+	}{
+		{
+			name: "ok watch test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient, stream *mocks.MockInfoKeeper_WatchChangesClient) {
+				gomock.InOrder(
+					mcli.EXPECT().WatchChanges(ctxMd, &pb.WatchChangesRequest{}).Return(stream, nil),
+					stream.EXPECT().Recv().Return(&pb.ChangeEvent{Key: sshKeyKey, TimeStamp: testTime}, nil),
+					mcli.EXPECT().GetRecord(ctxMd, &pb.GetRecordRequest{Key: sshKeyKey}).
+						Return(&pb.GetRecordResponse{Record: &pb.Record{
+							Type:    pb.RecordType_RECORD_TYPE_SSH_KEY,
+							Payload: &pb.Record_SshKey{SshKey: sshKeyToPb(testSshKey)},
+						}}, nil),
+					m.EXPECT().SaveServerRecord(context.Background(), "user", testSshKey).Return(nil),
+					stream.EXPECT().Recv().Return(&pb.ChangeEvent{Key: binaryKey, TimeStamp: testTime}, nil),
+					expectDownload(t, mcli, ctxMd, testBinaryRecord),
+					m.EXPECT().SaveServerRecord(context.Background(), "user", gomock.Any()).Return(nil),
+					stream.EXPECT().Recv().Return(nil, io.EOF),
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "subscribe error test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient, stream *mocks.MockInfoKeeper_WatchChangesClient) {
+				mcli.EXPECT().WatchChanges(ctxMd, &pb.WatchChangesRequest{}).Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "get record error test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient, stream *mocks.MockInfoKeeper_WatchChangesClient) {
+				gomock.InOrder(
+					mcli.EXPECT().WatchChanges(ctxMd, &pb.WatchChangesRequest{}).Return(stream, nil),
+					stream.EXPECT().Recv().Return(&pb.ChangeEvent{Key: sshKeyKey, TimeStamp: testTime}, nil),
+					mcli.EXPECT().GetRecord(ctxMd, &pb.GetRecordRequest{Key: sshKeyKey}).
+						Return(nil, errors.New("error")),
+				)
+			},
+			wantErr: true,
+		},
+		{
+			name: "recv error test",
+			prepare: func(m *mocks.MockRepositorier, mcli *mocks.MockInfoKeeperClient, stream *mocks.MockInfoKeeper_WatchChangesClient) {
+				gomock.InOrder(
+					mcli.EXPECT().WatchChanges(ctxMd, &pb.WatchChangesRequest{}).Return(stream, nil),
+					stream.EXPECT().Recv().Return(nil, errors.New("error")),
+				)
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mocks.NewMockRepositorier(ctrl)
+			mcli := mocks.NewMockInfoKeeperClient(ctrl)
+			stream := mocks.NewMockInfoKeeper_WatchChangesClient(ctrl)
+			test.prepare(m, mcli, stream)
+
+			err := WatchChanges(context.Background(), mcli, m, "user", "token")
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestResolveCommand(t *testing.T) {
+	assert.Equal(t, "--resolve -y=text -p=prompt -c=local|server",
+		resolveCommand(&pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt}))
+	assert.Equal(t, "--resolve -y=card -n=123 -c=local|server",
+		resolveCommand(&pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number}))
+}
+
+func TestRecordKeyText(t *testing.T) {
+	assert.Equal(t, "card 123",
+		recordKeyText(&pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: testCard.Number}))
+	assert.Equal(t, "text prompt",
+		recordKeyText(&pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: testTextRecord.Prompt}))
+}
+
+func TestDeviceUnaryInterceptor(t *testing.T) {
+	DeviceName = "laptop"
+	err := DeviceUnaryInterceptor(context.Background(), "method", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, ok := metadata.FromOutgoingContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, []string{"laptop"}, md.Get(authorizer.DeviceName))
+			return nil
+		})
+	assert.NoError(t, err)
+}
+
+func TestIdempotencyUnaryInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		errs      []error
+		wantCalls int
+		wantKey   bool
+		wantCode  codes.Code
+	}{
+		{
+			name:      "ok write test",
+			method:    pb.InfoKeeper_AddRecord_FullMethodName,
+			errs:      []error{nil},
+			wantCalls: 1,
+			wantKey:   true,
+			wantCode:  codes.OK,
+		},
+		{
+			name:   "retry unavailable test",
+			method: pb.InfoKeeper_SyncUserData_FullMethodName,
+			errs: []error{status.Error(codes.Unavailable, "unavailable"),
+				status.Error(codes.Unavailable, "unavailable"), nil},
+			wantCalls: 3,
+			wantKey:   true,
+			wantCode:  codes.OK,
+		},
+		{
+			name:   "attempts exceeded test",
+			method: pb.InfoKeeper_AddCard_FullMethodName,
+			errs: []error{status.Error(codes.Unavailable, "unavailable"),
+				status.Error(codes.Unavailable, "unavailable"), status.Error(codes.Unavailable, "unavailable")},
+			wantCalls: 3,
+			wantKey:   true,
+			wantCode:  codes.Unavailable,
+		},
+		{
+			name:      "no retry test",
+			method:    pb.InfoKeeper_AddCard_FullMethodName,
+			errs:      []error{status.Error(codes.AlreadyExists, "exists")},
+			wantCalls: 1,
+			wantKey:   true,
+			wantCode:  codes.AlreadyExists,
+		},
+		{
+			name:      "read method test",
+			method:    pb.InfoKeeper_GetRecord_FullMethodName,
+			errs:      []error{status.Error(codes.Unavailable, "unavailable")},
+			wantCalls: 1,
+			wantKey:   false,
+			wantCode:  codes.Unavailable,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			keys := map[string]bool{}
+			err := IdempotencyUnaryInterceptor(context.Background(), test.method, nil, nil, nil,
+				func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+					md, _ := metadata.FromOutgoingContext(ctx)
+					for _, k := range md.Get(authorizer.IdempotencyKey) {
+						keys[k] = true
+					}
+					err := test.errs[calls]
+					calls++
+					return err
+				})
+			assert.Equal(t, test.wantCode, status.Code(err))
+			assert.Equal(t, test.wantCalls, calls)
+			if test.wantKey {
+				assert.Len(t, keys, 1)
+			} else {
+				assert.Empty(t, keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package cmdexecutor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/customrecord"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/labels"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// UserTemplate хранит шаблон пользовательских записей.
+type UserTemplate struct {
+	Name      string
+	Fields    []customrecord.Field
+	Tags      []string
+	Folder    string
+	TimeStamp string
+}
+
+func (t UserTemplate) template() customrecord.Template {
+	return customrecord.Template{Name: t.Name, Fields: t.Fields}
+}
+
+// Templates используется для вывода результата пользователю.
+type Templates []UserTemplate
+
+// PrintData используется для вывода результата пользователю.
+func (t Templates) PrintData() {
+	fmt.Println("TEMPLATE")
+	for _, v := range t {
+		fmt.Println("Name: ", v.Name)
+		for _, f := range v.Fields {
+			if f.Secret {
+				fmt.Printf("Field: %s (%s, secret)\n", f.Name, f.Type)
+			} else {
+				fmt.Printf("Field: %s (%s)\n", f.Name, f.Type)
+			}
+		}
+		fmt.Println("Tags: ", strings.Join(v.Tags, ", "))
+		fmt.Println("Folder: ", v.Folder)
+		fmt.Println("Time Stamp: ", v.TimeStamp)
+	}
+}
+
+// UserCustomRecord хранит пользовательскую запись.
+// Значения секретных полей скрыты, если пользователь не запросил их показ.
+type UserCustomRecord struct {
+	Prompt    string
+	Template  string
+	Values    []customrecord.Value
+	Note      string
+	Tags      []string
+	Folder    string
+	TimeStamp string
+}
+
+// CustomRecords используется для вывода результата пользователю.
+type CustomRecords []UserCustomRecord
+
+// PrintData используется для вывода результата пользователю.
+func (c CustomRecords) PrintData() {
+	fmt.Println("CUSTOM RECORD")
+	for _, v := range c {
+		fmt.Println("Prompt: ", v.Prompt)
+		fmt.Println("Template: ", v.Template)
+		for _, f := range v.Values {
+			fmt.Printf("%s: %s\n", f.Name, f.Value)
+		}
+		fmt.Println("Note: ", v.Note)
+		fmt.Println("Tags: ", strings.Join(v.Tags, ", "))
+		fmt.Println("Folder: ", v.Folder)
+		fmt.Println("Time Stamp: ", v.TimeStamp)
+	}
+}
+
+// customData хранит расшифрованные данные пользовательской записи.
+type customData struct {
+	Prompt    string
+	Template  string
+	Values    map[string]string
+	Note      string
+	Tags      []string
+	Folder    string
+	TimeStamp string
+}
+
+var addTemplateExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	fields, err := customrecord.ParseFields(args.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := encryptTemplate(UserTemplate{
+		Name:      args.Template,
+		Fields:    fields,
+		TimeStamp: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.AddTemplate(ctx, UserLogin, t)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+var getTemplatesExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	ts, err := repo.GetUserTemplatesAfterTime(ctx, UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+
+	filter := labels.Filter{Tags: args.Tags, Folder: args.Folder}
+	res := make(Templates, 0, len(ts))
+	for _, v := range ts {
+		ut, err := decryptTemplate(v)
+		if err != nil {
+			return nil, err
+		}
+		if !filter.Match(ut.Tags, ut.Folder) {
+			continue
+		}
+		res = append(res, ut)
+	}
+
+	return res, nil
+}
+
+// getUserTemplate получает и расшифровывает шаблон по имени.
+func getUserTemplate(ctx context.Context, repo storage.Repositorier, name string) (UserTemplate, error) {
+	enN, err := cryptor.EncryptsString(name)
+	if err != nil {
+		return UserTemplate{}, err
+	}
+
+	t, err := repo.GetTemplate(ctx, UserLogin, enN)
+	if err != nil {
+		return UserTemplate{}, err
+	}
+
+	return decryptTemplate(t)
+}
+
+var addCustomExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	ut, err := getUserTemplate(ctx, repo, args.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := customrecord.ParseValues(args.Fields)
+	if err != nil {
+		return nil, err
+	}
+	err = ut.template().Validate(values)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := encryptCustomRecord(customData{
+		Prompt:    args.Prompt,
+		Template:  args.Template,
+		Values:    values,
+		Note:      args.Note,
+		TimeStamp: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.AddCustomRecord(ctx, UserLogin, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// toUserCustomRecord расшифровывает запись и подготавливает значения полей к выводу.
+func toUserCustomRecord(ctx context.Context, repo storage.Repositorier, r storage.CustomRecord, reveal bool) (UserCustomRecord, error) {
+	d, err := decryptCustomRecord(r)
+	if err != nil {
+		return UserCustomRecord{}, err
+	}
+
+	ut, err := getUserTemplate(ctx, repo, d.Template)
+	if err != nil {
+		return UserCustomRecord{}, err
+	}
+
+	return UserCustomRecord{
+		Prompt:    d.Prompt,
+		Template:  d.Template,
+		Values:    ut.template().Display(d.Values, reveal),
+		Note:      d.Note,
+		Tags:      d.Tags,
+		Folder:    d.Folder,
+		TimeStamp: d.TimeStamp,
+	}, nil
+}
+
+var getCustomExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	enP, err := cryptor.EncryptsString(args.Prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := repo.GetCustomRecord(ctx, UserLogin, enP)
+	if err != nil {
+		return nil, err
+	}
+
+	uc, err := toUserCustomRecord(ctx, repo, r, args.Reveal)
+	if err != nil {
+		return nil, err
+	}
+
+	return CustomRecords{uc}, nil
+}
+
+var getCustomsExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	rs, err := repo.GetUserCustomRecordsAfterTime(ctx, UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+
+	filter := labels.Filter{Tags: args.Tags, Folder: args.Folder}
+	res := make(CustomRecords, 0, len(rs))
+	for _, v := range rs {
+		uc, err := toUserCustomRecord(ctx, repo, v, args.Reveal)
+		if err != nil {
+			return nil, err
+		}
+		if !filter.Match(uc.Tags, uc.Folder) {
+			continue
+		}
+		res = append(res, uc)
+	}
+
+	return res, nil
+}
@@ -11,12 +11,23 @@
 
 Переменные вида get*ServerExec содержат функцию для получения соответствующих данных с сервера.
 
+Переменные setTagsExec и moveExec содержат функции для изменения тегов и папки записи.
+
+Переменные historyExec и restoreExec содержат функции для получения и восстановления
+предыдущих версий записи на сервере.
+
+Переменная resolveExec содержит функцию для разрешения конфликта записи при синхронизации.
+
 Переменная regExec содержит функцию для регистрации пользователя.
 
 Переменная authExec содержит функцию для аутентификации пользователя.
 
 Переменная exitExec содержит функцию для выхода из приложения.
 
+Функция ServeSshAgent запускает ssh-agent с ключами SSH пользователя.
+
+Функция WatchChanges сохраняет в локальную БД записи, измененные на сервере.
+
 Переменная verExec предоставляет пользователю информацию о версии и сборке приложения.
 
 Файл tools содержит функции для конвертации между разными типами информации одного вида.
@@ -0,0 +1,244 @@
+package cmdexecutor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// EmergencyContact хранит доверенное лицо и состояние его запроса доступа.
+type EmergencyContact struct {
+	Grantor     string
+	Grantee     string
+	Wait        time.Duration
+	RequestedAt string
+	AvailableAt string
+	Granted     bool
+}
+
+// EmergencyContacts используется для вывода результата пользователю.
+type EmergencyContacts []EmergencyContact
+
+// PrintData используется для вывода результата пользователю.
+func (c EmergencyContacts) PrintData() {
+	fmt.Println("EMERGENCY CONTACTS")
+	for _, v := range c {
+		fmt.Println("Owner: ", v.Grantor)
+		fmt.Println("Trusted contact: ", v.Grantee)
+		fmt.Println("Waiting period: ", v.Wait)
+		if v.RequestedAt != "" {
+			fmt.Println("Requested at: ", v.RequestedAt)
+			fmt.Println("Available at: ", v.AvailableAt)
+		}
+		fmt.Println("Granted: ", v.Granted)
+	}
+}
+
+// EmergencyRequest хранит время, после которого доступ будет предоставлен.
+type EmergencyRequest struct {
+	Grantor     string
+	AvailableAt string
+}
+
+// PrintData используется для вывода результата пользователю.
+func (r EmergencyRequest) PrintData() {
+	fmt.Println("EMERGENCY ACCESS REQUESTED")
+	fmt.Println("Owner: ", r.Grantor)
+	fmt.Println("Available at: ", r.AvailableAt)
+}
+
+// EmergencyVault хранит записи владельца, полученные доверенным лицом.
+type EmergencyVault struct {
+	Grantor string
+	Records SharedRecords
+}
+
+// PrintData используется для вывода результата пользователю.
+func (v EmergencyVault) PrintData() {
+	fmt.Println("EMERGENCY ACCESS GRANTED")
+	fmt.Println("Owner: ", v.Grantor)
+	v.Records.PrintData()
+}
+
+// emergencyRecords шифрует ключом key копию всех локальных записей пользователя для доверенного лица.
+func emergencyRecords(ctx context.Context, repo storage.Repositorier, key []byte) ([]*pb.SharedRecord, error) {
+	local, err := repo.GetUserRecords(ctx, UserLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*pb.SharedRecord, 0, len(local))
+	for _, r := range local {
+		rec, err := recordToPb(r)
+		if err != nil {
+			return nil, err
+		}
+		sealed, err := sealRecord(rec, key)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, sealed)
+	}
+	return res, nil
+}
+
+// sealEmergency шифрует копию локальных записей новым случайным ключом экстренного доступа,
+// а ключ - открытым ключом доверенного лица grantee.
+func sealEmergency(ctx context.Context, cl pb.InfoKeeperClient, repo storage.Repositorier,
+	grantee string) (wrapped []byte, records []*pb.SharedRecord, err error) {
+	r, err := cl.GetPublicKey(orgCtx(ctx), &pb.GetPublicKeyRequest{Login: grantee})
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := cryptor.GenerateEmergencyKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	wrapped, err = cryptor.WrapKey(r.GetPublicKey(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+	records, err = emergencyRecords(ctx, repo, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return wrapped, records, nil
+}
+
+// refreshEmergency заменяет копию записей у всех доверенных лиц пользователя
+// текущими локальными записями. Ошибки возвращаются как ошибки синхронизации,
+// чтобы не отменять уже выполненную синхронизацию.
+func refreshEmergency(ctx context.Context, cl pb.InfoKeeperClient, repo storage.Repositorier) SyncErrs {
+	res, err := cl.ListEmergencyContacts(orgCtx(ctx), &pb.ListEmergencyContactsRequest{})
+	if err != nil {
+		return SyncErrs{emergencySyncErr("", err)}
+	}
+
+	var errs SyncErrs
+	for _, c := range res.GetContacts() {
+		if c.GetGrantor() != UserLogin {
+			continue
+		}
+		wrapped, records, err := sealEmergency(ctx, cl, repo, c.GetGrantee())
+		if err == nil {
+			_, err = cl.UpdateEmergencyRecords(orgCtx(ctx), &pb.UpdateEmergencyRecordsRequest{
+				Login:      c.GetGrantee(),
+				WrappedKey: wrapped,
+				Records:    records,
+			})
+		}
+		if err != nil {
+			errs = append(errs, emergencySyncErr(c.GetGrantee(), err))
+		}
+	}
+	return errs
+}
+
+func emergencySyncErr(grantee string, err error) SyncErr {
+	return SyncErr{
+		Text:   "error updating emergency access for trusted contact ",
+		Value:  grantee,
+		ErrMsg: err.Error(),
+		Code:   pb.SyncErrorCode_SYNC_ERROR_CODE_INTERNAL,
+	}
+}
+
+var addEmergencyExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	if args.Member == "" {
+		return nil, errors.New("empty trusted contact login")
+	}
+	if args.Wait < 0 {
+		return nil, errors.New("negative waiting period")
+	}
+	wrapped, records, err := sealEmergency(ctx, cl, repo, args.Member)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = cl.AddEmergencyContact(orgCtx(ctx), &pb.AddEmergencyContactRequest{
+		Login:       args.Member,
+		WaitSeconds: int64(time.Duration(args.Wait) * time.Hour / time.Second),
+		WrappedKey:  wrapped,
+		Records:     records,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+var removeEmergencyExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	_, err := cl.RemoveEmergencyContact(orgCtx(ctx), &pb.RemoveEmergencyContactRequest{Login: args.Member})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+var declineEmergencyExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	_, err := cl.DeclineEmergencyAccess(orgCtx(ctx), &pb.DeclineEmergencyAccessRequest{Login: args.Member})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+var getEmergencyExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	r, err := cl.ListEmergencyContacts(orgCtx(ctx), &pb.ListEmergencyContactsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(EmergencyContacts, 0, len(r.GetContacts()))
+	for _, c := range r.GetContacts() {
+		res = append(res, EmergencyContact{
+			Grantor:     c.GetGrantor(),
+			Grantee:     c.GetGrantee(),
+			Wait:        time.Duration(c.GetWaitSeconds()) * time.Second,
+			RequestedAt: c.GetRequestedAt(),
+			AvailableAt: c.GetAvailableAt(),
+			Granted:     c.GetGranted(),
+		})
+	}
+
+	return res, nil
+}
+
+var requestEmergencyExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	r, err := cl.RequestEmergencyAccess(orgCtx(ctx), &pb.RequestEmergencyAccessRequest{Grantor: args.Member})
+	if err != nil {
+		return nil, err
+	}
+
+	return EmergencyRequest{Grantor: args.Member, AvailableAt: r.GetAvailableAt()}, nil
+}
+
+var emergencyKeyExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	r, err := cl.GetEmergencyKey(orgCtx(ctx), &pb.GetEmergencyKeyRequest{Grantor: args.Member})
+	if err != nil {
+		return nil, err
+	}
+	pub, priv, err := userKeys(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+	key, err := cryptor.UnwrapKey(pub, priv, r.GetWrappedKey())
+	if err != nil {
+		return nil, err
+	}
+	records, err := openRecords(ctx, repo, key, r.GetRecords(), args.Reveal)
+	if err != nil {
+		return nil, err
+	}
+
+	return EmergencyVault{Grantor: args.Member, Records: records}, nil
+}
@@ -0,0 +1,55 @@
+package cmdexecutor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+	"github.com/Julia-ivv/info-keeper.git/pkg/randomizer"
+)
+
+const (
+	idempotencyKeyLength = 16
+	// writeAttempts - количество попыток выполнить запрос на запись, если сервер недоступен.
+	writeAttempts = 3
+)
+
+// idempotentMethods - методы, которым передается ключ идемпотентности.
+var idempotentMethods = map[string]bool{
+	pb.InfoKeeper_AddCard_FullMethodName:                 true,
+	pb.InfoKeeper_AddLogin_FullMethodName:                true,
+	pb.InfoKeeper_AddTextData_FullMethodName:             true,
+	pb.InfoKeeper_AddBinaryData_FullMethodName:           true,
+	pb.InfoKeeper_AddRecord_FullMethodName:               true,
+	pb.InfoKeeper_ForceUpdateCard_FullMethodName:         true,
+	pb.InfoKeeper_ForceUpdateLoginPwd_FullMethodName:     true,
+	pb.InfoKeeper_ForceUpdateTextRecord_FullMethodName:   true,
+	pb.InfoKeeper_ForceUpdateBinaryRecord_FullMethodName: true,
+	pb.InfoKeeper_ForceUpdateRecord_FullMethodName:       true,
+	pb.InfoKeeper_SyncUserData_FullMethodName:            true,
+}
+
+// IdempotencyUnaryInterceptor передает серверу ключ идемпотентности в запросах на запись
+// и повторяет запрос с тем же ключом, если сервер недоступен.
+func IdempotencyUnaryInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if !idempotentMethods[method] {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	key, err := randomizer.GenerateRandomString(idempotencyKeyLength)
+	if err != nil {
+		return err
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, authorizer.IdempotencyKey, key)
+	for i := 1; ; i++ {
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		if status.Code(err) != codes.Unavailable || i == writeAttempts || ctx.Err() != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package cmdexecutor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/labels"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// recordKey возвращает запись с зашифрованными ключевыми полями
+// для типа записи, указанного пользователем.
+func recordKey(args cmdparser.UserArgs) (any, error) {
+	enA, err := encryptArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	switch args.RecordType {
+	case "card":
+		return storage.Card{Number: enA.CardNumber}, nil
+	case "login":
+		return storage.LoginPwd{Prompt: enA.Prompt, Login: enA.Login}, nil
+	case "text":
+		return storage.TextRecord{Prompt: enA.Prompt}, nil
+	case "binary":
+		return storage.BinaryRecord{Prompt: enA.Prompt}, nil
+	case "otp":
+		return storage.Otp{Issuer: enA.Prompt, Account: enA.Login}, nil
+	case "ssh":
+		return storage.SshKey{Prompt: enA.Prompt}, nil
+	case "template":
+		enN, err := cryptor.EncryptsString(args.Template)
+		if err != nil {
+			return nil, err
+		}
+		return storage.Template{Name: enN}, nil
+	case "custom":
+		return storage.CustomRecord{Prompt: enA.Prompt}, nil
+	default:
+		return nil, fmt.Errorf("unknown record type %q", args.RecordType)
+	}
+}
+
+var setTagsExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	key, err := recordKey(args)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := labels.NormalizeTags(args.Tags)
+	if err != nil {
+		return nil, err
+	}
+	enT, err := encryptTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.UpdateLabel(ctx, UserLogin, key, storage.LabelTags, enT, time.Now().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+var moveExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	key, err := recordKey(args)
+	if err != nil {
+		return nil, err
+	}
+	enF, err := encryptFolder(labels.NormalizeFolder(args.Folder))
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.UpdateLabel(ctx, UserLogin, key, storage.LabelFolder, enF, time.Now().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
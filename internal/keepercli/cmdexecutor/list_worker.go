@@ -0,0 +1,102 @@
+package cmdexecutor
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// RecordInfo хранит информацию о записи на сервере без ее данных.
+type RecordInfo struct {
+	Type      string
+	Prompt    string
+	TimeStamp string
+	Size      int64
+}
+
+// RecordInfos используется для вывода результата пользователю.
+type RecordInfos []RecordInfo
+
+// PrintData используется для вывода результата пользователю.
+func (r RecordInfos) PrintData() {
+	fmt.Println("SERVER RECORDS")
+	for _, v := range r {
+		fmt.Println("Type: ", v.Type)
+		fmt.Println("Prompt: ", v.Prompt)
+		fmt.Println("Time Stamp: ", v.TimeStamp)
+		fmt.Println("Size: ", v.Size)
+	}
+}
+
+var recordTypeNames = map[pb.RecordType]string{
+	pb.RecordType_RECORD_TYPE_CARD:      "card",
+	pb.RecordType_RECORD_TYPE_LOGIN_PWD: "login-password",
+	pb.RecordType_RECORD_TYPE_TEXT:      "text",
+	pb.RecordType_RECORD_TYPE_BINARY:    "binary",
+	pb.RecordType_RECORD_TYPE_OTP:       "one-time password",
+	pb.RecordType_RECORD_TYPE_SSH_KEY:   "ssh key",
+	pb.RecordType_RECORD_TYPE_TEMPLATE:  "template",
+	pb.RecordType_RECORD_TYPE_CUSTOM:    "custom",
+}
+
+// listServerRecords получает с сервера все страницы списка записей указанного типа.
+func listServerRecords(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, t pb.RecordType) (DataPrinter, error) {
+	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+
+	res := make(RecordInfos, 0)
+	var token string
+	for {
+		r, err := cl.ListRecords(ctxMd, &pb.ListRecordsRequest{
+			Type:          t,
+			ModifiedSince: args.Since,
+			PageToken:     token,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range r.GetRecords() {
+			p, err := cryptor.Decrypts(v.GetPrompt())
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, RecordInfo{
+				Type:      recordTypeNames[v.GetType()],
+				Prompt:    p,
+				TimeStamp: v.GetTimeStamp(),
+				Size:      v.GetSize(),
+			})
+		}
+
+		token = r.GetNextPageToken()
+		if token == "" {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+var getCardsServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	return listServerRecords(ctx, args, cl, pb.RecordType_RECORD_TYPE_CARD)
+}
+
+var getLoginsServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	return listServerRecords(ctx, args, cl, pb.RecordType_RECORD_TYPE_LOGIN_PWD)
+}
+
+var getTextsServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	return listServerRecords(ctx, args, cl, pb.RecordType_RECORD_TYPE_TEXT)
+}
+
+var getBinarysServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	return listServerRecords(ctx, args, cl, pb.RecordType_RECORD_TYPE_BINARY)
+}
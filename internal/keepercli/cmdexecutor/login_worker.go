@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"gitlab.com/david_mbuvi/go_asterisks"
@@ -12,6 +13,7 @@ import (
 	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/labels"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
 	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
 )
@@ -22,6 +24,8 @@ type UserLoginPwd struct {
 	Login     string
 	Pwd       string
 	Note      string
+	Tags      []string
+	Folder    string
 	TimeStamp string
 }
 
@@ -36,11 +40,13 @@ func (l LoginPwds) PrintData() {
 		fmt.Println("Login: ", v.Login)
 		fmt.Println("Pwd: ", v.Pwd)
 		fmt.Println("Note: ", v.Note)
+		fmt.Println("Tags: ", strings.Join(v.Tags, ", "))
+		fmt.Println("Folder: ", v.Folder)
 		fmt.Println("Time Stamp: ", v.TimeStamp)
 	}
 }
 
-var addLoginExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var addLoginExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	fmt.Print("Enter password: ")
 	pwd, err := go_asterisks.GetUsersPassword("", true, os.Stdin, os.Stdout)
 	if err != nil {
@@ -53,7 +59,7 @@ var addLoginExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo st
 		return nil, err
 	}
 
-	err = repo.AddLoginPwd(context.Background(), UserLogin, enA.Prompt, enA.Login, enA.Pwd, enA.Note, time.Now().Format(time.RFC3339))
+	err = repo.AddLoginPwd(ctx, UserLogin, enA.Prompt, enA.Login, enA.Pwd, enA.Note, time.Now().Format(time.RFC3339))
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +67,7 @@ var addLoginExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo st
 	return nil, nil
 }
 
-var updLoginExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var updLoginExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	fmt.Print("Enter password: ")
 	pwd, err := go_asterisks.GetUsersPassword("", true, os.Stdin, os.Stdout)
 	if err != nil {
@@ -74,7 +80,7 @@ var updLoginExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo st
 		return nil, err
 	}
 
-	err = repo.UpdateLoginPwd(context.Background(), UserLogin, enA.Prompt, enA.Login, enA.Pwd, enA.Note, time.Now().Format(time.RFC3339))
+	err = repo.UpdateLoginPwd(ctx, UserLogin, enA.Prompt, enA.Login, enA.Pwd, enA.Note, time.Now().Format(time.RFC3339))
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +88,7 @@ var updLoginExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo st
 	return nil, nil
 }
 
-var getLoginExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var getLoginExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enP, err := cryptor.EncryptsString(args.Prompt)
 	if err != nil {
 		return nil, err
@@ -92,7 +98,7 @@ var getLoginExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo st
 		return nil, err
 	}
 
-	l, err := repo.GetLoginPwd(context.Background(), UserLogin, enP, enL)
+	l, err := repo.GetLoginPwd(ctx, UserLogin, enP, enL)
 	if err != nil {
 		return nil, err
 	}
@@ -108,25 +114,29 @@ var getLoginExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo st
 	return res, nil
 }
 
-var getLoginsExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
-	ls, err := repo.GetUserLoginsPwdsAfterTime(context.Background(), UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
+var getLoginsExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	ls, err := repo.GetUserLoginsPwdsAfterTime(ctx, UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
 	if err != nil {
 		return nil, err
 	}
 
+	filter := labels.Filter{Tags: args.Tags, Folder: args.Folder}
 	res := make(LoginPwds, 0, len(ls))
 	for _, v := range ls {
 		l, err := decryptLoginPwd(v)
 		if err != nil {
 			return nil, err
 		}
+		if !filter.Match(l.Tags, l.Folder) {
+			continue
+		}
 		res = append(res, l)
 	}
 
 	return res, nil
 }
 
-var forceAddLoginServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var forceAddLoginServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enP, err := cryptor.EncryptsString(args.Prompt)
 	if err != nil {
 		return nil, err
@@ -136,15 +146,23 @@ var forceAddLoginServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClie
 		return nil, err
 	}
 
-	l, err := repo.GetLoginPwd(context.Background(), UserLogin, enP, enL)
+	l, err := repo.GetLoginPwd(ctx, UserLogin, enP, enL)
 	if err != nil {
 		return nil, err
 	}
 
 	lPb := loginToPb(l)
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
-	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
-	_, err = cl.ForceUpdateLoginPwd(ctxMd, &pb.ForceUpdateLoginPwdRequest{LoginPwd: lPb})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	resp, err := cl.ForceUpdateRecord(ctxMd, &pb.ForceUpdateRecordRequest{Record: &pb.Record{
+		Type:    pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+		Payload: &pb.Record_LoginPwd{LoginPwd: lPb},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	err = saveRevision(ctx, repo,
+		&pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_LOGIN_PWD, Prompt: l.Prompt, Key: l.Login}, resp.GetRevision())
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +170,7 @@ var forceAddLoginServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClie
 	return nil, nil
 }
 
-var getLoginServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var getLoginServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enP, err := cryptor.EncryptsString(args.Prompt)
 	if err != nil {
 		return nil, err
@@ -163,13 +181,17 @@ var getLoginServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, r
 	}
 
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
-	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
-	r, err := cl.GetUserLogin(ctxMd, &pb.GetUserLoginRequest{Prompt: enP, Login: enL})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	r, err := cl.GetRecord(ctxMd, &pb.GetRecordRequest{Key: &pb.RecordKey{
+		Type:   pb.RecordType_RECORD_TYPE_LOGIN_PWD,
+		Prompt: enP,
+		Key:    enL,
+	}})
 	if err != nil {
 		return nil, err
 	}
 
-	l := pbToLogin(r.LoginPwd)
+	l := pbToLogin(r.GetRecord().GetLoginPwd())
 	deL, err := decryptLoginPwd(l)
 	if err != nil {
 		return nil, err
@@ -181,6 +203,8 @@ var getLoginServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, r
 		Login:     deL.Login,
 		Pwd:       deL.Pwd,
 		Note:      deL.Note,
+		Tags:      deL.Tags,
+		Folder:    deL.Folder,
 		TimeStamp: deL.TimeStamp,
 	})
 
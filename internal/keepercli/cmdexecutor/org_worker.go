@@ -0,0 +1,384 @@
+package cmdexecutor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// memberRoles - роли участников коллекций, доступные пользователю.
+var memberRoles = map[string]pb.MemberRole{
+	"owner":  pb.MemberRole_MEMBER_ROLE_OWNER,
+	"editor": pb.MemberRole_MEMBER_ROLE_EDITOR,
+	"viewer": pb.MemberRole_MEMBER_ROLE_VIEWER,
+}
+
+// roleName возвращает название роли участника для вывода пользователю.
+func roleName(role pb.MemberRole) string {
+	for k, v := range memberRoles {
+		if v == role {
+			return k
+		}
+	}
+	return "unknown"
+}
+
+// Organization хранит созданную организацию.
+type Organization struct {
+	ID   int64
+	Name string
+}
+
+// PrintData используется для вывода результата пользователю.
+func (o Organization) PrintData() {
+	fmt.Println("ORGANIZATION")
+	fmt.Println("ID: ", o.ID)
+	fmt.Println("Name: ", o.Name)
+}
+
+// Collection хранит общую коллекцию и роль в ней пользователя.
+type Collection struct {
+	ID           int64
+	OrgID        int64
+	Organization string
+	Name         string
+	Role         string
+}
+
+// Collections используется для вывода результата пользователю.
+type Collections []Collection
+
+// PrintData используется для вывода результата пользователю.
+func (c Collections) PrintData() {
+	fmt.Println("SHARED COLLECTIONS")
+	for _, v := range c {
+		fmt.Println("ID: ", v.ID)
+		fmt.Println("Organization: ", v.Organization, "(", v.OrgID, ")")
+		fmt.Println("Name: ", v.Name)
+		fmt.Println("Role: ", v.Role)
+	}
+}
+
+// SharedRecords используется для вывода записей общей коллекции пользователю.
+type SharedRecords []DataPrinter
+
+// PrintData используется для вывода результата пользователю.
+func (r SharedRecords) PrintData() {
+	fmt.Println("SHARED RECORDS")
+	for _, v := range r {
+		v.PrintData()
+	}
+}
+
+// orgCtx возвращает контекст запроса к серверу с токеном пользователя.
+func orgCtx(ctx context.Context) context.Context {
+	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// userKeys получает пару ключей пользователя с сервера и расшифровывает закрытый ключ.
+func userKeys(ctx context.Context, cl pb.InfoKeeperClient) (publicKey []byte, privateKey []byte, err error) {
+	r, err := cl.GetUserKeys(orgCtx(ctx), &pb.GetUserKeysRequest{})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil, errors.New("no keys for shared collections, run --initkeys first")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateKey, err = cryptor.DecryptsInByte(r.GetKeys().GetPrivateKey())
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.GetKeys().GetPublicKey(), privateKey, nil
+}
+
+// collectionKey получает ключ коллекции с идентификатором id, расшифрованный ключами пользователя.
+func collectionKey(ctx context.Context, cl pb.InfoKeeperClient, id int64) ([]byte, error) {
+	r, err := cl.ListCollections(orgCtx(ctx), &pb.ListCollectionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range r.GetCollections() {
+		if c.GetId() != id {
+			continue
+		}
+		pub, priv, err := userKeys(ctx, cl)
+		if err != nil {
+			return nil, err
+		}
+		return cryptor.UnwrapKey(pub, priv, c.GetWrappedKey())
+	}
+	return nil, fmt.Errorf("collection %d not found", id)
+}
+
+// convertBytes заменяет непустые байтовые поля сообщения m результатом функции f.
+func convertBytes(m protoreflect.Message, f func([]byte) ([]byte, error)) (err error) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap():
+			err = convertBytes(v.Message(), f)
+		case fd.Kind() == protoreflect.BytesKind && !fd.IsList() && !fd.IsMap() && len(v.Bytes()) > 0:
+			var b []byte
+			b, err = f(v.Bytes())
+			if err == nil {
+				m.Set(fd, protoreflect.ValueOfBytes(b))
+			}
+		}
+		return err == nil
+	})
+	return err
+}
+
+// withKey возвращает функцию шифрования или дешифрования ключом коллекции key.
+func withKey(fn func(key []byte, data []byte) ([]byte, error), key []byte) func([]byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		return fn(key, data)
+	}
+}
+
+// sealRecord шифрует запись rec ключом key для передачи другим пользователям.
+// Поля записи, зашифрованные ключом пользователя, перешифровываются ключом key,
+// а запись целиком шифруется тем же ключом. rec изменяется.
+func sealRecord(rec *pb.Record, key []byte) (*pb.SharedRecord, error) {
+	err := convertBytes(rec.ProtoReflect(), cryptor.DecryptsInByte)
+	if err != nil {
+		return nil, err
+	}
+	err = convertBytes(rec.ProtoReflect(), withKey(cryptor.EncryptsWithKey, key))
+	if err != nil {
+		return nil, err
+	}
+	data, err := proto.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := &pb.SharedRecord{Type: rec.GetType(), TimeStamp: time.Now().Format(time.RFC3339)}
+	shared.Data, err = cryptor.EncryptsWithKey(key, data)
+	if err != nil {
+		return nil, err
+	}
+	return shared, nil
+}
+
+// openRecords расшифровывает ключом key записи, зашифрованные sealRecord, для вывода пользователю.
+func openRecords(ctx context.Context, repo storage.Repositorier, key []byte,
+	records []*pb.SharedRecord, reveal bool) (SharedRecords, error) {
+	res := make(SharedRecords, 0, len(records))
+	for _, v := range records {
+		data, err := cryptor.DecryptsWithKey(key, v.GetData())
+		if err != nil {
+			return nil, err
+		}
+		var rec pb.Record
+		err = proto.Unmarshal(data, &rec)
+		if err != nil {
+			return nil, err
+		}
+		err = convertBytes(rec.ProtoReflect(), withKey(cryptor.DecryptsWithKey, key))
+		if err != nil {
+			return nil, err
+		}
+		err = convertBytes(rec.ProtoReflect(), cryptor.EncryptsByte)
+		if err != nil {
+			return nil, err
+		}
+
+		local, err := pbToRecord(&rec)
+		if err != nil {
+			return nil, err
+		}
+		p, err := decryptRecord(ctx, repo, local, reveal)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+
+	return res, nil
+}
+
+var initKeysExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	_, err := cl.GetUserKeys(orgCtx(ctx), &pb.GetUserKeysRequest{})
+	if err == nil {
+		return nil, errors.New("keys for shared collections already exist")
+	}
+	if status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	pub, priv, err := cryptor.GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	enPriv, err := cryptor.EncryptsByte(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = cl.SetUserKeys(orgCtx(ctx), &pb.SetUserKeysRequest{
+		Keys: &pb.UserKeys{PublicKey: pub, PrivateKey: enPriv},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+var addOrgExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	r, err := cl.CreateOrganization(orgCtx(ctx), &pb.CreateOrganizationRequest{Name: args.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	return Organization{ID: r.GetOrganization().GetId(), Name: r.GetOrganization().GetName()}, nil
+}
+
+var addCollectionExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	pub, _, err := userKeys(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+	key, err := cryptor.GenerateCollectionKey()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := cryptor.WrapKey(pub, key)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := cl.CreateCollection(orgCtx(ctx), &pb.CreateCollectionRequest{
+		OrgId: args.OrgID, Name: args.Name, WrappedKey: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c := r.GetCollection()
+	return Collections{{
+		ID: c.GetId(), OrgID: c.GetOrgId(), Organization: c.GetOrganization(),
+		Name: c.GetName(), Role: roleName(c.GetRole()),
+	}}, nil
+}
+
+var getCollectionsExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	r, err := cl.ListCollections(orgCtx(ctx), &pb.ListCollectionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(Collections, 0, len(r.GetCollections()))
+	for _, c := range r.GetCollections() {
+		res = append(res, Collection{
+			ID: c.GetId(), OrgID: c.GetOrgId(), Organization: c.GetOrganization(),
+			Name: c.GetName(), Role: roleName(c.GetRole()),
+		})
+	}
+
+	return res, nil
+}
+
+var inviteExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	role, ok := memberRoles[args.Role]
+	if !ok {
+		return nil, fmt.Errorf("unknown role %q, use owner, editor or viewer", args.Role)
+	}
+	key, err := collectionKey(ctx, cl, args.Collection)
+	if err != nil {
+		return nil, err
+	}
+	r, err := cl.GetPublicKey(orgCtx(ctx), &pb.GetPublicKeyRequest{Login: args.Member})
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := cryptor.WrapKey(r.GetPublicKey(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = cl.AddCollectionMember(orgCtx(ctx), &pb.AddCollectionMemberRequest{
+		CollectionId: args.Collection, Login: args.Member, Role: role, WrappedKey: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+var toCollectionExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	localKey, err := recordKey(args)
+	if err != nil {
+		return nil, err
+	}
+	pbKey, err := recordPbKey(args)
+	if err != nil {
+		return nil, err
+	}
+	key, err := collectionKey(ctx, cl, args.Collection)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := cl.GetRecord(orgCtx(ctx), &pb.GetRecordRequest{Key: pbKey})
+	if err != nil {
+		return nil, err
+	}
+	shared, err := sealRecord(r.GetRecord(), key)
+	if err != nil {
+		return nil, err
+	}
+	sharedKey := proto.Clone(pbKey).(*pb.RecordKey)
+	err = convertBytes(sharedKey.ProtoReflect(), cryptor.DecryptsInByte)
+	if err != nil {
+		return nil, err
+	}
+	err = convertBytes(sharedKey.ProtoReflect(), withKey(cryptor.EncryptsWithKey, key))
+	if err != nil {
+		return nil, err
+	}
+	shared.Prompt, shared.Key = sharedKey.GetPrompt(), sharedKey.GetKey()
+
+	_, err = cl.MoveRecordToCollection(orgCtx(ctx), &pb.MoveRecordToCollectionRequest{
+		CollectionId: args.Collection, Source: pbKey, Record: shared,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.DeleteRecord(ctx, UserLogin, localKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+var getSharedExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	key, err := collectionKey(ctx, cl, args.Collection)
+	if err != nil {
+		return nil, err
+	}
+	r, err := cl.ListCollectionRecords(orgCtx(ctx), &pb.ListCollectionRecordsRequest{CollectionId: args.Collection})
+	if err != nil {
+		return nil, err
+	}
+
+	return openRecords(ctx, repo, key, r.GetRecords(), args.Reveal)
+}
@@ -0,0 +1,87 @@
+package cmdexecutor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+	"github.com/Julia-ivv/info-keeper.git/pkg/otp"
+)
+
+// UserOtpCode хранит текущий одноразовый пароль.
+type UserOtpCode struct {
+	Issuer    string
+	Account   string
+	Code      string
+	Remaining time.Duration
+	Note      string
+}
+
+// PrintData используется для вывода результата пользователю.
+func (o UserOtpCode) PrintData() {
+	fmt.Println("ONE-TIME PASSWORD")
+	fmt.Println("Issuer: ", o.Issuer)
+	fmt.Println("Account: ", o.Account)
+	fmt.Println("Code: ", o.Code)
+	fmt.Printf("Expires in: %d s\n", int(o.Remaining.Seconds()))
+	fmt.Println("Note: ", o.Note)
+}
+
+var addOtpExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	k, err := otp.ParseURI(args.OtpURI)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := encryptOtp(k, args.Note)
+	if err != nil {
+		return nil, err
+	}
+	o.TimeStamp = time.Now().Format(time.RFC3339)
+
+	err = repo.AddOtp(ctx, UserLogin, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+var getOtpExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	enI, err := cryptor.EncryptsString(args.Prompt)
+	if err != nil {
+		return nil, err
+	}
+	enA, err := cryptor.EncryptsString(args.Login)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := repo.GetOtp(ctx, UserLogin, enI, enA)
+	if err != nil {
+		return nil, err
+	}
+
+	k, note, err := decryptOtp(o)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	code, err := k.Code(now)
+	if err != nil {
+		return nil, err
+	}
+
+	return UserOtpCode{
+		Issuer:    k.Issuer,
+		Account:   k.Account,
+		Code:      code,
+		Remaining: k.Remaining(now),
+		Note:      note,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package cmdexecutor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+const (
+	// keepLocal оставляет при конфликте локальную версию записи.
+	keepLocal = "local"
+	// keepServer оставляет при конфликте версию записи с сервера.
+	keepServer = "server"
+)
+
+// recordTypeArgs содержит значения флага -y для типов записей.
+var recordTypeArgs = map[pb.RecordType]string{
+	pb.RecordType_RECORD_TYPE_CARD:      "card",
+	pb.RecordType_RECORD_TYPE_LOGIN_PWD: "login",
+	pb.RecordType_RECORD_TYPE_TEXT:      "text",
+	pb.RecordType_RECORD_TYPE_BINARY:    "binary",
+	pb.RecordType_RECORD_TYPE_OTP:       "otp",
+	pb.RecordType_RECORD_TYPE_SSH_KEY:   "ssh",
+	pb.RecordType_RECORD_TYPE_TEMPLATE:  "template",
+	pb.RecordType_RECORD_TYPE_CUSTOM:    "custom",
+}
+
+// resolveCommand возвращает команду для разрешения конфликта записи с ключом key.
+func resolveCommand(key *pb.RecordKey) string {
+	cmd := "--resolve -y=" + recordTypeArgs[key.GetType()]
+	switch key.GetType() {
+	case pb.RecordType_RECORD_TYPE_CARD:
+		cmd += " -n=" + decryptOrMark(key.GetKey())
+	case pb.RecordType_RECORD_TYPE_LOGIN_PWD, pb.RecordType_RECORD_TYPE_OTP:
+		cmd += " -p=" + decryptOrMark(key.GetPrompt()) + " -l=" + decryptOrMark(key.GetKey())
+	case pb.RecordType_RECORD_TYPE_TEMPLATE:
+		cmd += " -r=" + decryptOrMark(key.GetPrompt())
+	default:
+		cmd += " -p=" + decryptOrMark(key.GetPrompt())
+	}
+	return cmd + " -c=" + keepLocal + "|" + keepServer
+}
+
+// localRecord получает из локальной БД запись с ключевыми полями записи key.
+func localRecord(ctx context.Context, repo storage.Repositorier, key any) (any, error) {
+	switch k := key.(type) {
+	case storage.Card:
+		return repo.GetCard(ctx, UserLogin, k.Number)
+	case storage.LoginPwd:
+		return repo.GetLoginPwd(ctx, UserLogin, k.Prompt, k.Login)
+	case storage.TextRecord:
+		return repo.GetTextRecord(ctx, UserLogin, k.Prompt)
+	case storage.BinaryRecord:
+		return repo.GetBinaryRecord(ctx, UserLogin, k.Prompt)
+	case storage.Otp:
+		return repo.GetOtp(ctx, UserLogin, k.Issuer, k.Account)
+	case storage.SshKey:
+		return repo.GetSshKey(ctx, UserLogin, k.Prompt)
+	case storage.Template:
+		return repo.GetTemplate(ctx, UserLogin, k.Name)
+	case storage.CustomRecord:
+		return repo.GetCustomRecord(ctx, UserLogin, k.Prompt)
+	default:
+		return nil, fmt.Errorf("unsupported record type %T", key)
+	}
+}
+
+// recordToPb преобразует запись хранилища для запроса к серверу.
+func recordToPb(r any) (*pb.Record, error) {
+	switch v := r.(type) {
+	case storage.Card:
+		return &pb.Record{Type: pb.RecordType_RECORD_TYPE_CARD, Payload: &pb.Record_Card{Card: cardToPb(v)}}, nil
+	case storage.LoginPwd:
+		return &pb.Record{Type: pb.RecordType_RECORD_TYPE_LOGIN_PWD, Payload: &pb.Record_LoginPwd{LoginPwd: loginToPb(v)}}, nil
+	case storage.TextRecord:
+		return &pb.Record{Type: pb.RecordType_RECORD_TYPE_TEXT, Payload: &pb.Record_TextRecord{TextRecord: textToPb(v)}}, nil
+	case storage.BinaryRecord:
+		return &pb.Record{Type: pb.RecordType_RECORD_TYPE_BINARY, Payload: &pb.Record_BinaryRecord{BinaryRecord: binaryToPb(v)}}, nil
+	case storage.Otp:
+		return &pb.Record{Type: pb.RecordType_RECORD_TYPE_OTP, Payload: &pb.Record_Otp{Otp: otpToPb(v)}}, nil
+	case storage.SshKey:
+		return &pb.Record{Type: pb.RecordType_RECORD_TYPE_SSH_KEY, Payload: &pb.Record_SshKey{SshKey: sshKeyToPb(v)}}, nil
+	case storage.Template:
+		return &pb.Record{Type: pb.RecordType_RECORD_TYPE_TEMPLATE, Payload: &pb.Record_Template{Template: templateToPb(v)}}, nil
+	case storage.CustomRecord:
+		return &pb.Record{Type: pb.RecordType_RECORD_TYPE_CUSTOM, Payload: &pb.Record_CustomRecord{CustomRecord: customRecordToPb(v)}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported record type %T", r)
+	}
+}
+
+var resolveExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+
+	switch args.Keep {
+	case keepServer:
+		key, err := recordPbKey(args)
+		if err != nil {
+			return nil, err
+		}
+		r, err := pullRecord(ctxMd, cl, key)
+		if err != nil {
+			return nil, err
+		}
+		err = repo.RestoreRecord(ctx, UserLogin, r)
+		if err != nil {
+			return nil, err
+		}
+		return decryptRecord(ctx, repo, r, false)
+	case keepLocal:
+		key, err := recordKey(args)
+		if err != nil {
+			return nil, err
+		}
+		r, err := localRecord(ctx, repo, key)
+		if err != nil {
+			return nil, err
+		}
+		pbKey, err := recordPbKey(args)
+		if err != nil {
+			return nil, err
+		}
+		var rev *pb.RecordRevision
+		if b, ok := r.(storage.BinaryRecord); ok {
+			rev, err = uploadBinary(ctxMd, cl, b, true)
+		} else {
+			var rPb *pb.Record
+			rPb, err = recordToPb(r)
+			if err != nil {
+				return nil, err
+			}
+			var resp *pb.ForceUpdateRecordResponse
+			resp, err = cl.ForceUpdateRecord(ctxMd, &pb.ForceUpdateRecordRequest{Record: rPb})
+			rev = resp.GetRevision()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, saveRevision(ctx, repo, pbKey, rev)
+	default:
+		return nil, errors.New("specify the version to keep: -c=local or -c=server")
+	}
+}
@@ -0,0 +1,174 @@
+package cmdexecutor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gitlab.com/david_mbuvi/go_asterisks"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/sshagent"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// UserSshKey хранит ключ SSH.
+type UserSshKey struct {
+	Prompt     string
+	PrivateKey []byte
+	PublicKey  string
+	Comment    string
+	Passphrase string
+	Tags       []string
+	Folder     string
+	TimeStamp  string
+}
+
+// rawKey возвращает закрытый ключ, расшифрованный с помощью кодовой фразы, если она задана.
+func (k UserSshKey) rawKey() (any, error) {
+	if k.Passphrase != "" {
+		return ssh.ParseRawPrivateKeyWithPassphrase(k.PrivateKey, []byte(k.Passphrase))
+	}
+	return ssh.ParseRawPrivateKey(k.PrivateKey)
+}
+
+// SshKeys используется для вывода результата пользователю.
+// Закрытый ключ и кодовая фраза не выводятся.
+type SshKeys []UserSshKey
+
+// PrintData используется для вывода результата пользователю.
+func (s SshKeys) PrintData() {
+	fmt.Println("SSH KEY")
+	for _, v := range s {
+		fmt.Println("Prompt: ", v.Prompt)
+		fmt.Println("Public key: ", v.PublicKey)
+		fmt.Println("Comment: ", v.Comment)
+		fmt.Println("Tags: ", strings.Join(v.Tags, ", "))
+		fmt.Println("Folder: ", v.Folder)
+		fmt.Println("Time Stamp: ", v.TimeStamp)
+	}
+}
+
+var addSshKeyExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	data, err := os.ReadFile(args.SshKey)
+	if err != nil {
+		return nil, err
+	}
+
+	uk := UserSshKey{
+		Prompt:     args.Prompt,
+		PrivateKey: data,
+		Comment:    args.Note,
+		TimeStamp:  time.Now().Format(time.RFC3339),
+	}
+
+	raw, err := uk.rawKey()
+	var missingErr *ssh.PassphraseMissingError
+	if errors.As(err, &missingErr) {
+		fmt.Print("Enter passphrase: ")
+		var passphrase []byte
+		passphrase, err = go_asterisks.GetUsersPassword("", true, os.Stdin, os.Stdout)
+		if err != nil {
+			return nil, err
+		}
+		uk.Passphrase = string(passphrase)
+		raw, err = uk.rawKey()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := ssh.NewSignerFromKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	uk.PublicKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+
+	k, err := encryptSshKey(uk)
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.AddSshKey(ctx, UserLogin, k)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+var getSshKeyExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	enP, err := cryptor.EncryptsString(args.Prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := repo.GetSshKey(ctx, UserLogin, enP)
+	if err != nil {
+		return nil, err
+	}
+
+	uk, err := decryptSshKey(k)
+	if err != nil {
+		return nil, err
+	}
+
+	return SshKeys{uk}, nil
+}
+
+// loadSshKeys возвращает функцию загрузки расшифрованных ключей SSH текущего пользователя.
+func loadSshKeys(repo storage.Repositorier) sshagent.KeyLoader {
+	return func(ctx context.Context) ([]agent.AddedKey, error) {
+		ks, err := repo.GetUserSshKeysAfterTime(ctx, UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
+		if err != nil {
+			return nil, err
+		}
+
+		res := make([]agent.AddedKey, 0, len(ks))
+		for _, v := range ks {
+			uk, err := decryptSshKey(v)
+			if err != nil {
+				return nil, err
+			}
+			raw, err := uk.rawKey()
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, agent.AddedKey{PrivateKey: raw, Comment: uk.Comment})
+		}
+
+		return res, nil
+	}
+}
+
+// ServeSshAgent аутентифицирует пользователя по локальной базе данных
+// и предоставляет его ключи SSH по протоколу ssh-agent на unix-сокете до отмены контекста.
+func ServeSshAgent(ctx context.Context, userLogin string, socketPath string, repo storage.Repositorier) error {
+	fmt.Print("Enter your password: ")
+	password, err := go_asterisks.GetUsersPassword("", true, os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("Enter key: ")
+	cryptor.UserKey, err = go_asterisks.GetUsersPassword("", true, os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	err = repo.AuthUser(ctx, userLogin, string(password))
+	if err != nil {
+		return err
+	}
+	UserLogin = userLogin
+
+	fmt.Printf("SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK\n", socketPath)
+	return sshagent.Serve(ctx, socketPath, sshagent.New(loadSshKeys(repo)))
+}
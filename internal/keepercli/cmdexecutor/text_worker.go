@@ -3,6 +3,7 @@ package cmdexecutor
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc/metadata"
@@ -10,6 +11,7 @@ import (
 	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/labels"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
 	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
 )
@@ -19,6 +21,8 @@ type UserTextRecord struct {
 	Prompt    string
 	Data      string
 	Note      string
+	Tags      []string
+	Folder    string
 	TimeStamp string
 }
 
@@ -32,17 +36,19 @@ func (t TextRecords) PrintData() {
 		fmt.Println("Prompt: ", v.Prompt)
 		fmt.Println("Data: ", v.Data)
 		fmt.Println("Note: ", v.Note)
+		fmt.Println("Tags: ", strings.Join(v.Tags, ", "))
+		fmt.Println("Folder: ", v.Folder)
 		fmt.Println("Time Stamp: ", v.TimeStamp)
 	}
 }
 
-var addTextExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var addTextExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enA, err := encryptArgs(args)
 	if err != nil {
 		return nil, err
 	}
 
-	err = repo.AddTextRecord(context.Background(), UserLogin, enA.Prompt, enA.Text, enA.Note, time.Now().Format(time.RFC3339))
+	err = repo.AddTextRecord(ctx, UserLogin, enA.Prompt, enA.Text, enA.Note, time.Now().Format(time.RFC3339))
 	if err != nil {
 		return nil, err
 	}
@@ -50,13 +56,13 @@ var addTextExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo sto
 	return nil, nil
 }
 
-var updTextExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var updTextExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enA, err := encryptArgs(args)
 	if err != nil {
 		return nil, err
 	}
 
-	err = repo.UpdateTextRecord(context.Background(), UserLogin, enA.Prompt, enA.Text, enA.Note, time.Now().Format(time.RFC3339))
+	err = repo.UpdateTextRecord(ctx, UserLogin, enA.Prompt, enA.Text, enA.Note, time.Now().Format(time.RFC3339))
 	if err != nil {
 		return nil, err
 	}
@@ -64,13 +70,13 @@ var updTextExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo sto
 	return nil, nil
 }
 
-var getTextExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var getTextExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enP, err := cryptor.EncryptsString(args.Prompt)
 	if err != nil {
 		return nil, err
 	}
 
-	t, err := repo.GetTextRecord(context.Background(), UserLogin, enP)
+	t, err := repo.GetTextRecord(ctx, UserLogin, enP)
 	if err != nil {
 		return nil, err
 	}
@@ -86,38 +92,50 @@ var getTextExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo sto
 	return res, nil
 }
 
-var getTextsExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
-	ts, err := repo.GetUserTextRecordsAfterTime(context.Background(), UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
+var getTextsExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	ts, err := repo.GetUserTextRecordsAfterTime(ctx, UserLogin, time.Now().AddDate(-100, 0, 0).Format(time.RFC3339))
 	if err != nil {
 		return nil, err
 	}
 
+	filter := labels.Filter{Tags: args.Tags, Folder: args.Folder}
 	res := make(TextRecords, 0, len(ts))
 	for _, v := range ts {
 		t, err := decryptTextRecord(v)
 		if err != nil {
 			return nil, err
 		}
+		if !filter.Match(t.Tags, t.Folder) {
+			continue
+		}
 		res = append(res, t)
 	}
 
 	return res, nil
 }
 
-var forceAddTextServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var forceAddTextServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enP, err := cryptor.EncryptsString(args.Prompt)
 	if err != nil {
 		return nil, err
 	}
-	t, err := repo.GetTextRecord(context.Background(), UserLogin, enP)
+	t, err := repo.GetTextRecord(ctx, UserLogin, enP)
 	if err != nil {
 		return nil, err
 	}
 
 	tPb := textToPb(t)
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
-	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
-	_, err = cl.ForceUpdateTextRecord(ctxMd, &pb.ForceUpdateTextRecordRequest{TextRecord: tPb})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	resp, err := cl.ForceUpdateRecord(ctxMd, &pb.ForceUpdateRecordRequest{Record: &pb.Record{
+		Type:    pb.RecordType_RECORD_TYPE_TEXT,
+		Payload: &pb.Record_TextRecord{TextRecord: tPb},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	err = saveRevision(ctx, repo,
+		&pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: t.Prompt}, resp.GetRevision())
 	if err != nil {
 		return nil, err
 	}
@@ -125,20 +143,23 @@ var forceAddTextServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClien
 	return nil, nil
 }
 
-var getTextServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var getTextServerExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	enP, err := cryptor.EncryptsString(args.Prompt)
 	if err != nil {
 		return nil, err
 	}
 
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
-	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
-	r, err := cl.GetUserText(ctxMd, &pb.GetUserTextRequest{Prompt: enP})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	r, err := cl.GetRecord(ctxMd, &pb.GetRecordRequest{Key: &pb.RecordKey{
+		Type:   pb.RecordType_RECORD_TYPE_TEXT,
+		Prompt: enP,
+	}})
 	if err != nil {
 		return nil, err
 	}
 
-	t := pbToText(r.TextRecord)
+	t := pbToText(r.GetRecord().GetTextRecord())
 	deT, err := decryptTextRecord(t)
 	if err != nil {
 		return nil, err
@@ -149,6 +170,8 @@ var getTextServerExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, re
 		Prompt:    deT.Prompt,
 		Data:      deT.Data,
 		Note:      deT.Note,
+		Tags:      deT.Tags,
+		Folder:    deT.Folder,
 		TimeStamp: deT.TimeStamp,
 	})
 
@@ -1,10 +1,15 @@
 package cmdexecutor
 
 import (
+	"strconv"
+
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cryptor"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/customrecord"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/labels"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
 	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+	"github.com/Julia-ivv/info-keeper.git/pkg/otp"
 )
 
 func cardToPb(c storage.Card) *pb.UserCard {
@@ -14,6 +19,8 @@ func cardToPb(c storage.Card) *pb.UserCard {
 		Date:      c.Date,
 		Code:      c.Code,
 		Note:      c.Note,
+		Tags:      c.Tags,
+		Folder:    c.Folder,
 		TimeStamp: c.TimeStamp,
 	}
 }
@@ -32,6 +39,8 @@ func loginToPb(l storage.LoginPwd) *pb.UserLoginPwd {
 		Login:     l.Login,
 		Pwd:       l.Pwd,
 		Note:      l.Note,
+		Tags:      l.Tags,
+		Folder:    l.Folder,
 		TimeStamp: l.TimeStamp,
 	}
 }
@@ -49,6 +58,8 @@ func textToPb(t storage.TextRecord) *pb.UserTextRecord {
 		Prompt:    t.Prompt,
 		Data:      t.Data,
 		Note:      t.Note,
+		Tags:      t.Tags,
+		Folder:    t.Folder,
 		TimeStamp: t.TimeStamp,
 	}
 }
@@ -66,6 +77,8 @@ func binaryToPb(b storage.BinaryRecord) *pb.UserBinaryRecord {
 		Prompt:    b.Prompt,
 		Data:      b.Data,
 		Note:      b.Note,
+		Tags:      b.Tags,
+		Folder:    b.Folder,
 		TimeStamp: b.TimeStamp,
 	}
 }
@@ -85,6 +98,8 @@ func pbToCard(c *pb.UserCard) storage.Card {
 		Date:      c.Date,
 		Code:      c.Code,
 		Note:      c.Note,
+		Tags:      c.Tags,
+		Folder:    c.Folder,
 		TimeStamp: c.TimeStamp,
 	}
 }
@@ -103,6 +118,8 @@ func pbToLogin(l *pb.UserLoginPwd) storage.LoginPwd {
 		Login:     l.Login,
 		Pwd:       l.Pwd,
 		Note:      l.Note,
+		Tags:      l.Tags,
+		Folder:    l.Folder,
 		TimeStamp: l.TimeStamp,
 	}
 }
@@ -120,6 +137,8 @@ func pbToText(t *pb.UserTextRecord) storage.TextRecord {
 		Prompt:    t.Prompt,
 		Data:      t.Data,
 		Note:      t.Note,
+		Tags:      t.Tags,
+		Folder:    t.Folder,
 		TimeStamp: t.TimeStamp,
 	}
 }
@@ -137,6 +156,8 @@ func pbToBinary(b *pb.UserBinaryRecord) storage.BinaryRecord {
 		Prompt:    b.Prompt,
 		Data:      b.Data,
 		Note:      b.Note,
+		Tags:      b.Tags,
+		Folder:    b.Folder,
 		TimeStamp: b.TimeStamp,
 	}
 }
@@ -149,6 +170,170 @@ func pbToBinarys(pbBs []*pb.UserBinaryRecord) []storage.BinaryRecord {
 	return bs
 }
 
+func otpToPb(o storage.Otp) *pb.UserOtp {
+	return &pb.UserOtp{
+		Issuer:    o.Issuer,
+		Account:   o.Account,
+		Secret:    o.Secret,
+		Algorithm: o.Algorithm,
+		Digits:    o.Digits,
+		Period:    o.Period,
+		Note:      o.Note,
+		Tags:      o.Tags,
+		Folder:    o.Folder,
+		TimeStamp: o.TimeStamp,
+	}
+}
+
+func otpsToPb(otps []storage.Otp) []*pb.UserOtp {
+	pbO := make([]*pb.UserOtp, 0, len(otps))
+	for _, v := range otps {
+		pbO = append(pbO, otpToPb(v))
+	}
+	return pbO
+}
+
+func pbToOtp(o *pb.UserOtp) storage.Otp {
+	return storage.Otp{
+		Issuer:    o.Issuer,
+		Account:   o.Account,
+		Secret:    o.Secret,
+		Algorithm: o.Algorithm,
+		Digits:    o.Digits,
+		Period:    o.Period,
+		Note:      o.Note,
+		Tags:      o.Tags,
+		Folder:    o.Folder,
+		TimeStamp: o.TimeStamp,
+	}
+}
+
+func pbToOtps(pbOs []*pb.UserOtp) []storage.Otp {
+	otps := make([]storage.Otp, 0, len(pbOs))
+	for _, v := range pbOs {
+		otps = append(otps, pbToOtp(v))
+	}
+	return otps
+}
+
+func sshKeyToPb(k storage.SshKey) *pb.UserSshKey {
+	return &pb.UserSshKey{
+		Prompt:     k.Prompt,
+		PrivateKey: k.PrivateKey,
+		PublicKey:  k.PublicKey,
+		Comment:    k.Comment,
+		Passphrase: k.Passphrase,
+		Tags:       k.Tags,
+		Folder:     k.Folder,
+		TimeStamp:  k.TimeStamp,
+	}
+}
+
+func sshKeysToPb(ks []storage.SshKey) []*pb.UserSshKey {
+	pbK := make([]*pb.UserSshKey, 0, len(ks))
+	for _, v := range ks {
+		pbK = append(pbK, sshKeyToPb(v))
+	}
+	return pbK
+}
+
+func pbToSshKey(k *pb.UserSshKey) storage.SshKey {
+	return storage.SshKey{
+		Prompt:     k.Prompt,
+		PrivateKey: k.PrivateKey,
+		PublicKey:  k.PublicKey,
+		Comment:    k.Comment,
+		Passphrase: k.Passphrase,
+		Tags:       k.Tags,
+		Folder:     k.Folder,
+		TimeStamp:  k.TimeStamp,
+	}
+}
+
+func pbToSshKeys(pbKs []*pb.UserSshKey) []storage.SshKey {
+	ks := make([]storage.SshKey, 0, len(pbKs))
+	for _, v := range pbKs {
+		ks = append(ks, pbToSshKey(v))
+	}
+	return ks
+}
+
+func templateToPb(t storage.Template) *pb.UserTemplate {
+	return &pb.UserTemplate{
+		Name:      t.Name,
+		Fields:    t.Fields,
+		Tags:      t.Tags,
+		Folder:    t.Folder,
+		TimeStamp: t.TimeStamp,
+	}
+}
+
+func templatesToPb(ts []storage.Template) []*pb.UserTemplate {
+	pbT := make([]*pb.UserTemplate, 0, len(ts))
+	for _, v := range ts {
+		pbT = append(pbT, templateToPb(v))
+	}
+	return pbT
+}
+
+func pbToTemplate(t *pb.UserTemplate) storage.Template {
+	return storage.Template{
+		Name:      t.Name,
+		Fields:    t.Fields,
+		Tags:      t.Tags,
+		Folder:    t.Folder,
+		TimeStamp: t.TimeStamp,
+	}
+}
+
+func pbToTemplates(pbTs []*pb.UserTemplate) []storage.Template {
+	ts := make([]storage.Template, 0, len(pbTs))
+	for _, v := range pbTs {
+		ts = append(ts, pbToTemplate(v))
+	}
+	return ts
+}
+
+func customRecordToPb(r storage.CustomRecord) *pb.UserCustomRecord {
+	return &pb.UserCustomRecord{
+		Prompt:    r.Prompt,
+		Template:  r.Template,
+		Data:      r.Data,
+		Note:      r.Note,
+		Tags:      r.Tags,
+		Folder:    r.Folder,
+		TimeStamp: r.TimeStamp,
+	}
+}
+
+func customRecordsToPb(rs []storage.CustomRecord) []*pb.UserCustomRecord {
+	pbR := make([]*pb.UserCustomRecord, 0, len(rs))
+	for _, v := range rs {
+		pbR = append(pbR, customRecordToPb(v))
+	}
+	return pbR
+}
+
+func pbToCustomRecord(r *pb.UserCustomRecord) storage.CustomRecord {
+	return storage.CustomRecord{
+		Prompt:    r.Prompt,
+		Template:  r.Template,
+		Data:      r.Data,
+		Note:      r.Note,
+		Tags:      r.Tags,
+		Folder:    r.Folder,
+		TimeStamp: r.TimeStamp,
+	}
+}
+
+func pbToCustomRecords(pbRs []*pb.UserCustomRecord) []storage.CustomRecord {
+	rs := make([]storage.CustomRecord, 0, len(pbRs))
+	for _, v := range pbRs {
+		rs = append(rs, pbToCustomRecord(v))
+	}
+	return rs
+}
+
 type EncryptArgs struct {
 	Prompt     []byte
 	Note       []byte
@@ -240,6 +425,10 @@ func decryptCard(c storage.Card) (uc UserCard, err error) {
 	if err != nil {
 		return
 	}
+	uc.Tags, uc.Folder, err = decryptLabels(c.Tags, c.Folder)
+	if err != nil {
+		return
+	}
 	uc.TimeStamp = c.TimeStamp
 	return
 }
@@ -261,6 +450,10 @@ func decryptLoginPwd(l storage.LoginPwd) (ul UserLoginPwd, err error) {
 	if err != nil {
 		return
 	}
+	ul.Tags, ul.Folder, err = decryptLabels(l.Tags, l.Folder)
+	if err != nil {
+		return
+	}
 	ul.TimeStamp = l.TimeStamp
 	return
 }
@@ -278,6 +471,10 @@ func decryptTextRecord(t storage.TextRecord) (ut UserTextRecord, err error) {
 	if err != nil {
 		return
 	}
+	ut.Tags, ut.Folder, err = decryptLabels(t.Tags, t.Folder)
+	if err != nil {
+		return
+	}
 	ut.TimeStamp = t.TimeStamp
 	return
 }
@@ -295,6 +492,273 @@ func decryptBinaryRecord(b storage.BinaryRecord) (ub UserBinaryRecord, err error
 	if err != nil {
 		return
 	}
+	ub.Tags, ub.Folder, err = decryptLabels(b.Tags, b.Folder)
+	if err != nil {
+		return
+	}
 	ub.TimeStamp = b.TimeStamp
 	return
 }
+
+func encryptOtp(k otp.Key, note string) (o storage.Otp, err error) {
+	o.Issuer, err = cryptor.EncryptsString(k.Issuer)
+	if err != nil {
+		return
+	}
+	o.Account, err = cryptor.EncryptsString(k.Account)
+	if err != nil {
+		return
+	}
+	o.Secret, err = cryptor.EncryptsString(k.Secret)
+	if err != nil {
+		return
+	}
+	o.Algorithm, err = cryptor.EncryptsString(k.Algorithm)
+	if err != nil {
+		return
+	}
+	o.Digits, err = cryptor.EncryptsString(strconv.Itoa(k.Digits))
+	if err != nil {
+		return
+	}
+	o.Period, err = cryptor.EncryptsString(strconv.Itoa(k.Period))
+	if err != nil {
+		return
+	}
+	if note != "" {
+		o.Note, err = cryptor.EncryptsString(note)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func decryptOtp(o storage.Otp) (k otp.Key, note string, err error) {
+	k.Issuer, err = cryptor.Decrypts(o.Issuer)
+	if err != nil {
+		return
+	}
+	k.Account, err = cryptor.Decrypts(o.Account)
+	if err != nil {
+		return
+	}
+	k.Secret, err = cryptor.Decrypts(o.Secret)
+	if err != nil {
+		return
+	}
+	k.Algorithm, err = cryptor.Decrypts(o.Algorithm)
+	if err != nil {
+		return
+	}
+	d, err := cryptor.Decrypts(o.Digits)
+	if err != nil {
+		return
+	}
+	k.Digits, err = strconv.Atoi(d)
+	if err != nil {
+		return
+	}
+	p, err := cryptor.Decrypts(o.Period)
+	if err != nil {
+		return
+	}
+	k.Period, err = strconv.Atoi(p)
+	if err != nil {
+		return
+	}
+	if len(o.Note) != 0 {
+		note, err = cryptor.Decrypts(o.Note)
+	}
+	return
+}
+
+func encryptSshKey(uk UserSshKey) (k storage.SshKey, err error) {
+	k.Prompt, err = cryptor.EncryptsString(uk.Prompt)
+	if err != nil {
+		return
+	}
+	k.PrivateKey, err = cryptor.EncryptsByte(uk.PrivateKey)
+	if err != nil {
+		return
+	}
+	k.PublicKey, err = cryptor.EncryptsString(uk.PublicKey)
+	if err != nil {
+		return
+	}
+	if uk.Comment != "" {
+		k.Comment, err = cryptor.EncryptsString(uk.Comment)
+		if err != nil {
+			return
+		}
+	}
+	if uk.Passphrase != "" {
+		k.Passphrase, err = cryptor.EncryptsString(uk.Passphrase)
+		if err != nil {
+			return
+		}
+	}
+	k.TimeStamp = uk.TimeStamp
+	return
+}
+
+func decryptSshKey(k storage.SshKey) (uk UserSshKey, err error) {
+	uk.Prompt, err = cryptor.Decrypts(k.Prompt)
+	if err != nil {
+		return
+	}
+	uk.PrivateKey, err = cryptor.DecryptsInByte(k.PrivateKey)
+	if err != nil {
+		return
+	}
+	uk.PublicKey, err = cryptor.Decrypts(k.PublicKey)
+	if err != nil {
+		return
+	}
+	if len(k.Comment) != 0 {
+		uk.Comment, err = cryptor.Decrypts(k.Comment)
+		if err != nil {
+			return
+		}
+	}
+	if len(k.Passphrase) != 0 {
+		uk.Passphrase, err = cryptor.Decrypts(k.Passphrase)
+		if err != nil {
+			return
+		}
+	}
+	uk.Tags, uk.Folder, err = decryptLabels(k.Tags, k.Folder)
+	if err != nil {
+		return
+	}
+	uk.TimeStamp = k.TimeStamp
+	return
+}
+
+func encryptTemplate(ut UserTemplate) (t storage.Template, err error) {
+	t.Name, err = cryptor.EncryptsString(ut.Name)
+	if err != nil {
+		return
+	}
+	fields, err := customrecord.MarshalFields(ut.Fields)
+	if err != nil {
+		return
+	}
+	t.Fields, err = cryptor.EncryptsByte(fields)
+	if err != nil {
+		return
+	}
+	t.TimeStamp = ut.TimeStamp
+	return
+}
+
+func decryptTemplate(t storage.Template) (ut UserTemplate, err error) {
+	ut.Name, err = cryptor.Decrypts(t.Name)
+	if err != nil {
+		return
+	}
+	fields, err := cryptor.DecryptsInByte(t.Fields)
+	if err != nil {
+		return
+	}
+	ut.Fields, err = customrecord.UnmarshalFields(fields)
+	if err != nil {
+		return
+	}
+	ut.Tags, ut.Folder, err = decryptLabels(t.Tags, t.Folder)
+	if err != nil {
+		return
+	}
+	ut.TimeStamp = t.TimeStamp
+	return
+}
+
+func encryptCustomRecord(d customData) (r storage.CustomRecord, err error) {
+	r.Prompt, err = cryptor.EncryptsString(d.Prompt)
+	if err != nil {
+		return
+	}
+	r.Template, err = cryptor.EncryptsString(d.Template)
+	if err != nil {
+		return
+	}
+	values, err := customrecord.MarshalValues(d.Values)
+	if err != nil {
+		return
+	}
+	r.Data, err = cryptor.EncryptsByte(values)
+	if err != nil {
+		return
+	}
+	if d.Note != "" {
+		r.Note, err = cryptor.EncryptsString(d.Note)
+		if err != nil {
+			return
+		}
+	}
+	r.TimeStamp = d.TimeStamp
+	return
+}
+
+func decryptCustomRecord(r storage.CustomRecord) (d customData, err error) {
+	d.Prompt, err = cryptor.Decrypts(r.Prompt)
+	if err != nil {
+		return
+	}
+	d.Template, err = cryptor.Decrypts(r.Template)
+	if err != nil {
+		return
+	}
+	values, err := cryptor.DecryptsInByte(r.Data)
+	if err != nil {
+		return
+	}
+	d.Values, err = customrecord.UnmarshalValues(values)
+	if err != nil {
+		return
+	}
+	if len(r.Note) != 0 {
+		d.Note, err = cryptor.Decrypts(r.Note)
+		if err != nil {
+			return
+		}
+	}
+	d.Tags, d.Folder, err = decryptLabels(r.Tags, r.Folder)
+	if err != nil {
+		return
+	}
+	d.TimeStamp = r.TimeStamp
+	return
+}
+
+func encryptTags(tags []string) ([]byte, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	return cryptor.EncryptsString(labels.JoinTags(tags))
+}
+
+func encryptFolder(folder string) ([]byte, error) {
+	if folder == "" {
+		return nil, nil
+	}
+	return cryptor.EncryptsString(folder)
+}
+
+func decryptLabels(enTags []byte, enFolder []byte) (tags []string, folder string, err error) {
+	if len(enTags) != 0 {
+		var t string
+		t, err = cryptor.Decrypts(enTags)
+		if err != nil {
+			return
+		}
+		tags = labels.SplitTags(t)
+	}
+	if len(enFolder) != 0 {
+		folder, err = cryptor.Decrypts(enFolder)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,76 @@
+package cmdexecutor
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// RecordUsage хранит количество и размер записей одного типа на сервере.
+type RecordUsage struct {
+	Type    string
+	Records int64
+	Bytes   int64
+}
+
+// Usage хранит объем данных пользователя на сервере и его квоты.
+// Нулевое значение квоты означает отсутствие ограничения.
+type Usage struct {
+	Records         []RecordUsage
+	TotalBytes      int64
+	QuotaBytes      int64
+	QuotaRecords    int64
+	QuotaBinarySize int64
+}
+
+// quotaText возвращает значение квоты для вывода пользователю.
+func quotaText(q int64) string {
+	if q <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprint(q)
+}
+
+// PrintData используется для вывода результата пользователю.
+func (u Usage) PrintData() {
+	fmt.Println("SERVER USAGE")
+	for _, v := range u.Records {
+		fmt.Println("Type: ", v.Type)
+		fmt.Println("Records: ", v.Records, "of", quotaText(u.QuotaRecords))
+		fmt.Println("Bytes: ", v.Bytes)
+	}
+	fmt.Println("Total bytes: ", u.TotalBytes, "of", quotaText(u.QuotaBytes))
+	fmt.Println("Max binary size: ", quotaText(u.QuotaBinarySize))
+}
+
+var usageExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	r, err := cl.GetUsage(ctxMd, &pb.GetUsageRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := Usage{
+		Records:         make([]RecordUsage, 0, len(r.GetUsage())),
+		TotalBytes:      r.GetTotalBytes(),
+		QuotaBytes:      r.GetQuotaBytes(),
+		QuotaRecords:    r.GetQuotaRecords(),
+		QuotaBinarySize: r.GetQuotaBinarySize(),
+	}
+	for _, v := range r.GetUsage() {
+		res.Records = append(res.Records, RecordUsage{
+			Type:    recordTypeNames[v.GetType()],
+			Records: v.GetRecords(),
+			Bytes:   v.GetBytes(),
+		})
+	}
+
+	return res, nil
+}
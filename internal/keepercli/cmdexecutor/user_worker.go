@@ -1,13 +1,16 @@
 package cmdexecutor
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
-	"time"
+	"slices"
 
 	"gitlab.com/david_mbuvi/go_asterisks"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
@@ -31,7 +34,7 @@ var UserToken string
 // UserLogin хранит логин текущего пользователя.
 var UserLogin string
 
-var regExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var regExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	fmt.Print("Enter your password: ")
 	password, err := go_asterisks.GetUsersPassword("", true, os.Stdin, os.Stdout)
 	if err != nil {
@@ -44,18 +47,18 @@ var regExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage
 		return nil, err
 	}
 
-	resp, err := cl.AddUser(context.Background(), &pb.AddUserRequest{Login: args.AuthLogin, Pwd: string(password)})
+	resp, err := cl.AddUser(ctx, &pb.AddUserRequest{Login: args.AuthLogin, Pwd: string(password)})
 	if err != nil {
 		return nil, err
 	}
 	UserToken = resp.GetToken()
 
-	err = repo.RegUser(context.Background(), args.AuthLogin, string(password))
+	err = repo.RegUser(ctx, args.AuthLogin, string(password))
 	if err != nil {
 		return nil, err
 	}
 
-	err = repo.AuthUser(context.Background(), args.AuthLogin, string(password))
+	err = repo.AuthUser(ctx, args.AuthLogin, string(password))
 	if err != nil {
 		return nil, err
 	}
@@ -66,40 +69,123 @@ var regExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage
 
 // SyncErr хранит ошибку синхронизации.
 type SyncErr struct {
-	Text   string
-	Value  string
-	ErrMsg string
+	Text            string
+	Value           string
+	ErrMsg          string
+	Code            pb.SyncErrorCode
+	Key             *pb.RecordKey
+	ServerTimeStamp string
 }
 
 // SyncErrs используется для вывода результата пользователю.
+// Для конфликтов выводится команда для их разрешения.
 type SyncErrs []SyncErr
 
 // PrintData используется для вывода результата пользователю.
 func (s SyncErrs) PrintData() {
+	if len(s) == 0 {
+		return
+	}
+	fmt.Println("SYNC ERRORS")
 	for _, v := range s {
-		fmt.Println("SYNC ERRORS")
-		fmt.Printf("%s %s: %s\n", v.Text, v.Value, v.ErrMsg)
+		if v.Code == pb.SyncErrorCode_SYNC_ERROR_CODE_QUOTA_EXCEEDED {
+			fmt.Printf("%s %s: %s\n", v.Text, v.Value, v.ErrMsg)
+			fmt.Println("Storage quota on the server is exceeded, check it with: --usage")
+			continue
+		}
+		if v.Code != pb.SyncErrorCode_SYNC_ERROR_CODE_CONFLICT_NEWER_ON_SERVER || v.Key == nil {
+			fmt.Printf("%s %s: %s\n", v.Text, v.Value, v.ErrMsg)
+			continue
+		}
+		fmt.Printf("%s %s: the server has a newer version", v.Text, v.Value)
+		if v.ServerTimeStamp != "" {
+			fmt.Printf(" from %s", v.ServerTimeStamp)
+		}
+		fmt.Println()
+		fmt.Println("To resolve the conflict use: ", resolveCommand(v.Key))
+	}
+}
+
+// uploadSyncErr создает ошибку синхронизации для бинарных данных, не переданных на сервер.
+func uploadSyncErr(b storage.BinaryRecord, err error) SyncErr {
+	code := pb.SyncErrorCode_SYNC_ERROR_CODE_INTERNAL
+	switch status.Code(err) {
+	case codes.AlreadyExists:
+		code = pb.SyncErrorCode_SYNC_ERROR_CODE_CONFLICT_NEWER_ON_SERVER
+	case codes.ResourceExhausted:
+		code = pb.SyncErrorCode_SYNC_ERROR_CODE_QUOTA_EXCEEDED
+	}
+	return SyncErr{
+		Text:   "error for binary data with prompt ",
+		Value:  decryptOrMark(b.Prompt),
+		ErrMsg: err.Error(),
+		Code:   code,
+		Key:    &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: b.Prompt},
+	}
+}
+
+// appendRecords добавляет записи хранилища records к списку dst.
+func appendRecords[T any](dst []any, records []T) []any {
+	for _, v := range records {
+		dst = append(dst, v)
+	}
+	return dst
+}
+
+// acceptedRecords возвращает записи sent, переданные на сервер, для которых сервер не вернул ошибку.
+func acceptedRecords(sent []any, errs []*pb.SyncUserDataResponse_SyncErrorInfo) ([]any, error) {
+	accepted := make([]any, 0, len(sent))
+	for _, v := range sent {
+		key, err := localPbKey(v)
+		if err != nil {
+			return nil, err
+		}
+		rejected := slices.ContainsFunc(errs, func(e *pb.SyncUserDataResponse_SyncErrorInfo) bool {
+			k := e.GetKey()
+			return k.GetType() == key.GetType() && bytes.Equal(k.GetPrompt(), key.GetPrompt()) &&
+				bytes.Equal(k.GetKey(), key.GetKey())
+		})
+		if !rejected {
+			accepted = append(accepted, v)
+		}
 	}
+	return accepted, nil
 }
 
-func synchronization(cl pb.InfoKeeperClient, repo storage.Repositorier) (SyncErrs, error) {
-	lSync, err := repo.GetLastSyncTime(context.Background(), UserLogin)
+func synchronization(ctx context.Context, cl pb.InfoKeeperClient, repo storage.Repositorier) (SyncErrs, error) {
+	rev, err := repo.GetSyncRevision(ctx, UserLogin)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := repo.GetUserCardsToSync(ctx, UserLogin)
 	if err != nil {
 		return nil, err
 	}
-	cs, err := repo.GetUserCardsAfterTime(context.Background(), UserLogin, lSync)
+	ls, err := repo.GetUserLoginsPwdsToSync(ctx, UserLogin)
 	if err != nil {
 		return nil, err
 	}
-	ls, err := repo.GetUserLoginsPwdsAfterTime(context.Background(), UserLogin, lSync)
+	ts, err := repo.GetUserTextRecordsToSync(ctx, UserLogin)
 	if err != nil {
 		return nil, err
 	}
-	ts, err := repo.GetUserTextRecordsAfterTime(context.Background(), UserLogin, lSync)
+	bs, err := repo.GetUserBinaryRecordsToSync(ctx, UserLogin)
 	if err != nil {
 		return nil, err
 	}
-	bs, err := repo.GetUserBinaryRecordsAfterTime(context.Background(), UserLogin, lSync)
+	otps, err := repo.GetUserOtpsToSync(ctx, UserLogin)
+	if err != nil {
+		return nil, err
+	}
+	sshKeys, err := repo.GetUserSshKeysToSync(ctx, UserLogin)
+	if err != nil {
+		return nil, err
+	}
+	tmpls, err := repo.GetUserTemplatesToSync(ctx, UserLogin)
+	if err != nil {
+		return nil, err
+	}
+	customs, err := repo.GetUserCustomRecordsToSync(ctx, UserLogin)
 	if err != nil {
 		return nil, err
 	}
@@ -107,53 +193,128 @@ func synchronization(cl pb.InfoKeeperClient, repo storage.Repositorier) (SyncErr
 	pbC := cardsToPb(cs)
 	pbL := loginsToPb(ls)
 	pbT := textsToPb(ts)
-	pbB := binarysToPb(bs)
+	pbO := otpsToPb(otps)
+	pbK := sshKeysToPb(sshKeys)
+	pbTm := templatesToPb(tmpls)
+	pbCr := customRecordsToPb(customs)
 
 	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
-	ctxMd := metadata.NewOutgoingContext(context.Background(), md)
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+
+	sent := appendRecords(nil, cs)
+	sent = appendRecords(sent, ls)
+	sent = appendRecords(sent, ts)
+	sent = appendRecords(sent, otps)
+	sent = appendRecords(sent, sshKeys)
+	sent = appendRecords(sent, tmpls)
+	sent = appendRecords(sent, customs)
+
+	r := make(SyncErrs, 0)
+	pbB := make([]*pb.BinaryRecordRef, 0, len(bs))
+	for _, v := range bs {
+		rev, err := uploadBinary(ctxMd, cl, v, false)
+		if err != nil {
+			// Незагруженная запись не попадает в ссылки и в переданные записи,
+			// иначе сервер и клиент считали бы ее синхронизированной.
+			r = append(r, uploadSyncErr(v, err))
+			continue
+		}
+		sent = append(sent, v)
+		err = saveRevision(ctx, repo,
+			&pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: v.Prompt}, rev)
+		if err != nil {
+			r = append(r, uploadSyncErr(v, err))
+		}
+		pbB = append(pbB, &pb.BinaryRecordRef{
+			Prompt:    v.Prompt,
+			TimeStamp: v.TimeStamp,
+			Size:      int64(len(v.Data)),
+		})
+	}
+
 	resSync, err := cl.SyncUserData(ctxMd, &pb.SyncUserDataRequest{
 		Logins:        pbL,
 		Cards:         pbC,
 		TextRecords:   pbT,
-		BinaryRecords: pbB,
-		LastSync:      lSync,
+		BinaryRefs:    pbB,
+		Otps:          pbO,
+		SshKeys:       pbK,
+		Templates:     pbTm,
+		CustomRecords: pbCr,
+		SinceRevision: rev,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// удаления применяются до добавлений, чтобы запись, созданная заново после переноса, сохранилась
+	for _, v := range resSync.GetDeletedRecords() {
+		key, err := localRecordKey(v)
+		if err != nil {
+			return nil, err
+		}
+		err = repo.DeleteRecord(ctx, UserLogin, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	newCs := pbToCards(resSync.GetNewCards())
 	newLs := pbToLogins(resSync.GetNewLogins())
 	newTs := pbToTexts(resSync.GetNewTextRecords())
-	newBs := pbToBinarys(resSync.GetNewBinaryRecords())
+	newOs := pbToOtps(resSync.GetNewOtps())
+	newKs := pbToSshKeys(resSync.GetNewSshKeys())
+	newTms := pbToTemplates(resSync.GetNewTemplates())
+	newCrs := pbToCustomRecords(resSync.GetNewCustomRecords())
+	newBs := make([]storage.BinaryRecord, 0, len(resSync.GetNewBinaryRefs()))
+	for _, v := range resSync.GetNewBinaryRefs() {
+		b, err := downloadBinary(ctxMd, cl, v.GetPrompt())
+		if err != nil {
+			return nil, err
+		}
+		newBs = append(newBs, b)
+	}
 
-	err = repo.AddSyncData(context.Background(), UserLogin, newCs, newLs, newTs, newBs)
+	err = repo.AddSyncData(ctx, UserLogin, newCs, newLs, newTs, newBs, newOs, newKs, newTms, newCrs)
 	if err != nil {
 		return nil, err
 	}
 
-	err = repo.UpdateLastSyncTime(context.Background(), UserLogin, time.Now().Format(time.RFC3339))
+	synced, err := acceptedRecords(sent, resSync.GetSyncErrors())
+	if err != nil {
+		return nil, err
+	}
+	err = repo.UpdateSyncRevision(ctx, UserLogin, resSync.GetCurrentRevision(), synced)
 	if err != nil {
 		return nil, err
 	}
 
-	r := make(SyncErrs, 0, len(resSync.SyncErrors))
 	for _, v := range resSync.SyncErrors {
-		val, err := cryptor.Decrypts(v.Value)
-		if err != nil {
-			val = "decryption error"
-		}
 		r = append(r, SyncErr{
-			Text:   v.Text,
-			Value:  val,
-			ErrMsg: v.Err,
+			Text:            v.GetText(),
+			Value:           decryptOrMark(v.GetValue()),
+			ErrMsg:          v.GetErr(),
+			Code:            v.GetCode(),
+			Key:             v.GetKey(),
+			ServerTimeStamp: v.GetServerTimeStamp(),
 		})
 	}
+	// Копия записей у доверенных лиц обновляется после каждой синхронизации,
+	// чтобы экстренный доступ открывал актуальные записи.
+	r = append(r, refreshEmergency(ctx, cl, repo)...)
 
 	return r, nil
 }
 
-var authExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+func decryptOrMark(value []byte) string {
+	val, err := cryptor.Decrypts(value)
+	if err != nil {
+		return "decryption error"
+	}
+	return val
+}
+
+var authExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	fmt.Print("Enter your password: ")
 	password, err := go_asterisks.GetUsersPassword("", true, os.Stdin, os.Stdout)
 	if err != nil {
@@ -166,12 +327,12 @@ var authExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storag
 		return nil, err
 	}
 
-	resp, err := cl.AuthUser(context.Background(), &pb.AuthUserRequest{Login: args.AuthLogin, Pwd: string(password)})
+	resp, err := cl.AuthUser(ctx, &pb.AuthUserRequest{Login: args.AuthLogin, Pwd: string(password)})
 	if err != nil {
 		return nil, err
 	}
 
-	err = repo.AuthUser(context.Background(), args.AuthLogin, string(password))
+	err = repo.AuthUser(ctx, args.AuthLogin, string(password))
 	if err != nil {
 		return nil, err
 	}
@@ -179,12 +340,12 @@ var authExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storag
 	UserLogin = args.AuthLogin
 	UserToken = resp.GetToken()
 
-	return synchronization(cl, repo)
+	return synchronization(ctx, cl, repo)
 }
 
-var exitExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var exitExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	if UserLogin != "" {
-		r, err := synchronization(cl, repo)
+		r, err := synchronization(ctx, cl, repo)
 		if err != nil {
 			fmt.Println(err)
 		}
@@ -194,6 +355,6 @@ var exitExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storag
 	return nil, nil
 }
 
-var verExec = func(args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+var verExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
 	return appVersion, nil
 }
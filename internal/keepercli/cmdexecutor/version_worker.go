@@ -0,0 +1,250 @@
+package cmdexecutor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/authorizer"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/cmdparser"
+	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/storage"
+	pb "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
+)
+
+// RecordVersion хранит предыдущую версию записи на сервере.
+type RecordVersion struct {
+	ID     int64
+	Record DataPrinter
+}
+
+// RecordVersions используется для вывода результата пользователю.
+type RecordVersions []RecordVersion
+
+// PrintData используется для вывода результата пользователю.
+func (r RecordVersions) PrintData() {
+	fmt.Println("RECORD VERSIONS")
+	for _, v := range r {
+		fmt.Println("Version: ", v.ID)
+		v.Record.PrintData()
+	}
+}
+
+// recordPbKey возвращает ключ записи для запроса к серверу
+// с зашифрованными ключевыми полями записи, указанной пользователем.
+func recordPbKey(args cmdparser.UserArgs) (*pb.RecordKey, error) {
+	key, err := recordKey(args)
+	if err != nil {
+		return nil, err
+	}
+	return localPbKey(key)
+}
+
+// localPbKey возвращает ключ записи хранилища для запроса к серверу.
+func localPbKey(key any) (*pb.RecordKey, error) {
+	switch k := key.(type) {
+	case storage.Card:
+		return &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CARD, Key: k.Number}, nil
+	case storage.LoginPwd:
+		return &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_LOGIN_PWD, Prompt: k.Prompt, Key: k.Login}, nil
+	case storage.TextRecord:
+		return &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEXT, Prompt: k.Prompt}, nil
+	case storage.BinaryRecord:
+		return &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_BINARY, Prompt: k.Prompt}, nil
+	case storage.Otp:
+		return &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_OTP, Prompt: k.Issuer, Key: k.Account}, nil
+	case storage.SshKey:
+		return &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_SSH_KEY, Prompt: k.Prompt}, nil
+	case storage.Template:
+		return &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_TEMPLATE, Prompt: k.Name}, nil
+	case storage.CustomRecord:
+		return &pb.RecordKey{Type: pb.RecordType_RECORD_TYPE_CUSTOM, Prompt: k.Prompt}, nil
+	default:
+		return nil, fmt.Errorf("unsupported record type %T", key)
+	}
+}
+
+// localRecordKey преобразует ключ записи из ответа сервера в ключ записи хранилища.
+func localRecordKey(key *pb.RecordKey) (any, error) {
+	switch key.GetType() {
+	case pb.RecordType_RECORD_TYPE_CARD:
+		return storage.Card{Number: key.GetKey()}, nil
+	case pb.RecordType_RECORD_TYPE_LOGIN_PWD:
+		return storage.LoginPwd{Prompt: key.GetPrompt(), Login: key.GetKey()}, nil
+	case pb.RecordType_RECORD_TYPE_TEXT:
+		return storage.TextRecord{Prompt: key.GetPrompt()}, nil
+	case pb.RecordType_RECORD_TYPE_BINARY:
+		return storage.BinaryRecord{Prompt: key.GetPrompt()}, nil
+	case pb.RecordType_RECORD_TYPE_OTP:
+		return storage.Otp{Issuer: key.GetPrompt(), Account: key.GetKey()}, nil
+	case pb.RecordType_RECORD_TYPE_SSH_KEY:
+		return storage.SshKey{Prompt: key.GetPrompt()}, nil
+	case pb.RecordType_RECORD_TYPE_TEMPLATE:
+		return storage.Template{Name: key.GetPrompt()}, nil
+	case pb.RecordType_RECORD_TYPE_CUSTOM:
+		return storage.CustomRecord{Prompt: key.GetPrompt()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported record type %v", key.GetType())
+	}
+}
+
+// saveRevision сохраняет ревизию записи с ключом key, полученную от сервера в ответ на запись.
+func saveRevision(ctx context.Context, repo storage.Repositorier, key *pb.RecordKey, rev *pb.RecordRevision) error {
+	if rev == nil {
+		return nil
+	}
+	return repo.SaveRevision(ctx, UserLogin, storage.Revision{
+		Type:      int32(key.GetType()),
+		Prompt:    key.GetPrompt(),
+		Key:       key.GetKey(),
+		ID:        rev.GetRecordId(),
+		Revision:  rev.GetRevision(),
+		TimeStamp: rev.GetTimeStamp(),
+	})
+}
+
+// pbToRecord преобразует запись из ответа сервера для хранилища.
+func pbToRecord(r *pb.Record) (any, error) {
+	switch p := r.GetPayload().(type) {
+	case *pb.Record_Card:
+		return pbToCard(p.Card), nil
+	case *pb.Record_LoginPwd:
+		return pbToLogin(p.LoginPwd), nil
+	case *pb.Record_TextRecord:
+		return pbToText(p.TextRecord), nil
+	case *pb.Record_BinaryRecord:
+		return pbToBinary(p.BinaryRecord), nil
+	case *pb.Record_Otp:
+		return pbToOtp(p.Otp), nil
+	case *pb.Record_SshKey:
+		return pbToSshKey(p.SshKey), nil
+	case *pb.Record_Template:
+		return pbToTemplate(p.Template), nil
+	case *pb.Record_CustomRecord:
+		return pbToCustomRecord(p.CustomRecord), nil
+	default:
+		return nil, fmt.Errorf("unsupported record payload %T", p)
+	}
+}
+
+// decryptRecord расшифровывает запись любого типа для вывода пользователю.
+func decryptRecord(ctx context.Context, repo storage.Repositorier, r any, reveal bool) (DataPrinter, error) {
+	switch v := r.(type) {
+	case storage.Card:
+		uc, err := decryptCard(v)
+		if err != nil {
+			return nil, err
+		}
+		return Cards{uc}, nil
+	case storage.LoginPwd:
+		ul, err := decryptLoginPwd(v)
+		if err != nil {
+			return nil, err
+		}
+		return LoginPwds{ul}, nil
+	case storage.TextRecord:
+		ut, err := decryptTextRecord(v)
+		if err != nil {
+			return nil, err
+		}
+		return TextRecords{ut}, nil
+	case storage.BinaryRecord:
+		ub, err := decryptBinaryRecord(v)
+		if err != nil {
+			return nil, err
+		}
+		return BinaryRecords{ub}, nil
+	case storage.Otp:
+		k, note, err := decryptOtp(v)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		code, err := k.Code(now)
+		if err != nil {
+			return nil, err
+		}
+		return UserOtpCode{
+			Issuer:    k.Issuer,
+			Account:   k.Account,
+			Code:      code,
+			Remaining: k.Remaining(now),
+			Note:      note,
+		}, nil
+	case storage.SshKey:
+		uk, err := decryptSshKey(v)
+		if err != nil {
+			return nil, err
+		}
+		return SshKeys{uk}, nil
+	case storage.Template:
+		ut, err := decryptTemplate(v)
+		if err != nil {
+			return nil, err
+		}
+		return Templates{ut}, nil
+	case storage.CustomRecord:
+		uc, err := toUserCustomRecord(ctx, repo, v, reveal)
+		if err != nil {
+			return nil, err
+		}
+		return CustomRecords{uc}, nil
+	default:
+		return nil, fmt.Errorf("unsupported record type %T", r)
+	}
+}
+
+var historyExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	key, err := recordPbKey(args)
+	if err != nil {
+		return nil, err
+	}
+
+	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	r, err := cl.ListVersions(ctxMd, &pb.ListVersionsRequest{Key: key})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(RecordVersions, 0, len(r.GetVersions()))
+	for _, v := range r.GetVersions() {
+		rec, err := pbToRecord(v.GetRecord())
+		if err != nil {
+			return nil, err
+		}
+		p, err := decryptRecord(ctx, repo, rec, args.Reveal)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, RecordVersion{ID: v.GetVersion(), Record: p})
+	}
+
+	return res, nil
+}
+
+var restoreExec = func(ctx context.Context, args cmdparser.UserArgs, cl pb.InfoKeeperClient, repo storage.Repositorier) (DataPrinter, error) {
+	key, err := recordPbKey(args)
+	if err != nil {
+		return nil, err
+	}
+
+	md := metadata.New(map[string]string{authorizer.AccessToken: UserToken})
+	ctxMd := metadata.NewOutgoingContext(ctx, md)
+	r, err := cl.RestoreVersion(ctxMd, &pb.RestoreVersionRequest{Key: key, Version: args.VersionID})
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := pbToRecord(r.GetRecord())
+	if err != nil {
+		return nil, err
+	}
+	err = repo.RestoreRecord(ctx, UserLogin, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptRecord(ctx, repo, rec, args.Reveal)
+}
@@ -44,6 +44,48 @@ const (
 	CmdGetTextServer   UserCommandName = "getTextServer"
 	CmdGetBinaryServer UserCommandName = "getBinaryServer"
 
+	CmdGetCardsServer   UserCommandName = "getCardsServer"
+	CmdGetLoginsServer  UserCommandName = "getLoginsServer"
+	CmdGetTextsServer   UserCommandName = "getTextsServer"
+	CmdGetBinarysServer UserCommandName = "getBinarysServer"
+
+	CmdAddOtp UserCommandName = "addOtp"
+	CmdGetOtp UserCommandName = "getOtp"
+
+	CmdAddSshKey UserCommandName = "addSshKey"
+	CmdGetSshKey UserCommandName = "getSshKey"
+
+	CmdAddTemplate  UserCommandName = "addTemplate"
+	CmdGetTemplates UserCommandName = "getTemplates"
+	CmdAddCustom    UserCommandName = "addCustom"
+	CmdGetCustom    UserCommandName = "getCustom"
+	CmdGetCustoms   UserCommandName = "getCustoms"
+
+	CmdSetTags UserCommandName = "setTags"
+	CmdMove    UserCommandName = "move"
+
+	CmdHistory UserCommandName = "history"
+	CmdRestore UserCommandName = "restore"
+	CmdResolve UserCommandName = "resolve"
+
+	CmdAudit UserCommandName = "audit"
+	CmdUsage UserCommandName = "usage"
+
+	CmdInitKeys       UserCommandName = "initKeys"
+	CmdAddOrg         UserCommandName = "addOrg"
+	CmdAddCollection  UserCommandName = "addCollection"
+	CmdGetCollections UserCommandName = "getCollections"
+	CmdInvite         UserCommandName = "invite"
+	CmdToCollection   UserCommandName = "toCollection"
+	CmdGetShared      UserCommandName = "getShared"
+
+	CmdAddEmergency     UserCommandName = "addEmergency"
+	CmdRemoveEmergency  UserCommandName = "removeEmergency"
+	CmdDeclineEmergency UserCommandName = "declineEmergency"
+	CmdGetEmergency     UserCommandName = "getEmergency"
+	CmdRequestEmergency UserCommandName = "requestEmergency"
+	CmdEmergencyKey     UserCommandName = "emergencyKey"
+
 	CmdExit UserCommandName = "syncExit"
 	CmdVer  UserCommandName = "version"
 )
@@ -69,10 +111,10 @@ type Options struct {
 	GetText   bool `long:"gtext" description:"get text data using prompt, use with -p flag"`
 	GetBinary bool `long:"gbyte" description:"get binary data using prompt, use with -p flag"`
 
-	GetCards   bool `long:"gcards" description:"get all cards"`
-	GetLogins  bool `long:"gpwds" description:"get all pairs login-password"`
-	GetTexts   bool `long:"gtexts" description:"get all text data"`
-	GetBinarys bool `long:"gbytes" description:"get all binary data"`
+	GetCards   bool `long:"gcards" description:"get all cards, use with optional -g -d flags"`
+	GetLogins  bool `long:"gpwds" description:"get all pairs login-password, use with optional -g -d flags"`
+	GetTexts   bool `long:"gtexts" description:"get all text data, use with optional -g -d flags"`
+	GetBinarys bool `long:"gbytes" description:"get all binary data, use with optional -g -d flags"`
 
 	ForceAddCardServer   bool `long:"fcard" description:"add card to the server without verification, use with -n flag"`
 	ForceAddLoginServer  bool `long:"fpwd" description:"add pair login-password to the server without verification, use with -p -l flags"`
@@ -84,15 +126,74 @@ type Options struct {
 	GetTextServer   bool `long:"stext" description:"get text data from server using prompt, use with -p flag"`
 	GetBinaryServer bool `long:"sbyte" description:"get binary data from server using prompt, use with -p flag"`
 
-	UserLogin  string `short:"u" long:"userlogin" description:"user login"`
-	Prompt     string `short:"p" long:"prompt" description:"hint for users data"`
-	Login      string `short:"l" long:"login" description:"login for a login-password pair"`
-	Note       string `short:"m" long:"comment" description:"data description or comment"`
-	CardNumber string `short:"n" long:"number" description:"card number"`
-	CardDate   string `short:"e" long:"date" description:"card expiry date"`
-	CardCode   string `short:"v" long:"code" description:"card code"`
-	Text       string `short:"t" long:"text" description:"text data"`
-	Binary     string `short:"b" long:"byte" description:"path to the data file"`
+	GetCardsServer   bool `long:"scards" description:"list cards on the server without data, use with optional -s flag"`
+	GetLoginsServer  bool `long:"spwds" description:"list pairs login-password on the server without data, use with optional -s flag"`
+	GetTextsServer   bool `long:"stexts" description:"list text data on the server without data, use with optional -s flag"`
+	GetBinarysServer bool `long:"sbytes" description:"list binary data on the server without data, use with optional -s flag"`
+
+	AddOtp bool `long:"notp" description:"add one-time password generator from otpauth URI, use with -o -m flags"`
+	GetOtp bool `long:"gotp" description:"get current one-time password using issuer and account, use with -p -l flags"`
+
+	AddSshKey bool `long:"nssh" description:"add new SSH key from private key file, use with -p -k -m flags"`
+	GetSshKey bool `long:"gssh" description:"get SSH public key using prompt, use with -p flag"`
+
+	AddTemplate  bool `long:"ntmpl" description:"add new template of custom records, use with -r and repeated -f=name:type[:secret] flags"`
+	GetTemplates bool `long:"gtmpls" description:"get all templates of custom records, use with optional -g -d flags"`
+	AddCustom    bool `long:"ncustom" description:"add new custom record, use with -p -r -m and repeated -f=name=value flags"`
+	GetCustom    bool `long:"gcustom" description:"get custom record using prompt, use with -p and optional -w flags"`
+	GetCustoms   bool `long:"gcustoms" description:"get all custom records, use with optional -w -g -d flags"`
+
+	SetTags bool `long:"settags" description:"replace tags of record, use with -y, record key flags and repeated -g flags"`
+	Move    bool `long:"move" description:"move record to folder, use with -y, record key flags and -d flag"`
+
+	History bool `long:"history" description:"list previous versions of record on the server, use with -y, record key flags and optional -w flag"`
+	Restore bool `long:"restore" description:"restore previous version of record on the server, use with -y, record key flags, -i and optional -w flags"`
+	Resolve bool `long:"resolve" description:"resolve sync conflict of record, use with -y, record key flags and -c flags"`
+
+	Audit bool `long:"audit" description:"show access log of records on the server, use with optional -s -a flags"`
+	Usage bool `long:"usage" description:"show storage usage and quotas on the server"`
+
+	InitKeys       bool `long:"initkeys" description:"create key pair for shared collections"`
+	AddOrg         bool `long:"norg" description:"add new organization, use with --name flag"`
+	AddCollection  bool `long:"ncoll" description:"add new shared collection to organization, use with --org --name flags"`
+	GetCollections bool `long:"colls" description:"list shared collections with your role"`
+	Invite         bool `long:"invite" description:"invite user to shared collection, use with -j -u --role flags"`
+	ToCollection   bool `long:"tocoll" description:"move record to shared collection, use with -j, -y and record key flags"`
+	GetShared      bool `long:"gcoll" description:"get records of shared collection, use with -j and optional -w flags"`
+
+	AddEmergency     bool `long:"emadd" description:"add trusted contact for emergency access, use with -u --wait flags"`
+	RemoveEmergency  bool `long:"emremove" description:"remove trusted contact, use with -u flag"`
+	DeclineEmergency bool `long:"emdecline" description:"decline emergency access request of trusted contact, use with -u flag"`
+	GetEmergency     bool `long:"emlist" description:"list trusted contacts and users who trust you"`
+	RequestEmergency bool `long:"emrequest" description:"request emergency access to user vault, use with -u flag"`
+	EmergencyKey     bool `long:"emkey" description:"get records of user after waiting period, use with -u flag"`
+
+	UserLogin  string   `short:"u" long:"userlogin" description:"user login"`
+	Prompt     string   `short:"p" long:"prompt" description:"hint for users data"`
+	Login      string   `short:"l" long:"login" description:"login for a login-password pair"`
+	Note       string   `short:"m" long:"comment" description:"data description or comment"`
+	CardNumber string   `short:"n" long:"number" description:"card number"`
+	CardDate   string   `short:"e" long:"date" description:"card expiry date"`
+	CardCode   string   `short:"v" long:"code" description:"card code"`
+	Text       string   `short:"t" long:"text" description:"text data"`
+	Binary     string   `short:"b" long:"byte" description:"path to the data file"`
+	Since      string   `short:"s" long:"since" description:"modification time in RFC3339 format"`
+	Until      string   `short:"a" long:"until" description:"end of time range in RFC3339 format"`
+	OtpURI     string   `short:"o" long:"otpauth" description:"otpauth:// URI of one-time password generator"`
+	SshKey     string   `short:"k" long:"sshkey" description:"path to the private SSH key file"`
+	Template   string   `short:"r" long:"template" description:"template name of custom record"`
+	Fields     []string `short:"f" long:"field" description:"template field name:type[:secret] or custom record field name=value"`
+	Reveal     bool     `short:"w" long:"show-secrets" description:"show values of secret fields"`
+	Tags       []string `short:"g" long:"tag" description:"record tag"`
+	Folder     string   `short:"d" long:"folder" description:"record folder path like a/b/c"`
+	RecordType string   `short:"y" long:"type" description:"record type: card, login, text, binary, otp, ssh, template or custom"`
+	VersionID  int64    `short:"i" long:"version-id" description:"record version number from the history"`
+	Keep       string   `short:"c" long:"keep" description:"version of record to keep on sync conflict: local or server"`
+	Name       string   `long:"name" description:"organization or collection name"`
+	OrgID      int64    `long:"org" description:"organization id"`
+	Collection int64    `short:"j" long:"collection" description:"shared collection id"`
+	Role       string   `long:"role" description:"member role in shared collection: owner, editor or viewer"`
+	Wait       int64    `long:"wait" description:"waiting period of emergency access in hours"`
 
 	Exit bool `short:"x" long:"exit" description:"synchronization and exit"`
 	Ver  bool `long:"version" description:"version and build date"`
@@ -110,6 +211,24 @@ type UserArgs struct {
 	Pwd        string
 	Text       string
 	Binary     string
+	Since      string
+	Until      string
+	OtpURI     string
+	SshKey     string
+	Template   string
+	Fields     []string
+	Reveal     bool
+	Tags       []string
+	Folder     string
+	RecordType string
+	VersionID  int64
+	Keep       string
+	Name       string
+	OrgID      int64
+	Collection int64
+	Member     string
+	Role       string
+	Wait       int64
 }
 
 var opt Options
@@ -117,6 +236,7 @@ var parser = flags.NewParser(&opt, flags.Default)
 
 // ParseUserCmd парсит команду пользователя.
 func ParseUserCmd(c string) (cmdName string, args UserArgs, err error) {
+	defer clearOpt(&opt)
 	cSpl := splitCmd(c)
 
 	_, err = parser.ParseArgs(cSpl)
@@ -197,19 +317,19 @@ func ParseUserCmd(c string) (cmdName string, args UserArgs, err error) {
 
 	case opt.GetCards:
 		cmdName = CmdGetCards
-		args = UserArgs{}
+		args = UserArgs{Tags: opt.Tags, Folder: opt.Folder}
 		err = nil
 	case opt.GetLogins:
 		cmdName = CmdGetLogins
-		args = UserArgs{}
+		args = UserArgs{Tags: opt.Tags, Folder: opt.Folder}
 		err = nil
 	case opt.GetTexts:
 		cmdName = CmdGetTexts
-		args = UserArgs{}
+		args = UserArgs{Tags: opt.Tags, Folder: opt.Folder}
 		err = nil
 	case opt.GetBinarys:
 		cmdName = CmdGetBinarys
-		args = UserArgs{}
+		args = UserArgs{Tags: opt.Tags, Folder: opt.Folder}
 		err = nil
 
 	case opt.ForceAddCardServer:
@@ -246,6 +366,166 @@ func ParseUserCmd(c string) (cmdName string, args UserArgs, err error) {
 		args = UserArgs{Prompt: opt.Prompt}
 		err = nil
 
+	case opt.GetCardsServer:
+		cmdName = CmdGetCardsServer
+		args = UserArgs{Since: opt.Since}
+		err = nil
+	case opt.GetLoginsServer:
+		cmdName = CmdGetLoginsServer
+		args = UserArgs{Since: opt.Since}
+		err = nil
+	case opt.GetTextsServer:
+		cmdName = CmdGetTextsServer
+		args = UserArgs{Since: opt.Since}
+		err = nil
+	case opt.GetBinarysServer:
+		cmdName = CmdGetBinarysServer
+		args = UserArgs{Since: opt.Since}
+		err = nil
+
+	case opt.AddOtp:
+		cmdName = CmdAddOtp
+		args = UserArgs{Note: opt.Note, OtpURI: opt.OtpURI}
+		err = nil
+	case opt.GetOtp:
+		cmdName = CmdGetOtp
+		args = UserArgs{Prompt: opt.Prompt, Login: opt.Login}
+		err = nil
+
+	case opt.AddSshKey:
+		cmdName = CmdAddSshKey
+		args = UserArgs{Prompt: opt.Prompt, Note: opt.Note, SshKey: opt.SshKey}
+		err = nil
+	case opt.GetSshKey:
+		cmdName = CmdGetSshKey
+		args = UserArgs{Prompt: opt.Prompt}
+		err = nil
+
+	case opt.AddTemplate:
+		cmdName = CmdAddTemplate
+		args = UserArgs{Template: opt.Template, Fields: opt.Fields}
+		err = nil
+	case opt.GetTemplates:
+		cmdName = CmdGetTemplates
+		args = UserArgs{Tags: opt.Tags, Folder: opt.Folder}
+		err = nil
+	case opt.AddCustom:
+		cmdName = CmdAddCustom
+		args = UserArgs{Prompt: opt.Prompt, Note: opt.Note, Template: opt.Template, Fields: opt.Fields}
+		err = nil
+	case opt.GetCustom:
+		cmdName = CmdGetCustom
+		args = UserArgs{Prompt: opt.Prompt, Reveal: opt.Reveal}
+		err = nil
+	case opt.GetCustoms:
+		cmdName = CmdGetCustoms
+		args = UserArgs{Reveal: opt.Reveal, Tags: opt.Tags, Folder: opt.Folder}
+		err = nil
+
+	case opt.SetTags:
+		cmdName = CmdSetTags
+		args = UserArgs{
+			RecordType: opt.RecordType, Prompt: opt.Prompt, Login: opt.Login,
+			CardNumber: opt.CardNumber, Template: opt.Template, Tags: opt.Tags,
+		}
+		err = nil
+	case opt.Move:
+		cmdName = CmdMove
+		args = UserArgs{
+			RecordType: opt.RecordType, Prompt: opt.Prompt, Login: opt.Login,
+			CardNumber: opt.CardNumber, Template: opt.Template, Folder: opt.Folder,
+		}
+		err = nil
+
+	case opt.History:
+		cmdName = CmdHistory
+		args = UserArgs{
+			RecordType: opt.RecordType, Prompt: opt.Prompt, Login: opt.Login,
+			CardNumber: opt.CardNumber, Template: opt.Template, Reveal: opt.Reveal,
+		}
+		err = nil
+	case opt.Restore:
+		cmdName = CmdRestore
+		args = UserArgs{
+			RecordType: opt.RecordType, Prompt: opt.Prompt, Login: opt.Login,
+			CardNumber: opt.CardNumber, Template: opt.Template, VersionID: opt.VersionID,
+			Reveal: opt.Reveal,
+		}
+		err = nil
+	case opt.Resolve:
+		cmdName = CmdResolve
+		args = UserArgs{
+			RecordType: opt.RecordType, Prompt: opt.Prompt, Login: opt.Login,
+			CardNumber: opt.CardNumber, Template: opt.Template, Keep: opt.Keep,
+		}
+		err = nil
+
+	case opt.Audit:
+		cmdName = CmdAudit
+		args = UserArgs{Since: opt.Since, Until: opt.Until}
+		err = nil
+	case opt.Usage:
+		cmdName = CmdUsage
+		args = UserArgs{}
+		err = nil
+
+	case opt.InitKeys:
+		cmdName = CmdInitKeys
+		args = UserArgs{}
+		err = nil
+	case opt.AddOrg:
+		cmdName = CmdAddOrg
+		args = UserArgs{Name: opt.Name}
+		err = nil
+	case opt.AddCollection:
+		cmdName = CmdAddCollection
+		args = UserArgs{OrgID: opt.OrgID, Name: opt.Name}
+		err = nil
+	case opt.GetCollections:
+		cmdName = CmdGetCollections
+		args = UserArgs{}
+		err = nil
+	case opt.Invite:
+		cmdName = CmdInvite
+		args = UserArgs{Collection: opt.Collection, Member: opt.UserLogin, Role: opt.Role}
+		err = nil
+	case opt.ToCollection:
+		cmdName = CmdToCollection
+		args = UserArgs{
+			Collection: opt.Collection, RecordType: opt.RecordType, Prompt: opt.Prompt, Login: opt.Login,
+			CardNumber: opt.CardNumber, Template: opt.Template,
+		}
+		err = nil
+	case opt.GetShared:
+		cmdName = CmdGetShared
+		args = UserArgs{Collection: opt.Collection, Reveal: opt.Reveal}
+		err = nil
+
+	case opt.AddEmergency:
+		cmdName = CmdAddEmergency
+		args = UserArgs{Member: opt.UserLogin, Wait: opt.Wait}
+		err = nil
+	case opt.RemoveEmergency:
+		cmdName = CmdRemoveEmergency
+		args = UserArgs{Member: opt.UserLogin}
+		err = nil
+	case opt.DeclineEmergency:
+		cmdName = CmdDeclineEmergency
+		args = UserArgs{Member: opt.UserLogin}
+		err = nil
+	case opt.GetEmergency:
+		cmdName = CmdGetEmergency
+		args = UserArgs{}
+		err = nil
+	case opt.RequestEmergency:
+		cmdName = CmdRequestEmergency
+		args = UserArgs{Member: opt.UserLogin}
+		err = nil
+	case opt.EmergencyKey:
+		cmdName = CmdEmergencyKey
+		args = UserArgs{Member: opt.UserLogin}
+		err = nil
+
 	case opt.Exit:
 		cmdName = CmdExit
 		args = UserArgs{}
@@ -261,6 +541,5 @@ func ParseUserCmd(c string) (cmdName string, args UserArgs, err error) {
 		err = errors.New("unclear command")
 	}
 
-	clearOpt(&opt)
 	return
 }
@@ -231,6 +231,227 @@ func TestParseUserCmd(t *testing.T) {
 			wantArgs: UserArgs{},
 			wantErr:  true,
 		},
+		{
+			name:     "getCardsServer",
+			c:        "--scards -s=2024-01-02T15:04:05Z",
+			wantCmd:  CmdGetCardsServer,
+			wantArgs: UserArgs{Since: "2024-01-02T15:04:05Z"},
+			wantErr:  false,
+		},
+		{
+			name:     "getLoginsServer",
+			c:        "--spwds",
+			wantCmd:  CmdGetLoginsServer,
+			wantArgs: UserArgs{},
+			wantErr:  false,
+		},
+		{
+			name:     "getTextsServer",
+			c:        "--stexts",
+			wantCmd:  CmdGetTextsServer,
+			wantArgs: UserArgs{},
+			wantErr:  false,
+		},
+		{
+			name:     "getBinarysServer",
+			c:        "--sbytes -s=2024-01-02T15:04:05Z",
+			wantCmd:  CmdGetBinarysServer,
+			wantArgs: UserArgs{Since: "2024-01-02T15:04:05Z"},
+			wantErr:  false,
+		},
+		{
+			name:    "addOtp",
+			c:       "--notp -o=otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example -m=comment",
+			wantCmd: CmdAddOtp,
+			wantArgs: UserArgs{
+				Note:   "comment",
+				OtpURI: "otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example",
+			},
+			wantErr: false,
+		},
+		{
+			name:     "getOtp",
+			c:        "--gotp -p=Example -l=alice",
+			wantCmd:  CmdGetOtp,
+			wantArgs: UserArgs{Prompt: "Example", Login: "alice"},
+			wantErr:  false,
+		},
+		{
+			name:     "addSshKey",
+			c:        "--nssh -p=deploy -k=/home/user/.ssh/id_ed25519 -m=comment",
+			wantCmd:  CmdAddSshKey,
+			wantArgs: UserArgs{Prompt: "deploy", Note: "comment", SshKey: "/home/user/.ssh/id_ed25519"},
+			wantErr:  false,
+		},
+		{
+			name:     "getSshKey",
+			c:        "--gssh -p=deploy",
+			wantCmd:  CmdGetSshKey,
+			wantArgs: UserArgs{Prompt: "deploy"},
+			wantErr:  false,
+		},
+		{
+			name:     "addTemplate",
+			c:        "--ntmpl -r=server -f=host:text -f=port:number -f=password:text:secret",
+			wantCmd:  CmdAddTemplate,
+			wantArgs: UserArgs{Template: "server", Fields: []string{"host:text", "port:number", "password:text:secret"}},
+			wantErr:  false,
+		},
+		{
+			name:     "getTemplates",
+			c:        "--gtmpls",
+			wantCmd:  CmdGetTemplates,
+			wantArgs: UserArgs{},
+			wantErr:  false,
+		},
+		{
+			name:    "addCustom",
+			c:       "--ncustom -p=db -r=server -f=host=localhost -f=port=5432 -m=comment",
+			wantCmd: CmdAddCustom,
+			wantArgs: UserArgs{
+				Prompt: "db", Note: "comment", Template: "server",
+				Fields: []string{"host=localhost", "port=5432"},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "getCustom",
+			c:        "--gcustom -p=db -w",
+			wantCmd:  CmdGetCustom,
+			wantArgs: UserArgs{Prompt: "db", Reveal: true},
+			wantErr:  false,
+		},
+		{
+			name:     "getCustoms",
+			c:        "--gcustoms",
+			wantCmd:  CmdGetCustoms,
+			wantArgs: UserArgs{},
+			wantErr:  false,
+		},
+		{
+			name:     "getPwds with filter",
+			c:        "--gpwds -g=work -g=bank -d=finance/banks",
+			wantCmd:  CmdGetLogins,
+			wantArgs: UserArgs{Tags: []string{"work", "bank"}, Folder: "finance/banks"},
+			wantErr:  false,
+		},
+		{
+			name:     "setTags",
+			c:        "--settags -y=login -p=mail -l=user -g=work -g=mail",
+			wantCmd:  CmdSetTags,
+			wantArgs: UserArgs{RecordType: "login", Prompt: "mail", Login: "user", Tags: []string{"work", "mail"}},
+			wantErr:  false,
+		},
+		{
+			name:     "move",
+			c:        "--move -y=card -n=1234 -d=finance",
+			wantCmd:  CmdMove,
+			wantArgs: UserArgs{RecordType: "card", CardNumber: "1234", Folder: "finance"},
+			wantErr:  false,
+		},
+		{
+			name:     "history",
+			c:        "--history -y=text -p=notes",
+			wantCmd:  CmdHistory,
+			wantArgs: UserArgs{RecordType: "text", Prompt: "notes"},
+			wantErr:  false,
+		},
+		{
+			name:     "restore",
+			c:        "--restore -y=login -p=mail -l=user -i=3",
+			wantCmd:  CmdRestore,
+			wantArgs: UserArgs{RecordType: "login", Prompt: "mail", Login: "user", VersionID: 3},
+			wantErr:  false,
+		},
+		{
+			name:    "restore with bad version",
+			c:       "--restore -y=text -p=notes -i=abc",
+			wantErr: true,
+		},
+		{
+			name:     "resolve",
+			c:        "--resolve -y=otp -p=Example -l=alice -c=server",
+			wantCmd:  CmdResolve,
+			wantArgs: UserArgs{RecordType: "otp", Prompt: "Example", Login: "alice", Keep: "server"},
+			wantErr:  false,
+		},
+		{
+			name:     "audit",
+			c:        "--audit -s=2024-01-01T00:00:00Z -a=2024-02-01T00:00:00Z",
+			wantCmd:  CmdAudit,
+			wantArgs: UserArgs{Since: "2024-01-01T00:00:00Z", Until: "2024-02-01T00:00:00Z"},
+			wantErr:  false,
+		},
+		{
+			name:     "usage",
+			c:        "--usage",
+			wantCmd:  CmdUsage,
+			wantArgs: UserArgs{},
+			wantErr:  false,
+		},
+		{
+			name:     "addOrg",
+			c:        "--norg --name=team",
+			wantCmd:  CmdAddOrg,
+			wantArgs: UserArgs{Name: "team"},
+			wantErr:  false,
+		},
+		{
+			name:     "addCollection",
+			c:        "--ncoll --org=3 --name=ops",
+			wantCmd:  CmdAddCollection,
+			wantArgs: UserArgs{OrgID: 3, Name: "ops"},
+			wantErr:  false,
+		},
+		{
+			name:     "invite",
+			c:        "--invite -j=5 -u=bob --role=editor",
+			wantCmd:  CmdInvite,
+			wantArgs: UserArgs{Collection: 5, Member: "bob", Role: "editor"},
+			wantErr:  false,
+		},
+		{
+			name:     "toCollection",
+			c:        "--tocoll -j=5 -y=login -p=db -l=admin",
+			wantCmd:  CmdToCollection,
+			wantArgs: UserArgs{Collection: 5, RecordType: "login", Prompt: "db", Login: "admin"},
+			wantErr:  false,
+		},
+		{
+			name:     "getShared",
+			c:        "--gcoll -j=5 -w",
+			wantCmd:  CmdGetShared,
+			wantArgs: UserArgs{Collection: 5, Reveal: true},
+			wantErr:  false,
+		},
+		{
+			name:     "addEmergency",
+			c:        "--emadd -u=bob --wait=48",
+			wantCmd:  CmdAddEmergency,
+			wantArgs: UserArgs{Member: "bob", Wait: 48},
+			wantErr:  false,
+		},
+		{
+			name:     "declineEmergency",
+			c:        "--emdecline -u=bob",
+			wantCmd:  CmdDeclineEmergency,
+			wantArgs: UserArgs{Member: "bob"},
+			wantErr:  false,
+		},
+		{
+			name:     "getEmergency",
+			c:        "--emlist",
+			wantCmd:  CmdGetEmergency,
+			wantArgs: UserArgs{},
+			wantErr:  false,
+		},
+		{
+			name:     "emergencyKey",
+			c:        "--emkey -u=alice",
+			wantCmd:  CmdEmergencyKey,
+			wantArgs: UserArgs{Member: "alice"},
+			wantErr:  false,
+		},
 		{
 			name:     "ver",
 			c:        "--version",
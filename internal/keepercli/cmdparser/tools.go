@@ -14,15 +14,28 @@ func splitCmd(cmd string) []string {
 func clearOpt(opt *Options) error {
 	opt.AddBinary = false
 	opt.AddCard = false
+	opt.AddCollection = false
+	opt.AddEmergency = false
 	opt.AddLogin = false
+	opt.AddOrg = false
+	opt.AddOtp = false
+	opt.AddCustom = false
+	opt.AddSshKey = false
+	opt.AddTemplate = false
 	opt.AddText = false
+	opt.Audit = false
 	opt.Auth = false
 	opt.Binary = ""
 	opt.CardCode = ""
 	opt.CardDate = ""
 	opt.CardDate = ""
 	opt.CardNumber = ""
+	opt.Collection = 0
+	opt.DeclineEmergency = false
+	opt.EmergencyKey = false
 	opt.Exit = false
+	opt.Fields = nil
+	opt.Folder = ""
 	opt.ForceAddBinaryServer = false
 	opt.ForceAddCardServer = false
 	opt.ForceAddLoginServer = false
@@ -30,25 +43,62 @@ func clearOpt(opt *Options) error {
 	opt.GetBinary = false
 	opt.GetBinaryServer = false
 	opt.GetBinarys = false
+	opt.GetBinarysServer = false
 	opt.GetCard = false
 	opt.GetCardServer = false
 	opt.GetCards = false
+	opt.GetCardsServer = false
+	opt.GetCustom = false
+	opt.GetCollections = false
+	opt.GetCustoms = false
+	opt.GetEmergency = false
 	opt.GetLogin = false
 	opt.GetLoginServer = false
 	opt.GetLogins = false
+	opt.GetLoginsServer = false
+	opt.GetOtp = false
+	opt.GetShared = false
+	opt.GetSshKey = false
+	opt.GetTemplates = false
 	opt.GetText = false
 	opt.GetTextServer = false
 	opt.GetTexts = false
+	opt.GetTextsServer = false
+	opt.History = false
+	opt.InitKeys = false
+	opt.Invite = false
+	opt.Keep = ""
 	opt.Login = ""
+	opt.Move = false
+	opt.Name = ""
 	opt.Note = ""
+	opt.OrgID = 0
+	opt.OtpURI = ""
 	opt.Prompt = ""
+	opt.RecordType = ""
 	opt.Reg = false
+	opt.RemoveEmergency = false
+	opt.RequestEmergency = false
+	opt.Resolve = false
+	opt.Restore = false
+	opt.Reveal = false
+	opt.Role = ""
+	opt.Since = ""
+	opt.SetTags = false
+	opt.SshKey = ""
+	opt.Tags = nil
+	opt.Template = ""
 	opt.Text = ""
+	opt.ToCollection = false
+	opt.Until = ""
 	opt.UpdBinary = false
 	opt.UpdCard = false
 	opt.UpdLogin = false
 	opt.UpdText = false
+	opt.Usage = false
 	opt.UserLogin = ""
+	opt.VersionID = 0
+	opt.Wait = 0
 
 	return nil
 }
@@ -63,6 +63,32 @@ func TestClearOpt(t *testing.T) {
 			CardCode:             "q",
 			Text:                 "q",
 			Binary:               "q",
+			Tags:                 []string{"q"},
+			Folder:               "q",
+			RecordType:           "q",
+			SetTags:              true,
+			Move:                 true,
+			History:              true,
+			Restore:              true,
+			VersionID:            1,
+			InitKeys:             true,
+			AddOrg:               true,
+			AddCollection:        true,
+			GetCollections:       true,
+			Invite:               true,
+			ToCollection:         true,
+			GetShared:            true,
+			Name:                 "q",
+			OrgID:                1,
+			Collection:           1,
+			Role:                 "q",
+			AddEmergency:         true,
+			RemoveEmergency:      true,
+			DeclineEmergency:     true,
+			GetEmergency:         true,
+			RequestEmergency:     true,
+			EmergencyKey:         true,
+			Wait:                 1,
 			Exit:                 true,
 		}
 		err := clearOpt(&o)
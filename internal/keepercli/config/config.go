@@ -14,13 +14,28 @@ import (
 
 // Flags хранит настройки запуска приложения.
 type Flags struct {
-	GRPC           string `env:"GRPC_PORT" json:"grpc"`
-	DBURI          string `env:"DATABASE_NAME" json:"database_uri"`
-	ConfigFileName string `env:"CONFIG"`
+	GRPC               string `env:"GRPC_PORT" json:"grpc"`
+	DBURI              string `env:"DATABASE_NAME" json:"database_uri"`
+	ConfigFileName     string `env:"CONFIG"`
+	AgentSocket        string `env:"AGENT_SOCKET" json:"agent_socket"`
+	StorageTimeout     int    `env:"STORAGE_TIMEOUT" json:"storage_timeout"`
+	StorageBulkTimeout int    `env:"STORAGE_BULK_TIMEOUT" json:"storage_bulk_timeout"`
+	RequestTimeout     int    `env:"REQUEST_TIMEOUT" json:"request_timeout"`
+	SyncTimeout        int    `env:"SYNC_TIMEOUT" json:"sync_timeout"`
+	Mode               string
 }
 
+// ModeAgent режим работы клиента в качестве ssh-agent.
+const ModeAgent = "agent"
+
 const (
-	defGRPC string = ":3200"
+	defGRPC        string = ":3200"
+	defAgentSocket string = "keeper-agent.sock"
+
+	defStorageTimeout int = 3
+	defStorageBulk    int = 30
+	defRequestTimeout int = 30
+	defSyncTimeout    int = 600
 )
 
 func readFromConf(c *Flags) error {
@@ -51,6 +66,21 @@ func readFromConf(c *Flags) error {
 	if c.GRPC == "" {
 		c.GRPC = conf.GRPC
 	}
+	if c.AgentSocket == "" {
+		c.AgentSocket = conf.AgentSocket
+	}
+	if c.StorageTimeout == 0 {
+		c.StorageTimeout = conf.StorageTimeout
+	}
+	if c.StorageBulkTimeout == 0 {
+		c.StorageBulkTimeout = conf.StorageBulkTimeout
+	}
+	if c.RequestTimeout == 0 {
+		c.RequestTimeout = conf.RequestTimeout
+	}
+	if c.SyncTimeout == 0 {
+		c.SyncTimeout = conf.SyncTimeout
+	}
 
 	return nil
 }
@@ -63,8 +93,15 @@ func NewConfig() *Flags {
 	flag.StringVar(&c.DBURI, "d", "", "path to the database file")
 	flag.StringVar(&c.ConfigFileName, "c", "", "the name of configuration file")
 	flag.StringVar(&c.ConfigFileName, "config", "", "the name of configuration file")
+	flag.StringVar(&c.AgentSocket, "a", "", "path to the ssh-agent socket")
+	flag.IntVar(&c.StorageTimeout, "storage-timeout", 0, "timeout of single record local database operations, in seconds")
+	flag.IntVar(&c.StorageBulkTimeout, "storage-bulk-timeout", 0, "timeout of sync reads and writes in the local database, in seconds")
+	flag.IntVar(&c.RequestTimeout, "request-timeout", 0, "deadline of a regular command, in seconds")
+	flag.IntVar(&c.SyncTimeout, "sync-timeout", 0, "deadline of commands with synchronization or binary transfer, in seconds")
 	flag.Parse()
 
+	c.Mode = flag.Arg(0)
+
 	env.Parse(c)
 
 	if c.ConfigFileName != "" {
@@ -74,5 +111,21 @@ func NewConfig() *Flags {
 		}
 	}
 
+	if c.AgentSocket == "" {
+		c.AgentSocket = defAgentSocket
+	}
+	if c.StorageTimeout <= 0 {
+		c.StorageTimeout = defStorageTimeout
+	}
+	if c.StorageBulkTimeout <= 0 {
+		c.StorageBulkTimeout = defStorageBulk
+	}
+	if c.RequestTimeout <= 0 {
+		c.RequestTimeout = defRequestTimeout
+	}
+	if c.SyncTimeout <= 0 {
+		c.SyncTimeout = defSyncTimeout
+	}
+
 	return c
 }
@@ -10,6 +10,11 @@ func TestNewConfig(t *testing.T) {
 	flags := NewConfig()
 	if assert.NotEmpty(t, flags) {
 		assert.NotEmpty(t, flags.GRPC)
+		assert.NotEmpty(t, flags.AgentSocket)
+		assert.Positive(t, flags.StorageTimeout)
+		assert.Positive(t, flags.StorageBulkTimeout)
+		assert.Positive(t, flags.RequestTimeout)
+		assert.Positive(t, flags.SyncTimeout)
 	}
 }
 
@@ -19,4 +24,8 @@ func TestReadFromConf(t *testing.T) {
 	}
 	err := readFromConf(&c)
 	assert.NoError(t, err)
+	assert.Equal(t, 5, c.StorageTimeout)
+	assert.Equal(t, 60, c.StorageBulkTimeout)
+	assert.Equal(t, 20, c.RequestTimeout)
+	assert.Equal(t, 900, c.SyncTimeout)
 }
@@ -10,7 +10,12 @@ import (
 var UserKey []byte
 
 func generateNonce() (nonce []byte, aesgcm cipher.AEAD, err error) {
-	key := sha256.Sum256(UserKey)
+	return generateNonceFor(UserKey)
+}
+
+// generateNonceFor создает шифр и детерминированный nonce для ключа secret.
+func generateNonceFor(secret []byte) (nonce []byte, aesgcm cipher.AEAD, err error) {
+	key := sha256.Sum256(secret)
 
 	aesblock, err := aes.NewCipher(key[:])
 	if err != nil {
@@ -0,0 +1,96 @@
+package cryptor
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"golang.org/x/crypto/nacl/box"
+)
+
+// CollectionKeySize размер ключа общей коллекции.
+const CollectionKeySize = 32
+
+// keySize размер открытого и закрытого ключей пользователя.
+const keySize = 32
+
+// GenerateKeyPair создает пару ключей пользователя для общих коллекций.
+func GenerateKeyPair() (publicKey []byte, privateKey []byte, err error) {
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pub[:], priv[:], nil
+}
+
+// GenerateCollectionKey создает случайный ключ общей коллекции.
+func GenerateCollectionKey() (key []byte, err error) {
+	key = make([]byte, CollectionKeySize)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GenerateEmergencyKey создает случайный ключ экстренного доступа.
+// Ключ не связан с паролем пользователя и шифрует только копию записей для доверенного лица.
+func GenerateEmergencyKey() (key []byte, err error) {
+	return GenerateCollectionKey()
+}
+
+// toKey проверяет размер ключа и преобразует его в массив.
+func toKey(b []byte) (*[keySize]byte, error) {
+	if len(b) != keySize {
+		return nil, errors.New("invalid key size")
+	}
+	var key [keySize]byte
+	copy(key[:], b)
+	return &key, nil
+}
+
+// WrapKey шифрует ключ коллекции открытым ключом участника.
+// Расшифровать результат может только владелец закрытого ключа.
+func WrapKey(publicKey []byte, key []byte) (wrapped []byte, err error) {
+	pub, err := toKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return box.SealAnonymous(nil, key, pub, rand.Reader)
+}
+
+// UnwrapKey расшифровывает ключ коллекции парой ключей участника.
+func UnwrapKey(publicKey []byte, privateKey []byte, wrapped []byte) (key []byte, err error) {
+	pub, err := toKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := toKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := box.OpenAnonymous(nil, wrapped, pub, priv)
+	if !ok {
+		return nil, errors.New("can't unwrap key")
+	}
+	return key, nil
+}
+
+// EncryptsWithKey шифрует данные ключом коллекции.
+func EncryptsWithKey(key []byte, data []byte) (result []byte, err error) {
+	nonce, aesgcm, err := generateNonceFor(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aesgcm.Seal(nil, nonce, data, nil), nil
+}
+
+// DecryptsWithKey дешифрует данные ключом коллекции.
+func DecryptsWithKey(key []byte, data []byte) (result []byte, err error) {
+	nonce, aesgcm, err := generateNonceFor(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aesgcm.Open(nil, nonce, data, nil)
+}
@@ -0,0 +1,49 @@
+package cryptor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapKey(t *testing.T) {
+	pub, priv, err := GenerateKeyPair()
+	require.NoError(t, err)
+	key, err := GenerateCollectionKey()
+	require.NoError(t, err)
+	assert.Len(t, key, CollectionKeySize)
+
+	wrapped, err := WrapKey(pub, key)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, wrapped)
+
+	got, err := UnwrapKey(pub, priv, wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	otherPub, otherPriv, err := GenerateKeyPair()
+	require.NoError(t, err)
+	_, err = UnwrapKey(otherPub, otherPriv, wrapped)
+	assert.Error(t, err)
+
+	_, err = WrapKey([]byte("short"), key)
+	assert.Error(t, err)
+}
+
+func TestEncryptsWithKey(t *testing.T) {
+	key := []byte("collection key")
+	enc, err := EncryptsWithKey(key, []byte("data"))
+	require.NoError(t, err)
+
+	again, err := EncryptsWithKey(key, []byte("data"))
+	require.NoError(t, err)
+	assert.Equal(t, enc, again)
+
+	dec, err := DecryptsWithKey(key, enc)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), dec)
+
+	_, err = DecryptsWithKey([]byte("other key"), enc)
+	assert.Error(t, err)
+}
@@ -0,0 +1,167 @@
+// Пакет customrecord реализует шаблоны пользовательских записей
+// с типизированными полями и проверку значений по шаблону.
+package customrecord
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType тип поля шаблона.
+type FieldType = string
+
+const (
+	// FieldText - произвольный текст.
+	FieldText FieldType = "text"
+	// FieldNumber - целое число.
+	FieldNumber FieldType = "number"
+	// FieldDate - дата в формате 2006-01-02.
+	FieldDate FieldType = "date"
+)
+
+// DateLayout формат значений полей с типом FieldDate.
+const DateLayout = "2006-01-02"
+
+// SecretMask выводится вместо значений секретных полей.
+const SecretMask = "********"
+
+// Field описывает поле шаблона.
+type Field struct {
+	Name   string    `json:"name"`
+	Type   FieldType `json:"type"`
+	Secret bool      `json:"secret,omitempty"`
+}
+
+// Template описывает структуру пользовательской записи.
+type Template struct {
+	Name   string
+	Fields []Field
+}
+
+// ParseFields разбирает описания полей вида name:type или name:type:secret.
+func ParseFields(specs []string) ([]Field, error) {
+	if len(specs) == 0 {
+		return nil, errors.New("template has no fields")
+	}
+
+	fields := make([]Field, 0, len(specs))
+	names := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		parts := strings.Split(spec, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid field %q, want name:type[:secret]", spec)
+		}
+		f := Field{Name: parts[0], Type: parts[1]}
+		switch f.Type {
+		case FieldText, FieldNumber, FieldDate:
+		default:
+			return nil, fmt.Errorf("unknown type %q of field %q", f.Type, f.Name)
+		}
+		if len(parts) == 3 {
+			if parts[2] != "secret" {
+				return nil, fmt.Errorf("invalid field %q, want name:type[:secret]", spec)
+			}
+			f.Secret = true
+		}
+		if names[f.Name] {
+			return nil, fmt.Errorf("duplicate field %q", f.Name)
+		}
+		names[f.Name] = true
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// ParseValues разбирает значения полей вида name=value.
+func ParseValues(specs []string) (map[string]string, error) {
+	values := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		name, value, ok := strings.Cut(spec, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid value %q, want name=value", spec)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// Validate проверяет, что значения соответствуют полям шаблона.
+// Поля шаблона без значений допускаются.
+func (t Template) Validate(values map[string]string) error {
+	for name, value := range values {
+		f, ok := t.field(name)
+		if !ok {
+			return fmt.Errorf("template %q has no field %q", t.Name, name)
+		}
+		switch f.Type {
+		case FieldNumber:
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				return fmt.Errorf("field %q must be a number", name)
+			}
+		case FieldDate:
+			if _, err := time.Parse(DateLayout, value); err != nil {
+				return fmt.Errorf("field %q must be a date in format %s", name, DateLayout)
+			}
+		}
+	}
+	return nil
+}
+
+func (t Template) field(name string) (Field, bool) {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Value хранит значение поля для вывода пользователю.
+type Value struct {
+	Name  string
+	Value string
+}
+
+// Display возвращает значения в порядке полей шаблона.
+// Значения секретных полей заменяются на SecretMask, если reveal = false.
+func (t Template) Display(values map[string]string, reveal bool) []Value {
+	res := make([]Value, 0, len(values))
+	for _, f := range t.Fields {
+		v, ok := values[f.Name]
+		if !ok {
+			continue
+		}
+		if f.Secret && !reveal {
+			v = SecretMask
+		}
+		res = append(res, Value{Name: f.Name, Value: v})
+	}
+	return res
+}
+
+// MarshalFields кодирует поля шаблона для хранения.
+func MarshalFields(fields []Field) ([]byte, error) {
+	return json.Marshal(fields)
+}
+
+// UnmarshalFields декодирует поля шаблона.
+func UnmarshalFields(data []byte) (fields []Field, err error) {
+	err = json.Unmarshal(data, &fields)
+	return
+}
+
+// MarshalValues кодирует значения полей для хранения.
+func MarshalValues(values map[string]string) ([]byte, error) {
+	return json.Marshal(values)
+}
+
+// UnmarshalValues декодирует значения полей.
+func UnmarshalValues(data []byte) (values map[string]string, err error) {
+	err = json.Unmarshal(data, &values)
+	return
+}
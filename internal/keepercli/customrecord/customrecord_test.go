@@ -0,0 +1,106 @@
+package customrecord
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTemplate = Template{
+	Name: "db",
+	Fields: []Field{
+		{Name: "host", Type: FieldText},
+		{Name: "port", Type: FieldNumber},
+		{Name: "password", Type: FieldText, Secret: true},
+		{Name: "expiry", Type: FieldDate},
+	},
+}
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		want    []Field
+		wantErr bool
+	}{
+		{
+			name:  "ok test",
+			specs: []string{"host:text", "port:number", "password:text:secret", "expiry:date"},
+			want:  testTemplate.Fields,
+		},
+		{name: "empty test", specs: nil, wantErr: true},
+		{name: "no type test", specs: []string{"host"}, wantErr: true},
+		{name: "unknown type test", specs: []string{"host:url"}, wantErr: true},
+		{name: "bad flag test", specs: []string{"host:text:hidden"}, wantErr: true},
+		{name: "duplicate test", specs: []string{"host:text", "host:number"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseFields(tt.specs)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	values, err := ParseValues([]string{"host=db.local", "password=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"host": "db.local", "password": "a=b"}, values)
+
+	_, err = ParseValues([]string{"host"})
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		wantErr bool
+	}{
+		{name: "ok test", values: map[string]string{"host": "db", "port": "5432", "expiry": "2030-01-02"}},
+		{name: "unknown field test", values: map[string]string{"user": "admin"}, wantErr: true},
+		{name: "bad number test", values: map[string]string{"port": "http"}, wantErr: true},
+		{name: "bad date test", values: map[string]string{"expiry": "01/30"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testTemplate.Validate(tt.values)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	values := map[string]string{"password": "pwd", "host": "db"}
+
+	assert.Equal(t, []Value{{Name: "host", Value: "db"}, {Name: "password", Value: SecretMask}},
+		testTemplate.Display(values, false))
+	assert.Equal(t, []Value{{Name: "host", Value: "db"}, {Name: "password", Value: "pwd"}},
+		testTemplate.Display(values, true))
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := MarshalFields(testTemplate.Fields)
+	require.NoError(t, err)
+	fields, err := UnmarshalFields(data)
+	require.NoError(t, err)
+	assert.Equal(t, testTemplate.Fields, fields)
+
+	data, err = MarshalValues(map[string]string{"host": "db"})
+	require.NoError(t, err)
+	values, err := UnmarshalValues(data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"host": "db"}, values)
+}
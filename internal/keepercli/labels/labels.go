@@ -0,0 +1,85 @@
+// Пакет labels реализует теги и папки для упорядочивания записей
+// и фильтрацию записей по ним.
+package labels
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// tagSep разделитель тегов при хранении.
+const tagSep = ","
+
+// NormalizeTags проверяет теги, удаляет пробелы по краям, пустые значения и повторы.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	res := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if strings.Contains(tag, tagSep) {
+			return nil, fmt.Errorf("tag %q must not contain %q", tag, tagSep)
+		}
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// JoinTags объединяет теги в строку для хранения.
+func JoinTags(tags []string) string {
+	return strings.Join(tags, tagSep)
+}
+
+// SplitTags разбирает строку с тегами, полученную из JoinTags.
+func SplitTags(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, tagSep)
+}
+
+// NormalizeFolder приводит путь папки к виду a/b/c.
+// Пустая строка означает, что запись не находится в папке.
+func NormalizeFolder(folder string) string {
+	folder = strings.Trim(folder, "/ ")
+	if folder == "" {
+		return ""
+	}
+	return strings.Trim(path.Clean(folder), "/")
+}
+
+// Filter условия отбора записей по тегам и папке.
+type Filter struct {
+	Tags   []string
+	Folder string
+}
+
+// Match проверяет, что запись содержит все теги фильтра
+// и находится в папке фильтра или в одной из ее вложенных папок.
+func (f Filter) Match(tags []string, folder string) bool {
+	for _, want := range f.Tags {
+		found := false
+		for _, tag := range tags {
+			if tag == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	want := NormalizeFolder(f.Folder)
+	if want == "" {
+		return true
+	}
+	folder = NormalizeFolder(folder)
+	return folder == want || strings.HasPrefix(folder, want+"/")
+}
@@ -0,0 +1,69 @@
+package labels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "ok test", tags: []string{"work", " bank ", "work", ""}, want: []string{"bank", "work"}},
+		{name: "empty test", tags: nil, want: []string{}},
+		{name: "separator test", tags: []string{"a,b"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := NormalizeTags(tt.tags)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tags)
+		})
+	}
+}
+
+func TestJoinSplitTags(t *testing.T) {
+	assert.Equal(t, "bank,work", JoinTags([]string{"bank", "work"}))
+	assert.Equal(t, []string{"bank", "work"}, SplitTags("bank,work"))
+	assert.Nil(t, SplitTags(""))
+}
+
+func TestNormalizeFolder(t *testing.T) {
+	assert.Equal(t, "a/b", NormalizeFolder("/a//b/"))
+	assert.Equal(t, "a", NormalizeFolder("a/b/.."))
+	assert.Equal(t, "", NormalizeFolder(" / "))
+}
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		tags   []string
+		folder string
+		want   bool
+	}{
+		{name: "empty filter test", filter: Filter{}, want: true},
+		{name: "tags test", filter: Filter{Tags: []string{"bank", "work"}}, tags: []string{"work", "bank", "home"}, want: true},
+		{name: "missing tag test", filter: Filter{Tags: []string{"bank", "work"}}, tags: []string{"work"}, want: false},
+		{name: "folder test", filter: Filter{Folder: "a/b"}, folder: "a/b", want: true},
+		{name: "subfolder test", filter: Filter{Folder: "/a/"}, folder: "a/b", want: true},
+		{name: "other folder test", filter: Filter{Folder: "a/b"}, folder: "a/bc", want: false},
+		{name: "no folder test", filter: Filter{Folder: "a"}, folder: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Match(tt.tags, tt.folder))
+		})
+	}
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/Julia-ivv/info-keeper.git/internal/proto/pb (interfaces: InfoKeeperClient)
+// Source: github.com/Julia-ivv/info-keeper.git/internal/proto/pb (interfaces: InfoKeeperClient,InfoKeeper_UploadBinaryClient,InfoKeeper_DownloadBinaryClient,InfoKeeper_WatchChangesClient)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 
 	proto "github.com/Julia-ivv/info-keeper.git/internal/proto/pb"
 )
@@ -77,6 +78,46 @@ func (mr *MockInfoKeeperClientMockRecorder) AddCard(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCard", reflect.TypeOf((*MockInfoKeeperClient)(nil).AddCard), varargs...)
 }
 
+// AddCollectionMember mocks base method.
+func (m *MockInfoKeeperClient) AddCollectionMember(arg0 context.Context, arg1 *proto.AddCollectionMemberRequest, arg2 ...grpc.CallOption) (*proto.AddCollectionMemberResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddCollectionMember", varargs...)
+	ret0, _ := ret[0].(*proto.AddCollectionMemberResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCollectionMember indicates an expected call of AddCollectionMember.
+func (mr *MockInfoKeeperClientMockRecorder) AddCollectionMember(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCollectionMember", reflect.TypeOf((*MockInfoKeeperClient)(nil).AddCollectionMember), varargs...)
+}
+
+// AddEmergencyContact mocks base method.
+func (m *MockInfoKeeperClient) AddEmergencyContact(arg0 context.Context, arg1 *proto.AddEmergencyContactRequest, arg2 ...grpc.CallOption) (*proto.AddEmergencyContactResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddEmergencyContact", varargs...)
+	ret0, _ := ret[0].(*proto.AddEmergencyContactResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddEmergencyContact indicates an expected call of AddEmergencyContact.
+func (mr *MockInfoKeeperClientMockRecorder) AddEmergencyContact(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEmergencyContact", reflect.TypeOf((*MockInfoKeeperClient)(nil).AddEmergencyContact), varargs...)
+}
+
 // AddLogin mocks base method.
 func (m *MockInfoKeeperClient) AddLogin(arg0 context.Context, arg1 *proto.AddLoginRequest, arg2 ...grpc.CallOption) (*proto.AddLoginResponse, error) {
 	m.ctrl.T.Helper()
@@ -97,6 +138,26 @@ func (mr *MockInfoKeeperClientMockRecorder) AddLogin(arg0, arg1 interface{}, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLogin", reflect.TypeOf((*MockInfoKeeperClient)(nil).AddLogin), varargs...)
 }
 
+// AddRecord mocks base method.
+func (m *MockInfoKeeperClient) AddRecord(arg0 context.Context, arg1 *proto.AddRecordRequest, arg2 ...grpc.CallOption) (*proto.AddRecordResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddRecord", varargs...)
+	ret0, _ := ret[0].(*proto.AddRecordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRecord indicates an expected call of AddRecord.
+func (mr *MockInfoKeeperClientMockRecorder) AddRecord(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecord", reflect.TypeOf((*MockInfoKeeperClient)(nil).AddRecord), varargs...)
+}
+
 // AddTextData mocks base method.
 func (m *MockInfoKeeperClient) AddTextData(arg0 context.Context, arg1 *proto.AddTextDataRequest, arg2 ...grpc.CallOption) (*proto.AddTextDataResponse, error) {
 	m.ctrl.T.Helper()
@@ -157,6 +218,86 @@ func (mr *MockInfoKeeperClientMockRecorder) AuthUser(arg0, arg1 interface{}, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthUser", reflect.TypeOf((*MockInfoKeeperClient)(nil).AuthUser), varargs...)
 }
 
+// CreateCollection mocks base method.
+func (m *MockInfoKeeperClient) CreateCollection(arg0 context.Context, arg1 *proto.CreateCollectionRequest, arg2 ...grpc.CallOption) (*proto.CreateCollectionResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateCollection", varargs...)
+	ret0, _ := ret[0].(*proto.CreateCollectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockInfoKeeperClientMockRecorder) CreateCollection(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockInfoKeeperClient)(nil).CreateCollection), varargs...)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockInfoKeeperClient) CreateOrganization(arg0 context.Context, arg1 *proto.CreateOrganizationRequest, arg2 ...grpc.CallOption) (*proto.CreateOrganizationResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateOrganization", varargs...)
+	ret0, _ := ret[0].(*proto.CreateOrganizationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockInfoKeeperClientMockRecorder) CreateOrganization(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockInfoKeeperClient)(nil).CreateOrganization), varargs...)
+}
+
+// DeclineEmergencyAccess mocks base method.
+func (m *MockInfoKeeperClient) DeclineEmergencyAccess(arg0 context.Context, arg1 *proto.DeclineEmergencyAccessRequest, arg2 ...grpc.CallOption) (*proto.DeclineEmergencyAccessResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeclineEmergencyAccess", varargs...)
+	ret0, _ := ret[0].(*proto.DeclineEmergencyAccessResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclineEmergencyAccess indicates an expected call of DeclineEmergencyAccess.
+func (mr *MockInfoKeeperClientMockRecorder) DeclineEmergencyAccess(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineEmergencyAccess", reflect.TypeOf((*MockInfoKeeperClient)(nil).DeclineEmergencyAccess), varargs...)
+}
+
+// DownloadBinary mocks base method.
+func (m *MockInfoKeeperClient) DownloadBinary(arg0 context.Context, arg1 *proto.DownloadBinaryRequest, arg2 ...grpc.CallOption) (proto.InfoKeeper_DownloadBinaryClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DownloadBinary", varargs...)
+	ret0, _ := ret[0].(proto.InfoKeeper_DownloadBinaryClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadBinary indicates an expected call of DownloadBinary.
+func (mr *MockInfoKeeperClientMockRecorder) DownloadBinary(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBinary", reflect.TypeOf((*MockInfoKeeperClient)(nil).DownloadBinary), varargs...)
+}
+
 // ForceUpdateBinaryRecord mocks base method.
 func (m *MockInfoKeeperClient) ForceUpdateBinaryRecord(arg0 context.Context, arg1 *proto.ForceUpdateBinaryRecordRequest, arg2 ...grpc.CallOption) (*proto.ForceUpdateBinaryRecordResponse, error) {
 	m.ctrl.T.Helper()
@@ -217,6 +358,26 @@ func (mr *MockInfoKeeperClientMockRecorder) ForceUpdateLoginPwd(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceUpdateLoginPwd", reflect.TypeOf((*MockInfoKeeperClient)(nil).ForceUpdateLoginPwd), varargs...)
 }
 
+// ForceUpdateRecord mocks base method.
+func (m *MockInfoKeeperClient) ForceUpdateRecord(arg0 context.Context, arg1 *proto.ForceUpdateRecordRequest, arg2 ...grpc.CallOption) (*proto.ForceUpdateRecordResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ForceUpdateRecord", varargs...)
+	ret0, _ := ret[0].(*proto.ForceUpdateRecordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForceUpdateRecord indicates an expected call of ForceUpdateRecord.
+func (mr *MockInfoKeeperClientMockRecorder) ForceUpdateRecord(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceUpdateRecord", reflect.TypeOf((*MockInfoKeeperClient)(nil).ForceUpdateRecord), varargs...)
+}
+
 // ForceUpdateTextRecord mocks base method.
 func (m *MockInfoKeeperClient) ForceUpdateTextRecord(arg0 context.Context, arg1 *proto.ForceUpdateTextRecordRequest, arg2 ...grpc.CallOption) (*proto.ForceUpdateTextRecordResponse, error) {
 	m.ctrl.T.Helper()
@@ -237,6 +398,106 @@ func (mr *MockInfoKeeperClientMockRecorder) ForceUpdateTextRecord(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceUpdateTextRecord", reflect.TypeOf((*MockInfoKeeperClient)(nil).ForceUpdateTextRecord), varargs...)
 }
 
+// GetAuditLog mocks base method.
+func (m *MockInfoKeeperClient) GetAuditLog(arg0 context.Context, arg1 *proto.GetAuditLogRequest, arg2 ...grpc.CallOption) (*proto.GetAuditLogResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAuditLog", varargs...)
+	ret0, _ := ret[0].(*proto.GetAuditLogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockInfoKeeperClientMockRecorder) GetAuditLog(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockInfoKeeperClient)(nil).GetAuditLog), varargs...)
+}
+
+// GetEmergencyKey mocks base method.
+func (m *MockInfoKeeperClient) GetEmergencyKey(arg0 context.Context, arg1 *proto.GetEmergencyKeyRequest, arg2 ...grpc.CallOption) (*proto.GetEmergencyKeyResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetEmergencyKey", varargs...)
+	ret0, _ := ret[0].(*proto.GetEmergencyKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmergencyKey indicates an expected call of GetEmergencyKey.
+func (mr *MockInfoKeeperClientMockRecorder) GetEmergencyKey(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmergencyKey", reflect.TypeOf((*MockInfoKeeperClient)(nil).GetEmergencyKey), varargs...)
+}
+
+// GetPublicKey mocks base method.
+func (m *MockInfoKeeperClient) GetPublicKey(arg0 context.Context, arg1 *proto.GetPublicKeyRequest, arg2 ...grpc.CallOption) (*proto.GetPublicKeyResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetPublicKey", varargs...)
+	ret0, _ := ret[0].(*proto.GetPublicKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicKey indicates an expected call of GetPublicKey.
+func (mr *MockInfoKeeperClientMockRecorder) GetPublicKey(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicKey", reflect.TypeOf((*MockInfoKeeperClient)(nil).GetPublicKey), varargs...)
+}
+
+// GetRecord mocks base method.
+func (m *MockInfoKeeperClient) GetRecord(arg0 context.Context, arg1 *proto.GetRecordRequest, arg2 ...grpc.CallOption) (*proto.GetRecordResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetRecord", varargs...)
+	ret0, _ := ret[0].(*proto.GetRecordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecord indicates an expected call of GetRecord.
+func (mr *MockInfoKeeperClientMockRecorder) GetRecord(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecord", reflect.TypeOf((*MockInfoKeeperClient)(nil).GetRecord), varargs...)
+}
+
+// GetUsage mocks base method.
+func (m *MockInfoKeeperClient) GetUsage(arg0 context.Context, arg1 *proto.GetUsageRequest, arg2 ...grpc.CallOption) (*proto.GetUsageResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetUsage", varargs...)
+	ret0, _ := ret[0].(*proto.GetUsageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockInfoKeeperClientMockRecorder) GetUsage(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockInfoKeeperClient)(nil).GetUsage), varargs...)
+}
+
 // GetUserBinary mocks base method.
 func (m *MockInfoKeeperClient) GetUserBinary(arg0 context.Context, arg1 *proto.GetUserBinaryRequest, arg2 ...grpc.CallOption) (*proto.GetUserBinaryResponse, error) {
 	m.ctrl.T.Helper()
@@ -277,6 +538,26 @@ func (mr *MockInfoKeeperClientMockRecorder) GetUserCard(arg0, arg1 interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCard", reflect.TypeOf((*MockInfoKeeperClient)(nil).GetUserCard), varargs...)
 }
 
+// GetUserKeys mocks base method.
+func (m *MockInfoKeeperClient) GetUserKeys(arg0 context.Context, arg1 *proto.GetUserKeysRequest, arg2 ...grpc.CallOption) (*proto.GetUserKeysResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetUserKeys", varargs...)
+	ret0, _ := ret[0].(*proto.GetUserKeysResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserKeys indicates an expected call of GetUserKeys.
+func (mr *MockInfoKeeperClientMockRecorder) GetUserKeys(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserKeys", reflect.TypeOf((*MockInfoKeeperClient)(nil).GetUserKeys), varargs...)
+}
+
 // GetUserLogin mocks base method.
 func (m *MockInfoKeeperClient) GetUserLogin(arg0 context.Context, arg1 *proto.GetUserLoginRequest, arg2 ...grpc.CallOption) (*proto.GetUserLoginResponse, error) {
 	m.ctrl.T.Helper()
@@ -317,22 +598,665 @@ func (mr *MockInfoKeeperClientMockRecorder) GetUserText(arg0, arg1 interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserText", reflect.TypeOf((*MockInfoKeeperClient)(nil).GetUserText), varargs...)
 }
 
-// SyncUserData mocks base method.
-func (m *MockInfoKeeperClient) SyncUserData(arg0 context.Context, arg1 *proto.SyncUserDataRequest, arg2 ...grpc.CallOption) (*proto.SyncUserDataResponse, error) {
+// ListCollectionRecords mocks base method.
+func (m *MockInfoKeeperClient) ListCollectionRecords(arg0 context.Context, arg1 *proto.ListCollectionRecordsRequest, arg2 ...grpc.CallOption) (*proto.ListCollectionRecordsResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "SyncUserData", varargs...)
-	ret0, _ := ret[0].(*proto.SyncUserDataResponse)
+	ret := m.ctrl.Call(m, "ListCollectionRecords", varargs...)
+	ret0, _ := ret[0].(*proto.ListCollectionRecordsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SyncUserData indicates an expected call of SyncUserData.
-func (mr *MockInfoKeeperClientMockRecorder) SyncUserData(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// ListCollectionRecords indicates an expected call of ListCollectionRecords.
+func (mr *MockInfoKeeperClientMockRecorder) ListCollectionRecords(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncUserData", reflect.TypeOf((*MockInfoKeeperClient)(nil).SyncUserData), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollectionRecords", reflect.TypeOf((*MockInfoKeeperClient)(nil).ListCollectionRecords), varargs...)
+}
+
+// ListCollections mocks base method.
+func (m *MockInfoKeeperClient) ListCollections(arg0 context.Context, arg1 *proto.ListCollectionsRequest, arg2 ...grpc.CallOption) (*proto.ListCollectionsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListCollections", varargs...)
+	ret0, _ := ret[0].(*proto.ListCollectionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollections indicates an expected call of ListCollections.
+func (mr *MockInfoKeeperClientMockRecorder) ListCollections(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollections", reflect.TypeOf((*MockInfoKeeperClient)(nil).ListCollections), varargs...)
+}
+
+// ListEmergencyContacts mocks base method.
+func (m *MockInfoKeeperClient) ListEmergencyContacts(arg0 context.Context, arg1 *proto.ListEmergencyContactsRequest, arg2 ...grpc.CallOption) (*proto.ListEmergencyContactsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListEmergencyContacts", varargs...)
+	ret0, _ := ret[0].(*proto.ListEmergencyContactsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmergencyContacts indicates an expected call of ListEmergencyContacts.
+func (mr *MockInfoKeeperClientMockRecorder) ListEmergencyContacts(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmergencyContacts", reflect.TypeOf((*MockInfoKeeperClient)(nil).ListEmergencyContacts), varargs...)
+}
+
+// ListRecords mocks base method.
+func (m *MockInfoKeeperClient) ListRecords(arg0 context.Context, arg1 *proto.ListRecordsRequest, arg2 ...grpc.CallOption) (*proto.ListRecordsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListRecords", varargs...)
+	ret0, _ := ret[0].(*proto.ListRecordsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecords indicates an expected call of ListRecords.
+func (mr *MockInfoKeeperClientMockRecorder) ListRecords(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecords", reflect.TypeOf((*MockInfoKeeperClient)(nil).ListRecords), varargs...)
+}
+
+// ListVersions mocks base method.
+func (m *MockInfoKeeperClient) ListVersions(arg0 context.Context, arg1 *proto.ListVersionsRequest, arg2 ...grpc.CallOption) (*proto.ListVersionsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListVersions", varargs...)
+	ret0, _ := ret[0].(*proto.ListVersionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockInfoKeeperClientMockRecorder) ListVersions(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockInfoKeeperClient)(nil).ListVersions), varargs...)
+}
+
+// MoveRecordToCollection mocks base method.
+func (m *MockInfoKeeperClient) MoveRecordToCollection(arg0 context.Context, arg1 *proto.MoveRecordToCollectionRequest, arg2 ...grpc.CallOption) (*proto.MoveRecordToCollectionResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MoveRecordToCollection", varargs...)
+	ret0, _ := ret[0].(*proto.MoveRecordToCollectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveRecordToCollection indicates an expected call of MoveRecordToCollection.
+func (mr *MockInfoKeeperClientMockRecorder) MoveRecordToCollection(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveRecordToCollection", reflect.TypeOf((*MockInfoKeeperClient)(nil).MoveRecordToCollection), varargs...)
+}
+
+// RemoveEmergencyContact mocks base method.
+func (m *MockInfoKeeperClient) RemoveEmergencyContact(arg0 context.Context, arg1 *proto.RemoveEmergencyContactRequest, arg2 ...grpc.CallOption) (*proto.RemoveEmergencyContactResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveEmergencyContact", varargs...)
+	ret0, _ := ret[0].(*proto.RemoveEmergencyContactResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveEmergencyContact indicates an expected call of RemoveEmergencyContact.
+func (mr *MockInfoKeeperClientMockRecorder) RemoveEmergencyContact(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveEmergencyContact", reflect.TypeOf((*MockInfoKeeperClient)(nil).RemoveEmergencyContact), varargs...)
+}
+
+// RequestEmergencyAccess mocks base method.
+func (m *MockInfoKeeperClient) RequestEmergencyAccess(arg0 context.Context, arg1 *proto.RequestEmergencyAccessRequest, arg2 ...grpc.CallOption) (*proto.RequestEmergencyAccessResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RequestEmergencyAccess", varargs...)
+	ret0, _ := ret[0].(*proto.RequestEmergencyAccessResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestEmergencyAccess indicates an expected call of RequestEmergencyAccess.
+func (mr *MockInfoKeeperClientMockRecorder) RequestEmergencyAccess(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmergencyAccess", reflect.TypeOf((*MockInfoKeeperClient)(nil).RequestEmergencyAccess), varargs...)
+}
+
+// RestoreVersion mocks base method.
+func (m *MockInfoKeeperClient) RestoreVersion(arg0 context.Context, arg1 *proto.RestoreVersionRequest, arg2 ...grpc.CallOption) (*proto.RestoreVersionResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreVersion", varargs...)
+	ret0, _ := ret[0].(*proto.RestoreVersionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreVersion indicates an expected call of RestoreVersion.
+func (mr *MockInfoKeeperClientMockRecorder) RestoreVersion(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVersion", reflect.TypeOf((*MockInfoKeeperClient)(nil).RestoreVersion), varargs...)
+}
+
+// SetUserKeys mocks base method.
+func (m *MockInfoKeeperClient) SetUserKeys(arg0 context.Context, arg1 *proto.SetUserKeysRequest, arg2 ...grpc.CallOption) (*proto.SetUserKeysResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetUserKeys", varargs...)
+	ret0, _ := ret[0].(*proto.SetUserKeysResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserKeys indicates an expected call of SetUserKeys.
+func (mr *MockInfoKeeperClientMockRecorder) SetUserKeys(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserKeys", reflect.TypeOf((*MockInfoKeeperClient)(nil).SetUserKeys), varargs...)
+}
+
+// SyncUserData mocks base method.
+func (m *MockInfoKeeperClient) SyncUserData(arg0 context.Context, arg1 *proto.SyncUserDataRequest, arg2 ...grpc.CallOption) (*proto.SyncUserDataResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SyncUserData", varargs...)
+	ret0, _ := ret[0].(*proto.SyncUserDataResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncUserData indicates an expected call of SyncUserData.
+func (mr *MockInfoKeeperClientMockRecorder) SyncUserData(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncUserData", reflect.TypeOf((*MockInfoKeeperClient)(nil).SyncUserData), varargs...)
+}
+
+// UpdateEmergencyRecords mocks base method.
+func (m *MockInfoKeeperClient) UpdateEmergencyRecords(arg0 context.Context, arg1 *proto.UpdateEmergencyRecordsRequest, arg2 ...grpc.CallOption) (*proto.UpdateEmergencyRecordsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateEmergencyRecords", varargs...)
+	ret0, _ := ret[0].(*proto.UpdateEmergencyRecordsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEmergencyRecords indicates an expected call of UpdateEmergencyRecords.
+func (mr *MockInfoKeeperClientMockRecorder) UpdateEmergencyRecords(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmergencyRecords", reflect.TypeOf((*MockInfoKeeperClient)(nil).UpdateEmergencyRecords), varargs...)
+}
+
+// UploadBinary mocks base method.
+func (m *MockInfoKeeperClient) UploadBinary(arg0 context.Context, arg1 ...grpc.CallOption) (proto.InfoKeeper_UploadBinaryClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UploadBinary", varargs...)
+	ret0, _ := ret[0].(proto.InfoKeeper_UploadBinaryClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadBinary indicates an expected call of UploadBinary.
+func (mr *MockInfoKeeperClientMockRecorder) UploadBinary(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadBinary", reflect.TypeOf((*MockInfoKeeperClient)(nil).UploadBinary), varargs...)
+}
+
+// WatchChanges mocks base method.
+func (m *MockInfoKeeperClient) WatchChanges(arg0 context.Context, arg1 *proto.WatchChangesRequest, arg2 ...grpc.CallOption) (proto.InfoKeeper_WatchChangesClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchChanges", varargs...)
+	ret0, _ := ret[0].(proto.InfoKeeper_WatchChangesClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchChanges indicates an expected call of WatchChanges.
+func (mr *MockInfoKeeperClientMockRecorder) WatchChanges(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchChanges", reflect.TypeOf((*MockInfoKeeperClient)(nil).WatchChanges), varargs...)
+}
+
+// MockInfoKeeper_UploadBinaryClient is a mock of InfoKeeper_UploadBinaryClient interface.
+type MockInfoKeeper_UploadBinaryClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockInfoKeeper_UploadBinaryClientMockRecorder
+}
+
+// MockInfoKeeper_UploadBinaryClientMockRecorder is the mock recorder for MockInfoKeeper_UploadBinaryClient.
+type MockInfoKeeper_UploadBinaryClientMockRecorder struct {
+	mock *MockInfoKeeper_UploadBinaryClient
+}
+
+// NewMockInfoKeeper_UploadBinaryClient creates a new mock instance.
+func NewMockInfoKeeper_UploadBinaryClient(ctrl *gomock.Controller) *MockInfoKeeper_UploadBinaryClient {
+	mock := &MockInfoKeeper_UploadBinaryClient{ctrl: ctrl}
+	mock.recorder = &MockInfoKeeper_UploadBinaryClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInfoKeeper_UploadBinaryClient) EXPECT() *MockInfoKeeper_UploadBinaryClientMockRecorder {
+	return m.recorder
+}
+
+// CloseAndRecv mocks base method.
+func (m *MockInfoKeeper_UploadBinaryClient) CloseAndRecv() (*proto.UploadBinaryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAndRecv")
+	ret0, _ := ret[0].(*proto.UploadBinaryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAndRecv indicates an expected call of CloseAndRecv.
+func (mr *MockInfoKeeper_UploadBinaryClientMockRecorder) CloseAndRecv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAndRecv", reflect.TypeOf((*MockInfoKeeper_UploadBinaryClient)(nil).CloseAndRecv))
+}
+
+// CloseSend mocks base method.
+func (m *MockInfoKeeper_UploadBinaryClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockInfoKeeper_UploadBinaryClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockInfoKeeper_UploadBinaryClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockInfoKeeper_UploadBinaryClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockInfoKeeper_UploadBinaryClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockInfoKeeper_UploadBinaryClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockInfoKeeper_UploadBinaryClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockInfoKeeper_UploadBinaryClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockInfoKeeper_UploadBinaryClient)(nil).Header))
+}
+
+// RecvMsg mocks base method.
+func (m *MockInfoKeeper_UploadBinaryClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockInfoKeeper_UploadBinaryClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockInfoKeeper_UploadBinaryClient)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockInfoKeeper_UploadBinaryClient) Send(arg0 *proto.UploadBinaryRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockInfoKeeper_UploadBinaryClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockInfoKeeper_UploadBinaryClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockInfoKeeper_UploadBinaryClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockInfoKeeper_UploadBinaryClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockInfoKeeper_UploadBinaryClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockInfoKeeper_UploadBinaryClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockInfoKeeper_UploadBinaryClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockInfoKeeper_UploadBinaryClient)(nil).Trailer))
+}
+
+// MockInfoKeeper_DownloadBinaryClient is a mock of InfoKeeper_DownloadBinaryClient interface.
+type MockInfoKeeper_DownloadBinaryClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockInfoKeeper_DownloadBinaryClientMockRecorder
+}
+
+// MockInfoKeeper_DownloadBinaryClientMockRecorder is the mock recorder for MockInfoKeeper_DownloadBinaryClient.
+type MockInfoKeeper_DownloadBinaryClientMockRecorder struct {
+	mock *MockInfoKeeper_DownloadBinaryClient
+}
+
+// NewMockInfoKeeper_DownloadBinaryClient creates a new mock instance.
+func NewMockInfoKeeper_DownloadBinaryClient(ctrl *gomock.Controller) *MockInfoKeeper_DownloadBinaryClient {
+	mock := &MockInfoKeeper_DownloadBinaryClient{ctrl: ctrl}
+	mock.recorder = &MockInfoKeeper_DownloadBinaryClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInfoKeeper_DownloadBinaryClient) EXPECT() *MockInfoKeeper_DownloadBinaryClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockInfoKeeper_DownloadBinaryClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockInfoKeeper_DownloadBinaryClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockInfoKeeper_DownloadBinaryClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryClient) Recv() (*proto.DownloadBinaryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*proto.DownloadBinaryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockInfoKeeper_DownloadBinaryClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockInfoKeeper_DownloadBinaryClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockInfoKeeper_DownloadBinaryClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockInfoKeeper_DownloadBinaryClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockInfoKeeper_DownloadBinaryClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockInfoKeeper_DownloadBinaryClient)(nil).Trailer))
+}
+
+// MockInfoKeeper_WatchChangesClient is a mock of InfoKeeper_WatchChangesClient interface.
+type MockInfoKeeper_WatchChangesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockInfoKeeper_WatchChangesClientMockRecorder
+}
+
+// MockInfoKeeper_WatchChangesClientMockRecorder is the mock recorder for MockInfoKeeper_WatchChangesClient.
+type MockInfoKeeper_WatchChangesClientMockRecorder struct {
+	mock *MockInfoKeeper_WatchChangesClient
+}
+
+// NewMockInfoKeeper_WatchChangesClient creates a new mock instance.
+func NewMockInfoKeeper_WatchChangesClient(ctrl *gomock.Controller) *MockInfoKeeper_WatchChangesClient {
+	mock := &MockInfoKeeper_WatchChangesClient{ctrl: ctrl}
+	mock.recorder = &MockInfoKeeper_WatchChangesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInfoKeeper_WatchChangesClient) EXPECT() *MockInfoKeeper_WatchChangesClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockInfoKeeper_WatchChangesClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockInfoKeeper_WatchChangesClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockInfoKeeper_WatchChangesClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockInfoKeeper_WatchChangesClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockInfoKeeper_WatchChangesClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockInfoKeeper_WatchChangesClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockInfoKeeper_WatchChangesClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockInfoKeeper_WatchChangesClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockInfoKeeper_WatchChangesClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockInfoKeeper_WatchChangesClient) Recv() (*proto.ChangeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*proto.ChangeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockInfoKeeper_WatchChangesClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockInfoKeeper_WatchChangesClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockInfoKeeper_WatchChangesClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockInfoKeeper_WatchChangesClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockInfoKeeper_WatchChangesClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockInfoKeeper_WatchChangesClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockInfoKeeper_WatchChangesClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockInfoKeeper_WatchChangesClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockInfoKeeper_WatchChangesClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockInfoKeeper_WatchChangesClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockInfoKeeper_WatchChangesClient)(nil).Trailer))
 }
@@ -64,6 +64,20 @@ func (mr *MockRepositorierMockRecorder) AddCard(arg0, arg1, arg2, arg3, arg4, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCard", reflect.TypeOf((*MockRepositorier)(nil).AddCard), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 }
 
+// AddCustomRecord mocks base method.
+func (m *MockRepositorier) AddCustomRecord(arg0 context.Context, arg1 string, arg2 storage.CustomRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCustomRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCustomRecord indicates an expected call of AddCustomRecord.
+func (mr *MockRepositorierMockRecorder) AddCustomRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCustomRecord", reflect.TypeOf((*MockRepositorier)(nil).AddCustomRecord), arg0, arg1, arg2)
+}
+
 // AddLoginPwd mocks base method.
 func (m *MockRepositorier) AddLoginPwd(arg0 context.Context, arg1 string, arg2, arg3, arg4, arg5 []byte, arg6 string) error {
 	m.ctrl.T.Helper()
@@ -78,18 +92,60 @@ func (mr *MockRepositorierMockRecorder) AddLoginPwd(arg0, arg1, arg2, arg3, arg4
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLoginPwd", reflect.TypeOf((*MockRepositorier)(nil).AddLoginPwd), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 }
 
+// AddOtp mocks base method.
+func (m *MockRepositorier) AddOtp(arg0 context.Context, arg1 string, arg2 storage.Otp) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOtp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOtp indicates an expected call of AddOtp.
+func (mr *MockRepositorierMockRecorder) AddOtp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOtp", reflect.TypeOf((*MockRepositorier)(nil).AddOtp), arg0, arg1, arg2)
+}
+
+// AddSshKey mocks base method.
+func (m *MockRepositorier) AddSshKey(arg0 context.Context, arg1 string, arg2 storage.SshKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSshKey", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSshKey indicates an expected call of AddSshKey.
+func (mr *MockRepositorierMockRecorder) AddSshKey(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSshKey", reflect.TypeOf((*MockRepositorier)(nil).AddSshKey), arg0, arg1, arg2)
+}
+
 // AddSyncData mocks base method.
-func (m *MockRepositorier) AddSyncData(arg0 context.Context, arg1 string, arg2 []storage.Card, arg3 []storage.LoginPwd, arg4 []storage.TextRecord, arg5 []storage.BinaryRecord) error {
+func (m *MockRepositorier) AddSyncData(arg0 context.Context, arg1 string, arg2 []storage.Card, arg3 []storage.LoginPwd, arg4 []storage.TextRecord, arg5 []storage.BinaryRecord, arg6 []storage.Otp, arg7 []storage.SshKey, arg8 []storage.Template, arg9 []storage.CustomRecord) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddSyncData", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret := m.ctrl.Call(m, "AddSyncData", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddSyncData indicates an expected call of AddSyncData.
-func (mr *MockRepositorierMockRecorder) AddSyncData(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+func (mr *MockRepositorierMockRecorder) AddSyncData(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSyncData", reflect.TypeOf((*MockRepositorier)(nil).AddSyncData), arg0, arg1, arg2, arg3, arg4, arg5)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSyncData", reflect.TypeOf((*MockRepositorier)(nil).AddSyncData), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9)
+}
+
+// AddTemplate mocks base method.
+func (m *MockRepositorier) AddTemplate(arg0 context.Context, arg1 string, arg2 storage.Template) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTemplate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTemplate indicates an expected call of AddTemplate.
+func (mr *MockRepositorierMockRecorder) AddTemplate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTemplate", reflect.TypeOf((*MockRepositorier)(nil).AddTemplate), arg0, arg1, arg2)
 }
 
 // AddTextRecord mocks base method.
@@ -134,6 +190,20 @@ func (mr *MockRepositorierMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRepositorier)(nil).Close))
 }
 
+// DeleteRecord mocks base method.
+func (m *MockRepositorier) DeleteRecord(arg0 context.Context, arg1 string, arg2 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecord indicates an expected call of DeleteRecord.
+func (mr *MockRepositorierMockRecorder) DeleteRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecord", reflect.TypeOf((*MockRepositorier)(nil).DeleteRecord), arg0, arg1, arg2)
+}
+
 // GetBinaryRecord mocks base method.
 func (m *MockRepositorier) GetBinaryRecord(arg0 context.Context, arg1 string, arg2 []byte) (storage.BinaryRecord, error) {
 	m.ctrl.T.Helper()
@@ -164,19 +234,19 @@ func (mr *MockRepositorierMockRecorder) GetCard(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCard", reflect.TypeOf((*MockRepositorier)(nil).GetCard), arg0, arg1, arg2)
 }
 
-// GetLastSyncTime mocks base method.
-func (m *MockRepositorier) GetLastSyncTime(arg0 context.Context, arg1 string) (string, error) {
+// GetCustomRecord mocks base method.
+func (m *MockRepositorier) GetCustomRecord(arg0 context.Context, arg1 string, arg2 []byte) (storage.CustomRecord, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLastSyncTime", arg0, arg1)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetCustomRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(storage.CustomRecord)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLastSyncTime indicates an expected call of GetLastSyncTime.
-func (mr *MockRepositorierMockRecorder) GetLastSyncTime(arg0, arg1 interface{}) *gomock.Call {
+// GetCustomRecord indicates an expected call of GetCustomRecord.
+func (mr *MockRepositorierMockRecorder) GetCustomRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastSyncTime", reflect.TypeOf((*MockRepositorier)(nil).GetLastSyncTime), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomRecord", reflect.TypeOf((*MockRepositorier)(nil).GetCustomRecord), arg0, arg1, arg2)
 }
 
 // GetLoginPwd mocks base method.
@@ -194,6 +264,81 @@ func (mr *MockRepositorierMockRecorder) GetLoginPwd(arg0, arg1, arg2, arg3 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginPwd", reflect.TypeOf((*MockRepositorier)(nil).GetLoginPwd), arg0, arg1, arg2, arg3)
 }
 
+// GetOtp mocks base method.
+func (m *MockRepositorier) GetOtp(arg0 context.Context, arg1 string, arg2, arg3 []byte) (storage.Otp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOtp", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(storage.Otp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOtp indicates an expected call of GetOtp.
+func (mr *MockRepositorierMockRecorder) GetOtp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOtp", reflect.TypeOf((*MockRepositorier)(nil).GetOtp), arg0, arg1, arg2, arg3)
+}
+
+// GetRevision mocks base method.
+func (m *MockRepositorier) GetRevision(arg0 context.Context, arg1 string, arg2 int32, arg3, arg4 []byte) (storage.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(storage.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockRepositorierMockRecorder) GetRevision(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockRepositorier)(nil).GetRevision), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetSshKey mocks base method.
+func (m *MockRepositorier) GetSshKey(arg0 context.Context, arg1 string, arg2 []byte) (storage.SshKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSshKey", arg0, arg1, arg2)
+	ret0, _ := ret[0].(storage.SshKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSshKey indicates an expected call of GetSshKey.
+func (mr *MockRepositorierMockRecorder) GetSshKey(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSshKey", reflect.TypeOf((*MockRepositorier)(nil).GetSshKey), arg0, arg1, arg2)
+}
+
+// GetSyncRevision mocks base method.
+func (m *MockRepositorier) GetSyncRevision(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncRevision", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSyncRevision indicates an expected call of GetSyncRevision.
+func (mr *MockRepositorierMockRecorder) GetSyncRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncRevision", reflect.TypeOf((*MockRepositorier)(nil).GetSyncRevision), arg0, arg1)
+}
+
+// GetTemplate mocks base method.
+func (m *MockRepositorier) GetTemplate(arg0 context.Context, arg1 string, arg2 []byte) (storage.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(storage.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockRepositorierMockRecorder) GetTemplate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockRepositorier)(nil).GetTemplate), arg0, arg1, arg2)
+}
+
 // GetTextRecord mocks base method.
 func (m *MockRepositorier) GetTextRecord(arg0 context.Context, arg1 string, arg2 []byte) (storage.TextRecord, error) {
 	m.ctrl.T.Helper()
@@ -224,6 +369,21 @@ func (mr *MockRepositorierMockRecorder) GetUserBinaryRecordsAfterTime(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBinaryRecordsAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserBinaryRecordsAfterTime), arg0, arg1, arg2)
 }
 
+// GetUserBinaryRecordsToSync mocks base method.
+func (m *MockRepositorier) GetUserBinaryRecordsToSync(arg0 context.Context, arg1 string) ([]storage.BinaryRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBinaryRecordsToSync", arg0, arg1)
+	ret0, _ := ret[0].([]storage.BinaryRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBinaryRecordsToSync indicates an expected call of GetUserBinaryRecordsToSync.
+func (mr *MockRepositorierMockRecorder) GetUserBinaryRecordsToSync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBinaryRecordsToSync", reflect.TypeOf((*MockRepositorier)(nil).GetUserBinaryRecordsToSync), arg0, arg1)
+}
+
 // GetUserCardsAfterTime mocks base method.
 func (m *MockRepositorier) GetUserCardsAfterTime(arg0 context.Context, arg1, arg2 string) ([]storage.Card, error) {
 	m.ctrl.T.Helper()
@@ -239,6 +399,51 @@ func (mr *MockRepositorierMockRecorder) GetUserCardsAfterTime(arg0, arg1, arg2 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCardsAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserCardsAfterTime), arg0, arg1, arg2)
 }
 
+// GetUserCardsToSync mocks base method.
+func (m *MockRepositorier) GetUserCardsToSync(arg0 context.Context, arg1 string) ([]storage.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCardsToSync", arg0, arg1)
+	ret0, _ := ret[0].([]storage.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCardsToSync indicates an expected call of GetUserCardsToSync.
+func (mr *MockRepositorierMockRecorder) GetUserCardsToSync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCardsToSync", reflect.TypeOf((*MockRepositorier)(nil).GetUserCardsToSync), arg0, arg1)
+}
+
+// GetUserCustomRecordsAfterTime mocks base method.
+func (m *MockRepositorier) GetUserCustomRecordsAfterTime(arg0 context.Context, arg1, arg2 string) ([]storage.CustomRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCustomRecordsAfterTime", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]storage.CustomRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCustomRecordsAfterTime indicates an expected call of GetUserCustomRecordsAfterTime.
+func (mr *MockRepositorierMockRecorder) GetUserCustomRecordsAfterTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCustomRecordsAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserCustomRecordsAfterTime), arg0, arg1, arg2)
+}
+
+// GetUserCustomRecordsToSync mocks base method.
+func (m *MockRepositorier) GetUserCustomRecordsToSync(arg0 context.Context, arg1 string) ([]storage.CustomRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCustomRecordsToSync", arg0, arg1)
+	ret0, _ := ret[0].([]storage.CustomRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCustomRecordsToSync indicates an expected call of GetUserCustomRecordsToSync.
+func (mr *MockRepositorierMockRecorder) GetUserCustomRecordsToSync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCustomRecordsToSync", reflect.TypeOf((*MockRepositorier)(nil).GetUserCustomRecordsToSync), arg0, arg1)
+}
+
 // GetUserLoginsPwdsAfterTime mocks base method.
 func (m *MockRepositorier) GetUserLoginsPwdsAfterTime(arg0 context.Context, arg1, arg2 string) ([]storage.LoginPwd, error) {
 	m.ctrl.T.Helper()
@@ -254,6 +459,126 @@ func (mr *MockRepositorierMockRecorder) GetUserLoginsPwdsAfterTime(arg0, arg1, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLoginsPwdsAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserLoginsPwdsAfterTime), arg0, arg1, arg2)
 }
 
+// GetUserLoginsPwdsToSync mocks base method.
+func (m *MockRepositorier) GetUserLoginsPwdsToSync(arg0 context.Context, arg1 string) ([]storage.LoginPwd, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLoginsPwdsToSync", arg0, arg1)
+	ret0, _ := ret[0].([]storage.LoginPwd)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLoginsPwdsToSync indicates an expected call of GetUserLoginsPwdsToSync.
+func (mr *MockRepositorierMockRecorder) GetUserLoginsPwdsToSync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLoginsPwdsToSync", reflect.TypeOf((*MockRepositorier)(nil).GetUserLoginsPwdsToSync), arg0, arg1)
+}
+
+// GetUserOtpsAfterTime mocks base method.
+func (m *MockRepositorier) GetUserOtpsAfterTime(arg0 context.Context, arg1, arg2 string) ([]storage.Otp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOtpsAfterTime", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]storage.Otp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserOtpsAfterTime indicates an expected call of GetUserOtpsAfterTime.
+func (mr *MockRepositorierMockRecorder) GetUserOtpsAfterTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOtpsAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserOtpsAfterTime), arg0, arg1, arg2)
+}
+
+// GetUserOtpsToSync mocks base method.
+func (m *MockRepositorier) GetUserOtpsToSync(arg0 context.Context, arg1 string) ([]storage.Otp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOtpsToSync", arg0, arg1)
+	ret0, _ := ret[0].([]storage.Otp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserOtpsToSync indicates an expected call of GetUserOtpsToSync.
+func (mr *MockRepositorierMockRecorder) GetUserOtpsToSync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOtpsToSync", reflect.TypeOf((*MockRepositorier)(nil).GetUserOtpsToSync), arg0, arg1)
+}
+
+// GetUserRecords mocks base method.
+func (m *MockRepositorier) GetUserRecords(arg0 context.Context, arg1 string) ([]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRecords", arg0, arg1)
+	ret0, _ := ret[0].([]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRecords indicates an expected call of GetUserRecords.
+func (mr *MockRepositorierMockRecorder) GetUserRecords(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecords", reflect.TypeOf((*MockRepositorier)(nil).GetUserRecords), arg0, arg1)
+}
+
+// GetUserSshKeysAfterTime mocks base method.
+func (m *MockRepositorier) GetUserSshKeysAfterTime(arg0 context.Context, arg1, arg2 string) ([]storage.SshKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSshKeysAfterTime", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]storage.SshKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSshKeysAfterTime indicates an expected call of GetUserSshKeysAfterTime.
+func (mr *MockRepositorierMockRecorder) GetUserSshKeysAfterTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSshKeysAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserSshKeysAfterTime), arg0, arg1, arg2)
+}
+
+// GetUserSshKeysToSync mocks base method.
+func (m *MockRepositorier) GetUserSshKeysToSync(arg0 context.Context, arg1 string) ([]storage.SshKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSshKeysToSync", arg0, arg1)
+	ret0, _ := ret[0].([]storage.SshKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSshKeysToSync indicates an expected call of GetUserSshKeysToSync.
+func (mr *MockRepositorierMockRecorder) GetUserSshKeysToSync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSshKeysToSync", reflect.TypeOf((*MockRepositorier)(nil).GetUserSshKeysToSync), arg0, arg1)
+}
+
+// GetUserTemplatesAfterTime mocks base method.
+func (m *MockRepositorier) GetUserTemplatesAfterTime(arg0 context.Context, arg1, arg2 string) ([]storage.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTemplatesAfterTime", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]storage.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTemplatesAfterTime indicates an expected call of GetUserTemplatesAfterTime.
+func (mr *MockRepositorierMockRecorder) GetUserTemplatesAfterTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTemplatesAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserTemplatesAfterTime), arg0, arg1, arg2)
+}
+
+// GetUserTemplatesToSync mocks base method.
+func (m *MockRepositorier) GetUserTemplatesToSync(arg0 context.Context, arg1 string) ([]storage.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTemplatesToSync", arg0, arg1)
+	ret0, _ := ret[0].([]storage.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTemplatesToSync indicates an expected call of GetUserTemplatesToSync.
+func (mr *MockRepositorierMockRecorder) GetUserTemplatesToSync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTemplatesToSync", reflect.TypeOf((*MockRepositorier)(nil).GetUserTemplatesToSync), arg0, arg1)
+}
+
 // GetUserTextRecordsAfterTime mocks base method.
 func (m *MockRepositorier) GetUserTextRecordsAfterTime(arg0 context.Context, arg1, arg2 string) ([]storage.TextRecord, error) {
 	m.ctrl.T.Helper()
@@ -269,6 +594,21 @@ func (mr *MockRepositorierMockRecorder) GetUserTextRecordsAfterTime(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTextRecordsAfterTime", reflect.TypeOf((*MockRepositorier)(nil).GetUserTextRecordsAfterTime), arg0, arg1, arg2)
 }
 
+// GetUserTextRecordsToSync mocks base method.
+func (m *MockRepositorier) GetUserTextRecordsToSync(arg0 context.Context, arg1 string) ([]storage.TextRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTextRecordsToSync", arg0, arg1)
+	ret0, _ := ret[0].([]storage.TextRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTextRecordsToSync indicates an expected call of GetUserTextRecordsToSync.
+func (mr *MockRepositorierMockRecorder) GetUserTextRecordsToSync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTextRecordsToSync", reflect.TypeOf((*MockRepositorier)(nil).GetUserTextRecordsToSync), arg0, arg1)
+}
+
 // RegUser mocks base method.
 func (m *MockRepositorier) RegUser(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -283,6 +623,48 @@ func (mr *MockRepositorierMockRecorder) RegUser(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegUser", reflect.TypeOf((*MockRepositorier)(nil).RegUser), arg0, arg1, arg2)
 }
 
+// RestoreRecord mocks base method.
+func (m *MockRepositorier) RestoreRecord(arg0 context.Context, arg1 string, arg2 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRecord indicates an expected call of RestoreRecord.
+func (mr *MockRepositorierMockRecorder) RestoreRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRecord", reflect.TypeOf((*MockRepositorier)(nil).RestoreRecord), arg0, arg1, arg2)
+}
+
+// SaveRevision mocks base method.
+func (m *MockRepositorier) SaveRevision(arg0 context.Context, arg1 string, arg2 storage.Revision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRevision", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRevision indicates an expected call of SaveRevision.
+func (mr *MockRepositorierMockRecorder) SaveRevision(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRevision", reflect.TypeOf((*MockRepositorier)(nil).SaveRevision), arg0, arg1, arg2)
+}
+
+// SaveServerRecord mocks base method.
+func (m *MockRepositorier) SaveServerRecord(arg0 context.Context, arg1 string, arg2 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveServerRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveServerRecord indicates an expected call of SaveServerRecord.
+func (mr *MockRepositorierMockRecorder) SaveServerRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveServerRecord", reflect.TypeOf((*MockRepositorier)(nil).SaveServerRecord), arg0, arg1, arg2)
+}
+
 // UpdateBinaryRecord mocks base method.
 func (m *MockRepositorier) UpdateBinaryRecord(arg0 context.Context, arg1 string, arg2, arg3, arg4 []byte, arg5 string) error {
 	m.ctrl.T.Helper()
@@ -311,18 +693,18 @@ func (mr *MockRepositorierMockRecorder) UpdateCard(arg0, arg1, arg2, arg3, arg4,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCard", reflect.TypeOf((*MockRepositorier)(nil).UpdateCard), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 }
 
-// UpdateLastSyncTime mocks base method.
-func (m *MockRepositorier) UpdateLastSyncTime(arg0 context.Context, arg1, arg2 string) error {
+// UpdateLabel mocks base method.
+func (m *MockRepositorier) UpdateLabel(arg0 context.Context, arg1 string, arg2 interface{}, arg3 string, arg4 []byte, arg5 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateLastSyncTime", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UpdateLabel", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateLastSyncTime indicates an expected call of UpdateLastSyncTime.
-func (mr *MockRepositorierMockRecorder) UpdateLastSyncTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+// UpdateLabel indicates an expected call of UpdateLabel.
+func (mr *MockRepositorierMockRecorder) UpdateLabel(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastSyncTime", reflect.TypeOf((*MockRepositorier)(nil).UpdateLastSyncTime), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabel", reflect.TypeOf((*MockRepositorier)(nil).UpdateLabel), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // UpdateLoginPwd mocks base method.
@@ -339,6 +721,20 @@ func (mr *MockRepositorierMockRecorder) UpdateLoginPwd(arg0, arg1, arg2, arg3, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoginPwd", reflect.TypeOf((*MockRepositorier)(nil).UpdateLoginPwd), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 }
 
+// UpdateSyncRevision mocks base method.
+func (m *MockRepositorier) UpdateSyncRevision(arg0 context.Context, arg1 string, arg2 int64, arg3 []interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSyncRevision", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSyncRevision indicates an expected call of UpdateSyncRevision.
+func (mr *MockRepositorierMockRecorder) UpdateSyncRevision(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSyncRevision", reflect.TypeOf((*MockRepositorier)(nil).UpdateSyncRevision), arg0, arg1, arg2, arg3)
+}
+
 // UpdateTextRecord mocks base method.
 func (m *MockRepositorier) UpdateTextRecord(arg0 context.Context, arg1 string, arg2, arg3, arg4 []byte, arg5 string) error {
 	m.ctrl.T.Helper()
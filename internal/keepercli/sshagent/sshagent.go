@@ -0,0 +1,142 @@
+// Пакет sshagent реализует ssh-agent, который выдает ключи SSH из хранилища клиента.
+package sshagent
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// ErrReadOnly возвращается при попытке изменить набор ключей через агент.
+var ErrReadOnly = errors.New("keeper agent is read-only")
+
+// KeyLoader загружает расшифрованные ключи SSH пользователя.
+type KeyLoader func(ctx context.Context) ([]agent.AddedKey, error)
+
+// Agent реализует протокол ssh-agent для ключей из хранилища.
+// Ключи загружаются при каждом запросе, поэтому изменения в хранилище видны сразу.
+type Agent struct {
+	load KeyLoader
+}
+
+// New создает агента, получающего ключи через load.
+func New(load KeyLoader) *Agent {
+	return &Agent{load: load}
+}
+
+func (a *Agent) keyring() (agent.ExtendedAgent, error) {
+	keys, err := a.load(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	kr := agent.NewKeyring()
+	for _, k := range keys {
+		err = kr.Add(k)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return kr.(agent.ExtendedAgent), nil
+}
+
+// List возвращает открытые ключи пользователя.
+func (a *Agent) List() ([]*agent.Key, error) {
+	kr, err := a.keyring()
+	if err != nil {
+		return nil, err
+	}
+	return kr.List()
+}
+
+// Sign подписывает данные ключом, соответствующим открытому ключу key.
+func (a *Agent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return a.SignWithFlags(key, data, 0)
+}
+
+// SignWithFlags подписывает данные с учетом флагов алгоритма подписи.
+func (a *Agent) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	kr, err := a.keyring()
+	if err != nil {
+		return nil, err
+	}
+	return kr.SignWithFlags(key, data, flags)
+}
+
+// Signers возвращает объекты для подписи всеми ключами пользователя.
+func (a *Agent) Signers() ([]ssh.Signer, error) {
+	kr, err := a.keyring()
+	if err != nil {
+		return nil, err
+	}
+	return kr.Signers()
+}
+
+// Add не поддерживается: ключи добавляются командами клиента.
+func (a *Agent) Add(key agent.AddedKey) error {
+	return ErrReadOnly
+}
+
+// Remove не поддерживается.
+func (a *Agent) Remove(key ssh.PublicKey) error {
+	return ErrReadOnly
+}
+
+// RemoveAll не поддерживается.
+func (a *Agent) RemoveAll() error {
+	return ErrReadOnly
+}
+
+// Lock не поддерживается.
+func (a *Agent) Lock(passphrase []byte) error {
+	return ErrReadOnly
+}
+
+// Unlock не поддерживается.
+func (a *Agent) Unlock(passphrase []byte) error {
+	return ErrReadOnly
+}
+
+// Extension не поддерживается.
+func (a *Agent) Extension(extensionType string, contents []byte) ([]byte, error) {
+	return nil, agent.ErrExtensionUnsupported
+}
+
+// Serve обслуживает подключения к агенту на unix-сокете socketPath до отмены контекста.
+// Сокет доступен только владельцу процесса.
+func Serve(ctx context.Context, socketPath string, a agent.Agent) error {
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	err = os.Chmod(socketPath, 0600)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			agent.ServeAgent(a, conn)
+		}()
+	}
+}
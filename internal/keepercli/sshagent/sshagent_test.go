@@ -0,0 +1,84 @@
+package sshagent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func testKeys(t *testing.T) (ssh.PublicKey, KeyLoader) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+
+	return signer.PublicKey(), func(ctx context.Context) ([]agent.AddedKey, error) {
+		return []agent.AddedKey{{PrivateKey: priv, Comment: "deploy"}}, nil
+	}
+}
+
+func TestAgent(t *testing.T) {
+	pub, load := testKeys(t)
+	a := New(load)
+
+	keys, err := a.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, pub.Marshal(), keys[0].Marshal())
+	assert.Equal(t, "deploy", keys[0].Comment)
+
+	data := []byte("data")
+	sig, err := a.Sign(pub, data)
+	require.NoError(t, err)
+	assert.NoError(t, pub.Verify(data, sig))
+
+	assert.ErrorIs(t, a.Add(agent.AddedKey{}), ErrReadOnly)
+	assert.ErrorIs(t, a.RemoveAll(), ErrReadOnly)
+
+	a = New(func(ctx context.Context) ([]agent.AddedKey, error) {
+		return nil, errors.New("error")
+	})
+	_, err = a.List()
+	assert.Error(t, err)
+}
+
+func TestServe(t *testing.T) {
+	pub, load := testKeys(t)
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, socket, New(load))
+	}()
+
+	var conn net.Conn
+	var err error
+	for i := 0; i < 50; i++ {
+		conn, err = net.Dial("unix", socket)
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.NoError(t, err)
+	defer conn.Close()
+
+	keys, err := agent.NewClient(conn).List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, pub.Marshal(), keys[0].Marshal())
+
+	cancel()
+	assert.NoError(t, <-done)
+}
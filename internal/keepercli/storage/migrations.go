@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migration - версионная миграция локальной БД, созданной прежней версией клиента.
+// Номер последней примененной миграции хранится в PRAGMA user_version.
+type migration struct {
+	version int
+	name    string
+	up      func(ctx context.Context, tx *sql.Tx) error
+}
+
+// migrations - миграции локальной БД по возрастанию версии.
+// Миграции не должны менять таблицы, уже созданные в новой схеме функцией createTables.
+var migrations = []migration{
+	{version: 1, name: "tags_folders", up: addTagsFolders},
+	{version: 2, name: "sync_revision", up: addSyncRevision},
+}
+
+// recordTables - таблицы записей локальной БД.
+var recordTables = []string{
+	cardsTable.Name,
+	loginsTable.Name,
+	textsTable.Name,
+	binariesTable.Name,
+	otpsTable.Name,
+	sshKeysTable.Name,
+	templatesTable.Name,
+	customRecordsTable.Name,
+}
+
+// migrate применяет к БД миграции, версия которых больше PRAGMA user_version.
+// Каждая миграция выполняется в отдельной транзакции вместе с обновлением версии.
+func migrate(ctx context.Context, db *sql.DB) error {
+	var current int
+	err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&current)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		err = applyMigration(ctx, db, m)
+		if err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", m.version, m.name, err)
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = m.up(ctx, tx)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", m.version))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// hasColumn проверяет, есть ли столбец в таблице.
+func hasColumn(ctx context.Context, q querier, table, column string) (bool, error) {
+	var n int
+	err := q.QueryRowContext(ctx,
+		"SELECT count(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&n)
+	return n > 0, err
+}
+
+// addColumn добавляет столбец в таблицу, если его там еще нет.
+func addColumn(ctx context.Context, q querier, table, column, def string) error {
+	ok, err := hasColumn(ctx, q, table, column)
+	if err != nil || ok {
+		return err
+	}
+	_, err = q.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, def))
+	return err
+}
+
+// addTagsFolders добавляет метки и папку в записи, созданные до их появления.
+func addTagsFolders(ctx context.Context, tx *sql.Tx) error {
+	for _, table := range recordTables {
+		err := addColumn(ctx, tx, table, "tags", "BLOB")
+		if err != nil {
+			return err
+		}
+		err = addColumn(ctx, tx, table, "folder", "BLOB")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSyncRevision переводит БД на синхронизацию по ревизии сервера.
+// Записи прежней версии считаются непереданными на сервер,
+// а пользователи получают данные с сервера заново с нулевой ревизии.
+func addSyncRevision(ctx context.Context, tx *sql.Tx) error {
+	for _, table := range recordTables {
+		err := addColumn(ctx, tx, table, "pending", "INTEGER NOT NULL DEFAULT 1")
+		if err != nil {
+			return err
+		}
+	}
+
+	ok, err := hasColumn(ctx, tx, "users", "last_sync")
+	if err != nil || !ok {
+		return err
+	}
+	// SQLite не удаляет столбец с ограничением CHECK, поэтому таблица пересоздается.
+	for _, q := range []string{
+		"CREATE TABLE users_new " + usersColumns,
+		"INSERT INTO users_new (user_id, login, hash, salt) SELECT user_id, login, hash, salt FROM users",
+		"DROP TABLE users",
+		"ALTER TABLE users_new RENAME TO users",
+	} {
+		_, err = tx.ExecContext(ctx, q)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
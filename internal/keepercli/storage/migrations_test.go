@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// baselineSchema - схема локальной БД первой версии клиента.
+var baselineSchema = []string{
+	`CREATE TABLE users (
+		user_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		login TEXT UNIQUE NOT NULL CHECK(login != ''),
+		hash TEXT NOT NULL CHECK(hash != ''),
+		salt TEXT NOT NULL CHECK(salt != ''),
+		last_sync TEXT NOT NULL CHECK(last_sync != '')
+	)`,
+	`CREATE TABLE logins (
+		user_id INTEGER NOT NULL REFERENCES users (user_id),
+		prompt BLOB NOT NULL,
+		login BLOB NOT NULL,
+		pwd BLOB NOT NULL,
+		note BLOB,
+		time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+		PRIMARY KEY(user_id, login, prompt)
+	)`,
+	`CREATE TABLE cards (
+		user_id INTEGER NOT NULL REFERENCES users (user_id),
+		prompt BLOB NOT NULL,
+		number BLOB NOT NULL,
+		date BLOB NOT NULL,
+		code BLOB NOT NULL,
+		note BLOB,
+		time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+		PRIMARY KEY(user_id, number)
+	)`,
+	`CREATE TABLE text_data (
+		user_id INTEGER NOT NULL REFERENCES users (user_id),
+		prompt BLOB NOT NULL,
+		data BLOB NOT NULL,
+		note BLOB,
+		time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+		PRIMARY KEY(user_id, prompt)
+	)`,
+	`CREATE TABLE binary_data (
+		user_id INTEGER NOT NULL REFERENCES users (user_id),
+		prompt BLOB NOT NULL,
+		data BLOB NOT NULL,
+		note BLOB,
+		time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+		PRIMARY KEY(user_id, prompt)
+	)`,
+}
+
+// newBaselineDB создает файл БД в схеме первой версии клиента с одной картой пользователя.
+func newBaselineDB(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keeper.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, q := range baselineSchema {
+		_, err = db.ExecContext(ctx, q)
+		require.NoError(t, err)
+	}
+	_, err = db.ExecContext(ctx,
+		"INSERT INTO users (login, hash, salt, last_sync) VALUES (?,?,?,?)",
+		testUserLogin, hash(testUserPwd, "salt"), "salt", testTime)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx,
+		`INSERT INTO cards (user_id, prompt, number, date, code, note, time_stamp)
+		VALUES ((SELECT user_id FROM users WHERE login = ?),?,?,?,?,?,?)`,
+		testUserLogin, testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.TimeStamp)
+	require.NoError(t, err)
+
+	return path
+}
+
+func TestMigrate_Baseline(t *testing.T) {
+	ctx := context.Background()
+	path := newBaselineDB(t)
+
+	db, err := NewSQLiteStorage(path, Timeouts{})
+	require.NoError(t, err)
+
+	var version int
+	require.NoError(t, db.dbHandle.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version))
+	assert.Equal(t, migrations[len(migrations)-1].version, version)
+
+	for _, table := range recordTables {
+		for _, column := range []string{"tags", "folder"} {
+			ok, err := hasColumn(ctx, db.dbHandle, table, column)
+			require.NoError(t, err)
+			assert.True(t, ok, "%s.%s", table, column)
+		}
+	}
+
+	for _, table := range recordTables {
+		ok, err := hasColumn(ctx, db.dbHandle, table, "pending")
+		require.NoError(t, err)
+		assert.True(t, ok, "%s.pending", table)
+	}
+	ok, err := hasColumn(ctx, db.dbHandle, "users", "last_sync")
+	require.NoError(t, err)
+	assert.False(t, ok, "users.last_sync")
+
+	require.NoError(t, db.AuthUser(ctx, testUserLogin, testUserPwd))
+	card, err := db.GetCard(ctx, testUserLogin, testCard.Number)
+	require.NoError(t, err)
+	assert.Equal(t, testCard, card)
+
+	// Записи прежней версии передаются на сервер, а данные сервера загружаются заново.
+	rev, err := db.GetSyncRevision(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Zero(t, rev)
+	cards, err := db.GetUserCardsToSync(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Equal(t, []Card{testCard}, cards)
+
+	// Новый пользователь регистрируется без времени синхронизации.
+	require.NoError(t, db.RegUser(ctx, "new"+testUserLogin, testUserPwd))
+
+	// Повторное открытие не применяет миграции заново.
+	require.NoError(t, db.Close())
+	db, err = NewSQLiteStorage(path, Timeouts{})
+	require.NoError(t, err)
+	defer db.Close()
+	card, err = db.GetCard(ctx, testUserLogin, testCard.Number)
+	require.NoError(t, err)
+	assert.Equal(t, testCard, card)
+}
+
+func TestMigrate_NewDB(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "keeper.db"), Timeouts{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	var version int
+	require.NoError(t, db.dbHandle.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version))
+	assert.Equal(t, migrations[len(migrations)-1].version, version)
+}
@@ -0,0 +1,392 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Julia-ivv/info-keeper.git/internal/recordtable"
+)
+
+var cardsTable = recordtable.Table[Card]{
+	Name:    "cards",
+	Columns: []string{"prompt", "number", "date", "code", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"number"},
+	Fields: func(r *Card) []any {
+		return []any{&r.Prompt, &r.Number, &r.Date, &r.Code, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Question,
+	Pending:     true,
+}
+
+var loginsTable = recordtable.Table[LoginPwd]{
+	Name:    "logins",
+	Columns: []string{"prompt", "login", "pwd", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"prompt", "login"},
+	Fields: func(r *LoginPwd) []any {
+		return []any{&r.Prompt, &r.Login, &r.Pwd, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Question,
+	Pending:     true,
+}
+
+var textsTable = recordtable.Table[TextRecord]{
+	Name:    "text_data",
+	Columns: []string{"prompt", "data", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"prompt"},
+	Fields: func(r *TextRecord) []any {
+		return []any{&r.Prompt, &r.Data, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Question,
+	Pending:     true,
+}
+
+var binariesTable = recordtable.Table[BinaryRecord]{
+	Name:    "binary_data",
+	Columns: []string{"prompt", "data", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"prompt"},
+	Fields: func(r *BinaryRecord) []any {
+		return []any{&r.Prompt, &r.Data, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Question,
+	Pending:     true,
+}
+
+var otpsTable = recordtable.Table[Otp]{
+	Name:    "otps",
+	Columns: []string{"issuer", "account", "secret", "algorithm", "digits", "period", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"issuer", "account"},
+	Fields: func(r *Otp) []any {
+		return []any{&r.Issuer, &r.Account, &r.Secret, &r.Algorithm, &r.Digits, &r.Period, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Question,
+	Pending:     true,
+}
+
+var sshKeysTable = recordtable.Table[SshKey]{
+	Name:    "ssh_keys",
+	Columns: []string{"prompt", "private_key", "public_key", "comment", "passphrase", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"prompt"},
+	Fields: func(r *SshKey) []any {
+		return []any{&r.Prompt, &r.PrivateKey, &r.PublicKey, &r.Comment, &r.Passphrase, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Question,
+	Pending:     true,
+}
+
+var templatesTable = recordtable.Table[Template]{
+	Name:    "templates",
+	Columns: []string{"name", "fields", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"name"},
+	Fields: func(r *Template) []any {
+		return []any{&r.Name, &r.Fields, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Question,
+	Pending:     true,
+}
+
+var customRecordsTable = recordtable.Table[CustomRecord]{
+	Name:    "custom_records",
+	Columns: []string{"prompt", "template", "data", "note", recordtable.TagsColumn, recordtable.FolderColumn, recordtable.TimeStampColumn},
+	Keys:    []string{"prompt"},
+	Fields: func(r *CustomRecord) []any {
+		return []any{&r.Prompt, &r.Template, &r.Data, &r.Note, &r.Tags, &r.Folder, &r.TimeStamp}
+	},
+	Placeholder: recordtable.Question,
+	Pending:     true,
+}
+
+// getRecord получает запись пользователя по ключевым полям записи key.
+func getRecord[T any](ctx context.Context, db *SQLiteStorage, t recordtable.Table[T],
+	userLogin string, key T) (T, error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	row := db.dbHandle.QueryRowContext(ctx, t.GetQuery(), t.GetArgs(userLogin, &key)...)
+	err := row.Scan(t.GetDest(&key)...)
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+
+	return key, nil
+}
+
+// querier выполняет запросы к БД или в рамках транзакции.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// inTx выполняет fn в транзакции.
+func inTx(ctx context.Context, db *SQLiteStorage, fn func(tx *sql.Tx) error) error {
+	tx, err := db.dbHandle.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// getRecordsAfterTime получает записи пользователя,
+// добавленные или измененные после указанного времени.
+func getRecordsAfterTime[T any](ctx context.Context, db *SQLiteStorage, t recordtable.Table[T],
+	userLogin string, afterTime string) (records []T, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
+	defer cancel()
+
+	return queryRecords(ctx, db, t, t.AfterTimeQuery(), userLogin, afterTime)
+}
+
+// getRecordsToSync получает записи пользователя, измененные локально
+// и еще не переданные на сервер.
+func getRecordsToSync[T any](ctx context.Context, db *SQLiteStorage, t recordtable.Table[T],
+	userLogin string) (records []T, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
+	defer cancel()
+
+	return queryRecords(ctx, db, t, t.PendingQuery(), userLogin)
+}
+
+// listRecords возвращает функцию, которая добавляет к records все записи пользователя из таблицы t.
+func listRecords[T any](t recordtable.Table[T]) func(context.Context, *SQLiteStorage, string, []any) ([]any, error) {
+	return func(ctx context.Context, db *SQLiteStorage, userLogin string, records []any) ([]any, error) {
+		rs, err := queryRecords(ctx, db, t, t.ListQuery(), userLogin)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			records = append(records, r)
+		}
+		return records, nil
+	}
+}
+
+// queryRecords получает записи пользователя запросом query с параметрами args.
+func queryRecords[T any](ctx context.Context, db *SQLiteStorage, t recordtable.Table[T],
+	query string, args ...any) (records []T, err error) {
+	rows, err := db.dbHandle.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r T
+		err = rows.Scan(t.RowDest(&r)...)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// addRecord добавляет запись пользователя.
+func addRecord[T any](ctx context.Context, db *SQLiteStorage, t recordtable.Table[T],
+	userLogin string, r T) error {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	return insertRecord(ctx, db.dbHandle, t, userLogin, r)
+}
+
+// insertRecord добавляет запись пользователя запросом q.
+func insertRecord[T any](ctx context.Context, q querier, t recordtable.Table[T],
+	userLogin string, r T) error {
+	result, err := q.ExecContext(ctx, t.InsertQuery(), t.InsertArgs(userLogin, &r)...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return errors.New("expected to affect 1 row")
+	}
+
+	return nil
+}
+
+// saveNewerRecordsTx сохраняет записи пользователя, полученные с сервера, в рамках транзакции.
+// Существующие записи заменяются, только если полученные записи изменены позже.
+func saveNewerRecordsTx[T any](ctx context.Context, tx *sql.Tx, t recordtable.Table[T],
+	userLogin string, records []T) error {
+	for _, v := range records {
+		err := replaceIfNewer(ctx, tx, t, userLogin, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateRecord обновляет указанные столбцы записи пользователя.
+func updateRecord[T any](ctx context.Context, db *SQLiteStorage, t recordtable.Table[T],
+	userLogin string, r T, columns []string) error {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	result, err := db.dbHandle.ExecContext(ctx, t.UpdateColumnsQuery(columns), t.UpdateColumnsArgs(userLogin, &r, columns)...)
+	if err != nil {
+		return err
+	}
+	row, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if row != 1 {
+		return errors.New("expected to affect 1 row")
+	}
+	return nil
+}
+
+// updateLabel обновляет метку записи пользователя и время ее изменения.
+func updateLabel[T any](ctx context.Context, db *SQLiteStorage, t recordtable.Table[T],
+	userLogin string, key T, label Label, value []byte, timeStamp string) error {
+	t.Set(&key, label, value)
+	t.Set(&key, recordtable.TimeStampColumn, timeStamp)
+	return updateRecord(ctx, db, t, userLogin, key, []string{label, recordtable.TimeStampColumn})
+}
+
+// deleteRecord удаляет запись пользователя с ключевыми полями key.
+func deleteRecord[T any](ctx context.Context, db *SQLiteStorage, t recordtable.Table[T],
+	userLogin string, key T) error {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	_, err := db.dbHandle.ExecContext(ctx, t.DeleteQuery(), t.GetArgs(userLogin, &key)...)
+	return err
+}
+
+// saveRecord заменяет данные записи пользователя версией с сервера.
+// Если записи с такими ключевыми полями нет, она добавляется.
+func saveRecord[T any](ctx context.Context, db *SQLiteStorage, t recordtable.Table[T],
+	userLogin string, r T) error {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	return inTx(ctx, db, func(tx *sql.Tx) error {
+		return replaceRecord(ctx, tx, t, userLogin, r)
+	})
+}
+
+// saveNewerRecord сохраняет запись пользователя, полученную с сервера.
+// Существующая запись заменяется, только если полученная запись изменена позже.
+func saveNewerRecord[T any](ctx context.Context, db *SQLiteStorage, t recordtable.Table[T],
+	userLogin string, r T) error {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	return inTx(ctx, db, func(tx *sql.Tx) error {
+		return replaceIfNewer(ctx, tx, t, userLogin, r)
+	})
+}
+
+// replaceRecord заменяет данные записи пользователя версией с сервера или добавляет запись.
+// Запись совпадает с сервером, поэтому признак локального изменения с нее снимается.
+func replaceRecord[T any](ctx context.Context, q querier, t recordtable.Table[T],
+	userLogin string, r T) error {
+	result, err := q.ExecContext(ctx, t.UpdateQuery(), t.UpdateArgs(userLogin, &r)...)
+	if err != nil {
+		return err
+	}
+	row, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if row != 1 {
+		err = insertRecord(ctx, q, t, userLogin, r)
+		if err != nil {
+			return err
+		}
+	}
+
+	return markSynced(ctx, q, t, userLogin, r)
+}
+
+// markSynced снимает с записи пользователя признак локального изменения.
+func markSynced[T any](ctx context.Context, q querier, t recordtable.Table[T],
+	userLogin string, r T) error {
+	_, err := q.ExecContext(ctx, t.SyncedQuery(), t.GetArgs(userLogin, &r)...)
+	return err
+}
+
+// clearPending снимает признак локального изменения с записи r, переданной на сервер,
+// если ее время изменения не изменилось.
+func clearPending(ctx context.Context, q querier, userLogin string, r any) error {
+	switch v := r.(type) {
+	case Card:
+		return clearPendingRecord(ctx, q, cardsTable, userLogin, v)
+	case LoginPwd:
+		return clearPendingRecord(ctx, q, loginsTable, userLogin, v)
+	case TextRecord:
+		return clearPendingRecord(ctx, q, textsTable, userLogin, v)
+	case BinaryRecord:
+		return clearPendingRecord(ctx, q, binariesTable, userLogin, v)
+	case Otp:
+		return clearPendingRecord(ctx, q, otpsTable, userLogin, v)
+	case SshKey:
+		return clearPendingRecord(ctx, q, sshKeysTable, userLogin, v)
+	case Template:
+		return clearPendingRecord(ctx, q, templatesTable, userLogin, v)
+	case CustomRecord:
+		return clearPendingRecord(ctx, q, customRecordsTable, userLogin, v)
+	default:
+		return fmt.Errorf("unsupported record type %T", r)
+	}
+}
+
+// clearPendingRecord снимает признак локального изменения с записи пользователя в таблице t.
+func clearPendingRecord[T any](ctx context.Context, q querier, t recordtable.Table[T],
+	userLogin string, r T) error {
+	_, err := q.ExecContext(ctx, t.ClearPendingQuery(), t.ClearPendingArgs(userLogin, &r)...)
+	return err
+}
+
+// replaceIfNewer заменяет запись пользователя версией с сервера,
+// если локальная запись отсутствует или изменена раньше.
+func replaceIfNewer[T any](ctx context.Context, q querier, t recordtable.Table[T],
+	userLogin string, r T) error {
+	var local string
+	row := q.QueryRowContext(ctx, t.TimeStampQuery(), t.TimeStampArgs(userLogin, &r)...)
+	err := row.Scan(&local)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = insertRecord(ctx, q, t, userLogin, r)
+		if err != nil {
+			return err
+		}
+		return markSynced(ctx, q, t, userLogin, r)
+	}
+	if err != nil {
+		return err
+	}
+
+	tLocal, err := time.Parse(time.RFC3339, local)
+	if err != nil {
+		return err
+	}
+	tServer, err := time.Parse(time.RFC3339, t.Get(&r, recordtable.TimeStampColumn).(string))
+	if err != nil {
+		return err
+	}
+	if !tServer.After(tLocal) {
+		return nil
+	}
+
+	return replaceRecord(ctx, q, t, userLogin, r)
+}
@@ -9,22 +9,65 @@ import (
 
 	_ "modernc.org/sqlite"
 
+	"github.com/Julia-ivv/info-keeper.git/pkg/deadline"
 	"github.com/Julia-ivv/info-keeper.git/pkg/randomizer"
 )
 
 type SQLiteStorage struct {
 	dbHandle *sql.DB
+	timeouts Timeouts
 }
 
+// Timeouts задает время ожидания операций с локальной БД.
+// Нулевые значения заменяются значениями по умолчанию.
+// Если у контекста уже есть срок выполнения команды, используется он.
+type Timeouts struct {
+	// Query ограничивает операции с отдельными записями и пользователями.
+	Query time.Duration
+	// Bulk ограничивает выборки и сохранение данных при синхронизации.
+	Bulk time.Duration
+}
+
+const (
+	defQueryTimeout = 3 * time.Second
+	defBulkTimeout  = 30 * time.Second
+)
+
+func (t Timeouts) query() time.Duration {
+	if t.Query <= 0 {
+		return defQueryTimeout
+	}
+	return t.Query
+}
+
+func (t Timeouts) bulk() time.Duration {
+	if t.Bulk <= 0 {
+		return defBulkTimeout
+	}
+	return t.Bulk
+}
+
+// queryCtx ограничивает контекст временем ожидания одиночной операции.
+func (db *SQLiteStorage) queryCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	return deadline.WithTimeout(ctx, db.timeouts.query())
+}
+
+// bulkCtx ограничивает контекст временем ожидания массовой операции.
+func (db *SQLiteStorage) bulkCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	return deadline.WithTimeout(ctx, db.timeouts.bulk())
+}
+
+// usersColumns - описание столбцов таблицы пользователей.
+const usersColumns = `(
+	user_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	login TEXT UNIQUE NOT NULL CHECK(login != ''),
+	hash TEXT NOT NULL CHECK(hash != ''),
+	salt TEXT NOT NULL CHECK(salt != ''),
+	sync_revision INTEGER NOT NULL DEFAULT 0
+)`
+
 func createTables(ctx context.Context, db *sql.DB) error {
-	_, err := db.ExecContext(ctx,
-		`CREATE TABLE IF NOT EXISTS users (
-			user_id INTEGER PRIMARY KEY AUTOINCREMENT,
-			login TEXT UNIQUE NOT NULL CHECK(login != ''),
-			hash TEXT NOT NULL CHECK(hash != ''),
-			salt TEXT NOT NULL CHECK(salt != ''),
-			last_sync TEXT NOT NULL CHECK(last_sync != '')
-		)`)
+	_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS users "+usersColumns)
 	if err != nil {
 		return err
 	}
@@ -36,7 +79,10 @@ func createTables(ctx context.Context, db *sql.DB) error {
 			login BLOB NOT NULL,
 			pwd BLOB NOT NULL,
 			note BLOB,
+			tags BLOB,
+			folder BLOB,
 			time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+			pending INTEGER NOT NULL DEFAULT 1,
 			PRIMARY KEY(user_id, login, prompt)
 		)`)
 	if err != nil {
@@ -51,7 +97,10 @@ func createTables(ctx context.Context, db *sql.DB) error {
 			date BLOB NOT NULL,
 			code BLOB NOT NULL,
 			note BLOB,
+			tags BLOB,
+			folder BLOB,
 			time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+			pending INTEGER NOT NULL DEFAULT 1,
 			PRIMARY KEY(user_id, number)
 		)`)
 	if err != nil {
@@ -64,7 +113,10 @@ func createTables(ctx context.Context, db *sql.DB) error {
 			prompt BLOB NOT NULL,
 			data BLOB NOT NULL,
 			note BLOB,
+			tags BLOB,
+			folder BLOB,
 			time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+			pending INTEGER NOT NULL DEFAULT 1,
 			PRIMARY KEY(user_id, prompt)
 		)`)
 	if err != nil {
@@ -77,23 +129,113 @@ func createTables(ctx context.Context, db *sql.DB) error {
 			prompt BLOB NOT NULL,
 			data BLOB NOT NULL,
 			note BLOB,
+			tags BLOB,
+			folder BLOB,
+			time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+			pending INTEGER NOT NULL DEFAULT 1,
+			PRIMARY KEY(user_id, prompt)
+		)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS otps (
+			user_id INTEGER NOT NULL REFERENCES users (user_id),
+			issuer BLOB NOT NULL,
+			account BLOB NOT NULL,
+			secret BLOB NOT NULL,
+			algorithm BLOB NOT NULL,
+			digits BLOB NOT NULL,
+			period BLOB NOT NULL,
+			note BLOB,
+			tags BLOB,
+			folder BLOB,
+			time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+			pending INTEGER NOT NULL DEFAULT 1,
+			PRIMARY KEY(user_id, issuer, account)
+		)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS ssh_keys (
+			user_id INTEGER NOT NULL REFERENCES users (user_id),
+			prompt BLOB NOT NULL,
+			private_key BLOB NOT NULL,
+			public_key BLOB NOT NULL,
+			comment BLOB,
+			passphrase BLOB,
+			tags BLOB,
+			folder BLOB,
+			time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+			pending INTEGER NOT NULL DEFAULT 1,
+			PRIMARY KEY(user_id, prompt)
+		)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS templates (
+			user_id INTEGER NOT NULL REFERENCES users (user_id),
+			name BLOB NOT NULL,
+			fields BLOB NOT NULL,
+			tags BLOB,
+			folder BLOB,
+			time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+			pending INTEGER NOT NULL DEFAULT 1,
+			PRIMARY KEY(user_id, name)
+		)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS custom_records (
+			user_id INTEGER NOT NULL REFERENCES users (user_id),
+			prompt BLOB NOT NULL,
+			template BLOB NOT NULL,
+			data BLOB NOT NULL,
+			note BLOB,
+			tags BLOB,
+			folder BLOB,
 			time_stamp TEXT NOT NULL CHECK(time_stamp != ''),
+			pending INTEGER NOT NULL DEFAULT 1,
 			PRIMARY KEY(user_id, prompt)
 		)`)
 	if err != nil {
 		return err
 	}
 
+	_, err = db.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS revisions (
+			user_id INTEGER NOT NULL REFERENCES users (user_id),
+			record_type INTEGER NOT NULL,
+			prompt BLOB NOT NULL,
+			record_key BLOB NOT NULL,
+			record_id INTEGER NOT NULL,
+			revision INTEGER NOT NULL,
+			time_stamp TEXT NOT NULL,
+			PRIMARY KEY(user_id, record_type, prompt, record_key)
+		)`)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // NewSQLiteStorage создает новый объект для работы с БД.
-func NewSQLiteStorage(DBURI string) (*SQLiteStorage, error) {
+// БД, созданная прежней версией клиента, приводится к текущей схеме миграциями.
+// Параметр timeouts задает время ожидания операций с БД.
+func NewSQLiteStorage(DBURI string, timeouts Timeouts) (*SQLiteStorage, error) {
 	db, err := sql.Open("sqlite", DBURI)
 	if err != nil {
 		return nil, err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeouts.query())
 	defer cancel()
 	if err = db.PingContext(ctx); err != nil {
 		panic(err)
@@ -103,8 +245,12 @@ func NewSQLiteStorage(DBURI string) (*SQLiteStorage, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = migrate(ctx, db)
+	if err != nil {
+		return nil, err
+	}
 
-	return &SQLiteStorage{dbHandle: db}, nil
+	return &SQLiteStorage{dbHandle: db, timeouts: timeouts}, nil
 }
 
 // Close закрывает БД.
@@ -114,7 +260,7 @@ func (db *SQLiteStorage) Close() error {
 
 // RegUser регистрирует и аутентифицирует пользователя.
 func (db *SQLiteStorage) RegUser(ctx context.Context, login string, pwd string) error {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	ctx, cancel := db.queryCtx(ctx)
 	defer cancel()
 
 	salt, err := randomizer.GenerateRandomString(LengthSalt)
@@ -122,8 +268,8 @@ func (db *SQLiteStorage) RegUser(ctx context.Context, login string, pwd string)
 		return err
 	}
 	result, err := db.dbHandle.ExecContext(ctx,
-		"INSERT INTO users (login, hash, salt, last_sync) VALUES (?,?,?,?)",
-		login, hash(pwd, salt), salt, time.Now().Format(time.RFC3339))
+		"INSERT INTO users (login, hash, salt) VALUES (?,?,?)",
+		login, hash(pwd, salt), salt)
 	if err != nil {
 		return err
 	}
@@ -140,7 +286,7 @@ func (db *SQLiteStorage) RegUser(ctx context.Context, login string, pwd string)
 
 // AuthUser аутентифицирует пользователя.
 func (db *SQLiteStorage) AuthUser(ctx context.Context, login string, pwd string) error {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	ctx, cancel := db.queryCtx(ctx)
 	defer cancel()
 
 	row := db.dbHandle.QueryRowContext(ctx,
@@ -167,6 +313,8 @@ type Card struct {
 	Date      []byte
 	Code      []byte
 	Note      []byte
+	Tags      []byte
+	Folder    []byte
 	TimeStamp string
 }
 
@@ -174,70 +322,18 @@ type Card struct {
 // введенную или измененную после указанного времени.
 func (db *SQLiteStorage) GetUserCardsAfterTime(ctx context.Context, userLogin string,
 	afterTime string) (cards []Card, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	rows, err := db.dbHandle.QueryContext(ctx,
-		`SELECT prompt, number, date, code, note, time_stamp
-		FROM cards
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND time_stamp > ?`, userLogin, afterTime)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var prompt, number, date, code, note []byte
-	var timeStamp string
-	for rows.Next() {
-		err = rows.Scan(&prompt, &number, &date, &code, &note, &timeStamp)
-		if err != nil {
-			return nil, err
-		}
-
-		cards = append(cards, Card{
-			Prompt:    prompt,
-			Number:    number,
-			Date:      date,
-			Code:      code,
-			Note:      note,
-			TimeStamp: timeStamp,
-		})
-	}
+	return getRecordsAfterTime(ctx, db, cardsTable, userLogin, afterTime)
+}
 
-	err = rows.Err()
-	if err != nil {
-		return nil, err
-	}
-	return cards, nil
+// GetUserCardsToSync получает информацию о банковских картах пользователя,
+// измененную локально и еще не переданную на сервер.
+func (db *SQLiteStorage) GetUserCardsToSync(ctx context.Context, userLogin string) (cards []Card, err error) {
+	return getRecordsToSync(ctx, db, cardsTable, userLogin)
 }
 
 // GetCard получает информацию о банковской карте пользователя.
 func (db *SQLiteStorage) GetCard(ctx context.Context, userLogin string, number []byte) (card Card, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	row := db.dbHandle.QueryRowContext(ctx,
-		`SELECT prompt, date, code, note, time_stamp
-		FROM cards
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND number = ?`, userLogin, number)
-
-	var prompt, date, code, note []byte
-	var timeStamp string
-	err = row.Scan(&prompt, &date, &code, &note, &timeStamp)
-	if err != nil {
-		return Card{}, err
-	}
-
-	return Card{
-		Prompt:    prompt,
-		Number:    number,
-		Date:      date,
-		Code:      code,
-		Note:      note,
-		TimeStamp: timeStamp,
-	}, nil
+	return getRecord(ctx, db, cardsTable, userLogin, Card{Number: number})
 }
 
 // LoginPwd хранит информацию о паре логин-пароль.
@@ -246,6 +342,8 @@ type LoginPwd struct {
 	Login     []byte
 	Pwd       []byte
 	Note      []byte
+	Tags      []byte
+	Folder    []byte
 	TimeStamp string
 }
 
@@ -253,68 +351,18 @@ type LoginPwd struct {
 // введенную или измененную после указанного времени.
 func (db *SQLiteStorage) GetUserLoginsPwdsAfterTime(ctx context.Context, userLogin string,
 	afterTime string) (loginsPwds []LoginPwd, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	rows, err := db.dbHandle.QueryContext(ctx,
-		`SELECT prompt, login, pwd, note, time_stamp
-		FROM logins
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND time_stamp > ?`, userLogin, afterTime)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var prompt, login, pwd, note []byte
-	var timeStamp string
-	for rows.Next() {
-		err = rows.Scan(&prompt, &login, &pwd, &note, &timeStamp)
-		if err != nil {
-			return nil, err
-		}
-		loginsPwds = append(loginsPwds, LoginPwd{
-			Prompt:    prompt,
-			Login:     login,
-			Pwd:       pwd,
-			Note:      note,
-			TimeStamp: timeStamp,
-		})
-	}
-
-	err = rows.Err()
-	if err != nil {
-		return nil, err
-	}
+	return getRecordsAfterTime(ctx, db, loginsTable, userLogin, afterTime)
+}
 
-	return loginsPwds, nil
+// GetUserLoginsPwdsToSync получает информацию о парах логин-пароль,
+// измененную локально и еще не переданную на сервер.
+func (db *SQLiteStorage) GetUserLoginsPwdsToSync(ctx context.Context, userLogin string) (loginsPwds []LoginPwd, err error) {
+	return getRecordsToSync(ctx, db, loginsTable, userLogin)
 }
 
 // GetLoginPwd получает данные о паре логин-пароль.
 func (db *SQLiteStorage) GetLoginPwd(ctx context.Context, userLogin string, prompt []byte, login []byte) (loginPwd LoginPwd, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	row := db.dbHandle.QueryRowContext(ctx,
-		`SELECT pwd, note, time_stamp
-		FROM logins
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND prompt = ? AND login = ?`, userLogin, prompt, login)
-
-	var pwd, note []byte
-	var timeStamp string
-	err = row.Scan(&pwd, &note, &timeStamp)
-	if err != nil {
-		return LoginPwd{}, err
-	}
-
-	return LoginPwd{
-		Prompt:    prompt,
-		Login:     login,
-		Pwd:       pwd,
-		Note:      note,
-		TimeStamp: timeStamp,
-	}, nil
+	return getRecord(ctx, db, loginsTable, userLogin, LoginPwd{Prompt: prompt, Login: login})
 }
 
 // TextRecord хранит текстовую информацию.
@@ -322,6 +370,8 @@ type TextRecord struct {
 	Prompt    []byte
 	Data      []byte
 	Note      []byte
+	Tags      []byte
+	Folder    []byte
 	TimeStamp string
 }
 
@@ -329,65 +379,18 @@ type TextRecord struct {
 // добавленную или измененную после указанного времени.
 func (db *SQLiteStorage) GetUserTextRecordsAfterTime(ctx context.Context, userLogin string,
 	afterTime string) (records []TextRecord, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	rows, err := db.dbHandle.QueryContext(ctx,
-		`SELECT prompt, data, note, time_stamp
-		FROM text_data
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND time_stamp > ?`, userLogin, afterTime)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var prompt, data, note []byte
-		var timeStamp string
-		err = rows.Scan(&prompt, &data, &note, &timeStamp)
-		if err != nil {
-			return nil, err
-		}
-		records = append(records, TextRecord{
-			Prompt:    prompt,
-			Data:      data,
-			Note:      note,
-			TimeStamp: timeStamp,
-		})
-	}
+	return getRecordsAfterTime(ctx, db, textsTable, userLogin, afterTime)
+}
 
-	err = rows.Err()
-	if err != nil {
-		return nil, err
-	}
-	return records, nil
+// GetUserTextRecordsToSync получает текстовую информацию пользователя,
+// измененную локально и еще не переданную на сервер.
+func (db *SQLiteStorage) GetUserTextRecordsToSync(ctx context.Context, userLogin string) (records []TextRecord, err error) {
+	return getRecordsToSync(ctx, db, textsTable, userLogin)
 }
 
 // GetTextRecord получает текстовые данные.
 func (db *SQLiteStorage) GetTextRecord(ctx context.Context, userLogin string, prompt []byte) (record TextRecord, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	row := db.dbHandle.QueryRowContext(ctx,
-		`SELECT data, note, time_stamp
-		FROM text_data
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND prompt = ?`, userLogin, prompt)
-
-	var data, note []byte
-	var timeStamp string
-	err = row.Scan(&data, &note, &timeStamp)
-	if err != nil {
-		return TextRecord{}, err
-	}
-
-	return TextRecord{
-		Prompt:    prompt,
-		Data:      data,
-		Note:      note,
-		TimeStamp: timeStamp,
-	}, nil
+	return getRecord(ctx, db, textsTable, userLogin, TextRecord{Prompt: prompt})
 }
 
 // BinaryRecord хранит бинарные данные.
@@ -395,6 +398,8 @@ type BinaryRecord struct {
 	Prompt    []byte
 	Data      []byte
 	Note      []byte
+	Tags      []byte
+	Folder    []byte
 	TimeStamp string
 }
 
@@ -402,398 +407,509 @@ type BinaryRecord struct {
 // добавленную или измененную после указанного времени.
 func (db *SQLiteStorage) GetUserBinaryRecordsAfterTime(ctx context.Context, userLogin string,
 	afterTime string) (records []BinaryRecord, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	rows, err := db.dbHandle.QueryContext(ctx,
-		`SELECT prompt, data, note, time_stamp
-		FROM binary_data
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND time_stamp > ?`, userLogin, afterTime)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var prompt, data, note []byte
-		var timeStamp string
-		err = rows.Scan(&prompt, &data, &note, &timeStamp)
-		if err != nil {
-			return nil, err
-		}
-		records = append(records, BinaryRecord{
-			Prompt:    prompt,
-			Data:      data,
-			Note:      note,
-			TimeStamp: timeStamp,
-		})
-	}
+	return getRecordsAfterTime(ctx, db, binariesTable, userLogin, afterTime)
+}
 
-	err = rows.Err()
-	if err != nil {
-		return nil, err
-	}
-	return records, nil
+// GetUserBinaryRecordsToSync получает бинарную информацию пользователя,
+// измененную локально и еще не переданную на сервер.
+func (db *SQLiteStorage) GetUserBinaryRecordsToSync(ctx context.Context, userLogin string) (records []BinaryRecord, err error) {
+	return getRecordsToSync(ctx, db, binariesTable, userLogin)
 }
 
 // GetBinaryRecord получает бинарную информацию.
 func (db *SQLiteStorage) GetBinaryRecord(ctx context.Context, userLogin string, prompt []byte) (record BinaryRecord, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
+	return getRecord(ctx, db, binariesTable, userLogin, BinaryRecord{Prompt: prompt})
+}
 
-	row := db.dbHandle.QueryRowContext(ctx,
-		`SELECT data, note, time_stamp
-		FROM binary_data
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND prompt = ?`, userLogin, prompt)
+// Otp хранит параметры генерации одноразовых кодов.
+type Otp struct {
+	Issuer    []byte
+	Account   []byte
+	Secret    []byte
+	Algorithm []byte
+	Digits    []byte
+	Period    []byte
+	Note      []byte
+	Tags      []byte
+	Folder    []byte
+	TimeStamp string
+}
 
-	var data, note []byte
-	var timeStamp string
-	err = row.Scan(&data, &note, &timeStamp)
-	if err != nil {
-		return BinaryRecord{}, err
-	}
+// GetUserOtpsAfterTime получает параметры генерации одноразовых кодов пользователя,
+// добавленные или измененные после указанного времени.
+func (db *SQLiteStorage) GetUserOtpsAfterTime(ctx context.Context, userLogin string,
+	afterTime string) (otps []Otp, err error) {
+	return getRecordsAfterTime(ctx, db, otpsTable, userLogin, afterTime)
+}
 
-	return BinaryRecord{
-		Prompt:    prompt,
-		Data:      data,
-		Note:      note,
-		TimeStamp: timeStamp,
-	}, nil
+// GetUserOtpsToSync получает параметры генерации одноразовых кодов пользователя,
+// измененные локально и еще не переданные на сервер.
+func (db *SQLiteStorage) GetUserOtpsToSync(ctx context.Context, userLogin string) (otps []Otp, err error) {
+	return getRecordsToSync(ctx, db, otpsTable, userLogin)
+}
+
+// GetOtp получает параметры генерации одноразовых кодов.
+func (db *SQLiteStorage) GetOtp(ctx context.Context, userLogin string, issuer []byte, account []byte) (o Otp, err error) {
+	return getRecord(ctx, db, otpsTable, userLogin, Otp{Issuer: issuer, Account: account})
+}
+
+// SshKey хранит ключ SSH.
+type SshKey struct {
+	Prompt     []byte
+	PrivateKey []byte
+	PublicKey  []byte
+	Comment    []byte
+	Passphrase []byte
+	Tags       []byte
+	Folder     []byte
+	TimeStamp  string
+}
+
+// GetUserSshKeysAfterTime получает ключи SSH пользователя,
+// добавленные или измененные после указанного времени.
+func (db *SQLiteStorage) GetUserSshKeysAfterTime(ctx context.Context, userLogin string,
+	afterTime string) (keys []SshKey, err error) {
+	return getRecordsAfterTime(ctx, db, sshKeysTable, userLogin, afterTime)
+}
+
+// GetUserSshKeysToSync получает ключи SSH пользователя,
+// измененные локально и еще не переданные на сервер.
+func (db *SQLiteStorage) GetUserSshKeysToSync(ctx context.Context, userLogin string) (keys []SshKey, err error) {
+	return getRecordsToSync(ctx, db, sshKeysTable, userLogin)
+}
+
+// GetSshKey получает ключ SSH по подсказке.
+func (db *SQLiteStorage) GetSshKey(ctx context.Context, userLogin string, prompt []byte) (k SshKey, err error) {
+	return getRecord(ctx, db, sshKeysTable, userLogin, SshKey{Prompt: prompt})
+}
+
+// Template хранит шаблон пользовательских записей.
+type Template struct {
+	Name      []byte
+	Fields    []byte
+	Tags      []byte
+	Folder    []byte
+	TimeStamp string
+}
+
+// GetUserTemplatesAfterTime получает шаблоны пользовательских записей,
+// добавленные или измененные после указанного времени.
+func (db *SQLiteStorage) GetUserTemplatesAfterTime(ctx context.Context, userLogin string,
+	afterTime string) (templates []Template, err error) {
+	return getRecordsAfterTime(ctx, db, templatesTable, userLogin, afterTime)
+}
+
+// GetUserTemplatesToSync получает шаблоны пользовательских записей,
+// измененные локально и еще не переданные на сервер.
+func (db *SQLiteStorage) GetUserTemplatesToSync(ctx context.Context, userLogin string) (templates []Template, err error) {
+	return getRecordsToSync(ctx, db, templatesTable, userLogin)
+}
+
+// GetTemplate получает шаблон пользовательских записей по имени.
+func (db *SQLiteStorage) GetTemplate(ctx context.Context, userLogin string, name []byte) (t Template, err error) {
+	return getRecord(ctx, db, templatesTable, userLogin, Template{Name: name})
 }
 
-// GetLastSyncTime получает время последней синхронизации.
-func (db *SQLiteStorage) GetLastSyncTime(ctx context.Context, userLogin string) (lastSync string, err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// CustomRecord хранит пользовательскую запись, созданную по шаблону.
+type CustomRecord struct {
+	Prompt    []byte
+	Template  []byte
+	Data      []byte
+	Note      []byte
+	Tags      []byte
+	Folder    []byte
+	TimeStamp string
+}
+
+// GetUserCustomRecordsAfterTime получает пользовательские записи,
+// добавленные или измененные после указанного времени.
+func (db *SQLiteStorage) GetUserCustomRecordsAfterTime(ctx context.Context, userLogin string,
+	afterTime string) (records []CustomRecord, err error) {
+	return getRecordsAfterTime(ctx, db, customRecordsTable, userLogin, afterTime)
+}
+
+// GetUserCustomRecordsToSync получает пользовательские записи,
+// измененные локально и еще не переданные на сервер.
+func (db *SQLiteStorage) GetUserCustomRecordsToSync(ctx context.Context, userLogin string) (records []CustomRecord, err error) {
+	return getRecordsToSync(ctx, db, customRecordsTable, userLogin)
+}
+
+// GetCustomRecord получает пользовательскую запись по подсказке.
+func (db *SQLiteStorage) GetCustomRecord(ctx context.Context, userLogin string, prompt []byte) (r CustomRecord, err error) {
+	return getRecord(ctx, db, customRecordsTable, userLogin, CustomRecord{Prompt: prompt})
+}
+
+// GetSyncRevision получает номер ревизии данных на сервере, полученный при последней синхронизации.
+func (db *SQLiteStorage) GetSyncRevision(ctx context.Context, userLogin string) (revision int64, err error) {
+	ctx, cancel := db.queryCtx(ctx)
 	defer cancel()
 
 	row := db.dbHandle.QueryRowContext(ctx,
-		`SELECT last_sync
+		`SELECT sync_revision
 		FROM users
 		WHERE login = ?`, userLogin)
 
-	err = row.Scan(&lastSync)
+	err = row.Scan(&revision)
 	if err != nil {
-		return "", err
+		return 0, err
 	}
 
-	return lastSync, nil
+	return revision, nil
 }
 
-// UpdateLastSyncTime обновляет время последней синхронизации.
-func (db *SQLiteStorage) UpdateLastSyncTime(ctx context.Context, userLogin string, syncTime string) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// UpdateSyncRevision сохраняет номер ревизии данных на сервере после синхронизации
+// и снимает признак локального изменения с записей synced, принятых сервером.
+// Запись, измененная локально после передачи на сервер, остается непереданной.
+func (db *SQLiteStorage) UpdateSyncRevision(ctx context.Context, userLogin string, revision int64,
+	synced []any) (err error) {
+	ctx, cancel := db.bulkCtx(ctx)
 	defer cancel()
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`UPDATE users 
-		SET last_sync = ?
-		WHERE login = ?`, syncTime, userLogin)
-	if err != nil {
-		return err
-	}
-	row, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if row != 1 {
-		return errors.New("expected to affect 1 row")
-	}
-	return nil
+	return inTx(ctx, db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE users
+			SET sync_revision = ?
+			WHERE login = ?`, revision, userLogin)
+		if err != nil {
+			return err
+		}
+		row, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if row != 1 {
+			return errors.New("expected to affect 1 row")
+		}
+
+		for _, r := range synced {
+			err = clearPending(ctx, tx, userLogin, r)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // AddCard добавляет информацию о банковской карте.
 func (db *SQLiteStorage) AddCard(ctx context.Context, userLogin string, prompt []byte, number []byte, date []byte,
 	code []byte, note []byte, timeStamp string) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	result, err := db.dbHandle.ExecContext(ctx,
-		`INSERT INTO cards (user_id , prompt, number, date, code, note, time_stamp) 
-				VALUES ((SELECT user_id FROM users WHERE login = ?),?,?,?,?,?,?)`,
-		userLogin, prompt, number, date, code, note, timeStamp)
-	if err != nil {
-		return err
-	}
-
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rows != 1 {
-		return errors.New("expected to affect 1 row")
-	}
-
-	return nil
+	return addRecord(ctx, db, cardsTable, userLogin, Card{
+		Prompt:    prompt,
+		Number:    number,
+		Date:      date,
+		Code:      code,
+		Note:      note,
+		TimeStamp: timeStamp,
+	})
 }
 
 // AddLoginPwd добавляет информацию о паре логин-пароль.
 func (db *SQLiteStorage) AddLoginPwd(ctx context.Context, userLogin string, prompt []byte, login []byte,
 	pwd []byte, note []byte, timeStamp string) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	result, err := db.dbHandle.ExecContext(ctx,
-		`INSERT INTO logins (user_id , prompt, login, pwd, note, time_stamp) 
-		VALUES ((SELECT user_id FROM users WHERE login = ?),?,?,?,?,?)`,
-		userLogin, prompt, login, pwd, note, timeStamp)
-	if err != nil {
-		return err
-	}
-
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rows != 1 {
-		return errors.New("expected to affect 1 row")
-	}
-
-	return nil
+	return addRecord(ctx, db, loginsTable, userLogin, LoginPwd{
+		Prompt:    prompt,
+		Login:     login,
+		Pwd:       pwd,
+		Note:      note,
+		TimeStamp: timeStamp,
+	})
 }
 
 // AddTextRecord добавляет текстовую информацию.
 func (db *SQLiteStorage) AddTextRecord(ctx context.Context, userLogin string, prompt []byte,
 	data []byte, note []byte, timeStamp string) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	result, err := db.dbHandle.ExecContext(ctx,
-		`INSERT INTO text_data (user_id , prompt, data, note, time_stamp) 
-		VALUES ((SELECT user_id FROM users WHERE login = ?),?,?,?,?)`,
-		userLogin, prompt, data, note, timeStamp)
-	if err != nil {
-		return err
-	}
-
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rows != 1 {
-		return errors.New("expected to affect 1 row")
-	}
-
-	return nil
+	return addRecord(ctx, db, textsTable, userLogin, TextRecord{
+		Prompt:    prompt,
+		Data:      data,
+		Note:      note,
+		TimeStamp: timeStamp,
+	})
 }
 
 // AddBinaryRecord добавляет бинарную информацию.
 func (db *SQLiteStorage) AddBinaryRecord(ctx context.Context, userLogin string, prompt []byte,
 	data []byte, note []byte, timeStamp string) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
+	return addRecord(ctx, db, binariesTable, userLogin, BinaryRecord{
+		Prompt:    prompt,
+		Data:      data,
+		Note:      note,
+		TimeStamp: timeStamp,
+	})
+}
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`INSERT INTO binary_data (user_id , prompt, data, note, time_stamp) 
-		VALUES ((SELECT user_id FROM users WHERE login = ?),?,?,?,?)`,
-		userLogin, prompt, data, note, timeStamp)
-	if err != nil {
-		return err
-	}
+// AddOtp добавляет параметры генерации одноразовых кодов.
+func (db *SQLiteStorage) AddOtp(ctx context.Context, userLogin string, o Otp) (err error) {
+	return addRecord(ctx, db, otpsTable, userLogin, o)
+}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rows != 1 {
-		return errors.New("expected to affect 1 row")
-	}
+// AddSshKey добавляет ключ SSH.
+func (db *SQLiteStorage) AddSshKey(ctx context.Context, userLogin string, k SshKey) (err error) {
+	return addRecord(ctx, db, sshKeysTable, userLogin, k)
+}
 
-	return nil
+// AddTemplate добавляет шаблон пользовательских записей.
+func (db *SQLiteStorage) AddTemplate(ctx context.Context, userLogin string, t Template) (err error) {
+	return addRecord(ctx, db, templatesTable, userLogin, t)
+}
+
+// AddCustomRecord добавляет пользовательскую запись.
+func (db *SQLiteStorage) AddCustomRecord(ctx context.Context, userLogin string, r CustomRecord) (err error) {
+	return addRecord(ctx, db, customRecordsTable, userLogin, r)
 }
 
-// AddSyncData добавляет новые данные, полученные от сервера при синхронизации.
+// AddSyncData сохраняет данные, полученные от сервера при синхронизации.
+// Существующие записи заменяются, только если полученные записи изменены позже.
 func (db *SQLiteStorage) AddSyncData(ctx context.Context, userLogin string,
-	cards []Card, logins []LoginPwd, texts []TextRecord, binarys []BinaryRecord) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	cards []Card, logins []LoginPwd, texts []TextRecord, binarys []BinaryRecord, otps []Otp, sshKeys []SshKey,
+	templates []Template, customs []CustomRecord) (err error) {
+	ctx, cancel := db.bulkCtx(ctx)
 	defer cancel()
 
-	tx, err := db.dbHandle.Begin()
-	if err != nil {
-		return err
-	}
-
-	for _, v := range cards {
-		result, err := tx.ExecContext(ctx,
-			`INSERT INTO cards (user_id , prompt, number, date, code, note, time_stamp) 
-					VALUES ((SELECT user_id FROM users WHERE login = ?),?,?,?,?,?,?)`,
-			userLogin, v.Prompt, v.Number, v.Date, v.Code, v.Note, v.TimeStamp)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		rows, err := result.RowsAffected()
+	return inTx(ctx, db, func(tx *sql.Tx) error {
+		err := saveNewerRecordsTx(ctx, tx, cardsTable, userLogin, cards)
 		if err != nil {
-			tx.Rollback()
 			return err
 		}
-		if rows != 1 {
-			tx.Rollback()
-			return fmt.Errorf("expected to affect 1 row, affected %d", rows)
-		}
-	}
-
-	for _, v := range logins {
-		result, err := tx.ExecContext(ctx,
-			`INSERT INTO logins (user_id , prompt, login, pwd, note, time_stamp) 
-			VALUES ((SELECT user_id FROM users WHERE login = ?),?,?,?,?,?)`,
-			userLogin, v.Prompt, v.Login, v.Pwd, v.Note, v.TimeStamp)
+		err = saveNewerRecordsTx(ctx, tx, loginsTable, userLogin, logins)
 		if err != nil {
-			tx.Rollback()
 			return err
 		}
-		rows, err := result.RowsAffected()
+		err = saveNewerRecordsTx(ctx, tx, textsTable, userLogin, texts)
 		if err != nil {
-			tx.Rollback()
 			return err
 		}
-		if rows != 1 {
-			tx.Rollback()
-			return fmt.Errorf("expected to affect 1 row, affected %d", rows)
-		}
-	}
-
-	for _, v := range texts {
-		result, err := tx.ExecContext(ctx,
-			`INSERT INTO text_data (user_id , prompt, data, note, time_stamp) 
-			VALUES ((SELECT user_id FROM users WHERE login = ?),?,?,?,?)`,
-			userLogin, v.Prompt, v.Data, v.Note, v.TimeStamp)
+		err = saveNewerRecordsTx(ctx, tx, binariesTable, userLogin, binarys)
 		if err != nil {
-			tx.Rollback()
 			return err
 		}
-		rows, err := result.RowsAffected()
+		err = saveNewerRecordsTx(ctx, tx, otpsTable, userLogin, otps)
 		if err != nil {
-			tx.Rollback()
 			return err
 		}
-		if rows != 1 {
-			tx.Rollback()
-			return fmt.Errorf("expected to affect 1 row, affected %d", rows)
-		}
-	}
-
-	for _, v := range binarys {
-		result, err := tx.ExecContext(ctx,
-			`INSERT INTO binary_data (user_id , prompt, data, note, time_stamp) 
-			VALUES ((SELECT user_id FROM users WHERE login = ?),?,?,?,?)`,
-			userLogin, v.Prompt, v.Data, v.Note, v.TimeStamp)
+		err = saveNewerRecordsTx(ctx, tx, sshKeysTable, userLogin, sshKeys)
 		if err != nil {
-			tx.Rollback()
 			return err
 		}
-		rows, err := result.RowsAffected()
+		err = saveNewerRecordsTx(ctx, tx, templatesTable, userLogin, templates)
 		if err != nil {
-			tx.Rollback()
 			return err
 		}
-		if rows != 1 {
-			tx.Rollback()
-			return fmt.Errorf("expected to affect 1 row, affected %d", rows)
-		}
-	}
-
-	return tx.Commit()
+		return saveNewerRecordsTx(ctx, tx, customRecordsTable, userLogin, customs)
+	})
 }
 
 // UpdateCard обновляет информацию о банковской карте.
 func (db *SQLiteStorage) UpdateCard(ctx context.Context, userLogin string, prompt []byte,
 	number []byte, date []byte, code []byte, note []byte, timeStamp string) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	result, err := db.dbHandle.ExecContext(ctx,
-		`UPDATE cards 
-		SET prompt = ?, date = ?, code = ?, note = ?, time_stamp = ?
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND number = ?`,
-		prompt, date, code, note, timeStamp, userLogin, number)
-	if err != nil {
-		return err
-	}
-	row, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if row != 1 {
-		return errors.New("expected to affect 1 row")
-	}
-	return nil
+	return updateRecord(ctx, db, cardsTable, userLogin, Card{
+		Prompt:    prompt,
+		Number:    number,
+		Date:      date,
+		Code:      code,
+		Note:      note,
+		TimeStamp: timeStamp,
+	}, cardsTable.ContentColumns())
 }
 
 // UpdateLoginPwd обновляет информацию о паре логин-пароль.
 func (db *SQLiteStorage) UpdateLoginPwd(ctx context.Context, userLogin string, prompt []byte,
 	login []byte, pwd []byte, note []byte, timeStamp string) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	result, err := db.dbHandle.ExecContext(ctx,
-		`UPDATE logins 
-		SET pwd = ?, note = ?, time_stamp = ?
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND prompt = ?
-		AND login = ?`,
-		pwd, note, timeStamp, userLogin, prompt, login)
-	if err != nil {
-		return err
-	}
-	row, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if row != 1 {
-		return errors.New("expected to affect 1 row")
-	}
-	return nil
+	return updateRecord(ctx, db, loginsTable, userLogin, LoginPwd{
+		Prompt:    prompt,
+		Login:     login,
+		Pwd:       pwd,
+		Note:      note,
+		TimeStamp: timeStamp,
+	}, loginsTable.ContentColumns())
 }
 
 // UpdateTextRecord обновляет текстовую информацию.
 func (db *SQLiteStorage) UpdateTextRecord(ctx context.Context, userLogin string, prompt []byte,
 	data []byte, note []byte, timeStamp string) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	return updateRecord(ctx, db, textsTable, userLogin, TextRecord{
+		Prompt:    prompt,
+		Data:      data,
+		Note:      note,
+		TimeStamp: timeStamp,
+	}, textsTable.ContentColumns())
+}
+
+// UpdateBinaryRecord обновляет бинарные данные.
+func (db *SQLiteStorage) UpdateBinaryRecord(ctx context.Context, userLogin string, prompt []byte,
+	data []byte, note []byte, timeStamp string) (err error) {
+	return updateRecord(ctx, db, binariesTable, userLogin, BinaryRecord{
+		Prompt:    prompt,
+		Data:      data,
+		Note:      note,
+		TimeStamp: timeStamp,
+	}, binariesTable.ContentColumns())
+}
+
+// UpdateLabel обновляет метку label записи, заданной ключевыми полями key.
+func (db *SQLiteStorage) UpdateLabel(ctx context.Context, userLogin string, key any,
+	label Label, value []byte, timeStamp string) (err error) {
+	switch k := key.(type) {
+	case Card:
+		return updateLabel(ctx, db, cardsTable, userLogin, k, label, value, timeStamp)
+	case LoginPwd:
+		return updateLabel(ctx, db, loginsTable, userLogin, k, label, value, timeStamp)
+	case TextRecord:
+		return updateLabel(ctx, db, textsTable, userLogin, k, label, value, timeStamp)
+	case BinaryRecord:
+		return updateLabel(ctx, db, binariesTable, userLogin, k, label, value, timeStamp)
+	case Otp:
+		return updateLabel(ctx, db, otpsTable, userLogin, k, label, value, timeStamp)
+	case SshKey:
+		return updateLabel(ctx, db, sshKeysTable, userLogin, k, label, value, timeStamp)
+	case Template:
+		return updateLabel(ctx, db, templatesTable, userLogin, k, label, value, timeStamp)
+	case CustomRecord:
+		return updateLabel(ctx, db, customRecordsTable, userLogin, k, label, value, timeStamp)
+	default:
+		return fmt.Errorf("unsupported record type %T", key)
+	}
+}
+
+// GetUserRecords получает все записи пользователя любых типов.
+func (db *SQLiteStorage) GetUserRecords(ctx context.Context, userLogin string) (records []any, err error) {
+	ctx, cancel := db.bulkCtx(ctx)
 	defer cancel()
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`UPDATE text_data 
-		SET data = ?, note = ?, time_stamp = ?
-		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND prompt = ?`,
-		data, note, timeStamp, userLogin, prompt)
-	if err != nil {
-		return err
+	for _, list := range []func(context.Context, *SQLiteStorage, string, []any) ([]any, error){
+		listRecords(cardsTable),
+		listRecords(loginsTable),
+		listRecords(textsTable),
+		listRecords(binariesTable),
+		listRecords(otpsTable),
+		listRecords(sshKeysTable),
+		listRecords(templatesTable),
+		listRecords(customRecordsTable),
+	} {
+		records, err = list(ctx, db, userLogin, records)
+		if err != nil {
+			return nil, err
+		}
 	}
-	row, err := result.RowsAffected()
-	if err != nil {
-		return err
+	return records, nil
+}
+
+// DeleteRecord удаляет запись пользователя с ключевыми полями key.
+func (db *SQLiteStorage) DeleteRecord(ctx context.Context, userLogin string, key any) (err error) {
+	switch k := key.(type) {
+	case Card:
+		return deleteRecord(ctx, db, cardsTable, userLogin, k)
+	case LoginPwd:
+		return deleteRecord(ctx, db, loginsTable, userLogin, k)
+	case TextRecord:
+		return deleteRecord(ctx, db, textsTable, userLogin, k)
+	case BinaryRecord:
+		return deleteRecord(ctx, db, binariesTable, userLogin, k)
+	case Otp:
+		return deleteRecord(ctx, db, otpsTable, userLogin, k)
+	case SshKey:
+		return deleteRecord(ctx, db, sshKeysTable, userLogin, k)
+	case Template:
+		return deleteRecord(ctx, db, templatesTable, userLogin, k)
+	case CustomRecord:
+		return deleteRecord(ctx, db, customRecordsTable, userLogin, k)
+	default:
+		return fmt.Errorf("unsupported record type %T", key)
 	}
-	if row != 1 {
-		return errors.New("expected to affect 1 row")
+}
+
+// RestoreRecord заменяет запись пользователя восстановленной на сервере версией r.
+func (db *SQLiteStorage) RestoreRecord(ctx context.Context, userLogin string, r any) (err error) {
+	switch v := r.(type) {
+	case Card:
+		return saveRecord(ctx, db, cardsTable, userLogin, v)
+	case LoginPwd:
+		return saveRecord(ctx, db, loginsTable, userLogin, v)
+	case TextRecord:
+		return saveRecord(ctx, db, textsTable, userLogin, v)
+	case BinaryRecord:
+		return saveRecord(ctx, db, binariesTable, userLogin, v)
+	case Otp:
+		return saveRecord(ctx, db, otpsTable, userLogin, v)
+	case SshKey:
+		return saveRecord(ctx, db, sshKeysTable, userLogin, v)
+	case Template:
+		return saveRecord(ctx, db, templatesTable, userLogin, v)
+	case CustomRecord:
+		return saveRecord(ctx, db, customRecordsTable, userLogin, v)
+	default:
+		return fmt.Errorf("unsupported record type %T", r)
 	}
-	return nil
 }
 
-// UpdateBinaryRecord обновляет бинарные данные.
-func (db *SQLiteStorage) UpdateBinaryRecord(ctx context.Context, userLogin string, prompt []byte,
-	data []byte, note []byte, timeStamp string) (err error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+// SaveServerRecord сохраняет запись r, измененную на сервере,
+// если локальная запись отсутствует или изменена раньше.
+func (db *SQLiteStorage) SaveServerRecord(ctx context.Context, userLogin string, r any) (err error) {
+	switch v := r.(type) {
+	case Card:
+		return saveNewerRecord(ctx, db, cardsTable, userLogin, v)
+	case LoginPwd:
+		return saveNewerRecord(ctx, db, loginsTable, userLogin, v)
+	case TextRecord:
+		return saveNewerRecord(ctx, db, textsTable, userLogin, v)
+	case BinaryRecord:
+		return saveNewerRecord(ctx, db, binariesTable, userLogin, v)
+	case Otp:
+		return saveNewerRecord(ctx, db, otpsTable, userLogin, v)
+	case SshKey:
+		return saveNewerRecord(ctx, db, sshKeysTable, userLogin, v)
+	case Template:
+		return saveNewerRecord(ctx, db, templatesTable, userLogin, v)
+	case CustomRecord:
+		return saveNewerRecord(ctx, db, customRecordsTable, userLogin, v)
+	default:
+		return fmt.Errorf("unsupported record type %T", r)
+	}
+}
+
+// Revision хранит идентификатор, номер ревизии и время изменения записи на сервере.
+// Type содержит тип записи в нумерации RecordType протокола,
+// Prompt и Key - ключевые поля записи в том же виде, что и в запросе GetRecord.
+type Revision struct {
+	Type      int32
+	Prompt    []byte
+	Key       []byte
+	ID        int64
+	Revision  int64
+	TimeStamp string
+}
+
+// SaveRevision сохраняет ревизию записи, полученную от сервера.
+func (db *SQLiteStorage) SaveRevision(ctx context.Context, userLogin string, r Revision) (err error) {
+	ctx, cancel := db.queryCtx(ctx)
 	defer cancel()
 
-	result, err := db.dbHandle.ExecContext(ctx,
-		`UPDATE binary_data 
-		SET data = ?, note = ?, time_stamp = ?
+	_, err = db.dbHandle.ExecContext(ctx,
+		`INSERT INTO revisions (user_id, record_type, prompt, record_key, record_id, revision, time_stamp)
+		VALUES ((SELECT user_id FROM users WHERE login = ?), ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, record_type, prompt, record_key)
+		DO UPDATE SET record_id = excluded.record_id, revision = excluded.revision, time_stamp = excluded.time_stamp`,
+		userLogin, r.Type, nonNil(r.Prompt), nonNil(r.Key), r.ID, r.Revision, r.TimeStamp)
+	return err
+}
+
+// GetRevision получает сохраненную ревизию записи с типом recordType и ключевыми полями prompt и key.
+func (db *SQLiteStorage) GetRevision(ctx context.Context, userLogin string, recordType int32,
+	prompt []byte, key []byte) (r Revision, err error) {
+	ctx, cancel := db.queryCtx(ctx)
+	defer cancel()
+
+	row := db.dbHandle.QueryRowContext(ctx,
+		`SELECT record_id, revision, time_stamp
+		FROM revisions
 		WHERE user_id = (SELECT user_id FROM users WHERE login = ?)
-		AND prompt = ?`,
-		data, note, timeStamp, userLogin, prompt)
+		AND record_type = ? AND prompt = ? AND record_key = ?`,
+		userLogin, recordType, nonNil(prompt), nonNil(key))
+	err = row.Scan(&r.ID, &r.Revision, &r.TimeStamp)
 	if err != nil {
-		return err
+		return Revision{}, err
 	}
-	row, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if row != 1 {
-		return errors.New("expected to affect 1 row")
-	}
-	return nil
+	r.Type, r.Prompt, r.Key = recordType, prompt, key
+
+	return r, nil
 }
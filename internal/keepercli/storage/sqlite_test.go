@@ -2,12 +2,16 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"path/filepath"
 	"testing"
+	"time"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -43,6 +47,36 @@ var (
 		Note:      []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
 		TimeStamp: testTime,
 	}
+	testOtp = Otp{
+		Issuer:    []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		Account:   []byte{20, 251, 144, 177, 213, 73, 247, 129, 45, 118, 66, 54, 135, 0, 6, 123, 43, 112, 216, 226, 196},
+		Secret:    []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		Algorithm: []byte{73, 166, 196, 108},
+		Digits:    []byte{75},
+		Period:    []byte{75, 85},
+		Note:      []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
+		TimeStamp: testTime,
+	}
+	testSshKey = SshKey{
+		Prompt:     []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		PrivateKey: []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		PublicKey:  []byte{20, 251, 144, 177, 213, 73, 247, 129, 45, 118, 66, 54, 135, 0, 6, 123, 43, 112, 216, 226, 196},
+		Comment:    []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
+		Passphrase: []byte{75, 85},
+		TimeStamp:  testTime,
+	}
+	testTemplate = Template{
+		Name:      []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		Fields:    []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		TimeStamp: testTime,
+	}
+	testCustomRecord = CustomRecord{
+		Prompt:    []byte{8, 230, 152, 2, 249, 163, 40, 83, 43, 16, 152, 201, 204, 108, 25, 36, 123, 91, 33},
+		Template:  []byte{20, 251, 144, 177, 213, 73, 247, 129, 45, 118, 66, 54, 135, 0, 6, 123, 43, 112, 216, 226, 196},
+		Data:      []byte{8, 227, 147, 155, 5, 129, 24, 36, 16, 17, 54, 190, 134, 36, 109, 15, 120, 13, 182},
+		Note:      []byte{22, 251, 131, 189, 215, 11, 255, 110, 100, 134, 201, 112, 212, 94, 71, 85, 234, 240, 187, 114},
+		TimeStamp: testTime,
+	}
 )
 
 func TestCreateTables(t *testing.T) {
@@ -69,6 +103,11 @@ func TestCreateTables(t *testing.T) {
 				mock.ExpectExec("CREATE TABLE IF NOT EXISTS cards").WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectExec("CREATE TABLE IF NOT EXISTS text_data").WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectExec("CREATE TABLE IF NOT EXISTS binary_data").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS otps").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS ssh_keys").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS templates").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS custom_records").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS revisions").WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
 		},
@@ -117,6 +156,46 @@ func TestCreateTables(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "create otp error",
+			mockBehavior: func() {
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS logins").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS cards").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS text_data").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS binary_data").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS otps").WillReturnError(errTest)
+			},
+			wantErr: true,
+		},
+		{
+			name: "create ssh key error",
+			mockBehavior: func() {
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS logins").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS cards").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS text_data").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS binary_data").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS otps").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS ssh_keys").WillReturnError(errTest)
+			},
+			wantErr: true,
+		},
+		{
+			name: "create custom record error",
+			mockBehavior: func() {
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS logins").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS cards").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS text_data").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS binary_data").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS otps").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS ssh_keys").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS templates").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS custom_records").WillReturnError(errTest)
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -280,12 +359,12 @@ func TestGetUserCardsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
-				rows:   []string{"prompt", "number", "date", "code", "note", "time_stamp"},
-				values: []driver.Value{testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.TimeStamp},
+				rows:   []string{"prompt", "number", "date", "code", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.Tags, testCard.Folder, testCard.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT prompt, number, date, code, note, time_stamp FROM cards").
+				mock.ExpectQuery("SELECT prompt, number, date, code, note, tags, folder, time_stamp FROM cards").
 					WithArgs([]driver.Value{testUserLogin, testTimeEarlier}...).
 					WillReturnRows(rows)
 			},
@@ -297,11 +376,11 @@ func TestGetUserCardsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
-				rows:   []string{"prompt", "number", "date", "code", "note", "time_stamp"},
-				values: []driver.Value{testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.TimeStamp},
+				rows:   []string{"prompt", "number", "date", "code", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.Tags, testCard.Folder, testCard.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT prompt, number, date, code, note, time_stamp FROM cards").
+				mock.ExpectQuery("SELECT prompt, number, date, code, note, tags, folder, time_stamp FROM cards").
 					WithArgs([]driver.Value{testUserLogin, testTimeEarlier}...).
 					WillReturnError(errTest)
 			},
@@ -324,6 +403,79 @@ func TestGetUserCardsAfterTime(t *testing.T) {
 	}
 }
 
+func TestGetUserCardsToSync(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	type args struct {
+		c      Card
+		rows   []string
+		values []driver.Value
+	}
+	type mockBehavior func(a args)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		args         args
+		mockBehavior mockBehavior
+		wantRes      []Card
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			ctx:  context.Background(),
+			args: args{
+				c:      testCard,
+				rows:   []string{"prompt", "number", "date", "code", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.Tags, testCard.Folder, testCard.TimeStamp},
+			},
+			mockBehavior: func(a args) {
+				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
+				mock.ExpectQuery("SELECT prompt, number, date, code, note, tags, folder, time_stamp FROM cards").
+					WithArgs(testUserLogin).
+					WillReturnRows(rows)
+			},
+			wantRes: []Card{testCard},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			ctx:  context.Background(),
+			args: args{
+				c:      testCard,
+				rows:   []string{"prompt", "number", "date", "code", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testCard.Prompt, testCard.Number, testCard.Date, testCard.Code, testCard.Note, testCard.Tags, testCard.Folder, testCard.TimeStamp},
+			},
+			mockBehavior: func(a args) {
+				mock.ExpectQuery("SELECT prompt, number, date, code, note, tags, folder, time_stamp FROM cards").
+					WithArgs(testUserLogin).
+					WillReturnError(errTest)
+			},
+			wantRes: nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			c, err := testDB.GetUserCardsToSync(tt.ctx, testUserLogin)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRes, c)
+			}
+		})
+	}
+}
+
 func TestGetCard(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -353,12 +505,12 @@ func TestGetCard(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
-				rows:   []string{"prompt", "date", "code", "note", "time_stamp"},
-				values: []driver.Value{testCard.Prompt, testCard.Date, testCard.Code, testCard.Note, testCard.TimeStamp},
+				rows:   []string{"prompt", "date", "code", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testCard.Prompt, testCard.Date, testCard.Code, testCard.Note, testCard.Tags, testCard.Folder, testCard.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT prompt, date, code, note, time_stamp FROM cards").
+				mock.ExpectQuery("SELECT prompt, date, code, note, tags, folder, time_stamp FROM cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Number}...).
 					WillReturnRows(rows)
 			},
@@ -370,11 +522,11 @@ func TestGetCard(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testCard,
-				rows:   []string{"prompt", "date", "code", "note", "time_stamp"},
-				values: []driver.Value{testCard.Prompt, testCard.Date, testCard.Code, testCard.Note, testCard.TimeStamp},
+				rows:   []string{"prompt", "date", "code", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testCard.Prompt, testCard.Date, testCard.Code, testCard.Note, testCard.Tags, testCard.Folder, testCard.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT prompt, date, code, note, time_stamp FROM cards").
+				mock.ExpectQuery("SELECT prompt, date, code, note, tags, folder, time_stamp FROM cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Number}...).
 					WillReturnError(errTest)
 			},
@@ -426,12 +578,12 @@ func TestGetUserLoginsPwdsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
-				rows:   []string{"prompt", "login", "pwd", "note", "time_stamp"},
-				values: []driver.Value{testLoginPwd.Prompt, testLoginPwd.Login, testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.TimeStamp},
+				rows:   []string{"prompt", "login", "pwd", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testLoginPwd.Prompt, testLoginPwd.Login, testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.Tags, testLoginPwd.Folder, testLoginPwd.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT prompt, login, pwd, note, time_stamp FROM logins").
+				mock.ExpectQuery("SELECT prompt, login, pwd, note, tags, folder, time_stamp FROM logins").
 					WithArgs([]driver.Value{testUserLogin, testTimeEarlier}...).
 					WillReturnRows(rows)
 			},
@@ -443,11 +595,11 @@ func TestGetUserLoginsPwdsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
-				rows:   []string{"prompt", "login", "pwd", "note", "time_stamp"},
-				values: []driver.Value{testLoginPwd.Prompt, testLoginPwd.Login, testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.TimeStamp},
+				rows:   []string{"prompt", "login", "pwd", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testLoginPwd.Prompt, testLoginPwd.Login, testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.Tags, testLoginPwd.Folder, testLoginPwd.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT prompt, login, pwd, note, time_stamp FROM logins").
+				mock.ExpectQuery("SELECT prompt, login, pwd, note, tags, folder, time_stamp FROM logins").
 					WithArgs([]driver.Value{testUserLogin, testTimeEarlier}...).
 					WillReturnError(errTest)
 			},
@@ -470,6 +622,79 @@ func TestGetUserLoginsPwdsAfterTime(t *testing.T) {
 	}
 }
 
+func TestGetUserLoginsPwdsToSync(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	type args struct {
+		c      LoginPwd
+		rows   []string
+		values []driver.Value
+	}
+	type mockBehavior func(a args)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		args         args
+		mockBehavior mockBehavior
+		wantRes      []LoginPwd
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			ctx:  context.Background(),
+			args: args{
+				c:      testLoginPwd,
+				rows:   []string{"prompt", "login", "pwd", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testLoginPwd.Prompt, testLoginPwd.Login, testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.Tags, testLoginPwd.Folder, testLoginPwd.TimeStamp},
+			},
+			mockBehavior: func(a args) {
+				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
+				mock.ExpectQuery("SELECT prompt, login, pwd, note, tags, folder, time_stamp FROM logins").
+					WithArgs(testUserLogin).
+					WillReturnRows(rows)
+			},
+			wantRes: []LoginPwd{testLoginPwd},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			ctx:  context.Background(),
+			args: args{
+				c:      testLoginPwd,
+				rows:   []string{"prompt", "login", "pwd", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testLoginPwd.Prompt, testLoginPwd.Login, testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.Tags, testLoginPwd.Folder, testLoginPwd.TimeStamp},
+			},
+			mockBehavior: func(a args) {
+				mock.ExpectQuery("SELECT prompt, login, pwd, note, tags, folder, time_stamp FROM logins").
+					WithArgs(testUserLogin).
+					WillReturnError(errTest)
+			},
+			wantRes: nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			c, err := testDB.GetUserLoginsPwdsToSync(tt.ctx, testUserLogin)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRes, c)
+			}
+		})
+	}
+}
+
 func TestGetLoginPwd(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -499,12 +724,12 @@ func TestGetLoginPwd(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
-				rows:   []string{"pwd", "note", "time_stamp"},
-				values: []driver.Value{testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.TimeStamp},
+				rows:   []string{"pwd", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.Tags, testLoginPwd.Folder, testLoginPwd.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT pwd, note, time_stamp FROM logins").
+				mock.ExpectQuery("SELECT pwd, note, tags, folder, time_stamp FROM logins").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login}...).
 					WillReturnRows(rows)
 			},
@@ -516,11 +741,11 @@ func TestGetLoginPwd(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testLoginPwd,
-				rows:   []string{"prompt", "note", "time_stamp"},
-				values: []driver.Value{testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.TimeStamp},
+				rows:   []string{"prompt", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testLoginPwd.Pwd, testLoginPwd.Note, testLoginPwd.Tags, testLoginPwd.Folder, testLoginPwd.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT prompt, date, code, note, time_stamp FROM cards").
+				mock.ExpectQuery("SELECT prompt, date, code, note, tags, folder, time_stamp FROM cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login}...).
 					WillReturnError(errTest)
 			},
@@ -572,12 +797,12 @@ func TestGetUserTextRecordsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
-				rows:   []string{"prompt", "data", "note", "time_stamp"},
-				values: []driver.Value{testTextRecord.Prompt, testTextRecord.Data, testTextRecord.Note, testTextRecord.TimeStamp},
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testTextRecord.Prompt, testTextRecord.Data, testTextRecord.Note, testTextRecord.Tags, testTextRecord.Folder, testTextRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT prompt, data, note, time_stamp FROM text_data").
+				mock.ExpectQuery("SELECT prompt, data, note, tags, folder, time_stamp FROM text_data").
 					WithArgs([]driver.Value{testUserLogin, testTimeEarlier}...).
 					WillReturnRows(rows)
 			},
@@ -589,11 +814,11 @@ func TestGetUserTextRecordsAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
-				rows:   []string{"prompt", "data", "note", "time_stamp"},
-				values: []driver.Value{testTextRecord.Prompt, testTextRecord.Data, testTextRecord.Note, testTextRecord.TimeStamp},
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testTextRecord.Prompt, testTextRecord.Data, testTextRecord.Note, testTextRecord.Tags, testTextRecord.Folder, testTextRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT prompt, data, note, time_stamp FROM text_data").
+				mock.ExpectQuery("SELECT prompt, data, note, tags, folder, time_stamp FROM text_data").
 					WithArgs([]driver.Value{testUserLogin, testTimeEarlier}...).
 					WillReturnError(errTest)
 			},
@@ -616,6 +841,79 @@ func TestGetUserTextRecordsAfterTime(t *testing.T) {
 	}
 }
 
+func TestGetUserTextRecordsToSync(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	type args struct {
+		c      TextRecord
+		rows   []string
+		values []driver.Value
+	}
+	type mockBehavior func(a args)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		args         args
+		mockBehavior mockBehavior
+		wantRes      []TextRecord
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			ctx:  context.Background(),
+			args: args{
+				c:      testTextRecord,
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testTextRecord.Prompt, testTextRecord.Data, testTextRecord.Note, testTextRecord.Tags, testTextRecord.Folder, testTextRecord.TimeStamp},
+			},
+			mockBehavior: func(a args) {
+				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
+				mock.ExpectQuery("SELECT prompt, data, note, tags, folder, time_stamp FROM text_data").
+					WithArgs(testUserLogin).
+					WillReturnRows(rows)
+			},
+			wantRes: []TextRecord{testTextRecord},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			ctx:  context.Background(),
+			args: args{
+				c:      testTextRecord,
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testTextRecord.Prompt, testTextRecord.Data, testTextRecord.Note, testTextRecord.Tags, testTextRecord.Folder, testTextRecord.TimeStamp},
+			},
+			mockBehavior: func(a args) {
+				mock.ExpectQuery("SELECT prompt, data, note, tags, folder, time_stamp FROM text_data").
+					WithArgs(testUserLogin).
+					WillReturnError(errTest)
+			},
+			wantRes: nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			c, err := testDB.GetUserTextRecordsToSync(tt.ctx, testUserLogin)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRes, c)
+			}
+		})
+	}
+}
+
 func TestGetTextRecord(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -645,12 +943,12 @@ func TestGetTextRecord(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
-				rows:   []string{"data", "note", "time_stamp"},
-				values: []driver.Value{testTextRecord.Data, testTextRecord.Note, testTextRecord.TimeStamp},
+				rows:   []string{"data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testTextRecord.Data, testTextRecord.Note, testTextRecord.Tags, testTextRecord.Folder, testTextRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT data, note, time_stamp FROM text_data").
+				mock.ExpectQuery("SELECT data, note, tags, folder, time_stamp FROM text_data").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt}...).
 					WillReturnRows(rows)
 			},
@@ -662,11 +960,11 @@ func TestGetTextRecord(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testTextRecord,
-				rows:   []string{"data", "note", "time_stamp"},
-				values: []driver.Value{testTextRecord.Data, testTextRecord.Note, testTextRecord.TimeStamp},
+				rows:   []string{"data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testTextRecord.Data, testTextRecord.Note, testTextRecord.Tags, testTextRecord.Folder, testTextRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT data, note, time_stamp FROM text_data").
+				mock.ExpectQuery("SELECT data, note, tags, folder, time_stamp FROM text_data").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt}...).
 					WillReturnError(errTest)
 			},
@@ -717,12 +1015,12 @@ func TestGetUserBinaryRecordAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
-				rows:   []string{"prompt", "data", "note", "time_stamp"},
-				values: []driver.Value{testBinaryRecord.Prompt, testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.TimeStamp},
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testBinaryRecord.Prompt, testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.Tags, testBinaryRecord.Folder, testBinaryRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT prompt, data, note, time_stamp FROM binary_data").
+				mock.ExpectQuery("SELECT prompt, data, note, tags, folder, time_stamp FROM binary_data").
 					WithArgs([]driver.Value{testUserLogin, testTimeEarlier}...).
 					WillReturnRows(rows)
 			},
@@ -734,11 +1032,11 @@ func TestGetUserBinaryRecordAfterTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
-				rows:   []string{"prompt", "data", "note", "time_stamp"},
-				values: []driver.Value{testBinaryRecord.Prompt, testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.TimeStamp},
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testBinaryRecord.Prompt, testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.Tags, testBinaryRecord.Folder, testBinaryRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT data, note, time_stamp FROM binary_data").
+				mock.ExpectQuery("SELECT data, note, tags, folder, time_stamp FROM binary_data").
 					WithArgs([]driver.Value{testUserLogin, testTimeEarlier}...).
 					WillReturnError(errTest)
 			},
@@ -761,6 +1059,79 @@ func TestGetUserBinaryRecordAfterTime(t *testing.T) {
 	}
 }
 
+func TestGetUserBinaryRecordToSync(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	type args struct {
+		c      BinaryRecord
+		rows   []string
+		values []driver.Value
+	}
+	type mockBehavior func(a args)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		args         args
+		mockBehavior mockBehavior
+		wantRes      []BinaryRecord
+		wantErr      bool
+	}{
+		{
+			name: "ok test",
+			ctx:  context.Background(),
+			args: args{
+				c:      testBinaryRecord,
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testBinaryRecord.Prompt, testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.Tags, testBinaryRecord.Folder, testBinaryRecord.TimeStamp},
+			},
+			mockBehavior: func(a args) {
+				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
+				mock.ExpectQuery("SELECT prompt, data, note, tags, folder, time_stamp FROM binary_data").
+					WithArgs(testUserLogin).
+					WillReturnRows(rows)
+			},
+			wantRes: []BinaryRecord{testBinaryRecord},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			ctx:  context.Background(),
+			args: args{
+				c:      testBinaryRecord,
+				rows:   []string{"prompt", "data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testBinaryRecord.Prompt, testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.Tags, testBinaryRecord.Folder, testBinaryRecord.TimeStamp},
+			},
+			mockBehavior: func(a args) {
+				mock.ExpectQuery("SELECT data, note, tags, folder, time_stamp FROM binary_data").
+					WithArgs(testUserLogin).
+					WillReturnError(errTest)
+			},
+			wantRes: nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			c, err := testDB.GetUserBinaryRecordsToSync(tt.ctx, testUserLogin)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRes, c)
+			}
+		})
+	}
+}
+
 func TestGetBinaryRecord(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -790,12 +1161,12 @@ func TestGetBinaryRecord(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
-				rows:   []string{"data", "note", "time_stamp"},
-				values: []driver.Value{testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.TimeStamp},
+				rows:   []string{"data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.Tags, testBinaryRecord.Folder, testBinaryRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT data, note, time_stamp FROM binary_data").
+				mock.ExpectQuery("SELECT data, note, tags, folder, time_stamp FROM binary_data").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt}...).
 					WillReturnRows(rows)
 			},
@@ -807,11 +1178,11 @@ func TestGetBinaryRecord(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{
 				c:      testBinaryRecord,
-				rows:   []string{"data", "note", "time_stamp"},
-				values: []driver.Value{testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.TimeStamp},
+				rows:   []string{"data", "note", "tags", "folder", "time_stamp"},
+				values: []driver.Value{testBinaryRecord.Data, testBinaryRecord.Note, testBinaryRecord.Tags, testBinaryRecord.Folder, testBinaryRecord.TimeStamp},
 			},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT data, note, time_stamp FROM binary_data").
+				mock.ExpectQuery("SELECT data, note, tags, folder, time_stamp FROM binary_data").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt}...).
 					WillReturnError(errTest)
 			},
@@ -834,7 +1205,7 @@ func TestGetBinaryRecord(t *testing.T) {
 	}
 }
 
-func TestGetLastSyncTime(t *testing.T) {
+func TestGetSyncRevision(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("An error occurred while creating mock: %s", err)
@@ -854,23 +1225,23 @@ func TestGetLastSyncTime(t *testing.T) {
 		ctx          context.Context
 		args         args
 		mockBehavior mockBehavior
-		wantRes      string
+		wantRes      int64
 		wantErr      bool
 	}{
 		{
 			name: "ok test",
 			ctx:  context.Background(),
 			args: args{
-				rows:   []string{"last_sync"},
-				values: []driver.Value{testTime},
+				rows:   []string{"sync_revision"},
+				values: []driver.Value{int64(7)},
 			},
 			mockBehavior: func(a args) {
 				rows := sqlmock.NewRows(a.rows).AddRow(a.values...)
-				mock.ExpectQuery("SELECT last_sync FROM users").
+				mock.ExpectQuery("SELECT sync_revision FROM users").
 					WithArgs([]driver.Value{testUserLogin}...).
 					WillReturnRows(rows)
 			},
-			wantRes: testTime,
+			wantRes: 7,
 			wantErr: false,
 		},
 		{
@@ -878,11 +1249,11 @@ func TestGetLastSyncTime(t *testing.T) {
 			ctx:  context.Background(),
 			args: args{},
 			mockBehavior: func(a args) {
-				mock.ExpectQuery("SELECT last_sync FROM users").
+				mock.ExpectQuery("SELECT sync_revision FROM users").
 					WithArgs([]driver.Value{testUserLogin}...).
 					WillReturnError(errTest)
 			},
-			wantRes: "",
+			wantRes: 0,
 			wantErr: true,
 		},
 	}
@@ -890,18 +1261,18 @@ func TestGetLastSyncTime(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			l, err := testDB.GetLastSyncTime(tt.ctx, testUserLogin)
+			rev, err := testDB.GetSyncRevision(tt.ctx, testUserLogin)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.wantRes, l)
+				assert.Equal(t, tt.wantRes, rev)
 			}
 		})
 	}
 }
 
-func TestUpdateLastSyncTime(t *testing.T) {
+func TestUpdateSyncRevision(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("An error occurred while creating mock: %s", err)
@@ -915,16 +1286,26 @@ func TestUpdateLastSyncTime(t *testing.T) {
 	tests := []struct {
 		name         string
 		ctx          context.Context
+		synced       []any
 		mockBehavior mockBehavior
 		wantErr      bool
 	}{
 		{
-			name: "ok test",
-			ctx:  context.Background(),
+			name:   "ok test",
+			ctx:    context.Background(),
+			synced: []any{testCard, testTextRecord},
 			mockBehavior: func() {
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE users").
-					WithArgs([]driver.Value{testTime, testUserLogin}...).
+					WithArgs([]driver.Value{int64(7), testUserLogin}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE cards SET pending = 0 (.+) AND time_stamp = ?").
+					WithArgs(testUserLogin, testCard.Number, testCard.TimeStamp).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE text_data SET pending = 0 (.+) AND time_stamp = ?").
+					WithArgs(testUserLogin, testTextRecord.Prompt, testTextRecord.TimeStamp).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
 			},
 			wantErr: false,
 		},
@@ -932,9 +1313,11 @@ func TestUpdateLastSyncTime(t *testing.T) {
 			name: "error",
 			ctx:  context.Background(),
 			mockBehavior: func() {
-				mock.ExpectQuery("UPDATE users").
-					WithArgs([]driver.Value{testTime, testUserLogin}...).
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users").
+					WithArgs([]driver.Value{int64(7), testUserLogin}...).
 					WillReturnError(errTest)
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -942,9 +1325,39 @@ func TestUpdateLastSyncTime(t *testing.T) {
 			name: "error rows",
 			ctx:  context.Background(),
 			mockBehavior: func() {
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE users").
-					WithArgs([]driver.Value{testTime, testUserLogin}...).
+					WithArgs([]driver.Value{int64(7), testUserLogin}...).
 					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name:   "clear pending error",
+			ctx:    context.Background(),
+			synced: []any{testCard},
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users").
+					WithArgs([]driver.Value{int64(7), testUserLogin}...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE cards SET pending = 0").
+					WillReturnError(errTest)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name:   "unsupported record test",
+			ctx:    context.Background(),
+			synced: []any{"record"},
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users").
+					WithArgs([]driver.Value{int64(7), testUserLogin}...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -953,12 +1366,13 @@ func TestUpdateLastSyncTime(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior()
-			err := testDB.UpdateLastSyncTime(tt.ctx, testUserLogin, testTime)
+			err := testDB.UpdateSyncRevision(tt.ctx, testUserLogin, 7, tt.synced)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -993,7 +1407,7 @@ func TestAddCard(t *testing.T) {
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, a.c.TimeStamp}...).
+						a.c.Code, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
@@ -1007,7 +1421,7 @@ func TestAddCard(t *testing.T) {
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, a.c.TimeStamp}...).
+						a.c.Code, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(2, 2))
 			},
 			wantErr: true,
@@ -1021,7 +1435,7 @@ func TestAddCard(t *testing.T) {
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date,
-						a.c.Code, a.c.Note, a.c.TimeStamp}...).
+						a.c.Code, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnError(errTest)
 			},
 			wantErr: true,
@@ -1071,7 +1485,7 @@ func TestAddLoginPwd(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, a.c.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
@@ -1084,7 +1498,7 @@ func TestAddLoginPwd(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, a.c.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(2, 2))
 			},
 			wantErr: true,
@@ -1097,7 +1511,7 @@ func TestAddLoginPwd(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, a.c.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Login, a.c.Pwd, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnError(errTest)
 			},
 			wantErr: true,
@@ -1147,7 +1561,7 @@ func TestAddTextRecord(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
@@ -1160,7 +1574,7 @@ func TestAddTextRecord(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(2, 2))
 			},
 			wantErr: true,
@@ -1173,7 +1587,7 @@ func TestAddTextRecord(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnError(errTest)
 			},
 			wantErr: true,
@@ -1222,7 +1636,7 @@ func TestAddBinaryRecord(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
@@ -1235,7 +1649,7 @@ func TestAddBinaryRecord(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(2, 2))
 			},
 			wantErr: true,
@@ -1248,7 +1662,7 @@ func TestAddBinaryRecord(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Data, a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).
 					WillReturnError(errTest)
 			},
 			wantErr: true,
@@ -1304,18 +1718,92 @@ func TestAddSyncData(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT time_stamp FROM cards").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code,
-						a.c.Note, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+						a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE cards SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM logins").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note,
+					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, a.l.Tags, a.l.Folder,
 						a.l.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE logins SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM text_data").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.t.Prompt, a.t.Data, a.t.Note, a.t.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.t.Prompt, a.t.Data, a.t.Note, a.t.Tags, a.t.Folder, a.t.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE text_data SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM binary_data").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.b.Prompt, a.b.Data, a.b.Note, a.b.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.b.Prompt, a.b.Data, a.b.Note, a.b.Tags, a.b.Folder, a.b.TimeStamp}...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE binary_data SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM otps").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec("INSERT INTO otps").
+					WithArgs([]driver.Value{testUserLogin, testOtp.Issuer, testOtp.Account, testOtp.Secret,
+						testOtp.Algorithm, testOtp.Digits, testOtp.Period, testOtp.Note, testOtp.Tags, testOtp.Folder, testOtp.TimeStamp}...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE otps SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM ssh_keys").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec("INSERT INTO ssh_keys").
+					WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt, testSshKey.PrivateKey, testSshKey.PublicKey,
+						testSshKey.Comment, testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp}...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE ssh_keys SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM templates").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec("INSERT INTO templates").
+					WithArgs([]driver.Value{testUserLogin, testTemplate.Name, testTemplate.Fields, testTemplate.Tags, testTemplate.Folder, testTemplate.TimeStamp}...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE templates SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM custom_records").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec("INSERT INTO custom_records").
+					WithArgs([]driver.Value{testUserLogin, testCustomRecord.Prompt, testCustomRecord.Template,
+						testCustomRecord.Data, testCustomRecord.Note, testCustomRecord.Tags, testCustomRecord.Folder, testCustomRecord.TimeStamp}...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE custom_records SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "existing records test",
+			ctx:  context.Background(),
+			args: args{
+				c: testCard,
+				l: testLoginPwd,
+				t: testTextRecord,
+				b: testBinaryRecord,
+			},
+			mockBehavior: func(a args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT time_stamp FROM cards").
+					WillReturnRows(sqlmock.NewRows([]string{"time_stamp"}).AddRow(testTimeEarlier))
+				mock.ExpectExec("UPDATE cards SET (.+) pending = 1").
+					WithArgs([]driver.Value{a.c.Prompt, a.c.Date, a.c.Code, a.c.Note, a.c.Tags, a.c.Folder,
+						a.c.TimeStamp, testUserLogin, a.c.Number}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE cards SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				for _, table := range []string{"logins", "text_data", "binary_data", "otps", "ssh_keys", "templates", "custom_records"} {
+					mock.ExpectQuery("SELECT time_stamp FROM " + table).
+						WillReturnRows(sqlmock.NewRows([]string{"time_stamp"}).AddRow(testTime))
+				}
 				mock.ExpectCommit()
 			},
 			wantErr: false,
@@ -1331,9 +1819,11 @@ func TestAddSyncData(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT time_stamp FROM cards").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code,
-						a.c.Note, a.c.TimeStamp}...).WillReturnError(errTest)
+						a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).WillReturnError(errTest)
 				mock.ExpectRollback()
 			},
 			wantErr: true,
@@ -1349,9 +1839,11 @@ func TestAddSyncData(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT time_stamp FROM cards").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code,
-						a.c.Note, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(2, 2))
+						a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(2, 2))
 				mock.ExpectRollback()
 			},
 			wantErr: true,
@@ -1367,11 +1859,17 @@ func TestAddSyncData(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT time_stamp FROM cards").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code,
-						a.c.Note, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+						a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE cards SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM logins").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note,
+					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, a.l.Tags, a.l.Folder,
 						a.l.TimeStamp}...).WillReturnError(errTest)
 				mock.ExpectRollback()
 			},
@@ -1388,11 +1886,17 @@ func TestAddSyncData(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT time_stamp FROM cards").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code,
-						a.c.Note, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+						a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE cards SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM logins").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note,
+					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, a.l.Tags, a.l.Folder,
 						a.l.TimeStamp}...).WillReturnResult(sqlmock.NewResult(2, 2))
 				mock.ExpectRollback()
 			},
@@ -1409,14 +1913,24 @@ func TestAddSyncData(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT time_stamp FROM cards").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code,
-						a.c.Note, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+						a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE cards SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM logins").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note,
+					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, a.l.Tags, a.l.Folder,
 						a.l.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE logins SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM text_data").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.t.Prompt, a.t.Data, a.t.Note, a.t.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.t.Prompt, a.t.Data, a.t.Note, a.t.Tags, a.t.Folder, a.t.TimeStamp}...).
 					WillReturnError(errTest)
 				mock.ExpectRollback()
 			},
@@ -1433,14 +1947,24 @@ func TestAddSyncData(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT time_stamp FROM cards").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code,
-						a.c.Note, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+						a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE cards SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM logins").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note,
+					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, a.l.Tags, a.l.Folder,
 						a.l.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE logins SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM text_data").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.t.Prompt, a.t.Data, a.t.Note, a.t.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.t.Prompt, a.t.Data, a.t.Note, a.t.Tags, a.t.Folder, a.t.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(2, 2))
 				mock.ExpectRollback()
 			},
@@ -1457,17 +1981,31 @@ func TestAddSyncData(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT time_stamp FROM cards").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code,
-						a.c.Note, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+						a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE cards SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM logins").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note,
+					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, a.l.Tags, a.l.Folder,
 						a.l.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE logins SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM text_data").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.t.Prompt, a.t.Data, a.t.Note, a.t.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.t.Prompt, a.t.Data, a.t.Note, a.t.Tags, a.t.Folder, a.t.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE text_data SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM binary_data").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.b.Prompt, a.b.Data, a.b.Note, a.b.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.b.Prompt, a.b.Data, a.b.Note, a.b.Tags, a.b.Folder, a.b.TimeStamp}...).
 					WillReturnError(errTest)
 				mock.ExpectRollback()
 			},
@@ -1484,17 +2022,31 @@ func TestAddSyncData(t *testing.T) {
 			},
 			mockBehavior: func(a args) {
 				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT time_stamp FROM cards").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO cards").
 					WithArgs([]driver.Value{testUserLogin, a.c.Prompt, a.c.Number, a.c.Date, a.c.Code,
-						a.c.Note, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+						a.c.Note, a.c.Tags, a.c.Folder, a.c.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE cards SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM logins").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO logins").
-					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note,
+					WithArgs([]driver.Value{testUserLogin, a.l.Prompt, a.l.Login, a.l.Pwd, a.l.Note, a.l.Tags, a.l.Folder,
 						a.l.TimeStamp}...).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE logins SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM text_data").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO text_data").
-					WithArgs([]driver.Value{testUserLogin, a.t.Prompt, a.t.Data, a.t.Note, a.t.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.t.Prompt, a.t.Data, a.t.Note, a.t.Tags, a.t.Folder, a.t.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("UPDATE text_data SET pending = 0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT time_stamp FROM binary_data").
+					WillReturnError(sql.ErrNoRows)
 				mock.ExpectExec("INSERT INTO binary_data").
-					WithArgs([]driver.Value{testUserLogin, a.b.Prompt, a.b.Data, a.b.Note, a.b.TimeStamp}...).
+					WithArgs([]driver.Value{testUserLogin, a.b.Prompt, a.b.Data, a.b.Note, a.b.Tags, a.b.Folder, a.b.TimeStamp}...).
 					WillReturnResult(sqlmock.NewResult(2, 2))
 				mock.ExpectRollback()
 			},
@@ -1506,12 +2058,14 @@ func TestAddSyncData(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
 			err := testDB.AddSyncData(tt.ctx, testUserLogin, []Card{testCard}, []LoginPwd{testLoginPwd},
-				[]TextRecord{testTextRecord}, []BinaryRecord{testBinaryRecord})
+				[]TextRecord{testTextRecord}, []BinaryRecord{testBinaryRecord}, []Otp{testOtp},
+				[]SshKey{testSshKey}, []Template{testTemplate}, []CustomRecord{testCustomRecord})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -1825,3 +2379,499 @@ func TestUpdateBinaryRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestAddOtp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	mock.ExpectExec("INSERT INTO otps").
+		WithArgs([]driver.Value{testUserLogin, testOtp.Issuer, testOtp.Account, testOtp.Secret,
+			testOtp.Algorithm, testOtp.Digits, testOtp.Period, testOtp.Note, testOtp.Tags, testOtp.Folder, testOtp.TimeStamp}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = testDB.AddOtp(context.Background(), testUserLogin, testOtp)
+	assert.NoError(t, err)
+
+	mock.ExpectExec("INSERT INTO otps").WillReturnError(errTest)
+	err = testDB.AddOtp(context.Background(), testUserLogin, testOtp)
+	assert.Error(t, err)
+}
+
+func TestGetOtp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	rows := sqlmock.NewRows([]string{"secret", "algorithm", "digits", "period", "note", "tags", "folder", "time_stamp"}).
+		AddRow(testOtp.Secret, testOtp.Algorithm, testOtp.Digits, testOtp.Period, testOtp.Note, testOtp.Tags, testOtp.Folder, testOtp.TimeStamp)
+	mock.ExpectQuery("SELECT secret, algorithm, digits, period, note, tags, folder, time_stamp FROM otps").
+		WithArgs([]driver.Value{testUserLogin, testOtp.Issuer, testOtp.Account}...).
+		WillReturnRows(rows)
+	o, err := testDB.GetOtp(context.Background(), testUserLogin, testOtp.Issuer, testOtp.Account)
+	assert.NoError(t, err)
+	assert.Equal(t, testOtp, o)
+
+	rows = sqlmock.NewRows([]string{"issuer", "account", "secret", "algorithm", "digits", "period", "note", "tags", "folder", "time_stamp"}).
+		AddRow(testOtp.Issuer, testOtp.Account, testOtp.Secret, testOtp.Algorithm, testOtp.Digits,
+			testOtp.Period, testOtp.Note, testOtp.Tags, testOtp.Folder, testOtp.TimeStamp)
+	mock.ExpectQuery("SELECT issuer, account, secret, algorithm, digits, period, note, tags, folder, time_stamp FROM otps").
+		WithArgs(testUserLogin).
+		WillReturnRows(rows)
+	os, err := testDB.GetUserOtpsToSync(context.Background(), testUserLogin)
+	assert.NoError(t, err)
+	assert.Equal(t, []Otp{testOtp}, os)
+}
+
+func TestAddSshKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	mock.ExpectExec("INSERT INTO ssh_keys").
+		WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt, testSshKey.PrivateKey, testSshKey.PublicKey,
+			testSshKey.Comment, testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = testDB.AddSshKey(context.Background(), testUserLogin, testSshKey)
+	assert.NoError(t, err)
+
+	mock.ExpectExec("INSERT INTO ssh_keys").WillReturnError(errTest)
+	err = testDB.AddSshKey(context.Background(), testUserLogin, testSshKey)
+	assert.Error(t, err)
+}
+
+func TestGetSshKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	rows := sqlmock.NewRows([]string{"private_key", "public_key", "comment", "passphrase", "tags", "folder", "time_stamp"}).
+		AddRow(testSshKey.PrivateKey, testSshKey.PublicKey, testSshKey.Comment, testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp)
+	mock.ExpectQuery("SELECT private_key, public_key, comment, passphrase, tags, folder, time_stamp FROM ssh_keys").
+		WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt}...).
+		WillReturnRows(rows)
+	k, err := testDB.GetSshKey(context.Background(), testUserLogin, testSshKey.Prompt)
+	assert.NoError(t, err)
+	assert.Equal(t, testSshKey, k)
+
+	rows = sqlmock.NewRows([]string{"prompt", "private_key", "public_key", "comment", "passphrase", "tags", "folder", "time_stamp"}).
+		AddRow(testSshKey.Prompt, testSshKey.PrivateKey, testSshKey.PublicKey, testSshKey.Comment,
+			testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp)
+	mock.ExpectQuery("SELECT prompt, private_key, public_key, comment, passphrase, tags, folder, time_stamp FROM ssh_keys").
+		WithArgs(testUserLogin).
+		WillReturnRows(rows)
+	ks, err := testDB.GetUserSshKeysToSync(context.Background(), testUserLogin)
+	assert.NoError(t, err)
+	assert.Equal(t, []SshKey{testSshKey}, ks)
+}
+
+func TestAddTemplate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	mock.ExpectExec("INSERT INTO templates").
+		WithArgs([]driver.Value{testUserLogin, testTemplate.Name, testTemplate.Fields, testTemplate.Tags, testTemplate.Folder, testTemplate.TimeStamp}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = testDB.AddTemplate(context.Background(), testUserLogin, testTemplate)
+	assert.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"fields", "tags", "folder", "time_stamp"}).
+		AddRow(testTemplate.Fields, testTemplate.Tags, testTemplate.Folder, testTemplate.TimeStamp)
+	mock.ExpectQuery("SELECT fields, tags, folder, time_stamp FROM templates").
+		WithArgs([]driver.Value{testUserLogin, testTemplate.Name}...).
+		WillReturnRows(rows)
+	tmpl, err := testDB.GetTemplate(context.Background(), testUserLogin, testTemplate.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, testTemplate, tmpl)
+
+	rows = sqlmock.NewRows([]string{"name", "fields", "tags", "folder", "time_stamp"}).
+		AddRow(testTemplate.Name, testTemplate.Fields, testTemplate.Tags, testTemplate.Folder, testTemplate.TimeStamp)
+	mock.ExpectQuery("SELECT name, fields, tags, folder, time_stamp FROM templates").
+		WithArgs(testUserLogin).
+		WillReturnRows(rows)
+	tmpls, err := testDB.GetUserTemplatesToSync(context.Background(), testUserLogin)
+	assert.NoError(t, err)
+	assert.Equal(t, []Template{testTemplate}, tmpls)
+}
+
+func TestAddCustomRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	mock.ExpectExec("INSERT INTO custom_records").
+		WithArgs([]driver.Value{testUserLogin, testCustomRecord.Prompt, testCustomRecord.Template,
+			testCustomRecord.Data, testCustomRecord.Note, testCustomRecord.Tags, testCustomRecord.Folder, testCustomRecord.TimeStamp}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = testDB.AddCustomRecord(context.Background(), testUserLogin, testCustomRecord)
+	assert.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"template", "data", "note", "tags", "folder", "time_stamp"}).
+		AddRow(testCustomRecord.Template, testCustomRecord.Data, testCustomRecord.Note, testCustomRecord.Tags, testCustomRecord.Folder, testCustomRecord.TimeStamp)
+	mock.ExpectQuery("SELECT template, data, note, tags, folder, time_stamp FROM custom_records").
+		WithArgs([]driver.Value{testUserLogin, testCustomRecord.Prompt}...).
+		WillReturnRows(rows)
+	r, err := testDB.GetCustomRecord(context.Background(), testUserLogin, testCustomRecord.Prompt)
+	assert.NoError(t, err)
+	assert.Equal(t, testCustomRecord, r)
+
+	mock.ExpectQuery("SELECT template, data, note, tags, folder, time_stamp FROM custom_records").
+		WillReturnError(errTest)
+	_, err = testDB.GetCustomRecord(context.Background(), testUserLogin, testCustomRecord.Prompt)
+	assert.Error(t, err)
+}
+
+func TestUpdateLabel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+	testValue := []byte("test tags")
+
+	mock.ExpectExec("UPDATE cards SET tags").
+		WithArgs([]driver.Value{testValue, testTime, testUserLogin, testCard.Number}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = testDB.UpdateLabel(context.Background(), testUserLogin, Card{Number: testCard.Number},
+		LabelTags, testValue, testTime)
+	assert.NoError(t, err)
+
+	mock.ExpectExec("UPDATE otps SET folder").
+		WithArgs([]driver.Value{testValue, testTime, testUserLogin, testOtp.Issuer, testOtp.Account}...).
+		WillReturnResult(sqlmock.NewResult(1, 0))
+	err = testDB.UpdateLabel(context.Background(), testUserLogin, Otp{Issuer: testOtp.Issuer, Account: testOtp.Account},
+		LabelFolder, testValue, testTime)
+	assert.Error(t, err)
+
+	err = testDB.UpdateLabel(context.Background(), testUserLogin, "unknown", LabelTags, testValue, testTime)
+	assert.Error(t, err)
+}
+
+func TestDeleteRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	mock.ExpectExec("DELETE FROM cards").
+		WithArgs(testUserLogin, testCard.Number).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = testDB.DeleteRecord(context.Background(), testUserLogin, Card{Number: testCard.Number})
+	assert.NoError(t, err)
+
+	mock.ExpectExec("DELETE FROM logins").
+		WithArgs(testUserLogin, testLoginPwd.Prompt, testLoginPwd.Login).
+		WillReturnError(errors.New("error"))
+	err = testDB.DeleteRecord(context.Background(), testUserLogin,
+		LoginPwd{Prompt: testLoginPwd.Prompt, Login: testLoginPwd.Login})
+	assert.Error(t, err)
+
+	err = testDB.DeleteRecord(context.Background(), testUserLogin, "unknown")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE ssh_keys").
+		WithArgs([]driver.Value{testSshKey.PrivateKey, testSshKey.PublicKey, testSshKey.Comment,
+			testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp, testUserLogin, testSshKey.Prompt}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE ssh_keys SET pending = 0").
+		WithArgs(testUserLogin, testSshKey.Prompt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = testDB.RestoreRecord(context.Background(), testUserLogin, testSshKey)
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE ssh_keys").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO ssh_keys").
+		WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt, testSshKey.PrivateKey, testSshKey.PublicKey,
+			testSshKey.Comment, testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE ssh_keys SET pending = 0").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = testDB.RestoreRecord(context.Background(), testUserLogin, testSshKey)
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE ssh_keys").
+		WillReturnError(errTest)
+	mock.ExpectRollback()
+	err = testDB.RestoreRecord(context.Background(), testUserLogin, testSshKey)
+	assert.Error(t, err)
+
+	err = testDB.RestoreRecord(context.Background(), testUserLogin, "unknown")
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveServerRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT time_stamp FROM ssh_keys").
+		WithArgs(testSshKey.Prompt, testUserLogin).
+		WillReturnRows(sqlmock.NewRows([]string{"time_stamp"}).AddRow(testTimeEarlier))
+	mock.ExpectExec("UPDATE ssh_keys").
+		WithArgs([]driver.Value{testSshKey.PrivateKey, testSshKey.PublicKey, testSshKey.Comment,
+			testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp, testUserLogin, testSshKey.Prompt}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE ssh_keys SET pending = 0").
+		WithArgs(testUserLogin, testSshKey.Prompt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = testDB.SaveServerRecord(context.Background(), testUserLogin, testSshKey)
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT time_stamp FROM ssh_keys").
+		WithArgs(testSshKey.Prompt, testUserLogin).
+		WillReturnRows(sqlmock.NewRows([]string{"time_stamp"}).AddRow(testTime))
+	mock.ExpectCommit()
+	err = testDB.SaveServerRecord(context.Background(), testUserLogin, testSshKey)
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT time_stamp FROM ssh_keys").
+		WithArgs(testSshKey.Prompt, testUserLogin).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("INSERT INTO ssh_keys").
+		WithArgs([]driver.Value{testUserLogin, testSshKey.Prompt, testSshKey.PrivateKey, testSshKey.PublicKey,
+			testSshKey.Comment, testSshKey.Passphrase, testSshKey.Tags, testSshKey.Folder, testSshKey.TimeStamp}...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE ssh_keys SET pending = 0").
+		WithArgs(testUserLogin, testSshKey.Prompt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = testDB.SaveServerRecord(context.Background(), testUserLogin, testSshKey)
+	assert.NoError(t, err)
+
+	err = testDB.SaveServerRecord(context.Background(), testUserLogin, "unknown")
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+	r := Revision{Type: 1, Key: testCard.Number, ID: 7, Revision: 2, TimeStamp: testTime}
+
+	mock.ExpectExec("INSERT INTO revisions").
+		WithArgs(testUserLogin, r.Type, []byte{}, r.Key, r.ID, r.Revision, r.TimeStamp).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = testDB.SaveRevision(context.Background(), testUserLogin, r)
+	assert.NoError(t, err)
+
+	mock.ExpectExec("INSERT INTO revisions").WillReturnError(errTest)
+	err = testDB.SaveRevision(context.Background(), testUserLogin, r)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock: %s", err)
+	}
+	defer db.Close()
+
+	testDB := SQLiteStorage{dbHandle: db}
+
+	mock.ExpectQuery("SELECT record_id, revision, time_stamp FROM revisions").
+		WithArgs(testUserLogin, int32(3), testTextRecord.Prompt, []byte{}).
+		WillReturnRows(sqlmock.NewRows([]string{"record_id", "revision", "time_stamp"}).AddRow(7, 2, testTime))
+	r, err := testDB.GetRevision(context.Background(), testUserLogin, 3, testTextRecord.Prompt, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Revision{Type: 3, Prompt: testTextRecord.Prompt, ID: 7, Revision: 2, TimeStamp: testTime}, r)
+
+	mock.ExpectQuery("SELECT record_id, revision, time_stamp FROM revisions").
+		WillReturnError(sql.ErrNoRows)
+	_, err = testDB.GetRevision(context.Background(), testUserLogin, 3, testTextRecord.Prompt, nil)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLiteStorage_Timeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts Timeouts
+		parent   time.Duration
+		bulk     bool
+		want     time.Duration
+	}{
+		{name: "default query", want: defQueryTimeout},
+		{name: "default bulk", bulk: true, want: defBulkTimeout},
+		{name: "configured query", timeouts: Timeouts{Query: time.Minute}, want: time.Minute},
+		{name: "configured bulk", timeouts: Timeouts{Bulk: time.Hour}, bulk: true, want: time.Hour},
+		{name: "command deadline", timeouts: Timeouts{Bulk: time.Second}, parent: time.Hour, bulk: true, want: time.Hour},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := SQLiteStorage{timeouts: test.timeouts}
+			parent := context.Background()
+			if test.parent > 0 {
+				var cancel context.CancelFunc
+				parent, cancel = context.WithTimeout(parent, test.parent)
+				defer cancel()
+			}
+
+			var ctx context.Context
+			var cancel context.CancelFunc
+			if test.bulk {
+				ctx, cancel = db.bulkCtx(parent)
+			} else {
+				ctx, cancel = db.queryCtx(parent)
+			}
+			defer cancel()
+
+			dl, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(test.want), dl, time.Second)
+		})
+	}
+}
+
+func TestSQLiteStorage_SyncState(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "keeper.db"), Timeouts{})
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.RegUser(ctx, testUserLogin, testUserPwd))
+
+	rev, err := db.GetSyncRevision(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Zero(t, rev)
+
+	require.NoError(t, db.AddCard(ctx, testUserLogin, testCard.Prompt, testCard.Number, testCard.Date,
+		testCard.Code, testCard.Note, testTimeEarlier))
+	cards, err := db.GetUserCardsToSync(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Len(t, cards, 1, "a new local record must be sent")
+
+	require.NoError(t, db.AddTextRecord(ctx, testUserLogin, testTextRecord.Prompt, testTextRecord.Data,
+		testTextRecord.Note, testTimeEarlier))
+	require.NoError(t, db.UpdateSyncRevision(ctx, testUserLogin, 5, []any{cards[0]}))
+	rev, err = db.GetSyncRevision(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), rev)
+	cards, err = db.GetUserCardsToSync(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Empty(t, cards)
+	texts, err := db.GetUserTextRecordsToSync(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Len(t, texts, 1, "a record not accepted by the server must stay pending")
+
+	sent := texts[0]
+	require.NoError(t, db.UpdateTextRecord(ctx, testUserLogin, testTextRecord.Prompt, []byte("local"),
+		testTextRecord.Note, "2023-06-02T15:04:05Z"))
+	require.NoError(t, db.UpdateSyncRevision(ctx, testUserLogin, 6, []any{sent}))
+	texts, err = db.GetUserTextRecordsToSync(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Len(t, texts, 1, "a record changed after sending must stay pending")
+
+	require.NoError(t, db.UpdateCard(ctx, testUserLogin, testCard.Prompt, testCard.Number, testCard.Date,
+		testCard.Code, []byte("local"), testTimeEarlier))
+	cards, err = db.GetUserCardsToSync(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Len(t, cards, 1, "a local update must be sent")
+
+	require.NoError(t, db.AddSyncData(ctx, testUserLogin, []Card{testCard}, nil,
+		[]TextRecord{testTextRecord}, nil, nil, nil, nil, nil))
+	card, err := db.GetCard(ctx, testUserLogin, testCard.Number)
+	require.NoError(t, err)
+	assert.Equal(t, testCard.Note, card.Note, "a newer server version must replace the local record")
+	cards, err = db.GetUserCardsToSync(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Empty(t, cards, "records received from the server must not be sent back")
+	texts, err = db.GetUserTextRecordsToSync(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Empty(t, texts)
+
+	older := testCard
+	older.Note, older.TimeStamp = []byte("older"), testTimeEarlier
+	require.NoError(t, db.AddSyncData(ctx, testUserLogin, []Card{older}, nil, nil, nil, nil, nil, nil, nil))
+	card, err = db.GetCard(ctx, testUserLogin, testCard.Number)
+	require.NoError(t, err)
+	assert.Equal(t, testCard.Note, card.Note, "an older server version must not replace the local record")
+}
+
+func TestSQLiteStorage_GetUserRecords(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "keeper.db"), Timeouts{})
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.RegUser(ctx, testUserLogin, testUserPwd))
+	require.NoError(t, db.RegUser(ctx, "other", testUserPwd))
+
+	records, err := db.GetUserRecords(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	require.NoError(t, db.AddCard(ctx, testUserLogin, testCard.Prompt, testCard.Number, testCard.Date,
+		testCard.Code, testCard.Note, testCard.TimeStamp))
+	require.NoError(t, db.AddBinaryRecord(ctx, testUserLogin, testBinaryRecord.Prompt, testBinaryRecord.Data,
+		testBinaryRecord.Note, testBinaryRecord.TimeStamp))
+	require.NoError(t, db.AddOtp(ctx, testUserLogin, testOtp))
+	require.NoError(t, db.AddTextRecord(ctx, "other", testTextRecord.Prompt, testTextRecord.Data,
+		testTextRecord.Note, testTextRecord.TimeStamp))
+
+	records, err = db.GetUserRecords(ctx, testUserLogin)
+	require.NoError(t, err)
+	assert.Equal(t, []any{testCard, testBinaryRecord, testOtp}, records)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/Julia-ivv/info-keeper.git/internal/keepercli/config"
+	"github.com/Julia-ivv/info-keeper.git/internal/recordtable"
+	"github.com/Julia-ivv/info-keeper.git/pkg/deadline"
 )
 
 // Customer интерфейс для работы с пользователем.
@@ -14,15 +16,17 @@ type Customer interface {
 
 // Synchronizer интерфейс для выполнения синхронизации.
 type Synchronizer interface {
-	GetLastSyncTime(ctx context.Context, userLogin string) (lastSync string, err error)
+	GetSyncRevision(ctx context.Context, userLogin string) (revision int64, err error)
 	AddSyncData(ctx context.Context, userLogin string,
-		cards []Card, logins []LoginPwd, texts []TextRecord, binarys []BinaryRecord) (err error)
-	UpdateLastSyncTime(ctx context.Context, userLogin string, syncTime string) (err error)
+		cards []Card, logins []LoginPwd, texts []TextRecord, binarys []BinaryRecord, otps []Otp, sshKeys []SshKey,
+		templates []Template, customs []CustomRecord) (err error)
+	UpdateSyncRevision(ctx context.Context, userLogin string, revision int64, synced []any) (err error)
 }
 
 // CardWorker интерфейс для работы с банковскими картами.
 type CardWorker interface {
 	GetUserCardsAfterTime(ctx context.Context, userLogin string, afterTime string) (cards []Card, err error)
+	GetUserCardsToSync(ctx context.Context, userLogin string) (cards []Card, err error)
 	AddCard(ctx context.Context, userLogin string, prompt []byte, number []byte, date []byte,
 		code []byte, note []byte, timeStamp string) (err error)
 	GetCard(ctx context.Context, userLogin string, number []byte) (card Card, err error)
@@ -33,6 +37,7 @@ type CardWorker interface {
 // LoginPwdWorker интерфейс для работы с парами логин-пароль.
 type LoginPwdWorker interface {
 	GetUserLoginsPwdsAfterTime(ctx context.Context, userLogin string, afterTime string) (loginsPwds []LoginPwd, err error)
+	GetUserLoginsPwdsToSync(ctx context.Context, userLogin string) (loginsPwds []LoginPwd, err error)
 	AddLoginPwd(ctx context.Context, userLogin string, prompt []byte, login []byte, pwd []byte,
 		note []byte, timeStamp string) (err error)
 	GetLoginPwd(ctx context.Context, userLogin string, prompt []byte, login []byte) (loginPwd LoginPwd, err error)
@@ -43,6 +48,7 @@ type LoginPwdWorker interface {
 // TextDataWorker интерфейс для работы с текстовыми данными.
 type TextDataWorker interface {
 	GetUserTextRecordsAfterTime(ctx context.Context, userLogin string, afterTime string) (records []TextRecord, err error)
+	GetUserTextRecordsToSync(ctx context.Context, userLogin string) (records []TextRecord, err error)
 	AddTextRecord(ctx context.Context, userLogin string, prompt []byte, data []byte,
 		note []byte, timeStamp string) (err error)
 	GetTextRecord(ctx context.Context, userLogin string, prompt []byte) (record TextRecord, err error)
@@ -53,6 +59,7 @@ type TextDataWorker interface {
 // BinaryDataWorker интерфейс для работы с бинарными данными.
 type BinaryDataWorker interface {
 	GetUserBinaryRecordsAfterTime(ctx context.Context, userLogin string, afterTime string) (records []BinaryRecord, err error)
+	GetUserBinaryRecordsToSync(ctx context.Context, userLogin string) (records []BinaryRecord, err error)
 	AddBinaryRecord(ctx context.Context, userLogin string, prompt []byte, data []byte,
 		note []byte, timeStamp string) (err error)
 	GetBinaryRecord(ctx context.Context, userLogin string, prompt []byte) (record BinaryRecord, err error)
@@ -60,6 +67,79 @@ type BinaryDataWorker interface {
 		note []byte, timeStamp string) (err error)
 }
 
+// OtpWorker интерфейс для работы с параметрами генерации одноразовых кодов.
+type OtpWorker interface {
+	GetUserOtpsAfterTime(ctx context.Context, userLogin string, afterTime string) (otps []Otp, err error)
+	GetUserOtpsToSync(ctx context.Context, userLogin string) (otps []Otp, err error)
+	AddOtp(ctx context.Context, userLogin string, o Otp) (err error)
+	GetOtp(ctx context.Context, userLogin string, issuer []byte, account []byte) (o Otp, err error)
+}
+
+// SshKeyWorker интерфейс для работы с ключами SSH.
+type SshKeyWorker interface {
+	GetUserSshKeysAfterTime(ctx context.Context, userLogin string, afterTime string) (keys []SshKey, err error)
+	GetUserSshKeysToSync(ctx context.Context, userLogin string) (keys []SshKey, err error)
+	AddSshKey(ctx context.Context, userLogin string, k SshKey) (err error)
+	GetSshKey(ctx context.Context, userLogin string, prompt []byte) (k SshKey, err error)
+}
+
+// TemplateWorker интерфейс для работы с шаблонами пользовательских записей.
+type TemplateWorker interface {
+	GetUserTemplatesAfterTime(ctx context.Context, userLogin string, afterTime string) (templates []Template, err error)
+	GetUserTemplatesToSync(ctx context.Context, userLogin string) (templates []Template, err error)
+	AddTemplate(ctx context.Context, userLogin string, t Template) (err error)
+	GetTemplate(ctx context.Context, userLogin string, name []byte) (t Template, err error)
+}
+
+// CustomRecordWorker интерфейс для работы с пользовательскими записями.
+type CustomRecordWorker interface {
+	GetUserCustomRecordsAfterTime(ctx context.Context, userLogin string, afterTime string) (records []CustomRecord, err error)
+	GetUserCustomRecordsToSync(ctx context.Context, userLogin string) (records []CustomRecord, err error)
+	AddCustomRecord(ctx context.Context, userLogin string, r CustomRecord) (err error)
+	GetCustomRecord(ctx context.Context, userLogin string, prompt []byte) (r CustomRecord, err error)
+}
+
+// Label название метки записи.
+type Label = string
+
+const (
+	// LabelTags метка с тегами записи.
+	LabelTags Label = recordtable.TagsColumn
+	// LabelFolder метка с папкой записи.
+	LabelFolder Label = recordtable.FolderColumn
+)
+
+// LabelWorker интерфейс для работы с метками записей.
+type LabelWorker interface {
+	UpdateLabel(ctx context.Context, userLogin string, key any, label Label, value []byte, timeStamp string) (err error)
+}
+
+// VersionWorker интерфейс для работы с восстановленными версиями записей.
+type VersionWorker interface {
+	RestoreRecord(ctx context.Context, userLogin string, r any) (err error)
+}
+
+// RecordLister интерфейс для получения всех записей пользователя.
+type RecordLister interface {
+	GetUserRecords(ctx context.Context, userLogin string) (records []any, err error)
+}
+
+// RecordDeleter интерфейс для удаления записей, перенесенных в общие коллекции.
+type RecordDeleter interface {
+	DeleteRecord(ctx context.Context, userLogin string, key any) (err error)
+}
+
+// ChangeWorker интерфейс для сохранения записей, измененных на сервере.
+type ChangeWorker interface {
+	SaveServerRecord(ctx context.Context, userLogin string, r any) (err error)
+}
+
+// RevisionWorker интерфейс для работы с ревизиями записей на сервере.
+type RevisionWorker interface {
+	SaveRevision(ctx context.Context, userLogin string, r Revision) (err error)
+	GetRevision(ctx context.Context, userLogin string, recordType int32, prompt []byte, key []byte) (r Revision, err error)
+}
+
 // Repositorier интерфейс для работы с репозиторием.
 type Repositorier interface {
 	Close() error
@@ -69,11 +149,24 @@ type Repositorier interface {
 	LoginPwdWorker
 	TextDataWorker
 	BinaryDataWorker
+	OtpWorker
+	SshKeyWorker
+	TemplateWorker
+	CustomRecordWorker
+	LabelWorker
+	VersionWorker
+	RecordLister
+	RecordDeleter
+	ChangeWorker
+	RevisionWorker
 }
 
 // NewStorage создает новый объект репозитория.
 func NewStorage(cfg config.Flags) (Repositorier, error) {
-	db, err := NewSQLiteStorage(cfg.DBURI)
+	db, err := NewSQLiteStorage(cfg.DBURI, Timeouts{
+		Query: deadline.Seconds(cfg.StorageTimeout),
+		Bulk:  deadline.Seconds(cfg.StorageBulkTimeout),
+	})
 	if err != nil {
 		return nil, err
 	}
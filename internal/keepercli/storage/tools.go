@@ -14,3 +14,12 @@ func hash(value, salt string) string {
 
 	return hashString
 }
+
+// nonNil заменяет отсутствующее значение ключевого поля пустым,
+// так как ключевые столбцы не допускают NULL.
+func nonNil(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
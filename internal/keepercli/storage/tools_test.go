@@ -12,3 +12,8 @@ func TestHash(t *testing.T) {
 	h := hash(v, s)
 	assert.NotEmpty(t, h)
 }
+
+func TestNonNil(t *testing.T) {
+	assert.Equal(t, []byte{}, nonNil(nil))
+	assert.Equal(t, []byte("key"), nonNil([]byte("key")))
+}
@@ -35,6 +35,33 @@ const (
 	InfoKeeper_ForceUpdateLoginPwd_FullMethodName     = "/proto.InfoKeeper/ForceUpdateLoginPwd"
 	InfoKeeper_ForceUpdateTextRecord_FullMethodName   = "/proto.InfoKeeper/ForceUpdateTextRecord"
 	InfoKeeper_ForceUpdateBinaryRecord_FullMethodName = "/proto.InfoKeeper/ForceUpdateBinaryRecord"
+	InfoKeeper_UploadBinary_FullMethodName            = "/proto.InfoKeeper/UploadBinary"
+	InfoKeeper_DownloadBinary_FullMethodName          = "/proto.InfoKeeper/DownloadBinary"
+	InfoKeeper_ListRecords_FullMethodName             = "/proto.InfoKeeper/ListRecords"
+	InfoKeeper_AddRecord_FullMethodName               = "/proto.InfoKeeper/AddRecord"
+	InfoKeeper_GetRecord_FullMethodName               = "/proto.InfoKeeper/GetRecord"
+	InfoKeeper_ForceUpdateRecord_FullMethodName       = "/proto.InfoKeeper/ForceUpdateRecord"
+	InfoKeeper_ListVersions_FullMethodName            = "/proto.InfoKeeper/ListVersions"
+	InfoKeeper_RestoreVersion_FullMethodName          = "/proto.InfoKeeper/RestoreVersion"
+	InfoKeeper_WatchChanges_FullMethodName            = "/proto.InfoKeeper/WatchChanges"
+	InfoKeeper_GetAuditLog_FullMethodName             = "/proto.InfoKeeper/GetAuditLog"
+	InfoKeeper_GetUsage_FullMethodName                = "/proto.InfoKeeper/GetUsage"
+	InfoKeeper_SetUserKeys_FullMethodName             = "/proto.InfoKeeper/SetUserKeys"
+	InfoKeeper_GetUserKeys_FullMethodName             = "/proto.InfoKeeper/GetUserKeys"
+	InfoKeeper_GetPublicKey_FullMethodName            = "/proto.InfoKeeper/GetPublicKey"
+	InfoKeeper_CreateOrganization_FullMethodName      = "/proto.InfoKeeper/CreateOrganization"
+	InfoKeeper_CreateCollection_FullMethodName        = "/proto.InfoKeeper/CreateCollection"
+	InfoKeeper_ListCollections_FullMethodName         = "/proto.InfoKeeper/ListCollections"
+	InfoKeeper_AddCollectionMember_FullMethodName     = "/proto.InfoKeeper/AddCollectionMember"
+	InfoKeeper_MoveRecordToCollection_FullMethodName  = "/proto.InfoKeeper/MoveRecordToCollection"
+	InfoKeeper_ListCollectionRecords_FullMethodName   = "/proto.InfoKeeper/ListCollectionRecords"
+	InfoKeeper_AddEmergencyContact_FullMethodName     = "/proto.InfoKeeper/AddEmergencyContact"
+	InfoKeeper_UpdateEmergencyRecords_FullMethodName  = "/proto.InfoKeeper/UpdateEmergencyRecords"
+	InfoKeeper_RemoveEmergencyContact_FullMethodName  = "/proto.InfoKeeper/RemoveEmergencyContact"
+	InfoKeeper_RequestEmergencyAccess_FullMethodName  = "/proto.InfoKeeper/RequestEmergencyAccess"
+	InfoKeeper_DeclineEmergencyAccess_FullMethodName  = "/proto.InfoKeeper/DeclineEmergencyAccess"
+	InfoKeeper_ListEmergencyContacts_FullMethodName   = "/proto.InfoKeeper/ListEmergencyContacts"
+	InfoKeeper_GetEmergencyKey_FullMethodName         = "/proto.InfoKeeper/GetEmergencyKey"
 )
 
 // InfoKeeperClient is the client API for InfoKeeper service.
@@ -56,6 +83,33 @@ type InfoKeeperClient interface {
 	ForceUpdateLoginPwd(ctx context.Context, in *ForceUpdateLoginPwdRequest, opts ...grpc.CallOption) (*ForceUpdateLoginPwdResponse, error)
 	ForceUpdateTextRecord(ctx context.Context, in *ForceUpdateTextRecordRequest, opts ...grpc.CallOption) (*ForceUpdateTextRecordResponse, error)
 	ForceUpdateBinaryRecord(ctx context.Context, in *ForceUpdateBinaryRecordRequest, opts ...grpc.CallOption) (*ForceUpdateBinaryRecordResponse, error)
+	UploadBinary(ctx context.Context, opts ...grpc.CallOption) (InfoKeeper_UploadBinaryClient, error)
+	DownloadBinary(ctx context.Context, in *DownloadBinaryRequest, opts ...grpc.CallOption) (InfoKeeper_DownloadBinaryClient, error)
+	ListRecords(ctx context.Context, in *ListRecordsRequest, opts ...grpc.CallOption) (*ListRecordsResponse, error)
+	AddRecord(ctx context.Context, in *AddRecordRequest, opts ...grpc.CallOption) (*AddRecordResponse, error)
+	GetRecord(ctx context.Context, in *GetRecordRequest, opts ...grpc.CallOption) (*GetRecordResponse, error)
+	ForceUpdateRecord(ctx context.Context, in *ForceUpdateRecordRequest, opts ...grpc.CallOption) (*ForceUpdateRecordResponse, error)
+	ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error)
+	RestoreVersion(ctx context.Context, in *RestoreVersionRequest, opts ...grpc.CallOption) (*RestoreVersionResponse, error)
+	WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (InfoKeeper_WatchChangesClient, error)
+	GetAuditLog(ctx context.Context, in *GetAuditLogRequest, opts ...grpc.CallOption) (*GetAuditLogResponse, error)
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
+	SetUserKeys(ctx context.Context, in *SetUserKeysRequest, opts ...grpc.CallOption) (*SetUserKeysResponse, error)
+	GetUserKeys(ctx context.Context, in *GetUserKeysRequest, opts ...grpc.CallOption) (*GetUserKeysResponse, error)
+	GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*GetPublicKeyResponse, error)
+	CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*CreateOrganizationResponse, error)
+	CreateCollection(ctx context.Context, in *CreateCollectionRequest, opts ...grpc.CallOption) (*CreateCollectionResponse, error)
+	ListCollections(ctx context.Context, in *ListCollectionsRequest, opts ...grpc.CallOption) (*ListCollectionsResponse, error)
+	AddCollectionMember(ctx context.Context, in *AddCollectionMemberRequest, opts ...grpc.CallOption) (*AddCollectionMemberResponse, error)
+	MoveRecordToCollection(ctx context.Context, in *MoveRecordToCollectionRequest, opts ...grpc.CallOption) (*MoveRecordToCollectionResponse, error)
+	ListCollectionRecords(ctx context.Context, in *ListCollectionRecordsRequest, opts ...grpc.CallOption) (*ListCollectionRecordsResponse, error)
+	AddEmergencyContact(ctx context.Context, in *AddEmergencyContactRequest, opts ...grpc.CallOption) (*AddEmergencyContactResponse, error)
+	UpdateEmergencyRecords(ctx context.Context, in *UpdateEmergencyRecordsRequest, opts ...grpc.CallOption) (*UpdateEmergencyRecordsResponse, error)
+	RemoveEmergencyContact(ctx context.Context, in *RemoveEmergencyContactRequest, opts ...grpc.CallOption) (*RemoveEmergencyContactResponse, error)
+	RequestEmergencyAccess(ctx context.Context, in *RequestEmergencyAccessRequest, opts ...grpc.CallOption) (*RequestEmergencyAccessResponse, error)
+	DeclineEmergencyAccess(ctx context.Context, in *DeclineEmergencyAccessRequest, opts ...grpc.CallOption) (*DeclineEmergencyAccessResponse, error)
+	ListEmergencyContacts(ctx context.Context, in *ListEmergencyContactsRequest, opts ...grpc.CallOption) (*ListEmergencyContactsResponse, error)
+	GetEmergencyKey(ctx context.Context, in *GetEmergencyKeyRequest, opts ...grpc.CallOption) (*GetEmergencyKeyResponse, error)
 }
 
 type infoKeeperClient struct {
@@ -201,6 +255,320 @@ func (c *infoKeeperClient) ForceUpdateBinaryRecord(ctx context.Context, in *Forc
 	return out, nil
 }
 
+func (c *infoKeeperClient) UploadBinary(ctx context.Context, opts ...grpc.CallOption) (InfoKeeper_UploadBinaryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InfoKeeper_ServiceDesc.Streams[0], InfoKeeper_UploadBinary_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &infoKeeperUploadBinaryClient{stream}
+	return x, nil
+}
+
+type InfoKeeper_UploadBinaryClient interface {
+	Send(*UploadBinaryRequest) error
+	CloseAndRecv() (*UploadBinaryResponse, error)
+	grpc.ClientStream
+}
+
+type infoKeeperUploadBinaryClient struct {
+	grpc.ClientStream
+}
+
+func (x *infoKeeperUploadBinaryClient) Send(m *UploadBinaryRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *infoKeeperUploadBinaryClient) CloseAndRecv() (*UploadBinaryResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadBinaryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *infoKeeperClient) DownloadBinary(ctx context.Context, in *DownloadBinaryRequest, opts ...grpc.CallOption) (InfoKeeper_DownloadBinaryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InfoKeeper_ServiceDesc.Streams[1], InfoKeeper_DownloadBinary_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &infoKeeperDownloadBinaryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InfoKeeper_DownloadBinaryClient interface {
+	Recv() (*DownloadBinaryResponse, error)
+	grpc.ClientStream
+}
+
+type infoKeeperDownloadBinaryClient struct {
+	grpc.ClientStream
+}
+
+func (x *infoKeeperDownloadBinaryClient) Recv() (*DownloadBinaryResponse, error) {
+	m := new(DownloadBinaryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *infoKeeperClient) ListRecords(ctx context.Context, in *ListRecordsRequest, opts ...grpc.CallOption) (*ListRecordsResponse, error) {
+	out := new(ListRecordsResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_ListRecords_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) AddRecord(ctx context.Context, in *AddRecordRequest, opts ...grpc.CallOption) (*AddRecordResponse, error) {
+	out := new(AddRecordResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_AddRecord_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) GetRecord(ctx context.Context, in *GetRecordRequest, opts ...grpc.CallOption) (*GetRecordResponse, error) {
+	out := new(GetRecordResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_GetRecord_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) ForceUpdateRecord(ctx context.Context, in *ForceUpdateRecordRequest, opts ...grpc.CallOption) (*ForceUpdateRecordResponse, error) {
+	out := new(ForceUpdateRecordResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_ForceUpdateRecord_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error) {
+	out := new(ListVersionsResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_ListVersions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) RestoreVersion(ctx context.Context, in *RestoreVersionRequest, opts ...grpc.CallOption) (*RestoreVersionResponse, error) {
+	out := new(RestoreVersionResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_RestoreVersion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (InfoKeeper_WatchChangesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InfoKeeper_ServiceDesc.Streams[2], InfoKeeper_WatchChanges_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &infoKeeperWatchChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InfoKeeper_WatchChangesClient interface {
+	Recv() (*ChangeEvent, error)
+	grpc.ClientStream
+}
+
+type infoKeeperWatchChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *infoKeeperWatchChangesClient) Recv() (*ChangeEvent, error) {
+	m := new(ChangeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *infoKeeperClient) GetAuditLog(ctx context.Context, in *GetAuditLogRequest, opts ...grpc.CallOption) (*GetAuditLogResponse, error) {
+	out := new(GetAuditLogResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_GetAuditLog_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_GetUsage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) SetUserKeys(ctx context.Context, in *SetUserKeysRequest, opts ...grpc.CallOption) (*SetUserKeysResponse, error) {
+	out := new(SetUserKeysResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_SetUserKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) GetUserKeys(ctx context.Context, in *GetUserKeysRequest, opts ...grpc.CallOption) (*GetUserKeysResponse, error) {
+	out := new(GetUserKeysResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_GetUserKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*GetPublicKeyResponse, error) {
+	out := new(GetPublicKeyResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_GetPublicKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*CreateOrganizationResponse, error) {
+	out := new(CreateOrganizationResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_CreateOrganization_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) CreateCollection(ctx context.Context, in *CreateCollectionRequest, opts ...grpc.CallOption) (*CreateCollectionResponse, error) {
+	out := new(CreateCollectionResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_CreateCollection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) ListCollections(ctx context.Context, in *ListCollectionsRequest, opts ...grpc.CallOption) (*ListCollectionsResponse, error) {
+	out := new(ListCollectionsResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_ListCollections_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) AddCollectionMember(ctx context.Context, in *AddCollectionMemberRequest, opts ...grpc.CallOption) (*AddCollectionMemberResponse, error) {
+	out := new(AddCollectionMemberResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_AddCollectionMember_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) MoveRecordToCollection(ctx context.Context, in *MoveRecordToCollectionRequest, opts ...grpc.CallOption) (*MoveRecordToCollectionResponse, error) {
+	out := new(MoveRecordToCollectionResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_MoveRecordToCollection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) ListCollectionRecords(ctx context.Context, in *ListCollectionRecordsRequest, opts ...grpc.CallOption) (*ListCollectionRecordsResponse, error) {
+	out := new(ListCollectionRecordsResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_ListCollectionRecords_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) AddEmergencyContact(ctx context.Context, in *AddEmergencyContactRequest, opts ...grpc.CallOption) (*AddEmergencyContactResponse, error) {
+	out := new(AddEmergencyContactResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_AddEmergencyContact_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) UpdateEmergencyRecords(ctx context.Context, in *UpdateEmergencyRecordsRequest, opts ...grpc.CallOption) (*UpdateEmergencyRecordsResponse, error) {
+	out := new(UpdateEmergencyRecordsResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_UpdateEmergencyRecords_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) RemoveEmergencyContact(ctx context.Context, in *RemoveEmergencyContactRequest, opts ...grpc.CallOption) (*RemoveEmergencyContactResponse, error) {
+	out := new(RemoveEmergencyContactResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_RemoveEmergencyContact_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) RequestEmergencyAccess(ctx context.Context, in *RequestEmergencyAccessRequest, opts ...grpc.CallOption) (*RequestEmergencyAccessResponse, error) {
+	out := new(RequestEmergencyAccessResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_RequestEmergencyAccess_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) DeclineEmergencyAccess(ctx context.Context, in *DeclineEmergencyAccessRequest, opts ...grpc.CallOption) (*DeclineEmergencyAccessResponse, error) {
+	out := new(DeclineEmergencyAccessResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_DeclineEmergencyAccess_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) ListEmergencyContacts(ctx context.Context, in *ListEmergencyContactsRequest, opts ...grpc.CallOption) (*ListEmergencyContactsResponse, error) {
+	out := new(ListEmergencyContactsResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_ListEmergencyContacts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoKeeperClient) GetEmergencyKey(ctx context.Context, in *GetEmergencyKeyRequest, opts ...grpc.CallOption) (*GetEmergencyKeyResponse, error) {
+	out := new(GetEmergencyKeyResponse)
+	err := c.cc.Invoke(ctx, InfoKeeper_GetEmergencyKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InfoKeeperServer is the server API for InfoKeeper service.
 // All implementations must embed UnimplementedInfoKeeperServer
 // for forward compatibility
@@ -220,6 +588,33 @@ type InfoKeeperServer interface {
 	ForceUpdateLoginPwd(context.Context, *ForceUpdateLoginPwdRequest) (*ForceUpdateLoginPwdResponse, error)
 	ForceUpdateTextRecord(context.Context, *ForceUpdateTextRecordRequest) (*ForceUpdateTextRecordResponse, error)
 	ForceUpdateBinaryRecord(context.Context, *ForceUpdateBinaryRecordRequest) (*ForceUpdateBinaryRecordResponse, error)
+	UploadBinary(InfoKeeper_UploadBinaryServer) error
+	DownloadBinary(*DownloadBinaryRequest, InfoKeeper_DownloadBinaryServer) error
+	ListRecords(context.Context, *ListRecordsRequest) (*ListRecordsResponse, error)
+	AddRecord(context.Context, *AddRecordRequest) (*AddRecordResponse, error)
+	GetRecord(context.Context, *GetRecordRequest) (*GetRecordResponse, error)
+	ForceUpdateRecord(context.Context, *ForceUpdateRecordRequest) (*ForceUpdateRecordResponse, error)
+	ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error)
+	RestoreVersion(context.Context, *RestoreVersionRequest) (*RestoreVersionResponse, error)
+	WatchChanges(*WatchChangesRequest, InfoKeeper_WatchChangesServer) error
+	GetAuditLog(context.Context, *GetAuditLogRequest) (*GetAuditLogResponse, error)
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
+	SetUserKeys(context.Context, *SetUserKeysRequest) (*SetUserKeysResponse, error)
+	GetUserKeys(context.Context, *GetUserKeysRequest) (*GetUserKeysResponse, error)
+	GetPublicKey(context.Context, *GetPublicKeyRequest) (*GetPublicKeyResponse, error)
+	CreateOrganization(context.Context, *CreateOrganizationRequest) (*CreateOrganizationResponse, error)
+	CreateCollection(context.Context, *CreateCollectionRequest) (*CreateCollectionResponse, error)
+	ListCollections(context.Context, *ListCollectionsRequest) (*ListCollectionsResponse, error)
+	AddCollectionMember(context.Context, *AddCollectionMemberRequest) (*AddCollectionMemberResponse, error)
+	MoveRecordToCollection(context.Context, *MoveRecordToCollectionRequest) (*MoveRecordToCollectionResponse, error)
+	ListCollectionRecords(context.Context, *ListCollectionRecordsRequest) (*ListCollectionRecordsResponse, error)
+	AddEmergencyContact(context.Context, *AddEmergencyContactRequest) (*AddEmergencyContactResponse, error)
+	UpdateEmergencyRecords(context.Context, *UpdateEmergencyRecordsRequest) (*UpdateEmergencyRecordsResponse, error)
+	RemoveEmergencyContact(context.Context, *RemoveEmergencyContactRequest) (*RemoveEmergencyContactResponse, error)
+	RequestEmergencyAccess(context.Context, *RequestEmergencyAccessRequest) (*RequestEmergencyAccessResponse, error)
+	DeclineEmergencyAccess(context.Context, *DeclineEmergencyAccessRequest) (*DeclineEmergencyAccessResponse, error)
+	ListEmergencyContacts(context.Context, *ListEmergencyContactsRequest) (*ListEmergencyContactsResponse, error)
+	GetEmergencyKey(context.Context, *GetEmergencyKeyRequest) (*GetEmergencyKeyResponse, error)
 	mustEmbedUnimplementedInfoKeeperServer()
 }
 
@@ -272,6 +667,87 @@ func (UnimplementedInfoKeeperServer) ForceUpdateTextRecord(context.Context, *For
 func (UnimplementedInfoKeeperServer) ForceUpdateBinaryRecord(context.Context, *ForceUpdateBinaryRecordRequest) (*ForceUpdateBinaryRecordResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ForceUpdateBinaryRecord not implemented")
 }
+func (UnimplementedInfoKeeperServer) UploadBinary(InfoKeeper_UploadBinaryServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadBinary not implemented")
+}
+func (UnimplementedInfoKeeperServer) DownloadBinary(*DownloadBinaryRequest, InfoKeeper_DownloadBinaryServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadBinary not implemented")
+}
+func (UnimplementedInfoKeeperServer) ListRecords(context.Context, *ListRecordsRequest) (*ListRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecords not implemented")
+}
+func (UnimplementedInfoKeeperServer) AddRecord(context.Context, *AddRecordRequest) (*AddRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRecord not implemented")
+}
+func (UnimplementedInfoKeeperServer) GetRecord(context.Context, *GetRecordRequest) (*GetRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecord not implemented")
+}
+func (UnimplementedInfoKeeperServer) ForceUpdateRecord(context.Context, *ForceUpdateRecordRequest) (*ForceUpdateRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForceUpdateRecord not implemented")
+}
+func (UnimplementedInfoKeeperServer) ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVersions not implemented")
+}
+func (UnimplementedInfoKeeperServer) RestoreVersion(context.Context, *RestoreVersionRequest) (*RestoreVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreVersion not implemented")
+}
+func (UnimplementedInfoKeeperServer) WatchChanges(*WatchChangesRequest, InfoKeeper_WatchChangesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchChanges not implemented")
+}
+func (UnimplementedInfoKeeperServer) GetAuditLog(context.Context, *GetAuditLogRequest) (*GetAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuditLog not implemented")
+}
+func (UnimplementedInfoKeeperServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedInfoKeeperServer) SetUserKeys(context.Context, *SetUserKeysRequest) (*SetUserKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserKeys not implemented")
+}
+func (UnimplementedInfoKeeperServer) GetUserKeys(context.Context, *GetUserKeysRequest) (*GetUserKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserKeys not implemented")
+}
+func (UnimplementedInfoKeeperServer) GetPublicKey(context.Context, *GetPublicKeyRequest) (*GetPublicKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublicKey not implemented")
+}
+func (UnimplementedInfoKeeperServer) CreateOrganization(context.Context, *CreateOrganizationRequest) (*CreateOrganizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrganization not implemented")
+}
+func (UnimplementedInfoKeeperServer) CreateCollection(context.Context, *CreateCollectionRequest) (*CreateCollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCollection not implemented")
+}
+func (UnimplementedInfoKeeperServer) ListCollections(context.Context, *ListCollectionsRequest) (*ListCollectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCollections not implemented")
+}
+func (UnimplementedInfoKeeperServer) AddCollectionMember(context.Context, *AddCollectionMemberRequest) (*AddCollectionMemberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCollectionMember not implemented")
+}
+func (UnimplementedInfoKeeperServer) MoveRecordToCollection(context.Context, *MoveRecordToCollectionRequest) (*MoveRecordToCollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveRecordToCollection not implemented")
+}
+func (UnimplementedInfoKeeperServer) ListCollectionRecords(context.Context, *ListCollectionRecordsRequest) (*ListCollectionRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCollectionRecords not implemented")
+}
+func (UnimplementedInfoKeeperServer) AddEmergencyContact(context.Context, *AddEmergencyContactRequest) (*AddEmergencyContactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddEmergencyContact not implemented")
+}
+func (UnimplementedInfoKeeperServer) UpdateEmergencyRecords(context.Context, *UpdateEmergencyRecordsRequest) (*UpdateEmergencyRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateEmergencyRecords not implemented")
+}
+func (UnimplementedInfoKeeperServer) RemoveEmergencyContact(context.Context, *RemoveEmergencyContactRequest) (*RemoveEmergencyContactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveEmergencyContact not implemented")
+}
+func (UnimplementedInfoKeeperServer) RequestEmergencyAccess(context.Context, *RequestEmergencyAccessRequest) (*RequestEmergencyAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestEmergencyAccess not implemented")
+}
+func (UnimplementedInfoKeeperServer) DeclineEmergencyAccess(context.Context, *DeclineEmergencyAccessRequest) (*DeclineEmergencyAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclineEmergencyAccess not implemented")
+}
+func (UnimplementedInfoKeeperServer) ListEmergencyContacts(context.Context, *ListEmergencyContactsRequest) (*ListEmergencyContactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEmergencyContacts not implemented")
+}
+func (UnimplementedInfoKeeperServer) GetEmergencyKey(context.Context, *GetEmergencyKeyRequest) (*GetEmergencyKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmergencyKey not implemented")
+}
 func (UnimplementedInfoKeeperServer) mustEmbedUnimplementedInfoKeeperServer() {}
 
 // UnsafeInfoKeeperServer may be embedded to opt out of forward compatibility for this service.
@@ -555,40 +1031,540 @@ func _InfoKeeper_ForceUpdateBinaryRecord_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
-// InfoKeeper_ServiceDesc is the grpc.ServiceDesc for InfoKeeper service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var InfoKeeper_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "proto.InfoKeeper",
-	HandlerType: (*InfoKeeperServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "AddUser",
-			Handler:    _InfoKeeper_AddUser_Handler,
-		},
-		{
-			MethodName: "AuthUser",
-			Handler:    _InfoKeeper_AuthUser_Handler,
-		},
-		{
-			MethodName: "AddCard",
-			Handler:    _InfoKeeper_AddCard_Handler,
-		},
-		{
-			MethodName: "AddLogin",
-			Handler:    _InfoKeeper_AddLogin_Handler,
-		},
-		{
-			MethodName: "AddBinaryData",
-			Handler:    _InfoKeeper_AddBinaryData_Handler,
-		},
-		{
-			MethodName: "AddTextData",
-			Handler:    _InfoKeeper_AddTextData_Handler,
-		},
-		{
-			MethodName: "GetUserCard",
-			Handler:    _InfoKeeper_GetUserCard_Handler,
+func _InfoKeeper_UploadBinary_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InfoKeeperServer).UploadBinary(&infoKeeperUploadBinaryServer{stream})
+}
+
+type InfoKeeper_UploadBinaryServer interface {
+	SendAndClose(*UploadBinaryResponse) error
+	Recv() (*UploadBinaryRequest, error)
+	grpc.ServerStream
+}
+
+type infoKeeperUploadBinaryServer struct {
+	grpc.ServerStream
+}
+
+func (x *infoKeeperUploadBinaryServer) SendAndClose(m *UploadBinaryResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *infoKeeperUploadBinaryServer) Recv() (*UploadBinaryRequest, error) {
+	m := new(UploadBinaryRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _InfoKeeper_DownloadBinary_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadBinaryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InfoKeeperServer).DownloadBinary(m, &infoKeeperDownloadBinaryServer{stream})
+}
+
+type InfoKeeper_DownloadBinaryServer interface {
+	Send(*DownloadBinaryResponse) error
+	grpc.ServerStream
+}
+
+type infoKeeperDownloadBinaryServer struct {
+	grpc.ServerStream
+}
+
+func (x *infoKeeperDownloadBinaryServer) Send(m *DownloadBinaryResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InfoKeeper_ListRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).ListRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_ListRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).ListRecords(ctx, req.(*ListRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_AddRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).AddRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_AddRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).AddRecord(ctx, req.(*AddRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_GetRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).GetRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_GetRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).GetRecord(ctx, req.(*GetRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_ForceUpdateRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceUpdateRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).ForceUpdateRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_ForceUpdateRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).ForceUpdateRecord(ctx, req.(*ForceUpdateRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_ListVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).ListVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_ListVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).ListVersions(ctx, req.(*ListVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_RestoreVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).RestoreVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_RestoreVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).RestoreVersion(ctx, req.(*RestoreVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_WatchChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InfoKeeperServer).WatchChanges(m, &infoKeeperWatchChangesServer{stream})
+}
+
+type InfoKeeper_WatchChangesServer interface {
+	Send(*ChangeEvent) error
+	grpc.ServerStream
+}
+
+type infoKeeperWatchChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *infoKeeperWatchChangesServer) Send(m *ChangeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InfoKeeper_GetAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).GetAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_GetAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).GetAuditLog(ctx, req.(*GetAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_SetUserKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).SetUserKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_SetUserKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).SetUserKeys(ctx, req.(*SetUserKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_GetUserKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).GetUserKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_GetUserKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).GetUserKeys(ctx, req.(*GetUserKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_GetPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublicKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).GetPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_GetPublicKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).GetPublicKey(ctx, req.(*GetPublicKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_CreateOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).CreateOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_CreateOrganization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).CreateOrganization(ctx, req.(*CreateOrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_CreateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).CreateCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_CreateCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).CreateCollection(ctx, req.(*CreateCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_ListCollections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCollectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).ListCollections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_ListCollections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).ListCollections(ctx, req.(*ListCollectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_AddCollectionMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCollectionMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).AddCollectionMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_AddCollectionMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).AddCollectionMember(ctx, req.(*AddCollectionMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_MoveRecordToCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveRecordToCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).MoveRecordToCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_MoveRecordToCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).MoveRecordToCollection(ctx, req.(*MoveRecordToCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_ListCollectionRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCollectionRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).ListCollectionRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_ListCollectionRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).ListCollectionRecords(ctx, req.(*ListCollectionRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_AddEmergencyContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddEmergencyContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).AddEmergencyContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_AddEmergencyContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).AddEmergencyContact(ctx, req.(*AddEmergencyContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_UpdateEmergencyRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateEmergencyRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).UpdateEmergencyRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_UpdateEmergencyRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).UpdateEmergencyRecords(ctx, req.(*UpdateEmergencyRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_RemoveEmergencyContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveEmergencyContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).RemoveEmergencyContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_RemoveEmergencyContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).RemoveEmergencyContact(ctx, req.(*RemoveEmergencyContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_RequestEmergencyAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestEmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).RequestEmergencyAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_RequestEmergencyAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).RequestEmergencyAccess(ctx, req.(*RequestEmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_DeclineEmergencyAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeclineEmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).DeclineEmergencyAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_DeclineEmergencyAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).DeclineEmergencyAccess(ctx, req.(*DeclineEmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_ListEmergencyContacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEmergencyContactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).ListEmergencyContacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_ListEmergencyContacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).ListEmergencyContacts(ctx, req.(*ListEmergencyContactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfoKeeper_GetEmergencyKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmergencyKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoKeeperServer).GetEmergencyKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfoKeeper_GetEmergencyKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoKeeperServer).GetEmergencyKey(ctx, req.(*GetEmergencyKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InfoKeeper_ServiceDesc is the grpc.ServiceDesc for InfoKeeper service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InfoKeeper_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.InfoKeeper",
+	HandlerType: (*InfoKeeperServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddUser",
+			Handler:    _InfoKeeper_AddUser_Handler,
+		},
+		{
+			MethodName: "AuthUser",
+			Handler:    _InfoKeeper_AuthUser_Handler,
+		},
+		{
+			MethodName: "AddCard",
+			Handler:    _InfoKeeper_AddCard_Handler,
+		},
+		{
+			MethodName: "AddLogin",
+			Handler:    _InfoKeeper_AddLogin_Handler,
+		},
+		{
+			MethodName: "AddBinaryData",
+			Handler:    _InfoKeeper_AddBinaryData_Handler,
+		},
+		{
+			MethodName: "AddTextData",
+			Handler:    _InfoKeeper_AddTextData_Handler,
+		},
+		{
+			MethodName: "GetUserCard",
+			Handler:    _InfoKeeper_GetUserCard_Handler,
 		},
 		{
 			MethodName: "GetUserLogin",
@@ -622,7 +1598,119 @@ var InfoKeeper_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ForceUpdateBinaryRecord",
 			Handler:    _InfoKeeper_ForceUpdateBinaryRecord_Handler,
 		},
+		{
+			MethodName: "ListRecords",
+			Handler:    _InfoKeeper_ListRecords_Handler,
+		},
+		{
+			MethodName: "AddRecord",
+			Handler:    _InfoKeeper_AddRecord_Handler,
+		},
+		{
+			MethodName: "GetRecord",
+			Handler:    _InfoKeeper_GetRecord_Handler,
+		},
+		{
+			MethodName: "ForceUpdateRecord",
+			Handler:    _InfoKeeper_ForceUpdateRecord_Handler,
+		},
+		{
+			MethodName: "ListVersions",
+			Handler:    _InfoKeeper_ListVersions_Handler,
+		},
+		{
+			MethodName: "RestoreVersion",
+			Handler:    _InfoKeeper_RestoreVersion_Handler,
+		},
+		{
+			MethodName: "GetAuditLog",
+			Handler:    _InfoKeeper_GetAuditLog_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _InfoKeeper_GetUsage_Handler,
+		},
+		{
+			MethodName: "SetUserKeys",
+			Handler:    _InfoKeeper_SetUserKeys_Handler,
+		},
+		{
+			MethodName: "GetUserKeys",
+			Handler:    _InfoKeeper_GetUserKeys_Handler,
+		},
+		{
+			MethodName: "GetPublicKey",
+			Handler:    _InfoKeeper_GetPublicKey_Handler,
+		},
+		{
+			MethodName: "CreateOrganization",
+			Handler:    _InfoKeeper_CreateOrganization_Handler,
+		},
+		{
+			MethodName: "CreateCollection",
+			Handler:    _InfoKeeper_CreateCollection_Handler,
+		},
+		{
+			MethodName: "ListCollections",
+			Handler:    _InfoKeeper_ListCollections_Handler,
+		},
+		{
+			MethodName: "AddCollectionMember",
+			Handler:    _InfoKeeper_AddCollectionMember_Handler,
+		},
+		{
+			MethodName: "MoveRecordToCollection",
+			Handler:    _InfoKeeper_MoveRecordToCollection_Handler,
+		},
+		{
+			MethodName: "ListCollectionRecords",
+			Handler:    _InfoKeeper_ListCollectionRecords_Handler,
+		},
+		{
+			MethodName: "AddEmergencyContact",
+			Handler:    _InfoKeeper_AddEmergencyContact_Handler,
+		},
+		{
+			MethodName: "UpdateEmergencyRecords",
+			Handler:    _InfoKeeper_UpdateEmergencyRecords_Handler,
+		},
+		{
+			MethodName: "RemoveEmergencyContact",
+			Handler:    _InfoKeeper_RemoveEmergencyContact_Handler,
+		},
+		{
+			MethodName: "RequestEmergencyAccess",
+			Handler:    _InfoKeeper_RequestEmergencyAccess_Handler,
+		},
+		{
+			MethodName: "DeclineEmergencyAccess",
+			Handler:    _InfoKeeper_DeclineEmergencyAccess_Handler,
+		},
+		{
+			MethodName: "ListEmergencyContacts",
+			Handler:    _InfoKeeper_ListEmergencyContacts_Handler,
+		},
+		{
+			MethodName: "GetEmergencyKey",
+			Handler:    _InfoKeeper_GetEmergencyKey_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadBinary",
+			Handler:       _InfoKeeper_UploadBinary_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadBinary",
+			Handler:       _InfoKeeper_DownloadBinary_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchChanges",
+			Handler:       _InfoKeeper_WatchChanges_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "keeper.proto",
 }
@@ -0,0 +1,419 @@
+// Пакет recordtable реализует построение SQL-запросов для таблиц с записями пользователя.
+package recordtable
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Placeholder возвращает обозначение параметра запроса с номером n (начиная с 1).
+type Placeholder func(n int) string
+
+// Dollar - параметры в формате PostgreSQL: $1, $2 ...
+func Dollar(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// Question - параметры в формате SQLite: ?, ? ...
+func Question(n int) string {
+	return "?"
+}
+
+// TimeStampColumn - имя столбца с временем изменения записи.
+const TimeStampColumn = "time_stamp"
+
+const (
+	// TagsColumn - имя столбца с тегами записи.
+	TagsColumn = "tags"
+	// FolderColumn - имя столбца с папкой записи.
+	FolderColumn = "folder"
+)
+
+// VersionColumn - имя столбца с номером версии в таблице истории записей.
+const VersionColumn = "version_id"
+
+const (
+	// IDColumn - имя столбца с идентификатором записи на сервере.
+	IDColumn = "record_id"
+	// RevisionColumn - имя столбца с номером ревизии записи на сервере.
+	RevisionColumn = "revision"
+)
+
+const (
+	// SyncRevisionColumn - имя столбца с номером ревизии пользователя на сервере,
+	// на которой запись изменена последний раз.
+	SyncRevisionColumn = "sync_revision"
+	// PendingColumn - имя столбца с признаком локального изменения записи, еще не переданного на сервер.
+	PendingColumn = "pending"
+)
+
+// Table описывает таблицу с записями пользователя одного типа.
+type Table[T any] struct {
+	// Name - имя таблицы.
+	Name string
+	// Columns - столбцы записи в порядке полей структуры, последним идет time_stamp.
+	Columns []string
+	// Keys - столбцы, однозначно определяющие запись пользователя.
+	Keys []string
+	// Fields возвращает указатели на поля записи в порядке Columns.
+	Fields func(r *T) []any
+	// Placeholder - формат параметров запроса.
+	Placeholder Placeholder
+	// Revision - таблица хранит идентификатор и номер ревизии записи,
+	// номер ревизии увеличивается при каждом обновлении записи.
+	Revision bool
+	// Pending - таблица хранит признак локального изменения записи, еще не переданного на сервер.
+	// Новые записи получают этот признак по умолчанию, запросы обновления записи устанавливают его.
+	Pending bool
+}
+
+// userID возвращает подзапрос для получения id пользователя по логину.
+func (t Table[T]) userID(n int) string {
+	return "(SELECT user_id FROM users WHERE login = " + t.Placeholder(n) + ")"
+}
+
+// dataColumns возвращает столбцы, не входящие в ключ записи.
+func (t Table[T]) dataColumns() []string {
+	res := make([]string, 0, len(t.Columns))
+	for _, c := range t.Columns {
+		if !slices.Contains(t.Keys, c) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// ContentColumns возвращает столбцы, не входящие в ключ записи, кроме тегов и папки.
+func (t Table[T]) ContentColumns() []string {
+	res := make([]string, 0, len(t.Columns))
+	for _, c := range t.dataColumns() {
+		if c != TagsColumn && c != FolderColumn {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// pointers возвращает указатели на поля записи для указанных столбцов.
+func (t Table[T]) pointers(r *T, columns []string) []any {
+	fields := t.Fields(r)
+	res := make([]any, 0, len(columns))
+	for _, c := range columns {
+		res = append(res, fields[slices.Index(t.Columns, c)])
+	}
+	return res
+}
+
+// values возвращает значения полей записи для указанных столбцов.
+func (t Table[T]) values(r *T, columns []string) []any {
+	res := t.pointers(r, columns)
+	for i, p := range res {
+		switch v := p.(type) {
+		case *[]byte:
+			res[i] = *v
+		case *string:
+			res[i] = *v
+		case *int64:
+			res[i] = *v
+		case *time.Time:
+			res[i] = *v
+		}
+	}
+	return res
+}
+
+// conditions возвращает условия равенства столбцов параметрам, начиная с номера n.
+func (t Table[T]) conditions(columns []string, n int) []string {
+	res := make([]string, 0, len(columns))
+	for i, c := range columns {
+		res = append(res, c+" = "+t.Placeholder(n+i))
+	}
+	return res
+}
+
+// InsertQuery возвращает запрос для добавления записи.
+func (t Table[T]) InsertQuery() string {
+	params := make([]string, 0, len(t.Columns)+1)
+	params = append(params, t.userID(1))
+	for i := range t.Columns {
+		params = append(params, t.Placeholder(i+2))
+	}
+	return fmt.Sprintf("INSERT INTO %s (user_id, %s) VALUES (%s)",
+		t.Name, strings.Join(t.Columns, ", "), strings.Join(params, ", "))
+}
+
+// InsertArgs возвращает параметры запроса InsertQuery.
+func (t Table[T]) InsertArgs(userLogin string, r *T) []any {
+	return append([]any{userLogin}, t.values(r, t.Columns)...)
+}
+
+// UpsertQuery возвращает запрос, который добавляет запись, а если запись с таким ключом
+// уже существует, обновляет ее, только если время изменения в БД не больше нового.
+// Параметры запроса совпадают с InsertArgs, запрос не изменяет строк, если в БД более новая версия.
+func (t Table[T]) UpsertQuery() string {
+	columns := t.dataColumns()
+	set := make([]string, 0, len(columns)+1)
+	for _, c := range columns {
+		set = append(set, c+" = excluded."+c)
+	}
+	if t.Revision {
+		set = append(set, RevisionColumn+" = "+t.Name+"."+RevisionColumn+" + 1")
+	}
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s WHERE %s.%s <= excluded.%s",
+		t.InsertQuery(), t.conflictColumns(), strings.Join(set, ", "), t.Name, TimeStampColumn, TimeStampColumn)
+}
+
+// InsertOrSkipQuery возвращает запрос, который добавляет запись, только если записи
+// с таким ключом еще нет. Параметры запроса совпадают с InsertArgs.
+func (t Table[T]) InsertOrSkipQuery() string {
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", t.InsertQuery(), t.conflictColumns())
+}
+
+// conflictColumns возвращает столбцы уникального ключа записи пользователя.
+func (t Table[T]) conflictColumns() string {
+	return "user_id, " + strings.Join(t.Keys, ", ")
+}
+
+// TimeStampQuery возвращает запрос для получения времени изменения записи.
+func (t Table[T]) TimeStampQuery() string {
+	cond := t.conditions(t.Keys, 1)
+	cond = append(cond, "user_id = "+t.userID(len(t.Keys)+1))
+	return fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+		TimeStampColumn, t.Name, strings.Join(cond, " AND "))
+}
+
+// TimeStampArgs возвращает параметры запроса TimeStampQuery.
+func (t Table[T]) TimeStampArgs(userLogin string, r *T) []any {
+	return append(t.values(r, t.Keys), userLogin)
+}
+
+// UpdateQuery возвращает запрос для обновления записи.
+func (t Table[T]) UpdateQuery() string {
+	return t.UpdateColumnsQuery(t.dataColumns())
+}
+
+// UpdateArgs возвращает параметры запроса UpdateQuery.
+func (t Table[T]) UpdateArgs(userLogin string, r *T) []any {
+	return t.UpdateColumnsArgs(userLogin, r, t.dataColumns())
+}
+
+// UpdateColumnsQuery возвращает запрос для обновления указанных столбцов записи.
+func (t Table[T]) UpdateColumnsQuery(columns []string) string {
+	cond := []string{"user_id = " + t.userID(len(columns)+1)}
+	cond = append(cond, t.conditions(t.Keys, len(columns)+2)...)
+	set := t.conditions(columns, 1)
+	if t.Revision {
+		set = append(set, RevisionColumn+" = "+RevisionColumn+" + 1")
+	}
+	if t.Pending {
+		set = append(set, PendingColumn+" = 1")
+	}
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		t.Name, strings.Join(set, ", "), strings.Join(cond, " AND "))
+}
+
+// UpdateColumnsArgs возвращает параметры запроса UpdateColumnsQuery.
+func (t Table[T]) UpdateColumnsArgs(userLogin string, r *T, columns []string) []any {
+	args := t.values(r, columns)
+	args = append(args, userLogin)
+	return append(args, t.values(r, t.Keys)...)
+}
+
+// Set записывает значение value в поле записи, соответствующее столбцу column.
+func (t Table[T]) Set(r *T, column string, value any) {
+	switch p := t.Fields(r)[slices.Index(t.Columns, column)].(type) {
+	case *[]byte:
+		*p = value.([]byte)
+	case *string:
+		*p = value.(string)
+	case *time.Time:
+		*p = value.(time.Time)
+	}
+}
+
+// Get возвращает значение поля записи, соответствующего столбцу column.
+func (t Table[T]) Get(r *T, column string) any {
+	return t.values(r, []string{column})[0]
+}
+
+// keyCondition возвращает условие отбора записи пользователя по ключу
+// с параметрами, начиная с номера n: логин пользователя, затем ключевые столбцы.
+func (t Table[T]) keyCondition(n int) string {
+	cond := []string{"user_id = " + t.userID(n)}
+	cond = append(cond, t.conditions(t.Keys, n+1)...)
+	return strings.Join(cond, " AND ")
+}
+
+// GetQuery возвращает запрос для получения записи по ключу.
+func (t Table[T]) GetQuery() string {
+	return fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+		strings.Join(t.dataColumns(), ", "), t.Name, t.keyCondition(1))
+}
+
+// GetArgs возвращает параметры запроса GetQuery.
+func (t Table[T]) GetArgs(userLogin string, key *T) []any {
+	return append([]any{userLogin}, t.values(key, t.Keys)...)
+}
+
+// GetDest возвращает указатели на поля записи для результата запроса GetQuery.
+func (t Table[T]) GetDest(r *T) []any {
+	return t.pointers(r, t.dataColumns())
+}
+
+// DeleteQuery возвращает запрос для удаления записи по ключу.
+// Параметры запроса совпадают с GetArgs.
+func (t Table[T]) DeleteQuery() string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s", t.Name, t.keyCondition(1))
+}
+
+// RevisionQuery возвращает запрос для получения идентификатора, номера ревизии
+// и времени изменения записи по ключу. Параметры запроса совпадают с GetArgs.
+func (t Table[T]) RevisionQuery() string {
+	return fmt.Sprintf("SELECT %s, %s, %s FROM %s WHERE %s",
+		IDColumn, RevisionColumn, TimeStampColumn, t.Name, t.keyCondition(1))
+}
+
+// ListQuery возвращает запрос для получения всех записей пользователя.
+func (t Table[T]) ListQuery() string {
+	return fmt.Sprintf("SELECT %s FROM %s WHERE user_id = %s",
+		strings.Join(t.Columns, ", "), t.Name, t.userID(1))
+}
+
+// AfterTimeQuery возвращает запрос для получения записей пользователя,
+// добавленных или измененных после указанного времени.
+func (t Table[T]) AfterTimeQuery() string {
+	return fmt.Sprintf("SELECT %s FROM %s WHERE user_id = %s AND %s > %s",
+		strings.Join(t.Columns, ", "), t.Name, t.userID(1), TimeStampColumn, t.Placeholder(2))
+}
+
+// SyncRevisionQuery возвращает запрос, который отмечает запись текущим номером ревизии пользователя
+// и возвращает ее идентификатор, номер ревизии и время изменения, как RevisionQuery.
+// Параметры запроса совпадают с GetArgs.
+func (t Table[T]) SyncRevisionQuery() string {
+	return fmt.Sprintf("UPDATE %s SET %s = (SELECT %s FROM users WHERE users.user_id = %s.user_id) WHERE %s "+
+		"RETURNING %s, %s, %s",
+		t.Name, SyncRevisionColumn, SyncRevisionColumn, t.Name, t.keyCondition(1),
+		IDColumn, RevisionColumn, TimeStampColumn)
+}
+
+// AfterRevisionQuery возвращает запрос для получения записей пользователя, измененных
+// на ревизиях после первой указанной и не позднее второй.
+func (t Table[T]) AfterRevisionQuery() string {
+	return fmt.Sprintf("SELECT %s FROM %s WHERE user_id = %s AND %s > %s AND %s <= %s",
+		strings.Join(t.Columns, ", "), t.Name, t.userID(1),
+		SyncRevisionColumn, t.Placeholder(2), SyncRevisionColumn, t.Placeholder(3))
+}
+
+// PendingQuery возвращает запрос для получения записей пользователя,
+// измененных локально и еще не переданных на сервер.
+func (t Table[T]) PendingQuery() string {
+	return fmt.Sprintf("SELECT %s FROM %s WHERE user_id = %s AND %s = 1",
+		strings.Join(t.Columns, ", "), t.Name, t.userID(1), PendingColumn)
+}
+
+// ClearPendingQuery возвращает запрос, который снимает признак локального изменения с записи,
+// переданной на сервер, если после передачи запись не изменялась.
+func (t Table[T]) ClearPendingQuery() string {
+	return fmt.Sprintf("UPDATE %s SET %s = 0 WHERE %s AND %s = %s",
+		t.Name, PendingColumn, t.keyCondition(1), TimeStampColumn, t.Placeholder(len(t.Keys)+2))
+}
+
+// ClearPendingArgs возвращает параметры запроса ClearPendingQuery
+// для записи r с переданным на сервер временем изменения.
+func (t Table[T]) ClearPendingArgs(userLogin string, r *T) []any {
+	return append(t.GetArgs(userLogin, r), t.Get(r, TimeStampColumn))
+}
+
+// SyncedQuery возвращает запрос, который снимает признак локального изменения с записи.
+// Параметры запроса совпадают с GetArgs.
+func (t Table[T]) SyncedQuery() string {
+	return fmt.Sprintf("UPDATE %s SET %s = 0 WHERE %s", t.Name, PendingColumn, t.keyCondition(1))
+}
+
+// RowDest возвращает указатели на поля записи для результата запросов
+// ListQuery, AfterTimeQuery, AfterRevisionQuery и PendingQuery.
+func (t Table[T]) RowDest(r *T) []any {
+	return t.Fields(r)
+}
+
+// HistoryName возвращает имя таблицы с предыдущими версиями записей.
+func (t Table[T]) HistoryName() string {
+	return t.Name + "_history"
+}
+
+// ArchiveQuery возвращает запрос для копирования текущей версии записи в таблицу истории.
+// Параметры запроса совпадают с GetArgs.
+func (t Table[T]) ArchiveQuery() string {
+	columns := "user_id, " + strings.Join(t.Columns, ", ")
+	return fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE %s",
+		t.HistoryName(), columns, columns, t.Name, t.keyCondition(1))
+}
+
+// DeleteHistoryQuery возвращает запрос для удаления всех предыдущих версий записи.
+// Параметры запроса совпадают с GetArgs.
+func (t Table[T]) DeleteHistoryQuery() string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s", t.HistoryName(), t.keyCondition(1))
+}
+
+// PruneQuery возвращает запрос для удаления версий записи сверх заданного количества.
+func (t Table[T]) PruneQuery() string {
+	n := len(t.Keys) + 1
+	return fmt.Sprintf("DELETE FROM %s WHERE %s AND %s NOT IN "+
+		"(SELECT %s FROM %s WHERE %s ORDER BY %s DESC LIMIT %s)",
+		t.HistoryName(), t.keyCondition(1), VersionColumn,
+		VersionColumn, t.HistoryName(), t.keyCondition(n+1), VersionColumn, t.Placeholder(2*n+1))
+}
+
+// PruneArgs возвращает параметры запроса PruneQuery.
+func (t Table[T]) PruneArgs(userLogin string, key *T, retention int) []any {
+	args := t.GetArgs(userLogin, key)
+	args = append(args, t.GetArgs(userLogin, key)...)
+	return append(args, retention)
+}
+
+// VersionsQuery возвращает запрос для получения предыдущих версий записи, начиная с последней.
+// Параметры запроса совпадают с GetArgs.
+func (t Table[T]) VersionsQuery() string {
+	return fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s ORDER BY %s DESC",
+		VersionColumn, strings.Join(t.Columns, ", "), t.HistoryName(), t.keyCondition(1), VersionColumn)
+}
+
+// VersionsDest возвращает указатели на номер версии и поля записи для результата запроса VersionsQuery.
+func (t Table[T]) VersionsDest(version *int64, r *T) []any {
+	return append([]any{version}, t.Fields(r)...)
+}
+
+// VersionQuery возвращает запрос для получения версии записи по номеру.
+func (t Table[T]) VersionQuery() string {
+	return fmt.Sprintf("SELECT %s FROM %s WHERE %s AND %s = %s",
+		strings.Join(t.Columns, ", "), t.HistoryName(), t.keyCondition(1), VersionColumn, t.Placeholder(len(t.Keys)+2))
+}
+
+// VersionArgs возвращает параметры запроса VersionQuery.
+func (t Table[T]) VersionArgs(userLogin string, key *T, version int64) []any {
+	return append(t.GetArgs(userLogin, key), version)
+}
+
+// SizeExpr возвращает выражение для суммарного размера значений столбцов в байтах.
+// Пустые значения не учитываются, числовые столбцы содержат размер данных,
+// хранящихся вне таблицы, и прибавляются как есть.
+func (t Table[T]) SizeExpr(columns []string) string {
+	var r T
+	terms := make([]string, 0, len(columns))
+	for i, p := range t.pointers(&r, columns) {
+		if _, ok := p.(*int64); ok {
+			terms = append(terms, columns[i])
+			continue
+		}
+		terms = append(terms, "coalesce(octet_length("+columns[i]+"), 0)")
+	}
+	return strings.Join(terms, " + ")
+}
+
+// SizeQuery возвращает запрос для получения размера записи по ключу - выражения SizeExpr
+// для столбцов columns. Параметры запроса совпадают с параметрами GetQuery.
+func (t Table[T]) SizeQuery(columns []string) string {
+	return fmt.Sprintf("SELECT %s FROM %s WHERE %s", t.SizeExpr(columns), t.Name, t.keyCondition(1))
+}
@@ -0,0 +1,281 @@
+package recordtable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRecord struct {
+	Prompt    []byte
+	Login     []byte
+	Pwd       []byte
+	TimeStamp string
+}
+
+var testTable = Table[testRecord]{
+	Name:    "logins",
+	Columns: []string{"prompt", "login", "pwd", TimeStampColumn},
+	Keys:    []string{"prompt", "login"},
+	Fields: func(r *testRecord) []any {
+		return []any{&r.Prompt, &r.Login, &r.Pwd, &r.TimeStamp}
+	},
+	Placeholder: Dollar,
+}
+
+func TestQueries(t *testing.T) {
+	sqlite := testTable
+	sqlite.Placeholder = Question
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "insert test",
+			query: testTable.InsertQuery(),
+			want: "INSERT INTO logins (user_id, prompt, login, pwd, time_stamp) " +
+				"VALUES ((SELECT user_id FROM users WHERE login = $1), $2, $3, $4, $5)",
+		},
+		{
+			name:  "insert sqlite test",
+			query: sqlite.InsertQuery(),
+			want: "INSERT INTO logins (user_id, prompt, login, pwd, time_stamp) " +
+				"VALUES ((SELECT user_id FROM users WHERE login = ?), ?, ?, ?, ?)",
+		},
+		{
+			name:  "upsert test",
+			query: testTable.UpsertQuery(),
+			want: "INSERT INTO logins (user_id, prompt, login, pwd, time_stamp) " +
+				"VALUES ((SELECT user_id FROM users WHERE login = $1), $2, $3, $4, $5) " +
+				"ON CONFLICT (user_id, prompt, login) DO UPDATE SET pwd = excluded.pwd, time_stamp = excluded.time_stamp " +
+				"WHERE logins.time_stamp <= excluded.time_stamp",
+		},
+		{
+			name: "upsert revision test",
+			query: func() string {
+				tbl := sqlite
+				tbl.Revision = true
+				return tbl.UpsertQuery()
+			}(),
+			want: "INSERT INTO logins (user_id, prompt, login, pwd, time_stamp) " +
+				"VALUES ((SELECT user_id FROM users WHERE login = ?), ?, ?, ?, ?) " +
+				"ON CONFLICT (user_id, prompt, login) DO UPDATE SET pwd = excluded.pwd, time_stamp = excluded.time_stamp, " +
+				"revision = logins.revision + 1 WHERE logins.time_stamp <= excluded.time_stamp",
+		},
+		{
+			name:  "insert or skip test",
+			query: testTable.InsertOrSkipQuery(),
+			want: "INSERT INTO logins (user_id, prompt, login, pwd, time_stamp) " +
+				"VALUES ((SELECT user_id FROM users WHERE login = $1), $2, $3, $4, $5) " +
+				"ON CONFLICT (user_id, prompt, login) DO NOTHING",
+		},
+		{
+			name:  "time stamp test",
+			query: testTable.TimeStampQuery(),
+			want: "SELECT time_stamp FROM logins WHERE prompt = $1 AND login = $2 " +
+				"AND user_id = (SELECT user_id FROM users WHERE login = $3)",
+		},
+		{
+			name:  "update test",
+			query: testTable.UpdateQuery(),
+			want: "UPDATE logins SET pwd = $1, time_stamp = $2 " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $3) AND prompt = $4 AND login = $5",
+		},
+		{
+			name:  "update columns test",
+			query: sqlite.UpdateColumnsQuery([]string{"pwd"}),
+			want: "UPDATE logins SET pwd = ? " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = ?) AND prompt = ? AND login = ?",
+		},
+		{
+			name: "update revision test",
+			query: func() string {
+				tbl := testTable
+				tbl.Revision = true
+				return tbl.UpdateQuery()
+			}(),
+			want: "UPDATE logins SET pwd = $1, time_stamp = $2, revision = revision + 1 " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $3) AND prompt = $4 AND login = $5",
+		},
+		{
+			name:  "revision test",
+			query: testTable.RevisionQuery(),
+			want: "SELECT record_id, revision, time_stamp FROM logins " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND prompt = $2 AND login = $3",
+		},
+		{
+			name:  "get test",
+			query: testTable.GetQuery(),
+			want: "SELECT pwd, time_stamp FROM logins " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND prompt = $2 AND login = $3",
+		},
+		{
+			name:  "size test",
+			query: testTable.SizeQuery([]string{"pwd"}),
+			want: "SELECT coalesce(octet_length(pwd), 0) FROM logins " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND prompt = $2 AND login = $3",
+		},
+		{
+			name:  "delete test",
+			query: sqlite.DeleteQuery(),
+			want: "DELETE FROM logins " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = ?) AND prompt = ? AND login = ?",
+		},
+		{
+			name:  "delete history test",
+			query: testTable.DeleteHistoryQuery(),
+			want: "DELETE FROM logins_history " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND prompt = $2 AND login = $3",
+		},
+		{
+			name:  "archive test",
+			query: testTable.ArchiveQuery(),
+			want: "INSERT INTO logins_history (user_id, prompt, login, pwd, time_stamp) " +
+				"SELECT user_id, prompt, login, pwd, time_stamp FROM logins " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND prompt = $2 AND login = $3",
+		},
+		{
+			name:  "prune test",
+			query: testTable.PruneQuery(),
+			want: "DELETE FROM logins_history " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND prompt = $2 AND login = $3 " +
+				"AND version_id NOT IN (SELECT version_id FROM logins_history " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $4) AND prompt = $5 AND login = $6 " +
+				"ORDER BY version_id DESC LIMIT $7)",
+		},
+		{
+			name:  "versions test",
+			query: testTable.VersionsQuery(),
+			want: "SELECT version_id, prompt, login, pwd, time_stamp FROM logins_history " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND prompt = $2 AND login = $3 " +
+				"ORDER BY version_id DESC",
+		},
+		{
+			name:  "version test",
+			query: testTable.VersionQuery(),
+			want: "SELECT prompt, login, pwd, time_stamp FROM logins_history " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND prompt = $2 AND login = $3 " +
+				"AND version_id = $4",
+		},
+		{
+			name:  "list test",
+			query: testTable.ListQuery(),
+			want: "SELECT prompt, login, pwd, time_stamp FROM logins " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1)",
+		},
+		{
+			name:  "after time test",
+			query: testTable.AfterTimeQuery(),
+			want: "SELECT prompt, login, pwd, time_stamp FROM logins " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND time_stamp > $2",
+		},
+		{
+			name: "update pending test",
+			query: func() string {
+				tbl := sqlite
+				tbl.Pending = true
+				return tbl.UpdateColumnsQuery([]string{"pwd"})
+			}(),
+			want: "UPDATE logins SET pwd = ?, pending = 1 " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = ?) AND prompt = ? AND login = ?",
+		},
+		{
+			name:  "sync revision test",
+			query: testTable.SyncRevisionQuery(),
+			want: "UPDATE logins SET sync_revision = (SELECT sync_revision FROM users WHERE users.user_id = logins.user_id) " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND prompt = $2 AND login = $3 " +
+				"RETURNING record_id, revision, time_stamp",
+		},
+		{
+			name:  "after revision test",
+			query: testTable.AfterRevisionQuery(),
+			want: "SELECT prompt, login, pwd, time_stamp FROM logins " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = $1) AND sync_revision > $2 AND sync_revision <= $3",
+		},
+		{
+			name:  "pending test",
+			query: sqlite.PendingQuery(),
+			want: "SELECT prompt, login, pwd, time_stamp FROM logins " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = ?) AND pending = 1",
+		},
+		{
+			name:  "clear pending test",
+			query: sqlite.ClearPendingQuery(),
+			want: "UPDATE logins SET pending = 0 " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = ?) AND prompt = ? AND login = ? AND time_stamp = ?",
+		},
+		{
+			name:  "synced test",
+			query: sqlite.SyncedQuery(),
+			want: "UPDATE logins SET pending = 0 " +
+				"WHERE user_id = (SELECT user_id FROM users WHERE login = ?) AND prompt = ? AND login = ?",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.query)
+		})
+	}
+}
+
+func TestArgs(t *testing.T) {
+	r := testRecord{
+		Prompt:    []byte("p"),
+		Login:     []byte("l"),
+		Pwd:       []byte("w"),
+		TimeStamp: "ts",
+	}
+
+	assert.Equal(t, []any{"user", []byte("p"), []byte("l"), []byte("w"), "ts"}, testTable.InsertArgs("user", &r))
+	assert.Equal(t, []any{[]byte("p"), []byte("l"), "user"}, testTable.TimeStampArgs("user", &r))
+	assert.Equal(t, []any{[]byte("w"), "ts", "user", []byte("p"), []byte("l")}, testTable.UpdateArgs("user", &r))
+	assert.Equal(t, []any{"user", []byte("p"), []byte("l")}, testTable.GetArgs("user", &r))
+	assert.Equal(t, []any{"user", []byte("p"), []byte("l"), "ts"}, testTable.ClearPendingArgs("user", &r))
+	assert.Equal(t, []any{"ts", "user", []byte("p"), []byte("l")},
+		testTable.UpdateColumnsArgs("user", &r, []string{TimeStampColumn}))
+	assert.Equal(t, []any{"user", []byte("p"), []byte("l"), "user", []byte("p"), []byte("l"), 5},
+		testTable.PruneArgs("user", &r, 5))
+	assert.Equal(t, []any{"user", []byte("p"), []byte("l"), int64(3)}, testTable.VersionArgs("user", &r, 3))
+}
+
+func TestContentColumns(t *testing.T) {
+	labeled := testTable
+	labeled.Columns = []string{"prompt", "login", "pwd", TagsColumn, FolderColumn, TimeStampColumn}
+
+	assert.Equal(t, []string{"pwd", TimeStampColumn}, labeled.ContentColumns())
+	assert.Equal(t, []string{"pwd", TimeStampColumn}, testTable.ContentColumns())
+}
+
+func TestSizeExpr(t *testing.T) {
+	assert.Equal(t, "coalesce(octet_length(login), 0) + coalesce(octet_length(pwd), 0)",
+		testTable.SizeExpr([]string{"login", "pwd"}))
+	assert.Equal(t, "coalesce(octet_length(prompt), 0)", testTable.SizeExpr([]string{"prompt"}))
+}
+
+func TestSet(t *testing.T) {
+	var r testRecord
+
+	testTable.Set(&r, "pwd", []byte("w"))
+	testTable.Set(&r, TimeStampColumn, "ts")
+	assert.Equal(t, testRecord{Pwd: []byte("w"), TimeStamp: "ts"}, r)
+	assert.Equal(t, "ts", testTable.Get(&r, TimeStampColumn))
+}
+
+func TestDest(t *testing.T) {
+	var r testRecord
+
+	dest := testTable.GetDest(&r)
+	*dest[0].(*[]byte) = []byte("w")
+	*dest[1].(*string) = "ts"
+	assert.Equal(t, testRecord{Pwd: []byte("w"), TimeStamp: "ts"}, r)
+
+	assert.Len(t, testTable.RowDest(&r), len(testTable.Columns))
+
+	var version int64
+	dest = testTable.VersionsDest(&version, &r)
+	*dest[0].(*int64) = 3
+	assert.Equal(t, int64(3), version)
+	assert.Len(t, dest, len(testTable.Columns)+1)
+}
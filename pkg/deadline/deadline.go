@@ -0,0 +1,22 @@
+// Пакет deadline ограничивает время выполнения операций с учетом срока вызывающей стороны.
+package deadline
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout возвращает контекст, ограниченный таймаутом d.
+// Если у ctx уже есть срок (например, переданный клиентом gRPC), используется он.
+// Нулевой или отрицательный d означает отсутствие собственного ограничения.
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// Seconds переводит количество секунд из настроек в time.Duration.
+func Seconds(s int) time.Duration {
+	return time.Duration(s) * time.Second
+}
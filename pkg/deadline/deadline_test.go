@@ -0,0 +1,51 @@
+package deadline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		parent  time.Duration
+		d       time.Duration
+		want    time.Duration
+		hasTime bool
+	}{
+		{name: "own timeout", d: time.Minute, want: time.Minute, hasTime: true},
+		{name: "longer caller deadline", parent: time.Hour, d: time.Second, want: time.Hour, hasTime: true},
+		{name: "shorter caller deadline", parent: time.Second, d: time.Hour, want: time.Second, hasTime: true},
+		{name: "no limit", d: 0, hasTime: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := context.Background()
+			if tt.parent > 0 {
+				var cancel context.CancelFunc
+				parent, cancel = context.WithTimeout(parent, tt.parent)
+				defer cancel()
+			}
+
+			ctx, cancel := WithTimeout(parent, tt.d)
+			defer cancel()
+
+			dl, ok := ctx.Deadline()
+			assert.Equal(t, tt.hasTime, ok)
+			if ok {
+				assert.WithinDuration(t, time.Now().Add(tt.want), dl, time.Second)
+			}
+
+			cancel()
+			assert.Error(t, ctx.Err())
+		})
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	assert.Equal(t, 3*time.Second, Seconds(3))
+	assert.Equal(t, time.Duration(0), Seconds(0))
+}
@@ -0,0 +1,169 @@
+// Пакет otp реализует генерацию одноразовых кодов TOTP (RFC 6238).
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Значения параметров по умолчанию.
+const (
+	DefaultAlgorithm = "SHA1"
+	DefaultDigits    = 6
+	DefaultPeriod    = 30
+)
+
+// Key хранит параметры генерации одноразовых кодов.
+type Key struct {
+	Issuer    string
+	Account   string
+	Secret    string
+	Algorithm string
+	Digits    int
+	Period    int
+}
+
+// ParseURI получает параметры из URI формата otpauth://totp/Issuer:account?secret=...
+func ParseURI(uri string) (Key, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return Key{}, err
+	}
+	if u.Scheme != "otpauth" {
+		return Key{}, errors.New("invalid scheme " + u.Scheme)
+	}
+	if u.Host != "totp" {
+		return Key{}, errors.New("unsupported otp type " + u.Host)
+	}
+
+	k := Key{
+		Algorithm: DefaultAlgorithm,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if i := strings.Index(label, ":"); i >= 0 {
+		k.Issuer = strings.TrimSpace(label[:i])
+		k.Account = strings.TrimSpace(label[i+1:])
+	} else {
+		k.Account = label
+	}
+
+	q := u.Query()
+	if v := q.Get("issuer"); v != "" {
+		k.Issuer = v
+	}
+	k.Secret = strings.ToUpper(q.Get("secret"))
+	if v := q.Get("algorithm"); v != "" {
+		k.Algorithm = strings.ToUpper(v)
+	}
+	if v := q.Get("digits"); v != "" {
+		k.Digits, err = strconv.Atoi(v)
+		if err != nil {
+			return Key{}, err
+		}
+	}
+	if v := q.Get("period"); v != "" {
+		k.Period, err = strconv.Atoi(v)
+		if err != nil {
+			return Key{}, err
+		}
+	}
+
+	err = k.Validate()
+	if err != nil {
+		return Key{}, err
+	}
+	return k, nil
+}
+
+// Validate проверяет параметры генерации кодов.
+func (k Key) Validate() error {
+	if k.Secret == "" {
+		return errors.New("empty secret")
+	}
+	_, err := k.secret()
+	if err != nil {
+		return err
+	}
+	_, err = k.hash()
+	if err != nil {
+		return err
+	}
+	if k.Digits != 6 && k.Digits != 8 {
+		return fmt.Errorf("unsupported number of digits %d", k.Digits)
+	}
+	if k.Period <= 0 {
+		return fmt.Errorf("invalid period %d", k.Period)
+	}
+	return nil
+}
+
+func (k Key) secret() ([]byte, error) {
+	s := strings.ToUpper(strings.ReplaceAll(k.Secret, " ", ""))
+	return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(s, "="))
+}
+
+func (k Key) hash() (func() hash.Hash, error) {
+	switch k.Algorithm {
+	case "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	}
+	return nil, errors.New("unsupported algorithm " + k.Algorithm)
+}
+
+// Code возвращает одноразовый код для момента времени t.
+func (k Key) Code(t time.Time) (string, error) {
+	err := k.Validate()
+	if err != nil {
+		return "", err
+	}
+	secret, err := k.secret()
+	if err != nil {
+		return "", err
+	}
+	h, err := k.hash()
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(k.Period)))
+	mac := hmac.New(h, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < k.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", k.Digits, value%mod), nil
+}
+
+// Remaining возвращает время до смены кода после момента t.
+func (k Key) Remaining(t time.Time) time.Duration {
+	period := int64(k.Period)
+	if period <= 0 {
+		return 0
+	}
+	return time.Duration(period-t.Unix()%period) * time.Second
+}
@@ -0,0 +1,93 @@
+package otp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Секреты из приложения B RFC 6238.
+var (
+	testSecretSHA1   = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	testSecretSHA256 = base32.StdEncoding.EncodeToString([]byte("12345678901234567890123456789012"))
+	testSecretSHA512 = base32.StdEncoding.EncodeToString(
+		[]byte("1234567890123456789012345678901234567890123456789012345678901234"))
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		key  Key
+		time int64
+		want string
+	}{
+		{name: "sha1 59", key: Key{Secret: testSecretSHA1, Algorithm: "SHA1", Digits: 8, Period: 30}, time: 59, want: "94287082"},
+		{name: "sha256 59", key: Key{Secret: testSecretSHA256, Algorithm: "SHA256", Digits: 8, Period: 30}, time: 59, want: "46119246"},
+		{name: "sha512 59", key: Key{Secret: testSecretSHA512, Algorithm: "SHA512", Digits: 8, Period: 30}, time: 59, want: "90693936"},
+		{name: "sha1 1111111109", key: Key{Secret: testSecretSHA1, Algorithm: "SHA1", Digits: 8, Period: 30}, time: 1111111109, want: "07081804"},
+		{name: "sha256 1234567890", key: Key{Secret: testSecretSHA256, Algorithm: "SHA256", Digits: 8, Period: 30}, time: 1234567890, want: "91819424"},
+		{name: "sha512 20000000000", key: Key{Secret: testSecretSHA512, Algorithm: "SHA512", Digits: 8, Period: 30}, time: 20000000000, want: "47863826"},
+		{name: "sha1 6 digits", key: Key{Secret: testSecretSHA1, Algorithm: "SHA1", Digits: 6, Period: 30}, time: 59, want: "287082"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := tt.key.Code(time.Unix(tt.time, 0))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, code)
+		})
+	}
+}
+
+func TestCodeError(t *testing.T) {
+	_, err := Key{Secret: "!!!", Algorithm: "SHA1", Digits: 6, Period: 30}.Code(time.Now())
+	assert.Error(t, err)
+	_, err = Key{Secret: testSecretSHA1, Algorithm: "MD5", Digits: 6, Period: 30}.Code(time.Now())
+	assert.Error(t, err)
+	_, err = Key{Secret: testSecretSHA1, Algorithm: "SHA1", Digits: 7, Period: 30}.Code(time.Now())
+	assert.Error(t, err)
+}
+
+func TestRemaining(t *testing.T) {
+	k := Key{Period: 30}
+	assert.Equal(t, 30*time.Second, k.Remaining(time.Unix(60, 0)))
+	assert.Equal(t, 1*time.Second, k.Remaining(time.Unix(59, 0)))
+}
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    Key
+		wantErr bool
+	}{
+		{
+			name: "full uri",
+			uri:  "otpauth://totp/ACME%20Co:john@example.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60",
+			want: Key{Issuer: "ACME Co", Account: "john@example.com", Secret: "JBSWY3DPEHPK3PXP",
+				Algorithm: "SHA256", Digits: 8, Period: 60},
+		},
+		{
+			name: "defaults",
+			uri:  "otpauth://totp/alice?secret=jbswy3dpehpk3pxp",
+			want: Key{Account: "alice", Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA1", Digits: 6, Period: 30},
+		},
+		{name: "hotp", uri: "otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP&counter=1", wantErr: true},
+		{name: "scheme", uri: "https://totp/alice?secret=JBSWY3DPEHPK3PXP", wantErr: true},
+		{name: "no secret", uri: "otpauth://totp/alice", wantErr: true},
+		{name: "bad digits", uri: "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&digits=x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := ParseURI(tt.uri)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, k)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Пакет pubsub реализует рассылку сообщений подписчикам внутри процесса.
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// BufferSize - количество сообщений, которые подписчик может не успеть прочитать.
+// Сообщения сверх этого количества подписчику не доставляются.
+const BufferSize = 64
+
+// Broker рассылает сообщения подписчикам темы.
+type Broker[T any] struct {
+	mu   sync.Mutex
+	subs map[string]map[chan T]struct{}
+}
+
+// NewBroker создает объект для рассылки сообщений.
+func NewBroker[T any]() *Broker[T] {
+	return &Broker[T]{subs: make(map[string]map[chan T]struct{})}
+}
+
+// Subscribe подписывается на сообщения темы topic.
+// Подписка действует, пока не завершен контекст, после этого канал закрывается.
+func (b *Broker[T]) Subscribe(ctx context.Context, topic string) <-chan T {
+	ch := make(chan T, BufferSize)
+
+	b.mu.Lock()
+	if b.subs[topic] == nil {
+		b.subs[topic] = make(map[chan T]struct{})
+	}
+	b.subs[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs[topic], ch)
+		if len(b.subs[topic]) == 0 {
+			delete(b.subs, topic)
+		}
+		b.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Publish отправляет сообщение всем подписчикам темы topic.
+// Метод не блокируется: если буфер подписчика заполнен, сообщение для него отбрасывается.
+func (b *Broker[T]) Publish(topic string, msg T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs[topic] {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
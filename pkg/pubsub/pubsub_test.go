@@ -0,0 +1,35 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker(t *testing.T) {
+	b := NewBroker[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := b.Subscribe(ctx, "user")
+	other := b.Subscribe(context.Background(), "other")
+
+	b.Publish("user", 1)
+	assert.Equal(t, 1, <-ch)
+	assert.Empty(t, other)
+
+	for i := 0; i < BufferSize+1; i++ {
+		b.Publish("user", i)
+	}
+	assert.Len(t, ch, BufferSize)
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		_, ok := b.subs["user"]
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+	for range ch {
+	}
+}